@@ -24,19 +24,22 @@ const (
 
 	// Upgradeable indicates whether the operator and operands are in a state
 	// that allows for safe upgrades. It is True when all existing operand CRs
-	// are ready, and CreateOnlyMode is not enabled. CRs that don't exist yet are OK.
+	// are ready, no operand rollout is in flight, and CreateOnlyMode is not enabled.
+	// CRs that don't exist yet are OK.
 	//   Status:
 	//   - True: Safe to upgrade (all existing CRs are ready, CRs that don't exist are OK, and no CreateOnlyMode)
-	//   - False: Not safe to upgrade (any existing CR is not ready, or CreateOnlyMode enabled)
+	//   - False: Not safe to upgrade (a rollout is in flight, any existing CR is not ready, or CreateOnlyMode enabled)
 	//   Reason:
 	//   - Ready: All existing operands are ready or CRs don't exist yet
+	//   - OperationInProgress: A SPIRE server rollout, applying CA or datastore changes, or a SPIRE agent rollout is in flight
 	//   - OperandsNotReady: Some existing operands are not ready, or CreateOnlyMode is enabled
 	Upgradeable string = "Upgradeable"
 )
 
 const (
-	ReasonFailed           string = "Failed"
-	ReasonReady            string = "Ready"
-	ReasonInProgress       string = "Progressing"
-	ReasonOperandsNotReady string = "OperandsNotReady"
+	ReasonFailed              string = "Failed"
+	ReasonReady               string = "Ready"
+	ReasonInProgress          string = "Progressing"
+	ReasonOperandsNotReady    string = "OperandsNotReady"
+	ReasonOperationInProgress string = "OperationInProgress"
 )
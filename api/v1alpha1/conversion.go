@@ -0,0 +1,19 @@
+package v1alpha1
+
+// v1alpha1 is the storage version and the conversion hub for the operator.openshift.io API group,
+// other versions convert to and from these types.
+
+// Hub marks this type as a conversion hub.
+func (*ZeroTrustWorkloadIdentityManager) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*SpireServer) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*SpireAgent) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*SpiffeCSIDriver) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*SpireOIDCDiscoveryProvider) Hub() {}
@@ -11,6 +11,44 @@ type ConditionalStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// plannedChanges lists the changes the controller would make to the cluster.
+	// It is only populated when reconcileMode is DryRun.
+	// +listType=atomic
+	// +optional
+	PlannedChanges []PlannedChange `json:"plannedChanges,omitempty"`
+
+	// managedResources is the inventory of the resources generated for the current spec.
+	// Resources listed by a previous reconciliation that are no longer generated are deleted.
+	// +listType=atomic
+	// +optional
+	ManagedResources []ManagedResource `json:"managedResources,omitempty"`
+}
+
+// ManagedResource identifies a resource generated by the operator.
+type ManagedResource struct {
+	// apiVersion of the resource.
+	APIVersion string `json:"apiVersion"`
+	// kind of the resource.
+	Kind string `json:"kind"`
+	// namespace of the resource, empty for cluster-scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// name of the resource.
+	Name string `json:"name"`
+}
+
+// PlannedChange describes a write that a dry-run reconciliation skipped.
+type PlannedChange struct {
+	// action is the operation the controller would perform: Create, Update, Patch or Delete.
+	Action string `json:"action"`
+	// kind of the affected resource.
+	Kind string `json:"kind"`
+	// namespace of the affected resource, empty for cluster-scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// name of the affected resource.
+	Name string `json:"name"`
 }
 
 // ObjectReference is a reference to an object with a given name, kind and group.
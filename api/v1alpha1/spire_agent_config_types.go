@@ -1,14 +1,22 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,shortName=sagent
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
 // +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="SpireAgent is a singleton, .metadata.name must be 'cluster'"
 // +operator-sdk:csv:customresourcedefinitions:displayName="SpireAgent"
 
@@ -34,6 +42,16 @@ type SpireAgentSpec struct {
 	// +kubebuilder:default:="/run/spire/agent-sockets"
 	SocketPath string `json:"socketPath,omitempty"`
 
+	// socketName is the file name of the SPIRE agent Workload API socket in socketPath, e.g. "socket"
+	// for the workloads and service meshes expecting the socket at a specific path. The SPIRE OIDC
+	// discovery provider and the injected spiffe-helper follow it.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9._\-]+$`
+	// +kubebuilder:validation:XValidation:rule="self != '.' && self != '..'",message="socketName must be a file name"
+	// +kubebuilder:default:="spire-agent.sock"
+	SocketName string `json:"socketName,omitempty"`
+
 	// logLevel sets the logging level for the operand.
 	// Valid values are: debug, info, warn, error.
 	// +kubebuilder:validation:Optional
@@ -56,9 +74,192 @@ type SpireAgentSpec struct {
 	// +kubebuilder:validation:Optional
 	WorkloadAttestors *WorkloadAttestors `json:"workloadAttestors,omitempty"`
 
+	// sds configures the names of the resources served by the SPIRE agent Envoy SDS API,
+	// to match the names expected by Envoy or Istio sidecars.
+	// +kubebuilder:validation:Optional
+	SDS *SDSConfig `json:"sds,omitempty"`
+
+	// adminAPI configures the admin socket of the SPIRE agents, serving the debug and the delegated identity APIs,
+	// so that the spire-agent CLI can be used on the nodes for troubleshooting. The admin socket is disabled when unset.
+	// +kubebuilder:validation:Optional
+	AdminAPI *AgentAdminAPIConfig `json:"adminAPI,omitempty"`
+
+	// hostNetwork specifies whether the SPIRE agent pods run in the host network namespace. Some CNI
+	// configurations require it, e.g. when pods can't reach the SPIRE server before the node is attested.
+	// The DNS policy of the pods follows, so that they keep resolving the cluster services.
+	// +kubebuilder:default:="true"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	HostNetwork string `json:"hostNetwork,omitempty"`
+
+	// healthPort is the port the SPIRE agent serves its health endpoints on. With hostNetwork, the port
+	// is opened on the nodes and must not be used by other host network pods.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=9982
+	HealthPort int32 `json:"healthPort,omitempty"`
+
+	// metricsPort is the port the SPIRE agent serves its Prometheus metrics on. With hostNetwork, the
+	// port is opened on the nodes and must not be used by other host network pods.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=9402
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// livenessProbe tunes the liveness probe of the SPIRE agent.
+	// +kubebuilder:validation:Optional
+	LivenessProbe *ProbeTimings `json:"livenessProbe,omitempty"`
+
+	// readinessProbe tunes the readiness probe of the SPIRE agent, e.g. a shorter period reports the agents
+	// ready sooner after they attested the node.
+	// +kubebuilder:validation:Optional
+	ReadinessProbe *ProbeTimings `json:"readinessProbe,omitempty"`
+
+	// startupProbe adds a startup probe to the SPIRE agent, which holds back the liveness probe until the
+	// agent is live, e.g. while the node attestation is retried. Unset timings default to a period of
+	// 10 seconds and a failure threshold of 30.
+	// +kubebuilder:validation:Optional
+	StartupProbe *ProbeTimings `json:"startupProbe,omitempty"`
+
+	// availabilityTarget is the availability_target of the SPIRE agent: the X.509-SVIDs are rotated early
+	// enough that they stay valid for at least this long, so that the workloads keep valid identities
+	// through an outage of the SPIRE server. Must be at least 24h. The SVIDs whose TTL is not longer than
+	// the target, and all the SVIDs when it is unset, are rotated at half of their lifetime.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('24h')",message="availabilityTarget must be at least 24h"
+	AvailabilityTarget *metav1.Duration `json:"availabilityTarget,omitempty"`
+
+	// securityContextConstraints is the name of an existing SecurityContextConstraints the SPIRE agent pods are
+	// admitted with, instead of the SecurityContextConstraints created by the operator, e.g. to comply with a
+	// cluster policy on privileged workloads. The operator requires the pods to use it, so the cluster admin must
+	// grant its use to the spire-agent service account of the operand namespace. It must allow the host path
+	// volumes, the privileged containers, the host PID namespace and, with hostNetwork, the host network and ports.
+	// The SecurityContextConstraints previously created by the operator are removed.
+	// Must be a valid Kubernetes name.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	SecurityContextConstraints string `json:"securityContextConstraints,omitempty"`
+
+	// updateStrategy configures how the SPIRE agent pods are replaced when the DaemonSet changes,
+	// e.g. on operator upgrades or configuration changes.
+	// +kubebuilder:validation:Optional
+	UpdateStrategy *DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// rollout configures a staged rollout of the SPIRE agent DaemonSet. Changes of the agent pods are
+	// rolled out to the canary nodes first, and only to the other nodes once the canary agents are ready
+	// and, depending on the approval, the soak period elapsed or the change was approved.
+	// When not set, changes are rolled out to all the nodes at once.
+	// +kubebuilder:validation:Optional
+	Rollout *AgentRolloutConfig `json:"rollout,omitempty"`
+
+	// extraConfig is deep-merged into the rendered SPIRE agent configuration (agent.conf), for
+	// plugin settings and experimental flags that are not modeled by this API. Keys set by the
+	// operator take precedence, and lists are not merged. The configuration is passed to SPIRE
+	// as is, so unsupported settings can prevent the agents from starting. The trust domain and the
+	// SPIRE server address derive from the ZeroTrustWorkloadIdentityManager and can't be overridden.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraConfig *apiextensionsv1.JSON `json:"extraConfig,omitempty"`
+
+	// experimental is rendered into the experimental block of the SPIRE agent configuration, to evaluate the
+	// experimental features of SPIRE without a change of this API, e.g. {"use_sync_authorized_entries": "true"}.
+	// The values "true" and "false" are rendered as booleans and the other values as strings. Experimental
+	// features may change or be removed in any SPIRE release, so unsupported settings can prevent the agents
+	// from starting.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=32
+	Experimental map[string]string `json:"experimental,omitempty"`
+
+	// version pins the SPIRE version of the SPIRE agents, e.g. "1.12.4", or selects a minor version, e.g. "1.12",
+	// which runs its latest patch release supported by the operator. Unsupported versions are refused.
+	// Defaults to the SPIRE version shipped with the operator; pinning it holds the SPIRE agents back
+	// while the operator is upgraded.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+(\.[0-9]+)?$`
+	Version string `json:"version,omitempty"`
+
+	// extraContainers are appended to the containers of the SPIRE agent pods, e.g. a node-local log shipper.
+	// The names must not be used by the containers of the operator.
+	// Maximum 10 containers allowed.
+	// The containers are validated by the operator and the API server instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraContainers []corev1.Container `json:"extraContainers,omitempty"`
+
+	// extraInitContainers are appended to the init containers of the SPIRE agent pods.
+	// The names must not be used by the containers of the operator.
+	// Maximum 10 init containers allowed.
+	// The init containers are validated by the operator and the API server instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
+
 	CommonConfig `json:",inline"`
 }
 
+// DaemonSetUpdateStrategy configures the update strategy of a DaemonSet.
+// Surging is not supported: the agent pods use the host network and the host socket directory,
+// so two agent pods cannot run on the same node.
+// +kubebuilder:validation:XValidation:rule="!has(self.type) || self.type == 'RollingUpdate' || !has(self.maxUnavailable)",message="maxUnavailable can only be set with the RollingUpdate type"
+type DaemonSetUpdateStrategy struct {
+	// type is the update strategy of the DaemonSet.
+	// "RollingUpdate": The pods are replaced progressively, at most maxUnavailable nodes at a time.
+	// "OnDelete": The pods are only replaced when they are deleted, e.g. by node maintenance.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +kubebuilder:default:="RollingUpdate"
+	Type string `json:"type,omitempty"`
+
+	// maxUnavailable is the number or percentage of nodes whose pod can be unavailable during a rolling update.
+	// The workloads of a node cannot get new SVIDs while its agent is replaced, so large values speed up the
+	// rollout at the expense of the identity issuance. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AgentRolloutConfig configures the staged rollout of the SPIRE agent DaemonSet.
+// The canary nodes run their agents from a separate spire-agent-canary DaemonSet, which always runs the
+// latest revision of the agent pods. The revisions are reported in the StagedRollout condition.
+type AgentRolloutConfig struct {
+	// canaryNodeSelector selects the nodes of the canary pool, e.g. a dedicated machine pool label.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinProperties=1
+	CanaryNodeSelector map[string]string `json:"canaryNodeSelector"`
+
+	// soakPeriod is how long the canary agents must stay ready at a new revision before it is rolled out
+	// to the other nodes. The agents are ready when their health checks pass. Defaults to 10m.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+
+	// approval configures how a revision verified on the canary nodes is rolled out to the other nodes.
+	// "Automatic": The revision is rolled out once the soak period elapsed.
+	// "Manual": The revision is rolled out once the soak period elapsed and approvedRevision is set to it.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +kubebuilder:default:="Automatic"
+	Approval string `json:"approval,omitempty"`
+
+	// approvedRevision approves the rollout of a canary revision to the other nodes when approval is Manual.
+	// The revision waiting for the approval is reported in the StagedRollout condition.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=64
+	ApprovedRevision string `json:"approvedRevision,omitempty"`
+}
+
 // NodeAttestor defines the configuration for the Node Attestor.
 type NodeAttestor struct {
 	// k8sPSATEnabled specifies whether Kubernetes Projected Service Account Token (PSAT)
@@ -68,6 +269,67 @@ type NodeAttestor struct {
 	// +kubebuilder:validation:Enum:="true";"false"
 	// +kubebuilder:validation:Optional
 	K8sPSATEnabled string `json:"k8sPSATEnabled,omitempty"`
+
+	// k8sPSATAudience is the audience of the projected service account token the SPIRE agents attest with.
+	// It must be accepted by the k8sPSATAudience of the SpireServer. Defaults to "spire-server".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	K8sPSATAudience string `json:"k8sPSATAudience,omitempty"`
+}
+
+// SDSConfig defines the resource names of the SPIRE agent Envoy SDS API.
+// Unset names keep the SPIRE defaults.
+type SDSConfig struct {
+	// defaultSVIDName is the name of the TLS certificate resource serving the default X509-SVID.
+	// SPIRE defaults to "default".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	DefaultSVIDName string `json:"defaultSVIDName,omitempty"`
+
+	// defaultBundleName is the name of the validation context resource serving the trust bundle
+	// of the agent trust domain. SPIRE defaults to "ROOTCA".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	DefaultBundleName string `json:"defaultBundleName,omitempty"`
+
+	// defaultAllBundlesName is the name of the validation context resource serving the trust
+	// bundles of the agent trust domain and of all the federated trust domains.
+	// SPIRE defaults to "ALL".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	DefaultAllBundlesName string `json:"defaultAllBundlesName,omitempty"`
+}
+
+// AgentAdminAPIConfig configures the admin socket of the SPIRE agents.
+type AgentAdminAPIConfig struct {
+	// enabled controls whether the SPIRE agents serve their admin socket.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Enabled string `json:"enabled,omitempty"`
+
+	// socketPath is the directory on the host where the SPIRE agent admin socket, admin.sock, is created.
+	// It must differ from spec.socketPath, as the admin socket must not be reachable by the workloads.
+	// Must be an absolute path without traversal attempts or null bytes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^/[a-zA-Z0-9._/\-]*$`
+	// +kubebuilder:default:="/run/spire/agent-admin"
+	SocketPath string `json:"socketPath,omitempty"`
+
+	// authorizedDelegates are the SPIFFE IDs of the workloads authorized to call the delegated identity API
+	// on the admin socket, e.g. a node agent fetching SVIDs on behalf of other workloads.
+	// Maximum 50 delegates allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:MaxLength=2048
+	// +kubebuilder:validation:items:Pattern=`^spiffe://[a-z0-9._\-]+(/[a-zA-Z0-9._\-]+)*$`
+	// +listType=set
+	AuthorizedDelegates []string `json:"authorizedDelegates,omitempty"`
 }
 
 // WorkloadAttestors defines the configuration for the Workload Attestors.
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -8,7 +9,13 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:scale:specpath=.spec.replicaCount,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,shortName=sodp
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
 // +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="SpireOIDCDiscoveryProvider is a singleton, .metadata.name must be 'cluster'"
 // +operator-sdk:csv:customresourcedefinitions:displayName="SpireOIDCDiscoveryProvider"
 
@@ -23,6 +30,7 @@ type SpireOIDCDiscoveryProvider struct {
 
 // SpireOIDCDiscoveryProviderSpec defines the specifications for configuration related to the SPIRE OIDC
 // discovery provider
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || !has(oldSelf.autoscaling) || (has(self.replicaCount) ? self.replicaCount : 1) == (has(oldSelf.replicaCount) ? oldSelf.replicaCount : 1)",message="replicaCount can't be changed while autoscaling is configured, e.g. through the scale subresource"
 type SpireOIDCDiscoveryProviderSpec struct {
 
 	// logLevel sets the logging level for the operand.
@@ -49,16 +57,21 @@ type SpireOIDCDiscoveryProviderSpec struct {
 
 	// jwtIssuer is the JWT issuer url.
 	// Must be a valid HTTPS or HTTP URL.
-	// +kubebuilder:validation:Required
+	// When unset, the jwtIssuer of the SpireServer is used. When set, it must match the jwtIssuer
+	// of the SpireServer, as relying parties validate the iss claim of the JWT-SVIDs against it.
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MaxLength=512
 	// +kubebuilder:validation:Pattern=`^(?i)https?://[^\s?#]+$`
 	JwtIssuer string `json:"jwtIssuer,omitempty"`
 
 	// replicaCount is the number of replicas for the OIDC provider.
-	// Must be between 1 and 5.
+	// Must be between 1 and 10, the bounds of autoscaling.maxReplicas.
+	// It is also set through the scale subresource, e.g. by `oc scale` or an external autoscaler. It is ignored
+	// when autoscaling is configured, and can't be changed then, so that a scale write is rejected instead of
+	// being silently overridden by the HorizontalPodAutoscaler of the operator.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Minimum=1
-	// +kubebuilder:validation:Maximum=5
+	// +kubebuilder:validation:Maximum=10
 	// +kubebuilder:default:=1
 	ReplicaCount int `json:"replicaCount,omitempty"`
 
@@ -79,14 +92,141 @@ type SpireOIDCDiscoveryProviderSpec struct {
 	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
 	ExternalSecretRef string `json:"externalSecretRef,omitempty"`
 
+	// ingress configures a networking.k8s.io Ingress for the OIDC discovery provider endpoints, for clusters
+	// without OpenShift Routes. When set, the operator manages the Ingress instead of the Route and managedRoute
+	// is ignored.
+	// +kubebuilder:validation:Optional
+	Ingress *OIDCIngressConfig `json:"ingress,omitempty"`
+
+	// podDisruptionBudget configures the PodDisruptionBudget for the OIDC discovery provider Deployment.
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+
+	// topologySpreadConstraints spread the OIDC discovery provider pods across topology domains, e.g. zones, as required
+	// by platform policies. When labelSelector is not set, the constraint selects the OIDC discovery provider pods.
+	// Maximum 10 constraints allowed.
+	// The constraints are validated by the operator instead of the CRD schema.
+	// ref: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// autoscaling configures a HorizontalPodAutoscaler for the OIDC discovery provider Deployment.
+	// When set, the operator creates and owns the HorizontalPodAutoscaler and stops managing
+	// the Deployment replica count. Removing this field deletes the HorizontalPodAutoscaler.
+	// +kubebuilder:validation:Optional
+	Autoscaling *AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// version pins the SPIRE version of the OIDC discovery provider, e.g. "1.12.4", or selects a minor version, e.g. "1.12",
+	// which runs its latest patch release supported by the operator. Unsupported versions are refused.
+	// Defaults to the SPIRE version shipped with the operator; pinning it holds the OIDC discovery provider back
+	// while the operator is upgraded.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+(\.[0-9]+)?$`
+	Version string `json:"version,omitempty"`
+
+	// extraContainers are appended to the containers of the OIDC discovery provider pods, e.g. a metrics relabeling proxy.
+	// The names must not be used by the containers of the operator.
+	// Maximum 10 containers allowed.
+	// The containers are validated by the operator and the API server instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraContainers []corev1.Container `json:"extraContainers,omitempty"`
+
+	// extraInitContainers are appended to the init containers of the OIDC discovery provider pods.
+	// The names must not be used by the containers of the operator.
+	// Maximum 10 init containers allowed.
+	// The init containers are validated by the operator and the API server instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
+
 	CommonConfig `json:",inline"`
 }
 
+// AutoscalingConfig configures horizontal pod autoscaling based on CPU utilization.
+// CPU utilization is measured against the container CPU requests, so resources.requests.cpu must be set.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must be less than or equal to maxReplicas"
+type AutoscalingConfig struct {
+	// minReplicas is the lower limit for the number of replicas.
+	// Must be between 1 and 10.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default:=1
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// maxReplicas is the upper limit for the number of replicas.
+	// Must be between 1 and 10.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// targetCPUUtilizationPercentage is the target average CPU utilization across all replicas,
+	// as a percentage of the requested CPU.
+	// Must be between 1 and 100.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default:=80
+	TargetCPUUtilizationPercentage int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+// OIDCIngressConfig configures the Ingress of the OIDC discovery provider.
+// The OIDC discovery provider serves HTTPS only, so the ingress controller must be configured to
+// connect to the backend over TLS, e.g. with the nginx.ingress.kubernetes.io/backend-protocol: HTTPS annotation.
+type OIDCIngressConfig struct {
+	// className is the name of the IngressClass of the Ingress.
+	// When not set, the default IngressClass of the cluster is used.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	ClassName string `json:"className,omitempty"`
+
+	// host is the host the OIDC discovery endpoints are served on.
+	// Defaults to the host of the jwtIssuer.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	Host string `json:"host,omitempty"`
+
+	// tlsSecretName is the name of the secret, in the operand namespace, containing the TLS certificate
+	// the ingress controller serves for the host. When not set, TLS is terminated with the default
+	// certificate of the ingress controller.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// annotations are added to the Ingress, e.g. to configure the ingress controller.
+	// Maximum 64 annotations allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=64
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // SpireOIDCDiscoveryProviderStatus defines the observed state of the SPIRE OIDC discovery provider
 // reconciliation performed by the operator
 type SpireOIDCDiscoveryProviderStatus struct {
 	// conditions holds information about the current state of the SPIRE OIDC discovery provider deployment.
 	ConditionalStatus `json:",inline,omitempty"`
+
+	// replicas is the number of pods of the OIDC discovery provider Deployment, reported by the scale subresource.
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// selector is the label selector of the OIDC discovery provider pods, reported by the scale subresource so that
+	// a HorizontalPodAutoscaler can target the SpireOIDCDiscoveryProvider.
+	// +kubebuilder:validation:Optional
+	Selector string `json:"selector,omitempty"`
 }
 
 // GetConditionalStatus returns the conditional status of the SpireOIDCDiscoveryProvider
@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -8,12 +10,18 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,shortName=ss
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
 // +kubebuilder:validation:XValidation:rule="oldSelf == null || !has(oldSelf.spec.federation) || has(self.spec.federation)",message="Federation configuration cannot be removed once set."
 // +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="SpireServer is a singleton, .metadata.name must be 'cluster'"
 // +kubebuilder:validation:XValidation:rule="oldSelf.spec.persistence.size == self.spec.persistence.size",message="spec.persistence.size is immutable"
 // +kubebuilder:validation:XValidation:rule="oldSelf.spec.persistence.accessMode == self.spec.persistence.accessMode",message="spec.persistence.accessMode is immutable"
 // +kubebuilder:validation:XValidation:rule="oldSelf.spec.persistence.storageClass == self.spec.persistence.storageClass",message="spec.persistence.storageClass is immutable"
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.spec.persistence.type) || oldSelf.spec.persistence.type == self.spec.persistence.type",message="spec.persistence.type is immutable"
 // +operator-sdk:csv:customresourcedefinitions:displayName="SpireServer"
 
 // SpireServer defines the configuration for the SPIRE Server managed by zero trust workload identity manager.
@@ -27,6 +35,15 @@ type SpireServer struct {
 }
 
 // SpireServerSpec defines the specifications for configuring the SPIRE server.
+// +kubebuilder:validation:XValidation:rule="!has(self.caValidity) || duration(self.caValidity) > duration('0s')",message="caValidity must be a positive duration"
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultX509Validity) || duration(self.defaultX509Validity) > duration('0s')",message="defaultX509Validity must be a positive duration"
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultJWTValidity) || duration(self.defaultJWTValidity) > duration('0s')",message="defaultJWTValidity must be a positive duration"
+// +kubebuilder:validation:XValidation:rule="!has(self.caValidity) || !has(self.defaultX509Validity) || duration(self.caValidity) >= duration(self.defaultX509Validity)",message="caValidity must be greater than defaultX509Validity"
+// +kubebuilder:validation:XValidation:rule="!has(self.caValidity) || !has(self.defaultJWTValidity) || duration(self.caValidity) >= duration(self.defaultJWTValidity)",message="caValidity must be greater than defaultJWTValidity"
+// +kubebuilder:validation:XValidation:rule="!has(self.caValidity) || !has(self.agentValidity) || duration(self.caValidity) >= duration(self.agentValidity)",message="caValidity must be greater than agentValidity"
+// +kubebuilder:validation:XValidation:rule="!has(self.persistence.type) || self.persistence.type != 'EmptyDir' || (has(self.datastore) && self.datastore.databaseType != 'sqlite3')",message="persistence.type EmptyDir requires an external datastore, the sqlite3 database would be lost when the pod restarts"
+// +kubebuilder:validation:XValidation:rule="!has(self.persistence.type) || self.persistence.type != 'EmptyDir' || (has(self.keyManager) && self.keyManager.memoryEnabled == 'true' && self.keyManager.diskEnabled == 'false')",message="persistence.type EmptyDir requires the memory key manager, set keyManager.diskEnabled to false and keyManager.memoryEnabled to true"
+// +kubebuilder:validation:XValidation:rule="!has(self.backup) || !has(self.datastore) || self.datastore.databaseType != 'sqlite3' || self.persistence.accessMode != 'ReadWriteOncePod'",message="backup of the sqlite3 datastore is not supported with the ReadWriteOncePod persistence access mode"
 type SpireServerSpec struct {
 	// logLevel sets the logging level for the operand.
 	// Valid values are: debug, info, warn, error.
@@ -73,6 +90,13 @@ type SpireServerSpec struct {
 	// +kubebuilder:default="5m"
 	DefaultJWTValidity metav1.Duration `json:"defaultJWTValidity"`
 
+	// agentValidity is the validity period (TTL) for the X.509 SVIDs issued to the SPIRE agents.
+	// Defaults to defaultX509Validity when unset.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	AgentValidity metav1.Duration `json:"agentValidity,omitempty"`
+
 	// caKeyType specifies the key type used for the server CA (both X509 and JWT).
 	// Valid values are: rsa-2048, rsa-4096, ec-p256, ec-p384.
 	// +kubebuilder:validation:Optional
@@ -108,10 +132,683 @@ type SpireServerSpec struct {
 	// +kubebuilder:validation:Optional
 	Federation *FederationConfig `json:"federation,omitempty"`
 
+	// podDisruptionBudget configures the PodDisruptionBudget for the SPIRE server StatefulSet.
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+
+	// topologySpreadConstraints spread the SPIRE server pods across topology domains, e.g. zones, as required
+	// by platform policies. When labelSelector is not set, the constraint selects the SPIRE server pods.
+	// Maximum 10 constraints allowed.
+	// The constraints are validated by the operator instead of the CRD schema.
+	// ref: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// healthPort is the port the SPIRE server serves its health checks on. It must not be used by the other
+	// listeners of the SPIRE server pods.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=8080
+	HealthPort int32 `json:"healthPort,omitempty"`
+
+	// livenessProbe tunes the liveness probe of the SPIRE server, e.g. on slow storage.
+	// +kubebuilder:validation:Optional
+	LivenessProbe *ProbeTimings `json:"livenessProbe,omitempty"`
+
+	// readinessProbe tunes the readiness probe of the SPIRE server.
+	// +kubebuilder:validation:Optional
+	ReadinessProbe *ProbeTimings `json:"readinessProbe,omitempty"`
+
+	// startupProbe adds a startup probe to the SPIRE server, which holds back the liveness probe until the
+	// server is live, e.g. while a datastore migration runs. Unset timings default to a period of 10 seconds
+	// and a failure threshold of 30.
+	// +kubebuilder:validation:Optional
+	StartupProbe *ProbeTimings `json:"startupProbe,omitempty"`
+
+	// extraConfig is deep-merged into the rendered SPIRE server configuration (server.conf), for
+	// SPIRE settings that are not modeled by this API. Keys set by the operator take precedence,
+	// and lists are not merged. The configuration is passed to SPIRE as is, so unsupported
+	// settings can prevent the server from starting. The trust domain is the trustDomain of the
+	// ZeroTrustWorkloadIdentityManager and can't be overridden.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraConfig *apiextensionsv1.JSON `json:"extraConfig,omitempty"`
+
+	// experimental is rendered into the experimental block of the SPIRE server configuration, to evaluate the
+	// experimental features of SPIRE without a change of this API, e.g. {"events_based_cache": "true"}. The values "true"
+	// and "false" are rendered as booleans and the other values as strings. Experimental features may change or
+	// be removed in any SPIRE release, so unsupported settings can prevent the server from starting.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=32
+	Experimental map[string]string `json:"experimental,omitempty"`
+
+	// bundleDistribution replicates the trust bundle ConfigMap published by the SPIRE server into
+	// the selected namespaces, for workloads that validate peer SVIDs without the CSI driver.
+	// The copies are kept up to date when the bundle rotates. Distribution is disabled when unset.
+	// +kubebuilder:validation:Optional
+	BundleDistribution *BundleDistributionConfig `json:"bundleDistribution,omitempty"`
+
+	// bundlePublishers continuously publish the trust bundle of the SPIRE server to locations read by
+	// relying parties outside of the cluster, e.g. S3 or Cloud Storage buckets, or additional ConfigMaps.
+	// The bundle is published again each time it rotates. Publishing is disabled when unset.
+	// +kubebuilder:validation:Optional
+	BundlePublishers *BundlePublishersConfig `json:"bundlePublishers,omitempty"`
+
+	// credentialComposers configure the CredentialComposer plugins of the SPIRE server, which customize
+	// the X509-SVIDs and JWT-SVIDs it issues, e.g. with custom claims or subject organizational units.
+	// +kubebuilder:validation:Optional
+	CredentialComposers *CredentialComposersConfig `json:"credentialComposers,omitempty"`
+
+	// upstreamAuthority has the SPIRE server CA signed by an upstream authority instead of being
+	// self-signed, e.g. to run the server as a downstream server of a nested SPIRE topology.
+	// +kubebuilder:validation:Optional
+	UpstreamAuthority *UpstreamAuthorityConfig `json:"upstreamAuthority,omitempty"`
+
+	// backup configures scheduled backups of the SPIRE server datastore and the restore of a backup,
+	// for disaster recovery. Backups are disabled when unset.
+	// +kubebuilder:validation:Optional
+	Backup *DatastoreBackupConfig `json:"backup,omitempty"`
+
+	// exposure publishes the agent-facing API of the SPIRE server outside of the cluster, so that SPIRE
+	// agents running on VMs or in other clusters can attest to the in-cluster server. The API is only
+	// reachable from the cluster when unset.
+	// +kubebuilder:validation:Optional
+	Exposure *ServerExposureConfig `json:"exposure,omitempty"`
+
+	// auditLog configures the audit logging of the SPIRE server API calls, e.g. the registration entry
+	// changes and the SVID signing requests, for compliance review. Audit logging is disabled when unset.
+	// +kubebuilder:validation:Optional
+	AuditLog *AuditLogConfig `json:"auditLog,omitempty"`
+
+	// rateLimit configures the rate limiting of the node attestations and of the SVID signing requests per
+	// client IP address, e.g. to be relaxed so that many agents can re-attest at once after a node pool
+	// replacement. SPIRE rate limits both when unset.
+	// +kubebuilder:validation:Optional
+	RateLimit *RateLimitConfig `json:"rateLimit,omitempty"`
+
+	// nodeAttestor tunes the k8s_psat node attestor verifying the SPIRE agents of the cluster, whose name is
+	// the clusterName of the ZeroTrustWorkloadIdentityManager.
+	// +kubebuilder:validation:Optional
+	NodeAttestor *ServerNodeAttestor `json:"nodeAttestor,omitempty"`
+
+	// controllerManager configures the identities issued by the spire-controller-manager managed alongside
+	// the SPIRE server, e.g. to enforce an organization-wide SPIFFE ID naming scheme.
+	// +kubebuilder:validation:Optional
+	ControllerManager *ControllerManagerConfig `json:"controllerManager,omitempty"`
+
+	// caRotation has the operator rotate the X.509 and JWT authorities of the SPIRE server through its local
+	// authority API, on a schedule or on demand, e.g. after a suspected key compromise. The new authorities are
+	// prepared, activated and the previous ones tainted, waiting for the bundle to propagate between the steps.
+	// The SPIRE server keeps rotating its authorities on its own when unset.
+	// +kubebuilder:validation:Optional
+	CARotation *CARotationConfig `json:"caRotation,omitempty"`
+
+	// configReload applies the changes of the settings the SPIRE server can change at runtime, currently the
+	// logLevel, to the running SPIRE server pods through the SPIRE server API instead of rolling out the
+	// StatefulSet, so that the issuance of identities isn't interrupted. The changes of the other settings
+	// still roll out the StatefulSet, and enabling it rolls out the StatefulSet once.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	ConfigReload string `json:"configReload,omitempty"`
+
+	// agentEviction evicts the SPIRE agents of the deleted nodes of the cluster from the SPIRE server, and
+	// deletes the registration entries parented to them or aliasing their nodes, so that the records of the
+	// nodes removed by a scale down don't linger in the datastore.
+	// +kubebuilder:default:="true"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	AgentEviction string `json:"agentEviction,omitempty"`
+
+	// entryPruning periodically deletes the registration entries whose selectors reference pods or namespaces
+	// that no longer exist, so that the datastore doesn't grow unbounded in clusters with a high churn of
+	// workloads. Pruning is disabled when unset.
+	// +kubebuilder:validation:Optional
+	EntryPruning *EntryPruningConfig `json:"entryPruning,omitempty"`
+
+	// version pins the SPIRE version of the SPIRE server, e.g. "1.12.4", or selects a minor version, e.g. "1.12",
+	// which runs its latest patch release supported by the operator. Unsupported versions are refused.
+	// Defaults to the SPIRE version shipped with the operator; pinning it holds the SPIRE server back
+	// while the operator is upgraded.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+(\.[0-9]+)?$`
+	Version string `json:"version,omitempty"`
+
+	// extraContainers are appended to the containers of the SPIRE server pods, e.g. a log shipping sidecar.
+	// The names must not be used by the containers of the operator.
+	// Maximum 10 containers allowed.
+	// The containers are validated by the operator and the API server instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraContainers []corev1.Container `json:"extraContainers,omitempty"`
+
+	// extraInitContainers are appended to the init containers of the SPIRE server pods.
+	// The names must not be used by the containers of the operator.
+	// Maximum 10 init containers allowed.
+	// The init containers are validated by the operator and the API server instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraInitContainers []corev1.Container `json:"extraInitContainers,omitempty"`
+
 	CommonConfig `json:",inline"`
 }
 
+// BundleDistributionConfig configures the namespaces the trust bundle is distributed to
+type BundleDistributionConfig struct {
+	// namespaceSelector selects the namespaces the trust bundle ConfigMap is copied into.
+	// An empty selector selects all namespaces watched by the operator.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// configMapName is the name of the ConfigMap holding the trust bundle in the selected namespaces.
+	// Defaults to the bundleConfigMap of the ZeroTrustWorkloadIdentityManager.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// BundlePublishFormat is the format the trust bundle is published in
+// +kubebuilder:validation:Enum=spiffe;jwks;pem
+type BundlePublishFormat string
+
+const (
+	// BundlePublishFormatSPIFFE publishes the bundle as a SPIFFE bundle document
+	BundlePublishFormatSPIFFE BundlePublishFormat = "spiffe"
+	// BundlePublishFormatJWKS publishes the bundle as a JSON Web Key Set
+	BundlePublishFormatJWKS BundlePublishFormat = "jwks"
+	// BundlePublishFormatPEM publishes the X.509 authorities of the bundle as PEM certificates
+	BundlePublishFormatPEM BundlePublishFormat = "pem"
+)
+
+// BundlePublishersConfig configures the BundlePublisher plugins of the SPIRE server
+// +kubebuilder:validation:XValidation:rule="has(self.awsS3) || has(self.gcpCloudStorage) || (has(self.configMaps) && size(self.configMaps) > 0)",message="at least one bundle publisher must be configured"
+type BundlePublishersConfig struct {
+	// awsS3 publishes the trust bundle to an object of an Amazon S3 bucket.
+	// +kubebuilder:validation:Optional
+	AWSS3 *AWSS3BundlePublisherConfig `json:"awsS3,omitempty"`
+
+	// gcpCloudStorage publishes the trust bundle to an object of a Google Cloud Storage bucket.
+	// +kubebuilder:validation:Optional
+	GCPCloudStorage *GCPCloudStorageBundlePublisherConfig `json:"gcpCloudStorage,omitempty"`
+
+	// configMaps publish the trust bundle to additional ConfigMaps of the operand namespace, in the
+	// format and under the key expected by the relying parties. The ConfigMaps are created by the operator.
+	// Maximum 10 ConfigMaps allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=map
+	// +listMapKey=name
+	ConfigMaps []ConfigMapBundlePublisherConfig `json:"configMaps,omitempty"`
+}
+
+// CredentialComposersConfig configures the built-in and external CredentialComposer plugins
+type CredentialComposersConfig struct {
+	// uniqueID enables the built-in uniqueid plugin, which adds the x509UniqueIdentifier attribute
+	// derived from the SPIFFE ID to the subject of the X509-SVIDs.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	UniqueID string `json:"uniqueID,omitempty"`
+
+	// external are CredentialComposer plugins loaded from binaries provided in an extra volume of
+	// the SPIRE server, e.g. an image volume or an emptyDir populated by an extra init container.
+	// The plugins are run in the order listed. Maximum 5 plugins allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=5
+	// +listType=map
+	// +listMapKey=name
+	External []ExternalCredentialComposerConfig `json:"external,omitempty"`
+}
+
+// ExternalCredentialComposerConfig configures an external CredentialComposer plugin
+type ExternalCredentialComposerConfig struct {
+	// name of the plugin in the SPIRE server configuration.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// volumeName is the name of the extra volume of the SPIRE server holding the plugin binary.
+	// The volume is mounted read-only into the spire-server container at
+	// /run/spire/credential-composers/<name>.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	VolumeName string `json:"volumeName"`
+
+	// path of the plugin binary, relative to the root of the volume.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	Path string `json:"path"`
+
+	// checksum is the SHA-256 checksum of the plugin binary, in hexadecimal. The SPIRE server
+	// refuses to load a binary with a different checksum.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	Checksum string `json:"checksum,omitempty"`
+
+	// pluginData is the configuration of the plugin, passed as is in its plugin_data.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PluginData *apiextensionsv1.JSON `json:"pluginData,omitempty"`
+}
+
+// AWSS3BundlePublisherConfig configures the aws_s3 BundlePublisher plugin
+type AWSS3BundlePublisherConfig struct {
+	// region of the bucket, e.g. us-east-1.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	Region string `json:"region"`
+
+	// bucket the trust bundle is published to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	Bucket string `json:"bucket"`
+
+	// objectKey is the key of the object holding the trust bundle, e.g. spire/bundle.json.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	ObjectKey string `json:"objectKey"`
+
+	// format of the published trust bundle.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="spiffe"
+	Format BundlePublishFormat `json:"format,omitempty"`
+
+	// endpoint overrides the S3 endpoint, e.g. for S3 compatible object stores.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=2048
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// credentialsSecretName is the name of a Secret in the operand namespace holding the
+	// aws_access_key_id and aws_secret_access_key keys of the AWS credentials the bundle is published
+	// with. The default AWS credential chain of the SPIRE server pods is used when unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// GCPCloudStorageBundlePublisherConfig configures the gcp_cloudstorage BundlePublisher plugin
+type GCPCloudStorageBundlePublisherConfig struct {
+	// bucketName is the name of the bucket the trust bundle is published to.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=222
+	BucketName string `json:"bucketName"`
+
+	// objectName is the name of the object holding the trust bundle, e.g. spire/bundle.json.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	ObjectName string `json:"objectName"`
+
+	// format of the published trust bundle.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="spiffe"
+	Format BundlePublishFormat `json:"format,omitempty"`
+
+	// credentialsSecretName is the name of a Secret in the operand namespace holding the
+	// service_account.json key of the service account the bundle is published with. The
+	// application default credentials of the SPIRE server pods are used when unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// ConfigMapBundlePublisherConfig configures a ConfigMap the k8s_configmap BundlePublisher plugin publishes the trust bundle to
+type ConfigMapBundlePublisherConfig struct {
+	// name of the ConfigMap in the operand namespace. It must not be the bundleConfigMap of the
+	// ZeroTrustWorkloadIdentityManager nor a ConfigMap of the operands.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// key of the ConfigMap holding the trust bundle.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	// +kubebuilder:default:="bundle"
+	Key string `json:"key,omitempty"`
+
+	// format of the published trust bundle.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="spiffe"
+	Format BundlePublishFormat `json:"format,omitempty"`
+}
+
+// UpstreamAuthorityConfig configures the upstream authority signing the SPIRE server CA
+type UpstreamAuthorityConfig struct {
+	// spire has the server CA signed by an upstream SPIRE server of the same trust domain, making
+	// this server a downstream server. The upstream server must hold a downstream registration entry
+	// for the SPIRE server pods, e.g. with the selectors k8s:ns:<operand namespace> and k8s:sa:spire-server.
+	// +kubebuilder:validation:Required
+	Spire *SpireUpstreamAuthorityConfig `json:"spire"`
+}
+
+// SpireUpstreamAuthorityConfig configures the upstream SPIRE server and how this server authenticates to it
+type SpireUpstreamAuthorityConfig struct {
+	// serverAddress is the address of the upstream SPIRE server.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	ServerAddress string `json:"serverAddress"`
+
+	// serverPort is the port of the upstream SPIRE server.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=8081
+	ServerPort int32 `json:"serverPort,omitempty"`
+
+	// workloadAPICSIDriver is the name of the CSI driver exposing the Workload API of an agent of the
+	// upstream SPIRE server on the nodes running the SPIRE server. The server fetches the SVID it
+	// authenticates to the upstream server with from that agent, so the driver must not be the
+	// SPIFFE CSI driver deployed by the operator.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	WorkloadAPICSIDriver string `json:"workloadAPICSIDriver"`
+
+	// workloadAPISocketName is the name of the Workload API socket in the volume of the CSI driver.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:default:="spire-agent.sock"
+	WorkloadAPISocketName string `json:"workloadAPISocketName,omitempty"`
+}
+
+// DatastoreBackupConfig configures the CronJob backing up the SPIRE server datastore.
+// The sqlite3 database file is copied from the data volume of the SPIRE server, and postgres
+// databases are dumped with pg_dump. Other database types are not supported.
+type DatastoreBackupConfig struct {
+	// schedule of the backups, in cron format, e.g. "0 2 * * *".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=128
+	Schedule string `json:"schedule"`
+
+	// persistentVolumeClaim is the name of an existing PersistentVolumeClaim of the operand namespace
+	// the backups are written to. Object storage can be used through a PVC of a CSI driver backed by it.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	PersistentVolumeClaim string `json:"persistentVolumeClaim"`
+
+	// retention is the number of backups kept on the volume, the oldest backups are deleted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default:=7
+	Retention int32 `json:"retention,omitempty"`
+
+	// restoreFrom is the file name of a backup of the volume restored into the datastore before the
+	// SPIRE server starts, e.g. "datastore-20260102030405.dump". The backup is restored once, and a
+	// different backup can be restored by changing the file name. Requires PersistentVolumeClaim persistence.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^datastore-[0-9]{14}\.(sqlite3|dump)$`
+	RestoreFrom string `json:"restoreFrom,omitempty"`
+}
+
+// ServerExposureType is how the agent-facing API of the SPIRE server is published outside of the cluster
+// +kubebuilder:validation:Enum=LoadBalancer;Route
+type ServerExposureType string
+
+const (
+	// ServerExposureLoadBalancer publishes the API with a Service of type LoadBalancer
+	ServerExposureLoadBalancer ServerExposureType = "LoadBalancer"
+
+	// ServerExposureRoute publishes the API with an OpenShift Route with TLS passthrough
+	ServerExposureRoute ServerExposureType = "Route"
+)
+
+// ServerExposureConfig configures the publication of the agent-facing API of the SPIRE server.
+// The agents outside of the cluster connect to the advertised address, reported in
+// status.advertisedAddress, and authenticate the SPIRE server with the trust bundle.
+// +kubebuilder:validation:XValidation:rule="self.type == 'LoadBalancer' || !has(self.loadBalancer)",message="loadBalancer is only allowed with the LoadBalancer type"
+type ServerExposureConfig struct {
+	// type of the publication of the API.
+	// "LoadBalancer": a Service of type LoadBalancer, in front of the SPIRE server pods.
+	// "Route": an OpenShift Route with TLS passthrough. The agents connect to port 443 of the router,
+	// with the host of the Route as TLS server name.
+	// +kubebuilder:validation:Required
+	Type ServerExposureType `json:"type"`
+
+	// advertisedAddress is the host name or IP address the agents outside of the cluster connect to,
+	// e.g. a DNS record pointing to the load balancer. With the Route type, it is the host of the Route
+	// and must be a host name. When not set, the address assigned to the LoadBalancer Service or the
+	// host generated by the router is advertised.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	AdvertisedAddress string `json:"advertisedAddress,omitempty"`
+
+	// loadBalancer configures the LoadBalancer Service.
+	// +kubebuilder:validation:Optional
+	LoadBalancer *LoadBalancerExposureConfig `json:"loadBalancer,omitempty"`
+
+	// joinToken enables the join_token node attestor, for the agents outside of the cluster to attest
+	// with a one-time token generated with "spire-server token generate". The agents running in the
+	// cluster keep attesting with the k8s_psat node attestor.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	JoinToken string `json:"joinToken,omitempty"`
+}
+
+// LoadBalancerExposureConfig configures the LoadBalancer Service publishing the SPIRE server API
+type LoadBalancerExposureConfig struct {
+	// port of the load balancer the agents connect to.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=443
+	Port int32 `json:"port,omitempty"`
+
+	// annotations are added to the Service, e.g. to request an internal load balancer from the cloud provider.
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// sourceRanges restrict the client IP ranges allowed by the load balancer, e.g. "10.0.0.0/8".
+	// Maximum 32 ranges allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=set
+	SourceRanges []string `json:"sourceRanges,omitempty"`
+}
+
+// ControllerManagerConfig configures the spire-controller-manager and the default ClusterSPIFFEID the
+// operator creates for the workloads no other ClusterSPIFFEID matches.
+type ControllerManagerConfig struct {
+	// className is the class of the ClusterSPIFFEID, ClusterFederatedTrustDomain and ClusterStaticEntry
+	// resources the spire-controller-manager reconciles. The resources the operator creates are of this class.
+	// Defaults to "zero-trust-workload-identity-manager-spire".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	ClassName string `json:"className,omitempty"`
+
+	// defaultSPIFFEIDTemplate is the SPIFFE ID template of the default ClusterSPIFFEID, rendered by the
+	// spire-controller-manager for every selected pod. It must produce an ID of the trust domain, e.g.
+	// "spiffe://{{ .TrustDomain }}/ns/{{ .PodMeta.Namespace }}/sa/{{ .PodSpec.ServiceAccountName }}", the default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=512
+	// +kubebuilder:validation:Pattern=`^spiffe://`
+	DefaultSPIFFEIDTemplate string `json:"defaultSPIFFEIDTemplate,omitempty"`
+
+	// namespaceSelector selects the namespaces of the pods the default ClusterSPIFFEID issues identities to.
+	// The operand namespace is always excluded. All namespaces are selected when unset.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// podSelector selects the pods the default ClusterSPIFFEID issues identities to.
+	// All pods of the selected namespaces are selected when unset.
+	// +kubebuilder:validation:Optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// dnsNameTemplates are the templates of the DNS SANs of the X.509-SVIDs issued by the default ClusterSPIFFEID,
+	// for the TLS clients validating the server names, e.g. "{{ .PodMeta.Name }}" or
+	// "{{ .PodMeta.Name }}.{{ .PodMeta.Namespace }}.svc". The first DNS name is also the SVID subject common name.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MaxLength=253
+	DNSNameTemplates []string `json:"dnsNameTemplates,omitempty"`
+
+	// autoPopulateDNSNames adds the DNS names of the Services selecting a pod, e.g. "<service>.<namespace>.svc",
+	// to the X.509-SVIDs issued by the default ClusterSPIFFEID.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	AutoPopulateDNSNames string `json:"autoPopulateDNSNames,omitempty"`
+}
+
+// AuditLogConfig configures the SPIRE server audit log. The audit entries are written to the
+// server log, with the caller, the API method and its result.
+type AuditLogConfig struct {
+	// enabled controls whether the SPIRE server writes an audit entry for every API call.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Enabled string `json:"enabled,omitempty"`
+
+	// format is the output format of the server log when audit logging is enabled, overriding logFormat.
+	// "json" eases the ingestion of the audit entries by log collectors.
+	// +kubebuilder:validation:Enum=text;json
+	// +kubebuilder:validation:Optional
+	Format string `json:"format,omitempty"`
+}
+
+// ServerNodeAttestor configures the k8s_psat node attestor of the SPIRE server.
+type ServerNodeAttestor struct {
+	// k8sPSATAudience are the audiences accepted in the projected service account tokens of the SPIRE agents.
+	// It must include the k8sPSATAudience of the SpireAgent. Defaults to "spire-server".
+	// Maximum 10 audiences allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=253
+	// +listType=set
+	K8sPSATAudience []string `json:"k8sPSATAudience,omitempty"`
+
+	// k8sPSATServiceAccountAllowList are the service accounts, as "<namespace>:<name>", the SPIRE agents are allowed
+	// to attest with. Defaults to the spire-agent service account of the operand namespace.
+	// Maximum 20 service accounts allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:items:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?:[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +kubebuilder:validation:items:MaxLength=317
+	// +listType=set
+	K8sPSATServiceAccountAllowList []string `json:"k8sPSATServiceAccountAllowList,omitempty"`
+}
+
+// RateLimitConfig configures the rate limits of the SPIRE server API.
+type RateLimitConfig struct {
+	// attestation controls whether the node attestations are rate limited per client IP address.
+	// +kubebuilder:default:="true"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Attestation string `json:"attestation,omitempty"`
+
+	// signing controls whether the X.509 and JWT SVID signing requests are rate limited per client IP address.
+	// +kubebuilder:default:="true"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Signing string `json:"signing,omitempty"`
+}
+
+// CARotationConfig configures the rotations of the SPIRE server authorities driven by the operator.
+// +kubebuilder:validation:XValidation:rule="!has(self.propagationDelay) || duration(self.propagationDelay) >= duration('0s')",message="propagationDelay must not be negative"
+// +kubebuilder:validation:XValidation:rule="!has(self.interval) || duration(self.interval) == duration('0s') || duration(self.interval) >= duration('1h')",message="interval must be at least 1h"
+// +kubebuilder:validation:XValidation:rule="!has(self.interval) || !has(self.propagationDelay) || duration(self.interval) == duration('0s') || duration(self.interval) - duration(self.propagationDelay) > duration(self.propagationDelay)",message="interval must be longer than twice the propagation delay"
+type CARotationConfig struct {
+	// interval starts a rotation when the last rotation completed this long ago, e.g. "720h". Rotations are
+	// only started on demand when unset.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// request starts a rotation on demand when it is set to a value different from the last request
+	// served, e.g. the current date.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	Request string `json:"request,omitempty"`
+
+	// propagationDelay is waited after preparing the new authorities before activating them, and after
+	// activating them before tainting the previous ones, so that the agents and the workloads trust the new
+	// authorities before SVIDs are signed with them, and hold SVIDs signed with them before the previous ones
+	// are tainted.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	PropagationDelay metav1.Duration `json:"propagationDelay,omitempty"`
+}
+
+// EntryPruningConfig configures the pruning of the stale registration entries of the SPIRE server. An entry is
+// stale when a k8s:ns, k8s:pod-name or k8s:pod-uid selector of the entry references a namespace or a pod that
+// doesn't exist. Only the entries parented to the SPIRE agents of the cluster are pruned.
+type EntryPruningConfig struct {
+	// interval is the time between two sweeps of the registration entries.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// gracePeriod is how long an entry must have been found stale before it is pruned, so that the entries of
+	// the pods and namespaces being recreated, e.g. the pods of a StatefulSet, are kept.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// dryRun only reports the stale entries in a StaleEntriesFound event on the SpireServer instead of
+	// deleting them.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	DryRun string `json:"dryRun,omitempty"`
+}
+
+// CARotationPhase is the last step of a rotation of the SPIRE server authorities
+// +kubebuilder:validation:Enum=Prepared;Activated;Completed
+type CARotationPhase string
+
+const (
+	// CARotationPrepared is set once the new authorities are prepared
+	CARotationPrepared CARotationPhase = "Prepared"
+
+	// CARotationActivated is set once the new authorities are activated
+	CARotationActivated CARotationPhase = "Activated"
+
+	// CARotationCompleted is set once the previous authorities are tainted
+	CARotationCompleted CARotationPhase = "Completed"
+)
+
 // FederationConfig defines federation bundle endpoint and federated trust domains
+// +kubebuilder:validation:XValidation:rule="!has(self.federatesWith) || self.federatesWith.all(f, self.federatesWith.exists_one(g, g.trustDomain == f.trustDomain))",message="federatesWith must not list a trust domain twice"
 type FederationConfig struct {
 	// bundleEndpoint configures this cluster's federation bundle endpoint
 	// +kubebuilder:validation:Required
@@ -182,6 +879,7 @@ type HttpsWebConfig struct {
 }
 
 // AcmeConfig configures ACME certificate provisioning
+// +kubebuilder:validation:XValidation:rule="has(self.tosAccepted) && self.tosAccepted == 'true'",message="tosAccepted must be true to use ACME"
 type AcmeConfig struct {
 	// directoryUrl is the ACME directory URL (e.g., Let's Encrypt)
 	// +kubebuilder:validation:Required
@@ -229,6 +927,7 @@ type ServingCertConfig struct {
 type FederatesWithConfig struct {
 	// trustDomain is the federated trust domain name
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=255
 	// +kubebuilder:validation:Pattern=`^[a-z0-9._-]{1,255}$`
 	TrustDomain string `json:"trustDomain"`
 
@@ -250,6 +949,16 @@ type FederatesWithConfig struct {
 
 // Persistence defines volume-related settings.
 type Persistence struct {
+	// type of the volume holding the SPIRE server data directory.
+	// "PersistentVolumeClaim": A PersistentVolumeClaim is provisioned from the storage class.
+	// "EmptyDir": An emptyDir volume limited to size is used, for clusters without storage.
+	// The data is lost when the pod restarts, so emptyDir requires an external datastore
+	// and the memory key manager.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=PersistentVolumeClaim;EmptyDir
+	// +kubebuilder:default:=PersistentVolumeClaim
+	Type string `json:"type,omitempty"`
+
 	// size of the persistent volume (e.g., 1Gi).
 	// +kubebuilder:validation:Pattern=^[1-9][0-9]*Gi$
 	// +kubebuilder:default:="1Gi"
@@ -359,6 +1068,129 @@ type CASubject struct {
 type SpireServerStatus struct {
 	// conditions holds information about the current state of the SPIRE server resources.
 	ConditionalStatus `json:",inline,omitempty"`
+
+	// federatedTrustDomains reports the health of the bundle endpoint of each trust domain
+	// listed in spec.federation.federatesWith, so that stale partner bundles can be alerted on.
+	// +optional
+	// +listType=map
+	// +listMapKey=trustDomain
+	FederatedTrustDomains []FederatedTrustDomainStatus `json:"federatedTrustDomains,omitempty"`
+
+	// advertisedAddress is the host:port the agents outside of the cluster set as server address and
+	// port when spec.exposure is set. It is empty until the load balancer or the Route is assigned an address.
+	// +optional
+	AdvertisedAddress string `json:"advertisedAddress,omitempty"`
+
+	// certificateAuthority reports the X.509 CA of the SPIRE server and the trust bundle it serves, as
+	// read from the SPIRE server API, so that their expiry can be monitored without exec'ing into the pods.
+	// +optional
+	CertificateAuthority *CertificateAuthorityStatus `json:"certificateAuthority,omitempty"`
+
+	// caRotation reports the progress of the rotation of the SPIRE server authorities driven by the operator
+	// when spec.caRotation is set.
+	// +optional
+	CARotation *CARotationStatus `json:"caRotation,omitempty"`
+
+	// logLevel is the log level applied to the running SPIRE server pods when spec.configReload is enabled.
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// CARotationStatus reports the progress of the rotation of the SPIRE server authorities.
+type CARotationStatus struct {
+	// phase is the last step of the current or last rotation.
+	// +optional
+	Phase CARotationPhase `json:"phase,omitempty"`
+
+	// phaseTransitionTime is the time phase was last updated.
+	// +optional
+	PhaseTransitionTime *metav1.Time `json:"phaseTransitionTime,omitempty"`
+
+	// request is the spec.caRotation.request served by the current or last rotation.
+	// +optional
+	Request string `json:"request,omitempty"`
+
+	// lastCompletionTime is the time the last rotation completed.
+	// +optional
+	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
+}
+
+// CertificateAuthorityStatus reports the X.509 CA of the SPIRE server and its trust bundle.
+type CertificateAuthorityStatus struct {
+	// active is the X.509 CA the SPIRE server currently signs the X509-SVIDs with.
+	// +optional
+	Active *X509AuthorityStatus `json:"active,omitempty"`
+
+	// prepared is the X.509 CA the SPIRE server prepared to rotate to. It is only set once the SPIRE
+	// server added the next CA to the trust bundle, ahead of activating it.
+	// +optional
+	Prepared *X509AuthorityStatus `json:"prepared,omitempty"`
+
+	// bundleCertificateCount is the number of X.509 authorities in the trust bundle.
+	// +optional
+	BundleCertificateCount int32 `json:"bundleCertificateCount,omitempty"`
+
+	// bundleSHA256 is the hex encoded SHA-256 digest of the X.509 authorities of the trust bundle.
+	// It changes when the SPIRE server adds or removes a CA.
+	// +optional
+	BundleSHA256 string `json:"bundleSHA256,omitempty"`
+
+	// lastRefreshTime is the time the CA and the trust bundle were last read from the SPIRE server.
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+
+	// message explains why the last read from the SPIRE server failed. The CA and the trust bundle
+	// last read successfully are kept meanwhile.
+	// +optional
+	// +kubebuilder:validation:MaxLength=32768
+	Message string `json:"message,omitempty"`
+}
+
+// X509AuthorityStatus describes an X.509 CA certificate.
+type X509AuthorityStatus struct {
+	// subject is the distinguished name of the CA certificate.
+	// +required
+	Subject string `json:"subject"`
+
+	// notBefore is the time the CA certificate is valid from.
+	// +required
+	NotBefore metav1.Time `json:"notBefore"`
+
+	// notAfter is the time the CA certificate expires.
+	// +required
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// FederatedTrustDomainStatus reports the health of the bundle endpoint of a federated trust domain,
+// as probed by the operator.
+type FederatedTrustDomainStatus struct {
+	// trustDomain is the federated trust domain name.
+	// +required
+	TrustDomain string `json:"trustDomain"`
+
+	// bundleEndpointUrl is the URL of the bundle endpoint of the federated trust domain.
+	// +required
+	BundleEndpointUrl string `json:"bundleEndpointUrl"`
+
+	// healthy indicates whether the last fetch of the bundle from the bundle endpoint succeeded.
+	// Valid values are "true" and "false".
+	// +kubebuilder:validation:Pattern=`^(true|false)$`
+	// +required
+	Healthy string `json:"healthy"`
+
+	// message explains why the last fetch of the bundle failed.
+	// +optional
+	// +kubebuilder:validation:MaxLength=32768
+	Message string `json:"message,omitempty"`
+
+	// lastSuccessfulRefreshTime is the time the bundle was last fetched successfully.
+	// +optional
+	LastSuccessfulRefreshTime *metav1.Time `json:"lastSuccessfulRefreshTime,omitempty"`
+
+	// bundleSHA256 is the hex encoded SHA-256 digest of the bundle last fetched successfully.
+	// It changes when the federated trust domain rotates its keys.
+	// +optional
+	BundleSHA256 string `json:"bundleSHA256,omitempty"`
 }
 
 // GetConditionalStatus returns the conditional status of the SpireServer
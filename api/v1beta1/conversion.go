@@ -0,0 +1,121 @@
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// convertFields copies spec and status between API versions.
+// The v1beta1 schema is currently identical to v1alpha1, so the fields are converted through
+// their JSON representation. Fields that are renamed or restructured in v1beta1 must be
+// converted explicitly in the ConvertTo and ConvertFrom functions of the affected type.
+func convertFields(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// ConvertTo converts this ZeroTrustWorkloadIdentityManager to the hub version (v1alpha1).
+func (src *ZeroTrustWorkloadIdentityManager) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version.
+func (dst *ZeroTrustWorkloadIdentityManager) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertTo converts this SpireServer to the hub version (v1alpha1).
+func (src *SpireServer) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.SpireServer)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version.
+func (dst *SpireServer) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.SpireServer)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertTo converts this SpireAgent to the hub version (v1alpha1).
+func (src *SpireAgent) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.SpireAgent)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version.
+func (dst *SpireAgent) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.SpireAgent)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertTo converts this SpiffeCSIDriver to the hub version (v1alpha1).
+func (src *SpiffeCSIDriver) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.SpiffeCSIDriver)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version.
+func (dst *SpiffeCSIDriver) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.SpiffeCSIDriver)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertTo converts this SpireOIDCDiscoveryProvider to the hub version (v1alpha1).
+func (src *SpireOIDCDiscoveryProvider) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.SpireOIDCDiscoveryProvider)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v1alpha1) to this version.
+func (dst *SpireOIDCDiscoveryProvider) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.SpireOIDCDiscoveryProvider)
+	dst.ObjectMeta = src.ObjectMeta
+	if err := convertFields(&src.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	return convertFields(&src.Status, &dst.Status)
+}
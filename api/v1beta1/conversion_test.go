@@ -0,0 +1,97 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestSpireServerConversionRoundTrip(t *testing.T) {
+	hub := &v1alpha1.SpireServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", ResourceVersion: "42"},
+		Spec: v1alpha1.SpireServerSpec{
+			JwtIssuer:  "https://oidc.example.org",
+			CAValidity: metav1.Duration{Duration: 24 * time.Hour},
+			Datastore:  v1alpha1.DataStore{DatabaseType: "postgres", ConnectionString: "dbname=spire"},
+			Federation: &v1alpha1.FederationConfig{
+				BundleEndpoint: v1alpha1.BundleEndpointConfig{Profile: v1alpha1.HttpsSpiffeProfile, RefreshHint: 300},
+			},
+			PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{MinAvailable: ptr.To(intstr.FromInt32(1))},
+			CommonConfig:        v1alpha1.CommonConfig{Labels: map[string]string{"team": "security"}},
+		},
+		Status: v1alpha1.SpireServerStatus{ConditionalStatus: v1alpha1.ConditionalStatus{
+			Conditions: []metav1.Condition{{Type: v1alpha1.Ready, Status: metav1.ConditionTrue, Reason: v1alpha1.ReasonReady}},
+		}},
+	}
+
+	spoke := &SpireServer{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if spoke.Spec.Datastore.DatabaseType != "postgres" || spoke.Spec.Federation == nil {
+		t.Fatalf("ConvertFrom() did not copy the spec: %+v", spoke.Spec)
+	}
+
+	roundTripped := &v1alpha1.SpireServer{}
+	if err := spoke.ConvertTo(roundTripped); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if !equality.Semantic.DeepEqual(hub, roundTripped) {
+		t.Errorf("Round trip changed the object:\nwant %+v\ngot  %+v", hub, roundTripped)
+	}
+}
+
+func TestSpireOIDCDiscoveryProviderConversionRoundTrip(t *testing.T) {
+	hub := &v1alpha1.SpireOIDCDiscoveryProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			JwtIssuer:    "https://oidc.example.org",
+			ReplicaCount: 2,
+			Autoscaling:  &v1alpha1.AutoscalingConfig{MinReplicas: 2, MaxReplicas: 5},
+		},
+	}
+
+	spoke := &SpireOIDCDiscoveryProvider{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	roundTripped := &v1alpha1.SpireOIDCDiscoveryProvider{}
+	if err := spoke.ConvertTo(roundTripped); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if !equality.Semantic.DeepEqual(hub, roundTripped) {
+		t.Errorf("Round trip changed the object:\nwant %+v\ngot  %+v", hub, roundTripped)
+	}
+}
+
+func TestZeroTrustWorkloadIdentityManagerConversionRoundTrip(t *testing.T) {
+	hub := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			ClusterName:     "test-cluster",
+			BundleConfigMap: "spire-bundle",
+		},
+		Status: v1alpha1.ZeroTrustWorkloadIdentityManagerStatus{
+			Operands: []v1alpha1.OperandStatus{{Name: "cluster", Kind: "SpireServer", Ready: "true"}},
+		},
+	}
+
+	spoke := &ZeroTrustWorkloadIdentityManager{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	roundTripped := &v1alpha1.ZeroTrustWorkloadIdentityManager{}
+	if err := spoke.ConvertTo(roundTripped); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	if !equality.Semantic.DeepEqual(hub, roundTripped) {
+		t.Errorf("Round trip changed the object:\nwant %+v\ngot  %+v", hub, roundTripped)
+	}
+}
@@ -0,0 +1,156 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=scsi
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="SpiffeCSIDriver is a singleton, .metadata.name must be 'cluster'"
+// +operator-sdk:csv:customresourcedefinitions:displayName="SpiffeCSIDriver"
+
+// SpiffeCSIDriver defines the configuration for the SPIFFE CSI Driver managed by zero trust workload identity manager.
+// This includes settings related to the registration, socket paths, plugin name and optional runtime flags that influence how the driver operates.
+type SpiffeCSIDriver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              SpiffeCSIDriverSpec   `json:"spec,omitempty"`
+	Status            SpiffeCSIDriverStatus `json:"status,omitempty"`
+}
+
+// SpiffeCSIDriverSpec defines the specifications for configuration related to the SPIFFE CSI driver.
+type SpiffeCSIDriverSpec struct {
+
+	// agentSocketPath is the path to the directory containing the SPIRE agent's Workload API socket.
+	// This directory will be bind-mounted into workload containers by the CSI driver.
+	// The directory is shared between the SPIRE agent and CSI driver via a hostPath volume.
+	// Must match SpireAgent.spec.socketPath, the CSI driver is not reconciled while they differ.
+	// Must be an absolute path without traversal attempts or null bytes.
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^/[a-zA-Z0-9._/\-]*$`
+	// +kubebuilder:default:="/run/spire/agent-sockets"
+	AgentSocketPath string `json:"agentSocketPath,omitempty"`
+
+	// pluginName specifies the name of the CSI plugin.
+	// This sets the CSI driver name that will be deployed to the cluster and used in
+	// VolumeMount configurations. Must match the driver name referenced in workload pods.
+	// Must be a valid domain name format (e.g., csi.spiffe.io).
+	// +kubebuilder:validation:MaxLength=127
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +kubebuilder:default:="csi.spiffe.io"
+	PluginName string `json:"pluginName,omitempty"`
+
+	// kubeletPath is the root directory of the kubelet on the nodes.
+	// The CSI driver registers its plugin socket and mounts the workload volumes below this directory,
+	// so it must match the kubelet --root-dir of non-standard kubelets.
+	// Must be an absolute path without traversal attempts or null bytes.
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^/[a-zA-Z0-9._/\-]*$`
+	// +kubebuilder:default:="/var/lib/kubelet"
+	KubeletPath string `json:"kubeletPath,omitempty"`
+
+	// nodeDriverRegistrar configures the node-driver-registrar sidecar, which registers the
+	// CSI plugin with the kubelet and serves the health endpoint of the CSI driver pods.
+	// +kubebuilder:validation:Optional
+	NodeDriverRegistrar *NodeDriverRegistrarConfig `json:"nodeDriverRegistrar,omitempty"`
+
+	// securityContextConstraints is the name of an existing SecurityContextConstraints the SPIFFE CSI driver pods are
+	// admitted with, instead of the SecurityContextConstraints created by the operator, e.g. to comply with a
+	// cluster policy on privileged workloads. The operator requires the pods to use it, so the cluster admin must
+	// grant its use to the spire-spiffe-csi-driver service account of the operand namespace. It must allow the
+	// host path volumes and the privileged containers.
+	// The SecurityContextConstraints previously created by the operator are removed.
+	// Must be a valid Kubernetes name.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	SecurityContextConstraints string `json:"securityContextConstraints,omitempty"`
+
+	CommonConfig `json:",inline"`
+}
+
+// NodeDriverRegistrarConfig defines the configuration of the node-driver-registrar sidecar.
+type NodeDriverRegistrarConfig struct {
+	// image overrides the node-driver-registrar image set on the operator, e.g. to pull it
+	// from a mirror that satisfies an image provenance policy. The image is used as is,
+	// also when the cluster runs in FIPS mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=512
+	Image string `json:"image,omitempty"`
+
+	// resources define the resource requirements of the sidecar.
+	// Defaults to the resources of the SpiffeCSIDriver spec.
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// healthPort is the port the sidecar serves its health endpoint on.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=9809
+	HealthPort int32 `json:"healthPort,omitempty"`
+
+	// livenessProbe tunes the liveness probe of the sidecar, e.g. on slow nodes.
+	// +kubebuilder:validation:Optional
+	LivenessProbe *ProbeTimings `json:"livenessProbe,omitempty"`
+}
+
+// ProbeTimings defines the timings of a container probe. Unset fields keep the operator defaults.
+type ProbeTimings struct {
+	// initialDelaySeconds is the number of seconds after the container started before the probe runs.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// timeoutSeconds is the number of seconds after which the probe times out.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=300
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// periodSeconds is how often, in seconds, the probe runs.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// failureThreshold is the number of consecutive failures after which the container is restarted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// SpiffeCSIDriverStatus defines the observed state of the SPIFFE CSI driver reconciliation performed by the operator
+type SpiffeCSIDriverStatus struct {
+	// conditions holds information about the current state of the SPIFFE CSI driver deployment.
+	ConditionalStatus `json:",inline,omitempty"`
+}
+
+// GetConditionalStatus returns the conditional status of the SpiffeCSIDriver
+func (s *SpiffeCSIDriver) GetConditionalStatus() ConditionalStatus {
+	return s.Status.ConditionalStatus
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SpiffeCSIDriverList contains a list of SpiffeCSIDriver
+type SpiffeCSIDriverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SpiffeCSIDriver `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SpiffeCSIDriver{}, &SpiffeCSIDriverList{})
+}
@@ -0,0 +1,694 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ztwim
+// +kubebuilder:printcolumn:name="Trust Domain",type=string,JSONPath=`.spec.trustDomain`
+// +kubebuilder:printcolumn:name="Cluster Name",type=string,JSONPath=`.spec.clusterName`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=1
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="ZeroTrustWorkloadIdentityManager is a singleton, .metadata.name must be 'cluster'"
+// +operator-sdk:csv:customresourcedefinitions:displayName="ZeroTrustWorkloadIdentityManager"
+
+// ZeroTrustWorkloadIdentityManager defines the configuration for the
+// operator that manages the lifecycle of SPIRE components in OpenShift
+// clusters.
+//
+// Note: This resource is *intended as a global config for operands managed
+// by zero-trust-workload-identity-manager. It does not contain
+// low-level configuration for SPIRE components, which is managed separately
+// in the SpireConfig CRD.
+type ZeroTrustWorkloadIdentityManager struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ZeroTrustWorkloadIdentityManagerSpec   `json:"spec,omitempty"`
+	Status            ZeroTrustWorkloadIdentityManagerStatus `json:"status,omitempty"`
+}
+
+// ZeroTrustWorkloadIdentityManagerStatus defines the observed state of ZeroTrustWorkloadIdentityManager.
+// It aggregates the status from all managed operand CRs and provides an overall health view.
+type ZeroTrustWorkloadIdentityManagerStatus struct {
+	// conditions represent the latest available observations of the zero-trust-workload-identity-manager's state.
+	// This includes the aggregated status from all managed operand CRs.
+	ConditionalStatus `json:",inline,omitempty"`
+
+	// operands holds the status of each managed operand CR.
+	// Operands are indexed by their kind since all operands are named "cluster".
+	// This provides a quick overview of the health of each SPIRE component.
+	// +optional
+	// +listType=map
+	// +listMapKey=kind
+	Operands []OperandStatus `json:"operands,omitempty"`
+}
+
+// GetConditionalStatus returns the conditional status of the ZeroTrustWorkloadIdentityManager
+func (z *ZeroTrustWorkloadIdentityManager) GetConditionalStatus() ConditionalStatus {
+	return z.Status.ConditionalStatus
+}
+
+// OperandStatus represents the status of a single managed operand CR.
+// Each operand corresponds to a SPIRE component (e.g., SpireServer, SpireAgent).
+type OperandStatus struct {
+	// name is the name of the operand resource.
+	// For singleton resources, this is typically "cluster".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// kind is the Kind of the operand CR.
+	// Must be one of: SpireServer, SpireAgent, SpiffeCSIDriver, SpireOIDCDiscoveryProvider.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=SpireServer;SpireAgent;SpiffeCSIDriver;SpireOIDCDiscoveryProvider
+	// +required
+	Kind string `json:"kind"`
+
+	// ready indicates whether the operand is in a ready state.
+	// An operand is considered ready when all its resources are available and functioning correctly.
+	// Valid values are "true" and "false".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^(true|false)$`
+	// +required
+	Ready string `json:"ready"`
+
+	// message provides human-readable details about the operand's current state.
+	// This may include information about why an operand is not ready or other relevant status details.
+	// +optional
+	// +kubebuilder:validation:MaxLength=32768
+	Message string `json:"message,omitempty"`
+
+	// conditions represent the latest available observations of the operand's state.
+	// This includes key conditions from the operand CR that are relevant for overall health monitoring.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ZeroTrustWorkloadIdentityManagerList contains a list of ZeroTrustWorkloadIdentityManager
+type ZeroTrustWorkloadIdentityManagerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ZeroTrustWorkloadIdentityManager `json:"items"`
+}
+
+// ZeroTrustWorkloadIdentityManagerSpec defines the desired state of the ZeroTrustWorkloadIdentityManager
+type ZeroTrustWorkloadIdentityManagerSpec struct {
+	// trustDomain to be used for the SPIFFE identifiers.
+	// This field is immutable, since changing it invalidates every identity issued, unless the
+	// ztwim.openshift.io/allow-identity-change annotation is set to "true" for an intentional migration.
+	// Must be a valid SPIFFE trust domain (lowercase alphanumeric, hyphens, and dots).
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9\-\.]*[a-z0-9])?$`
+	TrustDomain string `json:"trustDomain,omitempty"`
+
+	// clusterName identifies this cluster within the trust domain.
+	// This field is immutable, since the SPIFFE IDs of the SPIRE agents derive from it, unless the
+	// ztwim.openshift.io/allow-identity-change annotation is set to "true" for an intentional migration.
+	// Must be a valid DNS-1123 subdomain.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// bundleConfigMap is the name of the ConfigMap that stores the SPIRE trust bundle.
+	// This ConfigMap contains the root certificates for the trust domain specified in trustDomain.
+	// The operator will create and maintain this ConfigMap.
+	// This field is immutable.
+	// Must be a valid Kubernetes name.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=spire-bundle
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="bundleConfigMap is immutable and cannot be changed"
+	BundleConfigMap string `json:"bundleConfigMap"`
+
+	// operandNamespace is the namespace where the SPIRE operands are installed.
+	// When not set, the operands are installed in the operator namespace.
+	// The namespace must exist, unless operandNamespaceManagement is enabled, and be watched by the operator,
+	// i.e. be the operator namespace or one of the namespaces listed in the WATCH_NAMESPACE environment variable.
+	// This field is immutable.
+	// Must be a valid DNS-1123 label.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="operandNamespace is immutable and cannot be changed"
+	OperandNamespace string `json:"operandNamespace,omitempty"`
+
+	// operandNamespaceManagement has the operator create and manage the operandNamespace, with the pod security
+	// labels, the node selector annotation and the monitoring label the operands require, instead of expecting
+	// a pre-created namespace. The namespace is deleted with the ZeroTrustWorkloadIdentityManager. The operator
+	// namespace is never managed.
+	// +kubebuilder:validation:Optional
+	OperandNamespaceManagement *OperandNamespaceManagementConfig `json:"operandNamespaceManagement,omitempty"`
+
+	// resyncInterval is how often the operand controllers re-reconcile their resources
+	// to repair drift, when the operand does not set its own resyncInterval.
+	// When not set, the interval configured with the operator --resync-interval flag is used.
+	// Must be at least 1m.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1m')",message="resyncInterval must be at least 1m"
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// networkPolicy configures the NetworkPolicies generated for the SPIRE server and the OIDC
+	// discovery provider, so that the operands run in clusters denying ingress traffic by default.
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
+	// tornjak deploys the Tornjak UI to browse and manage the registration entries and the agents of
+	// the SPIRE server. The Tornjak backend runs alongside the SPIRE server and the frontend is exposed
+	// with an OpenShift Route, behind an OAuth proxy authenticating the users with the cluster OAuth
+	// server. Only the users allowed to update the SpireServer get access. Tornjak is not deployed when unset.
+	// +kubebuilder:validation:Optional
+	Tornjak *TornjakConfig `json:"tornjak,omitempty"`
+
+	// profile tunes the operands for the size of the cluster. The SingleNode profile fits the operands on
+	// single-node OpenShift and edge clusters: the OIDC discovery provider runs a single replica unless
+	// autoscaled, the PodDisruptionBudgets are only managed when configured on the operand, the containers
+	// without resources request a minimal amount of CPU and memory, and the liveness probes run less often.
+	// The settings of the operands take precedence.
+	// +kubebuilder:default:=Default
+	// +kubebuilder:validation:Optional
+	Profile DeploymentProfile `json:"profile,omitempty"`
+
+	// topology places the operands when the control plane and the workers run in different clusters, as with
+	// HyperShift hosted control planes. The operator runs in both clusters: in the ManagementCluster mode it
+	// deploys the SPIRE server, which attests the agents and registers the workloads of the hosted cluster
+	// through its kubeconfig, and in the HostedCluster mode it deploys the SPIRE agents, the SPIFFE CSI
+	// driver and the OIDC discovery provider, connecting to the SPIRE server exposed by the management cluster.
+	// All the operands are deployed in the cluster when unset.
+	// +kubebuilder:validation:Optional
+	Topology *TopologyConfig `json:"topology,omitempty"`
+
+	// managementState controls whether the operator manages the operands of the cluster.
+	// Managed: The operands are reconciled to the desired state.
+	// Unmanaged: The operands are no longer reconciled and are left as they are.
+	// Removed: The resources of the operands are deleted, while the operand CRs are kept, and the
+	// operand namespace is deleted when created by the operator.
+	// A state other than Managed takes precedence over the managementState of the operands.
+	// +kubebuilder:default:=Managed
+	// +kubebuilder:validation:Optional
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
+	// featureGates enables or disables experimental operator capabilities on this cluster.
+	// An entry overrides the default of the gate and the operator --feature-gates flag.
+	// The effective state of every gate is reported in the FeatureGates condition.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	FeatureGates []FeatureGate `json:"featureGates,omitempty"`
+}
+
+// OperandNamespaceManagementConfig configures the operand namespace managed by the operator.
+type OperandNamespaceManagementConfig struct {
+	// enabled has the operator create the operandNamespace and keep its labels and annotations in place.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Enabled string `json:"enabled,omitempty"`
+
+	// nodeSelector is set as the openshift.io/node-selector annotation of the namespace, restricting the nodes
+	// the operand pods run on, e.g. "node-role.kubernetes.io/worker=". Defaults to an empty node selector, so
+	// that the SPIRE agents and the SPIFFE CSI driver run on every node regardless of the cluster default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=1024
+	NodeSelector string `json:"nodeSelector,omitempty"`
+
+	// labels are added to the namespace, e.g. for the namespace selectors of network or admission policies.
+	// The labels set by the operator take precedence.
+	// Maximum 64 labels allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=64
+	// +mapType=granular
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DeploymentProfile tunes the operands for the size of the cluster
+// +kubebuilder:validation:Enum=Default;SingleNode
+type DeploymentProfile string
+
+const (
+	// DeploymentProfileDefault sizes the operands for clusters of several nodes
+	DeploymentProfileDefault DeploymentProfile = "Default"
+
+	// DeploymentProfileSingleNode sizes the operands for single-node and resource-constrained clusters
+	DeploymentProfileSingleNode DeploymentProfile = "SingleNode"
+)
+
+// ManagementState is whether the operator manages a set of resources
+// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
+type ManagementState string
+
+const (
+	// ManagementStateManaged reconciles the resources to the desired state
+	ManagementStateManaged ManagementState = "Managed"
+
+	// ManagementStateUnmanaged leaves the resources as they are
+	ManagementStateUnmanaged ManagementState = "Unmanaged"
+
+	// ManagementStateRemoved deletes the resources
+	ManagementStateRemoved ManagementState = "Removed"
+)
+
+// TopologyMode is the placement of the operands across the management and the hosted clusters
+// +kubebuilder:validation:Enum=Standalone;ManagementCluster;HostedCluster
+type TopologyMode string
+
+const (
+	// TopologyStandalone deploys all the operands in the cluster
+	TopologyStandalone TopologyMode = "Standalone"
+
+	// TopologyManagementCluster deploys the SPIRE server, serving the agents of the hosted cluster
+	TopologyManagementCluster TopologyMode = "ManagementCluster"
+
+	// TopologyHostedCluster deploys the SPIRE agents, the SPIFFE CSI driver and the OIDC discovery provider,
+	// connecting to the SPIRE server of the management cluster
+	TopologyHostedCluster TopologyMode = "HostedCluster"
+)
+
+// TopologyConfig configures the placement of the operands across the management and the hosted clusters.
+// +kubebuilder:validation:XValidation:rule="self.mode != 'ManagementCluster' || has(self.hostedClusterKubeconfigSecretName)",message="hostedClusterKubeconfigSecretName is required in the ManagementCluster mode"
+// +kubebuilder:validation:XValidation:rule="self.mode != 'HostedCluster' || has(self.serverAddress)",message="serverAddress is required in the HostedCluster mode"
+type TopologyConfig struct {
+	// mode selects the operands deployed in this cluster.
+	// +kubebuilder:default:=Standalone
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="topology.mode is immutable and cannot be changed"
+	Mode TopologyMode `json:"mode,omitempty"`
+
+	// hostedClusterKubeconfigSecretName is the name of a Secret in the operand namespace holding, under the
+	// kubeconfig key, the kubeconfig of the hosted cluster, e.g. the service-network-admin-kubeconfig Secret of
+	// the hosted control plane namespace. The SPIRE server reviews the tokens of the agents, publishes the trust
+	// bundle and registers the workloads through it, so it must grant the permissions of the SPIRE server and of
+	// the SPIRE controller manager in the hosted cluster. Required in the ManagementCluster mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	HostedClusterKubeconfigSecretName string `json:"hostedClusterKubeconfigSecretName,omitempty"`
+
+	// serverAddress is the host:port the SPIRE agents reach the SPIRE server of the management cluster on,
+	// e.g. the status.advertisedAddress of the SpireServer exposed with spec.exposure. Required in the
+	// HostedCluster mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=512
+	ServerAddress string `json:"serverAddress,omitempty"`
+}
+
+// FeatureGateName is the name of an experimental operator capability
+// +kubebuilder:validation:Enum=Federation;NestedTopology;SpiffeHelperInjection
+type FeatureGateName string
+
+const (
+	// FeatureGateFederation gates the federation of the SPIRE server with other trust domains.
+	// Enabled by default.
+	FeatureGateFederation FeatureGateName = "Federation"
+
+	// FeatureGateNestedTopology gates the upstream authority of the SPIRE server, used to run it
+	// as a downstream server of a nested SPIRE topology. Disabled by default.
+	FeatureGateNestedTopology FeatureGateName = "NestedTopology"
+
+	// FeatureGateSpiffeHelperInjection gates the injection of the spiffe-helper sidecar into pods.
+	// Disabled by default.
+	FeatureGateSpiffeHelperInjection FeatureGateName = "SpiffeHelperInjection"
+)
+
+// FeatureGate enables or disables an experimental operator capability
+type FeatureGate struct {
+	// name of the feature gate.
+	// Must be one of: Federation, NestedTopology, SpiffeHelperInjection.
+	// +kubebuilder:validation:Required
+	// +required
+	Name FeatureGateName `json:"name"`
+
+	// enabled turns the capability on when true and off when false.
+	// +kubebuilder:validation:Required
+	// +required
+	Enabled bool `json:"enabled"`
+}
+
+// CommonConfig has similar config required for all other APIs
+type CommonConfig struct {
+
+	// labels to apply to all resources managed by the API.
+	// Maximum 64 labels allowed. Label keys and values must be valid Kubernetes labels.
+	// +mapType=granular
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=64
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// resources define the resource requirements.
+	// ref: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// affinity defines scheduling affinity rules.
+	// The rules are validated by the operator instead of the CRD schema, which would exceed the size
+	// limits of the API server with the schema of every affinity term.
+	// ref: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// tolerations define the pod tolerations.
+	// Maximum 50 tolerations allowed.
+	// ref: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=50
+	// +listType=atomic
+	Tolerations []*corev1.Toleration `json:"tolerations,omitempty"`
+
+	// nodeSelector defines the scheduling criteria using node labels.
+	// Maximum 50 node selectors allowed.
+	// ref: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=50
+	// +mapType=atomic
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// nodePlatform selects the nodes running the operand pods by operating system and architecture.
+	// When architectures are not set, the pods are restricted to the architectures of the operand images
+	// declared on the operator, so that they never land on nodes the images can't run on.
+	// When not set, the pods run on all the Linux nodes of the supported architectures.
+	// +kubebuilder:validation:Optional
+	NodePlatform *NodePlatformConfig `json:"nodePlatform,omitempty"`
+
+	// priorityClassName is the name of the PriorityClass assigned to the operand pods.
+	// When not set, the SPIRE agent and SPIFFE CSI driver default to system-node-critical
+	// so that node-level identity infrastructure is not evicted before application workloads.
+	// Must be a valid Kubernetes name.
+	// ref: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// imagePullSecrets are the Secrets used to pull the images of the operand pods, e.g. the credentials
+	// of a mirror registry that are not linked to the ServiceAccounts of the operands.
+	// The Secrets must exist in the operand namespace.
+	// Maximum 10 secrets allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=atomic
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// serviceAccountAnnotations are set on the ServiceAccount of the operand, e.g. to bind it to a cloud
+	// IAM role with eks.amazonaws.com/role-arn or iam.gke.io/gcp-service-account, for the KMS and upstream
+	// authority plugins of the SPIRE server. Removing an annotation doesn't update the ServiceAccount by itself.
+	// Maximum 64 annotations allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxProperties=64
+	// +mapType=granular
+	ServiceAccountAnnotations map[string]string `json:"serviceAccountAnnotations,omitempty"`
+
+	// extraVolumes are added to the operand pods, e.g. to provide site specific CA bundles, the binaries
+	// of custom SPIRE plugins or the host paths they require.
+	// The names must not be used by the volumes of the operator.
+	// Maximum 20 volumes allowed.
+	// The volumes are validated by the operator instead of the CRD schema, which would exceed the size
+	// limits of the API server with the schema of every volume source.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// extraVolumeMounts mount extraVolumes into the main container of the operand pods: spire-server,
+	// spire-agent, spiffe-csi-driver or spiffe-oidc-discovery-provider.
+	// The mount paths must not be used by the mounts of the operator.
+	// Maximum 20 volume mounts allowed.
+	// The volume mounts are validated by the operator instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
+	// env sets environment variables in the main container of the operand pods, e.g. the cloud
+	// credentials of the KMS and upstream authority plugins, proxy exceptions or debug toggles.
+	// A variable overrides the variable of the same name set by the operator.
+	// Maximum 50 variables allowed.
+	// The variables are validated by the operator instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// envFrom sets environment variables in the main container of the operand pods from the keys of
+	// Secrets and ConfigMaps. The variables of env take precedence over them.
+	// Maximum 10 sources allowed.
+	// The sources are validated by the operator instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=array
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// securityContext overrides the security context of the main container of the operand pods, e.g. to
+	// set the seccomp profile or the user required by a Pod Security Admission profile. The fields set
+	// replace the fields set by the operator. The spire-agent and spiffe-csi-driver containers must stay
+	// privileged and run as root, as they access host paths.
+	// The security context is validated by the operator instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// podSecurityContext sets the security context of the operand pods.
+	// The security context is validated by the operator instead of the CRD schema.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// reconcileMode controls whether the controller applies the resources it generates.
+	// Apply: resources are created and updated to match the desired state.
+	// DryRun: resources are computed and compared against the cluster, but nothing is
+	// created, updated or deleted. The changes that would be made are published in
+	// status.plannedChanges and as events on the resource.
+	// +kubebuilder:default:="Apply"
+	// +kubebuilder:validation:Enum:=Apply;DryRun
+	// +kubebuilder:validation:Optional
+	ReconcileMode string `json:"reconcileMode,omitempty"`
+
+	// paused stops the controller from reconciling the resources managed for this API.
+	// "true": Managed resources are left as they are, so manual changes are not reverted.
+	// The Paused condition is set to True and the last reported Ready condition is kept.
+	// "false": The controller reconciles the managed resources back to the desired state.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Paused string `json:"paused,omitempty"`
+
+	// managementState controls whether the operator manages the resources of this API.
+	// Managed: The resources are reconciled to the desired state.
+	// Unmanaged: The resources are no longer reconciled and are left as they are.
+	// Removed: The resources are deleted while this CR is kept, so that setting it back to
+	// Managed deploys them again.
+	// It is overridden by the managementState of the ZeroTrustWorkloadIdentityManager when not Managed.
+	// The effective state is reported in the ManagementState condition.
+	// +kubebuilder:default:=Managed
+	// +kubebuilder:validation:Optional
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
+	// resyncInterval is how often the controller re-reconciles the resources managed for
+	// this API to repair drift. It overrides the resyncInterval set on the
+	// ZeroTrustWorkloadIdentityManager.
+	// Must be at least 1m.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1m')",message="resyncInterval must be at least 1m"
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// excludedResources lists the generated resources the operator leaves to another owner, e.g. the
+	// ValidatingWebhookConfiguration or the RBAC objects managed with GitOps. The writes skipped for
+	// them are reported in the ExcludedResources condition.
+	// Maximum 32 exclusions allowed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=atomic
+	ExcludedResources []ResourceExclusion `json:"excludedResources,omitempty"`
+
+	// adoptExistingResources has the operator adopt the resources of the same name it did not create,
+	// e.g. when migrating from the SPIRE Helm charts, instead of failing to create them.
+	// "true": The desired state is applied to the existing resources, which are labeled as managed by
+	// the operator. A workload whose selector can't be changed is recreated, and the PersistentVolumeClaims
+	// of the SPIRE server are kept, so that its datastore and the trust bundle are preserved.
+	// "false": Only the resources created by the operator are managed.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	AdoptExistingResources string `json:"adoptExistingResources,omitempty"`
+}
+
+// ResourceExclusion excludes generated resources from management by the operator.
+type ResourceExclusion struct {
+	// kind of the excluded resources, e.g. ValidatingWebhookConfiguration or ClusterRole.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Kind string `json:"kind"`
+
+	// name of the excluded resource. All the generated resources of the kind are excluded when unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name,omitempty"`
+
+	// policy sets how much of the resource is left to its owner.
+	// CreateOnly: The resource is created when missing, and then never updated or deleted.
+	// Ignore: The resource is never created, updated or deleted.
+	// +kubebuilder:default:=Ignore
+	// +kubebuilder:validation:Optional
+	Policy ResourceExclusionPolicy `json:"policy,omitempty"`
+}
+
+// ResourceExclusionPolicy is how much of an excluded resource is left to its owner
+// +kubebuilder:validation:Enum=CreateOnly;Ignore
+type ResourceExclusionPolicy string
+
+const (
+	// ResourceExclusionCreateOnly creates the missing resource and then leaves it to its owner
+	ResourceExclusionCreateOnly ResourceExclusionPolicy = "CreateOnly"
+
+	// ResourceExclusionIgnore leaves the resource entirely to its owner
+	ResourceExclusionIgnore ResourceExclusionPolicy = "Ignore"
+)
+
+const (
+	// ReconcileModeApply applies the generated resources to the cluster.
+	ReconcileModeApply = "Apply"
+	// ReconcileModeDryRun only reports the changes the controller would make.
+	ReconcileModeDryRun = "DryRun"
+)
+
+// PodDisruptionBudgetConfig configures the PodDisruptionBudget managed for an operand.
+// When neither minAvailable nor maxUnavailable is set, minAvailable defaults to 1 for
+// operands running more than one replica and maxUnavailable defaults to 1 otherwise,
+// so that single replica operands never block node drains.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetConfig struct {
+	// enabled controls whether the operator manages a PodDisruptionBudget for the operand.
+	// "true": The operator creates and maintains the PodDisruptionBudget.
+	// "false": The operator removes any PodDisruptionBudget it previously created.
+	// +kubebuilder:default:="true"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Enabled string `json:"enabled,omitempty"`
+
+	// minAvailable is the number or percentage of pods that must remain available during a voluntary disruption.
+	// Mutually exclusive with maxUnavailable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// maxUnavailable is the number or percentage of pods that can be unavailable during a voluntary disruption.
+	// Mutually exclusive with minAvailable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// NetworkPolicyConfig configures the NetworkPolicies managed for the operands.
+// The policies only restrict ingress traffic, and their ports follow the Services generated by the operator:
+// the SPIRE server API only accepts the SPIRE agents, the OIDC discovery provider and host-network traffic,
+// the webhook and federation endpoints accept any client, and the metrics are only scraped by monitoring.
+// The OIDC discovery provider accepts any client on its serving port.
+type NetworkPolicyConfig struct {
+	// enabled controls whether the operator manages NetworkPolicies for the operands.
+	// "true": The operator creates and maintains the NetworkPolicies.
+	// "false": The operator removes any NetworkPolicy it previously created.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Enabled string `json:"enabled,omitempty"`
+}
+
+// TornjakConfig configures the Tornjak UI of the SPIRE server
+type TornjakConfig struct {
+	// enabled controls whether the operator deploys Tornjak.
+	// "true": The operator creates and maintains the Tornjak backend, frontend and Route.
+	// "false": The operator removes the Tornjak resources it previously created.
+	// +kubebuilder:default:="false"
+	// +kubebuilder:validation:Enum:="true";"false"
+	// +kubebuilder:validation:Optional
+	Enabled string `json:"enabled,omitempty"`
+
+	// routeHost is the host of the Route exposing the Tornjak UI.
+	// When not set, the host is generated by the OpenShift router.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	RouteHost string `json:"routeHost,omitempty"`
+
+	// resources define the resource requirements of the Tornjak backend and frontend containers.
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// NodePlatformConfig selects the nodes of an operand by operating system and architecture.
+// The operand pods get a kubernetes.io/os node selector for the operating system, and a node affinity
+// on kubernetes.io/arch for the architectures.
+type NodePlatformConfig struct {
+	// os is the operating system of the nodes running the operand pods. The operand images are only
+	// built for Linux, so that Windows nodes of mixed clusters are never selected.
+	// +kubebuilder:default:="linux"
+	// +kubebuilder:validation:Enum:="linux"
+	// +kubebuilder:validation:Optional
+	OS string `json:"os,omitempty"`
+
+	// architectures restricts the operand pods to nodes of the listed CPU architectures, e.g. when the
+	// operand images are mirrored for a subset of them. They must be supported by the operand images.
+	// When not set, the architectures of the operand images declared on the operator are selected.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=4
+	// +listType=set
+	Architectures []NodeArchitecture `json:"architectures,omitempty"`
+}
+
+// NodeArchitecture is a CPU architecture of the nodes, as in the kubernetes.io/arch node label.
+// +kubebuilder:validation:Enum:="amd64";"arm64";"ppc64le";"s390x"
+type NodeArchitecture string
+
+func init() {
+	SchemeBuilder.Register(&ZeroTrustWorkloadIdentityManager{}, &ZeroTrustWorkloadIdentityManagerList{})
+}
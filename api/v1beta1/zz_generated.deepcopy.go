@@ -0,0 +1,1986 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSS3BundlePublisherConfig) DeepCopyInto(out *AWSS3BundlePublisherConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSS3BundlePublisherConfig.
+func (in *AWSS3BundlePublisherConfig) DeepCopy() *AWSS3BundlePublisherConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSS3BundlePublisherConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AcmeConfig) DeepCopyInto(out *AcmeConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AcmeConfig.
+func (in *AcmeConfig) DeepCopy() *AcmeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AcmeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentAdminAPIConfig) DeepCopyInto(out *AgentAdminAPIConfig) {
+	*out = *in
+	if in.AuthorizedDelegates != nil {
+		in, out := &in.AuthorizedDelegates, &out.AuthorizedDelegates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentAdminAPIConfig.
+func (in *AgentAdminAPIConfig) DeepCopy() *AgentAdminAPIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentAdminAPIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRolloutConfig) DeepCopyInto(out *AgentRolloutConfig) {
+	*out = *in
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SoakPeriod != nil {
+		in, out := &in.SoakPeriod, &out.SoakPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRolloutConfig.
+func (in *AgentRolloutConfig) DeepCopy() *AgentRolloutConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRolloutConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogConfig) DeepCopyInto(out *AuditLogConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogConfig.
+func (in *AuditLogConfig) DeepCopy() *AuditLogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingConfig) DeepCopyInto(out *AutoscalingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingConfig.
+func (in *AutoscalingConfig) DeepCopy() *AutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleDistributionConfig) DeepCopyInto(out *BundleDistributionConfig) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleDistributionConfig.
+func (in *BundleDistributionConfig) DeepCopy() *BundleDistributionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleDistributionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleEndpointConfig) DeepCopyInto(out *BundleEndpointConfig) {
+	*out = *in
+	if in.HttpsWeb != nil {
+		in, out := &in.HttpsWeb, &out.HttpsWeb
+		*out = new(HttpsWebConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleEndpointConfig.
+func (in *BundleEndpointConfig) DeepCopy() *BundleEndpointConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleEndpointConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundlePublishersConfig) DeepCopyInto(out *BundlePublishersConfig) {
+	*out = *in
+	if in.AWSS3 != nil {
+		in, out := &in.AWSS3, &out.AWSS3
+		*out = new(AWSS3BundlePublisherConfig)
+		**out = **in
+	}
+	if in.GCPCloudStorage != nil {
+		in, out := &in.GCPCloudStorage, &out.GCPCloudStorage
+		*out = new(GCPCloudStorageBundlePublisherConfig)
+		**out = **in
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]ConfigMapBundlePublisherConfig, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundlePublishersConfig.
+func (in *BundlePublishersConfig) DeepCopy() *BundlePublishersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BundlePublishersConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationConfig) DeepCopyInto(out *CARotationConfig) {
+	*out = *in
+	out.Interval = in.Interval
+	out.PropagationDelay = in.PropagationDelay
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationConfig.
+func (in *CARotationConfig) DeepCopy() *CARotationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARotationStatus) DeepCopyInto(out *CARotationStatus) {
+	*out = *in
+	if in.PhaseTransitionTime != nil {
+		in, out := &in.PhaseTransitionTime, &out.PhaseTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCompletionTime != nil {
+		in, out := &in.LastCompletionTime, &out.LastCompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARotationStatus.
+func (in *CARotationStatus) DeepCopy() *CARotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CASubject) DeepCopyInto(out *CASubject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CASubject.
+func (in *CASubject) DeepCopy() *CASubject {
+	if in == nil {
+		return nil
+	}
+	out := new(CASubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateAuthorityStatus) DeepCopyInto(out *CertificateAuthorityStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(X509AuthorityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prepared != nil {
+		in, out := &in.Prepared, &out.Prepared
+		*out = new(X509AuthorityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateAuthorityStatus.
+func (in *CertificateAuthorityStatus) DeepCopy() *CertificateAuthorityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateAuthorityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonConfig) DeepCopyInto(out *CommonConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]*corev1.Toleration, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(corev1.Toleration)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePlatform != nil {
+		in, out := &in.NodePlatform, &out.NodePlatform
+		*out = new(NodePlatformConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ExcludedResources != nil {
+		in, out := &in.ExcludedResources, &out.ExcludedResources
+		*out = make([]ResourceExclusion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonConfig.
+func (in *CommonConfig) DeepCopy() *CommonConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionalStatus) DeepCopyInto(out *ConditionalStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]PlannedChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedResources != nil {
+		in, out := &in.ManagedResources, &out.ManagedResources
+		*out = make([]ManagedResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionalStatus.
+func (in *ConditionalStatus) DeepCopy() *ConditionalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapBundlePublisherConfig) DeepCopyInto(out *ConfigMapBundlePublisherConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapBundlePublisherConfig.
+func (in *ConfigMapBundlePublisherConfig) DeepCopy() *ConfigMapBundlePublisherConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapBundlePublisherConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerManagerConfig) DeepCopyInto(out *ControllerManagerConfig) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNSNameTemplates != nil {
+		in, out := &in.DNSNameTemplates, &out.DNSNameTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerManagerConfig.
+func (in *ControllerManagerConfig) DeepCopy() *ControllerManagerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerManagerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialComposersConfig) DeepCopyInto(out *CredentialComposersConfig) {
+	*out = *in
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = make([]ExternalCredentialComposerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialComposersConfig.
+func (in *CredentialComposersConfig) DeepCopy() *CredentialComposersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialComposersConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetUpdateStrategy) DeepCopyInto(out *DaemonSetUpdateStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetUpdateStrategy.
+func (in *DaemonSetUpdateStrategy) DeepCopy() *DaemonSetUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataStore) DeepCopyInto(out *DataStore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataStore.
+func (in *DataStore) DeepCopy() *DataStore {
+	if in == nil {
+		return nil
+	}
+	out := new(DataStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatastoreBackupConfig) DeepCopyInto(out *DatastoreBackupConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatastoreBackupConfig.
+func (in *DatastoreBackupConfig) DeepCopy() *DatastoreBackupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DatastoreBackupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EntryPruningConfig) DeepCopyInto(out *EntryPruningConfig) {
+	*out = *in
+	out.Interval = in.Interval
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EntryPruningConfig.
+func (in *EntryPruningConfig) DeepCopy() *EntryPruningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EntryPruningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalCredentialComposerConfig) DeepCopyInto(out *ExternalCredentialComposerConfig) {
+	*out = *in
+	if in.PluginData != nil {
+		in, out := &in.PluginData, &out.PluginData
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCredentialComposerConfig.
+func (in *ExternalCredentialComposerConfig) DeepCopy() *ExternalCredentialComposerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalCredentialComposerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGate) DeepCopyInto(out *FeatureGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGate.
+func (in *FeatureGate) DeepCopy() *FeatureGate {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTrustDomainStatus) DeepCopyInto(out *FederatedTrustDomainStatus) {
+	*out = *in
+	if in.LastSuccessfulRefreshTime != nil {
+		in, out := &in.LastSuccessfulRefreshTime, &out.LastSuccessfulRefreshTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTrustDomainStatus.
+func (in *FederatedTrustDomainStatus) DeepCopy() *FederatedTrustDomainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTrustDomainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatesWithConfig) DeepCopyInto(out *FederatesWithConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatesWithConfig.
+func (in *FederatesWithConfig) DeepCopy() *FederatesWithConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatesWithConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederationConfig) DeepCopyInto(out *FederationConfig) {
+	*out = *in
+	in.BundleEndpoint.DeepCopyInto(&out.BundleEndpoint)
+	if in.FederatesWith != nil {
+		in, out := &in.FederatesWith, &out.FederatesWith
+		*out = make([]FederatesWithConfig, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederationConfig.
+func (in *FederationConfig) DeepCopy() *FederationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FederationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPCloudStorageBundlePublisherConfig) DeepCopyInto(out *GCPCloudStorageBundlePublisherConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPCloudStorageBundlePublisherConfig.
+func (in *GCPCloudStorageBundlePublisherConfig) DeepCopy() *GCPCloudStorageBundlePublisherConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPCloudStorageBundlePublisherConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpsWebConfig) DeepCopyInto(out *HttpsWebConfig) {
+	*out = *in
+	if in.Acme != nil {
+		in, out := &in.Acme, &out.Acme
+		*out = new(AcmeConfig)
+		**out = **in
+	}
+	if in.ServingCert != nil {
+		in, out := &in.ServingCert, &out.ServingCert
+		*out = new(ServingCertConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpsWebConfig.
+func (in *HttpsWebConfig) DeepCopy() *HttpsWebConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpsWebConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyManager) DeepCopyInto(out *KeyManager) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyManager.
+func (in *KeyManager) DeepCopy() *KeyManager {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerExposureConfig) DeepCopyInto(out *LoadBalancerExposureConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SourceRanges != nil {
+		in, out := &in.SourceRanges, &out.SourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerExposureConfig.
+func (in *LoadBalancerExposureConfig) DeepCopy() *LoadBalancerExposureConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerExposureConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedResource) DeepCopyInto(out *ManagedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedResource.
+func (in *ManagedResource) DeepCopy() *ManagedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAttestor) DeepCopyInto(out *NodeAttestor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAttestor.
+func (in *NodeAttestor) DeepCopy() *NodeAttestor {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAttestor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDriverRegistrarConfig) DeepCopyInto(out *NodeDriverRegistrarConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDriverRegistrarConfig.
+func (in *NodeDriverRegistrarConfig) DeepCopy() *NodeDriverRegistrarConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDriverRegistrarConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePlatformConfig) DeepCopyInto(out *NodePlatformConfig) {
+	*out = *in
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]NodeArchitecture, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePlatformConfig.
+func (in *NodePlatformConfig) DeepCopy() *NodePlatformConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePlatformConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCIngressConfig) DeepCopyInto(out *OIDCIngressConfig) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCIngressConfig.
+func (in *OIDCIngressConfig) DeepCopy() *OIDCIngressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCIngressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandNamespaceManagementConfig) DeepCopyInto(out *OperandNamespaceManagementConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandNamespaceManagementConfig.
+func (in *OperandNamespaceManagementConfig) DeepCopy() *OperandNamespaceManagementConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandNamespaceManagementConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandStatus) DeepCopyInto(out *OperandStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandStatus.
+func (in *OperandStatus) DeepCopy() *OperandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Persistence) DeepCopyInto(out *Persistence) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Persistence.
+func (in *Persistence) DeepCopy() *Persistence {
+	if in == nil {
+		return nil
+	}
+	out := new(Persistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedChange) DeepCopyInto(out *PlannedChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedChange.
+func (in *PlannedChange) DeepCopy() *PlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfig.
+func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTimings) DeepCopyInto(out *ProbeTimings) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTimings.
+func (in *ProbeTimings) DeepCopy() *ProbeTimings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeTimings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitConfig) DeepCopyInto(out *RateLimitConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitConfig.
+func (in *RateLimitConfig) DeepCopy() *RateLimitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceExclusion) DeepCopyInto(out *ResourceExclusion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceExclusion.
+func (in *ResourceExclusion) DeepCopy() *ResourceExclusion {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceExclusion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SDSConfig) DeepCopyInto(out *SDSConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SDSConfig.
+func (in *SDSConfig) DeepCopy() *SDSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SDSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerExposureConfig) DeepCopyInto(out *ServerExposureConfig) {
+	*out = *in
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(LoadBalancerExposureConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerExposureConfig.
+func (in *ServerExposureConfig) DeepCopy() *ServerExposureConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerExposureConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerNodeAttestor) DeepCopyInto(out *ServerNodeAttestor) {
+	*out = *in
+	if in.K8sPSATAudience != nil {
+		in, out := &in.K8sPSATAudience, &out.K8sPSATAudience
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.K8sPSATServiceAccountAllowList != nil {
+		in, out := &in.K8sPSATServiceAccountAllowList, &out.K8sPSATServiceAccountAllowList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerNodeAttestor.
+func (in *ServerNodeAttestor) DeepCopy() *ServerNodeAttestor {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerNodeAttestor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServingCertConfig) DeepCopyInto(out *ServingCertConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServingCertConfig.
+func (in *ServingCertConfig) DeepCopy() *ServingCertConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServingCertConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiffeCSIDriver) DeepCopyInto(out *SpiffeCSIDriver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiffeCSIDriver.
+func (in *SpiffeCSIDriver) DeepCopy() *SpiffeCSIDriver {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiffeCSIDriver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpiffeCSIDriver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiffeCSIDriverList) DeepCopyInto(out *SpiffeCSIDriverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpiffeCSIDriver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiffeCSIDriverList.
+func (in *SpiffeCSIDriverList) DeepCopy() *SpiffeCSIDriverList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiffeCSIDriverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpiffeCSIDriverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiffeCSIDriverSpec) DeepCopyInto(out *SpiffeCSIDriverSpec) {
+	*out = *in
+	if in.NodeDriverRegistrar != nil {
+		in, out := &in.NodeDriverRegistrar, &out.NodeDriverRegistrar
+		*out = new(NodeDriverRegistrarConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiffeCSIDriverSpec.
+func (in *SpiffeCSIDriverSpec) DeepCopy() *SpiffeCSIDriverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiffeCSIDriverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpiffeCSIDriverStatus) DeepCopyInto(out *SpiffeCSIDriverStatus) {
+	*out = *in
+	in.ConditionalStatus.DeepCopyInto(&out.ConditionalStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpiffeCSIDriverStatus.
+func (in *SpiffeCSIDriverStatus) DeepCopy() *SpiffeCSIDriverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpiffeCSIDriverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireAgent) DeepCopyInto(out *SpireAgent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireAgent.
+func (in *SpireAgent) DeepCopy() *SpireAgent {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireAgent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpireAgent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireAgentList) DeepCopyInto(out *SpireAgentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpireAgent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireAgentList.
+func (in *SpireAgentList) DeepCopy() *SpireAgentList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireAgentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpireAgentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireAgentSpec) DeepCopyInto(out *SpireAgentSpec) {
+	*out = *in
+	if in.NodeAttestor != nil {
+		in, out := &in.NodeAttestor, &out.NodeAttestor
+		*out = new(NodeAttestor)
+		**out = **in
+	}
+	if in.WorkloadAttestors != nil {
+		in, out := &in.WorkloadAttestors, &out.WorkloadAttestors
+		*out = new(WorkloadAttestors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SDS != nil {
+		in, out := &in.SDS, &out.SDS
+		*out = new(SDSConfig)
+		**out = **in
+	}
+	if in.AdminAPI != nil {
+		in, out := &in.AdminAPI, &out.AdminAPI
+		*out = new(AgentAdminAPIConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AvailabilityTarget != nil {
+		in, out := &in.AvailabilityTarget, &out.AvailabilityTarget
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(DaemonSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AgentRolloutConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraConfig != nil {
+		in, out := &in.ExtraConfig, &out.ExtraConfig
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Experimental != nil {
+		in, out := &in.Experimental, &out.Experimental
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraContainers != nil {
+		in, out := &in.ExtraContainers, &out.ExtraContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraInitContainers != nil {
+		in, out := &in.ExtraInitContainers, &out.ExtraInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireAgentSpec.
+func (in *SpireAgentSpec) DeepCopy() *SpireAgentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireAgentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireAgentStatus) DeepCopyInto(out *SpireAgentStatus) {
+	*out = *in
+	in.ConditionalStatus.DeepCopyInto(&out.ConditionalStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireAgentStatus.
+func (in *SpireAgentStatus) DeepCopy() *SpireAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireOIDCDiscoveryProvider) DeepCopyInto(out *SpireOIDCDiscoveryProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireOIDCDiscoveryProvider.
+func (in *SpireOIDCDiscoveryProvider) DeepCopy() *SpireOIDCDiscoveryProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireOIDCDiscoveryProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpireOIDCDiscoveryProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireOIDCDiscoveryProviderList) DeepCopyInto(out *SpireOIDCDiscoveryProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpireOIDCDiscoveryProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireOIDCDiscoveryProviderList.
+func (in *SpireOIDCDiscoveryProviderList) DeepCopy() *SpireOIDCDiscoveryProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireOIDCDiscoveryProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpireOIDCDiscoveryProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireOIDCDiscoveryProviderSpec) DeepCopyInto(out *SpireOIDCDiscoveryProviderSpec) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(OIDCIngressConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingConfig)
+		**out = **in
+	}
+	if in.ExtraContainers != nil {
+		in, out := &in.ExtraContainers, &out.ExtraContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraInitContainers != nil {
+		in, out := &in.ExtraInitContainers, &out.ExtraInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireOIDCDiscoveryProviderSpec.
+func (in *SpireOIDCDiscoveryProviderSpec) DeepCopy() *SpireOIDCDiscoveryProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireOIDCDiscoveryProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireOIDCDiscoveryProviderStatus) DeepCopyInto(out *SpireOIDCDiscoveryProviderStatus) {
+	*out = *in
+	in.ConditionalStatus.DeepCopyInto(&out.ConditionalStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireOIDCDiscoveryProviderStatus.
+func (in *SpireOIDCDiscoveryProviderStatus) DeepCopy() *SpireOIDCDiscoveryProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireOIDCDiscoveryProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireServer) DeepCopyInto(out *SpireServer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireServer.
+func (in *SpireServer) DeepCopy() *SpireServer {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpireServer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireServerList) DeepCopyInto(out *SpireServerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SpireServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireServerList.
+func (in *SpireServerList) DeepCopy() *SpireServerList {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireServerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SpireServerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireServerSpec) DeepCopyInto(out *SpireServerSpec) {
+	*out = *in
+	out.CAValidity = in.CAValidity
+	out.DefaultX509Validity = in.DefaultX509Validity
+	out.DefaultJWTValidity = in.DefaultJWTValidity
+	out.AgentValidity = in.AgentValidity
+	if in.KeyManager != nil {
+		in, out := &in.KeyManager, &out.KeyManager
+		*out = new(KeyManager)
+		**out = **in
+	}
+	out.CASubject = in.CASubject
+	out.Persistence = in.Persistence
+	out.Datastore = in.Datastore
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(FederationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraConfig != nil {
+		in, out := &in.ExtraConfig, &out.ExtraConfig
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Experimental != nil {
+		in, out := &in.Experimental, &out.Experimental
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BundleDistribution != nil {
+		in, out := &in.BundleDistribution, &out.BundleDistribution
+		*out = new(BundleDistributionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BundlePublishers != nil {
+		in, out := &in.BundlePublishers, &out.BundlePublishers
+		*out = new(BundlePublishersConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialComposers != nil {
+		in, out := &in.CredentialComposers, &out.CredentialComposers
+		*out = new(CredentialComposersConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpstreamAuthority != nil {
+		in, out := &in.UpstreamAuthority, &out.UpstreamAuthority
+		*out = new(UpstreamAuthorityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(DatastoreBackupConfig)
+		**out = **in
+	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(ServerExposureConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLog != nil {
+		in, out := &in.AuditLog, &out.AuditLog
+		*out = new(AuditLogConfig)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitConfig)
+		**out = **in
+	}
+	if in.NodeAttestor != nil {
+		in, out := &in.NodeAttestor, &out.NodeAttestor
+		*out = new(ServerNodeAttestor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerManager != nil {
+		in, out := &in.ControllerManager, &out.ControllerManager
+		*out = new(ControllerManagerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationConfig)
+		**out = **in
+	}
+	if in.EntryPruning != nil {
+		in, out := &in.EntryPruning, &out.EntryPruning
+		*out = new(EntryPruningConfig)
+		**out = **in
+	}
+	if in.ExtraContainers != nil {
+		in, out := &in.ExtraContainers, &out.ExtraContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraInitContainers != nil {
+		in, out := &in.ExtraInitContainers, &out.ExtraInitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireServerSpec.
+func (in *SpireServerSpec) DeepCopy() *SpireServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireServerStatus) DeepCopyInto(out *SpireServerStatus) {
+	*out = *in
+	in.ConditionalStatus.DeepCopyInto(&out.ConditionalStatus)
+	if in.FederatedTrustDomains != nil {
+		in, out := &in.FederatedTrustDomains, &out.FederatedTrustDomains
+		*out = make([]FederatedTrustDomainStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = new(CertificateAuthorityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CARotation != nil {
+		in, out := &in.CARotation, &out.CARotation
+		*out = new(CARotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireServerStatus.
+func (in *SpireServerStatus) DeepCopy() *SpireServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpireUpstreamAuthorityConfig) DeepCopyInto(out *SpireUpstreamAuthorityConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpireUpstreamAuthorityConfig.
+func (in *SpireUpstreamAuthorityConfig) DeepCopy() *SpireUpstreamAuthorityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SpireUpstreamAuthorityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyConfig) DeepCopyInto(out *TopologyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyConfig.
+func (in *TopologyConfig) DeepCopy() *TopologyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TornjakConfig) DeepCopyInto(out *TornjakConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TornjakConfig.
+func (in *TornjakConfig) DeepCopy() *TornjakConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TornjakConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamAuthorityConfig) DeepCopyInto(out *UpstreamAuthorityConfig) {
+	*out = *in
+	if in.Spire != nil {
+		in, out := &in.Spire, &out.Spire
+		*out = new(SpireUpstreamAuthorityConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamAuthorityConfig.
+func (in *UpstreamAuthorityConfig) DeepCopy() *UpstreamAuthorityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamAuthorityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadAttestors) DeepCopyInto(out *WorkloadAttestors) {
+	*out = *in
+	if in.WorkloadAttestorsVerification != nil {
+		in, out := &in.WorkloadAttestorsVerification, &out.WorkloadAttestorsVerification
+		*out = new(WorkloadAttestorsVerification)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadAttestors.
+func (in *WorkloadAttestors) DeepCopy() *WorkloadAttestors {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadAttestors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadAttestorsVerification) DeepCopyInto(out *WorkloadAttestorsVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadAttestorsVerification.
+func (in *WorkloadAttestorsVerification) DeepCopy() *WorkloadAttestorsVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadAttestorsVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *X509AuthorityStatus) DeepCopyInto(out *X509AuthorityStatus) {
+	*out = *in
+	in.NotBefore.DeepCopyInto(&out.NotBefore)
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new X509AuthorityStatus.
+func (in *X509AuthorityStatus) DeepCopy() *X509AuthorityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(X509AuthorityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZeroTrustWorkloadIdentityManager) DeepCopyInto(out *ZeroTrustWorkloadIdentityManager) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZeroTrustWorkloadIdentityManager.
+func (in *ZeroTrustWorkloadIdentityManager) DeepCopy() *ZeroTrustWorkloadIdentityManager {
+	if in == nil {
+		return nil
+	}
+	out := new(ZeroTrustWorkloadIdentityManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ZeroTrustWorkloadIdentityManager) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZeroTrustWorkloadIdentityManagerList) DeepCopyInto(out *ZeroTrustWorkloadIdentityManagerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ZeroTrustWorkloadIdentityManager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZeroTrustWorkloadIdentityManagerList.
+func (in *ZeroTrustWorkloadIdentityManagerList) DeepCopy() *ZeroTrustWorkloadIdentityManagerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ZeroTrustWorkloadIdentityManagerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ZeroTrustWorkloadIdentityManagerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZeroTrustWorkloadIdentityManagerSpec) DeepCopyInto(out *ZeroTrustWorkloadIdentityManagerSpec) {
+	*out = *in
+	if in.OperandNamespaceManagement != nil {
+		in, out := &in.OperandNamespaceManagement, &out.OperandNamespaceManagement
+		*out = new(OperandNamespaceManagementConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		**out = **in
+	}
+	if in.Tornjak != nil {
+		in, out := &in.Tornjak, &out.Tornjak
+		*out = new(TornjakConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = new(TopologyConfig)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]FeatureGate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZeroTrustWorkloadIdentityManagerSpec.
+func (in *ZeroTrustWorkloadIdentityManagerSpec) DeepCopy() *ZeroTrustWorkloadIdentityManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZeroTrustWorkloadIdentityManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZeroTrustWorkloadIdentityManagerStatus) DeepCopyInto(out *ZeroTrustWorkloadIdentityManagerStatus) {
+	*out = *in
+	in.ConditionalStatus.DeepCopyInto(&out.ConditionalStatus)
+	if in.Operands != nil {
+		in, out := &in.Operands, &out.Operands
+		*out = make([]OperandStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZeroTrustWorkloadIdentityManagerStatus.
+func (in *ZeroTrustWorkloadIdentityManagerStatus) DeepCopy() *ZeroTrustWorkloadIdentityManagerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ZeroTrustWorkloadIdentityManagerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-ztwim is a kubectl plugin inspecting the SPIRE deployment managed by the
+// Zero Trust Workload Identity Manager: the conditions of the operand CRs, and the
+// registration entries, attested agents and trust bundle of the SPIRE server.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/inspect"
+)
+
+const usage = `Inspect the SPIRE deployment managed by the Zero Trust Workload Identity Manager.
+
+Usage:
+  kubectl ztwim [flags] <command>
+
+Commands:
+  status   Show the conditions of the operator CRs
+  entries  List the registration entries of the SPIRE server
+  agents   List the agents attested by the SPIRE server
+  bundle   Show the trust bundle of the SPIRE server
+
+Flags:
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	var (
+		kubeconfig  string
+		kubeContext string
+		namespace   string
+		output      string
+	)
+	flags := flag.NewFlagSet("kubectl-ztwim", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the kubectl configuration.")
+	flags.StringVar(&kubeContext, "context", "", "The kubeconfig context to use.")
+	flags.StringVar(&namespace, "namespace", "zero-trust-workload-identity-manager",
+		"The namespace of the operator. The operandNamespace of the ZeroTrustWorkloadIdentityManager takes precedence.")
+	flags.StringVar(&output, "output", "table", "The output format of entries, agents and bundle: table or json.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one command")
+	}
+	if output != "table" && output != "json" {
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	inspector := inspect.New(c, inspect.NewPodExecutor(config, clientset), os.Stdout, namespace)
+	outputJSON := output == "json"
+	switch command := flags.Arg(0); command {
+	case "status":
+		return inspector.Status(ctx)
+	case "entries":
+		return inspector.Entries(ctx, outputJSON)
+	case "agents":
+		return inspector.Agents(ctx, outputJSON)
+	case "bundle":
+		return inspector.Bundle(ctx, outputJSON)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/convert"
+)
+
+// convertCommand is the subcommand converting the values of the SPIRE Helm charts into the CRs of the operator
+const convertCommand = "convert"
+
+// runConvert writes the CRs converted from the Helm values, and warns about the values left out of the conversion
+func runConvert(args []string) error {
+	var (
+		valuesFile string
+		output     string
+		adopt      bool
+	)
+	flags := flag.NewFlagSet(convertCommand, flag.ExitOnError)
+	flags.StringVar(&valuesFile, "values", "-",
+		"The values.yaml of the spiffe/spire Helm chart. Set to - to read the standard input.")
+	flags.StringVar(&output, "output", "-",
+		"The file the custom resources are written to. Set to - to write to the standard output.")
+	flags.BoolVar(&adopt, "adopt-existing-resources", true,
+		"Set adoptExistingResources on the operand CRs, so that the operator adopts the resources of the Helm installation.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if valuesFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(valuesFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := convert.Convert(data, convert.Options{AdoptExistingResources: adopt})
+	if err != nil {
+		return err
+	}
+	for _, path := range result.Unsupported {
+		fmt.Fprintf(os.Stderr, "warning: %s has no equivalent in the custom resources and was not converted\n", path)
+	}
+
+	if output == "-" {
+		return result.WriteYAML(os.Stdout)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := result.WriteYAML(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
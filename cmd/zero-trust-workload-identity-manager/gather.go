@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2/textlogger"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/gather"
+)
+
+const (
+	// gatherCommand is the subcommand collecting the support data; the must-gather image links the
+	// operator binary as /usr/bin/gather, the entrypoint run by oc adm must-gather
+	gatherCommand = "gather"
+
+	// mustGatherDir is the directory oc adm must-gather copies the collected data from
+	mustGatherDir = "/must-gather"
+
+	defaultOperatorNamespace = "zero-trust-workload-identity-manager"
+)
+
+// runGather collects the support data of the operands into a support archive, or into a directory
+// when --dest-dir is set
+func runGather(args []string) error {
+	var (
+		destDir   string
+		output    string
+		namespace string
+		logsSince time.Duration
+	)
+	defaultNamespace := os.Getenv("OPERATOR_NAMESPACE")
+	if defaultNamespace == "" {
+		defaultNamespace = defaultOperatorNamespace
+	}
+
+	flags := flag.NewFlagSet(gatherCommand, flag.ExitOnError)
+	flags.StringVar(&destDir, "dest-dir", "",
+		"The directory the support data is written to. When not set, the support data is written to the --output archive.")
+	flags.StringVar(&output, "output", fmt.Sprintf("zero-trust-workload-identity-manager-gather-%s.tar.gz", time.Now().UTC().Format("20060102-150405")),
+		"The gzip compressed tar archive the support data is written to.")
+	flags.StringVar(&namespace, "namespace", defaultNamespace,
+		"The namespace of the operator. The operandNamespace of the ZeroTrustWorkloadIdentityManager takes precedence.")
+	flags.DurationVar(&logsSince, "since", 24*time.Hour,
+		"Only collect the container logs newer than the duration. Set to 0 to collect all logs.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	ctrl.SetLogger(textlogger.NewLogger(textlogger.NewConfig()))
+
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var writer gather.Writer
+	if destDir != "" {
+		if writer, err = gather.NewDirectoryWriter(destDir); err != nil {
+			return err
+		}
+	} else {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		writer = gather.NewArchiveWriter(file, "zero-trust-workload-identity-manager-gather")
+	}
+
+	gatherErr := gather.New(c, clientset, writer, gather.Options{Namespace: namespace, LogsSince: logsSince}).Gather(ctrl.SetupSignalHandler())
+	if err := writer.Close(); err != nil && gatherErr == nil {
+		gatherErr = err
+	}
+	if gatherErr != nil {
+		return gatherErr
+	}
+
+	destination := destDir
+	if destination == "" {
+		destination = output
+	}
+	setupLog.Info("collected support data", "destination", destination)
+	return nil
+}
@@ -17,30 +17,41 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"flag"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
 	operatorv1 "github.com/operator-framework/api/pkg/operators/v1"
 
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2/textlogger"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	operatoropenshiftiov1alpha1 "github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	operatoropenshiftiov1beta1 "github.com/openshift/zero-trust-workload-identity-manager/api/v1beta1"
 	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	spiffeCsiDriverController "github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/spiffe-csi-driver"
 	spireAgentController "github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/spire-agent"
@@ -48,6 +59,10 @@ import (
 	spireServerController "github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/spire-server"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	ztwimController "github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/zero-trust-workload-identity-manager"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/operatorconfig"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/version"
+	operandWebhook "github.com/openshift/zero-trust-workload-identity-manager/pkg/webhook"
 
 	securityv1 "github.com/openshift/api/security/v1"
 
@@ -76,27 +91,75 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(operatoropenshiftiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(operatoropenshiftiov1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	// Collect the support data instead of running the operator
+	if filepath.Base(os.Args[0]) == gatherCommand {
+		exitOnError(runGather(append([]string{"--dest-dir", mustGatherDir}, os.Args[1:]...)), "failed to gather support data")
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == gatherCommand {
+		exitOnError(runGather(os.Args[2:]), "failed to gather support data")
+		return
+	}
+	// Convert the values of a Helm installation of SPIRE instead of running the operator
+	if len(os.Args) > 1 && os.Args[1] == convertCommand {
+		exitOnError(runConvert(os.Args[2:]), "failed to convert the Helm values")
+		return
+	}
+
 	var (
 		metricsAddr          string
 		enableLeaderElection bool
 		probeAddr            string
+		pprofAddr            string
+		featureGates         string
 		secureMetrics        bool
 		enableHTTP2          bool
 		logLevel             int
 		metricsCerts         string
+		resyncInterval       time.Duration
+		leaseDuration        time.Duration
+		renewDeadline        time.Duration
+		retryPeriod          time.Duration
+		gracefulShutdown     time.Duration
+		retryBackoff         = retry.DefaultRetry
+		requestTimeout       time.Duration
+		managedByValues      string
+		cacheLabelSelector   string
+		lazyInformers        bool
+		informerSyncTimeout  time.Duration
+		maxConcurrent        int
+		maxConcurrentByName  string
+		rateLimiterOptions   = utils.DefaultRateLimiterOptions
+		degradedThreshold    int
 		metricsTLSOpts       []func(*tls.Config)
 		webhookTLSOpts       []func(*tls.Config)
 	)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP. Set to 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "",
+		"The localhost address the pprof endpoint binds to, e.g. 127.0.0.1:6060. "+
+			"Leave empty to disable profiling.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration that non-leader candidates will wait after observing a leadership renewal "+
+			"before attempting to acquire leadership.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration the acting leader will retry refreshing leadership before giving up. "+
+			"Must be less than the lease duration.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration leader election clients should wait between tries of actions. "+
+			"Must be less than the renew deadline.")
+	flag.DurationVar(&gracefulShutdown, "graceful-shutdown-timeout", 30*time.Second,
+		"The duration given to runnables to stop before the manager actually returns on stop. "+
+			"Set to 0 to stop without waiting.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
@@ -105,6 +168,48 @@ func main() {
 	flag.StringVar(&metricsCerts, "metrics-cert-dir", "",
 		"Secret name containing the certificates for the metrics server which should be present in operator namespace. "+
 			"If not provided self-signed certificates will be used")
+	flag.DurationVar(&resyncInterval, "resync-interval", utils.DefaultResyncInterval,
+		"How often managed resources are re-reconciled to repair drift. "+
+			"Can be overridden with resyncInterval on the ZeroTrustWorkloadIdentityManager or on each operand.")
+	flag.IntVar(&retryBackoff.Steps, "client-retry-steps", retryBackoff.Steps,
+		"The number of attempts made by the client when an update conflicts.")
+	flag.DurationVar(&retryBackoff.Duration, "client-retry-duration", retryBackoff.Duration,
+		"The initial wait between conflicting update attempts.")
+	flag.Float64Var(&retryBackoff.Factor, "client-retry-factor", retryBackoff.Factor,
+		"The factor the wait between conflicting update attempts is multiplied by after each attempt.")
+	flag.Float64Var(&retryBackoff.Jitter, "client-retry-jitter", retryBackoff.Jitter,
+		"The random fraction of the wait added to each wait between conflicting update attempts.")
+	flag.DurationVar(&requestTimeout, "client-request-timeout", time.Minute,
+		"The timeout of each request of the client to the API server, so that a hung API server or webhook fails the "+
+			"reconcile instead of stalling it. Set to 0 to disable.")
+	flag.StringVar(&managedByValues, "cache-managed-by-values", utils.AppManagedByLabelValue,
+		"Comma separated values of the app.kubernetes.io/managed-by label the managed resources are cached with. "+
+			"Add the value set by a previous installer, such as Helm, to adopt its resources.")
+	flag.StringVar(&cacheLabelSelector, "cache-label-selector", "",
+		"Additional label selector the managed resources must match to be cached, e.g. env=prod.")
+	flag.BoolVar(&lazyInformers, "lazy-informers", false,
+		"Start the informers of the kinds not watched by the controllers on their first read instead of at startup, "+
+			"so that a kind that can't be listed only fails the reconciles reading it.")
+	flag.DurationVar(&informerSyncTimeout, "informer-sync-timeout", time.Minute,
+		"How long a read waits for the informer of its kind to sync with --lazy-informers. Set to 0 to wait indefinitely.")
+	flag.IntVar(&maxConcurrent, "max-concurrent-reconciles", utils.DefaultMaxConcurrentReconciles,
+		"The number of concurrent reconciles of each controller.")
+	flag.StringVar(&maxConcurrentByName, "controller-max-concurrent-reconciles", os.Getenv(utils.MaxConcurrentReconcilesEnvName),
+		"Comma separated <controller>=<count> overrides of --max-concurrent-reconciles, e.g. spire-agent=2,spire-server=2. "+
+			"Defaults to the "+utils.MaxConcurrentReconcilesEnvName+" environment variable.")
+	flag.DurationVar(&rateLimiterOptions.BaseDelay, "rate-limiter-base-delay", rateLimiterOptions.BaseDelay,
+		"The initial delay before a failed reconcile is retried. The delay doubles on each consecutive failure.")
+	flag.DurationVar(&rateLimiterOptions.MaxDelay, "rate-limiter-max-delay", rateLimiterOptions.MaxDelay,
+		"The maximum delay before a failed reconcile is retried.")
+	flag.Float64Var(&rateLimiterOptions.QPS, "rate-limiter-qps", rateLimiterOptions.QPS,
+		"The overall number of retries per second of each controller.")
+	flag.IntVar(&rateLimiterOptions.BucketSize, "rate-limiter-bucket-size", rateLimiterOptions.BucketSize,
+		"The number of retries of each controller allowed in a burst above --rate-limiter-qps.")
+	flag.IntVar(&degradedThreshold, "degraded-failure-threshold", utils.DefaultDegradedFailureThreshold,
+		"The number of consecutive failed reconciles of a custom resource after which its Degraded condition is set.")
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"Comma separated <gate>=<true|false> pairs enabling or disabling experimental capabilities, e.g. Federation=false. "+
+			"Can be overridden with featureGates on the ZeroTrustWorkloadIdentityManager.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -114,6 +219,62 @@ func main() {
 	logConfig := textlogger.NewConfig(textlogger.Verbosity(logLevel))
 	ctrl.SetLogger(textlogger.NewLogger(logConfig))
 
+	if resyncInterval < time.Minute {
+		setupLog.Error(nil, "failed to start the operator, resync interval must be at least 1m", "resyncInterval", resyncInterval)
+		os.Exit(1)
+	}
+	utils.SetOperatorResyncInterval(resyncInterval)
+
+	if retryBackoff.Steps < 1 || retryBackoff.Duration <= 0 || retryBackoff.Factor < 1 || retryBackoff.Jitter < 0 {
+		setupLog.Error(nil, "failed to start the operator, invalid client retry backoff",
+			"steps", retryBackoff.Steps, "duration", retryBackoff.Duration, "factor", retryBackoff.Factor, "jitter", retryBackoff.Jitter)
+		os.Exit(1)
+	}
+	if requestTimeout < 0 {
+		setupLog.Error(nil, "failed to start the operator, client request timeout must not be negative", "requestTimeout", requestTimeout)
+		os.Exit(1)
+	}
+	clientOpts := []customClient.Option{customClient.WithRetryBackoff(retryBackoff), customClient.WithRequestTimeout(requestTimeout)}
+
+	concurrentReconciles, err := utils.ParseMaxConcurrentReconciles(maxConcurrent, maxConcurrentByName)
+	if err != nil {
+		setupLog.Error(err, "failed to start the operator, invalid max concurrent reconciles")
+		os.Exit(1)
+	}
+	for controllerName, count := range concurrentReconciles {
+		utils.SetMaxConcurrentReconciles(controllerName, count)
+	}
+
+	operatorFeatureGates, err := utils.ParseFeatureGates(featureGates)
+	if err != nil {
+		setupLog.Error(err, "failed to start the operator, invalid feature gates")
+		os.Exit(1)
+	}
+	utils.SetOperatorFeatureGates(operatorFeatureGates)
+
+	if err := rateLimiterOptions.Validate(); err != nil {
+		setupLog.Error(err, "failed to start the operator, invalid rate limiter options")
+		os.Exit(1)
+	}
+	utils.SetRateLimiterOptions(rateLimiterOptions)
+
+	if err := utils.SetDegradedFailureThreshold(degradedThreshold); err != nil {
+		setupLog.Error(err, "failed to start the operator, invalid degraded failure threshold")
+		os.Exit(1)
+	}
+
+	if renewDeadline >= leaseDuration || retryPeriod >= renewDeadline {
+		setupLog.Error(nil, "failed to start the operator, leader election timings must satisfy retry period < renew deadline < lease duration",
+			"leaseDuration", leaseDuration, "renewDeadline", renewDeadline, "retryPeriod", retryPeriod)
+		os.Exit(1)
+	}
+
+	// The profiles expose the operator memory, so they are only served on the loopback interface
+	if pprofAddr != "" && !isLoopbackAddress(pprofAddr) {
+		setupLog.Error(nil, "failed to start the operator, the pprof endpoint must bind to a localhost address", "pprofBindAddress", pprofAddr)
+		os.Exit(1)
+	}
+
 	// Validate that OPERATOR_NAMESPACE is set
 	operatorNamespace := utils.GetOperatorNamespace()
 	if operatorNamespace == "" {
@@ -122,6 +283,18 @@ func main() {
 	}
 	setupLog.Info("Operator namespace configured", "namespace", operatorNamespace)
 
+	// Restrict the manager cache to the watched namespaces; cluster scoped resources are not affected
+	cacheOptions := cache.Options{
+		SyncPeriod: &resyncInterval,
+	}
+	if watchNamespaces := utils.GetWatchNamespaces(); watchNamespaces != nil {
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config, len(watchNamespaces))
+		for _, namespace := range watchNamespaces {
+			cacheOptions.DefaultNamespaces[namespace] = cache.Config{}
+		}
+		setupLog.Info("Watching selected namespaces", "namespaces", watchNamespaces)
+	}
+
 	if !enableHTTP2 {
 		// if the enable-http2 flag is false (the default), http/2 should be disabled
 		// due to its vulnerabilities.
@@ -209,17 +382,28 @@ func main() {
 	}
 
 	// Create unified cache builder to prevent race conditions between manager and reconciler caches
-	cacheBuilder, err := customClient.NewCacheBuilder()
+	cacheOpts, err := managedResourceCacheOptions(managedByValues, cacheLabelSelector)
+	exitOnError(err, "invalid managed resource cache selector")
+	if lazyInformers {
+		cacheOpts = append(cacheOpts, customClient.WithLazyInformers(informerSyncTimeout))
+	}
+	cacheBuilder, err := customClient.NewCacheBuilder(cacheOpts...)
 	exitOnError(err, "unable to create cache builder")
 
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsServerOptions,
-		WebhookServer:          webhookServer,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "24a59323.operator.openshift.io",
-		NewCache:               cacheBuilder,
+		Scheme:                  scheme,
+		Metrics:                 metricsServerOptions,
+		WebhookServer:           webhookServer,
+		HealthProbeBindAddress:  probeAddr,
+		PprofBindAddress:        pprofAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "24a59323.operator.openshift.io",
+		LeaseDuration:           &leaseDuration,
+		RenewDeadline:           &renewDeadline,
+		RetryPeriod:             &retryPeriod,
+		GracefulShutdownTimeout: &gracefulShutdown,
+		NewCache:                cacheBuilder,
+		Cache:                   cacheOptions,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -234,19 +418,55 @@ func main() {
 	})
 	exitOnError(err, "unable to start manager")
 
-	ztwimControllerManager, err := ztwimController.New(mgr)
+	// Export the reconcile spans when an OTLP endpoint is configured with the OTEL_EXPORTER_OTLP_* variables
+	shutdownTracing, err := tracing.Setup(context.Background(), "zero-trust-workload-identity-manager", version.OperatorVersion)
+	exitOnError(err, "unable to set up tracing")
+	if tracing.Enabled() {
+		setupLog.Info("exporting reconcile traces with OTLP")
+	}
+	err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		// Flush the pending spans once the manager stops
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return shutdownTracing(flushCtx)
+	}))
+	exitOnError(err, "unable to set up tracing shutdown")
+
+	// Apply the operator configuration ConfigMap over the flags, and reapply it on each change
+	clientset, err := kubernetes.NewForConfig(config)
+	exitOnError(err, "unable to create the operator configuration client")
+	err = mgr.Add(operatorconfig.NewWatcher(clientset, operatorNamespace, operatorconfig.Defaults{
+		ResyncInterval: resyncInterval,
+		FeatureGates:   operatorFeatureGates,
+	}))
+	exitOnError(err, "unable to set up the operator configuration watcher")
+
+	ztwimControllerManager, err := ztwimController.New(mgr, clientOpts...)
 	exitOnError(err, "unable to set up ztwim controller manager")
 	if err = ztwimControllerManager.SetupWithManager(mgr); err != nil {
 		exitOnError(err, "unable to setup ztwim controller manager")
 	}
 
-	spireServerControllerManager, err := spireServerController.New(mgr)
+	spireServerControllerManager, err := spireServerController.New(mgr, clientOpts...)
 	exitOnError(err, "unable to set up spire server controller manager")
 	if err = spireServerControllerManager.SetupWithManager(mgr); err != nil {
 		exitOnError(err, "unable to setup spire server controller manager")
 	}
 
-	spireAgentControllerManager, err := spireAgentController.New(mgr)
+	nodeLifecycleControllerManager, err := spireServerController.NewNodeLifecycleReconciler(mgr, clientOpts...)
+	exitOnError(err, "unable to set up node lifecycle controller manager")
+	if err = nodeLifecycleControllerManager.SetupWithManager(mgr); err != nil {
+		exitOnError(err, "unable to setup node lifecycle controller manager")
+	}
+
+	entryPruningControllerManager, err := spireServerController.NewEntryPruningReconciler(mgr, clientOpts...)
+	exitOnError(err, "unable to set up entry pruning controller manager")
+	if err = entryPruningControllerManager.SetupWithManager(mgr); err != nil {
+		exitOnError(err, "unable to setup entry pruning controller manager")
+	}
+
+	spireAgentControllerManager, err := spireAgentController.New(mgr, clientOpts...)
 	if err != nil {
 		exitOnError(err, "unable to set up spire agent controller manager")
 	}
@@ -254,7 +474,7 @@ func main() {
 		exitOnError(err, "unable to setup spire agent controller manager")
 	}
 
-	spiffeCsiDriverControllerManager, err := spiffeCsiDriverController.New(mgr)
+	spiffeCsiDriverControllerManager, err := spiffeCsiDriverController.New(mgr, clientOpts...)
 	if err != nil {
 		exitOnError(err, "unable to set up spiffe csi driver controller manager")
 	}
@@ -262,7 +482,7 @@ func main() {
 		exitOnError(err, "unable to setup spiffe csi driver controller manager")
 	}
 
-	spireOIDCDiscoveryProviderControllerManager, err := spireOIDCDiscoveryProviderController.New(mgr)
+	spireOIDCDiscoveryProviderControllerManager, err := spireOIDCDiscoveryProviderController.New(mgr, clientOpts...)
 	if err != nil {
 		exitOnError(err, "unable to set up spire OIDC discovery provider controller manager")
 	}
@@ -270,6 +490,14 @@ func main() {
 		exitOnError(err, "unable to setup spire OIDC discovery provider controller manager")
 	}
 
+	// Webhooks are served by default; set ENABLE_WEBHOOKS=false when running the operator
+	// locally without the serving certificates OLM injects into the operator pod
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = operandWebhook.SetupWithManager(mgr); err != nil {
+			exitOnError(err, "unable to set up validating webhooks")
+		}
+	}
+
 	if err = mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		exitOnError(err, "unable to set up health check")
 	}
@@ -284,9 +512,46 @@ func main() {
 	exitOnError(err, "problem running manager")
 }
 
+// isLoopbackAddress reports whether the host of a host:port address is localhost or a loopback IP
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func exitOnError(err error, logMessage string) {
 	if err != nil {
 		setupLog.Error(err, logMessage)
 		os.Exit(1)
 	}
 }
+
+// managedResourceCacheOptions converts the cache flags into options for the custom cache builder
+func managedResourceCacheOptions(managedByValues, labelSelector string) ([]customClient.CacheOption, error) {
+	var values []string
+	for _, value := range strings.Split(managedByValues, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("at least one managed-by label value is required")
+	}
+	cacheOpts := []customClient.CacheOption{customClient.WithManagedResourceLabel(utils.AppManagedByLabelKey, values...)}
+
+	if labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache label selector %q: %w", labelSelector, err)
+		}
+		requirements, _ := selector.Requirements()
+		cacheOpts = append(cacheOpts, customClient.WithManagedResourceRequirements(requirements...))
+	}
+	return cacheOpts, nil
+}
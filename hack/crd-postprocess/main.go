@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// crd-postprocess completes the CRDs generated by controller-gen, rewriting the CRD files in place.
+//
+// controller-gen can't set the items of a field marked +kubebuilder:validation:Schemaless, so a list
+// field marked +kubebuilder:validation:Type=array and +kubebuilder:pruning:PreserveUnknownFields gets
+// items preserving the unknown fields of objects, which a structural schema requires.
+//
+// A CRD larger than the annotations a client-side kubectl apply records the applied object in can't
+// be installed without --server-side, so such a CRD fails the generation.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	documentSeparator = "---\n"
+	// maxCRDSize is the total size of the annotations of an object, which the last-applied-configuration
+	// annotation of a client-side kubectl apply must fit in
+	maxCRDSize = 256 * 1024
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: crd-postprocess <CRD directory>")
+		os.Exit(2)
+	}
+	files, err := filepath.Glob(filepath.Join(os.Args[1], "*.yaml"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, file := range files {
+		if err := postprocess(file); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// postprocess rewrites the CRD file when one of its schemaless list fields misses its items, and
+// checks that the CRD fits in the last-applied-configuration annotation
+func postprocess(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var crd map[string]interface{}
+	if err := yaml.Unmarshal(bytes.TrimPrefix(data, []byte(documentSeparator)), &crd); err != nil {
+		return err
+	}
+	if !completeItems(crd) {
+		return checkSize(data)
+	}
+
+	out, err := yaml.Marshal(crd)
+	if err != nil {
+		return err
+	}
+	// controller-gen starts the CRD files with a document separator
+	if bytes.HasPrefix(data, []byte(documentSeparator)) {
+		out = append([]byte(documentSeparator), out...)
+	}
+	if err := checkSize(out); err != nil {
+		return err
+	}
+	return os.WriteFile(file, out, 0o644)
+}
+
+// checkSize fails when the CRD doesn't fit in the last-applied-configuration annotation
+func checkSize(data []byte) error {
+	if len(data) > maxCRDSize {
+		return fmt.Errorf("the CRD is %d bytes, more than the %d bytes a client-side kubectl apply supports, make more fields schemaless", len(data), maxCRDSize)
+	}
+	return nil
+}
+
+// completeItems sets the items of the schemaless lists found in node, and reports whether any was set
+func completeItems(node interface{}) bool {
+	changed := false
+	switch n := node.(type) {
+	case map[string]interface{}:
+		_, hasItems := n["items"]
+		if n["type"] == "array" && n["x-kubernetes-preserve-unknown-fields"] == true && !hasItems {
+			delete(n, "x-kubernetes-preserve-unknown-fields")
+			n["items"] = map[string]interface{}{
+				"type":                                 "object",
+				"x-kubernetes-preserve-unknown-fields": true,
+			}
+			changed = true
+		}
+		for _, child := range n {
+			changed = completeItems(child) || changed
+		}
+	case []interface{}:
+		for _, child := range n {
+			changed = completeItems(child) || changed
+		}
+	}
+	return changed
+}
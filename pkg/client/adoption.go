@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// AdoptionClient wraps a CustomCtrlClient to adopt the resources of the same name created by another
+// installer, such as the SPIRE Helm charts. Those resources are missing from the cache, as they do not
+// carry the labels of the operator, so creates and applies look them up through the API reader, and
+// the desired state is applied over them. A workload whose immutable fields, such as its selector,
+// differ is recreated. The other calls are passed through to the wrapped client.
+type AdoptionClient struct {
+	CustomCtrlClient
+
+	mu      sync.Mutex
+	adopted []client.Object
+}
+
+// NewAdoptionClient returns an AdoptionClient adopting the existing resources written through c
+func NewAdoptionClient(c CustomCtrlClient) *AdoptionClient {
+	return &AdoptionClient{CustomCtrlClient: c}
+}
+
+// Create adopts the existing resource when the create conflicts with a resource the operator did not create
+func (c *AdoptionClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.CustomCtrlClient.Create(ctx, obj, opts...)
+	if !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, getErr := c.getUnmanaged(ctx, obj)
+	if getErr != nil {
+		return getErr
+	}
+	if existing == nil {
+		return err
+	}
+	return c.adopt(ctx, obj, existing)
+}
+
+// Apply adopts the existing resource when the operator did not create it
+func (c *AdoptionClient) Apply(ctx context.Context, obj client.Object, opts ...client.PatchOption) error {
+	existing, err := c.getUnmanaged(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return c.CustomCtrlClient.Apply(ctx, obj, opts...)
+	}
+	return c.adopt(ctx, obj, existing, opts...)
+}
+
+// Adopted returns the resources adopted so far, in the order they were adopted
+func (c *AdoptionClient) Adopted() []client.Object {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]client.Object(nil), c.adopted...)
+}
+
+// adopt applies obj over the existing resource. When the API server rejects the change of an immutable
+// field of a workload, the workload is deleted and created again. The PersistentVolumeClaims of a
+// StatefulSet are retained, so that the new pods mount the same volumes.
+func (c *AdoptionClient) adopt(ctx context.Context, obj, existing client.Object, opts ...client.PatchOption) error {
+	err := c.CustomCtrlClient.Apply(ctx, obj, opts...)
+	if kerrors.IsInvalid(err) && isWorkload(existing) {
+		if err := c.retainVolumeClaims(ctx, existing); err != nil {
+			return err
+		}
+		if err := c.CustomCtrlClient.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+		err = c.CustomCtrlClient.Apply(ctx, obj, opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.adopted = append(c.adopted, obj)
+	return nil
+}
+
+// retainVolumeClaims keeps the PersistentVolumeClaims of a StatefulSet from being deleted with it
+func (c *AdoptionClient) retainVolumeClaims(ctx context.Context, existing client.Object) error {
+	sts, ok := existing.(*appsv1.StatefulSet)
+	if !ok {
+		return nil
+	}
+	policy := sts.Spec.PersistentVolumeClaimRetentionPolicy
+	if policy == nil || policy.WhenDeleted != appsv1.DeletePersistentVolumeClaimRetentionPolicyType {
+		return nil
+	}
+	policy.WhenDeleted = appsv1.RetainPersistentVolumeClaimRetentionPolicyType
+	return c.CustomCtrlClient.Update(ctx, sts)
+}
+
+// getUnmanaged returns the existing resource of the same name as obj when it was not created by the
+// operator, and nil when it does not exist or is already managed
+func (c *AdoptionClient) getUnmanaged(ctx context.Context, obj client.Object) (client.Object, error) {
+	reader := c.APIReader()
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if reader == nil || !ok {
+		return nil, nil
+	}
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if existing.GetLabels()[utils.AppManagedByLabelKey] == utils.AppManagedByLabelValue {
+		return nil, nil
+	}
+	return existing, nil
+}
+
+// isWorkload returns whether obj runs pods whose selector can't be changed
+func isWorkload(obj client.Object) bool {
+	switch obj.(type) {
+	case *appsv1.Deployment, *appsv1.DaemonSet, *appsv1.StatefulSet:
+		return true
+	}
+	return false
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	operatorv1 "github.com/operator-framework/api/pkg/operators/v1"
 	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
@@ -18,19 +23,28 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
+// FieldManager is the field manager the operator applies its resources with
+const FieldManager = "zero-trust-workload-identity-manager"
+
 var (
 	// cacheResources is the list of resources that the controller watches,
 	// and creates informers for.
@@ -49,6 +63,11 @@ var (
 		&admissionregistrationv1.ValidatingWebhookConfiguration{},
 		&routev1.Route{},
 		&spiffev1alpha1.ClusterSPIFFEID{},
+		&policyv1.PodDisruptionBudget{},
+		&networkingv1.NetworkPolicy{},
+		&networkingv1.Ingress{},
+		&autoscalingv2.HorizontalPodAutoscaler{},
+		&batchv1.CronJob{},
 	}
 
 	cacheResourceWithoutReqSelectors = []client.Object{
@@ -58,6 +77,7 @@ var (
 		&v1alpha1.SpireServer{},
 		&v1alpha1.SpireOIDCDiscoveryProvider{},
 		&operatorv1.OperatorCondition{},
+		&corev1.Namespace{},
 	}
 
 	informerResources = []client.Object{
@@ -81,12 +101,20 @@ var (
 		&routev1.Route{},
 		&spiffev1alpha1.ClusterSPIFFEID{},
 		&operatorv1.OperatorCondition{},
+		&policyv1.PodDisruptionBudget{},
+		&networkingv1.NetworkPolicy{},
+		&networkingv1.Ingress{},
+		&autoscalingv2.HorizontalPodAutoscaler{},
+		&batchv1.CronJob{},
+		&corev1.Namespace{},
 	}
 )
 
 type customCtrlClientImpl struct {
 	client.Client
-	apiReader client.Reader
+	apiReader      client.Reader
+	retryBackoff   wait.Backoff
+	requestTimeout time.Duration
 }
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -99,22 +127,33 @@ type CustomCtrlClient interface {
 	UpdateWithRetry(context.Context, client.Object, ...client.UpdateOption) error
 	Create(context.Context, client.Object, ...client.CreateOption) error
 	Delete(context.Context, client.Object, ...client.DeleteOption) error
+	DeleteAllOf(context.Context, client.Object, ...client.DeleteAllOfOption) error
 	Patch(context.Context, client.Object, client.Patch, ...client.PatchOption) error
 	Exists(context.Context, client.ObjectKey, client.Object) (bool, error)
-	CreateOrUpdateObject(ctx context.Context, obj client.Object) error
+	Apply(ctx context.Context, obj client.Object, opts ...client.PatchOption) error
 	StatusUpdateWithRetry(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error
 	GetClient() client.Client
+	APIReader() client.Reader
 }
 
-func NewCustomClient(m manager.Manager) (CustomCtrlClient, error) {
+func NewCustomClient(m manager.Manager, opts ...Option) (CustomCtrlClient, error) {
 	c, err := BuildCustomClient(m)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build custom client: %w", err)
 	}
-	return &customCtrlClientImpl{
-		Client:    c,
-		apiReader: m.GetAPIReader(),
-	}, nil
+	customClient := &customCtrlClientImpl{
+		apiReader:    m.GetAPIReader(),
+		retryBackoff: retry.DefaultRetry,
+	}
+	for _, opt := range opts {
+		opt(customClient)
+	}
+	if customClient.requestTimeout > 0 {
+		c = newTimeoutClient(c, customClient.requestTimeout)
+		customClient.apiReader = &timeoutReader{Reader: customClient.apiReader, timeout: customClient.requestTimeout}
+	}
+	customClient.Client = newInstrumentedClient(c)
+	return customClient, nil
 }
 
 func (c *customCtrlClientImpl) Get(
@@ -131,27 +170,50 @@ func (c *customCtrlClientImpl) List(
 
 func (c *customCtrlClientImpl) Create(
 	ctx context.Context, obj client.Object, opts ...client.CreateOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "Create", obj)
+	defer func() { tracing.End(span, err) }()
+
 	return c.Client.Create(ctx, obj, opts...)
 }
 
 func (c *customCtrlClientImpl) Delete(
 	ctx context.Context, obj client.Object, opts ...client.DeleteOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "Delete", obj)
+	defer func() { tracing.End(span, err) }()
+
 	return c.Client.Delete(ctx, obj, opts...)
 }
 
+// DeleteAllOf deletes all objects of the given type matching the options,
+// e.g. client.InNamespace and client.MatchingLabels, in a single request
+func (c *customCtrlClientImpl) DeleteAllOf(
+	ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption,
+) (err error) {
+	ctx, span := c.startSpan(ctx, "DeleteAllOf", obj)
+	defer func() { tracing.End(span, err) }()
+
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
 func (c *customCtrlClientImpl) Update(
 	ctx context.Context, obj client.Object, opts ...client.UpdateOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "Update", obj)
+	defer func() { tracing.End(span, err) }()
+
 	return c.Client.Update(ctx, obj, opts...)
 }
 
 func (c *customCtrlClientImpl) UpdateWithRetry(
 	ctx context.Context, obj client.Object, opts ...client.UpdateOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "Update", obj)
+	defer func() { tracing.End(span, err) }()
+
 	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
-	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	if err := c.retryOnConflict(key, "update", obj, func() error {
 		current := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
 		if err := c.Client.Get(ctx, key, current); err != nil {
 			return fmt.Errorf("failed to fetch latest %q for update: %w", key, err)
@@ -170,9 +232,12 @@ func (c *customCtrlClientImpl) UpdateWithRetry(
 
 func (c *customCtrlClientImpl) StatusUpdateWithRetry(
 	ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "UpdateStatus", obj)
+	defer func() { tracing.End(span, err) }()
+
 	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
-	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	if err := c.retryOnConflict(key, "update_status", obj, func() error {
 		current := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
 		if err := c.Client.Get(ctx, key, current); err != nil {
 			return fmt.Errorf("failed to fetch latest %q for update: %w", key, err)
@@ -190,13 +255,19 @@ func (c *customCtrlClientImpl) StatusUpdateWithRetry(
 
 func (c *customCtrlClientImpl) StatusUpdate(
 	ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "UpdateStatus", obj)
+	defer func() { tracing.End(span, err) }()
+
 	return c.Client.Status().Update(ctx, obj, opts...)
 }
 
 func (c *customCtrlClientImpl) Patch(
 	ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption,
-) error {
+) (err error) {
+	ctx, span := c.startSpan(ctx, "Patch", obj)
+	defer func() { tracing.End(span, err) }()
+
 	return c.Client.Patch(ctx, obj, patch, opts...)
 }
 
@@ -210,13 +281,31 @@ func (c *customCtrlClientImpl) Exists(ctx context.Context, key client.ObjectKey,
 	return true, nil
 }
 
-// CreateOrUpdateObject tries to create the object, updates if already exists
-func (c *customCtrlClientImpl) CreateOrUpdateObject(ctx context.Context, obj client.Object) error {
-	err := c.Create(ctx, obj)
-	if err != nil && errors.IsAlreadyExists(err) {
-		return c.Update(ctx, obj)
+// Apply creates or updates the object with server-side apply using the operator field manager.
+// Only the fields set on obj are owned by the operator, so fields managed by other controllers,
+// like the replicas set by a HorizontalPodAutoscaler or injected containers, are left untouched.
+// Conflicts are forced, as the operator is the source of truth for the fields it sets.
+func (c *customCtrlClientImpl) Apply(ctx context.Context, obj client.Object, opts ...client.PatchOption) (err error) {
+	ctx, span := c.startSpan(ctx, "Apply", obj)
+	defer func() { tracing.End(span, err) }()
+
+	gvk, err := apiutil.GVKForObject(obj, c.Client.Scheme())
+	if err != nil {
+		return fmt.Errorf("failed to resolve the kind of %q: %w", client.ObjectKeyFromObject(obj), err)
 	}
-	return err
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	// Apply configurations must not carry the server managed metadata of a previous read
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	applyOpts := append([]client.PatchOption{client.FieldOwner(FieldManager), client.ForceOwnership}, opts...)
+	return c.Client.Patch(ctx, obj, client.Apply, applyOpts...)
+}
+
+// startSpan starts the span of a write of obj to the API server
+func (c *customCtrlClientImpl) startSpan(ctx context.Context, operation string, obj client.Object) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "Kubernetes "+operation,
+		tracing.ObjectAttributes(kindOf(obj, c.Client.Scheme()), obj.GetNamespace(), obj.GetName())...)
 }
 
 // GetClient returns the underlying client.Client
@@ -224,15 +313,72 @@ func (c *customCtrlClientImpl) GetClient() client.Client {
 	return c.Client
 }
 
+// APIReader returns a reader served by the API server, which sees the resources missing from the
+// cache because they do not carry the labels of the operator
+func (c *customCtrlClientImpl) APIReader() client.Reader {
+	return c.apiReader
+}
+
+// CacheOption configures the cache returned by NewCacheBuilder
+type CacheOption func(*cacheConfig)
+
+type cacheConfig struct {
+	managedLabelKey    string
+	managedLabelValues []string
+	requirements       []labels.Requirement
+
+	lazyInformers       bool
+	informerSyncTimeout time.Duration
+}
+
+// WithManagedResourceLabel replaces the label the managed resources are selected by in the cache.
+// The resources match when the label has any of the given values, e.g. to adopt resources
+// labeled app.kubernetes.io/managed-by=Helm by a previous install.
+func WithManagedResourceLabel(key string, values ...string) CacheOption {
+	return func(c *cacheConfig) {
+		c.managedLabelKey = key
+		c.managedLabelValues = values
+	}
+}
+
+// WithManagedResourceRequirements adds label requirements the managed resources must also match
+func WithManagedResourceRequirements(requirements ...labels.Requirement) CacheOption {
+	return func(c *cacheConfig) {
+		c.requirements = append(c.requirements, requirements...)
+	}
+}
+
+// managedResourceSelector returns the label selector the managed resources are cached with
+func (c *cacheConfig) managedResourceSelector() (labels.Selector, error) {
+	operator := selection.Equals
+	if len(c.managedLabelValues) > 1 {
+		operator = selection.In
+	}
+	managedReq, err := labels.NewRequirement(c.managedLabelKey, operator, c.managedLabelValues)
+	if err != nil {
+		return nil, fmt.Errorf("invalid managed resource label: %w", err)
+	}
+	return labels.NewSelector().Add(*managedReq).Add(c.requirements...), nil
+}
+
 // NewCacheBuilder returns a cache builder function that configures the manager's cache
 // with custom label selectors and informers. This function should be passed to the
 // manager's NewCache option to ensure a unified cache is used.
-func NewCacheBuilder() (cache.NewCacheFunc, error) {
-	spireServerManagedResourceAppManagedReq, err := labels.NewRequirement(utils.AppManagedByLabelKey, selection.Equals, []string{utils.AppManagedByLabelValue})
+// By default the managed resources are selected by the app.kubernetes.io/managed-by label
+// set by the operator, which can be changed with the given options, and the informers of
+// all the cached kinds are registered before the cache is returned, unless WithLazyInformers is set.
+func NewCacheBuilder(cacheOpts ...CacheOption) (cache.NewCacheFunc, error) {
+	cfg := &cacheConfig{
+		managedLabelKey:    utils.AppManagedByLabelKey,
+		managedLabelValues: []string{utils.AppManagedByLabelValue},
+	}
+	for _, opt := range cacheOpts {
+		opt(cfg)
+	}
+	managedResourceLabelReqSelector, err := cfg.managedResourceSelector()
 	if err != nil {
 		return nil, err
 	}
-	managedResourceLabelReqSelector := labels.NewSelector().Add(*spireServerManagedResourceAppManagedReq)
 
 	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
 		// Configure cache with custom label selectors
@@ -255,13 +401,17 @@ func NewCacheBuilder() (cache.NewCacheFunc, error) {
 			}
 		}
 
-		opts.ReaderFailOnMissingInformer = true
+		opts.ReaderFailOnMissingInformer = !cfg.lazyInformers
+		opts.DefaultTransform = chainTransforms(opts.DefaultTransform, stripCachedMetadata)
 
 		// Create the cache with the merged options
 		newCache, err := cache.New(config, opts)
 		if err != nil {
 			return nil, err
 		}
+		if cfg.lazyInformers {
+			return &lazyCache{Cache: newCache, syncTimeout: cfg.informerSyncTimeout}, nil
+		}
 
 		// Pre-register informers for all resources
 		for _, resource := range informerResources {
@@ -274,6 +424,43 @@ func NewCacheBuilder() (cache.NewCacheFunc, error) {
 	}, nil
 }
 
+// stripCachedMetadata drops the metadata the operator never reads from cached objects to reduce
+// memory usage. Updates from cached objects are unaffected: the API server keeps the managed
+// fields when none are sent, and the desired objects never carry the last-applied annotation.
+func stripCachedMetadata(in any) (any, error) {
+	obj, err := meta.Accessor(in)
+	if err != nil {
+		return in, nil
+	}
+	// Nil check the managed fields to avoid https://github.com/kubernetes/kubernetes/issues/124337
+	if obj.GetManagedFields() != nil {
+		obj.SetManagedFields(nil)
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			obj.SetAnnotations(annotations)
+		}
+	}
+	return in, nil
+}
+
+// chainTransforms runs the given transforms in order, skipping nil ones
+func chainTransforms(transforms ...toolscache.TransformFunc) toolscache.TransformFunc {
+	return func(in any) (any, error) {
+		var err error
+		for _, transform := range transforms {
+			if transform == nil {
+				continue
+			}
+			if in, err = transform(in); err != nil {
+				return nil, err
+			}
+		}
+		return in, nil
+	}
+}
+
 // BuildCustomClient now uses the manager's unified cache instead of creating a separate one.
 // This eliminates the race condition between manager and reconciler caches.
 func BuildCustomClient(mgr ctrl.Manager) (client.Client, error) {
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+const (
+	PlannedActionCreate = "Create"
+	PlannedActionUpdate = "Update"
+	PlannedActionPatch  = "Patch"
+	PlannedActionDelete = "Delete"
+)
+
+// DryRunClient wraps a CustomCtrlClient for dry-run reconciliations.
+// Reads and status updates are passed through to the wrapped client, while
+// creates, updates, patches and deletes are recorded as planned changes and
+// never sent to the API server. Writes made on the client returned by GetClient
+// are not intercepted.
+type DryRunClient struct {
+	CustomCtrlClient
+	scheme *runtime.Scheme
+
+	mu      sync.Mutex
+	changes []v1alpha1.PlannedChange
+}
+
+// NewDryRunClient returns a DryRunClient recording the writes made through c.
+// The scheme is used to resolve the kind of typed objects.
+func NewDryRunClient(c CustomCtrlClient, scheme *runtime.Scheme) *DryRunClient {
+	return &DryRunClient{
+		CustomCtrlClient: c,
+		scheme:           scheme,
+	}
+}
+
+func (c *DryRunClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.record(PlannedActionCreate, obj)
+	return nil
+}
+
+func (c *DryRunClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.record(PlannedActionUpdate, obj)
+	return nil
+}
+
+func (c *DryRunClient) UpdateWithRetry(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.record(PlannedActionUpdate, obj)
+	return nil
+}
+
+func (c *DryRunClient) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	c.record(PlannedActionPatch, obj)
+	return nil
+}
+
+func (c *DryRunClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	c.record(PlannedActionDelete, obj)
+	return nil
+}
+
+// DeleteAllOf records a single delete for the matched objects, named after the label selector
+func (c *DryRunClient) DeleteAllOf(_ context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteAllOpts := &client.DeleteAllOfOptions{}
+	deleteAllOpts.ApplyOptions(opts)
+
+	selector := "*"
+	if deleteAllOpts.LabelSelector != nil && !deleteAllOpts.LabelSelector.Empty() {
+		selector = deleteAllOpts.LabelSelector.String()
+	}
+	c.recordChange(PlannedActionDelete, obj, deleteAllOpts.Namespace, selector)
+	return nil
+}
+
+// Apply records a create or an update depending on whether the object exists
+func (c *DryRunClient) Apply(ctx context.Context, obj client.Object, _ ...client.PatchOption) error {
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		c.record(PlannedActionCreate, obj)
+		return nil
+	}
+	exists, err := c.CustomCtrlClient.Exists(ctx, client.ObjectKeyFromObject(obj), existing)
+	if err != nil {
+		return err
+	}
+	if exists {
+		c.record(PlannedActionUpdate, obj)
+	} else {
+		c.record(PlannedActionCreate, obj)
+	}
+	return nil
+}
+
+// Changes returns the changes recorded so far, in the order they were made
+func (c *DryRunClient) Changes() []v1alpha1.PlannedChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]v1alpha1.PlannedChange(nil), c.changes...)
+}
+
+func (c *DryRunClient) record(action string, obj client.Object) {
+	c.recordChange(action, obj, obj.GetNamespace(), obj.GetName())
+}
+
+func (c *DryRunClient) recordChange(action string, obj client.Object, namespace, name string) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" && c.scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, c.scheme); err == nil {
+			kind = gvk.Kind
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, v1alpha1.PlannedChange{
+		Action:    action,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	})
+}
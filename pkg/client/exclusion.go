@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// ExclusionClient wraps a CustomCtrlClient to leave the resources excluded from management to
+// their owner. Creates are passed through for the CreateOnly exclusions, and the other writes to
+// excluded resources are skipped and recorded. Reads, status updates and DeleteAllOf are passed
+// through to the wrapped client. Writes made on the client returned by GetClient are not intercepted.
+type ExclusionClient struct {
+	CustomCtrlClient
+	scheme     *runtime.Scheme
+	exclusions []v1alpha1.ResourceExclusion
+
+	mu      sync.Mutex
+	skipped []v1alpha1.PlannedChange
+}
+
+// NewExclusionClient returns an ExclusionClient skipping the writes made through c to the resources
+// matching exclusions. The scheme is used to resolve the kind of typed objects.
+func NewExclusionClient(c CustomCtrlClient, scheme *runtime.Scheme, exclusions []v1alpha1.ResourceExclusion) *ExclusionClient {
+	return &ExclusionClient{
+		CustomCtrlClient: c,
+		scheme:           scheme,
+		exclusions:       exclusions,
+	}
+}
+
+// Create creates the CreateOnly excluded resources, which may already exist outside of the cache
+// when created by their owner without the labels of the operator
+func (c *ExclusionClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	exclusion := c.exclusionFor(obj)
+	if exclusion == nil {
+		return c.CustomCtrlClient.Create(ctx, obj, opts...)
+	}
+	if !utils.IsCreateOnlyExclusion(exclusion) {
+		c.record(PlannedActionCreate, obj)
+		return nil
+	}
+	if err := c.CustomCtrlClient.Create(ctx, obj, opts...); err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (c *ExclusionClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.skip(PlannedActionUpdate, obj) {
+		return nil
+	}
+	return c.CustomCtrlClient.Update(ctx, obj, opts...)
+}
+
+func (c *ExclusionClient) UpdateWithRetry(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.skip(PlannedActionUpdate, obj) {
+		return nil
+	}
+	return c.CustomCtrlClient.UpdateWithRetry(ctx, obj, opts...)
+}
+
+func (c *ExclusionClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.skip(PlannedActionPatch, obj) {
+		return nil
+	}
+	return c.CustomCtrlClient.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *ExclusionClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.skip(PlannedActionDelete, obj) {
+		return nil
+	}
+	return c.CustomCtrlClient.Delete(ctx, obj, opts...)
+}
+
+// Apply creates the missing CreateOnly excluded resources and skips the other applies of excluded resources
+func (c *ExclusionClient) Apply(ctx context.Context, obj client.Object, opts ...client.PatchOption) error {
+	exclusion := c.exclusionFor(obj)
+	if exclusion == nil {
+		return c.CustomCtrlClient.Apply(ctx, obj, opts...)
+	}
+	if utils.IsCreateOnlyExclusion(exclusion) {
+		if existing, ok := obj.DeepCopyObject().(client.Object); ok {
+			exists, err := c.CustomCtrlClient.Exists(ctx, client.ObjectKeyFromObject(obj), existing)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return c.CustomCtrlClient.Apply(ctx, obj, opts...)
+			}
+		}
+	}
+	c.record(PlannedActionUpdate, obj)
+	return nil
+}
+
+// Skipped returns the writes skipped so far, in the order they were made
+func (c *ExclusionClient) Skipped() []v1alpha1.PlannedChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]v1alpha1.PlannedChange(nil), c.skipped...)
+}
+
+// skip records the write when obj is excluded from management
+func (c *ExclusionClient) skip(action string, obj client.Object) bool {
+	if c.exclusionFor(obj) == nil {
+		return false
+	}
+	c.record(action, obj)
+	return true
+}
+
+func (c *ExclusionClient) exclusionFor(obj client.Object) *v1alpha1.ResourceExclusion {
+	return utils.FindResourceExclusion(c.exclusions, c.kindOf(obj), obj.GetName())
+}
+
+func (c *ExclusionClient) kindOf(obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" && c.scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, c.scheme); err == nil {
+			kind = gvk.Kind
+		}
+	}
+	return kind
+}
+
+func (c *ExclusionClient) record(action string, obj client.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.skipped = append(c.skipped, v1alpha1.PlannedChange{
+		Action:    action,
+		Kind:      c.kindOf(obj),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	})
+}
@@ -10,29 +10,40 @@ import (
 )
 
 type FakeCustomCtrlClient struct {
-	CreateStub        func(context.Context, clienta.Object, ...clienta.CreateOption) error
-	createMutex       sync.RWMutex
-	createArgsForCall []struct {
+	APIReaderStub        func() clienta.Reader
+	aPIReaderMutex       sync.RWMutex
+	aPIReaderArgsForCall []struct {
+	}
+	aPIReaderReturns struct {
+		result1 clienta.Reader
+	}
+	aPIReaderReturnsOnCall map[int]struct {
+		result1 clienta.Reader
+	}
+	ApplyStub        func(context.Context, clienta.Object, ...clienta.PatchOption) error
+	applyMutex       sync.RWMutex
+	applyArgsForCall []struct {
 		arg1 context.Context
 		arg2 clienta.Object
-		arg3 []clienta.CreateOption
+		arg3 []clienta.PatchOption
 	}
-	createReturns struct {
+	applyReturns struct {
 		result1 error
 	}
-	createReturnsOnCall map[int]struct {
+	applyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	CreateOrUpdateObjectStub        func(context.Context, clienta.Object) error
-	createOrUpdateObjectMutex       sync.RWMutex
-	createOrUpdateObjectArgsForCall []struct {
+	CreateStub        func(context.Context, clienta.Object, ...clienta.CreateOption) error
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
 		arg1 context.Context
 		arg2 clienta.Object
+		arg3 []clienta.CreateOption
 	}
-	createOrUpdateObjectReturns struct {
+	createReturns struct {
 		result1 error
 	}
-	createOrUpdateObjectReturnsOnCall map[int]struct {
+	createReturnsOnCall map[int]struct {
 		result1 error
 	}
 	DeleteStub        func(context.Context, clienta.Object, ...clienta.DeleteOption) error
@@ -48,6 +59,19 @@ type FakeCustomCtrlClient struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteAllOfStub        func(context.Context, clienta.Object, ...clienta.DeleteAllOfOption) error
+	deleteAllOfMutex       sync.RWMutex
+	deleteAllOfArgsForCall []struct {
+		arg1 context.Context
+		arg2 clienta.Object
+		arg3 []clienta.DeleteAllOfOption
+	}
+	deleteAllOfReturns struct {
+		result1 error
+	}
+	deleteAllOfReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ExistsStub        func(context.Context, clienta.ObjectKey, clienta.Object) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
@@ -76,6 +100,16 @@ type FakeCustomCtrlClient struct {
 	getReturnsOnCall map[int]struct {
 		result1 error
 	}
+	GetClientStub        func() clienta.Client
+	getClientMutex       sync.RWMutex
+	getClientArgsForCall []struct {
+	}
+	getClientReturns struct {
+		result1 clienta.Client
+	}
+	getClientReturnsOnCall map[int]struct {
+		result1 clienta.Client
+	}
 	ListStub        func(context.Context, clienta.ObjectList, ...clienta.ListOption) error
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
@@ -155,18 +189,124 @@ type FakeCustomCtrlClient struct {
 	updateWithRetryReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetClientStub        func() clienta.Client
-	getClientMutex       sync.RWMutex
-	getClientArgsForCall []struct {
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCustomCtrlClient) APIReader() clienta.Reader {
+	fake.aPIReaderMutex.Lock()
+	ret, specificReturn := fake.aPIReaderReturnsOnCall[len(fake.aPIReaderArgsForCall)]
+	fake.aPIReaderArgsForCall = append(fake.aPIReaderArgsForCall, struct {
+	}{})
+	stub := fake.APIReaderStub
+	fakeReturns := fake.aPIReaderReturns
+	fake.recordInvocation("APIReader", []interface{}{})
+	fake.aPIReaderMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
-	getClientReturns struct {
-		result1 clienta.Client
+	if specificReturn {
+		return ret.result1
 	}
-	getClientReturnsOnCall map[int]struct {
-		result1 clienta.Client
+	return fakeReturns.result1
+}
+
+func (fake *FakeCustomCtrlClient) APIReaderCallCount() int {
+	fake.aPIReaderMutex.RLock()
+	defer fake.aPIReaderMutex.RUnlock()
+	return len(fake.aPIReaderArgsForCall)
+}
+
+func (fake *FakeCustomCtrlClient) APIReaderCalls(stub func() clienta.Reader) {
+	fake.aPIReaderMutex.Lock()
+	defer fake.aPIReaderMutex.Unlock()
+	fake.APIReaderStub = stub
+}
+
+func (fake *FakeCustomCtrlClient) APIReaderReturns(result1 clienta.Reader) {
+	fake.aPIReaderMutex.Lock()
+	defer fake.aPIReaderMutex.Unlock()
+	fake.APIReaderStub = nil
+	fake.aPIReaderReturns = struct {
+		result1 clienta.Reader
+	}{result1}
+}
+
+func (fake *FakeCustomCtrlClient) APIReaderReturnsOnCall(i int, result1 clienta.Reader) {
+	fake.aPIReaderMutex.Lock()
+	defer fake.aPIReaderMutex.Unlock()
+	fake.APIReaderStub = nil
+	if fake.aPIReaderReturnsOnCall == nil {
+		fake.aPIReaderReturnsOnCall = make(map[int]struct {
+			result1 clienta.Reader
+		})
 	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
+	fake.aPIReaderReturnsOnCall[i] = struct {
+		result1 clienta.Reader
+	}{result1}
+}
+
+func (fake *FakeCustomCtrlClient) Apply(arg1 context.Context, arg2 clienta.Object, arg3 ...clienta.PatchOption) error {
+	fake.applyMutex.Lock()
+	ret, specificReturn := fake.applyReturnsOnCall[len(fake.applyArgsForCall)]
+	fake.applyArgsForCall = append(fake.applyArgsForCall, struct {
+		arg1 context.Context
+		arg2 clienta.Object
+		arg3 []clienta.PatchOption
+	}{arg1, arg2, arg3})
+	stub := fake.ApplyStub
+	fakeReturns := fake.applyReturns
+	fake.recordInvocation("Apply", []interface{}{arg1, arg2, arg3})
+	fake.applyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCustomCtrlClient) ApplyCallCount() int {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	return len(fake.applyArgsForCall)
+}
+
+func (fake *FakeCustomCtrlClient) ApplyCalls(stub func(context.Context, clienta.Object, ...clienta.PatchOption) error) {
+	fake.applyMutex.Lock()
+	defer fake.applyMutex.Unlock()
+	fake.ApplyStub = stub
+}
+
+func (fake *FakeCustomCtrlClient) ApplyArgsForCall(i int) (context.Context, clienta.Object, []clienta.PatchOption) {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	argsForCall := fake.applyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCustomCtrlClient) ApplyReturns(result1 error) {
+	fake.applyMutex.Lock()
+	defer fake.applyMutex.Unlock()
+	fake.ApplyStub = nil
+	fake.applyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCustomCtrlClient) ApplyReturnsOnCall(i int, result1 error) {
+	fake.applyMutex.Lock()
+	defer fake.applyMutex.Unlock()
+	fake.ApplyStub = nil
+	if fake.applyReturnsOnCall == nil {
+		fake.applyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.applyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
 func (fake *FakeCustomCtrlClient) Create(arg1 context.Context, arg2 clienta.Object, arg3 ...clienta.CreateOption) error {
@@ -232,68 +372,6 @@ func (fake *FakeCustomCtrlClient) CreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeCustomCtrlClient) CreateOrUpdateObject(arg1 context.Context, arg2 clienta.Object) error {
-	fake.createOrUpdateObjectMutex.Lock()
-	ret, specificReturn := fake.createOrUpdateObjectReturnsOnCall[len(fake.createOrUpdateObjectArgsForCall)]
-	fake.createOrUpdateObjectArgsForCall = append(fake.createOrUpdateObjectArgsForCall, struct {
-		arg1 context.Context
-		arg2 clienta.Object
-	}{arg1, arg2})
-	stub := fake.CreateOrUpdateObjectStub
-	fakeReturns := fake.createOrUpdateObjectReturns
-	fake.recordInvocation("CreateOrUpdateObject", []interface{}{arg1, arg2})
-	fake.createOrUpdateObjectMutex.Unlock()
-	if stub != nil {
-		return stub(arg1, arg2)
-	}
-	if specificReturn {
-		return ret.result1
-	}
-	return fakeReturns.result1
-}
-
-func (fake *FakeCustomCtrlClient) CreateOrUpdateObjectCallCount() int {
-	fake.createOrUpdateObjectMutex.RLock()
-	defer fake.createOrUpdateObjectMutex.RUnlock()
-	return len(fake.createOrUpdateObjectArgsForCall)
-}
-
-func (fake *FakeCustomCtrlClient) CreateOrUpdateObjectCalls(stub func(context.Context, clienta.Object) error) {
-	fake.createOrUpdateObjectMutex.Lock()
-	defer fake.createOrUpdateObjectMutex.Unlock()
-	fake.CreateOrUpdateObjectStub = stub
-}
-
-func (fake *FakeCustomCtrlClient) CreateOrUpdateObjectArgsForCall(i int) (context.Context, clienta.Object) {
-	fake.createOrUpdateObjectMutex.RLock()
-	defer fake.createOrUpdateObjectMutex.RUnlock()
-	argsForCall := fake.createOrUpdateObjectArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
-}
-
-func (fake *FakeCustomCtrlClient) CreateOrUpdateObjectReturns(result1 error) {
-	fake.createOrUpdateObjectMutex.Lock()
-	defer fake.createOrUpdateObjectMutex.Unlock()
-	fake.CreateOrUpdateObjectStub = nil
-	fake.createOrUpdateObjectReturns = struct {
-		result1 error
-	}{result1}
-}
-
-func (fake *FakeCustomCtrlClient) CreateOrUpdateObjectReturnsOnCall(i int, result1 error) {
-	fake.createOrUpdateObjectMutex.Lock()
-	defer fake.createOrUpdateObjectMutex.Unlock()
-	fake.CreateOrUpdateObjectStub = nil
-	if fake.createOrUpdateObjectReturnsOnCall == nil {
-		fake.createOrUpdateObjectReturnsOnCall = make(map[int]struct {
-			result1 error
-		})
-	}
-	fake.createOrUpdateObjectReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
-}
-
 func (fake *FakeCustomCtrlClient) Delete(arg1 context.Context, arg2 clienta.Object, arg3 ...clienta.DeleteOption) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
@@ -357,6 +435,69 @@ func (fake *FakeCustomCtrlClient) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeCustomCtrlClient) DeleteAllOf(arg1 context.Context, arg2 clienta.Object, arg3 ...clienta.DeleteAllOfOption) error {
+	fake.deleteAllOfMutex.Lock()
+	ret, specificReturn := fake.deleteAllOfReturnsOnCall[len(fake.deleteAllOfArgsForCall)]
+	fake.deleteAllOfArgsForCall = append(fake.deleteAllOfArgsForCall, struct {
+		arg1 context.Context
+		arg2 clienta.Object
+		arg3 []clienta.DeleteAllOfOption
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteAllOfStub
+	fakeReturns := fake.deleteAllOfReturns
+	fake.recordInvocation("DeleteAllOf", []interface{}{arg1, arg2, arg3})
+	fake.deleteAllOfMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCustomCtrlClient) DeleteAllOfCallCount() int {
+	fake.deleteAllOfMutex.RLock()
+	defer fake.deleteAllOfMutex.RUnlock()
+	return len(fake.deleteAllOfArgsForCall)
+}
+
+func (fake *FakeCustomCtrlClient) DeleteAllOfCalls(stub func(context.Context, clienta.Object, ...clienta.DeleteAllOfOption) error) {
+	fake.deleteAllOfMutex.Lock()
+	defer fake.deleteAllOfMutex.Unlock()
+	fake.DeleteAllOfStub = stub
+}
+
+func (fake *FakeCustomCtrlClient) DeleteAllOfArgsForCall(i int) (context.Context, clienta.Object, []clienta.DeleteAllOfOption) {
+	fake.deleteAllOfMutex.RLock()
+	defer fake.deleteAllOfMutex.RUnlock()
+	argsForCall := fake.deleteAllOfArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCustomCtrlClient) DeleteAllOfReturns(result1 error) {
+	fake.deleteAllOfMutex.Lock()
+	defer fake.deleteAllOfMutex.Unlock()
+	fake.DeleteAllOfStub = nil
+	fake.deleteAllOfReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCustomCtrlClient) DeleteAllOfReturnsOnCall(i int, result1 error) {
+	fake.deleteAllOfMutex.Lock()
+	defer fake.deleteAllOfMutex.Unlock()
+	fake.DeleteAllOfStub = nil
+	if fake.deleteAllOfReturnsOnCall == nil {
+		fake.deleteAllOfReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteAllOfReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeCustomCtrlClient) Exists(arg1 context.Context, arg2 clienta.ObjectKey, arg3 clienta.Object) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
@@ -486,6 +627,59 @@ func (fake *FakeCustomCtrlClient) GetReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeCustomCtrlClient) GetClient() clienta.Client {
+	fake.getClientMutex.Lock()
+	ret, specificReturn := fake.getClientReturnsOnCall[len(fake.getClientArgsForCall)]
+	fake.getClientArgsForCall = append(fake.getClientArgsForCall, struct {
+	}{})
+	stub := fake.GetClientStub
+	fakeReturns := fake.getClientReturns
+	fake.recordInvocation("GetClient", []interface{}{})
+	fake.getClientMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeCustomCtrlClient) GetClientCallCount() int {
+	fake.getClientMutex.RLock()
+	defer fake.getClientMutex.RUnlock()
+	return len(fake.getClientArgsForCall)
+}
+
+func (fake *FakeCustomCtrlClient) GetClientCalls(stub func() clienta.Client) {
+	fake.getClientMutex.Lock()
+	defer fake.getClientMutex.Unlock()
+	fake.GetClientStub = stub
+}
+
+func (fake *FakeCustomCtrlClient) GetClientReturns(result1 clienta.Client) {
+	fake.getClientMutex.Lock()
+	defer fake.getClientMutex.Unlock()
+	fake.GetClientStub = nil
+	fake.getClientReturns = struct {
+		result1 clienta.Client
+	}{result1}
+}
+
+func (fake *FakeCustomCtrlClient) GetClientReturnsOnCall(i int, result1 clienta.Client) {
+	fake.getClientMutex.Lock()
+	defer fake.getClientMutex.Unlock()
+	fake.GetClientStub = nil
+	if fake.getClientReturnsOnCall == nil {
+		fake.getClientReturnsOnCall = make(map[int]struct {
+			result1 clienta.Client
+		})
+	}
+	fake.getClientReturnsOnCall[i] = struct {
+		result1 clienta.Client
+	}{result1}
+}
+
 func (fake *FakeCustomCtrlClient) List(arg1 context.Context, arg2 clienta.ObjectList, arg3 ...clienta.ListOption) error {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
@@ -868,18 +1062,22 @@ func (fake *FakeCustomCtrlClient) UpdateWithRetryReturnsOnCall(i int, result1 er
 func (fake *FakeCustomCtrlClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.aPIReaderMutex.RLock()
+	defer fake.aPIReaderMutex.RUnlock()
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
-	fake.createOrUpdateObjectMutex.RLock()
-	defer fake.createOrUpdateObjectMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.deleteAllOfMutex.RLock()
+	defer fake.deleteAllOfMutex.RUnlock()
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
-	fake.getClientMutex.RLock()
-	defer fake.getClientMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.getClientMutex.RLock()
+	defer fake.getClientMutex.RUnlock()
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	fake.patchMutex.RLock()
@@ -899,59 +1097,6 @@ func (fake *FakeCustomCtrlClient) Invocations() map[string][][]interface{} {
 	return copiedInvocations
 }
 
-func (fake *FakeCustomCtrlClient) GetClient() clienta.Client {
-	fake.getClientMutex.Lock()
-	ret, specificReturn := fake.getClientReturnsOnCall[len(fake.getClientArgsForCall)]
-	fake.getClientArgsForCall = append(fake.getClientArgsForCall, struct {
-	}{})
-	stub := fake.GetClientStub
-	fakeReturns := fake.getClientReturns
-	fake.recordInvocation("GetClient", []interface{}{})
-	fake.getClientMutex.Unlock()
-	if stub != nil {
-		return stub()
-	}
-	if specificReturn {
-		return ret.result1
-	}
-	return fakeReturns.result1
-}
-
-func (fake *FakeCustomCtrlClient) GetClientCallCount() int {
-	fake.getClientMutex.RLock()
-	defer fake.getClientMutex.RUnlock()
-	return len(fake.getClientArgsForCall)
-}
-
-func (fake *FakeCustomCtrlClient) GetClientCalls(stub func() clienta.Client) {
-	fake.getClientMutex.Lock()
-	defer fake.getClientMutex.Unlock()
-	fake.GetClientStub = stub
-}
-
-func (fake *FakeCustomCtrlClient) GetClientReturns(result1 clienta.Client) {
-	fake.getClientMutex.Lock()
-	defer fake.getClientMutex.Unlock()
-	fake.GetClientStub = nil
-	fake.getClientReturns = struct {
-		result1 clienta.Client
-	}{result1}
-}
-
-func (fake *FakeCustomCtrlClient) GetClientReturnsOnCall(i int, result1 clienta.Client) {
-	fake.getClientMutex.Lock()
-	defer fake.getClientMutex.Unlock()
-	fake.GetClientStub = nil
-	if fake.getClientReturnsOnCall == nil {
-		fake.getClientReturnsOnCall = make(map[int]struct {
-			result1 clienta.Client
-		})
-	}
-	fake.getClientReturnsOnCall[i] = struct {
-		result1 clienta.Client
-	}{result1}
-}
-
 func (fake *FakeCustomCtrlClient) recordInvocation(key string, args []interface{}) {
 	fake.invocationsMutex.Lock()
 	defer fake.invocationsMutex.Unlock()
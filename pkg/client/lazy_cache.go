@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithLazyInformers starts the informer of each cached kind on its first read instead of at startup, except
+// for the kinds watched by the controllers, which the manager starts before reconciling. A read waits at most
+// syncTimeout for the informer of its kind to sync, so that a kind the operator can't list, e.g. because of
+// a missing RBAC rule, only fails the reconciles reading it instead of the operator startup. Without this
+// option, the informers of every cached kind are registered when the cache is built, and the reads of the
+// other kinds fail with ErrResourceNotCached.
+func WithLazyInformers(syncTimeout time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.lazyInformers = true
+		c.informerSyncTimeout = syncTimeout
+	}
+}
+
+// lazyCache bounds the reads of the wrapped cache, which block until the informer of their kind is synced
+type lazyCache struct {
+	cache.Cache
+	syncTimeout time.Duration
+}
+
+func (c *lazyCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if c.syncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.syncTimeout)
+		defer cancel()
+	}
+	return c.Cache.Get(ctx, key, obj, opts...)
+}
+
+func (c *lazyCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if c.syncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.syncTimeout)
+		defer cancel()
+	}
+	return c.Cache.List(ctx, list, opts...)
+}
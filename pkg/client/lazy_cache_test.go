@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	operatorv1 "github.com/operator-framework/api/pkg/operators/v1"
+	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// unsyncedCache blocks the reads like a cache whose informer never syncs
+type unsyncedCache struct {
+	cache.Cache
+}
+
+func (c *unsyncedCache) Get(ctx context.Context, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *unsyncedCache) List(ctx context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestLazyCacheReadsTimeOut(t *testing.T) {
+	c := &lazyCache{Cache: &unsyncedCache{}, syncTimeout: 10 * time.Millisecond}
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "test"}, &corev1.ConfigMap{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the get to time out, got %v", err)
+	}
+	if err := c.List(context.Background(), &corev1.ConfigMapList{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the list to time out, got %v", err)
+	}
+}
+
+// namespacedRESTMapper maps every kind to a namespaced resource, without discovery
+type namespacedRESTMapper struct {
+	meta.RESTMapper
+}
+
+func (m namespacedRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	gvk := gk.WithVersion(versions[0])
+	return &meta.RESTMapping{
+		Resource:         gvk.GroupVersion().WithResource(strings.ToLower(gk.Kind) + "s"),
+		GroupVersionKind: gvk,
+		Scope:            meta.RESTScopeNamespace,
+	}, nil
+}
+
+func TestNewCacheBuilderWithLazyInformers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme, v1alpha1.AddToScheme, routev1.AddToScheme, spiffev1alpha1.AddToScheme, operatorv1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	newCache := func(cacheOpts ...CacheOption) cache.Cache {
+		t.Helper()
+		builder, err := NewCacheBuilder(cacheOpts...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		built, err := builder(&rest.Config{Host: "http://127.0.0.1:1"}, cache.Options{Scheme: scheme, Mapper: namespacedRESTMapper{}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return built
+	}
+	key := client.ObjectKey{Name: "test", Namespace: "test"}
+
+	// Without lazy informers, the kinds without informer are not read
+	var notCached *cache.ErrResourceNotCached
+	if err := newCache().Get(context.Background(), key, &corev1.Secret{}); !errors.As(err, &notCached) {
+		t.Errorf("Expected ErrResourceNotCached, got %v", err)
+	}
+
+	// With lazy informers, the informer of the kind is started on its first read
+	lazy := newCache(WithLazyInformers(time.Minute))
+	if c, ok := lazy.(*lazyCache); !ok || c.syncTimeout != time.Minute {
+		t.Fatalf("Expected a lazy cache with the sync timeout, got %#v", lazy)
+	}
+	var notStarted *cache.ErrCacheNotStarted
+	if err := lazy.Get(context.Background(), key, &corev1.Secret{}); !errors.As(err, &notStarted) {
+		t.Errorf("Expected the informer to be added to the cache not started yet, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// requestDuration complements the rest_client_request_duration_seconds of client-go with the kind of the
+	// resources. Get and List of the cached resources are served by the cache and don't reach the API server.
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ztwim_client_request_duration_seconds",
+		Help:    "Latency of the requests of the operator client, by verb and kind.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"verb", "kind"})
+
+	requestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ztwim_client_request_errors_total",
+		Help: "Failed requests of the operator client, by verb, kind and reason, e.g. Conflict, NotFound or Timeout.",
+	}, []string{"verb", "kind", "reason"})
+
+	conflictRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ztwim_client_conflict_retries_total",
+		Help: "Retries of the writes of UpdateWithRetry and StatusUpdateWithRetry after a conflict, by verb and kind.",
+	}, []string{"verb", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestDuration, requestErrors, conflictRetries)
+}
+
+// instrumentedClient records the latency and the errors of the requests of the wrapped client
+type instrumentedClient struct {
+	client.Client
+}
+
+func newInstrumentedClient(c client.Client) client.Client {
+	return &instrumentedClient{Client: c}
+}
+
+func (c *instrumentedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	defer observeRequest("get", c.kindOf(obj), time.Now(), &err)
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *instrumentedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	defer observeRequest("list", c.kindOf(list), time.Now(), &err)
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *instrumentedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	defer observeRequest("create", c.kindOf(obj), time.Now(), &err)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) (err error) {
+	defer observeRequest("delete", c.kindOf(obj), time.Now(), &err)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) (err error) {
+	defer observeRequest("deletecollection", c.kindOf(obj), time.Now(), &err)
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) (err error) {
+	defer observeRequest("update", c.kindOf(obj), time.Now(), &err)
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *instrumentedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	defer observeRequest(patchVerb(patch), c.kindOf(obj), time.Now(), &err)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *instrumentedClient) Status() client.SubResourceWriter {
+	return &instrumentedStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+// instrumentedStatusWriter records the requests of the status subresource, with the verbs suffixed by "_status"
+type instrumentedStatusWriter struct {
+	client.SubResourceWriter
+	client *instrumentedClient
+}
+
+func (w *instrumentedStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	defer observeRequest("update_status", w.client.kindOf(obj), time.Now(), &err)
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *instrumentedStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) (err error) {
+	defer observeRequest(patchVerb(patch)+"_status", w.client.kindOf(obj), time.Now(), &err)
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// kindOf returns the kind of obj, or of the items of a list
+func (c *instrumentedClient) kindOf(obj runtime.Object) string {
+	return kindOf(obj, c.Scheme())
+}
+
+// kindOf returns the kind of obj, or of the items of a list, falling back to its Go type when it is not
+// registered in the scheme
+func kindOf(obj runtime.Object, scheme *runtime.Scheme) string {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	if _, isList := obj.(client.ObjectList); isList {
+		return strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return gvk.Kind
+}
+
+// patchVerb returns "apply" for server-side apply patches, and "patch" otherwise
+func patchVerb(patch client.Patch) string {
+	if patch.Type() == client.Apply.Type() {
+		return "apply"
+	}
+	return "patch"
+}
+
+// observeRequest records the latency of a request started at start, and its error when *err is set
+func observeRequest(verb, kind string, start time.Time, err *error) {
+	requestDuration.WithLabelValues(verb, kind).Observe(time.Since(start).Seconds())
+	if *err == nil {
+		return
+	}
+	reason := string(kerrors.ReasonForError(*err))
+	switch {
+	case errors.Is(*err, context.DeadlineExceeded):
+		reason = string(metav1.StatusReasonTimeout)
+	case reason == "":
+		reason = "Unknown"
+	}
+	requestErrors.WithLabelValues(verb, kind, reason).Inc()
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := counter.Write(&metric); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := observer.(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	conflicts := 2
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "test"}}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), nil)
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	c := &customCtrlClientImpl{
+		Client:       newInstrumentedClient(fakeClient),
+		retryBackoff: wait.Backoff{Steps: 5},
+	}
+
+	gets := histogramCount(t, requestDuration.WithLabelValues("get", "ConfigMap"))
+	notFound := counterValue(t, requestErrors.WithLabelValues("get", "ConfigMap", "NotFound"))
+	updateConflicts := counterValue(t, requestErrors.WithLabelValues("update", "ConfigMap", "Conflict"))
+	retries := counterValue(t, conflictRetries.WithLabelValues("update", "ConfigMap"))
+
+	if exists, err := c.Exists(context.Background(), client.ObjectKey{Name: "missing", Namespace: "test"}, &corev1.ConfigMap{}); err != nil || exists {
+		t.Fatalf("Expected the ConfigMap not to exist, got %v, %v", exists, err)
+	}
+	if err := c.UpdateWithRetry(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "test"},
+		Data:       map[string]string{"key": "value"},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Exists and the three attempts of UpdateWithRetry each get the ConfigMap
+	if got := histogramCount(t, requestDuration.WithLabelValues("get", "ConfigMap")) - gets; got != 4 {
+		t.Errorf("Expected 4 observed gets, got %d", got)
+	}
+	if got := counterValue(t, requestErrors.WithLabelValues("get", "ConfigMap", "NotFound")) - notFound; got != 1 {
+		t.Errorf("Expected 1 NotFound error, got %v", got)
+	}
+	if got := counterValue(t, requestErrors.WithLabelValues("update", "ConfigMap", "Conflict")) - updateConflicts; got != 2 {
+		t.Errorf("Expected 2 Conflict errors, got %v", got)
+	}
+	if got := counterValue(t, conflictRetries.WithLabelValues("update", "ConfigMap")) - retries; got != 2 {
+		t.Errorf("Expected 2 conflict retries, got %v", got)
+	}
+}
+
+func TestPatchVerb(t *testing.T) {
+	if verb := patchVerb(client.Apply); verb != "apply" {
+		t.Errorf("Expected apply, got %s", verb)
+	}
+	if verb := patchVerb(client.MergeFrom(&corev1.ConfigMap{})); verb != "patch" {
+		t.Errorf("Expected patch, got %s", verb)
+	}
+}
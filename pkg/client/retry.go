@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RetryExhaustedRequeueInterval is how long callers wait before trying again once the
+// conflict retries of a write are exhausted
+const RetryExhaustedRequeueInterval = 30 * time.Second
+
+// Option configures the client returned by NewCustomClient
+type Option func(*customCtrlClientImpl)
+
+// WithRetryBackoff sets the backoff used by UpdateWithRetry and StatusUpdateWithRetry
+// when the API server reports a conflict. It defaults to retry.DefaultRetry.
+func WithRetryBackoff(backoff wait.Backoff) Option {
+	return func(c *customCtrlClientImpl) {
+		c.retryBackoff = backoff
+	}
+}
+
+// RetryExhaustedError is returned when a write still conflicts after every retry of the backoff
+type RetryExhaustedError struct {
+	Key      types.NamespacedName
+	Attempts int
+	Err      error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("conflict retries exhausted for %q after %d attempt(s): %v", e.Key, e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryExhausted reports whether err is, or wraps, a RetryExhaustedError
+func IsRetryExhausted(err error) bool {
+	var exhausted *RetryExhaustedError
+	return errors.As(err, &exhausted)
+}
+
+// retryOnConflict runs fn with the client backoff, converting a conflict that outlasts
+// the backoff into a RetryExhaustedError. The retries are counted by verb and kind of obj.
+func (c *customCtrlClientImpl) retryOnConflict(key types.NamespacedName, verb string, obj client.Object, fn func() error) error {
+	attempts := 0
+	err := retry.RetryOnConflict(c.retryBackoff, func() error {
+		attempts++
+		if attempts > 1 {
+			conflictRetries.WithLabelValues(verb, kindOf(obj, c.Client.Scheme())).Inc()
+		}
+		return fn()
+	})
+	if err != nil && kerrors.IsConflict(err) {
+		return &RetryExhaustedError{Key: key, Attempts: attempts, Err: err}
+	}
+	return err
+}
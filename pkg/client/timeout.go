@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithRequestTimeout bounds each request of the client, including each attempt of UpdateWithRetry and
+// StatusUpdateWithRetry, so that a hung API server or admission webhook fails the reconcile, which is then
+// retried with backoff, instead of blocking its worker. A timeout of zero leaves the requests unbounded.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *customCtrlClientImpl) {
+		c.requestTimeout = timeout
+	}
+}
+
+// timeoutClient runs the requests of the wrapped client with a context derived with the timeout
+type timeoutClient struct {
+	client.Client
+	timeout time.Duration
+}
+
+func newTimeoutClient(c client.Client, timeout time.Duration) client.Client {
+	return &timeoutClient{Client: c, timeout: timeout}
+}
+
+func (c *timeoutClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *timeoutClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *timeoutClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *timeoutClient) Status() client.SubResourceWriter {
+	return &timeoutStatusWriter{SubResourceWriter: c.Client.Status(), timeout: c.timeout}
+}
+
+// timeoutStatusWriter runs the requests of the status subresource with a context derived with the timeout
+type timeoutStatusWriter struct {
+	client.SubResourceWriter
+	timeout time.Duration
+}
+
+func (w *timeoutStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *timeoutStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// timeoutReader runs the reads of the wrapped reader with a context derived with the timeout
+type timeoutReader struct {
+	client.Reader
+	timeout time.Duration
+}
+
+func (r *timeoutReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func (r *timeoutReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.Reader.List(ctx, list, opts...)
+}
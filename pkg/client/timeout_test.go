@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestTimeoutClient(t *testing.T) {
+	// The hung API server only answers once the request is canceled
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+				return hang(ctx)
+			},
+			SubResourceUpdate: func(ctx context.Context, _ client.Client, _ string, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+				return hang(ctx)
+			},
+		}).
+		Build()
+	c := newTimeoutClient(fakeClient, 10*time.Millisecond)
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "timeout", Namespace: "test"}}
+
+	if err := c.Create(context.Background(), configMap); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the create to time out, got %v", err)
+	}
+	if err := c.Status().Update(context.Background(), configMap); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the status update to time out, got %v", err)
+	}
+
+	// The timeout never extends the deadline of the caller
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Create(ctx, configMap); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the create to be canceled, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package spiffe_csi_driver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -19,9 +20,12 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 
@@ -30,6 +34,8 @@ import (
 	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/operatorconfig"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 const (
@@ -46,11 +52,14 @@ type SpiffeCsiReconciler struct {
 	eventRecorder record.EventRecorder
 	log           logr.Logger
 	scheme        *runtime.Scheme
+
+	// failures counts the consecutive failed reconciles, to report the CRs that keep failing as Degraded
+	failures *utils.FailureTracker
 }
 
 // New returns a new Reconciler instance.
-func New(mgr ctrl.Manager) (*SpiffeCsiReconciler, error) {
-	c, err := customClient.NewCustomClient(mgr)
+func New(mgr ctrl.Manager, clientOpts ...customClient.Option) (*SpiffeCsiReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -60,10 +69,11 @@ func New(mgr ctrl.Manager) (*SpiffeCsiReconciler, error) {
 		eventRecorder: mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName),
 		log:           ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName),
 		scheme:        mgr.GetScheme(),
+		failures:      utils.NewFailureTracker(),
 	}, nil
 }
 
-func (r *SpiffeCsiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *SpiffeCsiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	r.log.Info(fmt.Sprintf("reconciling %s", utils.ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName))
 	var spiffeCSIDriver v1alpha1.SpiffeCSIDriver
 	if err := r.ctrlClient.Get(ctx, req.NamespacedName, &spiffeCSIDriver); err != nil {
@@ -74,13 +84,42 @@ func (r *SpiffeCsiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Tear down the managed resources in order when the CR is being deleted. The operand namespace
+	// is resolved first, as the operator may have restarted since the namespace was last configured.
+	if !spiffeCSIDriver.DeletionTimestamp.IsZero() {
+		namespace, err := utils.ResolveOperandNamespace(ctx, r.ctrlClient, spiffeCSIDriver.Status.ManagedResources)
+		if err != nil {
+			r.log.Error(err, "failed to resolve the operand namespace")
+			return ctrl.Result{}, err
+		}
+		return r.reconcileDeletion(ctx, &spiffeCSIDriver, namespace)
+	}
+
+	// Leave the managed resources untouched while reconciliation is paused
+	if utils.StringToBool(spiffeCSIDriver.Spec.Paused) {
+		r.log.Info("SpiffeCSIDriver reconciliation is paused, skipping", "name", spiffeCSIDriver.Name)
+		if err := status.SetPausedStatus(ctx, r.ctrlClient, &spiffeCSIDriver, func() *v1alpha1.ConditionalStatus {
+			return &spiffeCSIDriver.Status.ConditionalStatus
+		}); err != nil {
+			r.log.Error(err, "failed to update status")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Set Ready to false at the start of reconciliation
 	status.SetInitialReconciliationStatus(ctx, r.ctrlClient, &spiffeCSIDriver, func() *v1alpha1.ConditionalStatus {
 		return &spiffeCSIDriver.Status.ConditionalStatus
 	}, "SpiffeCSIDriver")
 
+	var dryRun *customClient.DryRunClient
+	var adoption *customClient.AdoptionClient
+	var exclusion *customClient.ExclusionClient
 	statusMgr := status.NewManager(r.ctrlClient)
 	defer func() {
+		statusMgr.SetDryRunStatus(r.eventRecorder, &spiffeCSIDriver, spiffeCSIDriver.Status.ConditionalStatus.Conditions, dryRun)
+		statusMgr.SetExcludedResourcesStatus(spiffeCSIDriver.Status.ConditionalStatus.Conditions, exclusion)
+		statusMgr.SetDegradedCondition(spiffeCSIDriver.Status.ConditionalStatus.Conditions, r.failures.Observe(req.NamespacedName, reconcileErr), reconcileErr)
+		statusMgr.RecordResourcesAdopted(adoption)
 		if err := statusMgr.ApplyStatus(ctx, &spiffeCSIDriver, func() *v1alpha1.ConditionalStatus {
 			return &spiffeCSIDriver.Status.ConditionalStatus
 		}); err != nil {
@@ -100,6 +139,22 @@ func (r *SpiffeCsiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Install the operands in the namespace configured on the ZTWIM
+	if err := utils.ConfigureOperandNamespace(ztwim.Spec.OperandNamespace); err != nil {
+		r.log.Error(err, "invalid operand namespace")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed, err.Error(), metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
+	// Leave the operand to the operator running in the other cluster of the topology
+	if !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpiffeCSIDriver) {
+		r.log.Info("SpiffeCSIDriver is not deployed in this cluster, skipping", "topology", utils.GetTopologyMode(&ztwim))
+		statusMgr.AddCondition(v1alpha1.Ready, utils.ConditionReasonNotDeployedInTopology,
+			fmt.Sprintf("SpiffeCSIDriver is not deployed in the %s topology", utils.GetTopologyMode(&ztwim)),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Set ZTWIM as the owner of SpiffeCSIDriver only if needed
 	if utils.NeedsOwnerReferenceUpdate(&spiffeCSIDriver, &ztwim) {
 		if err := controllerutil.SetControllerReference(&ztwim, &spiffeCSIDriver, r.scheme); err != nil {
@@ -120,14 +175,81 @@ func (r *SpiffeCsiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
+	// Add the cleanup finalizer so that deleting the CR tears down the managed resources in order
+	if controllerutil.AddFinalizer(&spiffeCSIDriver, utils.OperandCleanupFinalizer) {
+		if err := r.ctrlClient.Update(ctx, &spiffeCSIDriver); err != nil {
+			r.log.Error(err, "failed to add finalizer to SpiffeCSIDriver")
+			statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+				fmt.Sprintf("Failed to add finalizer to SpiffeCSIDriver: %v", err),
+				metav1.ConditionFalse)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In dry-run mode, record the writes made below as planned changes instead of applying them
+	if utils.IsDryRunMode(spiffeCSIDriver.Spec.ReconcileMode) {
+		r.log.Info("Running in dry-run mode - planned changes are reported in status and not applied")
+		dryRun = customClient.NewDryRunClient(r.ctrlClient, r.scheme)
+		dryRunReconciler := *r
+		dryRunReconciler.ctrlClient = dryRun
+		r = &dryRunReconciler
+	}
+
+	// Adopt the resources of the same name created by another installer, e.g. the SPIRE Helm charts
+	if utils.StringToBool(spiffeCSIDriver.Spec.AdoptExistingResources) {
+		adoption = customClient.NewAdoptionClient(r.ctrlClient)
+		adoptionReconciler := *r
+		adoptionReconciler.ctrlClient = adoption
+		r = &adoptionReconciler
+	}
+
+	// Leave the resources excluded from management to their owner
+	if len(spiffeCSIDriver.Spec.ExcludedResources) > 0 {
+		exclusion = customClient.NewExclusionClient(r.ctrlClient, r.scheme, spiffeCSIDriver.Spec.ExcludedResources)
+		exclusionReconciler := *r
+		exclusionReconciler.ctrlClient = exclusion
+		r = &exclusionReconciler
+	}
+
+	// Explain the significant reconcile actions in events on the CR
+	statusMgr.SetEventRecorder(r.eventRecorder, &spiffeCSIDriver, dryRun != nil)
+
+	// Leave the managed resources as they are or delete them as set by the management state
+	managementState := utils.GetManagementState(spiffeCSIDriver.Spec.ManagementState, &ztwim)
+	statusMgr.SetManagementStateCondition(managementState, "SpiffeCSIDriver", spiffeCSIDriver.Status.Conditions)
+	switch managementState {
+	case v1alpha1.ManagementStateUnmanaged:
+		r.log.Info("SpiffeCSIDriver is unmanaged, skipping", "name", spiffeCSIDriver.Name)
+		return ctrl.Result{}, nil
+	case v1alpha1.ManagementStateRemoved:
+		return ctrl.Result{}, r.reconcileRemoval(ctx, &spiffeCSIDriver, utils.OperandNamespaceOf(&ztwim), statusMgr)
+	}
+
 	// Handle create-only mode
 	createOnlyMode := r.handleCreateOnlyMode(&spiffeCSIDriver, statusMgr)
 
+	// Wait for the cluster to pass the preflight checks of the ZeroTrustWorkloadIdentityManager before the first deployment
+	if message, blocked := utils.PreflightChecksBlockDeployment(&ztwim, spiffeCSIDriver.Status.Conditions, DaemonSetAvailable); blocked {
+		r.log.Info("Waiting for the preflight checks to pass before deploying SpiffeCSIDriver", "message", message)
+		statusMgr.AddCondition(v1alpha1.Ready, utils.PreflightChecksFailed,
+			fmt.Sprintf("Waiting for the preflight checks of the ZeroTrustWorkloadIdentityManager: %s", message),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Validate common configuration
 	if err := r.validateCommonConfig(&spiffeCSIDriver, statusMgr); err != nil {
 		return ctrl.Result{}, nil
 	}
 
+	// The CSI driver mounts the Workload API socket directory of the SPIRE agents into the workloads
+	if err := r.checkAgentSocketPath(ctx, &spiffeCSIDriver, statusMgr); err != nil {
+		if errors.Is(err, errAgentSocketPathMismatch) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile static resources (ServiceAccount, CSI Driver)
 	if err := r.reconcileServiceAccount(ctx, &spiffeCSIDriver, statusMgr, createOnlyMode); err != nil {
 		return ctrl.Result{}, err
@@ -143,11 +265,22 @@ func (r *SpiffeCsiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// Reconcile DaemonSet
-	if err := r.reconcileDaemonSet(ctx, &spiffeCSIDriver, statusMgr, createOnlyMode); err != nil {
+	if err := r.reconcileDaemonSet(ctx, &spiffeCSIDriver, statusMgr, &ztwim, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Prune resources from the previous inventory that the current spec no longer generates
+	pruned, err := statusMgr.PruneOrphanedResources(ctx, r.ctrlClient, r.scheme, spiffeCSIDriver.Status.ManagedResources, createOnlyMode, dryRun != nil)
+	if err != nil {
+		r.log.Error(err, "failed to prune orphaned resources")
 		return ctrl.Result{}, err
 	}
+	for _, resource := range pruned {
+		r.log.Info("Pruned orphaned resource", "kind", resource.Kind, "namespace", resource.Namespace, "name", resource.Name)
+	}
 
-	return ctrl.Result{}, nil
+	// Requeue periodically so that drift from the desired state is repaired
+	return ctrl.Result{RequeueAfter: utils.ResyncInterval(spiffeCSIDriver.Spec.ResyncInterval, ztwim.Spec.ResyncInterval)}, nil
 }
 
 func (r *SpiffeCsiReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -168,12 +301,18 @@ func (r *SpiffeCsiReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.SpiffeCSIDriver{}, builder.WithPredicates(utils.GenerationOrOwnerReferenceChangedPredicate)).
 		Named(utils.ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: utils.MaxConcurrentReconciles(utils.ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName),
+			RateLimiter:             utils.NewRateLimiter(),
+		}).
 		Watches(&appsv1.DaemonSet{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&storagev1.CSIDriver{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&securityv1.SecurityContextConstraints{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&v1alpha1.SpireAgent{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&v1alpha1.ZeroTrustWorkloadIdentityManager{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(utils.ZTWIMSpecChangedPredicate)).
-		Complete(r)
+		WatchesRawSource(source.Channel(operatorconfig.Subscribe(), handler.EnqueueRequestsFromMapFunc(mapFunc))).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName, r))
 	if err != nil {
 		return err
 	}
@@ -199,8 +338,97 @@ func (r *SpiffeCsiReconciler) handleCreateOnlyMode(driver *v1alpha1.SpiffeCSIDri
 	return createOnlyMode
 }
 
-// validateCommonConfig validates common configuration fields (affinity, tolerations, nodeSelector, resources, labels)
+// errAgentSocketPathMismatch is returned when the agentSocketPath of the SpiffeCSIDriver differs from the socketPath of the SpireAgent
+var errAgentSocketPathMismatch = errors.New("agentSocketPath does not match the socketPath of the SpireAgent")
+
+// checkAgentSocketPath reports an agentSocketPath different from the socketPath of the SpireAgent in the
+// ConfigurationValid condition, the workloads would not find the Workload API socket
+func (r *SpiffeCsiReconciler) checkAgentSocketPath(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, statusMgr *status.Manager) error {
+	var agent v1alpha1.SpireAgent
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, &agent); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		r.log.Error(err, "failed to get SpireAgent")
+		return err
+	}
+	if utils.AgentSocketPathsMatch(driver.Spec.AgentSocketPath, agent.Spec.SocketPath) {
+		return nil
+	}
+	r.log.Error(errAgentSocketPathMismatch, "Inconsistent agent socket path in SpiffeCSIDriver configuration",
+		"agentSocketPath", driver.Spec.AgentSocketPath, "spireAgentSocketPath", agent.Spec.SocketPath)
+	statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, "AgentSocketPathMismatch",
+		fmt.Sprintf("agentSocketPath %q does not match the socketPath %q of the SpireAgent, workloads would not find the Workload API socket",
+			driver.Spec.AgentSocketPath, agent.Spec.SocketPath),
+		metav1.ConditionFalse)
+	return errAgentSocketPathMismatch
+}
+
+// validateCommonConfig validates common configuration fields (affinity, tolerations, nodeSelector, resources, labels, extra volumes, env, security context, image pull secrets, ServiceAccount annotations, node platform)
 func (r *SpiffeCsiReconciler) validateCommonConfig(driver *v1alpha1.SpiffeCSIDriver, statusMgr *status.Manager) error {
+	// Validate the extra volumes mounted into the SPIFFE CSI driver pods
+	if err := utils.ValidateExtraVolumes(driver.Spec.ExtraVolumes, driver.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "Invalid extra volumes in SpiffeCSIDriver configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraVolumes,
+			fmt.Sprintf("Extra volumes validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the environment variables set in the SPIFFE CSI driver container
+	if err := utils.ValidateContainerEnv(driver.Spec.Env, driver.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "Invalid environment variables in SpiffeCSIDriver configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidEnv,
+			fmt.Sprintf("Environment variables validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the security context overrides of the SPIFFE CSI driver pods, which access host paths
+	if err := utils.ValidateSecurityContext(driver.Spec.SecurityContext, driver.Spec.PodSecurityContext, true); err != nil {
+		r.log.Error(err, "Invalid security context in SpiffeCSIDriver configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidSecurityContext,
+			fmt.Sprintf("Security context validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the image pull secrets of the SPIFFE CSI driver pods
+	if err := utils.ValidateImagePullSecrets(driver.Spec.ImagePullSecrets); err != nil {
+		r.log.Error(err, "Invalid image pull secrets in SpiffeCSIDriver configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidImagePullSecrets,
+			fmt.Sprintf("Image pull secrets validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the annotations of the SPIFFE CSI driver ServiceAccount
+	if err := utils.ValidateServiceAccountAnnotations(driver.Spec.ServiceAccountAnnotations); err != nil {
+		r.log.Error(err, "Invalid ServiceAccount annotations in SpiffeCSIDriver configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidServiceAccountAnnotations,
+			fmt.Sprintf("ServiceAccount annotations validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the node platform of the SPIFFE CSI driver pods against the operand images and their node selector
+	if err := utils.ValidateNodePlatform(driver.Spec.NodeSelector, driver.Spec.NodePlatform); err != nil {
+		r.log.Error(err, "Invalid node platform in SpiffeCSIDriver configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidNodePlatform,
+			fmt.Sprintf("Node platform validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Refuse to fall back to the non-FIPS images when running in FIPS mode
+	if err := utils.ValidateFIPSImages(utils.SpiffeCSIDriverFIPSImageEnv, utils.NodeDriverRegistrarFIPSImageEnv, utils.SpiffeCSIInitContainerFIPSImageEnv); err != nil {
+		r.log.Error(err, "FIPS images of the SPIFFE CSI driver are not set")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonFIPSImageNotSet,
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	return utils.ValidateAndUpdateStatus(
 		r.log,
 		statusMgr,
@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
+	securityv1 "github.com/openshift/api/security/v1"
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,6 +24,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // newTestReconciler creates a reconciler for testing
@@ -339,6 +346,77 @@ func TestValidateCommonConfig_InvalidAffinity(t *testing.T) {
 	}
 }
 
+// TestCheckAgentSocketPath tests that the agentSocketPath must match the socketPath of the SpireAgent
+func TestCheckAgentSocketPath(t *testing.T) {
+	tests := []struct {
+		name            string
+		agentSocketPath string
+		agentGetErr     error
+		spireSocketPath string
+		expectedErr     error
+	}{
+		{
+			name:            "SpireAgent not found",
+			agentSocketPath: "/run/spire/custom",
+			agentGetErr:     kerrors.NewNotFound(schema.GroupResource{Resource: "spireagents"}, "cluster"),
+		},
+		{
+			name: "defaults",
+		},
+		{
+			name:            "matching paths",
+			agentSocketPath: "/run/spire/custom/",
+			spireSocketPath: "/run/spire/custom",
+		},
+		{
+			name:            "default path set explicitly",
+			agentSocketPath: utils.DefaultAgentSocketPath,
+		},
+		{
+			name:            "mismatching paths",
+			agentSocketPath: "/run/spire/custom",
+			spireSocketPath: "/run/spire/other",
+			expectedErr:     errAgentSocketPathMismatch,
+		},
+		{
+			name:        "SpireAgent get error",
+			agentGetErr: errors.New("connection refused"),
+			expectedErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				if tt.agentGetErr != nil {
+					return tt.agentGetErr
+				}
+				if agent, ok := obj.(*v1alpha1.SpireAgent); ok {
+					agent.Spec.SocketPath = tt.spireSocketPath
+				}
+				return nil
+			}
+			reconciler := newTestReconciler(fakeClient)
+			driver := &v1alpha1.SpiffeCSIDriver{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       v1alpha1.SpiffeCSIDriverSpec{AgentSocketPath: tt.agentSocketPath},
+			}
+
+			err := reconciler.checkAgentSocketPath(context.Background(), driver, status.NewManager(fakeClient))
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedErr.Error() {
+				t.Errorf("Expected error %v, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
 // TestHandleCreateOnlyMode_NotSet tests create-only mode when env var is not set
 func TestHandleCreateOnlyMode_NotSet(t *testing.T) {
 	t.Setenv("CREATE_ONLY_MODE", "")
@@ -1491,3 +1569,347 @@ func TestReconcile_DaemonSetError_MutationKiller(t *testing.T) {
 		t.Errorf("Expected RequeueAfter=0 when error returned, got %v", result.RequeueAfter)
 	}
 }
+
+// TestReconcile_DryRunMode tests that a dry-run reconciliation reports planned changes without writing them
+func TestReconcile_DryRunMode(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = storagev1.AddToScheme(scheme)
+	_ = securityv1.AddToScheme(scheme)
+
+	reconciler := newTestReconciler(fakeClient)
+	reconciler.scheme = scheme
+
+	csiDriver := &v1alpha1.SpiffeCSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.SpiffeCSIDriverSpec{
+			CommonConfig: v1alpha1.CommonConfig{ReconcileMode: v1alpha1.ReconcileModeDryRun},
+		},
+	}
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		TypeMeta:   metav1.TypeMeta{Kind: "ZeroTrustWorkloadIdentityManager"},
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	// Adopt the CR up front so that the only writes left are the operand resources
+	if err := controllerutil.SetControllerReference(ztwim, csiDriver, scheme); err != nil {
+		t.Fatalf("SetControllerReference() error = %v", err)
+	}
+	controllerutil.AddFinalizer(csiDriver, utils.OperandCleanupFinalizer)
+
+	fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		switch v := obj.(type) {
+		case *v1alpha1.SpiffeCSIDriver:
+			*v = *csiDriver
+			return nil
+		case *v1alpha1.ZeroTrustWorkloadIdentityManager:
+			*v = *ztwim
+			return nil
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 || fakeClient.DeleteCallCount() != 0 {
+		t.Errorf("Expected no writes in dry-run mode, got %d creates, %d updates, %d deletes",
+			fakeClient.CreateCallCount(), fakeClient.UpdateCallCount(), fakeClient.DeleteCallCount())
+	}
+
+	calls := fakeClient.StatusUpdateWithRetryCallCount()
+	if calls == 0 {
+		t.Fatal("Expected the status to be updated")
+	}
+	_, obj, _ := fakeClient.StatusUpdateWithRetryArgsForCall(calls - 1)
+	planned := obj.(*v1alpha1.SpiffeCSIDriver).Status.PlannedChanges
+	kinds := map[string]bool{}
+	for _, change := range planned {
+		if change.Action != "Create" {
+			t.Errorf("Expected only creates for an empty cluster, got %+v", change)
+		}
+		kinds[change.Kind] = true
+	}
+	for _, kind := range []string{"ServiceAccount", "CSIDriver", "SecurityContextConstraints", "DaemonSet"} {
+		if !kinds[kind] {
+			t.Errorf("Expected a planned %s creation, got %+v", kind, planned)
+		}
+	}
+}
+
+// TestReconcile_ResyncInterval tests that a successful reconciliation requeues after the configured resync interval
+func TestReconcile_ResyncInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *metav1.Duration
+		global   *metav1.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "no interval configured",
+			expected: 0,
+		},
+		{
+			name:     "ztwim interval is used",
+			global:   &metav1.Duration{Duration: 30 * time.Minute},
+			expected: 30 * time.Minute,
+		},
+		{
+			name:     "operand override takes precedence",
+			override: &metav1.Duration{Duration: 5 * time.Minute},
+			global:   &metav1.Duration{Duration: 30 * time.Minute},
+			expected: 5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+			_ = corev1.AddToScheme(scheme)
+			_ = appsv1.AddToScheme(scheme)
+			_ = storagev1.AddToScheme(scheme)
+			_ = securityv1.AddToScheme(scheme)
+
+			reconciler := newTestReconciler(fakeClient)
+			reconciler.scheme = scheme
+
+			// Dry-run keeps the reconciliation free of writes, so only the result is checked
+			csiDriver := &v1alpha1.SpiffeCSIDriver{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: v1alpha1.SpiffeCSIDriverSpec{
+					CommonConfig: v1alpha1.CommonConfig{
+						ReconcileMode:  v1alpha1.ReconcileModeDryRun,
+						ResyncInterval: tt.override,
+					},
+				},
+			}
+			ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+				TypeMeta:   metav1.TypeMeta{Kind: "ZeroTrustWorkloadIdentityManager"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+				Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+					TrustDomain:    "example.org",
+					ResyncInterval: tt.global,
+				},
+			}
+			if err := controllerutil.SetControllerReference(ztwim, csiDriver, scheme); err != nil {
+				t.Fatalf("SetControllerReference() error = %v", err)
+			}
+			controllerutil.AddFinalizer(csiDriver, utils.OperandCleanupFinalizer)
+
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				switch v := obj.(type) {
+				case *v1alpha1.SpiffeCSIDriver:
+					*v = *csiDriver
+					return nil
+				case *v1alpha1.ZeroTrustWorkloadIdentityManager:
+					*v = *ztwim
+					return nil
+				default:
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+			result, err := reconciler.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if result.RequeueAfter != tt.expected {
+				t.Errorf("Expected RequeueAfter %v, got %v", tt.expected, result.RequeueAfter)
+			}
+		})
+	}
+}
+
+// TestReconcile_Paused tests that a paused SpiffeCSIDriver only reports the Paused condition
+func TestReconcile_Paused(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newTestReconciler(fakeClient)
+
+	csiDriver := &v1alpha1.SpiffeCSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.SpiffeCSIDriverSpec{
+			CommonConfig: v1alpha1.CommonConfig{Paused: "true"},
+		},
+	}
+	fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		if v, ok := obj.(*v1alpha1.SpiffeCSIDriver); ok {
+			*v = *csiDriver
+			return nil
+		}
+		return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil || result.Requeue {
+		t.Fatalf("Reconcile() = %v, %v; expected no error and no requeue", result, err)
+	}
+
+	if fakeClient.GetCallCount() != 1 {
+		t.Errorf("Expected only the SpiffeCSIDriver to be read, got %d Get calls", fakeClient.GetCallCount())
+	}
+	if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+		t.Error("Expected no writes while paused")
+	}
+	if fakeClient.StatusUpdateWithRetryCallCount() != 1 {
+		t.Fatalf("Expected one status update, got %d", fakeClient.StatusUpdateWithRetryCallCount())
+	}
+	_, obj, _ := fakeClient.StatusUpdateWithRetryArgsForCall(0)
+	conditions := obj.(*v1alpha1.SpiffeCSIDriver).Status.Conditions
+	if !conditionHasStatus(conditions, "Paused", metav1.ConditionTrue) {
+		t.Errorf("Expected Paused=True, got %+v", conditions)
+	}
+}
+
+// TestReconcile_ManagementState tests that an unmanaged SpiffeCSIDriver is left as is and that a removed
+// one has its managed resources deleted, with the management state of the ZTWIM taking precedence
+func TestReconcile_ManagementState(t *testing.T) {
+	tests := []struct {
+		name          string
+		operandState  v1alpha1.ManagementState
+		ztwimState    v1alpha1.ManagementState
+		expectReason  string
+		expectDeletes int
+	}{
+		{
+			name:         "unmanaged operand",
+			operandState: v1alpha1.ManagementStateUnmanaged,
+			expectReason: string(v1alpha1.ManagementStateUnmanaged),
+		},
+		{
+			name:          "removed operand",
+			operandState:  v1alpha1.ManagementStateRemoved,
+			expectReason:  string(v1alpha1.ManagementStateRemoved),
+			expectDeletes: 3,
+		},
+		{
+			name:          "removed ZTWIM",
+			operandState:  v1alpha1.ManagementStateManaged,
+			ztwimState:    v1alpha1.ManagementStateRemoved,
+			expectReason:  string(v1alpha1.ManagementStateRemoved),
+			expectDeletes: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			reconciler := newTestReconciler(fakeClient)
+			_ = v1alpha1.AddToScheme(reconciler.scheme)
+
+			csiDriver := &v1alpha1.SpiffeCSIDriver{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: v1alpha1.SpiffeCSIDriverSpec{
+					CommonConfig: v1alpha1.CommonConfig{ManagementState: tt.operandState},
+				},
+			}
+			csiDriver.Status.ManagedResources = []v1alpha1.ManagedResource{
+				{APIVersion: "v1", Kind: "ServiceAccount", Namespace: utils.GetOperandNamespace(), Name: "spire-spiffe-csi-driver"},
+			}
+			ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{ManagementState: tt.ztwimState},
+			}
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				switch v := obj.(type) {
+				case *v1alpha1.SpiffeCSIDriver:
+					*v = *csiDriver
+				case *v1alpha1.ZeroTrustWorkloadIdentityManager:
+					*v = *ztwim
+				default:
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				return nil
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() returned an error: %v", err)
+			}
+
+			if fakeClient.CreateCallCount() != 0 {
+				t.Error("Expected no resources to be created")
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDeletes {
+				t.Errorf("Expected %d deletes, got %d", tt.expectDeletes, fakeClient.DeleteCallCount())
+			}
+			_, obj, _ := fakeClient.StatusUpdateWithRetryArgsForCall(fakeClient.StatusUpdateWithRetryCallCount() - 1)
+			driver := obj.(*v1alpha1.SpiffeCSIDriver)
+			ready := apimeta.FindStatusCondition(driver.Status.Conditions, v1alpha1.Ready)
+			if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != tt.expectReason {
+				t.Errorf("Expected Ready=False with reason %s, got %+v", tt.expectReason, ready)
+			}
+			if !conditionHasStatus(driver.Status.Conditions, utils.ManagementStateStatusType, metav1.ConditionFalse) {
+				t.Errorf("Expected ManagementState=False, got %+v", driver.Status.Conditions)
+			}
+			if tt.expectDeletes > 0 && len(driver.Status.ManagedResources) != 0 {
+				t.Errorf("Expected the inventory to be cleared, got %v", driver.Status.ManagedResources)
+			}
+		})
+	}
+}
+
+// TestReconcile_OperandNamespaceNotWatched tests that an operand namespace outside the watched namespaces is rejected
+func TestReconcile_OperandNamespaceNotWatched(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "operator-ns")
+	t.Setenv(utils.WatchNamespaceEnvName, "layered-product")
+
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newTestReconciler(fakeClient)
+
+	csiDriver := &v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{OperandNamespace: "other"},
+	}
+	fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		switch v := obj.(type) {
+		case *v1alpha1.SpiffeCSIDriver:
+			*v = *csiDriver
+			return nil
+		case *v1alpha1.ZeroTrustWorkloadIdentityManager:
+			*v = *ztwim
+			return nil
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+		t.Error("Expected no writes for an unwatched operand namespace")
+	}
+	calls := fakeClient.StatusUpdateWithRetryCallCount()
+	if calls == 0 {
+		t.Fatal("Expected the status to be updated")
+	}
+	_, obj, _ := fakeClient.StatusUpdateWithRetryArgsForCall(calls - 1)
+	conditions := obj.(*v1alpha1.SpiffeCSIDriver).Status.Conditions
+	if !conditionHasStatus(conditions, v1alpha1.Ready, metav1.ConditionFalse) {
+		t.Errorf("Expected Ready=False, got %+v", conditions)
+	}
+}
+
+func conditionHasStatus(conditions []metav1.Condition, conditionType string, conditionStatus metav1.ConditionStatus) bool {
+	for _, cond := range conditions {
+		if cond.Type == conditionType {
+			return cond.Status == conditionStatus
+		}
+	}
+	return false
+}
@@ -29,6 +29,7 @@ func (r *SpiffeCsiReconciler) reconcileCSIDriver(ctx context.Context, driver *v1
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &storagev1.CSIDriver{}
@@ -54,6 +55,7 @@ func (r *SpiffeCsiReconciler) reconcileCSIDriver(ctx context.Context, driver *v1
 		}
 
 		r.log.Info("Created CSIDriver", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(CSIDriverAvailable, v1alpha1.ReasonReady,
 			"All CSIDriver resources available",
 			metav1.ConditionTrue)
@@ -106,6 +108,7 @@ func (r *SpiffeCsiReconciler) reconcileCSIDriver(ctx context.Context, driver *v1
 	}
 
 	r.log.Info("Updated CSIDriver", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(CSIDriverAvailable, v1alpha1.ReasonReady,
 		"All CSIDriver resources available",
 		metav1.ConditionTrue)
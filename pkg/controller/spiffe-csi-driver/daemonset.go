@@ -3,6 +3,8 @@ package spiffe_csi_driver
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -18,11 +20,37 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 // reconcileDaemonSet reconciles the Spiffe CSI Driver DaemonSet
-func (r *SpiffeCsiReconciler) reconcileDaemonSet(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, statusMgr *status.Manager, createOnlyMode bool) error {
+func (r *SpiffeCsiReconciler) reconcileDaemonSet(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) error {
+	_, renderSpan := tracing.Start(ctx, "Render SPIFFE CSI driver DaemonSet")
 	spiffeCsiDaemonset := generateSpiffeCsiDriverDaemonSet(driver.Spec)
+	tracing.End(renderSpan, nil)
+	// Size the pods for the profile of the cluster before the user provided containers are added
+	utils.ApplyDeploymentProfile(&spiffeCsiDaemonset.Spec.Template.Spec, ztwim)
+	if err := utils.AddExtraVolumes(&spiffeCsiDaemonset.Spec.Template.Spec, "spiffe-csi-driver", driver.Spec.ExtraVolumes, driver.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "failed to add the extra volumes to the DaemonSet resource")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpiffeCSIDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.AddContainerEnv(&spiffeCsiDaemonset.Spec.Template.Spec, "spiffe-csi-driver", driver.Spec.Env, driver.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "failed to add the environment variables to the DaemonSet resource")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpiffeCSIDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.ApplySecurityContext(&spiffeCsiDaemonset.Spec.Template.Spec, "spiffe-csi-driver", driver.Spec.SecurityContext, driver.Spec.PodSecurityContext); err != nil {
+		r.log.Error(err, "failed to apply the security context overrides to the DaemonSet resource")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpiffeCSIDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
 	if err := controllerutil.SetControllerReference(driver, spiffeCsiDaemonset, r.scheme); err != nil {
 		r.log.Error(err, "failed to set owner reference for the DaemonSet resource")
 		statusMgr.AddCondition(DaemonSetAvailable, "SpiffeCSIDaemonSetGenerationFailed",
@@ -30,11 +58,12 @@ func (r *SpiffeCsiReconciler) reconcileDaemonSet(ctx context.Context, driver *v1
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(spiffeCsiDaemonset)
 
 	var existingSpiffeCsiDaemonSet appsv1.DaemonSet
 	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: spiffeCsiDaemonset.Name, Namespace: spiffeCsiDaemonset.Namespace}, &existingSpiffeCsiDaemonSet)
 	if err != nil && kerrors.IsNotFound(err) {
-		if err = r.ctrlClient.Create(ctx, spiffeCsiDaemonset); err != nil {
+		if err = r.ctrlClient.Apply(ctx, spiffeCsiDaemonset); err != nil {
 			r.log.Error(err, "Failed to create SpiffeCsiDaemon set")
 			statusMgr.AddCondition(DaemonSetAvailable, "SpiffeCSIDaemonSetCreationFailed",
 				err.Error(),
@@ -42,12 +71,12 @@ func (r *SpiffeCsiReconciler) reconcileDaemonSet(ctx context.Context, driver *v1
 			return fmt.Errorf("failed to create DaemonSet: %w", err)
 		}
 		r.log.Info("Created spiffe csi DaemonSet")
+		statusMgr.RecordResourceCreated(spiffeCsiDaemonset)
 	} else if err == nil && needsUpdate(existingSpiffeCsiDaemonSet, *spiffeCsiDaemonset) {
 		if createOnlyMode {
 			r.log.Info("Skipping DaemonSet update due to create-only mode")
 		} else {
-			spiffeCsiDaemonset.ResourceVersion = existingSpiffeCsiDaemonSet.ResourceVersion
-			if err = r.ctrlClient.Update(ctx, spiffeCsiDaemonset); err != nil {
+			if err = r.ctrlClient.Apply(ctx, spiffeCsiDaemonset); err != nil {
 				r.log.Error(err, "failed to update spiffe csi daemon set")
 				statusMgr.AddCondition(DaemonSetAvailable, "SpiffeCSIDaemonSetUpdateFailed",
 					err.Error(),
@@ -55,6 +84,7 @@ func (r *SpiffeCsiReconciler) reconcileDaemonSet(ctx context.Context, driver *v1
 				return fmt.Errorf("failed to update DaemonSet: %w", err)
 			}
 			r.log.Info("Updated spiffe csi DaemonSet")
+			statusMgr.RecordDriftRepaired(spiffeCsiDaemonset)
 		}
 	} else if err != nil {
 		r.log.Error(err, "Failed to get SpiffeCsiDaemon set")
@@ -75,7 +105,68 @@ func needsUpdate(current, desired appsv1.DaemonSet) bool {
 	return utils.ResourceNeedsUpdate(&current, &desired)
 }
 
+// defaultKubeletPath is the kubelet root directory used when the spec does not set one
+const defaultKubeletPath = "/var/lib/kubelet"
+
+// getKubeletPath returns the kubelet root directory of the nodes, without a trailing slash
+func getKubeletPath(config v1alpha1.SpiffeCSIDriverSpec) string {
+	if config.KubeletPath == "" {
+		return defaultKubeletPath
+	}
+	return path.Clean(config.KubeletPath)
+}
+
+// defaultRegistrarHealthPort is the port of the node-driver-registrar health endpoint
+// used when the spec does not set one
+const defaultRegistrarHealthPort int32 = 9809
+
+// getRegistrarImage returns the node-driver-registrar image, preferring the spec override
+func getRegistrarImage(config v1alpha1.SpiffeCSIDriverSpec) string {
+	if config.NodeDriverRegistrar != nil && config.NodeDriverRegistrar.Image != "" {
+		return config.NodeDriverRegistrar.Image
+	}
+	return utils.GetNodeDriverRegistrarImage()
+}
+
+// getRegistrarHealthPort returns the port the node-driver-registrar serves its health endpoint on
+func getRegistrarHealthPort(config v1alpha1.SpiffeCSIDriverSpec) int32 {
+	if config.NodeDriverRegistrar != nil && config.NodeDriverRegistrar.HealthPort != 0 {
+		return config.NodeDriverRegistrar.HealthPort
+	}
+	return defaultRegistrarHealthPort
+}
+
+// getRegistrarResources returns the node-driver-registrar resources, falling back to the
+// resources of the spec
+func getRegistrarResources(config v1alpha1.SpiffeCSIDriverSpec) corev1.ResourceRequirements {
+	if config.NodeDriverRegistrar != nil && config.NodeDriverRegistrar.Resources != nil {
+		return *config.NodeDriverRegistrar.Resources
+	}
+	return utils.DerefResourceRequirements(config.Resources)
+}
+
+// getRegistrarLivenessProbe returns the liveness probe of the node-driver-registrar with the
+// timings of the spec applied over the defaults
+func getRegistrarLivenessProbe(config v1alpha1.SpiffeCSIDriverSpec) *corev1.Probe {
+	probe := &corev1.Probe{
+		InitialDelaySeconds: 5,
+		TimeoutSeconds:      5,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromString("healthz"),
+			},
+		},
+	}
+	if config.NodeDriverRegistrar != nil {
+		utils.ApplyProbeTimings(probe, config.NodeDriverRegistrar.LivenessProbe)
+	}
+	return probe
+}
+
 func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *appsv1.DaemonSet {
+	kubeletPath := getKubeletPath(config)
+	registrarHealthPort := getRegistrarHealthPort(config)
 
 	// Generate standardized labels once and reuse them
 	labels := utils.SpiffeCSIDriverLabels(config.Labels)
@@ -90,7 +181,7 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-spiffe-csi-driver",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    labels,
 		},
 		Spec: appsv1.DaemonSetSpec{
@@ -108,13 +199,18 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						utils.RequiredSCCAnnotationKey: utils.GetSecurityContextConstraintsName(config.SecurityContextConstraints, "spire-spiffe-csi-driver"),
+					},
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: "spire-spiffe-csi-driver",
-					Affinity:           config.Affinity,
+					PriorityClassName:  utils.GetPriorityClassName(config.PriorityClassName, utils.SystemNodeCriticalPriorityClassName),
+					ImagePullSecrets:   config.ImagePullSecrets,
+					Affinity:           utils.PlatformAffinity(config.Affinity, config.NodePlatform),
 					Tolerations:        utils.DerefTolerations(config.Tolerations),
-					NodeSelector:       utils.DerefNodeSelector(config.NodeSelector),
+					NodeSelector:       utils.PlatformNodeSelector(config.NodeSelector, config.NodePlatform),
 					InitContainers: []corev1.Container{
 						{
 							Name:  "set-context",
@@ -179,18 +275,18 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 								},
 								{
 									Name:             "mountpoint-dir",
-									MountPath:        "/var/lib/kubelet/pods",
+									MountPath:        path.Join(kubeletPath, "pods"),
 									MountPropagation: mountPropagationPtr(corev1.MountPropagationBidirectional),
 								},
 							},
 						},
 						{
 							Name:  "node-driver-registrar",
-							Image: utils.GetNodeDriverRegistrarImage(),
+							Image: getRegistrarImage(config),
 							Args: []string{
 								"-csi-address", "/spiffe-csi/csi.sock",
-								"-kubelet-registration-path", fmt.Sprintf("/var/lib/kubelet/plugins/%s/csi.sock", config.PluginName),
-								"-health-port", "9809",
+								"-kubelet-registration-path", path.Join(kubeletPath, "plugins", config.PluginName, "csi.sock"),
+								"-health-port", strconv.Itoa(int(registrarHealthPort)),
 							},
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							VolumeMounts: []corev1.VolumeMount{
@@ -205,21 +301,12 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 							},
 							Ports: []corev1.ContainerPort{
 								{
-									ContainerPort: 9809,
+									ContainerPort: registrarHealthPort,
 									Name:          "healthz",
 								},
 							},
-							Resources: utils.DerefResourceRequirements(config.Resources),
-							LivenessProbe: &corev1.Probe{
-								InitialDelaySeconds: 5,
-								TimeoutSeconds:      5,
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path: "/healthz",
-										Port: intstr.FromString("healthz"),
-									},
-								},
-							},
+							Resources:     getRegistrarResources(config),
+							LivenessProbe: getRegistrarLivenessProbe(config),
 							SecurityContext: &corev1.SecurityContext{
 								Privileged: ptr.To(true),
 								Capabilities: &corev1.Capabilities{
@@ -242,7 +329,7 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 							Name: "spiffe-csi-socket-dir",
 							VolumeSource: corev1.VolumeSource{
 								HostPath: &corev1.HostPathVolumeSource{
-									Path: fmt.Sprintf("/var/lib/kubelet/plugins/%s", config.PluginName),
+									Path: path.Join(kubeletPath, "plugins", config.PluginName),
 									Type: hostPathTypePtr(corev1.HostPathDirectoryOrCreate),
 								},
 							},
@@ -251,7 +338,7 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 							Name: "mountpoint-dir",
 							VolumeSource: corev1.VolumeSource{
 								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/var/lib/kubelet/pods",
+									Path: path.Join(kubeletPath, "pods"),
 									Type: hostPathTypePtr(corev1.HostPathDirectory),
 								},
 							},
@@ -260,7 +347,7 @@ func generateSpiffeCsiDriverDaemonSet(config v1alpha1.SpiffeCSIDriverSpec) *apps
 							Name: "kubelet-plugin-registration-dir",
 							VolumeSource: corev1.VolumeSource{
 								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/var/lib/kubelet/plugins_registry",
+									Path: path.Join(kubeletPath, "plugins_registry"),
 									Type: hostPathTypePtr(corev1.HostPathDirectory),
 								},
 							},
@@ -14,11 +14,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -625,10 +627,14 @@ func TestReconcileDaemonSet(t *testing.T) {
 					return nil
 				}
 			}
-			fakeClient.CreateReturns(tt.createError)
-			fakeClient.UpdateReturns(tt.updateError)
+			// Both the create and the update go through server-side apply
+			if tt.createError != nil {
+				fakeClient.ApplyReturns(tt.createError)
+			} else {
+				fakeClient.ApplyReturns(tt.updateError)
+			}
 
-			err := reconciler.reconcileDaemonSet(context.Background(), driver, statusMgr, tt.createOnlyMode)
+			err := reconciler.reconcileDaemonSet(context.Background(), driver, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, tt.createOnlyMode)
 
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -636,14 +642,14 @@ func TestReconcileDaemonSet(t *testing.T) {
 			if !tt.expectError && err != nil {
 				t.Errorf("Expected no error, got: %v", err)
 			}
-			if tt.expectCreate && fakeClient.CreateCallCount() != 1 {
-				t.Errorf("Expected Create to be called once, got %d", fakeClient.CreateCallCount())
+			if (tt.expectCreate || tt.expectUpdate) && fakeClient.ApplyCallCount() != 1 {
+				t.Errorf("Expected Apply to be called once, got %d", fakeClient.ApplyCallCount())
 			}
-			if tt.expectUpdate && fakeClient.UpdateCallCount() != 1 {
-				t.Errorf("Expected Update to be called once, got %d", fakeClient.UpdateCallCount())
+			if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+				t.Error("Expected the DaemonSet to be applied instead of created or updated")
 			}
-			if tt.createOnlyMode && fakeClient.UpdateCallCount() != 0 {
-				t.Error("Expected Update not to be called in create-only mode")
+			if tt.createOnlyMode && fakeClient.ApplyCallCount() != 0 {
+				t.Error("Expected Apply not to be called in create-only mode")
 			}
 		})
 	}
@@ -711,3 +717,143 @@ func createDaemonSetTestDriver() *v1alpha1.SpiffeCSIDriver {
 		},
 	}
 }
+
+func TestGenerateSpiffeCsiDriverDaemonSetPriorityClass(t *testing.T) {
+	daemonSet := generateSpiffeCsiDriverDaemonSet(v1alpha1.SpiffeCSIDriverSpec{})
+	if daemonSet.Spec.Template.Spec.PriorityClassName != utils.SystemNodeCriticalPriorityClassName {
+		t.Errorf("Expected default priority class '%s', got '%s'", utils.SystemNodeCriticalPriorityClassName, daemonSet.Spec.Template.Spec.PriorityClassName)
+	}
+
+	config := v1alpha1.SpiffeCSIDriverSpec{
+		CommonConfig: v1alpha1.CommonConfig{PriorityClassName: "identity-critical"},
+	}
+	daemonSet = generateSpiffeCsiDriverDaemonSet(config)
+	if daemonSet.Spec.Template.Spec.PriorityClassName != "identity-critical" {
+		t.Errorf("Expected priority class 'identity-critical', got '%s'", daemonSet.Spec.Template.Spec.PriorityClassName)
+	}
+}
+
+func TestGenerateSpiffeCsiDriverDaemonSetRequiredSCC(t *testing.T) {
+	daemonSet := generateSpiffeCsiDriverDaemonSet(v1alpha1.SpiffeCSIDriverSpec{})
+	if got := daemonSet.Spec.Template.Annotations[utils.RequiredSCCAnnotationKey]; got != "spire-spiffe-csi-driver" {
+		t.Errorf("Expected the pods to require the SCC of the operator, got '%s'", got)
+	}
+
+	daemonSet = generateSpiffeCsiDriverDaemonSet(v1alpha1.SpiffeCSIDriverSpec{SecurityContextConstraints: "privileged"})
+	if got := daemonSet.Spec.Template.Annotations[utils.RequiredSCCAnnotationKey]; got != "privileged" {
+		t.Errorf("Expected the pods to require the referenced SCC, got '%s'", got)
+	}
+	if !utils.DaemonSetNeedsUpdate(generateSpiffeCsiDriverDaemonSet(v1alpha1.SpiffeCSIDriverSpec{}), daemonSet) {
+		t.Error("Expected an update when the required SCC changes")
+	}
+}
+
+func TestGenerateSpiffeCsiDriverDaemonSetNodePlatform(t *testing.T) {
+	daemonSet := generateSpiffeCsiDriverDaemonSet(v1alpha1.SpiffeCSIDriverSpec{
+		CommonConfig: v1alpha1.CommonConfig{NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""}},
+	})
+	expected := map[string]string{"node-role.kubernetes.io/worker": "", corev1.LabelOSStable: "linux"}
+	if !reflect.DeepEqual(expected, daemonSet.Spec.Template.Spec.NodeSelector) {
+		t.Errorf("Expected node selector %v, got %v", expected, daemonSet.Spec.Template.Spec.NodeSelector)
+	}
+
+	daemonSet = generateSpiffeCsiDriverDaemonSet(v1alpha1.SpiffeCSIDriverSpec{
+		CommonConfig: v1alpha1.CommonConfig{
+			NodePlatform: &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"amd64", "s390x"}},
+		},
+	})
+	affinity := daemonSet.Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		t.Fatalf("Expected a required node affinity, got %v", affinity)
+	}
+	expressions := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions
+	if len(expressions) != 1 || expressions[0].Key != corev1.LabelArchStable || !reflect.DeepEqual([]string{"amd64", "s390x"}, expressions[0].Values) {
+		t.Errorf("Expected the architectures requirement, got %v", expressions)
+	}
+}
+
+func TestGenerateSpiffeCsiDriverDaemonSetKubeletPath(t *testing.T) {
+	config := v1alpha1.SpiffeCSIDriverSpec{
+		PluginName:  "csi.spiffe.io",
+		KubeletPath: "/data/kubelet/",
+	}
+
+	daemonSet := generateSpiffeCsiDriverDaemonSet(config)
+
+	registrarContainer := daemonSet.Spec.Template.Spec.Containers[1]
+	expectedRegistrarArgs := []string{
+		"-csi-address", "/spiffe-csi/csi.sock",
+		"-kubelet-registration-path", "/data/kubelet/plugins/csi.spiffe.io/csi.sock",
+		"-health-port", "9809",
+	}
+	if !reflect.DeepEqual(registrarContainer.Args, expectedRegistrarArgs) {
+		t.Errorf("Expected registrar container args %v, got %v", expectedRegistrarArgs, registrarContainer.Args)
+	}
+
+	for _, mount := range daemonSet.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if mount.Name == "mountpoint-dir" && mount.MountPath != "/data/kubelet/pods" {
+			t.Errorf("Expected mountpoint-dir to be mounted at '/data/kubelet/pods', got '%s'", mount.MountPath)
+		}
+	}
+
+	expectedHostPaths := map[string]string{
+		"spiffe-csi-socket-dir":           "/data/kubelet/plugins/csi.spiffe.io",
+		"mountpoint-dir":                  "/data/kubelet/pods",
+		"kubelet-plugin-registration-dir": "/data/kubelet/plugins_registry",
+	}
+	for _, volume := range daemonSet.Spec.Template.Spec.Volumes {
+		if expected, ok := expectedHostPaths[volume.Name]; ok && volume.HostPath.Path != expected {
+			t.Errorf("Expected %s hostPath '%s', got '%s'", volume.Name, expected, volume.HostPath.Path)
+		}
+	}
+}
+
+func TestGenerateSpiffeCsiDriverDaemonSetNodeDriverRegistrar(t *testing.T) {
+	registrarResources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+	config := v1alpha1.SpiffeCSIDriverSpec{
+		PluginName: "csi.spiffe.io",
+		NodeDriverRegistrar: &v1alpha1.NodeDriverRegistrarConfig{
+			Image:      "mirror.example.com/sig-storage/csi-node-driver-registrar:v2.13.0",
+			Resources:  registrarResources,
+			HealthPort: 19809,
+			LivenessProbe: &v1alpha1.ProbeTimings{
+				TimeoutSeconds:   ptr.To(int32(15)),
+				FailureThreshold: ptr.To(int32(6)),
+			},
+		},
+		CommonConfig: v1alpha1.CommonConfig{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		},
+	}
+
+	daemonSet := generateSpiffeCsiDriverDaemonSet(config)
+
+	registrarContainer := daemonSet.Spec.Template.Spec.Containers[1]
+	if registrarContainer.Image != config.NodeDriverRegistrar.Image {
+		t.Errorf("Expected registrar image '%s', got '%s'", config.NodeDriverRegistrar.Image, registrarContainer.Image)
+	}
+	if registrarContainer.Args[len(registrarContainer.Args)-1] != "19809" {
+		t.Errorf("Expected registrar health port arg '19809', got %v", registrarContainer.Args)
+	}
+	if registrarContainer.Ports[0].ContainerPort != 19809 {
+		t.Errorf("Expected registrar container port 19809, got %d", registrarContainer.Ports[0].ContainerPort)
+	}
+	if !reflect.DeepEqual(registrarContainer.Resources, *registrarResources) {
+		t.Errorf("Expected registrar resources %v, got %v", *registrarResources, registrarContainer.Resources)
+	}
+	if !reflect.DeepEqual(daemonSet.Spec.Template.Spec.Containers[0].Resources, *config.Resources) {
+		t.Errorf("Expected CSI driver resources %v, got %v", *config.Resources, daemonSet.Spec.Template.Spec.Containers[0].Resources)
+	}
+
+	probe := registrarContainer.LivenessProbe
+	if probe.InitialDelaySeconds != 5 || probe.TimeoutSeconds != 15 || probe.PeriodSeconds != 0 || probe.FailureThreshold != 6 {
+		t.Errorf("Unexpected registrar liveness probe timings: %+v", probe)
+	}
+	if probe.HTTPGet == nil || probe.HTTPGet.Port != intstr.FromString("healthz") {
+		t.Errorf("Expected liveness probe on the healthz port, got %+v", probe.ProbeHandler)
+	}
+}
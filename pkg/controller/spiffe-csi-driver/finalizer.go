@@ -0,0 +1,66 @@
+package spiffe_csi_driver
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// reconcileDeletion tears down the SPIFFE CSI driver before the SpiffeCSIDriver CR is removed.
+// The CSI driver is the first operand torn down, as the SPIRE agent serves the workload API
+// socket it mounts into pods. The CSIDriver registration is removed first so that no new
+// volumes are provisioned, then the DaemonSet is deleted. The remaining resources are
+// garbage collected through their owner references.
+func (r *SpiffeCsiReconciler) reconcileDeletion(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, namespace string) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(driver, utils.OperandCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	getStatus := func() *v1alpha1.ConditionalStatus {
+		return &driver.Status.ConditionalStatus
+	}
+
+	if err := status.SetDeletionStatus(ctx, r.ctrlClient, driver, getStatus, utils.DeletionDeletingResources,
+		"Deleting the SPIFFE CSI driver resources"); err != nil {
+		r.log.Error(err, "failed to update status")
+	}
+	if err := utils.DeleteObjects(ctx, r.ctrlClient,
+		getSpiffeCSIDriver(driver.Spec.PluginName, nil),
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-spiffe-csi-driver", Namespace: namespace}},
+	); err != nil {
+		r.log.Error(err, "failed to delete SPIFFE CSI driver resources")
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(driver, utils.OperandCleanupFinalizer)
+	if err := r.ctrlClient.Update(ctx, driver); err != nil {
+		r.log.Error(err, "failed to remove finalizer from SpiffeCSIDriver")
+		return ctrl.Result{}, err
+	}
+	r.log.Info("SPIFFE CSI driver resources deleted, removed finalizer", "name", driver.Name)
+	return ctrl.Result{}, nil
+}
+
+// reconcileRemoval deletes the resources managed for the SpiffeCSIDriver while keeping the CR, when its
+// management state is Removed. The CSIDriver registration and the DaemonSet are deleted first, as in the teardown.
+func (r *SpiffeCsiReconciler) reconcileRemoval(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, namespace string, statusMgr *status.Manager) error {
+	if err := statusMgr.RemoveManagedResources(ctx, r.ctrlClient, r.scheme, driver.Status.ManagedResources,
+		getSpiffeCSIDriver(driver.Spec.PluginName, nil),
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-spiffe-csi-driver", Namespace: namespace}},
+	); err != nil {
+		r.log.Error(err, "failed to remove SPIFFE CSI driver resources")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+			fmt.Sprintf("Failed to remove the SPIFFE CSI driver resources: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	r.log.Info("SPIFFE CSI driver resources removed", "name", driver.Name)
+	return nil
+}
@@ -20,7 +20,7 @@ import (
 
 // generateSpiffeCSIDriverSCC returns a pointer to the desired SCC object
 func generateSpiffeCSIDriverSCC(customLabels map[string]string) *securityv1.SecurityContextConstraints {
-	csiServiceAccountUser := "system:serviceaccount:" + utils.GetOperatorNamespace() + ":spire-spiffe-csi-driver"
+	csiServiceAccountUser := "system:serviceaccount:" + utils.GetOperandNamespace() + ":spire-spiffe-csi-driver"
 	return &securityv1.SecurityContextConstraints{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   "spire-spiffe-csi-driver",
@@ -64,6 +64,9 @@ func generateSpiffeCSIDriverSCC(customLabels map[string]string) *securityv1.Secu
 // reconcileSCC reconciles the Spiffe CSI Driver Security Context Constraints
 func (r *SpiffeCsiReconciler) reconcileSCC(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, statusMgr *status.Manager) error {
 	desired := generateSpiffeCSIDriverSCC(driver.Spec.Labels)
+	if driver.Spec.SecurityContextConstraints != "" {
+		return r.checkReferencedSCC(ctx, driver.Spec.SecurityContextConstraints, desired, statusMgr)
+	}
 	if err := controllerutil.SetControllerReference(driver, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set the owner reference for the SCC resource")
 		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpiffeCSISCCGenerationFailed",
@@ -71,6 +74,7 @@ func (r *SpiffeCsiReconciler) reconcileSCC(ctx context.Context, driver *v1alpha1
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &securityv1.SecurityContextConstraints{}
@@ -96,6 +100,7 @@ func (r *SpiffeCsiReconciler) reconcileSCC(ctx context.Context, driver *v1alpha1
 		}
 
 		r.log.Info("Created SecurityContextConstraints", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpiffeCSISCCResourceCreated",
 			"SpiffeCSISCC resource created",
 			metav1.ConditionTrue)
@@ -128,8 +133,35 @@ func (r *SpiffeCsiReconciler) reconcileSCC(ctx context.Context, driver *v1alpha1
 	}
 
 	r.log.Info("Updated SecurityContextConstraints", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpiffeCSISCCResourceUpdated",
 		"SpiffeCSISCC resource updated",
 		metav1.ConditionTrue)
 	return nil
 }
+
+// checkReferencedSCC checks that the SecurityContextConstraints referenced by the SpiffeCSIDriver exists and grants
+// the access of required, the SecurityContextConstraints the operator creates otherwise
+func (r *SpiffeCsiReconciler) checkReferencedSCC(ctx context.Context, name string, required *securityv1.SecurityContextConstraints, statusMgr *status.Manager) error {
+	scc := &securityv1.SecurityContextConstraints{}
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: name}, scc); err != nil {
+		r.log.Error(err, "failed to get the referenced SecurityContextConstraints", "name", name)
+		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpiffeCSISCCGetFailed",
+			fmt.Sprintf("Failed to get SecurityContextConstraints %q: %v", name, err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	if err := utils.ValidateSecurityContextConstraints(scc, required); err != nil {
+		r.log.Error(err, "referenced SecurityContextConstraints can't admit the SPIFFE CSI driver pods")
+		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpiffeCSISCCInsufficient",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpiffeCSISCCReferenced",
+		fmt.Sprintf("Spiffe CSI Driver pods are admitted with the SecurityContextConstraints %q", name),
+		metav1.ConditionTrue)
+	return nil
+}
@@ -692,3 +692,45 @@ func TestReconcileSCC(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileSCC_ReferencedSCC(t *testing.T) {
+	driver := &v1alpha1.SpiffeCSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+		Spec:       v1alpha1.SpiffeCSIDriverSpec{SecurityContextConstraints: "privileged"},
+	}
+
+	tests := []struct {
+		name        string
+		volumes     []securityv1.FSType
+		privileged  bool
+		expectError bool
+	}{
+		{name: "referenced SCC admits the CSI driver pods", volumes: []securityv1.FSType{securityv1.FSTypeAll}, privileged: true},
+		{name: "referenced SCC without privileged containers", volumes: []securityv1.FSType{securityv1.FSTypeAll}, expectError: true},
+		{name: "referenced SCC without host path volumes", volumes: []securityv1.FSType{securityv1.FSTypeConfigMap, securityv1.FSTypeSecret}, privileged: true, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				*obj.(*securityv1.SecurityContextConstraints) = securityv1.SecurityContextConstraints{
+					ObjectMeta:               metav1.ObjectMeta{Name: key.Name},
+					AllowHostDirVolumePlugin: true,
+					AllowPrivilegedContainer: tt.privileged,
+					Volumes:                  tt.volumes,
+				}
+				return nil
+			}
+			reconciler := newSCCTestReconciler(fakeClient)
+
+			err := reconciler.reconcileSCC(context.Background(), driver, status.NewManager(fakeClient))
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcileSCC() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+				t.Error("Expected the referenced SCC to be left unchanged")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package spiffe_csi_driver
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -20,7 +21,7 @@ import (
 
 // reconcileServiceAccount reconciles the Spiffe CSI Driver ServiceAccount
 func (r *SpiffeCsiReconciler) reconcileServiceAccount(ctx context.Context, driver *v1alpha1.SpiffeCSIDriver, statusMgr *status.Manager, createOnlyMode bool) error {
-	desired := getSpiffeCSIDriverServiceAccount(driver.Spec.Labels)
+	desired := getSpiffeCSIDriverServiceAccount(driver.Spec.Labels, driver.Spec.ServiceAccountAnnotations)
 
 	if err := controllerutil.SetControllerReference(driver, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on service account")
@@ -29,6 +30,7 @@ func (r *SpiffeCsiReconciler) reconcileServiceAccount(ctx context.Context, drive
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.ServiceAccount{}
@@ -54,6 +56,7 @@ func (r *SpiffeCsiReconciler) reconcileServiceAccount(ctx context.Context, drive
 		}
 
 		r.log.Info("Created ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 			"All ServiceAccount resources available",
 			metav1.ConditionTrue)
@@ -89,16 +92,18 @@ func (r *SpiffeCsiReconciler) reconcileServiceAccount(ctx context.Context, drive
 	}
 
 	r.log.Info("Updated ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 		"All ServiceAccount resources available",
 		metav1.ConditionTrue)
 	return nil
 }
 
-// getSpiffeCSIDriverServiceAccount returns the Spiffe CSI Driver ServiceAccount with proper labels
-func getSpiffeCSIDriverServiceAccount(customLabels map[string]string) *corev1.ServiceAccount {
+// getSpiffeCSIDriverServiceAccount returns the Spiffe CSI Driver ServiceAccount with proper labels and annotations
+func getSpiffeCSIDriverServiceAccount(customLabels, annotations map[string]string) *corev1.ServiceAccount {
 	sa := utils.DecodeServiceAccountObjBytes(assets.MustAsset(utils.SpiffeCsiDriverServiceAccountAssetName))
 	sa.Labels = utils.SpiffeCSIDriverLabels(customLabels)
-	sa.Namespace = utils.GetOperatorNamespace()
+	sa.Namespace = utils.GetOperandNamespace()
+	sa.Annotations = maps.Clone(annotations)
 	return sa
 }
@@ -20,7 +20,7 @@ import (
 )
 
 func TestGetSpiffeCSIDriverServiceAccount(t *testing.T) {
-	sa := getSpiffeCSIDriverServiceAccount(nil)
+	sa := getSpiffeCSIDriverServiceAccount(nil, nil)
 
 	if sa == nil {
 		t.Fatal("Expected ServiceAccount, got nil")
@@ -64,6 +64,19 @@ func newSATestReconciler(fakeClient *fakes.FakeCustomCtrlClient) *SpiffeCsiRecon
 }
 
 // TestReconcileServiceAccount tests the reconcileServiceAccount function
+func TestGetSpiffeCSIDriverServiceAccount_OperandNamespace(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "operator-ns")
+	if err := utils.ConfigureOperandNamespace("layered-product"); err != nil {
+		t.Fatalf("ConfigureOperandNamespace() error = %v", err)
+	}
+	t.Cleanup(func() { _ = utils.ConfigureOperandNamespace("") })
+
+	sa := getSpiffeCSIDriverServiceAccount(nil, nil)
+	if sa.Namespace != "layered-product" {
+		t.Errorf("Expected namespace layered-product, got %s", sa.Namespace)
+	}
+}
+
 func TestReconcileServiceAccount(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -17,11 +19,14 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 // reconcileConfigMap reconciles the Spire Agent ConfigMap
 func (r *SpireAgentReconciler) reconcileConfigMap(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) (string, error) {
+	_, renderSpan := tracing.Start(ctx, "Render SPIRE agent ConfigMap")
 	spireAgentConfigMap, spireAgentConfigHash, err := generateSpireAgentConfigMap(agent, ztwim)
+	tracing.End(renderSpan, err)
 	if err != nil {
 		r.log.Error(err, "failed to generate spire-agent config map")
 		statusMgr.AddCondition(ConfigMapAvailable, "SpireAgentConfigMapGenerationFailed",
@@ -37,6 +42,7 @@ func (r *SpireAgentReconciler) reconcileConfigMap(ctx context.Context, agent *v1
 			metav1.ConditionFalse)
 		return "", err
 	}
+	statusMgr.TrackResource(spireAgentConfigMap)
 
 	var existingSpireAgentCM corev1.ConfigMap
 	err = r.ctrlClient.Get(ctx, types.NamespacedName{Name: spireAgentConfigMap.Name, Namespace: spireAgentConfigMap.Namespace}, &existingSpireAgentCM)
@@ -49,6 +55,7 @@ func (r *SpireAgentReconciler) reconcileConfigMap(ctx context.Context, agent *v1
 			return "", fmt.Errorf("failed to create ConfigMap: %w", err)
 		}
 		r.log.Info("Created spire agent ConfigMap")
+		statusMgr.RecordResourceCreated(spireAgentConfigMap)
 	} else if err == nil && (existingSpireAgentCM.Data["agent.conf"] != spireAgentConfigMap.Data["agent.conf"] ||
 		!equality.Semantic.DeepEqual(existingSpireAgentCM.Labels, spireAgentConfigMap.Labels)) {
 		if createOnlyMode {
@@ -63,6 +70,7 @@ func (r *SpireAgentReconciler) reconcileConfigMap(ctx context.Context, agent *v1
 				return "", fmt.Errorf("failed to update ConfigMap: %w", err)
 			}
 			r.log.Info("Updated ConfigMap with new config")
+			statusMgr.RecordDriftRepaired(spireAgentConfigMap)
 		}
 	} else if err != nil {
 		statusMgr.AddCondition(ConfigMapAvailable, "SpireAgentConfigMapGenerationFailed",
@@ -78,23 +86,72 @@ func (r *SpireAgentReconciler) reconcileConfigMap(ctx context.Context, agent *v1
 	return spireAgentConfigHash, nil
 }
 
+// validateRenderedAgentConfig checks that the SPIRE agent accepts the config rendered from the spec, e.g.
+// with the plugins added with extraConfig, before it is written to the ConfigMap. A config that can't be
+// rendered is reported when the ConfigMap is reconciled.
+func validateRenderedAgentConfig(agent *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	cm, _, err := generateSpireAgentConfigMap(agent, ztwim)
+	if err != nil {
+		return nil
+	}
+	return utils.ValidateSpireAgentConfig(cm.Data["agent.conf"])
+}
+
+// validateAgentExtraConfigGlobals rejects an extraConfig overriding the settings of the agent config
+// derived from the ZeroTrustWorkloadIdentityManager
+func validateAgentExtraConfigGlobals(cfg *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	extraConfig, err := utils.DecodeExtraConfig(cfg.Spec.ExtraConfig)
+	if err != nil {
+		return err
+	}
+	serverAddress, _ := spireServerAddress(ztwim)
+	return utils.ValidateExtraConfigGlobals(extraConfig, map[string]string{
+		"agent.trust_domain":   ztwim.Spec.TrustDomain,
+		"agent.server_address": serverAddress,
+	})
+}
+
+// minAvailabilityTarget is the shortest availability_target accepted by the SPIRE agent
+const minAvailabilityTarget = 24 * time.Hour
+
+// validateAvailabilityTarget validates the availability_target of the agent, which SPIRE refuses below
+// minAvailabilityTarget
+func validateAvailabilityTarget(config v1alpha1.SpireAgentSpec) error {
+	if config.AvailabilityTarget != nil && config.AvailabilityTarget.Duration < minAvailabilityTarget {
+		return fmt.Errorf("availabilityTarget %s must be at least %s", config.AvailabilityTarget.Duration, minAvailabilityTarget)
+	}
+	return nil
+}
+
+// spireServerAddress returns the host and the port the agents reach the SPIRE server on: the SPIRE server
+// Service in the operand namespace, or the SPIRE server exposed by the management cluster in the
+// HostedCluster topology, which is validated beforehand
+func spireServerAddress(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) (string, string) {
+	if utils.GetTopologyMode(ztwim) == v1alpha1.TopologyHostedCluster {
+		if host, port, err := utils.SplitServerAddress(ztwim.Spec.Topology.ServerAddress); err == nil {
+			return host, port
+		}
+	}
+	return "spire-server." + utils.GetOperandNamespace(), "443"
+}
+
 func generateAgentConfig(cfg *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) map[string]interface{} {
-	spireServerAddress := "spire-server." + utils.GetOperatorNamespace()
+	serverAddress, serverPort := spireServerAddress(ztwim)
 	agentConf := map[string]interface{}{
 		"agent": map[string]interface{}{
 			"data_dir":          "/var/lib/spire",
 			"log_level":         utils.GetLogLevelFromString(cfg.Spec.LogLevel),
 			"log_format":        utils.GetLogFormatFromString(cfg.Spec.LogFormat),
 			"retry_bootstrap":   true,
-			"server_address":    spireServerAddress,
-			"server_port":       "443",
-			"socket_path":       "/tmp/spire-agent/public/spire-agent.sock",
+			"server_address":    serverAddress,
+			"server_port":       serverPort,
+			"socket_path":       "/tmp/spire-agent/public/" + utils.GetAgentSocketName(cfg),
 			"trust_bundle_path": "/run/spire/bundle/bundle.crt",
 			"trust_domain":      ztwim.Spec.TrustDomain,
 		},
 		"health_checks": map[string]interface{}{
 			"bind_address":     "0.0.0.0",
-			"bind_port":        9982,
+			"bind_port":        int(getHealthPort(cfg.Spec)),
 			"listener_enabled": true,
 			"live_path":        "/live",
 			"ready_path":       "/ready",
@@ -107,11 +164,15 @@ func generateAgentConfig(cfg *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWork
 		"telemetry": map[string]interface{}{
 			"Prometheus": map[string]interface{}{
 				"host": "0.0.0.0",
-				"port": "9402",
+				"port": strconv.Itoa(int(getMetricsPort(cfg.Spec))),
 			},
 		},
 	}
 
+	if cfg.Spec.AvailabilityTarget != nil {
+		agentConf["agent"].(map[string]interface{})["availability_target"] = cfg.Spec.AvailabilityTarget.Duration.String()
+	}
+
 	if cfg.Spec.NodeAttestor != nil && cfg.Spec.NodeAttestor.K8sPSATEnabled == "true" {
 		agentConf["plugins"].(map[string]interface{})["NodeAttestor"] = []map[string]interface{}{
 			{
@@ -124,6 +185,10 @@ func generateAgentConfig(cfg *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWork
 		}
 	}
 
+	if experimental := utils.ExperimentalConfig(cfg.Spec.Experimental); experimental != nil {
+		agentConf["agent"].(map[string]interface{})["experimental"] = experimental
+	}
+
 	if cfg.Spec.WorkloadAttestors != nil && cfg.Spec.WorkloadAttestors.K8sEnabled == "true" {
 		plugin := map[string]interface{}{
 			"disable_container_selectors":    utils.StringToBool(cfg.Spec.WorkloadAttestors.DisableContainerSelectors),
@@ -140,9 +205,47 @@ func generateAgentConfig(cfg *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWork
 		}
 	}
 
+	if sds := generateSDSConfig(cfg.Spec.SDS); sds != nil {
+		agentConf["agent"].(map[string]interface{})["sds"] = sds
+	}
+
+	if isAdminAPIEnabled(cfg.Spec.AdminAPI) {
+		agentSection := agentConf["agent"].(map[string]interface{})
+		agentSection["admin_socket_path"] = agentAdminSocketMountPath + "/admin.sock"
+		if len(cfg.Spec.AdminAPI.AuthorizedDelegates) > 0 {
+			agentSection["authorized_delegates"] = cfg.Spec.AdminAPI.AuthorizedDelegates
+		}
+	}
+
 	return agentConf
 }
 
+// isAdminAPIEnabled returns whether the SPIRE agents serve their admin socket
+func isAdminAPIEnabled(adminAPI *v1alpha1.AgentAdminAPIConfig) bool {
+	return adminAPI != nil && utils.StringToBool(adminAPI.Enabled)
+}
+
+// generateSDSConfig returns the sds section of the agent config, or nil when no resource name is set
+func generateSDSConfig(sds *v1alpha1.SDSConfig) map[string]interface{} {
+	if sds == nil {
+		return nil
+	}
+	sdsConf := map[string]interface{}{}
+	if sds.DefaultSVIDName != "" {
+		sdsConf["default_svid_name"] = sds.DefaultSVIDName
+	}
+	if sds.DefaultBundleName != "" {
+		sdsConf["default_bundle_name"] = sds.DefaultBundleName
+	}
+	if sds.DefaultAllBundlesName != "" {
+		sdsConf["default_all_bundles_name"] = sds.DefaultAllBundlesName
+	}
+	if len(sdsConf) == 0 {
+		return nil
+	}
+	return sdsConf
+}
+
 // configureKubeletVerification configures the kubelet TLS verification settings
 // based on the WorkloadAttestorsVerification configuration.
 // This maps to SPIRE's skip_kubelet_verification and kubelet_ca_path options.
@@ -191,6 +294,12 @@ func buildHostCertPath(verification *v1alpha1.WorkloadAttestorsVerification) str
 
 func generateSpireAgentConfigMap(spireAgentConfig *v1alpha1.SpireAgent, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) (*corev1.ConfigMap, string, error) {
 	agentConfig := generateAgentConfig(spireAgentConfig, ztwim)
+	// Merge the user provided settings last so that the keys set by the operator win
+	extraConfig, err := utils.DecodeExtraConfig(spireAgentConfig.Spec.ExtraConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	utils.MergeExtraConfig(agentConfig, extraConfig)
 	agentConfigJSON, err := json.MarshalIndent(agentConfig, "", "  ")
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to marshal agent config: %w", err)
@@ -201,7 +310,7 @@ func generateSpireAgentConfigMap(spireAgentConfig *v1alpha1.SpireAgent, ztwim *v
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-agent",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    utils.SpireAgentLabels(spireAgentConfig.Spec.Labels),
 			Annotations: map[string]string{
 				utils.AppManagedByLabelKey: utils.AppManagedByLabelValue,
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -545,6 +547,225 @@ func TestGenerateSpireAgentConfigMapConsistency(t *testing.T) {
 	assert.Equal(t, cm2.Data["agent.conf"], cm3.Data["agent.conf"])
 }
 
+func TestGenerateAgentConfigSDS(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		sds      *v1alpha1.SDSConfig
+		expected interface{}
+	}{
+		{
+			name:     "unset keeps the SPIRE defaults",
+			sds:      nil,
+			expected: nil,
+		},
+		{
+			name:     "empty names keep the SPIRE defaults",
+			sds:      &v1alpha1.SDSConfig{},
+			expected: nil,
+		},
+		{
+			name: "all names",
+			sds: &v1alpha1.SDSConfig{
+				DefaultSVIDName:       "default",
+				DefaultBundleName:     "ROOTCA",
+				DefaultAllBundlesName: "ALL",
+			},
+			expected: map[string]interface{}{
+				"default_svid_name":        "default",
+				"default_bundle_name":      "ROOTCA",
+				"default_all_bundles_name": "ALL",
+			},
+		},
+		{
+			name:     "only the set names",
+			sds:      &v1alpha1.SDSConfig{DefaultBundleName: "null"},
+			expected: map[string]interface{}{"default_bundle_name": "null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{SDS: tt.sds}}
+
+			agentSection := generateAgentConfig(agent, ztwim)["agent"].(map[string]interface{})
+
+			sds, ok := agentSection["sds"]
+			if tt.expected == nil {
+				assert.False(t, ok, "sds should not be rendered")
+				return
+			}
+			assert.Equal(t, tt.expected, sds)
+		})
+	}
+}
+
+func TestGenerateAgentConfigAdminAPI(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		for _, adminAPI := range []*v1alpha1.AgentAdminAPIConfig{nil, {Enabled: "false", AuthorizedDelegates: []string{"spiffe://example.org/delegate"}}} {
+			agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{AdminAPI: adminAPI}}
+			agentSection := generateAgentConfig(agent, ztwim)["agent"].(map[string]interface{})
+			assert.NotContains(t, agentSection, "admin_socket_path")
+			assert.NotContains(t, agentSection, "authorized_delegates")
+		}
+	})
+
+	t.Run("enabled without delegates", func(t *testing.T) {
+		agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{AdminAPI: &v1alpha1.AgentAdminAPIConfig{Enabled: "true"}}}
+		agentSection := generateAgentConfig(agent, ztwim)["agent"].(map[string]interface{})
+		assert.Equal(t, "/tmp/spire-agent/private/admin.sock", agentSection["admin_socket_path"])
+		assert.NotContains(t, agentSection, "authorized_delegates")
+	})
+
+	t.Run("enabled with delegates", func(t *testing.T) {
+		delegates := []string{"spiffe://example.org/ns/istio-system/sa/ztunnel"}
+		agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{AdminAPI: &v1alpha1.AgentAdminAPIConfig{Enabled: "true", AuthorizedDelegates: delegates}}}
+		agentSection := generateAgentConfig(agent, ztwim)["agent"].(map[string]interface{})
+		assert.Equal(t, "/tmp/spire-agent/private/admin.sock", agentSection["admin_socket_path"])
+		assert.Equal(t, delegates, agentSection["authorized_delegates"])
+	})
+}
+
+func TestGenerateSpireAgentConfigMapWithExtraConfig(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			ClusterName:     "test-cluster",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	t.Run("extra settings are merged and operator keys win", func(t *testing.T) {
+		agent := &v1alpha1.SpireAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1alpha1.SpireAgentSpec{
+				ExtraConfig: &apiextensionsv1.JSON{Raw: []byte(`{"agent":{"trust_domain":"other.org","sds":{"default_svid_name":"default"}}}`)},
+			},
+		}
+		plain := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+		cm, hash, err := generateSpireAgentConfigMap(agent, ztwim)
+		require.NoError(t, err)
+		_, plainHash, err := generateSpireAgentConfigMap(plain, ztwim)
+		require.NoError(t, err)
+
+		var conf map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(cm.Data["agent.conf"]), &conf))
+		agentSection := conf["agent"].(map[string]interface{})
+		assert.Equal(t, "example.org", agentSection["trust_domain"])
+		assert.Equal(t, map[string]interface{}{"default_svid_name": "default"}, agentSection["sds"])
+		assert.NotEqual(t, plainHash, hash, "extraConfig changes must roll out the agents")
+	})
+
+	t.Run("invalid extra config is rejected", func(t *testing.T) {
+		agent := &v1alpha1.SpireAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1alpha1.SpireAgentSpec{
+				ExtraConfig: &apiextensionsv1.JSON{Raw: []byte(`"agent"`)},
+			},
+		}
+
+		_, _, err := generateSpireAgentConfigMap(agent, ztwim)
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerateAgentConfigWithExperimental(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+	agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{Experimental: map[string]string{"use_sync_authorized_entries": "false", "named_pipe_name": "spire-agent"}}}
+
+	agentSection := generateAgentConfig(agent, ztwim)["agent"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"use_sync_authorized_entries": false, "named_pipe_name": "spire-agent"}, agentSection["experimental"])
+
+	agentSection = generateAgentConfig(&v1alpha1.SpireAgent{}, ztwim)["agent"].(map[string]interface{})
+	assert.NotContains(t, agentSection, "experimental")
+}
+
+func TestGenerateAgentConfigAvailabilityTarget(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+	agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{AvailabilityTarget: &metav1.Duration{Duration: 36 * time.Hour}}}
+
+	agentSection := generateAgentConfig(agent, ztwim)["agent"].(map[string]interface{})
+	assert.Equal(t, "36h0m0s", agentSection["availability_target"])
+
+	agentSection = generateAgentConfig(&v1alpha1.SpireAgent{}, ztwim)["agent"].(map[string]interface{})
+	assert.NotContains(t, agentSection, "availability_target")
+}
+
+func TestValidateAvailabilityTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  *metav1.Duration
+		wantErr bool
+	}{
+		{name: "unset"},
+		{name: "minimum", target: &metav1.Duration{Duration: 24 * time.Hour}},
+		{name: "longer", target: &metav1.Duration{Duration: 72 * time.Hour}},
+		{name: "too short", target: &metav1.Duration{Duration: 12 * time.Hour}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAvailabilityTarget(v1alpha1.SpireAgentSpec{AvailabilityTarget: tt.target})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAgentExtraConfigGlobals(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	tests := []struct {
+		name        string
+		extraConfig string
+		expectError bool
+	}{
+		{name: "unset"},
+		{name: "other agent settings", extraConfig: `{"agent":{"sds":{"default_svid_name":"default"}}}`},
+		{name: "same trust domain", extraConfig: `{"agent":{"trust_domain":"example.org"}}`},
+		{name: "conflicting trust domain", extraConfig: `{"agent":{"trust_domain":"other.org"}}`, expectError: true},
+		{name: "conflicting server address", extraConfig: `{"agent":{"server_address":"spire-server.other"}}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+			if tt.extraConfig != "" {
+				agent.Spec.ExtraConfig = &apiextensionsv1.JSON{Raw: []byte(tt.extraConfig)}
+			}
+			err := validateAgentExtraConfigGlobals(agent, ztwim)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestGenerateAgentConfigNilChecks(t *testing.T) {
 	tests := []struct {
 		name string
@@ -990,3 +1211,30 @@ func TestGenerateAgentConfigWithVerification(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateAgentConfigPorts(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{HealthPort: 19982, MetricsPort: 19402}}
+
+	config := generateAgentConfig(agent, ztwim)
+	assert.Equal(t, 19982, config["health_checks"].(map[string]interface{})["bind_port"])
+	assert.Equal(t, "19402", config["telemetry"].(map[string]interface{})["Prometheus"].(map[string]interface{})["port"])
+}
+
+func TestGenerateAgentConfigHostedClusterTopology(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain: "example.org",
+			Topology:    &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster, ServerAddress: "spire-server.apps.mgmt.example.com:8443"},
+		},
+	}
+
+	agentSection := generateAgentConfig(&v1alpha1.SpireAgent{}, ztwim)["agent"].(map[string]interface{})
+	assert.Equal(t, "spire-server.apps.mgmt.example.com", agentSection["server_address"])
+	assert.Equal(t, "8443", agentSection["server_port"])
+
+	cfg := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{ExtraConfig: &apiextensionsv1.JSON{Raw: []byte(`{"agent":{"server_address":"spire-server.spire"}}`)}}}
+	assert.Error(t, validateAgentExtraConfigGlobals(cfg, ztwim))
+}
@@ -22,14 +22,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/operatorconfig"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 const (
@@ -40,6 +44,7 @@ const (
 	ServiceAvailable                    = "ServiceAvailable"
 	RBACAvailable                       = "RBACAvailable"
 	ConfigurationValid                  = "ConfigurationValid"
+	StagedRollout                       = "StagedRollout"
 )
 
 const spireAgentDaemonSetSpireAgentConfigHashAnnotationKey = "ztwim.openshift.io/spire-agent-config-hash"
@@ -51,11 +56,14 @@ type SpireAgentReconciler struct {
 	eventRecorder record.EventRecorder
 	log           logr.Logger
 	scheme        *runtime.Scheme
+
+	// failures counts the consecutive failed reconciles, to report the CRs that keep failing as Degraded
+	failures *utils.FailureTracker
 }
 
 // New returns a new Reconciler instance.
-func New(mgr ctrl.Manager) (*SpireAgentReconciler, error) {
-	c, err := customClient.NewCustomClient(mgr)
+func New(mgr ctrl.Manager, clientOpts ...customClient.Option) (*SpireAgentReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -65,10 +73,11 @@ func New(mgr ctrl.Manager) (*SpireAgentReconciler, error) {
 		eventRecorder: mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerSpireAgentControllerName),
 		log:           ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerSpireAgentControllerName),
 		scheme:        mgr.GetScheme(),
+		failures:      utils.NewFailureTracker(),
 	}, nil
 }
 
-func (r *SpireAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *SpireAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	r.log.Info(fmt.Sprintf("reconciling %s", utils.ZeroTrustWorkloadIdentityManagerSpireAgentControllerName))
 	var agent v1alpha1.SpireAgent
 	if err := r.ctrlClient.Get(ctx, req.NamespacedName, &agent); err != nil {
@@ -79,13 +88,42 @@ func (r *SpireAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Tear down the managed resources in order when the CR is being deleted. The operand namespace
+	// is resolved first, as the operator may have restarted since the namespace was last configured.
+	if !agent.DeletionTimestamp.IsZero() {
+		namespace, err := utils.ResolveOperandNamespace(ctx, r.ctrlClient, agent.Status.ManagedResources)
+		if err != nil {
+			r.log.Error(err, "failed to resolve the operand namespace")
+			return ctrl.Result{}, err
+		}
+		return r.reconcileDeletion(ctx, &agent, namespace)
+	}
+
+	// Leave the managed resources untouched while reconciliation is paused
+	if utils.StringToBool(agent.Spec.Paused) {
+		r.log.Info("SpireAgent reconciliation is paused, skipping", "name", agent.Name)
+		if err := status.SetPausedStatus(ctx, r.ctrlClient, &agent, func() *v1alpha1.ConditionalStatus {
+			return &agent.Status.ConditionalStatus
+		}); err != nil {
+			r.log.Error(err, "failed to update status")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Set Ready to false at the start of reconciliation
 	status.SetInitialReconciliationStatus(ctx, r.ctrlClient, &agent, func() *v1alpha1.ConditionalStatus {
 		return &agent.Status.ConditionalStatus
 	}, "SpireAgent")
 
+	var dryRun *customClient.DryRunClient
+	var adoption *customClient.AdoptionClient
+	var exclusion *customClient.ExclusionClient
 	statusMgr := status.NewManager(r.ctrlClient)
 	defer func() {
+		statusMgr.SetDryRunStatus(r.eventRecorder, &agent, agent.Status.ConditionalStatus.Conditions, dryRun)
+		statusMgr.SetExcludedResourcesStatus(agent.Status.ConditionalStatus.Conditions, exclusion)
+		statusMgr.SetDegradedCondition(agent.Status.ConditionalStatus.Conditions, r.failures.Observe(req.NamespacedName, reconcileErr), reconcileErr)
+		statusMgr.RecordResourcesAdopted(adoption)
 		if err := statusMgr.ApplyStatus(ctx, &agent, func() *v1alpha1.ConditionalStatus {
 			return &agent.Status.ConditionalStatus
 		}); err != nil {
@@ -105,6 +143,22 @@ func (r *SpireAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Install the operands in the namespace configured on the ZTWIM
+	if err := utils.ConfigureOperandNamespace(ztwim.Spec.OperandNamespace); err != nil {
+		r.log.Error(err, "invalid operand namespace")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed, err.Error(), metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
+	// Leave the operand to the operator running in the other cluster of the topology
+	if !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireAgent) {
+		r.log.Info("SpireAgent is not deployed in this cluster, skipping", "topology", utils.GetTopologyMode(&ztwim))
+		statusMgr.AddCondition(v1alpha1.Ready, utils.ConditionReasonNotDeployedInTopology,
+			fmt.Sprintf("SpireAgent is not deployed in the %s topology", utils.GetTopologyMode(&ztwim)),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Set ZTWIM as the owner of SpireAgent only if needed
 	if utils.NeedsOwnerReferenceUpdate(&agent, &ztwim) {
 		if err := controllerutil.SetControllerReference(&ztwim, &agent, r.scheme); err != nil {
@@ -125,11 +179,70 @@ func (r *SpireAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
+	// Add the cleanup finalizer so that deleting the CR tears down the managed resources in order
+	if controllerutil.AddFinalizer(&agent, utils.OperandCleanupFinalizer) {
+		if err := r.ctrlClient.Update(ctx, &agent); err != nil {
+			r.log.Error(err, "failed to add finalizer to SpireAgent")
+			statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+				fmt.Sprintf("Failed to add finalizer to SpireAgent: %v", err),
+				metav1.ConditionFalse)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In dry-run mode, record the writes made below as planned changes instead of applying them
+	if utils.IsDryRunMode(agent.Spec.ReconcileMode) {
+		r.log.Info("Running in dry-run mode - planned changes are reported in status and not applied")
+		dryRun = customClient.NewDryRunClient(r.ctrlClient, r.scheme)
+		dryRunReconciler := *r
+		dryRunReconciler.ctrlClient = dryRun
+		r = &dryRunReconciler
+	}
+
+	// Adopt the resources of the same name created by another installer, e.g. the SPIRE Helm charts
+	if utils.StringToBool(agent.Spec.AdoptExistingResources) {
+		adoption = customClient.NewAdoptionClient(r.ctrlClient)
+		adoptionReconciler := *r
+		adoptionReconciler.ctrlClient = adoption
+		r = &adoptionReconciler
+	}
+
+	// Leave the resources excluded from management to their owner
+	if len(agent.Spec.ExcludedResources) > 0 {
+		exclusion = customClient.NewExclusionClient(r.ctrlClient, r.scheme, agent.Spec.ExcludedResources)
+		exclusionReconciler := *r
+		exclusionReconciler.ctrlClient = exclusion
+		r = &exclusionReconciler
+	}
+
+	// Explain the significant reconcile actions in events on the CR
+	statusMgr.SetEventRecorder(r.eventRecorder, &agent, dryRun != nil)
+
+	// Leave the managed resources as they are or delete them as set by the management state
+	managementState := utils.GetManagementState(agent.Spec.ManagementState, &ztwim)
+	statusMgr.SetManagementStateCondition(managementState, "SpireAgent", agent.Status.Conditions)
+	switch managementState {
+	case v1alpha1.ManagementStateUnmanaged:
+		r.log.Info("SpireAgent is unmanaged, skipping", "name", agent.Name)
+		return ctrl.Result{}, nil
+	case v1alpha1.ManagementStateRemoved:
+		return ctrl.Result{}, r.reconcileRemoval(ctx, &agent, utils.OperandNamespaceOf(&ztwim), statusMgr)
+	}
+
 	// Handle create-only mode
 	createOnlyMode := r.handleCreateOnlyMode(&agent, statusMgr)
 
+	// Wait for the cluster to pass the preflight checks of the ZeroTrustWorkloadIdentityManager before the first deployment
+	if message, blocked := utils.PreflightChecksBlockDeployment(&ztwim, agent.Status.Conditions, DaemonSetAvailable); blocked {
+		r.log.Info("Waiting for the preflight checks to pass before deploying SpireAgent", "message", message)
+		statusMgr.AddCondition(v1alpha1.Ready, utils.PreflightChecksFailed,
+			fmt.Sprintf("Waiting for the preflight checks of the ZeroTrustWorkloadIdentityManager: %s", message),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Validate configuration (including proxy)
-	if err := r.validateConfiguration(ctx, &agent, statusMgr); err != nil {
+	if err := r.validateConfiguration(ctx, &agent, statusMgr, &ztwim); err != nil {
 		return ctrl.Result{}, nil
 	}
 
@@ -158,11 +271,28 @@ func (r *SpireAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	// Reconcile DaemonSet
-	if err := r.reconcileDaemonSet(ctx, &agent, statusMgr, &ztwim, createOnlyMode, configHash); err != nil {
+	soakRemaining, err := r.reconcileDaemonSet(ctx, &agent, statusMgr, &ztwim, createOnlyMode, configHash)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Prune resources from the previous inventory that the current spec no longer generates
+	pruned, err := statusMgr.PruneOrphanedResources(ctx, r.ctrlClient, r.scheme, agent.Status.ManagedResources, createOnlyMode, dryRun != nil)
+	if err != nil {
+		r.log.Error(err, "failed to prune orphaned resources")
 		return ctrl.Result{}, err
 	}
+	for _, resource := range pruned {
+		r.log.Info("Pruned orphaned resource", "kind", resource.Kind, "namespace", resource.Namespace, "name", resource.Name)
+	}
 
-	return ctrl.Result{}, nil
+	// Requeue periodically so that drift from the desired state is repaired, and when the canary agents
+	// of a staged rollout finished soaking
+	requeueAfter := utils.ResyncInterval(agent.Spec.ResyncInterval, ztwim.Spec.ResyncInterval)
+	if soakRemaining > 0 && (requeueAfter == 0 || soakRemaining < requeueAfter) {
+		requeueAfter = soakRemaining
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *SpireAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -183,6 +313,10 @@ func (r *SpireAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.SpireAgent{}, builder.WithPredicates(utils.GenerationOrOwnerReferenceChangedPredicate)).
 		Named(utils.ZeroTrustWorkloadIdentityManagerSpireAgentControllerName).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: utils.MaxConcurrentReconciles(utils.ZeroTrustWorkloadIdentityManagerSpireAgentControllerName),
+			RateLimiter:             utils.NewRateLimiter(),
+		}).
 		Watches(&appsv1.DaemonSet{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
@@ -190,8 +324,11 @@ func (r *SpireAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&securityv1.SecurityContextConstraints{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		// The SPIRE server rollout gates the rollout of new agent versions
+		Watches(&appsv1.StatefulSet{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(utils.ControllerManagedResourcesForComponent(utils.ComponentControlPlane))).
 		Watches(&v1alpha1.ZeroTrustWorkloadIdentityManager{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(utils.ZTWIMSpecChangedPredicate)).
-		Complete(r)
+		WatchesRawSource(source.Channel(operatorconfig.Subscribe(), handler.EnqueueRequestsFromMapFunc(mapFunc))).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerSpireAgentControllerName, r))
 	if err != nil {
 		return err
 	}
@@ -218,12 +355,167 @@ func (r *SpireAgentReconciler) handleCreateOnlyMode(agent *v1alpha1.SpireAgent,
 }
 
 // validateConfiguration validates SpireAgent configuration including proxy settings
-func (r *SpireAgentReconciler) validateConfiguration(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager) error {
+func (r *SpireAgentReconciler) validateConfiguration(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
 	// Validate proxy configuration - if proxy is enabled, CA bundle ConfigMap must be configured
 	if err := r.validateProxyConfiguration(statusMgr); err != nil {
 		return err
 	}
 
+	// Validate the free-form extra config merged into agent.conf
+	if _, err := utils.DecodeExtraConfig(agent.Spec.ExtraConfig); err != nil {
+		r.log.Error(err, "Invalid extra configuration in SpireAgent configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidExtraConfig",
+			fmt.Sprintf("Extra configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the experimental settings rendered into the experimental block of agent.conf
+	if err := utils.ValidateExperimentalConfig(agent.Spec.Experimental); err != nil {
+		r.log.Error(err, "Invalid experimental settings in SpireAgent configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidExperimentalConfig",
+			fmt.Sprintf("Experimental settings validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// SPIRE refuses the agent configuration with a too short availability_target
+	if err := validateAvailabilityTarget(agent.Spec); err != nil {
+		r.log.Error(err, "Invalid availability target in SpireAgent configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidAvailabilityTarget",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// The HostedCluster topology requires the address of the SPIRE server of the management cluster
+	if err := utils.ValidateTopology(ztwim.Spec.Topology); err != nil {
+		r.log.Error(err, "Invalid topology configuration")
+		statusMgr.AddCondition(ConfigurationValid, utils.ConditionReasonInvalidTopology,
+			fmt.Sprintf("Topology validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// The trust domain and the SPIRE server address derive from the ZeroTrustWorkloadIdentityManager,
+	// extraConfig must not override them
+	if err := validateAgentExtraConfigGlobals(agent, ztwim); err != nil {
+		r.log.Error(err, "Extra configuration conflicts with the ZeroTrustWorkloadIdentityManager")
+		statusMgr.AddCondition(ConfigurationValid, "ConflictingExtraConfig",
+			fmt.Sprintf("Extra configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// The health checks and the metrics of the agent are served on separate listeners
+	if getHealthPort(agent.Spec) == getMetricsPort(agent.Spec) {
+		err := fmt.Errorf("healthPort and metricsPort must be different, both are %d", getHealthPort(agent.Spec))
+		r.log.Error(err, "Invalid ports in SpireAgent configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidPorts",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the admin socket, which must not be exposed to the workloads
+	if err := validateAdminAPI(agent.Spec); err != nil {
+		r.log.Error(err, "Invalid admin API in SpireAgent configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidAdminAPI",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the extra volumes mounted into the SPIRE agent pods
+	if err := utils.ValidateExtraVolumes(agent.Spec.ExtraVolumes, agent.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "Invalid extra volumes in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraVolumes,
+			fmt.Sprintf("Extra volumes validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the environment variables set in the SPIRE agent container
+	if err := utils.ValidateContainerEnv(agent.Spec.Env, agent.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "Invalid environment variables in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidEnv,
+			fmt.Sprintf("Environment variables validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the security context overrides of the SPIRE agent pods, which access host paths
+	if err := utils.ValidateSecurityContext(agent.Spec.SecurityContext, agent.Spec.PodSecurityContext, true); err != nil {
+		r.log.Error(err, "Invalid security context in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidSecurityContext,
+			fmt.Sprintf("Security context validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the extra containers added to the SPIRE agent pods
+	if err := utils.ValidateExtraContainers(agent.Spec.ExtraContainers, agent.Spec.ExtraInitContainers); err != nil {
+		r.log.Error(err, "Invalid extra containers in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraContainers,
+			fmt.Sprintf("Extra containers validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the image pull secrets of the SPIRE agent pods
+	if err := utils.ValidateImagePullSecrets(agent.Spec.ImagePullSecrets); err != nil {
+		r.log.Error(err, "Invalid image pull secrets in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidImagePullSecrets,
+			fmt.Sprintf("Image pull secrets validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the annotations of the SPIRE agent ServiceAccount
+	if err := utils.ValidateServiceAccountAnnotations(agent.Spec.ServiceAccountAnnotations); err != nil {
+		r.log.Error(err, "Invalid ServiceAccount annotations in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidServiceAccountAnnotations,
+			fmt.Sprintf("ServiceAccount annotations validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the node platform of the SPIRE agent pods against the operand images and their node selector
+	if err := utils.ValidateNodePlatform(agent.Spec.NodeSelector, agent.Spec.NodePlatform); err != nil {
+		r.log.Error(err, "Invalid node platform in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidNodePlatform,
+			fmt.Sprintf("Node platform validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the SPIRE version the SPIRE agents are pinned to
+	if err := utils.SpireAgentOperand.ValidateVersion(agent.Spec.Version); err != nil {
+		r.log.Error(err, "Invalid version in SpireAgent configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidVersion,
+			fmt.Sprintf("Version validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Refuse to fall back to the non-FIPS image when running in FIPS mode
+	if err := utils.ValidateFIPSImages(utils.SpireAgentOperand.FIPSImageEnvFor(agent.Spec.Version)); err != nil {
+		r.log.Error(err, "FIPS image of the SPIRE agent is not set")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonFIPSImageNotSet,
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the rendered config last, so that the invalid settings of the spec are reported first
+	if err := validateRenderedAgentConfig(agent, ztwim); err != nil {
+		r.log.Error(err, "Rendered SPIRE agent configuration is invalid")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidRenderedConfiguration",
+			fmt.Sprintf("Rendered SPIRE agent configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	return utils.ValidateAndUpdateStatus(
 		r.log,
 		statusMgr,
@@ -6,12 +6,16 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr"
+	securityv1 "github.com/openshift/api/security/v1"
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -468,7 +472,7 @@ func TestValidateConfiguration_ValidConfig(t *testing.T) {
 	}
 
 	statusMgr := status.NewManager(fakeClient)
-	err := reconciler.validateConfiguration(context.Background(), agent, statusMgr)
+	err := reconciler.validateConfiguration(context.Background(), agent, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 	// With default/empty config, validation should pass
 	if err != nil {
@@ -498,7 +502,7 @@ func TestValidateConfiguration_InvalidAffinity(t *testing.T) {
 	}
 
 	statusMgr := status.NewManager(fakeClient)
-	err := reconciler.validateConfiguration(context.Background(), agent, statusMgr)
+	err := reconciler.validateConfiguration(context.Background(), agent, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 	// Invalid affinity should return error
 	if err == nil {
@@ -621,6 +625,57 @@ func TestReconcile_FullFlow(t *testing.T) {
 	t.Log("Reconcile completed without panic")
 }
 
+// TestReconcile_WaitsForPreflightChecks tests that the SPIRE agents are not deployed while the
+// preflight checks of the ZeroTrustWorkloadIdentityManager fail
+func TestReconcile_WaitsForPreflightChecks(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	reconciler := newTestReconciler(fakeClient)
+	reconciler.scheme = scheme
+
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	ztwim.Status.Conditions = []metav1.Condition{{
+		Type:    utils.PreflightChecksPassedStatusType,
+		Status:  metav1.ConditionFalse,
+		Reason:  utils.PreflightChecksFailed,
+		Message: "The cluster does not meet the prerequisites of the operands: PreflightPodSecurity",
+	}}
+	var agent v1alpha1.SpireAgent
+	fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		switch v := obj.(type) {
+		case *v1alpha1.SpireAgent:
+			agent.DeepCopyInto(v)
+			return nil
+		case *v1alpha1.ZeroTrustWorkloadIdentityManager:
+			*v = *ztwim
+			return nil
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+	fakeClient.StatusUpdateWithRetryStub = func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+		obj.(*v1alpha1.SpireAgent).DeepCopyInto(&agent)
+		return nil
+	}
+	agent = v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if fakeClient.CreateCallCount() != 0 {
+		t.Errorf("Expected no resource to be created, got %d creates", fakeClient.CreateCallCount())
+	}
+	ready := apimeta.FindStatusCondition(agent.Status.Conditions, v1alpha1.Ready)
+	if ready == nil || ready.Reason != utils.PreflightChecksFailed {
+		t.Errorf("Expected Ready to report the failed preflight checks, got %+v", ready)
+	}
+}
+
 // TestSpireAgentReconciler_Fields tests SpireAgentReconciler struct fields
 func TestSpireAgentReconciler_Fields(t *testing.T) {
 	fakeClient := &fakes.FakeCustomCtrlClient{}
@@ -1047,7 +1102,7 @@ func TestValidateConfiguration_AllScenarios(t *testing.T) {
 			reconciler := newTestReconciler(fakeClient)
 			statusMgr := status.NewManager(fakeClient)
 
-			err := reconciler.validateConfiguration(context.Background(), tt.agent, statusMgr)
+			err := reconciler.validateConfiguration(context.Background(), tt.agent, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 			if tt.expectError && err == nil {
 				t.Fatal("Expected error but got nil")
@@ -1107,7 +1162,7 @@ func TestValidateConfiguration_ConditionUpdate(t *testing.T) {
 				}
 			}
 
-			err := reconciler.validateConfiguration(context.Background(), agent, statusMgr)
+			err := reconciler.validateConfiguration(context.Background(), agent, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 			// validateConfiguration should succeed regardless of existing condition state
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
@@ -1208,6 +1263,8 @@ func TestReconcile_FullFlow_AllScenarios(t *testing.T) {
 			_ = v1alpha1.AddToScheme(scheme)
 			_ = corev1.AddToScheme(scheme)
 			_ = appsv1.AddToScheme(scheme)
+			_ = rbacv1.AddToScheme(scheme)
+			_ = securityv1.AddToScheme(scheme)
 
 			reconciler := &SpireAgentReconciler{
 				ctrlClient:    fakeClient,
@@ -1507,20 +1564,15 @@ func TestReconcileDaemonSet_AllScenarios(t *testing.T) {
 				return nil
 			}
 
+			// Both the create and the update go through server-side apply
 			if tt.createErr != nil {
-				fakeClient.CreateReturns(tt.createErr)
-			} else {
-				fakeClient.CreateReturns(nil)
-			}
-
-			if tt.updateErr != nil {
-				fakeClient.UpdateReturns(tt.updateErr)
+				fakeClient.ApplyReturns(tt.createErr)
 			} else {
-				fakeClient.UpdateReturns(nil)
+				fakeClient.ApplyReturns(tt.updateErr)
 			}
 
 			statusMgr := status.NewManager(fakeClient)
-			err := reconciler.reconcileDaemonSet(context.Background(), agent, statusMgr, ztwim, tt.createOnlyMode, "test-hash")
+			_, err := reconciler.reconcileDaemonSet(context.Background(), agent, statusMgr, ztwim, tt.createOnlyMode, "test-hash")
 
 			if tt.expectError && err == nil {
 				t.Fatal("Expected error but got nil")
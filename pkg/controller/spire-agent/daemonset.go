@@ -3,6 +3,9 @@ package spire_agent
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -17,62 +20,119 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
-// reconcileDaemonSet reconciles the Spire Agent DaemonSet
-func (r *SpireAgentReconciler) reconcileDaemonSet(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool, configHash string) error {
+// reconcileDaemonSet reconciles the Spire Agent DaemonSet, and the canary DaemonSet of a staged rollout.
+// It returns how long the staged rollout waits for the canary agents to soak.
+func (r *SpireAgentReconciler) reconcileDaemonSet(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool, configHash string) (time.Duration, error) {
+	_, renderSpan := tracing.Start(ctx, "Render SPIRE agent DaemonSet")
 	spireAgentDaemonset := generateSpireAgentDaemonSet(agent.Spec, ztwim, configHash)
+	tracing.End(renderSpan, nil)
+	// Size the pods for the profile of the cluster before the user provided containers are added
+	utils.ApplyDeploymentProfile(&spireAgentDaemonset.Spec.Template.Spec, ztwim)
+	// The liveness probe timings of the spec take precedence over the profile
+	utils.ApplyProbeTimings(spireAgentDaemonset.Spec.Template.Spec.Containers[0].LivenessProbe, agent.Spec.LivenessProbe)
+	if err := utils.AddExtraVolumes(&spireAgentDaemonset.Spec.Template.Spec, "spire-agent", agent.Spec.ExtraVolumes, agent.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "failed to add the extra volumes")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return 0, err
+	}
+	if err := utils.AddContainerEnv(&spireAgentDaemonset.Spec.Template.Spec, "spire-agent", agent.Spec.Env, agent.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "failed to add the environment variables")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return 0, err
+	}
+	if err := utils.ApplySecurityContext(&spireAgentDaemonset.Spec.Template.Spec, "spire-agent", agent.Spec.SecurityContext, agent.Spec.PodSecurityContext); err != nil {
+		r.log.Error(err, "failed to apply the security context overrides")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return 0, err
+	}
+	if err := utils.AddExtraContainers(&spireAgentDaemonset.Spec.Template.Spec, agent.Spec.ExtraContainers, agent.Spec.ExtraInitContainers); err != nil {
+		r.log.Error(err, "failed to add the extra containers")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return 0, err
+	}
 	if err := controllerutil.SetControllerReference(agent, spireAgentDaemonset, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference")
 		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGenerationFailed",
 			err.Error(),
 			metav1.ConditionFalse)
-		return err
+		return 0, err
 	}
+	statusMgr.TrackResource(spireAgentDaemonset)
 
 	var existingSpireAgentDaemonSet appsv1.DaemonSet
 	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: spireAgentDaemonset.Name, Namespace: spireAgentDaemonset.Namespace}, &existingSpireAgentDaemonSet)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get spire-agent daemonset")
+		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGetFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return 0, err
+	}
+
+	// Roll the changes out to the canary nodes first when a staged rollout is configured
+	existing := &existingSpireAgentDaemonSet
+	if err != nil {
+		existing = nil
+	}
+	canaryPending, requeueAfter, stageErr := r.reconcileStagedRollout(ctx, agent, spireAgentDaemonset, existing, statusMgr, createOnlyMode)
+	if stageErr != nil {
+		return 0, stageErr
+	}
+
 	if err != nil && kerrors.IsNotFound(err) {
-		if err = r.ctrlClient.Create(ctx, spireAgentDaemonset); err != nil {
+		if err = r.ctrlClient.Apply(ctx, spireAgentDaemonset); err != nil {
 			r.log.Error(err, "failed to create spire-agent daemonset")
 			statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetCreationFailed",
 				err.Error(),
 				metav1.ConditionFalse)
-			return fmt.Errorf("failed to create DaemonSet: %w", err)
+			return 0, fmt.Errorf("failed to create DaemonSet: %w", err)
 		}
 		r.log.Info("Created spire agent DaemonSet")
+		statusMgr.RecordResourceCreated(spireAgentDaemonset)
 	} else if err == nil && needsUpdate(existingSpireAgentDaemonSet, *spireAgentDaemonset) {
 		if createOnlyMode {
 			r.log.Info("Skipping DaemonSet update due to create-only mode")
+		} else if canaryPending {
+			r.log.Info("Skipping DaemonSet update until the canary nodes verified the new revision")
+		} else if !r.upgradeAllowed(ctx, agent, &existingSpireAgentDaemonSet, spireAgentDaemonset, statusMgr) {
+			r.log.Info("Skipping DaemonSet update until the SPIRE agent version change is safe")
 		} else {
-			spireAgentDaemonset.ResourceVersion = existingSpireAgentDaemonSet.ResourceVersion
-			if err = r.ctrlClient.Update(ctx, spireAgentDaemonset); err != nil {
+			if err = r.ctrlClient.Apply(ctx, spireAgentDaemonset); err != nil {
 				r.log.Error(err, "failed to update spire agent DaemonSet")
 				statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetUpdateFailed",
 					err.Error(),
 					metav1.ConditionFalse)
-				return fmt.Errorf("failed to update DaemonSet: %w", err)
+				return 0, fmt.Errorf("failed to update DaemonSet: %w", err)
 			}
 			r.log.Info("Updated spire agent DaemonSet")
+			statusMgr.RecordWorkloadUpdated(spireAgentDaemonset, &existingSpireAgentDaemonSet.Spec.Template, &spireAgentDaemonset.Spec.Template,
+				spireAgentDaemonSetSpireAgentConfigHashAnnotationKey)
 		}
-	} else if err != nil {
-		r.log.Error(err, "failed to get spire-agent daemonset")
-		statusMgr.AddCondition(DaemonSetAvailable, "SpireAgentDaemonSetGetFailed",
-			err.Error(),
-			metav1.ConditionFalse)
-		return err
 	}
 
 	// Check DaemonSet health/readiness
 	statusMgr.CheckDaemonSetHealth(ctx, spireAgentDaemonset.Name, spireAgentDaemonset.Namespace, DaemonSetAvailable)
 
-	return nil
+	return requeueAfter, nil
 }
 
 func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, spireAgentConfigHash string) *appsv1.DaemonSet {
 
 	// Generate standardized labels once and reuse them
 	labels := utils.SpireAgentLabels(config.Labels)
+	labels[utils.VersionLabelKey] = utils.SpireAgentOperand.Version(config.Version)
+	hostNetwork := useHostNetwork(config)
 
 	// For selectors, we need only the core identifying labels (without custom user labels)
 	selectorLabels := map[string]string{
@@ -96,7 +156,7 @@ func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1
 				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "spire-agent"}},
 			},
 		},
-		{Name: "spire-agent-admin-socket-dir", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: "spire-agent-admin-socket-dir", VolumeSource: getAdminSocketVolumeSource(config.AdminAPI)},
 		{Name: "spire-agent-persistence", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
 		{
 			Name: "spire-bundle",
@@ -113,7 +173,7 @@ func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1
 							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
 								Path:              "spire-agent",
 								ExpirationSeconds: ptr.To(int64(7200)),
-								Audience:          "spire-server",
+								Audience:          getK8sPSATAudience(config.NodeAttestor),
 							},
 						},
 					},
@@ -131,6 +191,10 @@ func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1
 		},
 	}
 
+	if isAdminAPIEnabled(config.AdminAPI) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "spire-agent-admin-socket-dir", MountPath: agentAdminSocketMountPath})
+	}
+
 	// Conditionally add kubelet CA hostPath mount for hostCert verification mode
 	if hostCertPath := getHostCertMountPath(config.WorkloadAttestors); hostCertPath != "" {
 		volumes = append(volumes, corev1.Volume{
@@ -152,36 +216,34 @@ func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1
 	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-agent",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    labels,
 		},
 		Spec: appsv1.DaemonSetSpec{
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
-			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
-				Type: appsv1.RollingUpdateDaemonSetStrategyType,
-				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
-					MaxUnavailable: &intstr.IntOrString{IntVal: 1},
-				},
-			},
+			UpdateStrategy: getUpdateStrategy(config.UpdateStrategy),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						"kubectl.kubernetes.io/default-container":            "spire-agent",
 						spireAgentDaemonSetSpireAgentConfigHashAnnotationKey: spireAgentConfigHash,
+						utils.RequiredSCCAnnotationKey:                       utils.GetSecurityContextConstraintsName(config.SecurityContextConstraints, "spire-agent"),
 					},
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
 					HostPID:            true,
-					HostNetwork:        true,
-					DNSPolicy:          corev1.DNSClusterFirstWithHostNet,
+					HostNetwork:        hostNetwork,
+					DNSPolicy:          getDNSPolicy(hostNetwork),
 					ServiceAccountName: "spire-agent",
+					PriorityClassName:  utils.GetPriorityClassName(config.PriorityClassName, utils.SystemNodeCriticalPriorityClassName),
+					ImagePullSecrets:   config.ImagePullSecrets,
 					Containers: []corev1.Container{
 						{
 							Name:            "spire-agent",
-							Image:           utils.GetSpireAgentImage(),
+							Image:           utils.SpireAgentOperand.Image(config.Version),
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Args:            []string{"-config", "/opt/spire/conf/agent/agent.conf"},
 							Env: []corev1.EnvVar{
@@ -194,37 +256,20 @@ func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1
 								},
 							},
 							Ports: []corev1.ContainerPort{
-								{Name: "healthz", ContainerPort: 9982},
-							},
-							LivenessProbe: &corev1.Probe{
-								InitialDelaySeconds: 15,
-								PeriodSeconds:       60,
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path: "/live",
-										Port: intstr.FromString("healthz"),
-									},
-								},
-							},
-							ReadinessProbe: &corev1.Probe{
-								InitialDelaySeconds: 10,
-								PeriodSeconds:       30,
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Path: "/ready",
-										Port: intstr.FromString("healthz"),
-									},
-								},
+								{Name: "healthz", ContainerPort: getHealthPort(config)},
 							},
-							VolumeMounts: volumeMounts,
-							Resources:    utils.DerefResourceRequirements(config.Resources),
+							LivenessProbe:  getLivenessProbe(config),
+							ReadinessProbe: getReadinessProbe(config),
+							StartupProbe:   getStartupProbe(config),
+							VolumeMounts:   volumeMounts,
+							Resources:      utils.DerefResourceRequirements(config.Resources),
 							SecurityContext: &corev1.SecurityContext{
 								Privileged: ptr.To(true),
 							},
 						},
 					},
-					Affinity:     config.Affinity,
-					NodeSelector: utils.DerefNodeSelector(config.NodeSelector),
+					Affinity:     utils.PlatformAffinity(config.Affinity, config.NodePlatform),
+					NodeSelector: utils.PlatformNodeSelector(config.NodeSelector, config.NodePlatform),
 					Tolerations:  utils.DerefTolerations(config.Tolerations),
 					Volumes:      volumes,
 				},
@@ -241,6 +286,139 @@ func generateSpireAgentDaemonSet(config v1alpha1.SpireAgentSpec, ztwim *v1alpha1
 	return ds
 }
 
+// Ports of the SPIRE agent used when the spec does not set them
+const (
+	defaultAgentHealthPort  int32 = 9982
+	defaultAgentMetricsPort int32 = 9402
+)
+
+const (
+	// agentAdminSocketMountPath is where the admin socket directory is mounted in the agent container
+	agentAdminSocketMountPath = "/tmp/spire-agent/private"
+
+	// defaultAgentAdminSocketPath is the directory of the admin socket on the host when spec.adminAPI.socketPath is unset
+	defaultAgentAdminSocketPath = "/run/spire/agent-admin"
+)
+
+// validateAdminAPI validates the admin socket configuration. SPIRE refuses an admin socket in the
+// directory of the workload socket, which the workloads mount.
+func validateAdminAPI(config v1alpha1.SpireAgentSpec) error {
+	if !isAdminAPIEnabled(config.AdminAPI) {
+		return nil
+	}
+	socketPath := config.AdminAPI.SocketPath
+	if socketPath == "" {
+		socketPath = defaultAgentAdminSocketPath
+	}
+	if path.Clean(socketPath) == path.Clean(config.SocketPath) {
+		return fmt.Errorf("adminAPI.socketPath must differ from socketPath %s", config.SocketPath)
+	}
+	for _, delegate := range config.AdminAPI.AuthorizedDelegates {
+		if !strings.HasPrefix(delegate, "spiffe://") {
+			return fmt.Errorf("adminAPI.authorizedDelegates entry %q is not a SPIFFE ID", delegate)
+		}
+	}
+	return nil
+}
+
+// useHostNetwork reports whether the agent pods run in the host network namespace, which they do unless disabled
+func useHostNetwork(config v1alpha1.SpireAgentSpec) bool {
+	return config.HostNetwork != "false"
+}
+
+// getDNSPolicy returns the DNS policy resolving the cluster services from the agent pods
+func getDNSPolicy(hostNetwork bool) corev1.DNSPolicy {
+	if hostNetwork {
+		return corev1.DNSClusterFirstWithHostNet
+	}
+	return corev1.DNSClusterFirst
+}
+
+// getHealthPort returns the port the agent serves its health endpoints on
+func getHealthPort(config v1alpha1.SpireAgentSpec) int32 {
+	if config.HealthPort != 0 {
+		return config.HealthPort
+	}
+	return defaultAgentHealthPort
+}
+
+// getLivenessProbe returns the liveness probe of the agent with the timings of the spec applied over the defaults
+func getLivenessProbe(config v1alpha1.SpireAgentSpec) *corev1.Probe {
+	probe := &corev1.Probe{
+		InitialDelaySeconds: 15,
+		PeriodSeconds:       60,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/live",
+				Port: intstr.FromString("healthz"),
+			},
+		},
+	}
+	utils.ApplyProbeTimings(probe, config.LivenessProbe)
+	return probe
+}
+
+// getReadinessProbe returns the readiness probe of the agent with the timings of the spec applied over the defaults
+func getReadinessProbe(config v1alpha1.SpireAgentSpec) *corev1.Probe {
+	probe := &corev1.Probe{
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       30,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/ready",
+				Port: intstr.FromString("healthz"),
+			},
+		},
+	}
+	utils.ApplyProbeTimings(probe, config.ReadinessProbe)
+	return probe
+}
+
+// getStartupProbe returns the startup probe of the agent, only set when the spec configures it
+func getStartupProbe(config v1alpha1.SpireAgentSpec) *corev1.Probe {
+	if config.StartupProbe == nil {
+		return nil
+	}
+	probe := &corev1.Probe{
+		PeriodSeconds:    10,
+		FailureThreshold: 30,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/live",
+				Port: intstr.FromString("healthz"),
+			},
+		},
+	}
+	utils.ApplyProbeTimings(probe, config.StartupProbe)
+	return probe
+}
+
+// getMetricsPort returns the port the agent serves its Prometheus metrics on
+func getMetricsPort(config v1alpha1.SpireAgentSpec) int32 {
+	if config.MetricsPort != 0 {
+		return config.MetricsPort
+	}
+	return defaultAgentMetricsPort
+}
+
+// getUpdateStrategy returns the update strategy of the agent DaemonSet.
+// Rolling updates replace one agent at a time unless maxUnavailable is set.
+func getUpdateStrategy(config *v1alpha1.DaemonSetUpdateStrategy) appsv1.DaemonSetUpdateStrategy {
+	if config != nil && config.Type == string(appsv1.OnDeleteDaemonSetStrategyType) {
+		return appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+	}
+	maxUnavailable := intstr.FromInt32(1)
+	if config != nil && config.MaxUnavailable != nil {
+		maxUnavailable = *config.MaxUnavailable
+	}
+	return appsv1.DaemonSetUpdateStrategy{
+		Type: appsv1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+}
+
 // getHostCertMountPath returns the host path to mount for kubelet CA verification.
 // Returns empty string if no host mount is needed (skip mode).
 // For auto mode without explicit paths, returns the OpenShift default path.
@@ -270,6 +448,32 @@ func getHostCertMountPath(workloadAttestors *v1alpha1.WorkloadAttestors) string
 	}
 }
 
+// getK8sPSATAudience returns the audience of the token the agent attests with, which the SPIRE server must accept
+func getK8sPSATAudience(nodeAttestor *v1alpha1.NodeAttestor) string {
+	if nodeAttestor != nil && nodeAttestor.K8sPSATAudience != "" {
+		return nodeAttestor.K8sPSATAudience
+	}
+	return utils.DefaultK8sPSATAudience
+}
+
+// getAdminSocketVolumeSource returns the volume of the admin socket directory, the directory on the host
+// when the admin socket is enabled so that the spire-agent CLI can reach it from the node
+func getAdminSocketVolumeSource(adminAPI *v1alpha1.AgentAdminAPIConfig) corev1.VolumeSource {
+	if !isAdminAPIEnabled(adminAPI) {
+		return corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	}
+	socketPath := adminAPI.SocketPath
+	if socketPath == "" {
+		socketPath = defaultAgentAdminSocketPath
+	}
+	return corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{
+			Path: socketPath,
+			Type: hostPathTypePtr(corev1.HostPathDirectoryOrCreate),
+		},
+	}
+}
+
 func hostPathTypePtr(t corev1.HostPathType) *corev1.HostPathType {
 	return &t
 }
@@ -6,6 +6,10 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 func TestGetHostCertMountPath(t *testing.T) {
@@ -156,3 +160,297 @@ func TestHostPathTypePtr(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, "DirectoryOrCreate", string(*result))
 }
+
+func TestGenerateSpireAgentDaemonSetPriorityClass(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	t.Run("defaults to system-node-critical", func(t *testing.T) {
+		ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+		assert.Equal(t, utils.SystemNodeCriticalPriorityClassName, ds.Spec.Template.Spec.PriorityClassName)
+	})
+
+	t.Run("uses configured priority class", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{
+			CommonConfig: v1alpha1.CommonConfig{PriorityClassName: "identity-critical"},
+		}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		assert.Equal(t, "identity-critical", ds.Spec.Template.Spec.PriorityClassName)
+	})
+}
+
+func TestGenerateSpireAgentDaemonSetUpdateStrategy(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	t.Run("defaults to rolling updates of one node at a time", func(t *testing.T) {
+		ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+		assert.Equal(t, appsv1.RollingUpdateDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+		assert.Equal(t, "1", ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable.String())
+	})
+
+	t.Run("uses configured maxUnavailable", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{
+			UpdateStrategy: &v1alpha1.DaemonSetUpdateStrategy{
+				Type:           "RollingUpdate",
+				MaxUnavailable: ptr.To(intstr.FromString("10%")),
+			},
+		}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		assert.Equal(t, appsv1.RollingUpdateDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+		assert.Equal(t, "10%", ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable.String())
+	})
+
+	t.Run("uses OnDelete without rolling update parameters", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{
+			UpdateStrategy: &v1alpha1.DaemonSetUpdateStrategy{Type: "OnDelete"},
+		}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		assert.Equal(t, appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+		assert.Nil(t, ds.Spec.UpdateStrategy.RollingUpdate)
+	})
+}
+
+func TestGenerateSpireAgentDaemonSetNetwork(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	t.Run("defaults to the host network", func(t *testing.T) {
+		ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+		assert.True(t, ds.Spec.Template.Spec.HostNetwork)
+		assert.Equal(t, corev1.DNSClusterFirstWithHostNet, ds.Spec.Template.Spec.DNSPolicy)
+		assert.Equal(t, defaultAgentHealthPort, ds.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)
+	})
+
+	t.Run("uses the pod network and configured health port", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{HostNetwork: "false", HealthPort: 19982}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		assert.False(t, ds.Spec.Template.Spec.HostNetwork)
+		assert.Equal(t, corev1.DNSClusterFirst, ds.Spec.Template.Spec.DNSPolicy)
+		assert.Equal(t, int32(19982), ds.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)
+	})
+}
+
+func TestGenerateSpireAgentDaemonSetNodePlatform(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	t.Run("defaults to the Linux nodes", func(t *testing.T) {
+		ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+		assert.Equal(t, map[string]string{corev1.LabelOSStable: "linux"}, ds.Spec.Template.Spec.NodeSelector)
+		assert.Nil(t, ds.Spec.Template.Spec.Affinity)
+	})
+
+	t.Run("requires the configured architectures", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{
+			CommonConfig: v1alpha1.CommonConfig{
+				NodePlatform: &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"arm64"}},
+			},
+		}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Len(t, terms, 1)
+		assert.Equal(t, []corev1.NodeSelectorRequirement{
+			{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"}},
+		}, terms[0].MatchExpressions)
+	})
+}
+
+func TestGenerateSpireAgentDaemonSetImagePullSecrets(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	config := v1alpha1.SpireAgentSpec{
+		CommonConfig: v1alpha1.CommonConfig{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}},
+		},
+	}
+	ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}}, ds.Spec.Template.Spec.ImagePullSecrets)
+}
+
+func TestGenerateSpireAgentDaemonSetVersion(t *testing.T) {
+	t.Setenv(utils.SpireAgentImageEnv, "ghcr.io/spiffe/spire-agent:1.13.3")
+	t.Setenv(utils.VersionedImageEnv(utils.SpireAgentImageEnv, "1.12.4"), "ghcr.io/spiffe/spire-agent:1.12.4")
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+	assert.Equal(t, "ghcr.io/spiffe/spire-agent:1.13.3", ds.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, utils.SpireAgentOperand.DefaultVersion, ds.Labels[utils.VersionLabelKey])
+
+	ds = generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{Version: "1.12"}, ztwim, "hash")
+	assert.Equal(t, "ghcr.io/spiffe/spire-agent:1.12.4", ds.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "1.12.4", ds.Labels[utils.VersionLabelKey])
+	assert.Equal(t, "1.12.4", ds.Spec.Template.Labels[utils.VersionLabelKey])
+}
+
+func TestGenerateSpireAgentDaemonSetRequiredSCC(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+	assert.Equal(t, "spire-agent", ds.Spec.Template.Annotations[utils.RequiredSCCAnnotationKey])
+
+	ds = generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{SecurityContextConstraints: "node-agents"}, ztwim, "hash")
+	assert.Equal(t, "node-agents", ds.Spec.Template.Annotations[utils.RequiredSCCAnnotationKey])
+}
+
+func TestGenerateSpireAgentDaemonSetAdminAPI(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	adminVolume := func(ds *appsv1.DaemonSet) corev1.Volume {
+		for _, volume := range ds.Spec.Template.Spec.Volumes {
+			if volume.Name == "spire-agent-admin-socket-dir" {
+				return volume
+			}
+		}
+		t.Fatal("admin socket volume not found")
+		return corev1.Volume{}
+	}
+	adminMounted := func(ds *appsv1.DaemonSet) bool {
+		for _, mount := range ds.Spec.Template.Spec.Containers[0].VolumeMounts {
+			if mount.Name == "spire-agent-admin-socket-dir" {
+				assert.Equal(t, "/tmp/spire-agent/private", mount.MountPath)
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+		assert.NotNil(t, adminVolume(ds).EmptyDir)
+		assert.False(t, adminMounted(ds))
+	})
+
+	t.Run("enabled with the default path", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{AdminAPI: &v1alpha1.AgentAdminAPIConfig{Enabled: "true"}}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		assert.Equal(t, &corev1.HostPathVolumeSource{
+			Path: "/run/spire/agent-admin",
+			Type: hostPathTypePtr(corev1.HostPathDirectoryOrCreate),
+		}, adminVolume(ds).HostPath)
+		assert.True(t, adminMounted(ds))
+	})
+
+	t.Run("enabled with a custom path", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{AdminAPI: &v1alpha1.AgentAdminAPIConfig{Enabled: "true", SocketPath: "/var/run/spire-admin"}}
+		ds := generateSpireAgentDaemonSet(config, ztwim, "hash")
+		assert.Equal(t, "/var/run/spire-admin", adminVolume(ds).HostPath.Path)
+	})
+}
+
+func TestValidateAdminAPI(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  v1alpha1.SpireAgentSpec
+		wantErr bool
+	}{
+		{
+			name:   "unset",
+			config: v1alpha1.SpireAgentSpec{SocketPath: "/run/spire/agent-sockets"},
+		},
+		{
+			name: "disabled with the workload socket path",
+			config: v1alpha1.SpireAgentSpec{
+				SocketPath: "/run/spire/agent-sockets",
+				AdminAPI:   &v1alpha1.AgentAdminAPIConfig{Enabled: "false", SocketPath: "/run/spire/agent-sockets"},
+			},
+		},
+		{
+			name: "enabled with delegates",
+			config: v1alpha1.SpireAgentSpec{
+				SocketPath: "/run/spire/agent-sockets",
+				AdminAPI:   &v1alpha1.AgentAdminAPIConfig{Enabled: "true", AuthorizedDelegates: []string{"spiffe://example.org/delegate"}},
+			},
+		},
+		{
+			name: "enabled with the workload socket path",
+			config: v1alpha1.SpireAgentSpec{
+				SocketPath: "/run/spire/agent-sockets",
+				AdminAPI:   &v1alpha1.AgentAdminAPIConfig{Enabled: "true", SocketPath: "/run/spire/agent-sockets/"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "enabled with a delegate that is not a SPIFFE ID",
+			config: v1alpha1.SpireAgentSpec{
+				SocketPath: "/run/spire/agent-sockets",
+				AdminAPI:   &v1alpha1.AgentAdminAPIConfig{Enabled: "true", AuthorizedDelegates: []string{"example.org/delegate"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAdminAPI(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenerateSpireAgentDaemonSetProbes(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		container := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash").Spec.Template.Spec.Containers[0]
+		assert.Equal(t, int32(15), container.LivenessProbe.InitialDelaySeconds)
+		assert.Equal(t, int32(60), container.LivenessProbe.PeriodSeconds)
+		assert.Equal(t, int32(30), container.ReadinessProbe.PeriodSeconds)
+		assert.Nil(t, container.StartupProbe)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		config := v1alpha1.SpireAgentSpec{
+			LivenessProbe:  &v1alpha1.ProbeTimings{FailureThreshold: ptr.To(int32(5))},
+			ReadinessProbe: &v1alpha1.ProbeTimings{PeriodSeconds: ptr.To(int32(10)), TimeoutSeconds: ptr.To(int32(3))},
+			StartupProbe:   &v1alpha1.ProbeTimings{FailureThreshold: ptr.To(int32(60))},
+		}
+		container := generateSpireAgentDaemonSet(config, ztwim, "hash").Spec.Template.Spec.Containers[0]
+		assert.Equal(t, int32(5), container.LivenessProbe.FailureThreshold)
+		assert.Equal(t, int32(60), container.LivenessProbe.PeriodSeconds, "unset timings keep their defaults")
+		assert.Equal(t, int32(10), container.ReadinessProbe.PeriodSeconds)
+		assert.Equal(t, int32(3), container.ReadinessProbe.TimeoutSeconds)
+		if assert.NotNil(t, container.StartupProbe) {
+			assert.Equal(t, "/live", container.StartupProbe.HTTPGet.Path)
+			assert.Equal(t, int32(10), container.StartupProbe.PeriodSeconds)
+			assert.Equal(t, int32(60), container.StartupProbe.FailureThreshold)
+		}
+	})
+}
+
+func TestGenerateSpireAgentDaemonSetK8sPSATAudience(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+	tokenAudience := func(ds *appsv1.DaemonSet) string {
+		for _, volume := range ds.Spec.Template.Spec.Volumes {
+			if volume.Name == "spire-token" {
+				return volume.Projected.Sources[0].ServiceAccountToken.Audience
+			}
+		}
+		t.Fatal("token volume not found")
+		return ""
+	}
+
+	ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, ztwim, "hash")
+	assert.Equal(t, "spire-server", tokenAudience(ds))
+
+	config := v1alpha1.SpireAgentSpec{NodeAttestor: &v1alpha1.NodeAttestor{K8sPSATEnabled: "true", K8sPSATAudience: "fleet.example.org"}}
+	ds = generateSpireAgentDaemonSet(config, ztwim, "hash")
+	assert.Equal(t, "fleet.example.org", tokenAudience(ds))
+}
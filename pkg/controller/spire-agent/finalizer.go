@@ -0,0 +1,82 @@
+package spire_agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// reconcileDeletion tears down the SPIRE agents before the SpireAgent CR is removed.
+// When the CSI driver or the OIDC discovery provider are being deleted as well, the agents
+// are kept until those are gone, since both rely on the agent workload API socket.
+// The DaemonSet is then deleted, and the remaining resources are garbage collected through
+// their owner references.
+func (r *SpireAgentReconciler) reconcileDeletion(ctx context.Context, agent *v1alpha1.SpireAgent, namespace string) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(agent, utils.OperandCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	getStatus := func() *v1alpha1.ConditionalStatus {
+		return &agent.Status.ConditionalStatus
+	}
+
+	waiting, err := utils.ObjectsBeingDeleted(ctx, r.ctrlClient,
+		&v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&v1alpha1.SpireOIDCDiscoveryProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+	)
+	if err != nil {
+		r.log.Error(err, "failed to check dependent operands")
+		return ctrl.Result{}, err
+	}
+	if len(waiting) > 0 {
+		r.log.Info("Waiting for dependent operands to be deleted", "operands", waiting)
+		if err := status.SetDeletionStatus(ctx, r.ctrlClient, agent, getStatus, utils.DeletionWaitingForDependents,
+			fmt.Sprintf("Waiting for %s to be deleted", strings.Join(waiting, ", "))); err != nil {
+			r.log.Error(err, "failed to update status")
+		}
+		return ctrl.Result{RequeueAfter: utils.DeletionRequeueInterval}, nil
+	}
+
+	if err := status.SetDeletionStatus(ctx, r.ctrlClient, agent, getStatus, utils.DeletionDeletingResources,
+		"Deleting the SPIRE agent resources"); err != nil {
+		r.log.Error(err, "failed to update status")
+	}
+	if err := utils.DeleteObjects(ctx, r.ctrlClient,
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: namespace}},
+	); err != nil {
+		r.log.Error(err, "failed to delete SPIRE agent resources")
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(agent, utils.OperandCleanupFinalizer)
+	if err := r.ctrlClient.Update(ctx, agent); err != nil {
+		r.log.Error(err, "failed to remove finalizer from SpireAgent")
+		return ctrl.Result{}, err
+	}
+	r.log.Info("SPIRE agent resources deleted, removed finalizer", "name", agent.Name)
+	return ctrl.Result{}, nil
+}
+
+// reconcileRemoval deletes the resources managed for the SpireAgent while keeping the CR, when its
+// management state is Removed. The DaemonSet is deleted first, as in the teardown.
+func (r *SpireAgentReconciler) reconcileRemoval(ctx context.Context, agent *v1alpha1.SpireAgent, namespace string, statusMgr *status.Manager) error {
+	if err := statusMgr.RemoveManagedResources(ctx, r.ctrlClient, r.scheme, agent.Status.ManagedResources,
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: namespace}},
+	); err != nil {
+		r.log.Error(err, "failed to remove SPIRE agent resources")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+			fmt.Sprintf("Failed to remove the SPIRE agent resources: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	r.log.Info("SPIRE agent resources removed", "name", agent.Name)
+	return nil
+}
@@ -0,0 +1,73 @@
+package spire_agent
+
+import (
+	"context"
+	"testing"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func TestReconcileDeletion(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name          string
+		csiDriver     *v1alpha1.SpiffeCSIDriver
+		expectRequeue bool
+	}{
+		{name: "CSI driver being deleted", csiDriver: &v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster", DeletionTimestamp: &now}}, expectRequeue: true},
+		{name: "CSI driver kept", csiDriver: &v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}},
+		{name: "CSI driver gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				if driver, ok := obj.(*v1alpha1.SpiffeCSIDriver); ok && tt.csiDriver != nil {
+					*driver = *tt.csiDriver
+					return nil
+				}
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			reconciler := newTestReconciler(fakeClient)
+			agent := &v1alpha1.SpireAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "cluster",
+					DeletionTimestamp: &now,
+					Finalizers:        []string{utils.OperandCleanupFinalizer},
+				},
+			}
+
+			result, err := reconciler.reconcileDeletion(context.Background(), agent, "operand-ns")
+			if err != nil {
+				t.Fatalf("reconcileDeletion() error = %v", err)
+			}
+			if tt.expectRequeue {
+				if result.RequeueAfter == 0 || fakeClient.DeleteCallCount() != 0 {
+					t.Error("Expected the agents to be kept until the CSI driver is deleted")
+				}
+				if !controllerutil.ContainsFinalizer(agent, utils.OperandCleanupFinalizer) {
+					t.Error("Expected the finalizer to be kept while waiting")
+				}
+				return
+			}
+			if fakeClient.DeleteCallCount() != 1 {
+				t.Fatalf("Expected the DaemonSet to be deleted, got %d deletes", fakeClient.DeleteCallCount())
+			}
+			if _, obj, _ := fakeClient.DeleteArgsForCall(0); obj.GetNamespace() != "operand-ns" {
+				t.Errorf("Expected the DaemonSet to be deleted in the operand namespace, got %q", obj.GetNamespace())
+			}
+			if controllerutil.ContainsFinalizer(agent, utils.OperandCleanupFinalizer) {
+				t.Error("Expected the finalizer to be removed")
+			}
+		})
+	}
+}
@@ -49,6 +49,7 @@ func (r *SpireAgentReconciler) reconcileClusterRole(ctx context.Context, agent *
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.ClusterRole{}
@@ -74,6 +75,7 @@ func (r *SpireAgentReconciler) reconcileClusterRole(ctx context.Context, agent *
 		}
 
 		r.log.Info("Created ClusterRole", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -100,6 +102,7 @@ func (r *SpireAgentReconciler) reconcileClusterRole(ctx context.Context, agent *
 	}
 
 	r.log.Info("Updated ClusterRole", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -114,6 +117,7 @@ func (r *SpireAgentReconciler) reconcileClusterRoleBinding(ctx context.Context,
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.ClusterRoleBinding{}
@@ -139,6 +143,7 @@ func (r *SpireAgentReconciler) reconcileClusterRoleBinding(ctx context.Context,
 		}
 
 		r.log.Info("Created ClusterRoleBinding", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -165,6 +170,7 @@ func (r *SpireAgentReconciler) reconcileClusterRoleBinding(ctx context.Context,
 	}
 
 	r.log.Info("Updated ClusterRoleBinding", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -181,7 +187,7 @@ func getSpireAgentClusterRoleBinding(customLabels map[string]string) *rbacv1.Clu
 	crb.Labels = utils.SpireAgentLabels(customLabels)
 	// Update the subject namespace
 	for i := range crb.Subjects {
-		crb.Subjects[i].Namespace = utils.GetOperatorNamespace()
+		crb.Subjects[i].Namespace = utils.GetOperandNamespace()
 	}
 	return crb
 }
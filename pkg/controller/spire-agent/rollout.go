@@ -0,0 +1,262 @@
+package spire_agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	spireAgentCanaryDaemonSetName = "spire-agent-canary"
+
+	// rolloutStageLabelKey distinguishes the agent pods of the canary nodes from the other agent pods
+	rolloutStageLabelKey = "ztwim.openshift.io/rollout-stage"
+	rolloutStageCanary   = "canary"
+
+	// rolloutRevisionAnnotationKey records the revision of the agent pods a DaemonSet runs
+	rolloutRevisionAnnotationKey = "ztwim.openshift.io/rollout-revision"
+	// canaryVerifiedAtAnnotationKey records when the canary agents first became ready at their revision
+	canaryVerifiedAtAnnotationKey = "ztwim.openshift.io/canary-verified-at"
+
+	defaultCanarySoakPeriod = 10 * time.Minute
+	approvalManual          = "Manual"
+)
+
+// reconcileStagedRollout rolls the agent pods of desired out to the canary nodes through the canary
+// DaemonSet, and keeps the pods of desired off the canary nodes. It reports whether updating existing,
+// the current agent DaemonSet, to desired must wait for the canary revision to be verified, and how
+// long the canary revision still has to soak. existing is nil when the agent DaemonSet doesn't exist.
+func (r *SpireAgentReconciler) reconcileStagedRollout(ctx context.Context, agent *v1alpha1.SpireAgent, desired, existing *appsv1.DaemonSet, statusMgr *status.Manager, createOnlyMode bool) (bool, time.Duration, error) {
+	rollout := agent.Spec.Rollout
+	if rollout == nil {
+		// The canary DaemonSet is deleted before the agent DaemonSet is rolled out to the canary nodes,
+		// two agents on a node would clash on the agent socket and host port
+		if err := r.deleteCanaryDaemonSet(ctx, desired.Namespace, statusMgr); err != nil {
+			return false, 0, err
+		}
+		existingCondition := apimeta.FindStatusCondition(agent.Status.ConditionalStatus.Conditions, StagedRollout)
+		if existingCondition != nil && existingCondition.Reason != "StagedRolloutDisabled" {
+			statusMgr.AddCondition(StagedRollout, "StagedRolloutDisabled",
+				"Staged rollout is not configured, changes are rolled out to all the nodes at once",
+				metav1.ConditionTrue)
+		}
+		return false, 0, nil
+	}
+
+	revision := rolloutRevision(desired)
+	metav1.SetMetaDataAnnotation(&desired.ObjectMeta, rolloutRevisionAnnotationKey, revision)
+	canary := generateCanaryDaemonSet(desired, rollout)
+	excludeCanaryNodes(desired, rollout)
+
+	verifiedAt, err := r.reconcileCanaryDaemonSet(ctx, agent, canary, revision, statusMgr, createOnlyMode)
+	if err != nil {
+		return false, 0, err
+	}
+
+	// The agents are rolled out to all the nodes at once on install, and when enabling the staged rollout
+	if existing == nil || existing.Annotations[rolloutRevisionAnnotationKey] == "" || existing.Annotations[rolloutRevisionAnnotationKey] == revision {
+		statusMgr.AddCondition(StagedRollout, "RolloutComplete",
+			fmt.Sprintf("Revision %s is rolled out to all the nodes", revision),
+			metav1.ConditionTrue)
+		return false, 0, nil
+	}
+
+	if verifiedAt.IsZero() {
+		reason, message := "CanaryRolloutInProgress", fmt.Sprintf("Rolling out revision %s to the canary nodes", revision)
+		if canary.Status.ObservedGeneration == canary.Generation && canary.Generation > 0 && canary.Status.DesiredNumberScheduled == 0 {
+			reason, message = "NoCanaryNodes", fmt.Sprintf("No node matches the canary node selector, revision %s cannot be verified", revision)
+		}
+		statusMgr.AddCondition(StagedRollout, reason, message, metav1.ConditionFalse)
+		return true, 0, nil
+	}
+
+	soakPeriod := defaultCanarySoakPeriod
+	if rollout.SoakPeriod != nil {
+		soakPeriod = rollout.SoakPeriod.Duration
+	}
+	if remaining := time.Until(verifiedAt.Add(soakPeriod)); remaining > 0 {
+		statusMgr.AddCondition(StagedRollout, "CanarySoaking",
+			fmt.Sprintf("Revision %s is ready on the canary nodes, rolling it out to the other nodes in %s", revision, remaining.Round(time.Second)),
+			metav1.ConditionFalse)
+		return true, remaining, nil
+	}
+
+	if rollout.Approval == approvalManual && rollout.ApprovedRevision != revision {
+		statusMgr.AddCondition(StagedRollout, "WaitingForApproval",
+			fmt.Sprintf("Revision %s is verified on the canary nodes, set spec.rollout.approvedRevision to %s to roll it out to the other nodes", revision, revision),
+			metav1.ConditionFalse)
+		return true, 0, nil
+	}
+
+	statusMgr.AddCondition(StagedRollout, "CanaryVerified",
+		fmt.Sprintf("Revision %s is verified on the canary nodes, rolling it out to the other nodes", revision),
+		metav1.ConditionTrue)
+	return false, 0, nil
+}
+
+// reconcileCanaryDaemonSet reconciles the canary DaemonSet and returns when its agents were first
+// ready at revision, or the zero time while they are not. canary is updated to the existing
+// DaemonSet, so that its status can be inspected.
+func (r *SpireAgentReconciler) reconcileCanaryDaemonSet(ctx context.Context, agent *v1alpha1.SpireAgent, canary *appsv1.DaemonSet, revision string, statusMgr *status.Manager, createOnlyMode bool) (time.Time, error) {
+	statusMgr.TrackResource(canary)
+
+	var existingCanary appsv1.DaemonSet
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: canary.Name, Namespace: canary.Namespace}, &existingCanary)
+	if err != nil && kerrors.IsNotFound(err) {
+		if err = r.ctrlClient.Apply(ctx, canary); err != nil {
+			r.log.Error(err, "failed to create spire-agent canary daemonset")
+			statusMgr.AddCondition(StagedRollout, "SpireAgentCanaryDaemonSetCreationFailed",
+				err.Error(),
+				metav1.ConditionFalse)
+			return time.Time{}, fmt.Errorf("failed to create canary DaemonSet: %w", err)
+		}
+		r.log.Info("Created spire agent canary DaemonSet")
+		statusMgr.RecordResourceCreated(canary)
+		return time.Time{}, nil
+	} else if err != nil {
+		r.log.Error(err, "failed to get spire-agent canary daemonset")
+		statusMgr.AddCondition(StagedRollout, "SpireAgentCanaryDaemonSetGetFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return time.Time{}, err
+	}
+
+	// The soak period starts when the canary agents are first ready at the revision
+	var verifiedAt time.Time
+	if existingCanary.Annotations[rolloutRevisionAnnotationKey] == revision && status.IsDaemonSetHealthy(&existingCanary) {
+		verifiedAt, err = time.Parse(time.RFC3339, existingCanary.Annotations[canaryVerifiedAtAnnotationKey])
+		if err != nil {
+			verifiedAt = time.Now().UTC().Truncate(time.Second)
+		}
+		metav1.SetMetaDataAnnotation(&canary.ObjectMeta, canaryVerifiedAtAnnotationKey, verifiedAt.Format(time.RFC3339))
+	}
+
+	if needsUpdate(existingCanary, *canary) {
+		if createOnlyMode {
+			r.log.Info("Skipping canary DaemonSet update due to create-only mode")
+		} else if !r.upgradeAllowed(ctx, agent, &existingCanary, canary, statusMgr) {
+			r.log.Info("Skipping canary DaemonSet update until the SPIRE agent version change is safe")
+		} else {
+			if err = r.ctrlClient.Apply(ctx, canary); err != nil {
+				r.log.Error(err, "failed to update spire agent canary DaemonSet")
+				statusMgr.AddCondition(StagedRollout, "SpireAgentCanaryDaemonSetUpdateFailed",
+					err.Error(),
+					metav1.ConditionFalse)
+				return time.Time{}, fmt.Errorf("failed to update canary DaemonSet: %w", err)
+			}
+			r.log.Info("Updated spire agent canary DaemonSet")
+			statusMgr.RecordWorkloadUpdated(canary, &existingCanary.Spec.Template, &canary.Spec.Template,
+				spireAgentDaemonSetSpireAgentConfigHashAnnotationKey)
+		}
+	}
+
+	canary.Generation = existingCanary.Generation
+	canary.Status = existingCanary.Status
+	return verifiedAt, nil
+}
+
+// deleteCanaryDaemonSet deletes the canary DaemonSet left by a staged rollout that is no longer configured
+func (r *SpireAgentReconciler) deleteCanaryDaemonSet(ctx context.Context, namespace string, statusMgr *status.Manager) error {
+	var existingCanary appsv1.DaemonSet
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: spireAgentCanaryDaemonSetName, Namespace: namespace}, &existingCanary)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		r.log.Error(err, "failed to get spire-agent canary daemonset")
+		statusMgr.AddCondition(StagedRollout, "SpireAgentCanaryDaemonSetGetFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	if err = r.ctrlClient.Delete(ctx, &existingCanary); err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to delete spire-agent canary daemonset")
+		statusMgr.AddCondition(StagedRollout, "SpireAgentCanaryDaemonSetDeletionFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return fmt.Errorf("failed to delete canary DaemonSet: %w", err)
+	}
+	r.log.Info("Deleted spire agent canary DaemonSet")
+	return nil
+}
+
+// rolloutRevision identifies the agent pods of ds, so that the canary DaemonSet and the agent DaemonSet
+// can be compared
+func rolloutRevision(ds *appsv1.DaemonSet) string {
+	template, err := json.Marshal(ds.Spec.Template)
+	if err != nil {
+		return ""
+	}
+	return utils.GenerateConfigHash(template)[:10]
+}
+
+// generateCanaryDaemonSet derives the DaemonSet running the agents of the canary nodes from the agent DaemonSet
+func generateCanaryDaemonSet(ds *appsv1.DaemonSet, rollout *v1alpha1.AgentRolloutConfig) *appsv1.DaemonSet {
+	canary := ds.DeepCopy()
+	canary.Name = spireAgentCanaryDaemonSetName
+	canary.Labels[rolloutStageLabelKey] = rolloutStageCanary
+	canary.Spec.Selector.MatchLabels[rolloutStageLabelKey] = rolloutStageCanary
+	canary.Spec.Template.Labels[rolloutStageLabelKey] = rolloutStageCanary
+
+	nodeSelector := map[string]string{}
+	for key, value := range canary.Spec.Template.Spec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	for key, value := range rollout.CanaryNodeSelector {
+		nodeSelector[key] = value
+	}
+	canary.Spec.Template.Spec.NodeSelector = nodeSelector
+	return canary
+}
+
+// excludeCanaryNodes keeps the pods of the agent DaemonSet off the canary nodes, which run the agents
+// of the canary DaemonSet. A node is excluded when it has all the labels of the canary node selector.
+func excludeCanaryNodes(ds *appsv1.DaemonSet, rollout *v1alpha1.AgentRolloutConfig) {
+	affinity := ds.Spec.Template.Spec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	terms := []corev1.NodeSelectorTerm{{}}
+	if required != nil && len(required.NodeSelectorTerms) > 0 {
+		terms = required.NodeSelectorTerms
+	}
+
+	keys := make([]string, 0, len(rollout.CanaryNodeSelector))
+	for key := range rollout.CanaryNodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// The terms are ORed, so each term of the user affinity is split in one term per canary label
+	excluded := make([]corev1.NodeSelectorTerm, 0, len(terms)*len(keys))
+	for _, term := range terms {
+		for _, key := range keys {
+			excludedTerm := *term.DeepCopy()
+			excludedTerm.MatchExpressions = append(excludedTerm.MatchExpressions, corev1.NodeSelectorRequirement{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{rollout.CanaryNodeSelector[key]},
+			})
+			excluded = append(excluded, excludedTerm)
+		}
+	}
+	affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: excluded}
+	ds.Spec.Template.Spec.Affinity = affinity
+}
@@ -0,0 +1,231 @@
+package spire_agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var rolloutTestZTWIM = &v1alpha1.ZeroTrustWorkloadIdentityManager{
+	Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+}
+
+func TestGenerateCanaryDaemonSet(t *testing.T) {
+	config := v1alpha1.SpireAgentSpec{
+		CommonConfig: v1alpha1.CommonConfig{NodeSelector: map[string]string{"kubernetes.io/os": "linux"}},
+	}
+	ds := generateSpireAgentDaemonSet(config, rolloutTestZTWIM, "hash")
+	canary := generateCanaryDaemonSet(ds, &v1alpha1.AgentRolloutConfig{CanaryNodeSelector: map[string]string{"pool": "canary"}})
+
+	assert.Equal(t, spireAgentCanaryDaemonSetName, canary.Name)
+	assert.Equal(t, rolloutStageCanary, canary.Spec.Selector.MatchLabels[rolloutStageLabelKey])
+	assert.Equal(t, rolloutStageCanary, canary.Spec.Template.Labels[rolloutStageLabelKey])
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "pool": "canary"}, canary.Spec.Template.Spec.NodeSelector)
+
+	// The agent DaemonSet is left unchanged
+	assert.NotContains(t, ds.Spec.Selector.MatchLabels, rolloutStageLabelKey)
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, ds.Spec.Template.Spec.NodeSelector)
+}
+
+func TestExcludeCanaryNodes(t *testing.T) {
+	rollout := &v1alpha1.AgentRolloutConfig{CanaryNodeSelector: map[string]string{"pool": "canary", "zone": "a"}}
+
+	t.Run("without affinity", func(t *testing.T) {
+		ds := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, rolloutTestZTWIM, "hash")
+		excludeCanaryNodes(ds, rollout)
+
+		terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Equal(t, []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"canary"}}}},
+			{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"a"}}}},
+		}, terms)
+	})
+
+	t.Run("keeps the configured node affinity", func(t *testing.T) {
+		userTerm := corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node-role.kubernetes.io/worker", Operator: corev1.NodeSelectorOpExists}}}
+		config := v1alpha1.SpireAgentSpec{
+			CommonConfig: v1alpha1.CommonConfig{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{userTerm}},
+			}}},
+		}
+		ds := generateSpireAgentDaemonSet(config, rolloutTestZTWIM, "hash")
+		excludeCanaryNodes(ds, rollout)
+
+		terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		assert.Len(t, terms, 2)
+		for _, term := range terms {
+			assert.Equal(t, userTerm.MatchExpressions[0], term.MatchExpressions[0])
+			assert.Len(t, term.MatchExpressions, 2)
+		}
+		// The affinity of the spec is not modified
+		assert.Len(t, config.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+	})
+}
+
+func TestReconcileStagedRollout(t *testing.T) {
+	base := generateSpireAgentDaemonSet(v1alpha1.SpireAgentSpec{}, rolloutTestZTWIM, "hash")
+	revision := rolloutRevision(base)
+
+	// newCanary returns the existing canary DaemonSet at rev, ready since verifiedAt when set
+	newCanary := func(rev string, ready bool, verifiedAt time.Time) *appsv1.DaemonSet {
+		canary := generateCanaryDaemonSet(base, &v1alpha1.AgentRolloutConfig{CanaryNodeSelector: map[string]string{"pool": "canary"}})
+		canary.Annotations = map[string]string{rolloutRevisionAnnotationKey: rev}
+		canary.Generation = 2
+		canary.Status.ObservedGeneration = 2
+		if ready {
+			canary.Status.DesiredNumberScheduled = 2
+			canary.Status.NumberReady = 2
+			canary.Status.UpdatedNumberScheduled = 2
+			canary.Status.NumberAvailable = 2
+		}
+		if !verifiedAt.IsZero() {
+			canary.Annotations[canaryVerifiedAtAnnotationKey] = verifiedAt.Format(time.RFC3339)
+		}
+		return canary
+	}
+	existingMain := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "spire-agent",
+		Annotations: map[string]string{rolloutRevisionAnnotationKey: "previous"},
+	}}
+
+	tests := []struct {
+		name            string
+		rollout         *v1alpha1.AgentRolloutConfig
+		existing        *appsv1.DaemonSet
+		canary          *appsv1.DaemonSet
+		expectPending   bool
+		expectRequeue   bool
+		expectReason    string
+		expectCanaryApp bool
+		expectCanaryDel bool
+	}{
+		{
+			name:     "staged rollout not configured",
+			existing: existingMain,
+		},
+		{
+			name:            "staged rollout removed deletes the canary",
+			existing:        existingMain,
+			canary:          newCanary(revision, true, time.Now().Add(-time.Hour)),
+			expectCanaryDel: true,
+		},
+		{
+			name:            "install rolls out to all the nodes",
+			rollout:         &v1alpha1.AgentRolloutConfig{},
+			expectReason:    "RolloutComplete",
+			expectCanaryApp: true,
+		},
+		{
+			name:            "canary nodes roll out the new revision",
+			rollout:         &v1alpha1.AgentRolloutConfig{},
+			existing:        existingMain,
+			canary:          newCanary("previous", true, time.Now().Add(-time.Hour)),
+			expectPending:   true,
+			expectReason:    "CanaryRolloutInProgress",
+			expectCanaryApp: true,
+		},
+		{
+			name:          "no canary nodes",
+			rollout:       &v1alpha1.AgentRolloutConfig{},
+			existing:      existingMain,
+			canary:        newCanary(revision, false, time.Time{}),
+			expectPending: true,
+			expectReason:  "NoCanaryNodes",
+		},
+		{
+			name:            "canary revision soaking",
+			rollout:         &v1alpha1.AgentRolloutConfig{},
+			existing:        existingMain,
+			canary:          newCanary(revision, true, time.Time{}),
+			expectPending:   true,
+			expectRequeue:   true,
+			expectReason:    "CanarySoaking",
+			expectCanaryApp: true,
+		},
+		{
+			name:         "canary revision verified",
+			rollout:      &v1alpha1.AgentRolloutConfig{SoakPeriod: &metav1.Duration{Duration: time.Minute}},
+			existing:     existingMain,
+			canary:       newCanary(revision, true, time.Now().Add(-2*time.Minute)),
+			expectReason: "CanaryVerified",
+		},
+		{
+			name:          "manual approval pending",
+			rollout:       &v1alpha1.AgentRolloutConfig{Approval: approvalManual, ApprovedRevision: "previous"},
+			existing:      existingMain,
+			canary:        newCanary(revision, true, time.Now().Add(-time.Hour)),
+			expectPending: true,
+			expectReason:  "WaitingForApproval",
+		},
+		{
+			name:         "manually approved revision",
+			rollout:      &v1alpha1.AgentRolloutConfig{Approval: approvalManual, ApprovedRevision: revision},
+			existing:     existingMain,
+			canary:       newCanary(revision, true, time.Now().Add(-time.Hour)),
+			expectReason: "CanaryVerified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				if tt.canary == nil {
+					return kerrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "daemonsets"}, key.Name)
+				}
+				tt.canary.DeepCopyInto(obj.(*appsv1.DaemonSet))
+				return nil
+			}
+			reconciler := newTestReconciler(fakeClient)
+			agent := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+			if tt.rollout != nil {
+				tt.rollout.CanaryNodeSelector = map[string]string{"pool": "canary"}
+			}
+			agent.Spec.Rollout = tt.rollout
+			desired := base.DeepCopy()
+
+			statusMgr := status.NewManager(fakeClient)
+			pending, requeueAfter, err := reconciler.reconcileStagedRollout(context.Background(), agent, desired, tt.existing, statusMgr, false)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			assert.Equal(t, tt.expectPending, pending)
+			assert.Equal(t, tt.expectRequeue, requeueAfter > 0)
+			assert.Equal(t, tt.expectCanaryApp, fakeClient.ApplyCallCount() > 0)
+			assert.Equal(t, tt.expectCanaryDel, fakeClient.DeleteCallCount() > 0)
+			if tt.expectCanaryDel {
+				_, deleted, _ := fakeClient.DeleteArgsForCall(0)
+				assert.Equal(t, spireAgentCanaryDaemonSetName, deleted.GetName())
+			}
+
+			if err := statusMgr.ApplyStatus(context.Background(), agent, func() *v1alpha1.ConditionalStatus {
+				return &agent.Status.ConditionalStatus
+			}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			cond := apimeta.FindStatusCondition(agent.Status.Conditions, StagedRollout)
+			if tt.expectReason == "" {
+				assert.Nil(t, cond)
+				assert.Nil(t, desired.Spec.Template.Spec.Affinity)
+				return
+			}
+			if assert.NotNil(t, cond) {
+				assert.Equal(t, tt.expectReason, cond.Reason)
+			}
+			assert.Equal(t, revision, desired.Annotations[rolloutRevisionAnnotationKey])
+			assert.NotNil(t, desired.Spec.Template.Spec.Affinity.NodeAffinity)
+		})
+	}
+}
@@ -38,7 +38,7 @@ func generateSpireAgentSCC(config *v1alpha1.SpireAgent) *securityv1.SecurityCont
 			Type: securityv1.FSGroupStrategyMustRunAs,
 		},
 		Users: []string{
-			fmt.Sprintf("system:serviceaccount:%s:spire-agent", utils.GetOperatorNamespace()),
+			fmt.Sprintf("system:serviceaccount:%s:spire-agent", utils.GetOperandNamespace()),
 		},
 		Volumes: []securityv1.FSType{
 			securityv1.FSTypeConfigMap,
@@ -66,6 +66,13 @@ func generateSpireAgentSCC(config *v1alpha1.SpireAgent) *securityv1.SecurityCont
 // reconcileSCC reconciles the Spire Agent Security Context Constraints
 func (r *SpireAgentReconciler) reconcileSCC(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager) error {
 	desired := generateSpireAgentSCC(agent)
+	if agent.Spec.SecurityContextConstraints != "" {
+		// The agent pods only need the host network when they run in it
+		required := desired.DeepCopy()
+		required.AllowHostNetwork = useHostNetwork(agent.Spec)
+		required.AllowHostPorts = required.AllowHostNetwork
+		return r.checkReferencedSCC(ctx, agent.Spec.SecurityContextConstraints, required, statusMgr)
+	}
 	if err := controllerutil.SetControllerReference(agent, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference")
 		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpireAgentSCCGenerationFailed",
@@ -73,6 +80,7 @@ func (r *SpireAgentReconciler) reconcileSCC(ctx context.Context, agent *v1alpha1
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &securityv1.SecurityContextConstraints{}
@@ -98,6 +106,7 @@ func (r *SpireAgentReconciler) reconcileSCC(ctx context.Context, agent *v1alpha1
 		}
 
 		r.log.Info("Created SecurityContextConstraints", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpireAgentSCCResourceCreated",
 			"Spire Agent SCC resources applied",
 			metav1.ConditionTrue)
@@ -130,8 +139,35 @@ func (r *SpireAgentReconciler) reconcileSCC(ctx context.Context, agent *v1alpha1
 	}
 
 	r.log.Info("Updated SecurityContextConstraints", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpireAgentSCCResourceUpdated",
 		"Spire Agent SCC resources updated",
 		metav1.ConditionTrue)
 	return nil
 }
+
+// checkReferencedSCC checks that the SecurityContextConstraints referenced by the SpireAgent exists and grants
+// the access of required, the SecurityContextConstraints the operator creates otherwise
+func (r *SpireAgentReconciler) checkReferencedSCC(ctx context.Context, name string, required *securityv1.SecurityContextConstraints, statusMgr *status.Manager) error {
+	scc := &securityv1.SecurityContextConstraints{}
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: name}, scc); err != nil {
+		r.log.Error(err, "failed to get the referenced SecurityContextConstraints", "name", name)
+		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpireAgentSCCGetFailed",
+			fmt.Sprintf("Failed to get SecurityContextConstraints %q: %v", name, err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	if err := utils.ValidateSecurityContextConstraints(scc, required); err != nil {
+		r.log.Error(err, "referenced SecurityContextConstraints can't admit the SPIRE agent pods")
+		statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpireAgentSCCInsufficient",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	statusMgr.AddCondition(SecurityContextConstraintsAvailable, "SpireAgentSCCReferenced",
+		fmt.Sprintf("Spire Agent pods are admitted with the SecurityContextConstraints %q", name),
+		metav1.ConditionTrue)
+	return nil
+}
@@ -354,3 +354,56 @@ func TestReconcileSCC_PreservesExistingFields(t *testing.T) {
 		}
 	}
 }
+
+func TestReconcileSCC_ReferencedSCC(t *testing.T) {
+	newAgent := func(hostNetwork string) *v1alpha1.SpireAgent {
+		return &v1alpha1.SpireAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+			Spec:       v1alpha1.SpireAgentSpec{SecurityContextConstraints: "node-agents", HostNetwork: hostNetwork},
+		}
+	}
+	referenced := func(agent *v1alpha1.SpireAgent) *securityv1.SecurityContextConstraints {
+		scc := generateSpireAgentSCC(agent)
+		scc.Name = "node-agents"
+		scc.AllowHostNetwork = false
+		scc.AllowHostPorts = false
+		return scc
+	}
+
+	tests := []struct {
+		name        string
+		hostNetwork string
+		getErr      error
+		expectError bool
+	}{
+		{name: "referenced SCC admits the agent pods", hostNetwork: "false"},
+		{name: "referenced SCC without host network", hostNetwork: "true", expectError: true},
+		{name: "referenced SCC not found", hostNetwork: "false", getErr: kerrors.NewNotFound(schema.GroupResource{}, "node-agents"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			agent := newAgent(tt.hostNetwork)
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				if tt.getErr != nil {
+					return tt.getErr
+				}
+				if key.Name != "node-agents" {
+					t.Errorf("Expected the referenced SCC to be read, got %s", key.Name)
+				}
+				*obj.(*securityv1.SecurityContextConstraints) = *referenced(agent)
+				return nil
+			}
+			reconciler := newSCCTestReconciler(fakeClient)
+
+			err := reconciler.reconcileSCC(context.Background(), agent, status.NewManager(fakeClient))
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcileSCC() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+				t.Error("Expected the referenced SCC to be left unchanged")
+			}
+		})
+	}
+}
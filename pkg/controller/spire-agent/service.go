@@ -8,6 +8,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
@@ -32,7 +33,7 @@ func (r *SpireAgentReconciler) reconcileService(ctx context.Context, agent *v1al
 
 // reconcileAgentService reconciles the Spire Agent Service
 func (r *SpireAgentReconciler) reconcileAgentService(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager, createOnlyMode bool) error {
-	desired := getSpireAgentService(agent.Spec.Labels)
+	desired := getSpireAgentService(agent.Spec.Labels, getMetricsPort(agent.Spec))
 
 	if err := controllerutil.SetControllerReference(agent, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on service")
@@ -41,6 +42,7 @@ func (r *SpireAgentReconciler) reconcileAgentService(ctx context.Context, agent
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.Service{}
@@ -66,6 +68,7 @@ func (r *SpireAgentReconciler) reconcileAgentService(ctx context.Context, agent
 		}
 
 		r.log.Info("Created Service", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -110,17 +113,23 @@ func (r *SpireAgentReconciler) reconcileAgentService(ctx context.Context, agent
 	}
 
 	r.log.Info("Updated Service", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
-// getSpireAgentService returns the Spire Agent Service with proper labels and selectors
-func getSpireAgentService(customLabels map[string]string) *corev1.Service {
+// getSpireAgentService returns the Spire Agent Service with proper labels and selectors, targeting the metrics port of the agents
+func getSpireAgentService(customLabels map[string]string, metricsPort int32) *corev1.Service {
 	svc := utils.DecodeServiceObjBytes(assets.MustAsset(utils.SpireAgentServiceAssetName))
 	svc.Labels = utils.SpireAgentLabels(customLabels)
-	svc.Namespace = utils.GetOperatorNamespace()
+	svc.Namespace = utils.GetOperandNamespace()
 	svc.Spec.Selector = map[string]string{
 		"app.kubernetes.io/name":     "spire-agent",
 		"app.kubernetes.io/instance": utils.StandardInstance,
 	}
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Name == "metrics" {
+			svc.Spec.Ports[i].TargetPort = intstr.FromInt32(metricsPort)
+		}
+	}
 	return svc
 }
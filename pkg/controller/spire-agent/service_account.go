@@ -3,6 +3,7 @@ package spire_agent
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -20,7 +21,7 @@ import (
 
 // reconcileServiceAccount reconciles the Spire Agent ServiceAccount
 func (r *SpireAgentReconciler) reconcileServiceAccount(ctx context.Context, agent *v1alpha1.SpireAgent, statusMgr *status.Manager, createOnlyMode bool) error {
-	desired := getSpireAgentServiceAccount(agent.Spec.Labels)
+	desired := getSpireAgentServiceAccount(agent.Spec.Labels, agent.Spec.ServiceAccountAnnotations)
 
 	if err := controllerutil.SetControllerReference(agent, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on service account")
@@ -29,6 +30,7 @@ func (r *SpireAgentReconciler) reconcileServiceAccount(ctx context.Context, agen
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.ServiceAccount{}
@@ -54,6 +56,7 @@ func (r *SpireAgentReconciler) reconcileServiceAccount(ctx context.Context, agen
 		}
 
 		r.log.Info("Created ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 			"All ServiceAccount resources available",
 			metav1.ConditionTrue)
@@ -89,16 +92,18 @@ func (r *SpireAgentReconciler) reconcileServiceAccount(ctx context.Context, agen
 	}
 
 	r.log.Info("Updated ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 		"All ServiceAccount resources available",
 		metav1.ConditionTrue)
 	return nil
 }
 
-// getSpireAgentServiceAccount returns the Spire Agent ServiceAccount with proper labels
-func getSpireAgentServiceAccount(customLabels map[string]string) *corev1.ServiceAccount {
+// getSpireAgentServiceAccount returns the Spire Agent ServiceAccount with proper labels and annotations
+func getSpireAgentServiceAccount(customLabels, annotations map[string]string) *corev1.ServiceAccount {
 	sa := utils.DecodeServiceAccountObjBytes(assets.MustAsset(utils.SpireAgentServiceAccountAssetName))
 	sa.Labels = utils.SpireAgentLabels(customLabels)
-	sa.Namespace = utils.GetOperatorNamespace()
+	sa.Namespace = utils.GetOperandNamespace()
+	sa.Annotations = maps.Clone(annotations)
 	return sa
 }
@@ -39,7 +39,7 @@ func TestGetSpireAgentServiceAccount(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sa := getSpireAgentServiceAccount(tt.customLabels)
+			sa := getSpireAgentServiceAccount(tt.customLabels, nil)
 
 			if sa == nil {
 				t.Fatal("Expected ServiceAccount, got nil")
@@ -74,14 +74,14 @@ func TestGetSpireAgentServiceAccount(t *testing.T) {
 	}
 
 	t.Run("preserves all asset labels", func(t *testing.T) {
-		saWithoutCustom := getSpireAgentServiceAccount(nil)
+		saWithoutCustom := getSpireAgentServiceAccount(nil, nil)
 		assetLabels := make(map[string]string)
 		for k, v := range saWithoutCustom.Labels {
 			assetLabels[k] = v
 		}
 
 		customLabels := map[string]string{"app-version": "v1.2.3"}
-		saWithCustom := getSpireAgentServiceAccount(customLabels)
+		saWithCustom := getSpireAgentServiceAccount(customLabels, nil)
 
 		for k, v := range assetLabels {
 			if saWithCustom.Labels[k] != v {
@@ -181,7 +181,7 @@ func TestReconcileServiceAccount(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
 			},
 			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
-				desiredSA := getSpireAgentServiceAccount(nil)
+				desiredSA := getSpireAgentServiceAccount(nil, nil)
 				desiredSA.ResourceVersion = "123"
 				fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
 					if sa, ok := obj.(*corev1.ServiceAccount); ok {
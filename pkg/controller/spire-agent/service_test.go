@@ -39,7 +39,7 @@ func TestGetSpireAgentService(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := getSpireAgentService(tt.customLabels)
+			svc := getSpireAgentService(tt.customLabels, defaultAgentMetricsPort)
 
 			if svc == nil {
 				t.Fatal("Expected Service, got nil")
@@ -82,14 +82,14 @@ func TestGetSpireAgentService(t *testing.T) {
 	}
 
 	t.Run("preserves all asset labels", func(t *testing.T) {
-		svcWithoutCustom := getSpireAgentService(nil)
+		svcWithoutCustom := getSpireAgentService(nil, defaultAgentMetricsPort)
 		assetLabels := make(map[string]string)
 		for k, v := range svcWithoutCustom.Labels {
 			assetLabels[k] = v
 		}
 
 		customLabels := map[string]string{"region": "us-east-1"}
-		svcWithCustom := getSpireAgentService(customLabels)
+		svcWithCustom := getSpireAgentService(customLabels, defaultAgentMetricsPort)
 
 		for k, v := range assetLabels {
 			if svcWithCustom.Labels[k] != v {
@@ -101,6 +101,15 @@ func TestGetSpireAgentService(t *testing.T) {
 			t.Errorf("Custom label was not added")
 		}
 	})
+
+	t.Run("targets the configured metrics port", func(t *testing.T) {
+		svc := getSpireAgentService(nil, 19402)
+		for _, port := range svc.Spec.Ports {
+			if port.Name == "metrics" && port.TargetPort.IntValue() != 19402 {
+				t.Errorf("Expected the metrics port to target 19402, got %s", port.TargetPort.String())
+			}
+		}
+	})
 }
 
 // newServiceTestReconciler creates a reconciler for Service tests
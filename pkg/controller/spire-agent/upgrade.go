@@ -0,0 +1,65 @@
+package spire_agent
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// spireServerStatefulSetName is the StatefulSet of the SPIRE server the agents attest to
+const spireServerStatefulSetName = "spire-server"
+
+// upgradeAllowed reports whether the existing SPIRE agent DaemonSet can be rolled to the version of the
+// desired one, and records the outcome in the UpgradeAllowed condition. A new agent version is only
+// rolled out once the SPIRE server finished its own rollout and supports it, since agents must never be
+// newer than the server.
+func (r *SpireAgentReconciler) upgradeAllowed(ctx context.Context, agent *v1alpha1.SpireAgent, existing, desired *appsv1.DaemonSet, statusMgr *status.Manager) bool {
+	current, target := existing.Labels[utils.VersionLabelKey], desired.Labels[utils.VersionLabelKey]
+	if current != target && !r.spireServerSupports(ctx, current, target, statusMgr) {
+		return false
+	}
+
+	// Only set to true if the condition previously existed as false
+	existingCondition := apimeta.FindStatusCondition(agent.Status.ConditionalStatus.Conditions, utils.UpgradeAllowedStatusType)
+	if existingCondition != nil && existingCondition.Status == metav1.ConditionFalse {
+		statusMgr.AddCondition(utils.UpgradeAllowedStatusType, utils.UpgradeAllowed,
+			"SPIRE agent version change passed the upgrade checks",
+			metav1.ConditionTrue)
+	}
+	return true
+}
+
+// spireServerSupports reports whether the SPIRE server finished its rollout and supports agents of the
+// target version, adding a false UpgradeAllowed condition otherwise
+func (r *SpireAgentReconciler) spireServerSupports(ctx context.Context, current, target string, statusMgr *status.Manager) bool {
+	var server appsv1.StatefulSet
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: spireServerStatefulSetName, Namespace: utils.GetOperandNamespace()}, &server); err != nil {
+		r.log.Error(err, "failed to get the spire server StatefulSet to check the agent version skew")
+		statusMgr.AddCondition(utils.UpgradeAllowedStatusType, utils.WaitingForSpireServer,
+			fmt.Sprintf("Waiting for the SPIRE server before rolling out SPIRE agent %s: %v", target, err),
+			metav1.ConditionFalse)
+		return false
+	}
+	if !status.IsStatefulSetHealthy(&server) {
+		statusMgr.AddCondition(utils.UpgradeAllowedStatusType, utils.WaitingForSpireServer,
+			fmt.Sprintf("Waiting for the SPIRE server rollout to complete before rolling out SPIRE agent %s", target),
+			metav1.ConditionFalse)
+		return false
+	}
+	if err := utils.CheckSpireAgentVersionSkew(server.Labels[utils.VersionLabelKey], target); err != nil {
+		r.log.Error(err, "unsafe SPIRE agent version change", "current", current, "desired", target)
+		statusMgr.AddCondition(utils.UpgradeAllowedStatusType, utils.UnsafeVersionSkew,
+			err.Error(),
+			metav1.ConditionFalse)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package spire_agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newVersionedDaemonSet(version string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "spire-agent",
+			Labels: map[string]string{utils.VersionLabelKey: version},
+		},
+	}
+}
+
+func newSpireServerStatefulSet(version string, ready bool) *appsv1.StatefulSet {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       spireServerStatefulSetName,
+			Labels:     map[string]string{utils.VersionLabelKey: version},
+			Generation: 1,
+		},
+		Spec: appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1))},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			CurrentReplicas:    1,
+			UpdatedReplicas:    1,
+		},
+	}
+	if ready {
+		sts.Status.ReadyReplicas = 1
+		sts.Status.AvailableReplicas = 1
+	}
+	return sts
+}
+
+func TestUpgradeAllowed(t *testing.T) {
+	tests := []struct {
+		name            string
+		current         string
+		desired         string
+		server          *appsv1.StatefulSet
+		expectAllowed   bool
+		expectGet       bool
+		expectCondition *metav1.Condition
+	}{
+		{
+			name:          "unchanged agent version",
+			current:       "1.13.3",
+			desired:       "1.13.3",
+			expectAllowed: true,
+		},
+		{
+			name:          "server already upgraded",
+			current:       "1.12.4",
+			desired:       "1.13.3",
+			server:        newSpireServerStatefulSet("1.13.3", true),
+			expectAllowed: true,
+			expectGet:     true,
+		},
+		{
+			name:            "server rollout in progress",
+			current:         "1.12.4",
+			desired:         "1.13.3",
+			server:          newSpireServerStatefulSet("1.13.3", false),
+			expectGet:       true,
+			expectCondition: &metav1.Condition{Status: metav1.ConditionFalse, Reason: utils.WaitingForSpireServer},
+		},
+		{
+			name:            "server not found",
+			current:         "1.12.4",
+			desired:         "1.13.3",
+			expectGet:       true,
+			expectCondition: &metav1.Condition{Status: metav1.ConditionFalse, Reason: utils.WaitingForSpireServer},
+		},
+		{
+			name:            "agent newer than the server",
+			current:         "1.12.4",
+			desired:         "1.13.3",
+			server:          newSpireServerStatefulSet("1.12.4", true),
+			expectGet:       true,
+			expectCondition: &metav1.Condition{Status: metav1.ConditionFalse, Reason: utils.UnsafeVersionSkew},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+				if tt.server == nil {
+					return kerrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, key.Name)
+				}
+				tt.server.DeepCopyInto(obj.(*appsv1.StatefulSet))
+				return nil
+			}
+			reconciler := newTestReconciler(fakeClient)
+			agent := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+			statusMgr := status.NewManager(fakeClient)
+			allowed := reconciler.upgradeAllowed(context.Background(), agent, newVersionedDaemonSet(tt.current), newVersionedDaemonSet(tt.desired), statusMgr)
+			if allowed != tt.expectAllowed {
+				t.Errorf("Expected allowed=%v, got %v", tt.expectAllowed, allowed)
+			}
+			if got := fakeClient.GetCallCount() > 0; got != tt.expectGet {
+				t.Errorf("Expected the spire server StatefulSet lookup=%v, got %d Get calls", tt.expectGet, fakeClient.GetCallCount())
+			}
+
+			if err := statusMgr.ApplyStatus(context.Background(), agent, func() *v1alpha1.ConditionalStatus {
+				return &agent.Status.ConditionalStatus
+			}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			cond := apimeta.FindStatusCondition(agent.Status.Conditions, utils.UpgradeAllowedStatusType)
+			if tt.expectCondition == nil {
+				if cond != nil {
+					t.Errorf("Expected no UpgradeAllowed condition, got %+v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Status != tt.expectCondition.Status || cond.Reason != tt.expectCondition.Reason {
+				t.Errorf("Expected UpgradeAllowed %s with reason %s, got %+v", tt.expectCondition.Status, tt.expectCondition.Reason, cond)
+			}
+		})
+	}
+}
@@ -15,10 +15,10 @@ import (
 	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
 )
 
-// reconcileClusterSpiffeIDs reconciles the ClusterSpiffeID resources
-func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, createOnlyMode bool) error {
+// reconcileClusterSpiffeIDs reconciles the ClusterSpiffeID resources, templated by the controllerManager config of the SpireServer
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, controllerManager *v1alpha1.ControllerManagerConfig, statusMgr *status.Manager, createOnlyMode bool) error {
 	// Reconcile OIDC Discovery Provider ClusterSPIFFEID
-	desiredOIDC := generateSpireIODCDiscoveryProviderSpiffeID(oidc.Spec.Labels)
+	desiredOIDC := generateSpireIODCDiscoveryProviderSpiffeID(oidc.Spec.Labels, controllerManager)
 	if err := controllerutil.SetControllerReference(oidc, desiredOIDC, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference for OIDC ClusterSPIFFEID")
 		statusMgr.AddCondition(ClusterSPIFFEIDAvailable, "SpireClusterSpiffeIDGenerationFailed",
@@ -26,6 +26,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desiredOIDC)
 
 	// Get existing OIDC ClusterSPIFFEID (from cache)
 	existingOIDC := &spiffev1alpha1.ClusterSPIFFEID{}
@@ -50,6 +51,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 			return err
 		}
 		r.log.Info("Created OIDC ClusterSPIFFEID", "name", desiredOIDC.Name)
+		statusMgr.RecordResourceCreated(desiredOIDC)
 	} else {
 		// Resource exists, check if we need to update
 		if utils.ResourceNeedsUpdate(existingOIDC, desiredOIDC) {
@@ -67,6 +69,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 					return err
 				}
 				r.log.Info("Updated OIDC ClusterSPIFFEID", "name", desiredOIDC.Name)
+				statusMgr.RecordDriftRepaired(desiredOIDC)
 			}
 		} else {
 			r.log.V(1).Info("OIDC ClusterSPIFFEID is up to date", "name", desiredOIDC.Name)
@@ -74,7 +77,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 	}
 
 	// Reconcile Default Fallback ClusterSPIFFEID
-	desiredDefault := generateDefaultFallbackClusterSPIFFEID(oidc.Spec.Labels)
+	desiredDefault := generateDefaultFallbackClusterSPIFFEID(oidc.Spec.Labels, controllerManager)
 	if err = controllerutil.SetControllerReference(oidc, desiredDefault, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference for default ClusterSPIFFEID")
 		statusMgr.AddCondition(ClusterSPIFFEIDAvailable, "SpireClusterSpiffeIDGenerationFailed",
@@ -82,6 +85,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desiredDefault)
 
 	// Get existing Default ClusterSPIFFEID (from cache)
 	existingDefault := &spiffev1alpha1.ClusterSPIFFEID{}
@@ -106,6 +110,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 			return err
 		}
 		r.log.Info("Created Default ClusterSPIFFEID", "name", desiredDefault.Name)
+		statusMgr.RecordResourceCreated(desiredDefault)
 	} else {
 		// Resource exists, check if we need to update
 		if utils.ResourceNeedsUpdate(existingDefault, desiredDefault) {
@@ -123,6 +128,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 					return err
 				}
 				r.log.Info("Updated Default ClusterSPIFFEID", "name", desiredDefault.Name)
+				statusMgr.RecordDriftRepaired(desiredDefault)
 			}
 		} else {
 			r.log.V(1).Info("Default ClusterSPIFFEID is up to date", "name", desiredDefault.Name)
@@ -135,14 +141,14 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileClusterSpiffeIDs(ctx con
 	return nil
 }
 
-func generateSpireIODCDiscoveryProviderSpiffeID(customLabels map[string]string) *spiffev1alpha1.ClusterSPIFFEID {
+func generateSpireIODCDiscoveryProviderSpiffeID(customLabels map[string]string, controllerManager *v1alpha1.ControllerManagerConfig) *spiffev1alpha1.ClusterSPIFFEID {
 	clusterSpiffeID := &spiffev1alpha1.ClusterSPIFFEID{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   "zero-trust-workload-identity-manager-spire-oidc-discovery-provider",
 			Labels: utils.SpireOIDCDiscoveryProviderLabels(customLabels),
 		},
 		Spec: spiffev1alpha1.ClusterSPIFFEIDSpec{
-			ClassName:        "zero-trust-workload-identity-manager-spire",
+			ClassName:        utils.GetControllerManagerClassName(controllerManager),
 			Hint:             "oidc-discovery-provider",
 			SPIFFEIDTemplate: "spiffe://{{ .TrustDomain }}/ns/{{ .PodMeta.Namespace }}/sa/{{ .PodSpec.ServiceAccountName }}",
 			DNSNameTemplates: []string{
@@ -162,7 +168,7 @@ func generateSpireIODCDiscoveryProviderSpiffeID(customLabels map[string]string)
 						Key:      "kubernetes.io/metadata.name",
 						Operator: metav1.LabelSelectorOpIn,
 						Values: []string{
-							utils.GetOperatorNamespace(),
+							utils.GetOperandNamespace(),
 						},
 					},
 				},
@@ -172,29 +178,47 @@ func generateSpireIODCDiscoveryProviderSpiffeID(customLabels map[string]string)
 	return clusterSpiffeID
 }
 
-func generateDefaultFallbackClusterSPIFFEID(customLabels map[string]string) *spiffev1alpha1.ClusterSPIFFEID {
+// generateDefaultFallbackClusterSPIFFEID returns the ClusterSPIFFEID issuing identities to the workloads no other
+// ClusterSPIFFEID matches, with the SPIFFE ID and DNS name templates and the selectors of controllerManager when set
+func generateDefaultFallbackClusterSPIFFEID(customLabels map[string]string, controllerManager *v1alpha1.ControllerManagerConfig) *spiffev1alpha1.ClusterSPIFFEID {
 	clusterSpiffeID := &spiffev1alpha1.ClusterSPIFFEID{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   "zero-trust-workload-identity-manager-spire-default",
 			Labels: utils.SpireOIDCDiscoveryProviderLabels(customLabels),
 		},
 		Spec: spiffev1alpha1.ClusterSPIFFEIDSpec{
-			ClassName:        "zero-trust-workload-identity-manager-spire",
-			Hint:             "default",
-			SPIFFEIDTemplate: "spiffe://{{ .TrustDomain }}/ns/{{ .PodMeta.Namespace }}/sa/{{ .PodSpec.ServiceAccountName }}",
-			Fallback:         true,
-			NamespaceSelector: &metav1.LabelSelector{
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      "kubernetes.io/metadata.name",
-						Operator: metav1.LabelSelectorOpNotIn,
-						Values: []string{
-							utils.GetOperatorNamespace(),
-						},
-					},
-				},
-			},
+			ClassName:         utils.GetControllerManagerClassName(controllerManager),
+			Hint:              "default",
+			SPIFFEIDTemplate:  utils.DefaultSPIFFEIDTemplate,
+			Fallback:          true,
+			NamespaceSelector: &metav1.LabelSelector{},
 		},
 	}
+
+	if controllerManager != nil {
+		if controllerManager.DefaultSPIFFEIDTemplate != "" {
+			clusterSpiffeID.Spec.SPIFFEIDTemplate = controllerManager.DefaultSPIFFEIDTemplate
+		}
+		if controllerManager.NamespaceSelector != nil {
+			clusterSpiffeID.Spec.NamespaceSelector = controllerManager.NamespaceSelector.DeepCopy()
+		}
+		if controllerManager.PodSelector != nil {
+			clusterSpiffeID.Spec.PodSelector = controllerManager.PodSelector.DeepCopy()
+		}
+		if len(controllerManager.DNSNameTemplates) > 0 {
+			clusterSpiffeID.Spec.DNSNameTemplates = append([]string(nil), controllerManager.DNSNameTemplates...)
+		}
+		clusterSpiffeID.Spec.AutoPopulateDNSNames = utils.StringToBool(controllerManager.AutoPopulateDNSNames)
+	}
+
+	// The operand namespace workloads get their identities from the component ClusterSPIFFEIDs
+	clusterSpiffeID.Spec.NamespaceSelector.MatchExpressions = append(clusterSpiffeID.Spec.NamespaceSelector.MatchExpressions,
+		metav1.LabelSelectorRequirement{
+			Key:      "kubernetes.io/metadata.name",
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values: []string{
+				utils.GetOperandNamespace(),
+			},
+		})
 	return clusterSpiffeID
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -110,7 +111,7 @@ func TestReconcileClusterSpiffeIDs(t *testing.T) {
 			fakeClient.CreateReturns(tt.createError)
 			fakeClient.UpdateReturns(tt.updateError)
 
-			err := reconciler.reconcileClusterSpiffeIDs(context.Background(), oidc, statusMgr, tt.createOnlyMode)
+			err := reconciler.reconcileClusterSpiffeIDs(context.Background(), oidc, nil, statusMgr, tt.createOnlyMode)
 
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -131,7 +132,7 @@ func TestReconcileClusterSpiffeIDs(t *testing.T) {
 func TestGenerateClusterSPIFFEIDs(t *testing.T) {
 	tests := []struct {
 		name         string
-		genFunc      func(map[string]string) *spiffev1alpha1.ClusterSPIFFEID
+		genFunc      func(map[string]string, *v1alpha1.ControllerManagerConfig) *spiffev1alpha1.ClusterSPIFFEID
 		expectedName string
 		customLabels map[string]string
 		checkCustom  bool
@@ -164,7 +165,7 @@ func TestGenerateClusterSPIFFEIDs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			csid := tt.genFunc(tt.customLabels)
+			csid := tt.genFunc(tt.customLabels, nil)
 			if csid == nil {
 				t.Fatal("Expected non-nil ClusterSPIFFEID")
 			}
@@ -202,3 +203,55 @@ func createClusterSpiffeIDTestOIDCCR() *v1alpha1.SpireOIDCDiscoveryProvider {
 		},
 	}
 }
+
+func TestGenerateClusterSPIFFEIDsWithControllerManager(t *testing.T) {
+	controllerManager := &v1alpha1.ControllerManagerConfig{
+		ClassName:               "acme-spire",
+		DefaultSPIFFEIDTemplate: "spiffe://{{ .TrustDomain }}/team/{{ index .PodMeta.Labels \"team\" }}",
+		NamespaceSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"spiffe": "enabled"}},
+		PodSelector:             &metav1.LabelSelector{MatchLabels: map[string]string{"identity": "default"}},
+		DNSNameTemplates:        []string{"{{ .PodMeta.Name }}.{{ .PodMeta.Namespace }}.svc"},
+		AutoPopulateDNSNames:    "true",
+	}
+
+	oidcCSID := generateSpireIODCDiscoveryProviderSpiffeID(nil, controllerManager)
+	if oidcCSID.Spec.ClassName != "acme-spire" {
+		t.Errorf("Expected the OIDC ClusterSPIFFEID class name %q, got %q", "acme-spire", oidcCSID.Spec.ClassName)
+	}
+
+	defaultCSID := generateDefaultFallbackClusterSPIFFEID(nil, controllerManager)
+	if defaultCSID.Spec.ClassName != "acme-spire" {
+		t.Errorf("Expected the default ClusterSPIFFEID class name %q, got %q", "acme-spire", defaultCSID.Spec.ClassName)
+	}
+	if defaultCSID.Spec.SPIFFEIDTemplate != controllerManager.DefaultSPIFFEIDTemplate {
+		t.Errorf("Expected SPIFFE ID template %q, got %q", controllerManager.DefaultSPIFFEIDTemplate, defaultCSID.Spec.SPIFFEIDTemplate)
+	}
+	if defaultCSID.Spec.PodSelector == nil || defaultCSID.Spec.PodSelector.MatchLabels["identity"] != "default" {
+		t.Errorf("Expected the configured pod selector, got %v", defaultCSID.Spec.PodSelector)
+	}
+	if len(defaultCSID.Spec.DNSNameTemplates) != 1 || defaultCSID.Spec.DNSNameTemplates[0] != controllerManager.DNSNameTemplates[0] {
+		t.Errorf("Expected the configured DNS name templates, got %v", defaultCSID.Spec.DNSNameTemplates)
+	}
+	if !defaultCSID.Spec.AutoPopulateDNSNames {
+		t.Error("Expected the DNS names of the Services to be auto-populated")
+	}
+	namespaceSelector := defaultCSID.Spec.NamespaceSelector
+	if namespaceSelector.MatchLabels["spiffe"] != "enabled" {
+		t.Errorf("Expected the configured namespace selector, got %v", namespaceSelector)
+	}
+	if len(namespaceSelector.MatchExpressions) != 1 || namespaceSelector.MatchExpressions[0].Operator != metav1.LabelSelectorOpNotIn {
+		t.Errorf("Expected the operand namespace to stay excluded, got %v", namespaceSelector.MatchExpressions)
+	}
+	if len(controllerManager.NamespaceSelector.MatchExpressions) != 0 {
+		t.Error("Expected the SpireServer namespace selector not to be modified")
+	}
+
+	// Without a controllerManager config the defaults are kept
+	defaultCSID = generateDefaultFallbackClusterSPIFFEID(nil, nil)
+	if defaultCSID.Spec.ClassName != utils.DefaultSpireControllerManagerClassName || defaultCSID.Spec.SPIFFEIDTemplate != utils.DefaultSPIFFEIDTemplate {
+		t.Errorf("Expected the default class name and template, got %q and %q", defaultCSID.Spec.ClassName, defaultCSID.Spec.SPIFFEIDTemplate)
+	}
+	if defaultCSID.Spec.PodSelector != nil || len(defaultCSID.Spec.DNSNameTemplates) != 0 || defaultCSID.Spec.AutoPopulateDNSNames {
+		t.Errorf("Expected no pod selector nor DNS names, got %v", defaultCSID.Spec)
+	}
+}
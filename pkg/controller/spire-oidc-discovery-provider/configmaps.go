@@ -17,11 +17,14 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 // reconcileConfigMap reconciles the OIDC Discovery Provider ConfigMap
-func (r *SpireOidcDiscoveryProviderReconciler) reconcileConfigMap(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) (string, error) {
-	cm, err := generateOIDCConfigMapFromCR(oidc, ztwim)
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileConfigMap(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, agentSocketName string, createOnlyMode bool) (string, error) {
+	_, renderSpan := tracing.Start(ctx, "Render OIDC discovery provider ConfigMap")
+	cm, err := generateOIDCConfigMapFromCR(oidc, ztwim, agentSocketName)
+	tracing.End(renderSpan, err)
 	if err != nil {
 		r.log.Error(err, "failed to generate OIDC ConfigMap from CR")
 		statusMgr.AddCondition(ConfigMapAvailable, "SpireOIDCConfigMapCreationFailed",
@@ -37,6 +40,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileConfigMap(ctx context.Co
 			metav1.ConditionFalse)
 		return "", err
 	}
+	statusMgr.TrackResource(cm)
 
 	var existingOidcCm corev1.ConfigMap
 	err = r.ctrlClient.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, &existingOidcCm)
@@ -49,6 +53,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileConfigMap(ctx context.Co
 			return "", err
 		}
 		r.log.Info("Created ConfigMap", "Namespace", cm.Namespace, "Name", cm.Name)
+		statusMgr.RecordResourceCreated(cm)
 	} else if err == nil && (utils.GenerateMapHash(existingOidcCm.Data) != utils.GenerateMapHash(cm.Data) ||
 		!equality.Semantic.DeepEqual(existingOidcCm.Labels, cm.Labels)) {
 		if createOnlyMode {
@@ -63,6 +68,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileConfigMap(ctx context.Co
 				return "", err
 			}
 			r.log.Info("Updated ConfigMap", "Namespace", cm.Namespace, "Name", cm.Name)
+			statusMgr.RecordDriftRepaired(cm)
 		}
 	} else if err != nil {
 		r.log.Error(err, "Failed to get ConfigMap")
@@ -79,16 +85,13 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileConfigMap(ctx context.Co
 	return utils.GenerateMapHash(cm.Data), nil
 }
 
-// generateOIDCConfigMapFromCR creates a ConfigMap for the spire oidc discovery provider from the CR spec
-func generateOIDCConfigMapFromCR(dp *v1alpha1.SpireOIDCDiscoveryProvider, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) (*corev1.ConfigMap, error) {
+// generateOIDCConfigMapFromCR creates a ConfigMap for the spire oidc discovery provider from the CR spec,
+// reaching the SPIRE agent through its Workload API socket agentSocketName
+func generateOIDCConfigMapFromCR(dp *v1alpha1.SpireOIDCDiscoveryProvider, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, agentSocketName string) (*corev1.ConfigMap, error) {
 	if dp == nil {
 		return nil, errors.New("spire OIDC Discovery Provider Config is nil")
 	}
 
-	// Socket filename is hardcoded to match SPIRE Agent configuration
-	// The SPIRE Agent creates the socket with filename "spire-agent.sock" (hardcoded in agent config)
-	const agentSocketName = "spire-agent.sock"
-
 	// Determine trust domain
 	trustDomain := ztwim.Spec.TrustDomain
 
@@ -98,8 +101,8 @@ func generateOIDCConfigMapFromCR(dp *v1alpha1.SpireOIDCDiscoveryProvider, ztwim
 		return nil, fmt.Errorf("invalid JWT issuer URL: %w", err)
 	}
 	// OIDC config map data
-	oidcDefaultDomain := "spire-spiffe-oidc-discovery-provider." + utils.GetOperatorNamespace()
-	oidcSVCDomain := "spire-spiffe-oidc-discovery-provider." + utils.GetOperatorNamespace() + ".svc.cluster.local"
+	oidcDefaultDomain := "spire-spiffe-oidc-discovery-provider." + utils.GetOperandNamespace()
+	oidcSVCDomain := "spire-spiffe-oidc-discovery-provider." + utils.GetOperandNamespace() + ".svc.cluster.local"
 	oidcConfig := map[string]interface{}{
 		"domains": []string{
 			"spire-spiffe-oidc-discovery-provider",
@@ -133,7 +136,7 @@ func generateOIDCConfigMapFromCR(dp *v1alpha1.SpireOIDCDiscoveryProvider, ztwim
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-spiffe-oidc-discovery-provider",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    utils.SpireOIDCDiscoveryProviderLabels(dp.Spec.Labels),
 		},
 		Data: map[string]string{
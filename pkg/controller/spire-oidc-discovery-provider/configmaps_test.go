@@ -37,7 +37,7 @@ func TestReconcileConfigMap(t *testing.T) {
 		fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, "spire-oidc-discovery-provider"))
 		fakeClient.CreateReturns(nil)
 
-		hash, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		hash, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
@@ -61,7 +61,7 @@ func TestReconcileConfigMap(t *testing.T) {
 		fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, "spire-oidc-discovery-provider"))
 		fakeClient.CreateReturns(errors.New("create failed"))
 
-		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err == nil {
 			t.Error("Expected error when Create fails")
@@ -78,7 +78,7 @@ func TestReconcileConfigMap(t *testing.T) {
 
 		fakeClient.GetReturns(errors.New("connection refused"))
 
-		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err == nil {
 			t.Error("Expected error when Get fails")
@@ -112,7 +112,7 @@ func TestReconcileConfigMap(t *testing.T) {
 		}
 		fakeClient.UpdateReturns(nil)
 
-		hash, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		hash, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
@@ -152,7 +152,7 @@ func TestReconcileConfigMap(t *testing.T) {
 		}
 		fakeClient.UpdateReturns(errors.New("update conflict"))
 
-		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err == nil {
 			t.Error("Expected error when Update fails")
@@ -185,7 +185,7 @@ func TestReconcileConfigMap(t *testing.T) {
 			return nil
 		}
 
-		hash, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, true)
+		hash, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, true)
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
@@ -212,7 +212,7 @@ func TestReconcileConfigMap(t *testing.T) {
 		ztwim := createOIDCTestZTWIM()
 		statusMgr := status.NewManager(fakeClient)
 
-		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err == nil {
 			t.Error("Expected error when SetControllerReference fails")
@@ -227,7 +227,7 @@ func TestReconcileConfigMap(t *testing.T) {
 		ztwim := createOIDCTestZTWIM()
 		statusMgr := status.NewManager(fakeClient)
 
-		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, false)
+		_, err := reconciler.reconcileConfigMap(context.Background(), oidc, statusMgr, ztwim, utils.DefaultAgentSocketName, false)
 
 		if err == nil {
 			t.Error("Expected error when CR is nil")
@@ -239,7 +239,7 @@ func TestReconcileConfigMap(t *testing.T) {
 func TestGenerateOIDCConfigMapFromCR_NilConfig(t *testing.T) {
 	ztwim := createOIDCTestZTWIM()
 
-	_, err := generateOIDCConfigMapFromCR(nil, ztwim)
+	_, err := generateOIDCConfigMapFromCR(nil, ztwim, utils.DefaultAgentSocketName)
 
 	if err == nil {
 		t.Error("Expected error when config is nil")
@@ -304,7 +304,7 @@ func TestGenerateOIDCConfigMapFromCR(t *testing.T) {
 		}
 
 		// Act
-		result, err := generateOIDCConfigMapFromCR(cr, ztwim)
+		result, err := generateOIDCConfigMapFromCR(cr, ztwim, utils.DefaultAgentSocketName)
 
 		// Assert
 		require.NoError(t, err)
@@ -366,7 +366,7 @@ func TestGenerateOIDCConfigMapFromCR(t *testing.T) {
 		}
 
 		// Act
-		result, err := generateOIDCConfigMapFromCR(cr, ztwim)
+		result, err := generateOIDCConfigMapFromCR(cr, ztwim, utils.DefaultAgentSocketName)
 
 		// Assert
 		require.NoError(t, err)
@@ -415,7 +415,7 @@ func TestGenerateOIDCConfigMapFromCR(t *testing.T) {
 		}
 
 		// Act
-		result, err := generateOIDCConfigMapFromCR(cr, ztwim)
+		result, err := generateOIDCConfigMapFromCR(cr, ztwim, utils.DefaultAgentSocketName)
 
 		// Assert
 		require.NoError(t, err)
@@ -463,7 +463,7 @@ func TestOIDCConfigJSONFormatting(t *testing.T) {
 		},
 	}
 
-	result, err := generateOIDCConfigMapFromCR(cr, ztwim)
+	result, err := generateOIDCConfigMapFromCR(cr, ztwim, utils.DefaultAgentSocketName)
 	require.NoError(t, err)
 
 	oidcJSON := result.Data["oidc-discovery-provider.conf"]
@@ -477,3 +477,16 @@ func TestOIDCConfigJSONFormatting(t *testing.T) {
 	err = json.Unmarshal([]byte(oidcJSON), &temp)
 	assert.NoError(t, err)
 }
+
+func TestOIDCConfigAgentSocketName(t *testing.T) {
+	cr := &v1alpha1.SpireOIDCDiscoveryProvider{
+		Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{JwtIssuer: "https://oidc.example.org"},
+	}
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	result, err := generateOIDCConfigMapFromCR(cr, ztwim, "socket")
+	require.NoError(t, err)
+	assert.Contains(t, result.Data["oidc-discovery-provider.conf"], `"socket_path": "/spiffe-workload-api/socket"`)
+}
@@ -2,14 +2,20 @@ package spire_oidc_discovery_provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/go-logr/logr"
 	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -23,27 +29,36 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/operatorconfig"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 const spireOidcDeploymentSpireOidcConfigHashAnnotationKey = "ztwim.openshift.io/spire-oidc-discovery-provider-config-hash"
 
 const (
-	DeploymentAvailable      = "DeploymentAvailable"
-	ConfigMapAvailable       = "ConfigMapAvailable"
-	ClusterSPIFFEIDAvailable = "ClusterSPIFFEIDAvailable"
-	RouteAvailable           = "RouteAvailable"
-	RBACAvailable            = "RBACAvailable"
-	ConfigurationValid       = "ConfigurationValid"
-	ServiceAccountAvailable  = "ServiceAccountAvailable"
-	ServiceAvailable         = "ServiceAvailable"
+	DeploymentAvailable              = "DeploymentAvailable"
+	ConfigMapAvailable               = "ConfigMapAvailable"
+	ClusterSPIFFEIDAvailable         = "ClusterSPIFFEIDAvailable"
+	RouteAvailable                   = "RouteAvailable"
+	RBACAvailable                    = "RBACAvailable"
+	ConfigurationValid               = "ConfigurationValid"
+	ServiceAccountAvailable          = "ServiceAccountAvailable"
+	ServiceAvailable                 = "ServiceAvailable"
+	PodDisruptionBudgetAvailable     = "PodDisruptionBudgetAvailable"
+	HorizontalPodAutoscalerAvailable = "HorizontalPodAutoscalerAvailable"
+	NetworkPolicyAvailable           = "NetworkPolicyAvailable"
+	IngressAvailable                 = "IngressAvailable"
 )
 
 // SpireOidcDiscoveryProviderReconciler reconciles a SpireOidcDiscoveryProvider object
@@ -53,11 +68,14 @@ type SpireOidcDiscoveryProviderReconciler struct {
 	eventRecorder record.EventRecorder
 	log           logr.Logger
 	scheme        *runtime.Scheme
+
+	// failures counts the consecutive failed reconciles, to report the CRs that keep failing as Degraded
+	failures *utils.FailureTracker
 }
 
 // New returns a new Reconciler instance.
-func New(mgr ctrl.Manager) (*SpireOidcDiscoveryProviderReconciler, error) {
-	c, err := customClient.NewCustomClient(mgr)
+func New(mgr ctrl.Manager, clientOpts ...customClient.Option) (*SpireOidcDiscoveryProviderReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -67,10 +85,11 @@ func New(mgr ctrl.Manager) (*SpireOidcDiscoveryProviderReconciler, error) {
 		eventRecorder: mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName),
 		log:           ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName),
 		scheme:        mgr.GetScheme(),
+		failures:      utils.NewFailureTracker(),
 	}, nil
 }
 
-func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	r.log.Info(fmt.Sprintf("reconciling %s", utils.ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName))
 
 	var oidcDiscoveryProviderConfig v1alpha1.SpireOIDCDiscoveryProvider
@@ -82,13 +101,42 @@ func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, re
 		return ctrl.Result{}, err
 	}
 
+	// Tear down the managed resources in order when the CR is being deleted. The operand namespace
+	// is resolved first, as the operator may have restarted since the namespace was last configured.
+	if !oidcDiscoveryProviderConfig.DeletionTimestamp.IsZero() {
+		namespace, err := utils.ResolveOperandNamespace(ctx, r.ctrlClient, oidcDiscoveryProviderConfig.Status.ManagedResources)
+		if err != nil {
+			r.log.Error(err, "failed to resolve the operand namespace")
+			return ctrl.Result{}, err
+		}
+		return r.reconcileDeletion(ctx, &oidcDiscoveryProviderConfig, namespace)
+	}
+
+	// Leave the managed resources untouched while reconciliation is paused
+	if utils.StringToBool(oidcDiscoveryProviderConfig.Spec.Paused) {
+		r.log.Info("SpireOIDCDiscoveryProvider reconciliation is paused, skipping", "name", oidcDiscoveryProviderConfig.Name)
+		if err := status.SetPausedStatus(ctx, r.ctrlClient, &oidcDiscoveryProviderConfig, func() *v1alpha1.ConditionalStatus {
+			return &oidcDiscoveryProviderConfig.Status.ConditionalStatus
+		}); err != nil {
+			r.log.Error(err, "failed to update status")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Set Ready to false at the start of reconciliation
 	status.SetInitialReconciliationStatus(ctx, r.ctrlClient, &oidcDiscoveryProviderConfig, func() *v1alpha1.ConditionalStatus {
 		return &oidcDiscoveryProviderConfig.Status.ConditionalStatus
 	}, "SpireOIDCDiscoveryProvider")
 
+	var dryRun *customClient.DryRunClient
+	var adoption *customClient.AdoptionClient
+	var exclusion *customClient.ExclusionClient
 	statusMgr := status.NewManager(r.ctrlClient)
 	defer func() {
+		statusMgr.SetDryRunStatus(r.eventRecorder, &oidcDiscoveryProviderConfig, oidcDiscoveryProviderConfig.Status.ConditionalStatus.Conditions, dryRun)
+		statusMgr.SetExcludedResourcesStatus(oidcDiscoveryProviderConfig.Status.ConditionalStatus.Conditions, exclusion)
+		statusMgr.SetDegradedCondition(oidcDiscoveryProviderConfig.Status.ConditionalStatus.Conditions, r.failures.Observe(req.NamespacedName, reconcileErr), reconcileErr)
+		statusMgr.RecordResourcesAdopted(adoption)
 		if err := statusMgr.ApplyStatus(ctx, &oidcDiscoveryProviderConfig, func() *v1alpha1.ConditionalStatus {
 			return &oidcDiscoveryProviderConfig.Status.ConditionalStatus
 		}); err != nil {
@@ -108,6 +156,22 @@ func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, re
 		return ctrl.Result{}, err
 	}
 
+	// Install the operands in the namespace configured on the ZTWIM
+	if err := utils.ConfigureOperandNamespace(ztwim.Spec.OperandNamespace); err != nil {
+		r.log.Error(err, "invalid operand namespace")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed, err.Error(), metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
+	// Leave the operand to the operator running in the other cluster of the topology
+	if !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireOIDCDiscoveryProvider) {
+		r.log.Info("SpireOIDCDiscoveryProvider is not deployed in this cluster, skipping", "topology", utils.GetTopologyMode(&ztwim))
+		statusMgr.AddCondition(v1alpha1.Ready, utils.ConditionReasonNotDeployedInTopology,
+			fmt.Sprintf("SpireOIDCDiscoveryProvider is not deployed in the %s topology", utils.GetTopologyMode(&ztwim)),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Set ZTWIM as the owner of SpireOidcDiscoveryProvider only if needed
 	if utils.NeedsOwnerReferenceUpdate(&oidcDiscoveryProviderConfig, &ztwim) {
 		if err := controllerutil.SetControllerReference(&ztwim, &oidcDiscoveryProviderConfig, r.scheme); err != nil {
@@ -128,9 +192,82 @@ func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, re
 		}
 	}
 
+	// Add the cleanup finalizer so that deleting the CR tears down the managed resources in order
+	if controllerutil.AddFinalizer(&oidcDiscoveryProviderConfig, utils.OperandCleanupFinalizer) {
+		if err := r.ctrlClient.Update(ctx, &oidcDiscoveryProviderConfig); err != nil {
+			r.log.Error(err, "failed to add finalizer to SpireOIDCDiscoveryProvider")
+			statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+				fmt.Sprintf("Failed to add finalizer to SpireOIDCDiscoveryProvider: %v", err),
+				metav1.ConditionFalse)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In dry-run mode, record the writes made below as planned changes instead of applying them
+	if utils.IsDryRunMode(oidcDiscoveryProviderConfig.Spec.ReconcileMode) {
+		r.log.Info("Running in dry-run mode - planned changes are reported in status and not applied")
+		dryRun = customClient.NewDryRunClient(r.ctrlClient, r.scheme)
+		dryRunReconciler := *r
+		dryRunReconciler.ctrlClient = dryRun
+		r = &dryRunReconciler
+	}
+
+	// Adopt the resources of the same name created by another installer, e.g. the SPIRE Helm charts
+	if utils.StringToBool(oidcDiscoveryProviderConfig.Spec.AdoptExistingResources) {
+		adoption = customClient.NewAdoptionClient(r.ctrlClient)
+		adoptionReconciler := *r
+		adoptionReconciler.ctrlClient = adoption
+		r = &adoptionReconciler
+	}
+
+	// Leave the resources excluded from management to their owner
+	if len(oidcDiscoveryProviderConfig.Spec.ExcludedResources) > 0 {
+		exclusion = customClient.NewExclusionClient(r.ctrlClient, r.scheme, oidcDiscoveryProviderConfig.Spec.ExcludedResources)
+		exclusionReconciler := *r
+		exclusionReconciler.ctrlClient = exclusion
+		r = &exclusionReconciler
+	}
+
+	// Explain the significant reconcile actions in events on the CR
+	statusMgr.SetEventRecorder(r.eventRecorder, &oidcDiscoveryProviderConfig, dryRun != nil)
+
+	// Leave the managed resources as they are or delete them as set by the management state
+	managementState := utils.GetManagementState(oidcDiscoveryProviderConfig.Spec.ManagementState, &ztwim)
+	statusMgr.SetManagementStateCondition(managementState, "SpireOIDCDiscoveryProvider", oidcDiscoveryProviderConfig.Status.Conditions)
+	switch managementState {
+	case v1alpha1.ManagementStateUnmanaged:
+		r.log.Info("SpireOIDCDiscoveryProvider is unmanaged, skipping", "name", oidcDiscoveryProviderConfig.Name)
+		return ctrl.Result{}, nil
+	case v1alpha1.ManagementStateRemoved:
+		return ctrl.Result{}, r.reconcileRemoval(ctx, &oidcDiscoveryProviderConfig, utils.OperandNamespaceOf(&ztwim), statusMgr)
+	}
+
 	// Handle create-only mode
 	createOnlyMode := r.handleCreateOnlyMode(&oidcDiscoveryProviderConfig, statusMgr)
 
+	// Wait for the cluster to pass the preflight checks of the ZeroTrustWorkloadIdentityManager before the first deployment
+	if message, blocked := utils.PreflightChecksBlockDeployment(&ztwim, oidcDiscoveryProviderConfig.Status.Conditions, DeploymentAvailable); blocked {
+		r.log.Info("Waiting for the preflight checks to pass before deploying SpireOIDCDiscoveryProvider", "message", message)
+		statusMgr.AddCondition(v1alpha1.Ready, utils.PreflightChecksFailed,
+			fmt.Sprintf("Waiting for the preflight checks of the ZeroTrustWorkloadIdentityManager: %s", message),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
+	// The SpireServer provides the JWT issuer and the identity templating of the spire-controller-manager
+	server, err := r.getSpireServer(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Serve the JWT issuer of the SpireServer so that the discovery document matches the JWT-SVIDs
+	if err := r.resolveJWTIssuer(&oidcDiscoveryProviderConfig, server, statusMgr); err != nil {
+		if errors.Is(err, errJWTIssuerMismatch) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Validate configuration
 	if err := r.validateConfiguration(ctx, &oidcDiscoveryProviderConfig, statusMgr); err != nil {
 		return ctrl.Result{}, nil
@@ -146,18 +283,43 @@ func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, re
 	}
 
 	// Reconcile ClusterSpiffeIDs
-	if err := r.reconcileClusterSpiffeIDs(ctx, &oidcDiscoveryProviderConfig, statusMgr, createOnlyMode); err != nil {
+	var controllerManager *v1alpha1.ControllerManagerConfig
+	if server != nil {
+		controllerManager = server.Spec.ControllerManager
+	}
+	if err := r.reconcileClusterSpiffeIDs(ctx, &oidcDiscoveryProviderConfig, controllerManager, statusMgr, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The SpireAgent names the Workload API socket the provider reaches the agent through
+	agent, err := r.getSpireAgent(ctx)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile ConfigMap
-	configHash, err := r.reconcileConfigMap(ctx, &oidcDiscoveryProviderConfig, statusMgr, &ztwim, createOnlyMode)
+	configHash, err := r.reconcileConfigMap(ctx, &oidcDiscoveryProviderConfig, statusMgr, &ztwim, utils.GetAgentSocketName(agent), createOnlyMode)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// Reconcile Deployment
-	if err := r.reconcileDeployment(ctx, &oidcDiscoveryProviderConfig, statusMgr, createOnlyMode, configHash); err != nil {
+	if err := r.reconcileDeployment(ctx, &oidcDiscoveryProviderConfig, statusMgr, &ztwim, createOnlyMode, configHash); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile PodDisruptionBudget
+	if err := r.reconcilePodDisruptionBudget(ctx, &oidcDiscoveryProviderConfig, statusMgr, &ztwim, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile HorizontalPodAutoscaler
+	if err := r.reconcileHorizontalPodAutoscaler(ctx, &oidcDiscoveryProviderConfig, statusMgr, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile NetworkPolicy if enabled
+	if err := r.reconcileNetworkPolicy(ctx, &oidcDiscoveryProviderConfig, statusMgr, &ztwim, createOnlyMode); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -172,7 +334,23 @@ func (r *SpireOidcDiscoveryProviderReconciler) Reconcile(ctx context.Context, re
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Reconcile Ingress (if configured)
+	if err := r.reconcileIngress(ctx, &oidcDiscoveryProviderConfig, statusMgr, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Prune resources from the previous inventory that the current spec no longer generates
+	pruned, err := statusMgr.PruneOrphanedResources(ctx, r.ctrlClient, r.scheme, oidcDiscoveryProviderConfig.Status.ManagedResources, createOnlyMode, dryRun != nil)
+	if err != nil {
+		r.log.Error(err, "failed to prune orphaned resources")
+		return ctrl.Result{}, err
+	}
+	for _, resource := range pruned {
+		r.log.Info("Pruned orphaned resource", "kind", resource.Kind, "namespace", resource.Namespace, "name", resource.Name)
+	}
+
+	// Requeue periodically so that drift from the desired state is repaired
+	return ctrl.Result{RequeueAfter: utils.ResyncInterval(oidcDiscoveryProviderConfig.Spec.ResyncInterval, ztwim.Spec.ResyncInterval)}, nil
 }
 
 func (r *SpireOidcDiscoveryProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -193,6 +371,10 @@ func (r *SpireOidcDiscoveryProviderReconciler) SetupWithManager(mgr ctrl.Manager
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.SpireOIDCDiscoveryProvider{}, builder.WithPredicates(utils.GenerationOrOwnerReferenceChangedPredicate)).
 		Named(utils.ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: utils.MaxConcurrentReconciles(utils.ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName),
+			RateLimiter:             utils.NewRateLimiter(),
+		}).
 		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
@@ -201,8 +383,15 @@ func (r *SpireOidcDiscoveryProviderReconciler) SetupWithManager(mgr ctrl.Manager
 		Watches(&rbacv1.Role{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&rbacv1.RoleBinding{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&spiffev1alpha1.ClusterSPIFFEID{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&policyv1.PodDisruptionBudget{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&networkingv1.NetworkPolicy{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&networkingv1.Ingress{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&autoscalingv2.HorizontalPodAutoscaler{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&v1alpha1.SpireServer{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1alpha1.SpireAgent{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&v1alpha1.ZeroTrustWorkloadIdentityManager{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(utils.ZTWIMSpecChangedPredicate)).
-		Complete(r)
+		WatchesRawSource(source.Channel(operatorconfig.Subscribe(), handler.EnqueueRequestsFromMapFunc(mapFunc))).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName, r))
 	if err != nil {
 		return err
 	}
@@ -228,6 +417,77 @@ func (r *SpireOidcDiscoveryProviderReconciler) handleCreateOnlyMode(oidc *v1alph
 	return createOnlyMode
 }
 
+// errJWTIssuerMismatch is returned when the jwtIssuer of the SpireOIDCDiscoveryProvider differs from the SpireServer one
+var errJWTIssuerMismatch = errors.New("jwtIssuer does not match the jwtIssuer of the SpireServer")
+
+// getSpireServer returns the cluster SpireServer, or nil when it doesn't exist
+func (r *SpireOidcDiscoveryProviderReconciler) getSpireServer(ctx context.Context) (*v1alpha1.SpireServer, error) {
+	var server v1alpha1.SpireServer
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, &server); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		r.log.Error(err, "failed to get SpireServer")
+		return nil, err
+	}
+	return &server, nil
+}
+
+// getSpireAgent returns the cluster SpireAgent, or nil when it doesn't exist
+func (r *SpireOidcDiscoveryProviderReconciler) getSpireAgent(ctx context.Context) (*v1alpha1.SpireAgent, error) {
+	var agent v1alpha1.SpireAgent
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, &agent); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		r.log.Error(err, "failed to get SpireAgent")
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// resolveJWTIssuer keeps the jwtIssuer of oidc consistent with the SpireServer: an unset jwtIssuer is
+// inherited from the SpireServer in memory, and a different one is reported in the ConfigurationValid condition
+func (r *SpireOidcDiscoveryProviderReconciler) resolveJWTIssuer(oidc *v1alpha1.SpireOIDCDiscoveryProvider, server *v1alpha1.SpireServer, statusMgr *status.Manager) error {
+	// Without a SpireServer there is nothing to inherit, validateConfiguration reports an unset jwtIssuer
+	if server == nil {
+		return nil
+	}
+
+	serverIssuer := server.Spec.JwtIssuer
+	switch {
+	case serverIssuer == "":
+		return nil
+	case oidc.Spec.JwtIssuer == "":
+		oidc.Spec.JwtIssuer = serverIssuer
+		return nil
+	case !jwtIssuersMatch(oidc.Spec.JwtIssuer, serverIssuer):
+		r.log.Error(errJWTIssuerMismatch, "Inconsistent JWT issuer in SpireOIDCDiscoveryProvider configuration",
+			"jwtIssuer", oidc.Spec.JwtIssuer, "spireServerJwtIssuer", serverIssuer)
+		statusMgr.AddCondition(ConfigurationValid, "JWTIssuerMismatch",
+			fmt.Sprintf("jwtIssuer %q does not match the jwtIssuer %q of the SpireServer, JWT-SVIDs would fail validation; unset it to inherit the SpireServer one",
+				oidc.Spec.JwtIssuer, serverIssuer),
+			metav1.ConditionFalse)
+		return errJWTIssuerMismatch
+	}
+	return nil
+}
+
+// jwtIssuersMatch reports whether two JWT issuer URLs are the same, ignoring the case of the scheme
+// and host and a trailing slash
+func jwtIssuersMatch(a, b string) bool {
+	normalize := func(issuer string) string {
+		u, err := url.Parse(strings.TrimSuffix(issuer, "/"))
+		if err != nil {
+			return issuer
+		}
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		return u.String()
+	}
+	return normalize(a) == normalize(b)
+}
+
 // validateConfiguration validates the SpireOIDCDiscoveryProvider configuration
 func (r *SpireOidcDiscoveryProviderReconciler) validateConfiguration(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager) error {
 	// Validate common configuration
@@ -249,6 +509,32 @@ func (r *SpireOidcDiscoveryProviderReconciler) validateConfiguration(ctx context
 		return err
 	}
 
+	if err := validateAutoscaling(&oidc.Spec); err != nil {
+		r.log.Error(err, "Invalid autoscaling configuration in SpireOIDCDiscoveryProvider")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidAutoscalingConfiguration",
+			fmt.Sprintf("Autoscaling validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the SPIRE version the OIDC discovery provider is pinned to
+	if err := utils.SpireOIDCDiscoveryProviderOperand.ValidateVersion(oidc.Spec.Version); err != nil {
+		r.log.Error(err, "Invalid version in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(ConfigurationValid, utils.ConditionReasonInvalidVersion,
+			fmt.Sprintf("Version validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Refuse to fall back to the non-FIPS image when running in FIPS mode
+	if err := utils.ValidateFIPSImages(utils.SpireOIDCDiscoveryProviderOperand.FIPSImageEnvFor(oidc.Spec.Version)); err != nil {
+		r.log.Error(err, "FIPS image of the OIDC discovery provider is not set")
+		statusMgr.AddCondition(ConfigurationValid, utils.ConditionReasonFIPSImageNotSet,
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	// Only set to true if the condition previously existed as false
 	existingCondition := apimeta.FindStatusCondition(oidc.Status.ConditionalStatus.Conditions, ConfigurationValid)
 	if existingCondition != nil && existingCondition.Status == metav1.ConditionFalse {
@@ -259,6 +545,30 @@ func (r *SpireOidcDiscoveryProviderReconciler) validateConfiguration(ctx context
 	return nil
 }
 
+// validateAutoscaling validates that the autoscaling configuration can be acted upon by the HorizontalPodAutoscaler
+func validateAutoscaling(config *v1alpha1.SpireOIDCDiscoveryProviderSpec) error {
+	// CPU utilization targets are computed against the CPU requests, so autoscaling needs them set
+	if config.Autoscaling != nil && (config.Resources == nil || config.Resources.Requests.Cpu().IsZero()) {
+		return fmt.Errorf("resources.requests.cpu must be set when autoscaling is configured")
+	}
+	return nil
+}
+
+// ValidateSpec runs the SpireOIDCDiscoveryProvider spec validations the reconciler performs, so that
+// the admission webhook can reject invalid specs up front.
+func ValidateSpec(config *v1alpha1.SpireOIDCDiscoveryProviderSpec) error {
+	// An unset jwtIssuer is inherited from the SpireServer during reconciliation
+	if config.JwtIssuer != "" {
+		if err := utils.IsValidURL(config.JwtIssuer); err != nil {
+			return fmt.Errorf("jwtIssuer: %w", err)
+		}
+	}
+	if err := utils.SpireOIDCDiscoveryProviderOperand.ValidateVersion(config.Version); err != nil {
+		return err
+	}
+	return validateAutoscaling(config)
+}
+
 // validateProxyConfiguration validates proxy configuration using shared validation logic
 func (r *SpireOidcDiscoveryProviderReconciler) validateProxyConfiguration(statusMgr *status.Manager) error {
 	result := utils.ValidateProxyConfiguration()
@@ -271,8 +581,80 @@ func (r *SpireOidcDiscoveryProviderReconciler) validateProxyConfiguration(status
 	return nil
 }
 
-// validateCommonConfig validates common configuration fields (affinity, tolerations, nodeSelector, resources, labels)
+// validateCommonConfig validates common configuration fields (affinity, tolerations, nodeSelector, resources, labels, extra volumes, env, security context, extra containers, image pull secrets, ServiceAccount annotations, node platform, topology spread constraints)
 func (r *SpireOidcDiscoveryProviderReconciler) validateCommonConfig(oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager) error {
+	// Validate the extra volumes mounted into the OIDC discovery provider pods
+	if err := utils.ValidateExtraVolumes(oidc.Spec.ExtraVolumes, oidc.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "Invalid extra volumes in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraVolumes,
+			fmt.Sprintf("Extra volumes validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the environment variables set in the OIDC discovery provider container
+	if err := utils.ValidateContainerEnv(oidc.Spec.Env, oidc.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "Invalid environment variables in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidEnv,
+			fmt.Sprintf("Environment variables validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the security context overrides of the OIDC discovery provider pods
+	if err := utils.ValidateSecurityContext(oidc.Spec.SecurityContext, oidc.Spec.PodSecurityContext, false); err != nil {
+		r.log.Error(err, "Invalid security context in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidSecurityContext,
+			fmt.Sprintf("Security context validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the extra containers added to the OIDC discovery provider pods
+	if err := utils.ValidateExtraContainers(oidc.Spec.ExtraContainers, oidc.Spec.ExtraInitContainers); err != nil {
+		r.log.Error(err, "Invalid extra containers in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraContainers,
+			fmt.Sprintf("Extra containers validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the image pull secrets of the OIDC discovery provider pods
+	if err := utils.ValidateImagePullSecrets(oidc.Spec.ImagePullSecrets); err != nil {
+		r.log.Error(err, "Invalid image pull secrets in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidImagePullSecrets,
+			fmt.Sprintf("Image pull secrets validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the annotations of the OIDC discovery provider ServiceAccount
+	if err := utils.ValidateServiceAccountAnnotations(oidc.Spec.ServiceAccountAnnotations); err != nil {
+		r.log.Error(err, "Invalid ServiceAccount annotations in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidServiceAccountAnnotations,
+			fmt.Sprintf("ServiceAccount annotations validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the node platform of the OIDC discovery provider pods against the operand images and their node selector
+	if err := utils.ValidateNodePlatform(oidc.Spec.NodeSelector, oidc.Spec.NodePlatform); err != nil {
+		r.log.Error(err, "Invalid node platform in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidNodePlatform,
+			fmt.Sprintf("Node platform validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the topology spread constraints of the OIDC discovery provider pods
+	if err := utils.ValidateTopologySpreadConstraints(oidc.Spec.TopologySpreadConstraints); err != nil {
+		r.log.Error(err, "Invalid topology spread constraints in SpireOIDCDiscoveryProvider configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidTopologySpreadConstraints,
+			fmt.Sprintf("Topology spread constraints validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	return utils.ValidateAndUpdateStatus(
 		r.log,
 		statusMgr,
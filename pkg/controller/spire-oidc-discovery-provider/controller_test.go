@@ -1085,7 +1085,7 @@ func TestReconcileClusterSpiffeIDs_AllScenarios(t *testing.T) {
 			}
 
 			statusMgr := status.NewManager(fakeClient)
-			err := reconciler.reconcileClusterSpiffeIDs(context.Background(), oidc, statusMgr, tt.createOnlyMode)
+			err := reconciler.reconcileClusterSpiffeIDs(context.Background(), oidc, nil, statusMgr, tt.createOnlyMode)
 
 			if tt.expectError && err == nil {
 				t.Fatal("Expected error but got nil")
@@ -1736,3 +1736,58 @@ func TestReconcile_ReconciliationStepErrors_MutationKillers(t *testing.T) {
 		})
 	}
 }
+
+// TestResolveJWTIssuer tests that the jwtIssuer is inherited from the SpireServer and that a different one is rejected
+func TestResolveJWTIssuer(t *testing.T) {
+	tests := []struct {
+		name           string
+		oidcIssuer     string
+		server         *v1alpha1.SpireServer
+		expectIssuer   string
+		expectMismatch bool
+	}{
+		{
+			name:         "unset issuer is inherited from the SpireServer",
+			server:       &v1alpha1.SpireServer{Spec: v1alpha1.SpireServerSpec{JwtIssuer: "https://oidc.example.org"}},
+			expectIssuer: "https://oidc.example.org",
+		},
+		{
+			name:         "matching issuer is kept",
+			oidcIssuer:   "https://OIDC.example.org/",
+			server:       &v1alpha1.SpireServer{Spec: v1alpha1.SpireServerSpec{JwtIssuer: "https://oidc.example.org"}},
+			expectIssuer: "https://OIDC.example.org/",
+		},
+		{
+			name:           "different issuer is rejected",
+			oidcIssuer:     "https://other.example.org",
+			server:         &v1alpha1.SpireServer{Spec: v1alpha1.SpireServerSpec{JwtIssuer: "https://oidc.example.org"}},
+			expectIssuer:   "https://other.example.org",
+			expectMismatch: true,
+		},
+		{
+			name:         "missing SpireServer leaves the issuer unchanged",
+			oidcIssuer:   "https://other.example.org",
+			expectIssuer: "https://other.example.org",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			reconciler := newTestReconciler(fakeClient)
+			statusMgr := status.NewManager(fakeClient)
+			oidc := &v1alpha1.SpireOIDCDiscoveryProvider{Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{JwtIssuer: tt.oidcIssuer}}
+
+			err := reconciler.resolveJWTIssuer(oidc, tt.server, statusMgr)
+			if tt.expectMismatch != errors.Is(err, errJWTIssuerMismatch) {
+				t.Errorf("Expected mismatch=%v, got error %v", tt.expectMismatch, err)
+			}
+			if !tt.expectMismatch && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if oidc.Spec.JwtIssuer != tt.expectIssuer {
+				t.Errorf("Expected jwtIssuer %q, got %q", tt.expectIssuer, oidc.Spec.JwtIssuer)
+			}
+		})
+	}
+}
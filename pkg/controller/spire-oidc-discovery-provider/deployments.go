@@ -2,23 +2,64 @@ package spire_oidc_discovery_provider
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// replicasHandoverFieldManager keeps the replicas of the Deployment once the operator stops applying them,
+// until the HorizontalPodAutoscaler takes them over
+const replicasHandoverFieldManager = "zero-trust-workload-identity-manager-replicas-handover"
+
 // reconcileDeployment reconciles the OIDC Discovery Provider Deployment
-func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeployment(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, createOnlyMode bool, configHash string) error {
-	deployment := generateDeployment(oidc, configHash)
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeployment(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool, configHash string) error {
+	_, renderSpan := tracing.Start(ctx, "Render OIDC discovery provider Deployment")
+	deployment := generateDeployment(oidc, ztwim, configHash)
+	// Size the pods for the profile of the cluster before the user provided containers are added
+	utils.ApplyDeploymentProfile(&deployment.Spec.Template.Spec, ztwim)
+	tracing.End(renderSpan, nil)
+	if err := utils.AddExtraVolumes(&deployment.Spec.Template.Spec, "spiffe-oidc-discovery-provider", oidc.Spec.ExtraVolumes, oidc.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "failed to add the extra volumes")
+		statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentCreationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.AddContainerEnv(&deployment.Spec.Template.Spec, "spiffe-oidc-discovery-provider", oidc.Spec.Env, oidc.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "failed to add the environment variables")
+		statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentCreationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.ApplySecurityContext(&deployment.Spec.Template.Spec, "spiffe-oidc-discovery-provider", oidc.Spec.SecurityContext, oidc.Spec.PodSecurityContext); err != nil {
+		r.log.Error(err, "failed to apply the security context overrides")
+		statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentCreationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.AddExtraContainers(&deployment.Spec.Template.Spec, oidc.Spec.ExtraContainers, oidc.Spec.ExtraInitContainers); err != nil {
+		r.log.Error(err, "failed to add the extra containers")
+		statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentCreationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
 	if err := controllerutil.SetControllerReference(oidc, deployment, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference")
 		statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentCreationFailed",
@@ -26,14 +67,20 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeployment(ctx context.C
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(deployment)
 
 	var existingSpireOidcDeployment appsv1.Deployment
 	err := r.ctrlClient.Get(ctx, types.NamespacedName{
 		Name:      deployment.Name,
 		Namespace: deployment.Namespace,
 	}, &existingSpireOidcDeployment)
+	// The HorizontalPodAutoscaler owns the replica count once autoscaling is configured,
+	// so replicas are left out of the applied configuration of an existing Deployment
+	if err == nil && oidc.Spec.Autoscaling != nil {
+		deployment.Spec.Replicas = nil
+	}
 	if err != nil && kerrors.IsNotFound(err) {
-		if err = r.ctrlClient.Create(ctx, deployment); err != nil {
+		if err = r.ctrlClient.Apply(ctx, deployment); err != nil {
 			r.log.Error(err, "Failed to create spire oidc discovery provider deployment")
 			statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentCreationFailed",
 				err.Error(),
@@ -41,12 +88,21 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeployment(ctx context.C
 			return err
 		}
 		r.log.Info("Created spire oidc discovery provider deployment")
+		statusMgr.RecordResourceCreated(deployment)
 	} else if err == nil && needsUpdate(existingSpireOidcDeployment, *deployment) {
 		if createOnlyMode {
 			r.log.Info("Skipping Deployment update due to create-only mode")
 		} else {
-			deployment.ResourceVersion = existingSpireOidcDeployment.ResourceVersion
-			if err = r.ctrlClient.Update(ctx, deployment); err != nil {
+			if deployment.Spec.Replicas == nil {
+				if err = r.handOverReplicas(ctx, deployment); err != nil {
+					r.log.Error(err, "Failed to hand over the replicas of the spire oidc discovery provider deployment")
+					statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentUpdateFailed",
+						err.Error(),
+						metav1.ConditionFalse)
+					return err
+				}
+			}
+			if err = r.ctrlClient.Apply(ctx, deployment); err != nil {
 				r.log.Error(err, "Failed to update spire oidc discovery provider deployment")
 				statusMgr.AddCondition(DeploymentAvailable, "SpireOIDCDeploymentUpdateFailed",
 					err.Error(),
@@ -54,6 +110,8 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeployment(ctx context.C
 				return err
 			}
 			r.log.Info("Updated spire oidc discovery provider deployment")
+			statusMgr.RecordWorkloadUpdated(deployment, &existingSpireOidcDeployment.Spec.Template, &deployment.Spec.Template,
+				spireOidcDeploymentSpireOidcConfigHashAnnotationKey)
 		}
 	} else if err != nil {
 		r.log.Error(err, "Failed to get existing spire oidc discovery provider deployment")
@@ -65,15 +123,93 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeployment(ctx context.C
 
 	// Check Deployment health/readiness
 	statusMgr.CheckDeploymentHealth(ctx, deployment.Name, deployment.Namespace, DeploymentAvailable)
+	r.updateScaleStatus(ctx, oidc, deployment, statusMgr)
 
 	return nil
 }
 
+// ownsReplicas reports whether the operator field manager applied the replicas of the Deployment
+func ownsReplicas(deployment *appsv1.Deployment) bool {
+	for _, entry := range deployment.ManagedFields {
+		if entry.Manager != customClient.FieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields["f:spec"]["f:replicas"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// handOverReplicas applies the current replicas of the Deployment with the replicasHandoverFieldManager when the
+// operator still owns them, before it leaves them out of its applied configuration once autoscaling is configured.
+// Otherwise server-side apply would remove the replicas only the operator owned, scaling the Deployment down to a
+// single replica until the HorizontalPodAutoscaler scales it up again. The Deployment is read from the API server,
+// as the cache strips the managed fields.
+func (r *SpireOidcDiscoveryProviderReconciler) handOverReplicas(ctx context.Context, deployment *appsv1.Deployment) error {
+	var live appsv1.Deployment
+	if err := r.ctrlClient.APIReader().Get(ctx, client.ObjectKeyFromObject(deployment), &live); err != nil {
+		return err
+	}
+	if !ownsReplicas(&live) {
+		return nil
+	}
+	handover := &unstructured.Unstructured{}
+	handover.SetAPIVersion("apps/v1")
+	handover.SetKind("Deployment")
+	handover.SetNamespace(live.Namespace)
+	handover.SetName(live.Name)
+	if err := unstructured.SetNestedField(handover.Object, int64(ptr.Deref(live.Spec.Replicas, 1)), "spec", "replicas"); err != nil {
+		return err
+	}
+	return r.ctrlClient.Apply(ctx, handover, client.FieldOwner(replicasHandoverFieldManager))
+}
+
+// updateScaleStatus publishes the replicas and the pod selector of the Deployment in the status fields read by
+// the scale subresource of the SpireOIDCDiscoveryProvider
+func (r *SpireOidcDiscoveryProviderReconciler) updateScaleStatus(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, deployment *appsv1.Deployment, statusMgr *status.Manager) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		r.log.Error(err, "failed to convert the Deployment selector")
+		return
+	}
+	var current appsv1.Deployment
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, &current); err != nil {
+		// The Deployment health check reports the failure
+		return
+	}
+	if oidc.Status.Replicas != current.Status.Replicas || oidc.Status.Selector != selector.String() {
+		oidc.Status.Replicas = current.Status.Replicas
+		oidc.Status.Selector = selector.String()
+		statusMgr.ForceStatusUpdate()
+	}
+}
+
+// oidcReplicas returns the number of replicas of the OIDC discovery provider. A single replica runs in the
+// SingleNode profile unless autoscaling is configured.
+func oidcReplicas(config *v1alpha1.SpireOIDCDiscoveryProviderSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) int32 {
+	switch {
+	case config.Autoscaling != nil:
+		return autoscalingMinReplicas(config.Autoscaling)
+	case utils.IsSingleNodeProfile(ztwim):
+		return 1
+	case config.ReplicaCount > 0:
+		return int32(config.ReplicaCount)
+	default:
+		return 1
+	}
+}
+
 // generateDeployment generates and return the deployment manifest based on configuration provided via SpireOIDCDiscoveryProvider spec.
-func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, spireOidcConfigMapHash string) *appsv1.Deployment {
+func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, spireOidcConfigMapHash string) *appsv1.Deployment {
 
 	// Generate standardized labels once and reuse them
 	labels := utils.SpireOIDCDiscoveryProviderLabels(config.Spec.Labels)
+	labels[utils.VersionLabelKey] = utils.SpireOIDCDiscoveryProviderOperand.Version(config.Spec.Version)
 
 	// For selectors, we need only the core identifying labels (without custom user labels)
 	selectorLabels := map[string]string{
@@ -82,10 +218,7 @@ func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, spireOidcCo
 		"app.kubernetes.io/component": labels["app.kubernetes.io/component"],
 	}
 
-	replicas := int32(1)
-	if config.Spec.ReplicaCount > 0 {
-		replicas = int32(config.Spec.ReplicaCount)
-	}
+	replicas := oidcReplicas(&config.Spec, ztwim)
 
 	// Apply default CSI driver name if not specified
 	csiDriverName := config.Spec.CSIDriverName
@@ -96,7 +229,7 @@ func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, spireOidcCo
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-spiffe-oidc-discovery-provider",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    labels,
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -113,6 +246,8 @@ func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, spireOidcCo
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: "spire-spiffe-oidc-discovery-provider",
+					PriorityClassName:  config.Spec.PriorityClassName,
+					ImagePullSecrets:   config.Spec.ImagePullSecrets,
 					Volumes: []corev1.Volume{
 						{
 							Name: "spiffe-workload-api",
@@ -152,7 +287,7 @@ func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, spireOidcCo
 								ReadOnlyRootFilesystem: ptr.To(true),
 							},
 							Name:            "spiffe-oidc-discovery-provider",
-							Image:           utils.GetSpireOIDCDiscoveryProviderImage(),
+							Image:           utils.SpireOIDCDiscoveryProviderOperand.Image(config.Spec.Version),
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Args:            []string{"-config", "/run/spire/oidc/config/oidc-discovery-provider.conf"},
 							Ports: []corev1.ContainerPort{
@@ -190,9 +325,10 @@ func generateDeployment(config *v1alpha1.SpireOIDCDiscoveryProvider, spireOidcCo
 							Resources: utils.DerefResourceRequirements(config.Spec.Resources),
 						},
 					},
-					Affinity:     config.Spec.Affinity,
-					NodeSelector: utils.DerefNodeSelector(config.Spec.NodeSelector),
-					Tolerations:  utils.DerefTolerations(config.Spec.Tolerations),
+					Affinity:                  utils.PlatformAffinity(config.Spec.Affinity, config.Spec.NodePlatform),
+					NodeSelector:              utils.PlatformNodeSelector(config.Spec.NodeSelector, config.Spec.NodePlatform),
+					TopologySpreadConstraints: utils.TopologySpreadConstraints(config.Spec.TopologySpreadConstraints, selectorLabels),
+					Tolerations:               utils.DerefTolerations(config.Spec.Tolerations),
 				},
 			},
 		},
@@ -3,10 +3,12 @@ package spire_oidc_discovery_provider
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
@@ -17,11 +19,14 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestBuildDeployment(t *testing.T) {
@@ -123,11 +128,47 @@ func TestBuildDeployment(t *testing.T) {
 			hash: "test-hash-node",
 			expected: func(deployment *appsv1.Deployment) {
 				assert.Equal(t, map[string]string{
-					"node-type": "compute",
-					"zone":      "us-west-1a",
+					"node-type":          "compute",
+					"zone":               "us-west-1a",
+					corev1.LabelOSStable: "linux",
 				}, deployment.Spec.Template.Spec.NodeSelector)
 			},
 		},
+		{
+			name: "deployment with node platform",
+			config: &v1alpha1.SpireOIDCDiscoveryProvider{
+				Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+					CommonConfig: v1alpha1.CommonConfig{
+						NodePlatform: &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"arm64"}},
+					},
+				},
+			},
+			hash: "test-hash-platform",
+			expected: func(deployment *appsv1.Deployment) {
+				assert.Equal(t, []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"}},
+					},
+				}}, deployment.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+			},
+		},
+		{
+			name: "deployment with topology spread constraints",
+			config: &v1alpha1.SpireOIDCDiscoveryProvider{
+				Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+						{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule},
+					},
+				},
+			},
+			hash: "test-hash-spread",
+			expected: func(deployment *appsv1.Deployment) {
+				constraints := deployment.Spec.Template.Spec.TopologySpreadConstraints
+				assert.Len(t, constraints, 1)
+				assert.Equal(t, corev1.LabelTopologyZone, constraints[0].TopologyKey)
+				assert.Equal(t, deployment.Spec.Selector.MatchLabels, constraints[0].LabelSelector.MatchLabels)
+			},
+		},
 		{
 			name: "deployment with affinity",
 			config: &v1alpha1.SpireOIDCDiscoveryProvider{
@@ -266,7 +307,7 @@ func TestBuildDeployment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			deployment := generateDeployment(tt.config, tt.hash)
+			deployment := generateDeployment(tt.config, nil, tt.hash)
 
 			// Common assertions for all tests
 			require.NotNil(t, deployment)
@@ -340,15 +381,15 @@ func TestReconcileDeployment(t *testing.T) {
 		statusMgr := status.NewManager(fakeClient)
 
 		fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, "spire-spiffe-oidc-discovery-provider"))
-		fakeClient.CreateReturns(nil)
+		fakeClient.ApplyReturns(nil)
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, false, "test-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "test-hash")
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
-		if fakeClient.CreateCallCount() != 1 {
-			t.Errorf("Expected Create to be called once, got %d", fakeClient.CreateCallCount())
+		if fakeClient.ApplyCallCount() != 1 || fakeClient.CreateCallCount() != 0 {
+			t.Errorf("Expected the Deployment to be applied once, got %d applies and %d creates", fakeClient.ApplyCallCount(), fakeClient.CreateCallCount())
 		}
 	})
 
@@ -360,9 +401,9 @@ func TestReconcileDeployment(t *testing.T) {
 		statusMgr := status.NewManager(fakeClient)
 
 		fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, "spire-spiffe-oidc-discovery-provider"))
-		fakeClient.CreateReturns(errors.New("create failed"))
+		fakeClient.ApplyReturns(errors.New("create failed"))
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, false, "test-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "test-hash")
 
 		if err == nil {
 			t.Error("Expected error when Create fails")
@@ -378,7 +419,7 @@ func TestReconcileDeployment(t *testing.T) {
 
 		fakeClient.GetReturns(errors.New("connection refused"))
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, false, "test-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "test-hash")
 
 		if err == nil {
 			t.Error("Expected error when Get fails")
@@ -411,15 +452,44 @@ func TestReconcileDeployment(t *testing.T) {
 			}
 			return nil
 		}
-		fakeClient.UpdateReturns(nil)
+		fakeClient.ApplyReturns(nil)
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, false, "new-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "new-hash")
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
-		if fakeClient.UpdateCallCount() != 1 {
-			t.Errorf("Expected Update to be called once, got %d", fakeClient.UpdateCallCount())
+		if fakeClient.ApplyCallCount() != 1 || fakeClient.UpdateCallCount() != 0 {
+			t.Errorf("Expected the Deployment to be applied once, got %d applies and %d updates", fakeClient.ApplyCallCount(), fakeClient.UpdateCallCount())
+		}
+	})
+
+	t.Run("publishes the scale status", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newDeploymentTestReconciler(fakeClient)
+
+		oidc := createDeploymentTestOIDCCR()
+		statusMgr := status.NewManager(fakeClient)
+
+		fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if deploy, ok := obj.(*appsv1.Deployment); ok {
+				*deploy = appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+					Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+					Status:     appsv1.DeploymentStatus{Replicas: 2},
+				}
+			}
+			return nil
+		}
+
+		if err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "test-hash"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if oidc.Status.Replicas != 2 {
+			t.Errorf("Expected 2 replicas in status, got %d", oidc.Status.Replicas)
+		}
+		if !strings.Contains(oidc.Status.Selector, "app.kubernetes.io/component=") {
+			t.Errorf("Expected the pod selector in status, got %q", oidc.Status.Selector)
 		}
 	})
 
@@ -449,9 +519,9 @@ func TestReconcileDeployment(t *testing.T) {
 			}
 			return nil
 		}
-		fakeClient.UpdateReturns(errors.New("update conflict"))
+		fakeClient.ApplyReturns(errors.New("update conflict"))
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, false, "new-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "new-hash")
 
 		if err == nil {
 			t.Error("Expected error when Update fails")
@@ -485,13 +555,104 @@ func TestReconcileDeployment(t *testing.T) {
 			return nil
 		}
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, true, "new-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, true, "new-hash")
 
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
-		if fakeClient.UpdateCallCount() != 0 {
-			t.Error("Expected Update not to be called in create-only mode")
+		if fakeClient.ApplyCallCount() != 0 {
+			t.Error("Expected Apply not to be called in create-only mode")
+		}
+	})
+
+	t.Run("autoscaled update leaves replicas to the HorizontalPodAutoscaler", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newDeploymentTestReconciler(fakeClient)
+
+		oidc := createDeploymentTestOIDCCR()
+		oidc.Spec.Autoscaling = &v1alpha1.AutoscalingConfig{MaxReplicas: 5}
+		statusMgr := status.NewManager(fakeClient)
+
+		existingDeployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "spire-spiffe-oidc-discovery-provider",
+				Namespace: utils.GetOperatorNamespace(),
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(4)),
+			},
+		}
+		fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if deploy, ok := obj.(*appsv1.Deployment); ok {
+				*deploy = *existingDeployment
+			}
+			return nil
+		}
+		fakeClient.APIReaderReturns(fake.NewClientBuilder().WithScheme(reconciler.scheme).WithObjects(existingDeployment).Build())
+
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "new-hash")
+
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+		if fakeClient.ApplyCallCount() != 1 {
+			t.Fatalf("Expected Apply to be called once, got %d", fakeClient.ApplyCallCount())
+		}
+		_, obj, _ := fakeClient.ApplyArgsForCall(0)
+		if replicas := obj.(*appsv1.Deployment).Spec.Replicas; replicas != nil {
+			t.Errorf("Expected replicas to be left out of the applied Deployment, got %d", *replicas)
+		}
+	})
+
+	t.Run("switch to autoscaling hands over the replicas before leaving them out", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newDeploymentTestReconciler(fakeClient)
+
+		oidc := createDeploymentTestOIDCCR()
+		oidc.Spec.Autoscaling = &v1alpha1.AutoscalingConfig{MaxReplicas: 5}
+		statusMgr := status.NewManager(fakeClient)
+
+		existingDeployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "spire-spiffe-oidc-discovery-provider",
+				Namespace: utils.GetOperatorNamespace(),
+				ManagedFields: []metav1.ManagedFieldsEntry{{
+					Manager:   customClient.FieldManager,
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{},"f:template":{}}}`)},
+				}},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(3)),
+			},
+		}
+		fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if deploy, ok := obj.(*appsv1.Deployment); ok {
+				*deploy = *existingDeployment
+			}
+			return nil
+		}
+		fakeClient.APIReaderReturns(fake.NewClientBuilder().WithScheme(reconciler.scheme).WithObjects(existingDeployment).Build())
+
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "new-hash")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.ApplyCallCount() != 2 {
+			t.Fatalf("Expected the replicas hand over and the Deployment to be applied, got %d applies", fakeClient.ApplyCallCount())
+		}
+		_, handover, opts := fakeClient.ApplyArgsForCall(0)
+		replicas, _, _ := unstructured.NestedInt64(handover.(*unstructured.Unstructured).Object, "spec", "replicas")
+		if replicas != 3 {
+			t.Errorf("Expected the current 3 replicas to be handed over, got %d", replicas)
+		}
+		if len(opts) != 1 || opts[0] != client.FieldOwner(replicasHandoverFieldManager) {
+			t.Errorf("Expected the hand over to use the %s field manager, got %v", replicasHandoverFieldManager, opts)
+		}
+		_, obj, _ := fakeClient.ApplyArgsForCall(1)
+		if replicas := obj.(*appsv1.Deployment).Spec.Replicas; replicas != nil {
+			t.Errorf("Expected replicas to be left out of the applied Deployment, got %d", *replicas)
 		}
 	})
 
@@ -508,7 +669,7 @@ func TestReconcileDeployment(t *testing.T) {
 		oidc := createDeploymentTestOIDCCR()
 		statusMgr := status.NewManager(fakeClient)
 
-		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, false, "test-hash")
+		err := reconciler.reconcileDeployment(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false, "test-hash")
 
 		if err == nil {
 			t.Error("Expected error when SetControllerReference fails")
@@ -543,3 +704,33 @@ func createDeploymentTestOIDCCR() *v1alpha1.SpireOIDCDiscoveryProvider {
 		},
 	}
 }
+
+func TestOIDCReplicas(t *testing.T) {
+	singleNode := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{Profile: v1alpha1.DeploymentProfileSingleNode},
+	}
+
+	tests := []struct {
+		name     string
+		spec     v1alpha1.SpireOIDCDiscoveryProviderSpec
+		ztwim    *v1alpha1.ZeroTrustWorkloadIdentityManager
+		expected int32
+	}{
+		{name: "replica count", spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{ReplicaCount: 3}, expected: 3},
+		{name: "single replica in the SingleNode profile", spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{ReplicaCount: 3}, ztwim: singleNode, expected: 1},
+		{
+			name:     "autoscaling in the SingleNode profile",
+			spec:     v1alpha1.SpireOIDCDiscoveryProviderSpec{Autoscaling: &v1alpha1.AutoscalingConfig{MinReplicas: 2, MaxReplicas: 4}},
+			ztwim:    singleNode,
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if replicas := oidcReplicas(&tt.spec, tt.ztwim); replicas != tt.expected {
+				t.Errorf("Expected %d replicas, got %d", tt.expected, replicas)
+			}
+		})
+	}
+}
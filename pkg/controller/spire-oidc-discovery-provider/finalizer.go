@@ -0,0 +1,70 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// reconcileDeletion tears down the OIDC discovery provider before the SpireOIDCDiscoveryProvider
+// CR is removed. The provider is a SPIFFE workload, so it is torn down together with the CSI
+// driver, before the SPIRE agent and server it depends on. The Deployment is deleted before
+// the ClusterSPIFFEIDs registering it, and the remaining resources are garbage collected
+// through their owner references.
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileDeletion(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, namespace string) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(oidc, utils.OperandCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	getStatus := func() *v1alpha1.ConditionalStatus {
+		return &oidc.Status.ConditionalStatus
+	}
+
+	if err := status.SetDeletionStatus(ctx, r.ctrlClient, oidc, getStatus, utils.DeletionDeletingResources,
+		"Deleting the SPIRE OIDC discovery provider resources"); err != nil {
+		r.log.Error(err, "failed to update status")
+	}
+	if err := utils.DeleteObjects(ctx, r.ctrlClient,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spire-spiffe-oidc-discovery-provider", Namespace: namespace}},
+		&spiffev1alpha1.ClusterSPIFFEID{ObjectMeta: metav1.ObjectMeta{Name: "zero-trust-workload-identity-manager-spire-oidc-discovery-provider"}},
+		&spiffev1alpha1.ClusterSPIFFEID{ObjectMeta: metav1.ObjectMeta{Name: "zero-trust-workload-identity-manager-spire-default"}},
+	); err != nil {
+		r.log.Error(err, "failed to delete SPIRE OIDC discovery provider resources")
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(oidc, utils.OperandCleanupFinalizer)
+	if err := r.ctrlClient.Update(ctx, oidc); err != nil {
+		r.log.Error(err, "failed to remove finalizer from SpireOIDCDiscoveryProvider")
+		return ctrl.Result{}, err
+	}
+	r.log.Info("SPIRE OIDC discovery provider resources deleted, removed finalizer", "name", oidc.Name)
+	return ctrl.Result{}, nil
+}
+
+// reconcileRemoval deletes the resources managed for the SpireOIDCDiscoveryProvider while keeping the CR, when its
+// management state is Removed. The Deployment and the ClusterSPIFFEIDs registering it are deleted first, as in the teardown.
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileRemoval(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, namespace string, statusMgr *status.Manager) error {
+	if err := statusMgr.RemoveManagedResources(ctx, r.ctrlClient, r.scheme, oidc.Status.ManagedResources,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spire-spiffe-oidc-discovery-provider", Namespace: namespace}},
+		&spiffev1alpha1.ClusterSPIFFEID{ObjectMeta: metav1.ObjectMeta{Name: "zero-trust-workload-identity-manager-spire-oidc-discovery-provider"}},
+		&spiffev1alpha1.ClusterSPIFFEID{ObjectMeta: metav1.ObjectMeta{Name: "zero-trust-workload-identity-manager-spire-default"}},
+	); err != nil {
+		r.log.Error(err, "failed to remove OIDC discovery provider resources")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+			fmt.Sprintf("Failed to remove the OIDC discovery provider resources: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	r.log.Info("OIDC discovery provider resources removed", "name", oidc.Name)
+	return nil
+}
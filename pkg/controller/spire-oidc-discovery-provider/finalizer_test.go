@@ -0,0 +1,40 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func TestReconcileDeletion(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newTestReconciler(fakeClient)
+	now := metav1.Now()
+	cr := &v1alpha1.SpireOIDCDiscoveryProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{utils.OperandCleanupFinalizer},
+		},
+	}
+
+	result, err := reconciler.reconcileDeletion(context.Background(), cr, "operand-ns")
+	if err != nil {
+		t.Fatalf("reconcileDeletion() error = %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("Expected no requeue, got %v", result.RequeueAfter)
+	}
+	if fakeClient.DeleteCallCount() != 3 {
+		t.Errorf("Expected 3 deletes, got %d", fakeClient.DeleteCallCount())
+	}
+	if fakeClient.UpdateCallCount() != 1 || controllerutil.ContainsFinalizer(cr, utils.OperandCleanupFinalizer) {
+		t.Error("Expected the finalizer to be removed")
+	}
+}
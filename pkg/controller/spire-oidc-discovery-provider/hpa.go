@@ -0,0 +1,142 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	defaultAutoscalingMinReplicas         = int32(1)
+	defaultTargetCPUUtilizationPercentage = int32(80)
+)
+
+// autoscalingMinReplicas returns the configured lower replica limit, falling back to the API default
+func autoscalingMinReplicas(config *v1alpha1.AutoscalingConfig) int32 {
+	if config.MinReplicas > 0 {
+		return config.MinReplicas
+	}
+	return defaultAutoscalingMinReplicas
+}
+
+// generateOIDCHorizontalPodAutoscaler returns the HorizontalPodAutoscaler scaling the OIDC discovery provider Deployment
+func generateOIDCHorizontalPodAutoscaler(config *v1alpha1.SpireOIDCDiscoveryProviderSpec) *autoscalingv2.HorizontalPodAutoscaler {
+	targetCPU := config.Autoscaling.TargetCPUUtilizationPercentage
+	if targetCPU == 0 {
+		targetCPU = defaultTargetCPUUtilizationPercentage
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "spire-spiffe-oidc-discovery-provider",
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.SpireOIDCDiscoveryProviderLabels(config.Labels),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "spire-spiffe-oidc-discovery-provider",
+			},
+			MinReplicas: ptr.To(autoscalingMinReplicas(config.Autoscaling)),
+			MaxReplicas: config.Autoscaling.MaxReplicas,
+			Metrics: []autoscalingv2.MetricSpec{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name: corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: ptr.To(targetCPU),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileHorizontalPodAutoscaler reconciles the HorizontalPodAutoscaler for the OIDC discovery provider Deployment
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileHorizontalPodAutoscaler(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, createOnlyMode bool) error {
+	existing := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "spire-spiffe-oidc-discovery-provider", Namespace: utils.GetOperandNamespace()}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get HorizontalPodAutoscaler")
+		statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, "SpireOIDCHorizontalPodAutoscalerGetFailed",
+			fmt.Sprintf("Failed to get HorizontalPodAutoscaler: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	exists := err == nil
+
+	if oidc.Spec.Autoscaling == nil {
+		if exists {
+			if err := r.ctrlClient.Delete(ctx, existing); err != nil && !kerrors.IsNotFound(err) {
+				r.log.Error(err, "failed to delete HorizontalPodAutoscaler")
+				statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, "SpireOIDCHorizontalPodAutoscalerDeletionFailed",
+					fmt.Sprintf("Failed to delete HorizontalPodAutoscaler: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Deleted HorizontalPodAutoscaler", "name", existing.Name, "namespace", existing.Namespace)
+		}
+		// Running with a fixed replica count is a valid configuration and must not mark the operand as not ready
+		statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, "SpireOIDCHorizontalPodAutoscalerDisabled",
+			"Autoscaling disabled, using the configured replica count",
+			metav1.ConditionTrue)
+		return nil
+	}
+
+	desired := generateOIDCHorizontalPodAutoscaler(&oidc.Spec)
+	if err := controllerutil.SetControllerReference(oidc, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on HorizontalPodAutoscaler")
+		statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, "SpireOIDCHorizontalPodAutoscalerGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on HorizontalPodAutoscaler: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	if !exists {
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create HorizontalPodAutoscaler")
+			statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, "SpireOIDCHorizontalPodAutoscalerCreationFailed",
+				fmt.Sprintf("Failed to create HorizontalPodAutoscaler: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created HorizontalPodAutoscaler", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+	} else if utils.ResourceNeedsUpdate(existing, desired) {
+		if createOnlyMode {
+			r.log.Info("Skipping HorizontalPodAutoscaler update due to create-only mode")
+		} else {
+			desired.ResourceVersion = existing.ResourceVersion
+			if err := r.ctrlClient.Update(ctx, desired); err != nil {
+				r.log.Error(err, "failed to update HorizontalPodAutoscaler")
+				statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, "SpireOIDCHorizontalPodAutoscalerUpdateFailed",
+					fmt.Sprintf("Failed to update HorizontalPodAutoscaler: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Updated HorizontalPodAutoscaler", "name", desired.Name, "namespace", desired.Namespace)
+			statusMgr.RecordDriftRepaired(desired)
+		}
+	}
+
+	statusMgr.AddCondition(HorizontalPodAutoscalerAvailable, v1alpha1.ReasonReady,
+		"HorizontalPodAutoscaler available",
+		metav1.ConditionTrue)
+	return nil
+}
@@ -0,0 +1,145 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func TestGenerateOIDCHorizontalPodAutoscaler(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		hpa := generateOIDCHorizontalPodAutoscaler(&v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			Autoscaling: &v1alpha1.AutoscalingConfig{MaxReplicas: 5},
+		})
+		if hpa.Spec.ScaleTargetRef.Kind != "Deployment" || hpa.Spec.ScaleTargetRef.Name != "spire-spiffe-oidc-discovery-provider" {
+			t.Errorf("Unexpected scaleTargetRef %+v", hpa.Spec.ScaleTargetRef)
+		}
+		if *hpa.Spec.MinReplicas != 1 {
+			t.Errorf("Expected minReplicas 1, got %d", *hpa.Spec.MinReplicas)
+		}
+		if hpa.Spec.MaxReplicas != 5 {
+			t.Errorf("Expected maxReplicas 5, got %d", hpa.Spec.MaxReplicas)
+		}
+		if len(hpa.Spec.Metrics) != 1 || *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != 80 {
+			t.Errorf("Expected a single CPU metric targeting 80%%, got %+v", hpa.Spec.Metrics)
+		}
+	})
+
+	t.Run("configured values", func(t *testing.T) {
+		hpa := generateOIDCHorizontalPodAutoscaler(&v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			Autoscaling: &v1alpha1.AutoscalingConfig{MinReplicas: 2, MaxReplicas: 8, TargetCPUUtilizationPercentage: 60},
+		})
+		if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 8 {
+			t.Errorf("Expected replicas 2-8, got %d-%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+		}
+		if *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != 60 {
+			t.Errorf("Expected CPU target 60, got %d", *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+		}
+	})
+
+	t.Run("deployment starts at minReplicas", func(t *testing.T) {
+		deployment := generateDeployment(&v1alpha1.SpireOIDCDiscoveryProvider{Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			ReplicaCount: 4,
+			Autoscaling:  &v1alpha1.AutoscalingConfig{MinReplicas: 2, MaxReplicas: 8},
+		}}, nil, "")
+		if *deployment.Spec.Replicas != 2 {
+			t.Errorf("Expected deployment replicas 2, got %d", *deployment.Spec.Replicas)
+		}
+	})
+}
+
+func TestReconcileOIDCHorizontalPodAutoscaler(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "spire-spiffe-oidc-discovery-provider")
+	autoscaling := &v1alpha1.AutoscalingConfig{MinReplicas: 1, MaxReplicas: 5}
+
+	tests := []struct {
+		name         string
+		spec         v1alpha1.SpireOIDCDiscoveryProviderSpec
+		setupClient  func(*fakes.FakeCustomCtrlClient)
+		expectError  bool
+		expectCreate int
+		expectUpdate int
+		expectDelete int
+	}{
+		{
+			name: "create when not found",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{Autoscaling: autoscaling},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+		},
+		{
+			name: "get error",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{Autoscaling: autoscaling},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(errors.New("connection refused"))
+			},
+			expectError: true,
+		},
+		{
+			name: "update when maxReplicas changes",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{Autoscaling: &v1alpha1.AutoscalingConfig{MinReplicas: 1, MaxReplicas: 8}},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+						*hpa = *generateOIDCHorizontalPodAutoscaler(&v1alpha1.SpireOIDCDiscoveryProviderSpec{Autoscaling: autoscaling})
+						hpa.ResourceVersion = "123"
+					}
+					return nil
+				}
+			},
+			expectUpdate: 1,
+		},
+		{
+			name: "autoscaling removed deletes existing",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(nil)
+			},
+			expectDelete: 1,
+		},
+		{
+			name: "autoscaling not configured and not found",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			oidc := &v1alpha1.SpireOIDCDiscoveryProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+				Spec:       tt.spec,
+			}
+			statusMgr := status.NewManager(fakeClient)
+
+			err := reconciler.reconcileHorizontalPodAutoscaler(context.Background(), oidc, statusMgr, false)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcileHorizontalPodAutoscaler() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+		})
+	}
+}
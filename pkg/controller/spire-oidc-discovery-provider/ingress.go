@@ -0,0 +1,164 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// oidcDiscoveryProviderIngressName is the name of the Ingress of the OIDC discovery provider, shared with the Route
+const oidcDiscoveryProviderIngressName = "spire-oidc-discovery-provider"
+
+// generateOIDCDiscoveryProviderIngress returns the Ingress of the OIDC discovery provider Service.
+// The host defaults to the host of the JWT issuer, like the host of the managed Route.
+func generateOIDCDiscoveryProviderIngress(config *v1alpha1.SpireOIDCDiscoveryProvider) (*networkingv1.Ingress, error) {
+	ingressConfig := config.Spec.Ingress
+	labels := utils.SpireOIDCDiscoveryProviderLabels(config.Spec.Labels)
+
+	host := ingressConfig.Host
+	if host == "" {
+		issuer := config.Spec.JwtIssuer
+		if !strings.Contains(issuer, "://") {
+			issuer = "https://" + issuer
+		}
+		issuerURL, err := url.Parse(issuer)
+		if err != nil || issuerURL.Hostname() == "" {
+			return nil, fmt.Errorf("invalid JWT issuer URL %q: the Ingress host cannot be defaulted", config.Spec.JwtIssuer)
+		}
+		host = issuerURL.Hostname()
+	}
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        oidcDiscoveryProviderIngressName,
+			Namespace:   utils.GetOperandNamespace(),
+			Labels:      labels,
+			Annotations: ingressConfig.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: ptr.To(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "spire-spiffe-oidc-discovery-provider",
+											Port: networkingv1.ServiceBackendPort{Name: "https"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if ingressConfig.ClassName != "" {
+		ingress.Spec.IngressClassName = ptr.To(ingressConfig.ClassName)
+	}
+	if ingressConfig.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{Hosts: []string{host}, SecretName: ingressConfig.TLSSecretName},
+		}
+	}
+
+	return ingress, nil
+}
+
+// reconcileIngress reconciles the Ingress of the OIDC discovery provider, when configured
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileIngress(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, createOnlyMode bool) error {
+	existing := &networkingv1.Ingress{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: oidcDiscoveryProviderIngressName, Namespace: utils.GetOperandNamespace()}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get Ingress")
+		statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressGetFailed",
+			fmt.Sprintf("Failed to get Ingress: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	exists := err == nil
+
+	if oidc.Spec.Ingress == nil {
+		if exists {
+			if err := r.ctrlClient.Delete(ctx, existing); err != nil && !kerrors.IsNotFound(err) {
+				r.log.Error(err, "failed to delete Ingress")
+				statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressDeletionFailed",
+					fmt.Sprintf("Failed to delete Ingress: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Deleted Ingress", "name", existing.Name, "namespace", existing.Namespace)
+		}
+		statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressDisabled",
+			"Ingress not configured",
+			metav1.ConditionTrue)
+		return nil
+	}
+
+	desired, err := generateOIDCDiscoveryProviderIngress(oidc)
+	if err != nil {
+		r.log.Error(err, "failed to generate Ingress")
+		statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressGenerationFailed",
+			fmt.Sprintf("Failed to generate Ingress: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := controllerutil.SetControllerReference(oidc, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on Ingress")
+		statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on Ingress: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	if !exists {
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create Ingress")
+			statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressCreationFailed",
+				fmt.Sprintf("Failed to create Ingress: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created Ingress", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+	} else if utils.ResourceNeedsUpdate(existing, desired) {
+		if createOnlyMode {
+			r.log.Info("Skipping Ingress update due to create-only mode")
+		} else {
+			desired.ResourceVersion = existing.ResourceVersion
+			if err := r.ctrlClient.Update(ctx, desired); err != nil {
+				r.log.Error(err, "failed to update Ingress")
+				statusMgr.AddCondition(IngressAvailable, "SpireOIDCIngressUpdateFailed",
+					fmt.Sprintf("Failed to update Ingress: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Updated Ingress", "name", desired.Name, "namespace", desired.Namespace)
+			statusMgr.RecordDriftRepaired(desired)
+		}
+	}
+
+	statusMgr.AddCondition(IngressAvailable, v1alpha1.ReasonReady,
+		"Ingress available",
+		metav1.ConditionTrue)
+	return nil
+}
@@ -0,0 +1,214 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func newIngressTestOIDC(ingress *v1alpha1.OIDCIngressConfig) *v1alpha1.SpireOIDCDiscoveryProvider {
+	return &v1alpha1.SpireOIDCDiscoveryProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+		Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			JwtIssuer: "https://oidc.example.com/issuer",
+			Ingress:   ingress,
+		},
+	}
+}
+
+func TestGenerateOIDCDiscoveryProviderIngress(t *testing.T) {
+	t.Run("defaults the host to the JWT issuer host", func(t *testing.T) {
+		ingress, err := generateOIDCDiscoveryProviderIngress(newIngressTestOIDC(&v1alpha1.OIDCIngressConfig{}))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if ingress.Name != "spire-oidc-discovery-provider" {
+			t.Errorf("Expected name 'spire-oidc-discovery-provider', got '%s'", ingress.Name)
+		}
+		if ingress.Spec.IngressClassName != nil {
+			t.Errorf("Expected the default IngressClass, got %v", *ingress.Spec.IngressClassName)
+		}
+		if len(ingress.Spec.TLS) != 0 {
+			t.Errorf("Expected no TLS configuration, got %v", ingress.Spec.TLS)
+		}
+		if len(ingress.Spec.Rules) != 1 || ingress.Spec.Rules[0].Host != "oidc.example.com" {
+			t.Fatalf("Expected a rule for host 'oidc.example.com', got %v", ingress.Spec.Rules)
+		}
+		paths := ingress.Spec.Rules[0].HTTP.Paths
+		if len(paths) != 1 || paths[0].Path != "/" || *paths[0].PathType != networkingv1.PathTypePrefix {
+			t.Fatalf("Expected a single '/' prefix path, got %v", paths)
+		}
+		backend := paths[0].Backend.Service
+		if backend.Name != "spire-spiffe-oidc-discovery-provider" || backend.Port.Name != "https" {
+			t.Errorf("Expected the https port of the OIDC discovery provider Service, got %v", backend)
+		}
+	})
+
+	t.Run("uses the configured class, host, TLS secret and annotations", func(t *testing.T) {
+		ingress, err := generateOIDCDiscoveryProviderIngress(newIngressTestOIDC(&v1alpha1.OIDCIngressConfig{
+			ClassName:     "nginx",
+			Host:          "oidc.internal.example.com",
+			TLSSecretName: "oidc-tls",
+			Annotations:   map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"},
+		}))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if ingress.Spec.IngressClassName == nil || *ingress.Spec.IngressClassName != "nginx" {
+			t.Errorf("Expected IngressClass 'nginx', got %v", ingress.Spec.IngressClassName)
+		}
+		if ingress.Spec.Rules[0].Host != "oidc.internal.example.com" {
+			t.Errorf("Expected host 'oidc.internal.example.com', got '%s'", ingress.Spec.Rules[0].Host)
+		}
+		if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "oidc-tls" ||
+			len(ingress.Spec.TLS[0].Hosts) != 1 || ingress.Spec.TLS[0].Hosts[0] != "oidc.internal.example.com" {
+			t.Errorf("Expected TLS for the host with secret 'oidc-tls', got %v", ingress.Spec.TLS)
+		}
+		if ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"] != "HTTPS" {
+			t.Errorf("Expected the configured annotations, got %v", ingress.Annotations)
+		}
+	})
+
+	t.Run("accepts a JWT issuer without scheme", func(t *testing.T) {
+		oidc := newIngressTestOIDC(&v1alpha1.OIDCIngressConfig{})
+		oidc.Spec.JwtIssuer = "oidc.example.com"
+		ingress, err := generateOIDCDiscoveryProviderIngress(oidc)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if ingress.Spec.Rules[0].Host != "oidc.example.com" {
+			t.Errorf("Expected host 'oidc.example.com', got '%s'", ingress.Spec.Rules[0].Host)
+		}
+	})
+
+	t.Run("fails when the host cannot be defaulted", func(t *testing.T) {
+		oidc := newIngressTestOIDC(&v1alpha1.OIDCIngressConfig{})
+		oidc.Spec.JwtIssuer = ""
+		if _, err := generateOIDCDiscoveryProviderIngress(oidc); err == nil {
+			t.Error("Expected an error for an empty JWT issuer")
+		}
+	})
+}
+
+func TestReconcileIngress(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "spire-oidc-discovery-provider")
+	configured := &v1alpha1.OIDCIngressConfig{ClassName: "nginx"}
+	existingIngress := func(fc *fakes.FakeCustomCtrlClient, mutate func(*networkingv1.Ingress)) {
+		fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if ingress, ok := obj.(*networkingv1.Ingress); ok {
+				desired, _ := generateOIDCDiscoveryProviderIngress(newIngressTestOIDC(configured))
+				*ingress = *desired
+				ingress.ResourceVersion = "123"
+				mutate(ingress)
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name           string
+		ingress        *v1alpha1.OIDCIngressConfig
+		setupClient    func(*fakes.FakeCustomCtrlClient)
+		createOnlyMode bool
+		expectError    bool
+		expectCreate   int
+		expectUpdate   int
+		expectDelete   int
+	}{
+		{
+			name:    "create when not found",
+			ingress: configured,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+		},
+		{
+			name:    "create error",
+			ingress: configured,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+				fc.CreateReturns(errors.New("create failed"))
+			},
+			expectError:  true,
+			expectCreate: 1,
+		},
+		{
+			name:    "get error",
+			ingress: configured,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(errors.New("connection refused"))
+			},
+			expectError: true,
+		},
+		{
+			name:    "up to date",
+			ingress: configured,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingIngress(fc, func(*networkingv1.Ingress) {})
+			},
+		},
+		{
+			name:    "update when the rules drifted",
+			ingress: configured,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingIngress(fc, func(ingress *networkingv1.Ingress) { ingress.Spec.Rules = nil })
+			},
+			expectUpdate: 1,
+		},
+		{
+			name:    "create only mode skips update",
+			ingress: configured,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingIngress(fc, func(ingress *networkingv1.Ingress) { ingress.Spec.Rules = nil })
+			},
+			createOnlyMode: true,
+		},
+		{
+			name: "not configured deletes existing",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingIngress(fc, func(*networkingv1.Ingress) {})
+			},
+			expectDelete: 1,
+		},
+		{
+			name: "not configured and absent",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			statusMgr := status.NewManager(fakeClient)
+
+			err := reconciler.reconcileIngress(context.Background(), newIngressTestOIDC(tt.ingress), statusMgr, tt.createOnlyMode)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcileIngress() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// generateOIDCNetworkPolicy returns the NetworkPolicy of the OIDC discovery provider pods, allowing any client,
+// e.g. the router of the Route or the relying parties in the cluster, on the ports of the generated Service
+func generateOIDCNetworkPolicy(config *v1alpha1.SpireOIDCDiscoveryProviderSpec) *networkingv1.NetworkPolicy {
+	labels := utils.SpireOIDCDiscoveryProviderLabels(config.Labels)
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{Ports: utils.NetworkPolicyPorts(getSpireOIDCDiscoveryProviderService(config.Labels).Spec.Ports)},
+	}
+	return utils.GenerateNetworkPolicy("spire-spiffe-oidc-discovery-provider", labels, utils.PodSelectorLabels(labels), ingress)
+}
+
+// reconcileNetworkPolicy reconciles the NetworkPolicy of the OIDC discovery provider pods
+func (r *SpireOidcDiscoveryProviderReconciler) reconcileNetworkPolicy(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) error {
+	desired := generateOIDCNetworkPolicy(&oidc.Spec)
+
+	existing := &networkingv1.NetworkPolicy{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get NetworkPolicy")
+		statusMgr.AddCondition(NetworkPolicyAvailable, "SpireOIDCNetworkPolicyGetFailed",
+			fmt.Sprintf("Failed to get NetworkPolicy: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	exists := err == nil
+
+	if !utils.IsNetworkPolicyEnabled(ztwim.Spec.NetworkPolicy) {
+		if exists {
+			if err := r.ctrlClient.Delete(ctx, existing); err != nil && !kerrors.IsNotFound(err) {
+				r.log.Error(err, "failed to delete NetworkPolicy")
+				statusMgr.AddCondition(NetworkPolicyAvailable, "SpireOIDCNetworkPolicyDeletionFailed",
+					fmt.Sprintf("Failed to delete NetworkPolicy: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Deleted NetworkPolicy", "name", desired.Name, "namespace", desired.Namespace)
+		}
+		statusMgr.AddCondition(NetworkPolicyAvailable, "SpireOIDCNetworkPolicyDisabled",
+			"NetworkPolicy management disabled",
+			metav1.ConditionTrue)
+		return nil
+	}
+
+	if err := controllerutil.SetControllerReference(oidc, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on NetworkPolicy")
+		statusMgr.AddCondition(NetworkPolicyAvailable, "SpireOIDCNetworkPolicyGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on NetworkPolicy: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	if !exists {
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create NetworkPolicy")
+			statusMgr.AddCondition(NetworkPolicyAvailable, "SpireOIDCNetworkPolicyCreationFailed",
+				fmt.Sprintf("Failed to create NetworkPolicy: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created NetworkPolicy", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+	} else if utils.ResourceNeedsUpdate(existing, desired) {
+		if createOnlyMode {
+			r.log.Info("Skipping NetworkPolicy update due to create-only mode")
+		} else {
+			desired.ResourceVersion = existing.ResourceVersion
+			if err := r.ctrlClient.Update(ctx, desired); err != nil {
+				r.log.Error(err, "failed to update NetworkPolicy")
+				statusMgr.AddCondition(NetworkPolicyAvailable, "SpireOIDCNetworkPolicyUpdateFailed",
+					fmt.Sprintf("Failed to update NetworkPolicy: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Updated NetworkPolicy", "name", desired.Name, "namespace", desired.Namespace)
+			statusMgr.RecordDriftRepaired(desired)
+		}
+	}
+
+	statusMgr.AddCondition(NetworkPolicyAvailable, v1alpha1.ReasonReady,
+		"NetworkPolicy available",
+		metav1.ConditionTrue)
+	return nil
+}
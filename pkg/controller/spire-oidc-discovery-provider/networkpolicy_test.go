@@ -0,0 +1,113 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func TestGenerateOIDCNetworkPolicy(t *testing.T) {
+	policy := generateOIDCNetworkPolicy(&v1alpha1.SpireOIDCDiscoveryProviderSpec{})
+	if policy.Name != "spire-spiffe-oidc-discovery-provider" {
+		t.Errorf("Expected name 'spire-spiffe-oidc-discovery-provider', got '%s'", policy.Name)
+	}
+	if policy.Spec.PodSelector.MatchLabels["app.kubernetes.io/name"] != "spiffe-oidc-discovery-provider" {
+		t.Errorf("Expected the OIDC discovery provider pods, got %v", policy.Spec.PodSelector)
+	}
+	if len(policy.Spec.Ingress) != 1 {
+		t.Fatalf("Expected 1 ingress rule, got %d", len(policy.Spec.Ingress))
+	}
+	rule := policy.Spec.Ingress[0]
+	if len(rule.Ports) != 1 || *rule.Ports[0].Port != intstr.FromString("https") {
+		t.Errorf("Expected the https port, got %v", rule.Ports)
+	}
+	if len(rule.From) != 0 {
+		t.Errorf("Expected the serving port to accept any client, got %v", rule.From)
+	}
+}
+
+func TestReconcileNetworkPolicy(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "spire-spiffe-oidc-discovery-provider")
+	existingPolicy := func(fc *fakes.FakeCustomCtrlClient) {
+		fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if policy, ok := obj.(*networkingv1.NetworkPolicy); ok {
+				*policy = *generateOIDCNetworkPolicy(&v1alpha1.SpireOIDCDiscoveryProviderSpec{})
+				policy.ResourceVersion = "123"
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name          string
+		networkPolicy *v1alpha1.NetworkPolicyConfig
+		setupClient   func(*fakes.FakeCustomCtrlClient)
+		expectCreate  int
+		expectUpdate  int
+		expectDelete  int
+	}{
+		{
+			name:          "create when enabled",
+			networkPolicy: &v1alpha1.NetworkPolicyConfig{Enabled: "true"},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+		},
+		{
+			name:          "up to date",
+			networkPolicy: &v1alpha1.NetworkPolicyConfig{Enabled: "true"},
+			setupClient:   existingPolicy,
+		},
+		{
+			name:          "disabled deletes existing",
+			networkPolicy: &v1alpha1.NetworkPolicyConfig{Enabled: "false"},
+			setupClient:   existingPolicy,
+			expectDelete:  1,
+		},
+		{
+			name: "disabled by default",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			oidc := &v1alpha1.SpireOIDCDiscoveryProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+			}
+			ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+				Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{NetworkPolicy: tt.networkPolicy},
+			}
+			statusMgr := status.NewManager(fakeClient)
+
+			if err := reconciler.reconcileNetworkPolicy(context.Background(), oidc, statusMgr, ztwim, false); err != nil {
+				t.Fatalf("reconcileNetworkPolicy() error = %v", err)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package spire_oidc_discovery_provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func TestGenerateOIDCPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name                   string
+		spec                   v1alpha1.SpireOIDCDiscoveryProviderSpec
+		expectedMinAvailable   *intstr.IntOrString
+		expectedMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:                   "single replica defaults to maxUnavailable 1",
+			spec:                   v1alpha1.SpireOIDCDiscoveryProviderSpec{ReplicaCount: 1},
+			expectedMaxUnavailable: ptr.To(intstr.FromInt32(1)),
+		},
+		{
+			name:                 "multiple replicas default to minAvailable 1",
+			spec:                 v1alpha1.SpireOIDCDiscoveryProviderSpec{ReplicaCount: 3},
+			expectedMinAvailable: ptr.To(intstr.FromInt32(1)),
+		},
+		{
+			name: "configured maxUnavailable",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+				ReplicaCount:        3,
+				PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: ptr.To(intstr.FromString("50%"))},
+			},
+			expectedMaxUnavailable: ptr.To(intstr.FromString("50%")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdb := generateOIDCPodDisruptionBudget(&tt.spec, nil)
+			if pdb.Name != "spire-spiffe-oidc-discovery-provider" {
+				t.Errorf("Expected name 'spire-spiffe-oidc-discovery-provider', got '%s'", pdb.Name)
+			}
+			deployment := generateDeployment(&v1alpha1.SpireOIDCDiscoveryProvider{Spec: tt.spec}, nil, "")
+			if len(pdb.Spec.Selector.MatchLabels) != len(deployment.Spec.Selector.MatchLabels) {
+				t.Errorf("Expected selector %v, got %v", deployment.Spec.Selector.MatchLabels, pdb.Spec.Selector.MatchLabels)
+			}
+			for k, v := range deployment.Spec.Selector.MatchLabels {
+				if pdb.Spec.Selector.MatchLabels[k] != v {
+					t.Errorf("Expected selector label %s=%s, got %s", k, v, pdb.Spec.Selector.MatchLabels[k])
+				}
+			}
+			if (pdb.Spec.MinAvailable == nil) != (tt.expectedMinAvailable == nil) ||
+				(pdb.Spec.MinAvailable != nil && *pdb.Spec.MinAvailable != *tt.expectedMinAvailable) {
+				t.Errorf("Expected minAvailable %v, got %v", tt.expectedMinAvailable, pdb.Spec.MinAvailable)
+			}
+			if (pdb.Spec.MaxUnavailable == nil) != (tt.expectedMaxUnavailable == nil) ||
+				(pdb.Spec.MaxUnavailable != nil && *pdb.Spec.MaxUnavailable != *tt.expectedMaxUnavailable) {
+				t.Errorf("Expected maxUnavailable %v, got %v", tt.expectedMaxUnavailable, pdb.Spec.MaxUnavailable)
+			}
+		})
+	}
+}
+
+func TestReconcileOIDCPodDisruptionBudget(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "spire-spiffe-oidc-discovery-provider")
+
+	tests := []struct {
+		name         string
+		spec         v1alpha1.SpireOIDCDiscoveryProviderSpec
+		setupClient  func(*fakes.FakeCustomCtrlClient)
+		expectError  bool
+		expectCreate int
+		expectUpdate int
+		expectDelete int
+	}{
+		{
+			name: "create when not found",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+		},
+		{
+			name: "get error",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(errors.New("connection refused"))
+			},
+			expectError: true,
+		},
+		{
+			name: "update when replicas change the default",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{ReplicaCount: 2},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if pdb, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+						*pdb = *generateOIDCPodDisruptionBudget(&v1alpha1.SpireOIDCDiscoveryProviderSpec{ReplicaCount: 1}, nil)
+						pdb.ResourceVersion = "123"
+					}
+					return nil
+				}
+			},
+			expectUpdate: 1,
+		},
+		{
+			name: "disabled deletes existing",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+				PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{Enabled: "false"},
+			},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(nil)
+			},
+			expectDelete: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			oidc := &v1alpha1.SpireOIDCDiscoveryProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+				Spec:       tt.spec,
+			}
+			statusMgr := status.NewManager(fakeClient)
+
+			err := reconciler.reconcilePodDisruptionBudget(context.Background(), oidc, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, false)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcilePodDisruptionBudget() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+		})
+	}
+}
@@ -54,6 +54,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRole(ctx con
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.Role{}
@@ -79,6 +80,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRole(ctx con
 		}
 
 		r.log.Info("Created external cert Role", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -105,6 +107,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRole(ctx con
 	}
 
 	r.log.Info("Updated external cert Role", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -119,6 +122,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRoleBinding(
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.RoleBinding{}
@@ -144,6 +148,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRoleBinding(
 		}
 
 		r.log.Info("Created external cert RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -170,6 +175,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRoleBinding(
 	}
 
 	r.log.Info("Updated external cert RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -178,14 +184,14 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileExternalCertRoleBinding(
 func getExternalCertRole(customLabels map[string]string) *rbacv1.Role {
 	role := utils.DecodeRoleObjBytes(assets.MustAsset(utils.SpireOIDCExternalCertRoleAssetName))
 	role.Labels = utils.SpireOIDCDiscoveryProviderLabels(customLabels)
-	role.Namespace = utils.GetOperatorNamespace()
+	role.Namespace = utils.GetOperandNamespace()
 	return role
 }
 
 func getExternalCertRoleBinding(customLabels map[string]string) *rbacv1.RoleBinding {
 	rb := utils.DecodeRoleBindingObjBytes(assets.MustAsset(utils.SpireOIDCExternalCertRoleBindingAssetName))
 	rb.Labels = utils.SpireOIDCDiscoveryProviderLabels(customLabels)
-	rb.Namespace = utils.GetOperatorNamespace()
+	rb.Namespace = utils.GetOperandNamespace()
 	// Note: subjects namespace (openshift-ingress) is already set in the template
 	return rb
 }
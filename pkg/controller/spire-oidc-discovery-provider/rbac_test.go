@@ -117,7 +117,7 @@ func newFakeClient(store *testStore) customClient.CustomCtrlClient {
 		return true, nil
 	}
 
-	fake.CreateOrUpdateObjectStub = func(ctx context.Context, obj client.Object) error {
+	fake.ApplyStub = func(ctx context.Context, obj client.Object, opts ...client.PatchOption) error {
 		err := store.Create(ctx, obj)
 		if err != nil && kerrors.IsAlreadyExists(err) {
 			return store.Update(ctx, obj)
@@ -20,6 +20,14 @@ import (
 
 // reconcileRoute reconciles the OIDC Discovery Provider Route
 func (r *SpireOidcDiscoveryProviderReconciler) reconcileRoute(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, createOnlyMode bool) error {
+	if oidc.Spec.Ingress != nil {
+		// The Ingress replaces the Route; the Route is no longer tracked and is pruned
+		statusMgr.AddCondition(RouteAvailable, "ManagedRouteReplacedByIngress",
+			"Spire OIDC endpoints are exposed through the managed Ingress",
+			metav1.ConditionTrue)
+		return nil
+	}
+
 	if utils.StringToBool(oidc.Spec.ManagedRoute) {
 		// Create Route for OIDC Discovery Provider
 		route, err := generateOIDCDiscoveryProviderRoute(oidc)
@@ -30,6 +38,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileRoute(ctx context.Contex
 				metav1.ConditionFalse)
 			return err
 		}
+		statusMgr.TrackResource(route)
 
 		var existingRoute routev1.Route
 		err = r.ctrlClient.Get(ctx, types.NamespacedName{
@@ -52,6 +61,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileRoute(ctx context.Contex
 					metav1.ConditionTrue)
 
 				r.log.Info("Created route", "Namespace", route.Namespace, "Name", route.Name)
+				statusMgr.RecordResourceCreated(route)
 			} else {
 				r.log.Error(err, "Failed to get existing route")
 				statusMgr.AddCondition(RouteAvailable, "ManagedRouteRetrievalFailed",
@@ -80,6 +90,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileRoute(ctx context.Contex
 					metav1.ConditionTrue)
 
 				r.log.Info("Updated route", "Namespace", route.Namespace, "Name", route.Name)
+				statusMgr.RecordDriftRepaired(route)
 			}
 		} else {
 			// Route exists and is up to date - only update status if it's currently not ready
@@ -119,7 +130,7 @@ func generateOIDCDiscoveryProviderRoute(config *v1alpha1.SpireOIDCDiscoveryProvi
 	route := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-oidc-discovery-provider",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    labels,
 		},
 		Spec: routev1.RouteSpec{
@@ -831,6 +831,34 @@ func TestReconcileRoute_ManagedRouteDisabled(t *testing.T) {
 	}
 }
 
+// TestReconcileRoute_IngressConfigured tests that the Ingress replaces the managed Route
+func TestReconcileRoute_IngressConfigured(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newRouteTestReconciler(fakeClient)
+
+	oidc := &v1alpha1.SpireOIDCDiscoveryProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			UID:  "test-uid",
+		},
+		Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			ManagedRoute: "true",
+			JwtIssuer:    "https://test.example.com",
+			Ingress:      &v1alpha1.OIDCIngressConfig{},
+		},
+	}
+
+	statusMgr := status.NewManager(fakeClient)
+	err := reconciler.reconcileRoute(context.Background(), oidc, statusMgr, false)
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if fakeClient.GetCallCount() != 0 || fakeClient.CreateCallCount() != 0 {
+		t.Error("Expected the Route not to be managed when the Ingress is configured")
+	}
+}
+
 // TestReconcileRoute_CreateSuccess tests successful Route creation
 func TestReconcileRoute_CreateSuccess(t *testing.T) {
 	fakeClient := &fakes.FakeCustomCtrlClient{}
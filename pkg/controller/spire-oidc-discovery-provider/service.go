@@ -29,6 +29,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileService(ctx context.Cont
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.Service{}
@@ -54,6 +55,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileService(ctx context.Cont
 		}
 
 		r.log.Info("Created Service", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(ServiceAvailable, v1alpha1.ReasonReady,
 			"All Service resources available",
 			metav1.ConditionTrue)
@@ -107,6 +109,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileService(ctx context.Cont
 	}
 
 	r.log.Info("Updated Service", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(ServiceAvailable, v1alpha1.ReasonReady,
 		"All Service resources available",
 		metav1.ConditionTrue)
@@ -117,7 +120,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileService(ctx context.Cont
 func getSpireOIDCDiscoveryProviderService(customLabels map[string]string) *corev1.Service {
 	svc := utils.DecodeServiceObjBytes(assets.MustAsset(utils.SpireOIDCDiscoveryProviderServiceAssetName))
 	svc.Labels = utils.SpireOIDCDiscoveryProviderLabels(customLabels)
-	svc.Namespace = utils.GetOperatorNamespace()
+	svc.Namespace = utils.GetOperandNamespace()
 	svc.Spec.Selector = map[string]string{
 		"app.kubernetes.io/name":     "spiffe-oidc-discovery-provider",
 		"app.kubernetes.io/instance": utils.StandardInstance,
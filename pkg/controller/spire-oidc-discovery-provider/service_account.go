@@ -3,6 +3,7 @@ package spire_oidc_discovery_provider
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -20,7 +21,7 @@ import (
 
 // reconcileServiceAccount reconciles the Spire OIDC Discovery Provider ServiceAccount
 func (r *SpireOidcDiscoveryProviderReconciler) reconcileServiceAccount(ctx context.Context, oidc *v1alpha1.SpireOIDCDiscoveryProvider, statusMgr *status.Manager, createOnlyMode bool) error {
-	desired := getSpireOIDCDiscoveryProviderServiceAccount(oidc.Spec.Labels)
+	desired := getSpireOIDCDiscoveryProviderServiceAccount(oidc.Spec.Labels, oidc.Spec.ServiceAccountAnnotations)
 
 	if err := controllerutil.SetControllerReference(oidc, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on service account")
@@ -29,6 +30,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileServiceAccount(ctx conte
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.ServiceAccount{}
@@ -54,6 +56,7 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileServiceAccount(ctx conte
 		}
 
 		r.log.Info("Created ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 			"All ServiceAccount resources available",
 			metav1.ConditionTrue)
@@ -89,16 +92,18 @@ func (r *SpireOidcDiscoveryProviderReconciler) reconcileServiceAccount(ctx conte
 	}
 
 	r.log.Info("Updated ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 		"All ServiceAccount resources available",
 		metav1.ConditionTrue)
 	return nil
 }
 
-// getSpireOIDCDiscoveryProviderServiceAccount returns the Spire OIDC Discovery Provider ServiceAccount with proper labels
-func getSpireOIDCDiscoveryProviderServiceAccount(customLabels map[string]string) *corev1.ServiceAccount {
+// getSpireOIDCDiscoveryProviderServiceAccount returns the Spire OIDC Discovery Provider ServiceAccount with proper labels and annotations
+func getSpireOIDCDiscoveryProviderServiceAccount(customLabels, annotations map[string]string) *corev1.ServiceAccount {
 	sa := utils.DecodeServiceAccountObjBytes(assets.MustAsset(utils.SpireOIDCDiscoveryProviderServiceAccountAssetName))
 	sa.Labels = utils.SpireOIDCDiscoveryProviderLabels(customLabels)
-	sa.Namespace = utils.GetOperatorNamespace()
+	sa.Namespace = utils.GetOperandNamespace()
+	sa.Annotations = maps.Clone(annotations)
 	return sa
 }
@@ -21,7 +21,7 @@ import (
 
 func TestGetSpireOIDCDiscoveryProviderServiceAccount(t *testing.T) {
 	t.Run("without custom labels", func(t *testing.T) {
-		sa := getSpireOIDCDiscoveryProviderServiceAccount(nil)
+		sa := getSpireOIDCDiscoveryProviderServiceAccount(nil, nil)
 
 		if sa == nil {
 			t.Fatal("Expected ServiceAccount, got nil")
@@ -56,7 +56,7 @@ func TestGetSpireOIDCDiscoveryProviderServiceAccount(t *testing.T) {
 			"zone":         "global",
 		}
 
-		sa := getSpireOIDCDiscoveryProviderServiceAccount(customLabels)
+		sa := getSpireOIDCDiscoveryProviderServiceAccount(customLabels, nil)
 
 		if sa == nil {
 			t.Fatal("Expected ServiceAccount, got nil")
@@ -83,7 +83,7 @@ func TestGetSpireOIDCDiscoveryProviderServiceAccount(t *testing.T) {
 
 	t.Run("preserves all asset labels", func(t *testing.T) {
 		// Get labels without custom labels (these come from asset file)
-		saWithoutCustom := getSpireOIDCDiscoveryProviderServiceAccount(nil)
+		saWithoutCustom := getSpireOIDCDiscoveryProviderServiceAccount(nil, nil)
 		assetLabels := make(map[string]string)
 		for k, v := range saWithoutCustom.Labels {
 			assetLabels[k] = v
@@ -93,7 +93,7 @@ func TestGetSpireOIDCDiscoveryProviderServiceAccount(t *testing.T) {
 		customLabels := map[string]string{
 			"release": "v2.5.0",
 		}
-		saWithCustom := getSpireOIDCDiscoveryProviderServiceAccount(customLabels)
+		saWithCustom := getSpireOIDCDiscoveryProviderServiceAccount(customLabels, nil)
 
 		// All asset labels should still be present
 		for k, v := range assetLabels {
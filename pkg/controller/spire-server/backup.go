@@ -0,0 +1,478 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/version"
+)
+
+const (
+	// datastoreBackupCronJobName is the name of the CronJob backing up the datastore
+	datastoreBackupCronJobName = "spire-server-datastore-backup"
+	// datastoreBackupMountPath is where the backup volume is mounted in the backup and restore containers
+	datastoreBackupMountPath = "/backup"
+	// spireDataMountPath is where the data volume of the SPIRE server is mounted
+	spireDataMountPath = "/run/spire/data"
+	// spireDataPVCName is the PVC provisioned for the data volume of the single SPIRE server replica
+	spireDataPVCName = "spire-data-spire-server-0"
+	// datastoreCredentialsSecretName is the Secret holding the connection string of the backup and restore containers
+	datastoreCredentialsSecretName = "spire-server-datastore-credentials"
+	// datastoreCredentialsSecretKey is the key of the connection string in the datastore credentials Secret
+	datastoreCredentialsSecretKey = "connection-string"
+)
+
+// datastoreBackupScript writes a timestamped backup to the backup volume and deletes the oldest
+// backups beyond the retention. Backups are written to a temporary file first, so that a failed
+// backup never shows up as a complete one. The sqlite3 database is copied with the online backup
+// API, which takes a consistent snapshot including the transactions committed to the WAL file.
+const datastoreBackupScript = `set -eu
+name="datastore-$(date -u +%Y%m%d%H%M%S).${BACKUP_EXTENSION}"
+if [ "${DATABASE_TYPE}" = "sqlite3" ]; then
+  sqlite3 -cmd ".timeout 30000" "${DATABASE_PATH}" ".backup '/backup/${name}.tmp'"
+else
+  pg_dump --format=custom --dbname="${CONNECTION_STRING}" --file="/backup/${name}.tmp"
+fi
+mv "/backup/${name}.tmp" "/backup/${name}"
+ls -1 /backup/datastore-*."${BACKUP_EXTENSION}" | sort -r | tail -n "+$((RETENTION + 1))" | xargs -r rm -f --
+echo "Backed up the datastore to ${name}"
+`
+
+// datastoreRestoreScript restores the backup once, recording the restored backup on the data volume
+// so that the restore is not repeated when the SPIRE server pod restarts. A sqlite3 backup is a
+// complete snapshot, so the WAL file of the replaced database is dropped with it.
+const datastoreRestoreScript = `set -eu
+marker="/run/spire/data/.restored-from"
+if [ "$(cat "${marker}" 2>/dev/null)" = "${BACKUP_FILE}" ]; then
+  echo "Backup ${BACKUP_FILE} already restored"
+  exit 0
+fi
+if [ "${DATABASE_TYPE}" = "sqlite3" ]; then
+  cp "/backup/${BACKUP_FILE}" "${DATABASE_PATH}.tmp"
+  rm -f "${DATABASE_PATH}-wal" "${DATABASE_PATH}-shm"
+  mv "${DATABASE_PATH}.tmp" "${DATABASE_PATH}"
+else
+  pg_restore --clean --if-exists --no-owner --dbname="${CONNECTION_STRING}" "/backup/${BACKUP_FILE}"
+fi
+echo "${BACKUP_FILE}" > "${marker}"
+echo "Restored the datastore from ${BACKUP_FILE}"
+`
+
+// backupFileExtension returns the extension of the backups of the given database type
+func backupFileExtension(databaseType string) string {
+	if databaseType == "sqlite3" {
+		return "sqlite3"
+	}
+	return "dump"
+}
+
+// sqliteDatabasePath returns the path of the sqlite3 database file of the connection string,
+// which must be on the data volume to be backed up
+func sqliteDatabasePath(connectionString string) (string, error) {
+	dbPath, _, _ := strings.Cut(strings.TrimPrefix(connectionString, "file:"), "?")
+	dbPath = path.Clean(dbPath)
+	if !strings.HasPrefix(dbPath, spireDataMountPath+"/") {
+		return "", fmt.Errorf("the sqlite3 database %q must be in %s to be backed up", dbPath, spireDataMountPath)
+	}
+	return dbPath, nil
+}
+
+// validateDatastoreBackup validates the backup configuration against the datastore and the persistence
+func validateDatastoreBackup(config *v1alpha1.SpireServerSpec) error {
+	backup := config.Backup
+	if backup == nil {
+		return nil
+	}
+	if fields := strings.Fields(backup.Schedule); len(fields) != 5 && !strings.HasPrefix(backup.Schedule, "@") {
+		return fmt.Errorf("backup.schedule %q must be a cron expression of 5 fields", backup.Schedule)
+	}
+
+	switch config.Datastore.DatabaseType {
+	case "sqlite3":
+		if _, err := sqliteDatabasePath(config.Datastore.ConnectionString); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+		// The backup pod mounts the data volume next to the SPIRE server pod
+		if config.Persistence.AccessMode == string(corev1.ReadWriteOncePod) {
+			return fmt.Errorf("backup of the sqlite3 datastore is not supported with the %s persistence access mode", corev1.ReadWriteOncePod)
+		}
+	case "postgres":
+	default:
+		return fmt.Errorf("backup of the %q datastore is not supported, only sqlite3 and postgres are", config.Datastore.DatabaseType)
+	}
+
+	if backup.RestoreFrom != "" {
+		if config.Persistence.Type == utils.PersistenceTypeEmptyDir {
+			return fmt.Errorf("backup.restoreFrom requires persistence.type %q", utils.PersistenceTypePersistentVolumeClaim)
+		}
+		if extension := backupFileExtension(config.Datastore.DatabaseType); path.Ext(backup.RestoreFrom) != "."+extension {
+			return fmt.Errorf("backup.restoreFrom %q is not a backup of the %s datastore, expected a .%s file", backup.RestoreFrom, config.Datastore.DatabaseType, extension)
+		}
+	}
+	return nil
+}
+
+// datastoreEnv returns the environment of the backup and restore containers locating the datastore.
+// The connection string holds the database credentials, so it is read from the datastore credentials
+// Secret rather than set in the pod spec.
+func datastoreEnv(datastore v1alpha1.DataStore) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "DATABASE_TYPE", Value: datastore.DatabaseType},
+		{Name: "BACKUP_EXTENSION", Value: backupFileExtension(datastore.DatabaseType)},
+	}
+	if datastore.DatabaseType == "sqlite3" {
+		dbPath, _ := sqliteDatabasePath(datastore.ConnectionString)
+		return append(env, corev1.EnvVar{Name: "DATABASE_PATH", Value: dbPath})
+	}
+	return append(env, corev1.EnvVar{
+		Name: "CONNECTION_STRING",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: datastoreCredentialsSecretName},
+			Key:                  datastoreCredentialsSecretKey,
+		}},
+	})
+}
+
+// generateDatastoreCredentialsSecret returns the Secret holding the connection string of the backup
+// and restore containers of an external datastore
+func generateDatastoreCredentialsSecret(config *v1alpha1.SpireServerSpec) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      datastoreCredentialsSecretName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.StandardizedLabels(datastoreBackupCronJobName, utils.ComponentControlPlane, version.SpireServerVersion, config.Labels),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{datastoreCredentialsSecretKey: []byte(config.Datastore.ConnectionString)},
+	}
+}
+
+// datastoreContainerSecurityContext returns the restricted security context of the backup and restore containers
+func datastoreContainerSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		RunAsNonRoot:             ptr.To(true),
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// datastoreTLSVolume returns the volume and mount of the database TLS Secret, if configured
+func datastoreTLSVolume(datastore v1alpha1.DataStore) (*corev1.Volume, *corev1.VolumeMount) {
+	if datastore.TLSSecretName == "" {
+		return nil, nil
+	}
+	return &corev1.Volume{
+		Name:         "db-certs",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: datastore.TLSSecretName}},
+	}, &corev1.VolumeMount{
+		Name:      "db-certs",
+		MountPath: DBTLSMountPath,
+		ReadOnly:  true,
+	}
+}
+
+// generateDatastoreBackupCronJob returns the CronJob backing up the datastore to the backup volume.
+// With the sqlite3 datastore, the backup pods mount the data volume of the SPIRE server and are
+// scheduled on the node of the SPIRE server pod. The data volume is mounted read-write, as reading
+// a database in WAL mode updates its shared memory file.
+func generateDatastoreBackupCronJob(config *v1alpha1.SpireServerSpec) *batchv1.CronJob {
+	// The backup pods must not match the selectors of the SPIRE server pods
+	labels := utils.StandardizedLabels(datastoreBackupCronJobName, utils.ComponentControlPlane, version.SpireServerVersion, config.Labels)
+	backup := config.Backup
+
+	volumes := []corev1.Volume{
+		{
+			Name: "datastore-backup",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: backup.PersistentVolumeClaim},
+			},
+		},
+		{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: "datastore-backup", MountPath: datastoreBackupMountPath},
+		{Name: "tmp", MountPath: "/tmp"},
+	}
+
+	var affinity *corev1.Affinity
+	if config.Datastore.DatabaseType == "sqlite3" {
+		volumes = append(volumes, corev1.Volume{
+			Name: "spire-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: spireDataPVCName},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "spire-data", MountPath: spireDataMountPath})
+		serverLabels := utils.SpireServerLabels(config.Labels)
+		affinity = &corev1.Affinity{
+			PodAffinity: &corev1.PodAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+					{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app.kubernetes.io/name":      serverLabels["app.kubernetes.io/name"],
+								"app.kubernetes.io/instance":  serverLabels["app.kubernetes.io/instance"],
+								"app.kubernetes.io/component": serverLabels["app.kubernetes.io/component"],
+							},
+						},
+						TopologyKey: corev1.LabelHostname,
+					},
+				},
+			},
+		}
+	}
+	if volume, mount := datastoreTLSVolume(config.Datastore); volume != nil {
+		volumes = append(volumes, *volume)
+		volumeMounts = append(volumeMounts, *mount)
+	}
+
+	env := append(datastoreEnv(config.Datastore), corev1.EnvVar{Name: "RETENTION", Value: strconv.Itoa(int(getBackupRetention(backup)))})
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      datastoreBackupCronJobName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   backup.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To(int32(3)),
+			FailedJobsHistoryLimit:     ptr.To(int32(3)),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							RestartPolicy:                corev1.RestartPolicyNever,
+							AutomountServiceAccountToken: ptr.To(false),
+							ImagePullSecrets:             config.ImagePullSecrets,
+							Affinity:                     affinity,
+							NodeSelector:                 utils.DerefNodeSelector(config.NodeSelector),
+							Tolerations:                  utils.DerefTolerations(config.Tolerations),
+							Containers: []corev1.Container{
+								{
+									Name:            "datastore-backup",
+									Image:           utils.GetDatastoreBackupImage(),
+									ImagePullPolicy: corev1.PullIfNotPresent,
+									Command:         []string{"/bin/sh", "-c", datastoreBackupScript},
+									Env:             env,
+									VolumeMounts:    volumeMounts,
+									SecurityContext: datastoreContainerSecurityContext(),
+								},
+							},
+							Volumes: volumes,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// pg_dump may connect to a database outside of the cluster
+	utils.AddProxyConfigToPod(&cronJob.Spec.JobTemplate.Spec.Template.Spec)
+	return cronJob
+}
+
+// getBackupRetention returns the number of backups to keep, defaulting to 7
+func getBackupRetention(backup *v1alpha1.DatastoreBackupConfig) int32 {
+	if backup.Retention > 0 {
+		return backup.Retention
+	}
+	return 7
+}
+
+// addDatastoreRestoreToStatefulSet adds the init container restoring backup.restoreFrom before the SPIRE server starts
+func addDatastoreRestoreToStatefulSet(sts *appsv1.StatefulSet, config *v1alpha1.SpireServerSpec) {
+	podSpec := &sts.Spec.Template.Spec
+	volumeMounts := []corev1.VolumeMount{
+		{Name: "spire-data", MountPath: spireDataMountPath},
+		{Name: "datastore-backup", MountPath: datastoreBackupMountPath, ReadOnly: true},
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "datastore-backup",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: config.Backup.PersistentVolumeClaim, ReadOnly: true},
+		},
+	})
+	// The database TLS Secret volume is already added for the spire-server container
+	if _, mount := datastoreTLSVolume(config.Datastore); mount != nil {
+		volumeMounts = append(volumeMounts, *mount)
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:            "restore-datastore",
+		Image:           utils.GetDatastoreBackupImage(),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/bin/sh", "-c", datastoreRestoreScript},
+		Env:             append(datastoreEnv(config.Datastore), corev1.EnvVar{Name: "BACKUP_FILE", Value: config.Backup.RestoreFrom}),
+		VolumeMounts:    volumeMounts,
+		SecurityContext: datastoreContainerSecurityContext(),
+	})
+}
+
+// datastoreBackupCondition reports the outcome of the last backup from the status of the CronJob
+func datastoreBackupCondition(cronJob *batchv1.CronJob) (string, string, metav1.ConditionStatus) {
+	lastSuccess := cronJob.Status.LastSuccessfulTime
+	lastSchedule := cronJob.Status.LastScheduleTime
+	if len(cronJob.Status.Active) == 0 && lastSchedule != nil && (lastSuccess == nil || lastSuccess.Before(lastSchedule)) {
+		return "DatastoreBackupFailed",
+			fmt.Sprintf("The datastore backup scheduled at %s failed", lastSchedule.UTC().Format(time.RFC3339)),
+			metav1.ConditionFalse
+	}
+	if lastSuccess != nil {
+		return "DatastoreBackupSucceeded",
+			fmt.Sprintf("Last successful datastore backup at %s", lastSuccess.UTC().Format(time.RFC3339)),
+			metav1.ConditionTrue
+	}
+	return "DatastoreBackupScheduled", "Datastore backups scheduled, no backup completed yet", metav1.ConditionTrue
+}
+
+// reconcileDatastoreBackup reconciles the CronJob backing up the datastore and reports the last backup.
+// The CronJob is deleted when backups are disabled.
+func (r *SpireServerReconciler) reconcileDatastoreBackup(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) error {
+	existing := &batchv1.CronJob{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: datastoreBackupCronJobName, Namespace: utils.GetOperandNamespace()}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get datastore backup CronJob")
+		statusMgr.AddCondition(DatastoreBackupAvailable, "DatastoreBackupCronJobGetFailed",
+			fmt.Sprintf("Failed to get datastore backup CronJob: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	exists := err == nil
+
+	if server.Spec.Backup == nil {
+		// Backups disabled - remove the CronJob of a previous configuration, don't set status
+		if exists {
+			if err := r.deleteDatastoreCredentialsSecret(ctx); err != nil {
+				return err
+			}
+			if err := r.ctrlClient.Delete(ctx, existing); err != nil && !kerrors.IsNotFound(err) {
+				r.log.Error(err, "failed to delete datastore backup CronJob")
+				return err
+			}
+			r.log.Info("Deleted datastore backup CronJob", "name", existing.Name, "namespace", existing.Namespace)
+		}
+		return nil
+	}
+
+	if err := r.reconcileDatastoreCredentialsSecret(ctx, server, existing, exists, statusMgr); err != nil {
+		return err
+	}
+
+	desired := generateDatastoreBackupCronJob(&server.Spec)
+	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on datastore backup CronJob")
+		statusMgr.AddCondition(DatastoreBackupAvailable, "DatastoreBackupCronJobGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on datastore backup CronJob: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	if !exists {
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create datastore backup CronJob")
+			statusMgr.AddCondition(DatastoreBackupAvailable, "DatastoreBackupCronJobCreationFailed",
+				fmt.Sprintf("Failed to create datastore backup CronJob: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created datastore backup CronJob", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+		existing = desired
+	} else if utils.ResourceNeedsUpdate(existing, desired) {
+		if createOnlyMode {
+			r.log.Info("Skipping datastore backup CronJob update due to create-only mode")
+		} else {
+			desired.ResourceVersion = existing.ResourceVersion
+			if err := r.ctrlClient.Update(ctx, desired); err != nil {
+				r.log.Error(err, "failed to update datastore backup CronJob")
+				statusMgr.AddCondition(DatastoreBackupAvailable, "DatastoreBackupCronJobUpdateFailed",
+					fmt.Sprintf("Failed to update datastore backup CronJob: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Updated datastore backup CronJob", "name", desired.Name, "namespace", desired.Namespace)
+			statusMgr.RecordDriftRepaired(desired)
+		}
+	}
+
+	reason, message, conditionStatus := datastoreBackupCondition(existing)
+	statusMgr.AddCondition(DatastoreBackupAvailable, reason, message, conditionStatus)
+	return nil
+}
+
+// reconcileDatastoreCredentialsSecret applies the Secret holding the connection string of an external
+// datastore, and deletes it once the datastore is the sqlite3 database. Secrets are not cached, so the
+// Secret is applied on each reconcile, and deleted only when the existing CronJob still references it.
+func (r *SpireServerReconciler) reconcileDatastoreCredentialsSecret(ctx context.Context, server *v1alpha1.SpireServer, existing *batchv1.CronJob, exists bool, statusMgr *status.Manager) error {
+	if server.Spec.Datastore.DatabaseType == "sqlite3" {
+		if exists && findEnvVar(existing.Spec.JobTemplate.Spec.Template.Spec.Containers, "CONNECTION_STRING") {
+			return r.deleteDatastoreCredentialsSecret(ctx)
+		}
+		return nil
+	}
+
+	secret := generateDatastoreCredentialsSecret(&server.Spec)
+	if err := controllerutil.SetControllerReference(server, secret, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on datastore credentials Secret")
+		statusMgr.AddCondition(DatastoreBackupAvailable, "DatastoreCredentialsSecretGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on datastore credentials Secret: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(secret)
+
+	if err := r.ctrlClient.Apply(ctx, secret); err != nil {
+		r.log.Error(err, "failed to apply datastore credentials Secret")
+		statusMgr.AddCondition(DatastoreBackupAvailable, "DatastoreCredentialsSecretApplyFailed",
+			fmt.Sprintf("Failed to apply datastore credentials Secret: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	return nil
+}
+
+// deleteDatastoreCredentialsSecret deletes the Secret holding the connection string of an external datastore
+func (r *SpireServerReconciler) deleteDatastoreCredentialsSecret(ctx context.Context) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: datastoreCredentialsSecretName, Namespace: utils.GetOperandNamespace()}}
+	if err := r.ctrlClient.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to delete datastore credentials Secret")
+		return err
+	}
+	return nil
+}
+
+// findEnvVar reports whether one of the containers sets the environment variable name
+func findEnvVar(containers []corev1.Container, name string) bool {
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
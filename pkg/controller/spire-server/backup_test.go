@@ -0,0 +1,361 @@
+package spire_server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func newBackupTestSpec(databaseType, connectionString string) *v1alpha1.SpireServerSpec {
+	return &v1alpha1.SpireServerSpec{
+		Persistence: v1alpha1.Persistence{Size: "1Gi", AccessMode: "ReadWriteOnce"},
+		Datastore:   v1alpha1.DataStore{DatabaseType: databaseType, ConnectionString: connectionString},
+		Backup: &v1alpha1.DatastoreBackupConfig{
+			Schedule:              "0 2 * * *",
+			PersistentVolumeClaim: "spire-backups",
+		},
+	}
+}
+
+func findEnv(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func TestValidateDatastoreBackup(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*v1alpha1.SpireServerSpec)
+		expectError bool
+	}{
+		{name: "sqlite3 datastore on the data volume"},
+		{name: "backups disabled", modify: func(spec *v1alpha1.SpireServerSpec) { spec.Backup = nil }},
+		{name: "schedule macro", modify: func(spec *v1alpha1.SpireServerSpec) { spec.Backup.Schedule = "@daily" }},
+		{name: "invalid schedule", modify: func(spec *v1alpha1.SpireServerSpec) { spec.Backup.Schedule = "daily" }, expectError: true},
+		{
+			name: "postgres datastore",
+			modify: func(spec *v1alpha1.SpireServerSpec) {
+				spec.Datastore = v1alpha1.DataStore{DatabaseType: "postgres", ConnectionString: "dbname=spire host=db"}
+			},
+		},
+		{
+			name:        "mysql datastore",
+			modify:      func(spec *v1alpha1.SpireServerSpec) { spec.Datastore.DatabaseType = "mysql" },
+			expectError: true,
+		},
+		{
+			name:        "sqlite3 database outside of the data volume",
+			modify:      func(spec *v1alpha1.SpireServerSpec) { spec.Datastore.ConnectionString = "/tmp/datastore.sqlite3" },
+			expectError: true,
+		},
+		{
+			name:        "sqlite3 database on a ReadWriteOncePod volume",
+			modify:      func(spec *v1alpha1.SpireServerSpec) { spec.Persistence.AccessMode = "ReadWriteOncePod" },
+			expectError: true,
+		},
+		{
+			name:   "restore a sqlite3 backup",
+			modify: func(spec *v1alpha1.SpireServerSpec) { spec.Backup.RestoreFrom = "datastore-20260102030405.sqlite3" },
+		},
+		{
+			name:        "restore a postgres backup into sqlite3",
+			modify:      func(spec *v1alpha1.SpireServerSpec) { spec.Backup.RestoreFrom = "datastore-20260102030405.dump" },
+			expectError: true,
+		},
+		{
+			name: "restore with emptyDir persistence",
+			modify: func(spec *v1alpha1.SpireServerSpec) {
+				spec.Datastore = v1alpha1.DataStore{DatabaseType: "postgres", ConnectionString: "dbname=spire host=db"}
+				spec.Persistence.Type = "EmptyDir"
+				spec.Backup.RestoreFrom = "datastore-20260102030405.dump"
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newBackupTestSpec("sqlite3", "/run/spire/data/datastore.sqlite3")
+			if tt.modify != nil {
+				tt.modify(spec)
+			}
+			err := validateDatastoreBackup(spec)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateDatastoreBackup() error = %v, expectError = %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestGenerateDatastoreBackupCronJob(t *testing.T) {
+	t.Run("sqlite3 datastore", func(t *testing.T) {
+		cronJob := generateDatastoreBackupCronJob(newBackupTestSpec("sqlite3", "file:/run/spire/data/datastore.sqlite3?_busy_timeout=5000"))
+
+		if cronJob.Spec.Schedule != "0 2 * * *" || cronJob.Spec.ConcurrencyPolicy != batchv1.ForbidConcurrent {
+			t.Errorf("Unexpected schedule or concurrency policy: %q, %q", cronJob.Spec.Schedule, cronJob.Spec.ConcurrencyPolicy)
+		}
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		if name := cronJob.Spec.JobTemplate.Spec.Template.Labels["app.kubernetes.io/name"]; name == "spire-server" {
+			t.Error("Expected the backup pods not to match the SPIRE server selector")
+		}
+
+		claims := map[string]string{}
+		for _, volume := range podSpec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				claims[volume.Name] = volume.PersistentVolumeClaim.ClaimName
+			}
+		}
+		if claims["datastore-backup"] != "spire-backups" || claims["spire-data"] != spireDataPVCName {
+			t.Errorf("Expected the backup and data PVCs to be mounted, got %v", claims)
+		}
+		if podSpec.Affinity == nil || podSpec.Affinity.PodAffinity == nil ||
+			podSpec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector.MatchLabels["app.kubernetes.io/name"] != "spire-server" {
+			t.Errorf("Expected the backup pods to be scheduled next to the SPIRE server pod, got %v", podSpec.Affinity)
+		}
+
+		for _, mount := range podSpec.Containers[0].VolumeMounts {
+			if mount.Name == "spire-data" && mount.ReadOnly {
+				t.Error("Expected the data volume to be mounted read-write for the sqlite3 online backup")
+			}
+		}
+		if script := podSpec.Containers[0].Command[2]; !strings.Contains(script, `sqlite3 -cmd ".timeout 30000" "${DATABASE_PATH}" ".backup`) {
+			t.Errorf("Expected the sqlite3 database to be copied with the online backup API, got %q", script)
+		}
+
+		env := podSpec.Containers[0].Env
+		if findEnv(env, "DATABASE_PATH") != "/run/spire/data/datastore.sqlite3" || findEnv(env, "RETENTION") != "7" {
+			t.Errorf("Unexpected backup environment: %v", env)
+		}
+	})
+
+	t.Run("postgres datastore", func(t *testing.T) {
+		spec := newBackupTestSpec("postgres", "dbname=spire host=db sslrootcert=/run/spire/db/certs/ca.crt")
+		spec.Datastore.TLSSecretName = "db-tls"
+		spec.Backup.Retention = 14
+		cronJob := generateDatastoreBackupCronJob(spec)
+
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		if podSpec.Affinity != nil {
+			t.Errorf("Expected no affinity without the sqlite3 datastore, got %v", podSpec.Affinity)
+		}
+		env := podSpec.Containers[0].Env
+		if findEnv(env, "BACKUP_EXTENSION") != "dump" || findEnv(env, "RETENTION") != "14" {
+			t.Errorf("Unexpected backup environment: %v", env)
+		}
+		for _, e := range env {
+			if e.Name != "CONNECTION_STRING" {
+				continue
+			}
+			if e.Value != "" || e.ValueFrom == nil || e.ValueFrom.SecretKeyRef == nil ||
+				e.ValueFrom.SecretKeyRef.Name != datastoreCredentialsSecretName || e.ValueFrom.SecretKeyRef.Key != datastoreCredentialsSecretKey {
+				t.Errorf("Expected the connection string to be read from the credentials Secret, got %v", e)
+			}
+		}
+
+		secret := generateDatastoreCredentialsSecret(spec)
+		if string(secret.Data[datastoreCredentialsSecretKey]) != spec.Datastore.ConnectionString {
+			t.Errorf("Expected the credentials Secret to hold the connection string, got %v", secret.Data)
+		}
+		mounted := false
+		for _, mount := range podSpec.Containers[0].VolumeMounts {
+			if mount.Name == "db-certs" && mount.MountPath == DBTLSMountPath {
+				mounted = true
+			}
+		}
+		if !mounted {
+			t.Error("Expected the database TLS Secret to be mounted")
+		}
+	})
+}
+
+func TestGenerateSpireServerStatefulSetWithDatastoreRestore(t *testing.T) {
+	spec := newBackupTestSpec("sqlite3", "/run/spire/data/datastore.sqlite3")
+
+	sts := GenerateSpireServerStatefulSet(spec, "test-hash", "test-hash")
+	if len(sts.Spec.Template.Spec.InitContainers) != 0 {
+		t.Errorf("Expected no restore init container without restoreFrom, got %d init containers", len(sts.Spec.Template.Spec.InitContainers))
+	}
+
+	spec.Backup.RestoreFrom = "datastore-20260102030405.sqlite3"
+	sts = GenerateSpireServerStatefulSet(spec, "test-hash", "test-hash")
+	initContainers := sts.Spec.Template.Spec.InitContainers
+	if len(initContainers) != 1 || initContainers[0].Name != "restore-datastore" {
+		t.Fatalf("Expected the restore init container, got %v", initContainers)
+	}
+	if findEnv(initContainers[0].Env, "BACKUP_FILE") != spec.Backup.RestoreFrom {
+		t.Errorf("Expected the backup file to be passed to the restore container, got %v", initContainers[0].Env)
+	}
+	backupMounted := false
+	for _, volume := range sts.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == "spire-backups" && volume.PersistentVolumeClaim.ReadOnly {
+			backupMounted = true
+		}
+	}
+	if !backupMounted {
+		t.Error("Expected the backup volume to be mounted read-only")
+	}
+}
+
+func TestDatastoreBackupCondition(t *testing.T) {
+	scheduled := metav1.NewTime(time.Date(2026, 1, 2, 2, 0, 0, 0, time.UTC))
+	completed := metav1.NewTime(scheduled.Add(time.Minute))
+
+	tests := []struct {
+		name         string
+		status       batchv1.CronJobStatus
+		expectReason string
+		expectStatus metav1.ConditionStatus
+	}{
+		{name: "no backup yet", expectReason: "DatastoreBackupScheduled", expectStatus: metav1.ConditionTrue},
+		{
+			name:         "last backup succeeded",
+			status:       batchv1.CronJobStatus{LastScheduleTime: &scheduled, LastSuccessfulTime: &completed},
+			expectReason: "DatastoreBackupSucceeded",
+			expectStatus: metav1.ConditionTrue,
+		},
+		{
+			name:         "first backup running",
+			status:       batchv1.CronJobStatus{LastScheduleTime: &scheduled, Active: []corev1.ObjectReference{{Name: "backup"}}},
+			expectReason: "DatastoreBackupScheduled",
+			expectStatus: metav1.ConditionTrue,
+		},
+		{
+			name:         "last backup failed",
+			status:       batchv1.CronJobStatus{LastScheduleTime: &completed, LastSuccessfulTime: &scheduled},
+			expectReason: "DatastoreBackupFailed",
+			expectStatus: metav1.ConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, _, conditionStatus := datastoreBackupCondition(&batchv1.CronJob{Status: tt.status})
+			if reason != tt.expectReason || conditionStatus != tt.expectStatus {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.expectReason, tt.expectStatus, reason, conditionStatus)
+			}
+		})
+	}
+}
+
+func TestReconcileDatastoreBackup(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, datastoreBackupCronJobName)
+	existingDatabaseCronJob := func(fc *fakes.FakeCustomCtrlClient, databaseType, connectionString, schedule string) {
+		fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if cronJob, ok := obj.(*batchv1.CronJob); ok {
+				*cronJob = *generateDatastoreBackupCronJob(newBackupTestSpec(databaseType, connectionString))
+				cronJob.ResourceVersion = "123"
+				cronJob.Spec.Schedule = schedule
+			}
+			return nil
+		}
+	}
+	existingCronJob := func(fc *fakes.FakeCustomCtrlClient, schedule string) {
+		existingDatabaseCronJob(fc, "sqlite3", "/run/spire/data/datastore.sqlite3", schedule)
+	}
+
+	tests := []struct {
+		name         string
+		backup       bool
+		postgres     bool
+		setupClient  func(*fakes.FakeCustomCtrlClient)
+		expectCreate int
+		expectUpdate int
+		expectDelete int
+		expectApply  int
+	}{
+		{
+			name:         "create when not found",
+			backup:       true,
+			setupClient:  func(fc *fakes.FakeCustomCtrlClient) { fc.GetReturns(notFound) },
+			expectCreate: 1,
+		},
+		{
+			name:        "up to date",
+			backup:      true,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) { existingCronJob(fc, "0 2 * * *") },
+		},
+		{
+			name:         "update when the schedule differs",
+			backup:       true,
+			setupClient:  func(fc *fakes.FakeCustomCtrlClient) { existingCronJob(fc, "0 3 * * *") },
+			expectUpdate: 1,
+		},
+		{
+			name:         "disabled deletes existing and the credentials Secret",
+			setupClient:  func(fc *fakes.FakeCustomCtrlClient) { existingCronJob(fc, "0 2 * * *") },
+			expectDelete: 2,
+		},
+		{
+			name:         "postgres applies the credentials Secret",
+			backup:       true,
+			postgres:     true,
+			setupClient:  func(fc *fakes.FakeCustomCtrlClient) { fc.GetReturns(notFound) },
+			expectCreate: 1,
+			expectApply:  1,
+		},
+		{
+			name:   "sqlite3 deletes the credentials Secret of postgres",
+			backup: true,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingDatabaseCronJob(fc, "postgres", "dbname=spire host=db", "0 2 * * *")
+			},
+			expectUpdate: 1,
+			expectDelete: 1,
+		},
+		{
+			name:        "disabled and absent",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) { fc.GetReturns(notFound) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			spec := newBackupTestSpec("sqlite3", "/run/spire/data/datastore.sqlite3")
+			if tt.postgres {
+				spec = newBackupTestSpec("postgres", "dbname=spire host=db")
+			}
+			if !tt.backup {
+				spec.Backup = nil
+			}
+			server := &v1alpha1.SpireServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+				Spec:       *spec,
+			}
+
+			if err := reconciler.reconcileDatastoreBackup(context.Background(), server, status.NewManager(fakeClient), false); err != nil {
+				t.Fatalf("reconcileDatastoreBackup() error = %v", err)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+			if fakeClient.ApplyCallCount() != tt.expectApply {
+				t.Errorf("Expected %d Apply calls, got %d", tt.expectApply, fakeClient.ApplyCallCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// bundleDistributionLabelKey marks the copies of the trust bundle ConfigMap distributed to the
+// selected namespaces, so that the copies left in namespaces no longer selected can be pruned
+const bundleDistributionLabelKey = "ztwim.openshift.io/distributed-bundle"
+
+// namespaceLabelsChangedPredicate passes the namespaces that are created or relabeled, which can
+// change the set of namespaces the trust bundle is distributed to
+var namespaceLabelsChangedPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// validateBundleDistribution validates the namespace selector of the bundle distribution
+func validateBundleDistribution(config *v1alpha1.BundleDistributionConfig) error {
+	if config == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(config.NamespaceSelector); err != nil {
+		return fmt.Errorf("bundleDistribution.namespaceSelector: %w", err)
+	}
+	return nil
+}
+
+// getDistributedBundleConfigMapName returns the name of the trust bundle copies in the selected namespaces
+func getDistributedBundleConfigMapName(config *v1alpha1.BundleDistributionConfig, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) string {
+	if config.ConfigMapName != "" {
+		return config.ConfigMapName
+	}
+	return ztwim.Spec.BundleConfigMap
+}
+
+// generateDistributedBundleConfigMap generates the copy of the trust bundle ConfigMap in namespace
+func generateDistributedBundleConfigMap(config *v1alpha1.SpireServerSpec, name, namespace string, data map[string]string) *corev1.ConfigMap {
+	labels := utils.SpireServerLabels(config.Labels)
+	labels[bundleDistributionLabelKey] = "true"
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: maps.Clone(data),
+	}
+}
+
+// bundleDistributionNamespaces returns the sorted names of the namespaces the trust bundle is distributed to.
+// The operand namespace holds the source ConfigMap and is never a target, and namespaces that are
+// being deleted or not watched by the operator are skipped.
+func (r *SpireServerReconciler) bundleDistributionNamespaces(ctx context.Context, config *v1alpha1.BundleDistributionConfig) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(config.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	// A nil selector selects nothing, while an unset namespaceSelector selects all namespaces
+	if config.NamespaceSelector == nil {
+		selector = labels.Everything()
+	}
+
+	var namespaceList corev1.NamespaceList
+	if err := r.ctrlClient.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var namespaces []string
+	for _, namespace := range namespaceList.Items {
+		if namespace.Name == utils.GetOperandNamespace() ||
+			namespace.Status.Phase == corev1.NamespaceTerminating ||
+			!utils.IsWatchedNamespace(namespace.Name) {
+			continue
+		}
+		namespaces = append(namespaces, namespace.Name)
+	}
+	slices.Sort(namespaces)
+	return namespaces, nil
+}
+
+// reconcileBundleDistribution copies the trust bundle ConfigMap published by the SPIRE server into the
+// namespaces selected by spec.bundleDistribution. The source ConfigMap is watched, so the copies are
+// updated when the bundle rotates. Copies in namespaces that are no longer selected are deleted, and
+// the copies are garbage collected with the SpireServer through their owner reference.
+func (r *SpireServerReconciler) reconcileBundleDistribution(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) error {
+	config := server.Spec.BundleDistribution
+	if config == nil {
+		// Distribution disabled - remove the copies of a previous configuration, don't set status
+		return r.pruneDistributedBundles(ctx, "", nil)
+	}
+
+	var source corev1.ConfigMap
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: ztwim.Spec.BundleConfigMap, Namespace: utils.GetOperandNamespace()}, &source)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get the trust bundle ConfigMap")
+		statusMgr.AddCondition(BundleDistributionAvailable, "BundleDistributionFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err != nil || len(source.Data) == 0 {
+		r.log.Info("Waiting for the SPIRE server to publish the trust bundle before distributing it")
+		statusMgr.AddCondition(BundleDistributionAvailable, "TrustBundleNotPublished",
+			"Waiting for the SPIRE server to publish the trust bundle",
+			metav1.ConditionFalse)
+		return nil
+	}
+
+	namespaces, err := r.bundleDistributionNamespaces(ctx, config)
+	if err != nil {
+		r.log.Error(err, "failed to select the trust bundle distribution namespaces")
+		statusMgr.AddCondition(BundleDistributionAvailable, "BundleDistributionFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	name := getDistributedBundleConfigMapName(config, ztwim)
+	var skipped []string
+	for _, namespace := range namespaces {
+		distributed, err := r.reconcileDistributedBundle(ctx, server, name, namespace, source.Data, statusMgr, createOnlyMode)
+		if err != nil {
+			r.log.Error(err, "failed to distribute the trust bundle", "namespace", namespace)
+			statusMgr.AddCondition(BundleDistributionAvailable, "BundleDistributionFailed",
+				err.Error(),
+				metav1.ConditionFalse)
+			return err
+		}
+		if !distributed {
+			skipped = append(skipped, namespace)
+		}
+	}
+
+	if err := r.pruneDistributedBundles(ctx, name, namespaces); err != nil {
+		statusMgr.AddCondition(BundleDistributionAvailable, "BundleDistributionFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	message := fmt.Sprintf("Trust bundle distributed to %d namespace(s)", len(namespaces)-len(skipped))
+	if len(skipped) > 0 {
+		message += fmt.Sprintf(", skipped the namespaces with a %s ConfigMap not managed by the operator: %s",
+			name, strings.Join(skipped, ", "))
+	}
+	statusMgr.AddCondition(BundleDistributionAvailable, "TrustBundleDistributed",
+		message,
+		metav1.ConditionTrue)
+	return nil
+}
+
+// reconcileDistributedBundle creates or updates the copy of the trust bundle in namespace.
+// It returns false when the namespace already holds a ConfigMap of that name which is not
+// managed by the operator, which is left untouched.
+func (r *SpireServerReconciler) reconcileDistributedBundle(ctx context.Context, server *v1alpha1.SpireServer, name, namespace string, data map[string]string, statusMgr *status.Manager, createOnlyMode bool) (bool, error) {
+	desired := generateDistributedBundleConfigMap(&server.Spec, name, namespace, data)
+	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
+		return false, fmt.Errorf("failed to set controller reference on the trust bundle ConfigMap: %w", err)
+	}
+
+	// Only the ConfigMaps managed by the operator are cached, so a foreign ConfigMap is reported
+	// as not found and only detected when creating the copy fails
+	var existing corev1.ConfigMap
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &existing)
+	if err != nil && kerrors.IsNotFound(err) {
+		if err = r.ctrlClient.Create(ctx, desired); err != nil {
+			if kerrors.IsAlreadyExists(err) {
+				r.log.Info("Skipping trust bundle distribution to a ConfigMap not managed by the operator", "namespace", namespace, "name", name)
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to create the trust bundle ConfigMap %s/%s: %w", namespace, name, err)
+		}
+		r.log.Info("Created trust bundle ConfigMap", "namespace", namespace, "name", name)
+		statusMgr.RecordResourceCreated(desired)
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get the trust bundle ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Data, desired.Data) && equality.Semantic.DeepEqual(existing.Labels, desired.Labels) {
+		return true, nil
+	}
+	if createOnlyMode {
+		r.log.Info("Skipping trust bundle ConfigMap update due to create-only mode", "namespace", namespace, "name", name)
+		return true, nil
+	}
+	desired.ResourceVersion = existing.ResourceVersion
+	if err := r.ctrlClient.Update(ctx, desired); err != nil {
+		return false, fmt.Errorf("failed to update the trust bundle ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	r.log.Info("Updated trust bundle ConfigMap", "namespace", namespace, "name", name)
+	statusMgr.RecordDriftRepaired(desired)
+	return true, nil
+}
+
+// pruneDistributedBundles deletes the copies of the trust bundle that are not named name or live
+// outside of the given namespaces
+func (r *SpireServerReconciler) pruneDistributedBundles(ctx context.Context, name string, namespaces []string) error {
+	var configMaps corev1.ConfigMapList
+	if err := r.ctrlClient.List(ctx, &configMaps, client.MatchingLabels{bundleDistributionLabelKey: "true"}); err != nil {
+		return fmt.Errorf("failed to list the distributed trust bundle ConfigMaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if configMap.Name == name && slices.Contains(namespaces, configMap.Namespace) {
+			continue
+		}
+		if err := utils.DeleteObjects(ctx, r.ctrlClient, configMap); err != nil {
+			r.log.Error(err, "failed to delete the distributed trust bundle ConfigMap", "namespace", configMap.Namespace, "name", configMap.Name)
+			return err
+		}
+		r.log.Info("Deleted trust bundle ConfigMap from a namespace no longer selected", "namespace", configMap.Namespace, "name", configMap.Name)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package spire_server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newBundleDistributionTestResources(config *v1alpha1.BundleDistributionConfig) (*v1alpha1.SpireServer, *v1alpha1.ZeroTrustWorkloadIdentityManager) {
+	server := createTestSpireServer()
+	server.Spec.BundleDistribution = config
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+	return server, ztwim
+}
+
+// stubBundleDistributionClient serves the trust bundle source ConfigMap with sourceData, the given
+// namespaces and distributed copies, and reports every other ConfigMap as not found
+func stubBundleDistributionClient(fakeClient *fakes.FakeCustomCtrlClient, sourceData map[string]string, namespaces []corev1.Namespace, copies []corev1.ConfigMap) {
+	fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Namespace == utils.GetOperandNamespace() && key.Name == "spire-bundle" && sourceData != nil {
+			obj.(*corev1.ConfigMap).Data = sourceData
+			return nil
+		}
+		for _, cm := range copies {
+			if cm.Namespace == key.Namespace && cm.Name == key.Name {
+				cm.DeepCopyInto(obj.(*corev1.ConfigMap))
+				return nil
+			}
+		}
+		return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+	}
+	fakeClient.ListStub = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		switch l := list.(type) {
+		case *corev1.NamespaceList:
+			l.Items = namespaces
+		case *corev1.ConfigMapList:
+			l.Items = copies
+		}
+		return nil
+	}
+}
+
+func TestReconcileBundleDistribution(t *testing.T) {
+	bundleData := map[string]string{"bundle.crt": "-----BEGIN CERTIFICATE-----"}
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: utils.GetOperandNamespace()}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}},
+	}
+
+	t.Run("copies the trust bundle into the selected namespaces", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(&v1alpha1.BundleDistributionConfig{})
+		stubBundleDistributionClient(fakeClient, bundleData, namespaces, nil)
+
+		statusMgr := status.NewManager(fakeClient)
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, statusMgr, ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if fakeClient.CreateCallCount() != 1 {
+			t.Fatalf("Expected 1 Create call, got %d", fakeClient.CreateCallCount())
+		}
+		_, obj, _ := fakeClient.CreateArgsForCall(0)
+		cm := obj.(*corev1.ConfigMap)
+		if cm.Namespace != "team-a" || cm.Name != "spire-bundle" {
+			t.Errorf("Expected ConfigMap team-a/spire-bundle, got %s/%s", cm.Namespace, cm.Name)
+		}
+		if cm.Data["bundle.crt"] != bundleData["bundle.crt"] {
+			t.Errorf("Expected the trust bundle to be copied, got %v", cm.Data)
+		}
+		if cm.Labels[bundleDistributionLabelKey] != "true" {
+			t.Errorf("Expected label %s on the copy", bundleDistributionLabelKey)
+		}
+		if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].Name != server.Name {
+			t.Errorf("Expected the copy to be owned by the SpireServer, got %v", cm.OwnerReferences)
+		}
+	})
+
+	t.Run("uses the configured ConfigMap name", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(&v1alpha1.BundleDistributionConfig{ConfigMapName: "spiffe-trust-bundle"})
+		stubBundleDistributionClient(fakeClient, bundleData, namespaces[:1], nil)
+
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, status.NewManager(fakeClient), ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		_, obj, _ := fakeClient.CreateArgsForCall(0)
+		if obj.GetName() != "spiffe-trust-bundle" {
+			t.Errorf("Expected ConfigMap spiffe-trust-bundle, got %s", obj.GetName())
+		}
+	})
+
+	t.Run("waits for the SPIRE server to publish the trust bundle", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(&v1alpha1.BundleDistributionConfig{})
+		stubBundleDistributionClient(fakeClient, nil, namespaces, nil)
+
+		statusMgr := status.NewManager(fakeClient)
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, statusMgr, ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.CreateCallCount() != 0 {
+			t.Errorf("Expected no Create call, got %d", fakeClient.CreateCallCount())
+		}
+
+		if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+			return &server.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		cond := apimeta.FindStatusCondition(server.Status.Conditions, BundleDistributionAvailable)
+		if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "TrustBundleNotPublished" {
+			t.Errorf("Expected BundleDistributionAvailable False with reason TrustBundleNotPublished, got %+v", cond)
+		}
+	})
+
+	t.Run("updates the copies when the trust bundle rotates", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(&v1alpha1.BundleDistributionConfig{})
+		existing := *generateDistributedBundleConfigMap(&server.Spec, "spire-bundle", "team-a", map[string]string{"bundle.crt": "old"})
+		stubBundleDistributionClient(fakeClient, bundleData, namespaces, []corev1.ConfigMap{existing})
+
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, status.NewManager(fakeClient), ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.UpdateCallCount() != 1 {
+			t.Fatalf("Expected 1 Update call, got %d", fakeClient.UpdateCallCount())
+		}
+		_, obj, _ := fakeClient.UpdateArgsForCall(0)
+		if obj.(*corev1.ConfigMap).Data["bundle.crt"] != bundleData["bundle.crt"] {
+			t.Errorf("Expected the rotated trust bundle, got %v", obj.(*corev1.ConfigMap).Data)
+		}
+		if fakeClient.DeleteCallCount() != 0 {
+			t.Errorf("Expected no Delete call, got %d", fakeClient.DeleteCallCount())
+		}
+	})
+
+	t.Run("skips ConfigMaps not managed by the operator", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(&v1alpha1.BundleDistributionConfig{})
+		stubBundleDistributionClient(fakeClient, bundleData, namespaces, nil)
+		fakeClient.CreateReturns(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, "spire-bundle"))
+
+		statusMgr := status.NewManager(fakeClient)
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, statusMgr, ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.UpdateCallCount() != 0 {
+			t.Errorf("Expected no Update call, got %d", fakeClient.UpdateCallCount())
+		}
+	})
+
+	t.Run("prunes the copies in namespaces that are no longer selected", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(&v1alpha1.BundleDistributionConfig{})
+		stale := *generateDistributedBundleConfigMap(&server.Spec, "spire-bundle", "team-c", bundleData)
+		stubBundleDistributionClient(fakeClient, bundleData, namespaces, []corev1.ConfigMap{stale})
+
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, status.NewManager(fakeClient), ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.DeleteCallCount() != 1 {
+			t.Fatalf("Expected 1 Delete call, got %d", fakeClient.DeleteCallCount())
+		}
+		_, obj, _ := fakeClient.DeleteArgsForCall(0)
+		if obj.GetNamespace() != "team-c" {
+			t.Errorf("Expected the copy in team-c to be deleted, got %s", obj.GetNamespace())
+		}
+	})
+
+	t.Run("prunes all copies when distribution is disabled", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server, ztwim := newBundleDistributionTestResources(nil)
+		existing := *generateDistributedBundleConfigMap(&server.Spec, "spire-bundle", "team-a", bundleData)
+		stubBundleDistributionClient(fakeClient, bundleData, namespaces, []corev1.ConfigMap{existing})
+
+		if err := reconciler.reconcileBundleDistribution(context.Background(), server, status.NewManager(fakeClient), ztwim, false); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.CreateCallCount() != 0 {
+			t.Errorf("Expected no Create call, got %d", fakeClient.CreateCallCount())
+		}
+		if fakeClient.DeleteCallCount() != 1 {
+			t.Errorf("Expected 1 Delete call, got %d", fakeClient.DeleteCallCount())
+		}
+	})
+}
+
+func TestValidateBundleDistribution(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *v1alpha1.BundleDistributionConfig
+		expectError bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:   "all namespaces",
+			config: &v1alpha1.BundleDistributionConfig{},
+		},
+		{
+			name: "valid selector",
+			config: &v1alpha1.BundleDistributionConfig{NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"spiffe.io/trust-bundle": "enabled"},
+			}},
+		},
+		{
+			name: "invalid selector operator",
+			config: &v1alpha1.BundleDistributionConfig{NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Matches"}},
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBundleDistribution(tt.config)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// GCPBundlePublisherCredentialsMountPath is where the service account key of the
+	// gcp_cloudstorage BundlePublisher is mounted into the spire-server container
+	GCPBundlePublisherCredentialsMountPath = "/run/spire/bundle-publisher/gcp"
+
+	// gcpServiceAccountKey is the key of the Secret holding the GCP service account key
+	gcpServiceAccountKey = "service_account.json"
+
+	// defaultBundlePublisherConfigMapKey is the key of the published ConfigMaps holding the bundle
+	defaultBundlePublisherConfigMapKey = "bundle"
+)
+
+// operandConfigMapNames are the ConfigMaps of the operand namespace the trust bundle must not be published to
+var operandConfigMapNames = []string{
+	"spire-server",
+	"spire-agent",
+	"spire-controller-manager",
+	"spire-spiffe-oidc-discovery-provider",
+}
+
+// bundlePublishFormat returns format, defaulting to the SPIFFE bundle format
+func bundlePublishFormat(format v1alpha1.BundlePublishFormat) string {
+	if format == "" {
+		return string(v1alpha1.BundlePublishFormatSPIFFE)
+	}
+	return string(format)
+}
+
+// bundlePublisherConfigMapKey returns the key of the ConfigMap the bundle is published under
+func bundlePublisherConfigMapKey(configMap v1alpha1.ConfigMapBundlePublisherConfig) string {
+	if configMap.Key == "" {
+		return defaultBundlePublisherConfigMapKey
+	}
+	return configMap.Key
+}
+
+// validateBundlePublishers checks that the trust bundle is not published to the ConfigMaps of the operands
+func validateBundlePublishers(config *v1alpha1.BundlePublishersConfig) error {
+	if config == nil {
+		return nil
+	}
+	for i, configMap := range config.ConfigMaps {
+		if slices.Contains(operandConfigMapNames, configMap.Name) {
+			return fmt.Errorf("bundlePublishers.configMaps[%d]: ConfigMap %s is managed by the operator", i, configMap.Name)
+		}
+	}
+	return nil
+}
+
+// generateBundlePublisherPlugins generates the BundlePublisher plugins of the SPIRE server
+func generateBundlePublisherPlugins(config *v1alpha1.BundlePublishersConfig) []map[string]interface{} {
+	var plugins []map[string]interface{}
+
+	if s3 := config.AWSS3; s3 != nil {
+		pluginData := map[string]interface{}{
+			"region":     s3.Region,
+			"bucket":     s3.Bucket,
+			"object_key": s3.ObjectKey,
+			"format":     bundlePublishFormat(s3.Format),
+		}
+		if s3.Endpoint != "" {
+			pluginData["endpoint"] = s3.Endpoint
+		}
+		plugins = append(plugins, map[string]interface{}{
+			"aws_s3": map[string]interface{}{"plugin_data": pluginData},
+		})
+	}
+
+	if gcs := config.GCPCloudStorage; gcs != nil {
+		pluginData := map[string]interface{}{
+			"bucket_name": gcs.BucketName,
+			"object_name": gcs.ObjectName,
+			"format":      bundlePublishFormat(gcs.Format),
+		}
+		if gcs.CredentialsSecretName != "" {
+			pluginData["service_account_file"] = path.Join(GCPBundlePublisherCredentialsMountPath, gcpServiceAccountKey)
+		}
+		plugins = append(plugins, map[string]interface{}{
+			"gcp_cloudstorage": map[string]interface{}{"plugin_data": pluginData},
+		})
+	}
+
+	if len(config.ConfigMaps) > 0 {
+		// The plugin publishes to one ConfigMap per cluster, the ConfigMaps are all in the local cluster
+		clusters := make(map[string]interface{}, len(config.ConfigMaps))
+		for _, configMap := range config.ConfigMaps {
+			clusters[configMap.Name] = map[string]interface{}{
+				"configmap_name": configMap.Name,
+				"configmap_key":  bundlePublisherConfigMapKey(configMap),
+				"namespace":      utils.GetOperandNamespace(),
+				"format":         bundlePublishFormat(configMap.Format),
+			}
+		}
+		plugins = append(plugins, map[string]interface{}{
+			"k8s_configmap": map[string]interface{}{
+				"plugin_data": map[string]interface{}{"clusters": clusters},
+			},
+		})
+	}
+
+	return plugins
+}
+
+// addBundlePublishersToStatefulSet exposes the credentials of the bundle publishers to the spire-server container
+func addBundlePublishersToStatefulSet(sts *appsv1.StatefulSet, config *v1alpha1.BundlePublishersConfig) {
+	container := &sts.Spec.Template.Spec.Containers[0]
+
+	// The AWS SDK of the aws_s3 plugin reads the credentials from the environment
+	if config.AWSS3 != nil && config.AWSS3.CredentialsSecretName != "" {
+		secretName := config.AWSS3.CredentialsSecretName
+		for _, env := range []struct{ name, key string }{
+			{name: "AWS_ACCESS_KEY_ID", key: "aws_access_key_id"},
+			{name: "AWS_SECRET_ACCESS_KEY", key: "aws_secret_access_key"},
+		} {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name: env.name,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+						Key:                  env.key,
+					},
+				},
+			})
+		}
+	}
+
+	if config.GCPCloudStorage != nil && config.GCPCloudStorage.CredentialsSecretName != "" {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "bundle-publisher-gcp",
+			MountPath: GCPBundlePublisherCredentialsMountPath,
+			ReadOnly:  true,
+		})
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "bundle-publisher-gcp",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: config.GCPCloudStorage.CredentialsSecretName,
+					Items:      []corev1.KeyToPath{{Key: gcpServiceAccountKey, Path: gcpServiceAccountKey}},
+				},
+			},
+		})
+	}
+}
+
+// bundlePublisherConfigMapNames returns the names of the ConfigMaps the trust bundle is published to
+func bundlePublisherConfigMapNames(config *v1alpha1.BundlePublishersConfig) []string {
+	if config == nil {
+		return nil
+	}
+	names := make([]string, 0, len(config.ConfigMaps))
+	for _, configMap := range config.ConfigMaps {
+		names = append(names, configMap.Name)
+	}
+	return names
+}
+
+// addBundlePublisherConfigMapsToRole grants the SPIRE server the update of the ConfigMaps it publishes the bundle to
+func addBundlePublisherConfigMapsToRole(role *rbacv1.Role, config *v1alpha1.BundlePublishersConfig) {
+	names := bundlePublisherConfigMapNames(config)
+	if len(names) == 0 {
+		return
+	}
+	role.Rules = append(role.Rules, rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		ResourceNames: names,
+		Verbs:         []string{"get", "patch", "update"},
+	})
+}
+
+// reconcileBundlePublisherConfigMaps creates the ConfigMaps the trust bundle is published to. Like the
+// bundle ConfigMap, they are only created: their content is owned by the SPIRE server.
+func (r *SpireServerReconciler) reconcileBundlePublisherConfigMaps(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	for _, name := range bundlePublisherConfigMapNames(server.Spec.BundlePublishers) {
+		if name == ztwim.Spec.BundleConfigMap {
+			err := fmt.Errorf("bundle publisher ConfigMap %s is the bundle ConfigMap of the ZeroTrustWorkloadIdentityManager", name)
+			r.log.Error(err, "invalid bundle publishers")
+			statusMgr.AddCondition(BundleConfigAvailable, "BundlePublisherConfigMapInvalid",
+				err.Error(),
+				metav1.ConditionFalse)
+			return err
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: utils.GetOperandNamespace(),
+				Labels:    utils.SpireServerLabels(server.Spec.Labels),
+			},
+		}
+		if err := controllerutil.SetControllerReference(server, configMap, r.scheme); err != nil {
+			r.log.Error(err, "failed to set controller reference on bundle publisher config map", "name", name)
+			statusMgr.AddCondition(BundleConfigAvailable, "BundlePublisherConfigMapCreationFailed",
+				err.Error(),
+				metav1.ConditionFalse)
+			return err
+		}
+		statusMgr.TrackResource(configMap)
+
+		if err := r.ctrlClient.Create(ctx, configMap); err != nil {
+			if kerrors.IsAlreadyExists(err) {
+				continue
+			}
+			r.log.Error(err, "failed to create bundle publisher config map", "name", name)
+			statusMgr.AddCondition(BundleConfigAvailable, "BundlePublisherConfigMapCreationFailed",
+				err.Error(),
+				metav1.ConditionFalse)
+			return fmt.Errorf("failed to create bundle publisher ConfigMap %s: %w", name, err)
+		}
+		r.log.Info("Created bundle publisher ConfigMap", "name", name)
+		statusMgr.RecordResourceCreated(configMap)
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package spire_server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGenerateBundlePublisherPlugins(t *testing.T) {
+	config := &v1alpha1.BundlePublishersConfig{
+		AWSS3: &v1alpha1.AWSS3BundlePublisherConfig{
+			Region:    "us-east-1",
+			Bucket:    "trust-bundles",
+			ObjectKey: "spire/bundle.json",
+			Endpoint:  "https://s3.example.org",
+		},
+		GCPCloudStorage: &v1alpha1.GCPCloudStorageBundlePublisherConfig{
+			BucketName:            "trust-bundles",
+			ObjectName:            "spire/bundle.pem",
+			Format:                v1alpha1.BundlePublishFormatPEM,
+			CredentialsSecretName: "gcp-credentials",
+		},
+		ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{
+			{Name: "jwks-bundle", Key: "keys.json", Format: v1alpha1.BundlePublishFormatJWKS},
+		},
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"aws_s3": map[string]interface{}{
+				"plugin_data": map[string]interface{}{
+					"region":     "us-east-1",
+					"bucket":     "trust-bundles",
+					"object_key": "spire/bundle.json",
+					"format":     "spiffe",
+					"endpoint":   "https://s3.example.org",
+				},
+			},
+		},
+		{
+			"gcp_cloudstorage": map[string]interface{}{
+				"plugin_data": map[string]interface{}{
+					"bucket_name":          "trust-bundles",
+					"object_name":          "spire/bundle.pem",
+					"format":               "pem",
+					"service_account_file": "/run/spire/bundle-publisher/gcp/service_account.json",
+				},
+			},
+		},
+		{
+			"k8s_configmap": map[string]interface{}{
+				"plugin_data": map[string]interface{}{
+					"clusters": map[string]interface{}{
+						"jwks-bundle": map[string]interface{}{
+							"configmap_name": "jwks-bundle",
+							"configmap_key":  "keys.json",
+							"namespace":      utils.GetOperandNamespace(),
+							"format":         "jwks",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	plugins := generateBundlePublisherPlugins(config)
+	if !reflect.DeepEqual(plugins, expected) {
+		t.Errorf("Expected plugins %v, got %v", expected, plugins)
+	}
+}
+
+func TestGenerateServerConfMapBundlePublishers(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			ClusterName:     "test-cluster",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	server := createTestSpireServer()
+	plugins := generateServerConfMap(&server.Spec, ztwim)["plugins"].(map[string]interface{})
+	if _, ok := plugins["BundlePublisher"]; ok {
+		t.Error("Expected no BundlePublisher plugin without bundle publishers")
+	}
+
+	server.Spec.BundlePublishers = &v1alpha1.BundlePublishersConfig{
+		ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{{Name: "relying-party-bundle"}},
+	}
+	plugins = generateServerConfMap(&server.Spec, ztwim)["plugins"].(map[string]interface{})
+	bundlePublishers, ok := plugins["BundlePublisher"].([]map[string]interface{})
+	if !ok || len(bundlePublishers) != 1 {
+		t.Fatalf("Expected one BundlePublisher plugin, got %v", plugins["BundlePublisher"])
+	}
+	clusters := bundlePublishers[0]["k8s_configmap"].(map[string]interface{})["plugin_data"].(map[string]interface{})["clusters"].(map[string]interface{})
+	cluster := clusters["relying-party-bundle"].(map[string]interface{})
+	if cluster["configmap_key"] != "bundle" || cluster["format"] != "spiffe" {
+		t.Errorf("Expected the default key and format, got %v", cluster)
+	}
+}
+
+func TestAddBundlePublishersToStatefulSet(t *testing.T) {
+	newStatefulSet := func() *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "spire-server"}},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("without credentials", func(t *testing.T) {
+		sts := newStatefulSet()
+		addBundlePublishersToStatefulSet(sts, &v1alpha1.BundlePublishersConfig{
+			AWSS3:           &v1alpha1.AWSS3BundlePublisherConfig{Region: "us-east-1", Bucket: "bundles", ObjectKey: "bundle.json"},
+			GCPCloudStorage: &v1alpha1.GCPCloudStorageBundlePublisherConfig{BucketName: "bundles", ObjectName: "bundle.json"},
+		})
+		container := sts.Spec.Template.Spec.Containers[0]
+		if len(container.Env) != 0 || len(container.VolumeMounts) != 0 || len(sts.Spec.Template.Spec.Volumes) != 0 {
+			t.Errorf("Expected the default credentials to be used, got env %v and volumes %v", container.Env, sts.Spec.Template.Spec.Volumes)
+		}
+	})
+
+	t.Run("with credentials", func(t *testing.T) {
+		sts := newStatefulSet()
+		addBundlePublishersToStatefulSet(sts, &v1alpha1.BundlePublishersConfig{
+			AWSS3: &v1alpha1.AWSS3BundlePublisherConfig{
+				Region: "us-east-1", Bucket: "bundles", ObjectKey: "bundle.json", CredentialsSecretName: "aws-credentials",
+			},
+			GCPCloudStorage: &v1alpha1.GCPCloudStorageBundlePublisherConfig{
+				BucketName: "bundles", ObjectName: "bundle.json", CredentialsSecretName: "gcp-credentials",
+			},
+		})
+		container := sts.Spec.Template.Spec.Containers[0]
+
+		if len(container.Env) != 2 {
+			t.Fatalf("Expected 2 environment variables, got %v", container.Env)
+		}
+		for i, expected := range []struct{ name, key string }{
+			{"AWS_ACCESS_KEY_ID", "aws_access_key_id"},
+			{"AWS_SECRET_ACCESS_KEY", "aws_secret_access_key"},
+		} {
+			env := container.Env[i]
+			if env.Name != expected.name || env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil ||
+				env.ValueFrom.SecretKeyRef.Name != "aws-credentials" || env.ValueFrom.SecretKeyRef.Key != expected.key {
+				t.Errorf("Expected %s from key %s of aws-credentials, got %+v", expected.name, expected.key, env)
+			}
+		}
+
+		if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != GCPBundlePublisherCredentialsMountPath {
+			t.Errorf("Expected the GCP credentials to be mounted, got %v", container.VolumeMounts)
+		}
+		volumes := sts.Spec.Template.Spec.Volumes
+		if len(volumes) != 1 || volumes[0].Secret == nil || volumes[0].Secret.SecretName != "gcp-credentials" {
+			t.Errorf("Expected the GCP credentials Secret volume, got %v", volumes)
+		}
+	})
+}
+
+func TestAddBundlePublisherConfigMapsToRole(t *testing.T) {
+	role := getSpireBundleRole(nil)
+	rules := len(role.Rules)
+
+	addBundlePublisherConfigMapsToRole(role, nil)
+	if len(role.Rules) != rules {
+		t.Fatalf("Expected no rule to be added without bundle publishers, got %v", role.Rules)
+	}
+
+	addBundlePublisherConfigMapsToRole(role, &v1alpha1.BundlePublishersConfig{
+		ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{{Name: "bundle-a"}, {Name: "bundle-b"}},
+	})
+	if len(role.Rules) != rules+1 {
+		t.Fatalf("Expected a rule for the bundle publisher ConfigMaps, got %v", role.Rules)
+	}
+	expected := rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		ResourceNames: []string{"bundle-a", "bundle-b"},
+		Verbs:         []string{"get", "patch", "update"},
+	}
+	if !reflect.DeepEqual(role.Rules[rules], expected) {
+		t.Errorf("Expected rule %v, got %v", expected, role.Rules[rules])
+	}
+}
+
+func TestValidateBundlePublishers(t *testing.T) {
+	if err := validateBundlePublishers(nil); err != nil {
+		t.Errorf("Expected no error without bundle publishers, got %v", err)
+	}
+	if err := validateBundlePublishers(&v1alpha1.BundlePublishersConfig{
+		ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{{Name: "relying-party-bundle"}},
+	}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	err := validateBundlePublishers(&v1alpha1.BundlePublishersConfig{
+		ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{{Name: "relying-party-bundle"}, {Name: "spire-server"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "bundlePublishers.configMaps[1]") {
+		t.Errorf("Expected the operand ConfigMap to be rejected, got %v", err)
+	}
+}
+
+func TestReconcileBundlePublisherConfigMaps(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{BundleConfigMap: "spire-bundle"},
+	}
+
+	t.Run("creates the ConfigMaps", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server := createTestSpireServer()
+		server.Spec.BundlePublishers = &v1alpha1.BundlePublishersConfig{
+			ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{{Name: "bundle-a"}, {Name: "bundle-b"}},
+		}
+		fakeClient.CreateReturnsOnCall(0, kerrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, "bundle-a"))
+
+		statusMgr := status.NewManager(fakeClient)
+		if err := reconciler.reconcileBundlePublisherConfigMaps(context.Background(), server, statusMgr, ztwim); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if fakeClient.CreateCallCount() != 2 {
+			t.Fatalf("Expected 2 Create calls, got %d", fakeClient.CreateCallCount())
+		}
+		_, obj, _ := fakeClient.CreateArgsForCall(1)
+		cm := obj.(*corev1.ConfigMap)
+		if cm.Name != "bundle-b" || cm.Namespace != utils.GetOperandNamespace() {
+			t.Errorf("Expected ConfigMap bundle-b in the operand namespace, got %s/%s", cm.Namespace, cm.Name)
+		}
+		if len(cm.OwnerReferences) != 1 {
+			t.Errorf("Expected the ConfigMap to be owned by the SpireServer, got %v", cm.OwnerReferences)
+		}
+	})
+
+	t.Run("rejects the bundle ConfigMap", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server := createTestSpireServer()
+		server.Spec.BundlePublishers = &v1alpha1.BundlePublishersConfig{
+			ConfigMaps: []v1alpha1.ConfigMapBundlePublisherConfig{{Name: "spire-bundle"}},
+		}
+
+		statusMgr := status.NewManager(fakeClient)
+		if err := reconciler.reconcileBundlePublisherConfigMaps(context.Background(), server, statusMgr, ztwim); err == nil {
+			t.Fatal("Expected an error")
+		}
+		if fakeClient.CreateCallCount() != 0 {
+			t.Errorf("Expected no Create call, got %d", fakeClient.CreateCallCount())
+		}
+	})
+}
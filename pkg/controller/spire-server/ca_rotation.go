@@ -0,0 +1,273 @@
+package spire_server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/inspect"
+)
+
+const (
+	// spireServerContainerName is the container of the SPIRE server pods running the SPIRE server
+	spireServerContainerName = "spire-server"
+
+	// spireServerBinary is the SPIRE server CLI of the SPIRE server image. The local authority API is
+	// only served to admin callers, which the CLI is on the admin socket of the SPIRE server.
+	spireServerBinary = "/opt/spire/bin/spire-server"
+
+	// caRotationRetryInterval is how long a failed rotation step is retried after
+	caRotationRetryInterval = time.Minute
+
+	// defaultCARotationPropagationDelay is waited between the rotation steps when spec.caRotation.propagationDelay is unset
+	defaultCARotationPropagationDelay = 10 * time.Minute
+
+	// minCARotationInterval is the shortest interval between scheduled rotations
+	minCARotationInterval = time.Hour
+)
+
+// authorityKinds are the kinds of authorities rotated, as named by the local authority CLI commands
+var authorityKinds = []string{"x509", "jwt"}
+
+// spireServerCLI runs the SPIRE server CLI with args in a running SPIRE server pod and returns its output
+type spireServerCLI func(ctx context.Context, args ...string) ([]byte, error)
+
+// newSpireServerCLI returns a spireServerCLI running the CLI through the exec subresource of the SPIRE server pods
+func newSpireServerCLI(clientset kubernetes.Interface, executor inspect.Executor) spireServerCLI {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		namespace := utils.GetOperandNamespace()
+		pods, err := runningSpireServerPods(ctx, clientset, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return executor.Exec(ctx, namespace, pods[0], spireServerContainerName, append([]string{spireServerBinary}, args...))
+	}
+}
+
+// runningSpireServerPods returns the names of the running SPIRE server pods in namespace
+func runningSpireServerPods(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]string, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(utils.PodSelectorLabels(utils.SpireServerLabels(nil))).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			names = append(names, pod.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no running SPIRE server pod found in namespace %s", namespace)
+	}
+	return names, nil
+}
+
+// localAuthorityState is the output of the "localauthority <kind> show" command
+type localAuthorityState struct {
+	Active   *localAuthority `json:"active"`
+	Prepared *localAuthority `json:"prepared"`
+	Old      *localAuthority `json:"old"`
+}
+
+// localAuthority is an authority in the output of the "localauthority <kind> show" command
+type localAuthority struct {
+	AuthorityID string `json:"authority_id"`
+}
+
+// caRotationPropagationDelay returns the delay waited between the rotation steps
+func caRotationPropagationDelay(config *v1alpha1.CARotationConfig) time.Duration {
+	if config.PropagationDelay.Duration > 0 {
+		return config.PropagationDelay.Duration
+	}
+	return defaultCARotationPropagationDelay
+}
+
+// validateCARotation validates the schedule of the rotations of the SPIRE server authorities
+func validateCARotation(config *v1alpha1.CARotationConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.PropagationDelay.Duration < 0 {
+		return fmt.Errorf("caRotation.propagationDelay must not be negative")
+	}
+	if interval := config.Interval.Duration; interval != 0 {
+		if interval < minCARotationInterval {
+			return fmt.Errorf("caRotation.interval %s must be at least %s", interval, minCARotationInterval)
+		}
+		if delay := caRotationPropagationDelay(config); interval <= 2*delay {
+			return fmt.Errorf("caRotation.interval %s must be longer than twice the propagation delay %s", interval, delay)
+		}
+	}
+	return nil
+}
+
+// caRotationDue returns whether a rotation must be started, and otherwise when the next scheduled rotation is due
+func caRotationDue(server *v1alpha1.SpireServer, now time.Time) (bool, time.Duration) {
+	config := server.Spec.CARotation
+	rotation := server.Status.CARotation
+	if rotation == nil {
+		rotation = &v1alpha1.CARotationStatus{}
+	}
+	if config.Request != "" && config.Request != rotation.Request {
+		return true, 0
+	}
+	if config.Interval.Duration == 0 {
+		return false, 0
+	}
+	last := server.CreationTimestamp.Time
+	if rotation.LastCompletionTime != nil {
+		last = rotation.LastCompletionTime.Time
+	}
+	if due := last.Add(config.Interval.Duration).Sub(now); due > 0 {
+		return false, due
+	}
+	return true, 0
+}
+
+// showLocalAuthorities returns the state of the authorities of kind
+func (r *SpireServerReconciler) showLocalAuthorities(ctx context.Context, kind string) (*localAuthorityState, error) {
+	output, err := r.spireServerCLI(ctx, "localauthority", kind, "show", "-output", "json")
+	if err != nil {
+		return nil, err
+	}
+	state := &localAuthorityState{}
+	if err := json.Unmarshal(output, state); err != nil {
+		return nil, fmt.Errorf("failed to decode the %s authorities: %w", kind, err)
+	}
+	return state, nil
+}
+
+// prepareAuthorities prepares new X.509 and JWT authorities
+func (r *SpireServerReconciler) prepareAuthorities(ctx context.Context) error {
+	for _, kind := range authorityKinds {
+		if _, err := r.spireServerCLI(ctx, "localauthority", kind, "prepare", "-output", "json"); err != nil {
+			return fmt.Errorf("failed to prepare the %s authority: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// activateAuthorities activates the prepared X.509 and JWT authorities. An authority already activated,
+// e.g. when the previous attempt failed on the other kind, is not activated again.
+func (r *SpireServerReconciler) activateAuthorities(ctx context.Context) error {
+	for _, kind := range authorityKinds {
+		state, err := r.showLocalAuthorities(ctx, kind)
+		if err != nil {
+			return err
+		}
+		if state.Prepared == nil || state.Prepared.AuthorityID == "" {
+			continue
+		}
+		if _, err := r.spireServerCLI(ctx, "localauthority", kind, "activate", "-authorityID", state.Prepared.AuthorityID, "-output", "json"); err != nil {
+			return fmt.Errorf("failed to activate the %s authority %s: %w", kind, state.Prepared.AuthorityID, err)
+		}
+	}
+	return nil
+}
+
+// taintAuthorities taints the previous X.509 and JWT authorities, so that the agents and the workloads
+// replace the SVIDs signed with them
+func (r *SpireServerReconciler) taintAuthorities(ctx context.Context) error {
+	for _, kind := range authorityKinds {
+		state, err := r.showLocalAuthorities(ctx, kind)
+		if err != nil {
+			return err
+		}
+		if state.Old == nil || state.Old.AuthorityID == "" {
+			continue
+		}
+		if _, err := r.spireServerCLI(ctx, "localauthority", kind, "taint", "-authorityID", state.Old.AuthorityID, "-output", "json"); err != nil {
+			return fmt.Errorf("failed to taint the %s authority %s: %w", kind, state.Old.AuthorityID, err)
+		}
+	}
+	return nil
+}
+
+// reconcileCARotation drives the rotation of the SPIRE server authorities configured in spec.caRotation:
+// the new authorities are prepared, activated after the propagation delay, and the previous authorities
+// tainted after the propagation delay. Each step is recorded in status.caRotation so that the rotation
+// resumes across reconciles, and reported in the CARotationProgressing condition. It returns when the
+// next step or the next scheduled rotation is due, zero when none is.
+func (r *SpireServerReconciler) reconcileCARotation(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager) time.Duration {
+	config := server.Spec.CARotation
+	if config == nil {
+		return 0
+	}
+	if utils.IsDryRunMode(server.Spec.ReconcileMode) {
+		r.log.Info("Skipping the CA rotation in dry-run mode")
+		return 0
+	}
+
+	now := metav1.Now()
+	rotation := server.Status.CARotation
+	if rotation == nil {
+		rotation = &v1alpha1.CARotationStatus{}
+	}
+	delay := caRotationPropagationDelay(config)
+
+	var step func(context.Context) error
+	var next v1alpha1.CARotationPhase
+	var reason, message string
+	switch rotation.Phase {
+	case v1alpha1.CARotationPrepared, v1alpha1.CARotationActivated:
+		if wait := rotation.PhaseTransitionTime.Add(delay).Sub(now.Time); wait > 0 {
+			return wait
+		}
+		if rotation.Phase == v1alpha1.CARotationPrepared {
+			step, next = r.activateAuthorities, v1alpha1.CARotationActivated
+			reason, message = "AuthoritiesActivated", "The new authorities are activated, the previous authorities are tainted next"
+		} else {
+			step, next = r.taintAuthorities, v1alpha1.CARotationCompleted
+			reason, message = "RotationCompleted", "The authorities were rotated"
+		}
+	default:
+		due, wait := caRotationDue(server, now.Time)
+		if !due {
+			return wait
+		}
+		step, next = r.prepareAuthorities, v1alpha1.CARotationPrepared
+		reason, message = "AuthoritiesPrepared", "The new authorities are prepared, they are activated next"
+	}
+
+	if err := step(ctx); err != nil {
+		r.log.Error(err, "failed to rotate the SPIRE server authorities", "phase", rotation.Phase)
+		statusMgr.AddCondition(utils.CARotationProgressingStatusType, "RotationStepFailed",
+			fmt.Sprintf("Failed to rotate the authorities: %v", err),
+			metav1.ConditionTrue)
+		return caRotationRetryInterval
+	}
+
+	updated := rotation.DeepCopy()
+	updated.Phase = next
+	updated.PhaseTransitionTime = &now
+	if next == v1alpha1.CARotationPrepared {
+		updated.Request = config.Request
+	}
+	if next == v1alpha1.CARotationCompleted {
+		updated.LastCompletionTime = &now
+		statusMgr.AddCondition(utils.CARotationProgressingStatusType, reason, message, metav1.ConditionFalse)
+	} else {
+		statusMgr.AddCondition(utils.CARotationProgressingStatusType, reason, message, metav1.ConditionTrue)
+	}
+	r.log.Info("Rotated the SPIRE server authorities", "phase", next)
+	server.Status.CARotation = updated
+	statusMgr.ForceStatusUpdate()
+
+	if next == v1alpha1.CARotationCompleted {
+		if due, wait := caRotationDue(server, now.Time); !due {
+			return wait
+		}
+	}
+	return delay
+}
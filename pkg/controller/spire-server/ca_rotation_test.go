@@ -0,0 +1,283 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// fakeSpireServerCLI records the SPIRE server CLI commands and serves the local authority state
+type fakeSpireServerCLI struct {
+	commands []string
+	show     string
+	err      error
+}
+
+func (f *fakeSpireServerCLI) run(ctx context.Context, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if strings.Contains(command, " show ") {
+		return []byte(f.show), nil
+	}
+	return []byte(`{}`), nil
+}
+
+func TestValidateCARotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *v1alpha1.CARotationConfig
+		expectedErr string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:   "on demand only",
+			config: &v1alpha1.CARotationConfig{Request: "2026-10-15"},
+		},
+		{
+			name:   "scheduled",
+			config: &v1alpha1.CARotationConfig{Interval: metav1.Duration{Duration: 720 * time.Hour}},
+		},
+		{
+			name:        "interval shorter than the minimum",
+			config:      &v1alpha1.CARotationConfig{Interval: metav1.Duration{Duration: 30 * time.Minute}},
+			expectedErr: "must be at least 1h0m0s",
+		},
+		{
+			name: "interval shorter than the rotation steps",
+			config: &v1alpha1.CARotationConfig{
+				Interval:         metav1.Duration{Duration: 2 * time.Hour},
+				PropagationDelay: metav1.Duration{Duration: time.Hour},
+			},
+			expectedErr: "must be longer than twice the propagation delay",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCARotation(tt.config)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestCARotationDue(t *testing.T) {
+	now := time.Now()
+	completed := metav1.NewTime(now.Add(-10 * time.Hour))
+
+	tests := []struct {
+		name         string
+		config       v1alpha1.CARotationConfig
+		rotation     *v1alpha1.CARotationStatus
+		expectedDue  bool
+		expectedWait time.Duration
+	}{
+		{
+			name:        "new request",
+			config:      v1alpha1.CARotationConfig{Request: "b"},
+			rotation:    &v1alpha1.CARotationStatus{Request: "a"},
+			expectedDue: true,
+		},
+		{
+			name:     "request already served and no schedule",
+			config:   v1alpha1.CARotationConfig{Request: "a"},
+			rotation: &v1alpha1.CARotationStatus{Request: "a"},
+		},
+		{
+			name:         "scheduled rotation not due yet",
+			config:       v1alpha1.CARotationConfig{Interval: metav1.Duration{Duration: 24 * time.Hour}},
+			rotation:     &v1alpha1.CARotationStatus{LastCompletionTime: &completed},
+			expectedWait: 14 * time.Hour,
+		},
+		{
+			name:        "scheduled rotation due",
+			config:      v1alpha1.CARotationConfig{Interval: metav1.Duration{Duration: 5 * time.Hour}},
+			rotation:    &v1alpha1.CARotationStatus{LastCompletionTime: &completed},
+			expectedDue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestSpireServer()
+			server.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+			server.Spec.CARotation = &tt.config
+			server.Status.CARotation = tt.rotation
+
+			due, wait := caRotationDue(server, now)
+			if due != tt.expectedDue {
+				t.Errorf("Expected due %t, got %t", tt.expectedDue, due)
+			}
+			if wait != tt.expectedWait {
+				t.Errorf("Expected the next rotation in %s, got %s", tt.expectedWait, wait)
+			}
+		})
+	}
+}
+
+func TestReconcileCARotation(t *testing.T) {
+	const show = `{"active":{"authority_id":"active-id"},"prepared":{"authority_id":"prepared-id"},"old":{"authority_id":"old-id"}}`
+
+	newServer := func(rotation *v1alpha1.CARotationStatus) *v1alpha1.SpireServer {
+		server := createTestSpireServer()
+		server.Spec.CARotation = &v1alpha1.CARotationConfig{
+			Request:          "2026-10-15",
+			PropagationDelay: metav1.Duration{Duration: 10 * time.Minute},
+		}
+		server.Status.CARotation = rotation
+		return server
+	}
+	applyConditions := func(t *testing.T, server *v1alpha1.SpireServer, statusMgr *status.Manager) {
+		t.Helper()
+		if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+			return &server.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	t.Run("prepares the authorities on a new request", func(t *testing.T) {
+		cli := &fakeSpireServerCLI{show: show}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerCLI = cli.run
+		server := newServer(&v1alpha1.CARotationStatus{Phase: v1alpha1.CARotationCompleted, Request: "2026-01-01"})
+		statusMgr := status.NewManager(&fakes.FakeCustomCtrlClient{})
+
+		if next := reconciler.reconcileCARotation(context.Background(), server, statusMgr); next != 10*time.Minute {
+			t.Errorf("Expected the next step after the propagation delay, got %s", next)
+		}
+		expected := []string{"localauthority x509 prepare -output json", "localauthority jwt prepare -output json"}
+		if strings.Join(cli.commands, ";") != strings.Join(expected, ";") {
+			t.Errorf("Expected commands %v, got %v", expected, cli.commands)
+		}
+		if server.Status.CARotation.Phase != v1alpha1.CARotationPrepared || server.Status.CARotation.Request != "2026-10-15" {
+			t.Errorf("Expected the rotation to be prepared for the request, got %+v", server.Status.CARotation)
+		}
+
+		applyConditions(t, server, statusMgr)
+		cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.CARotationProgressingStatusType)
+		if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "AuthoritiesPrepared" {
+			t.Errorf("Expected CARotationProgressing True with AuthoritiesPrepared, got %+v", cond)
+		}
+	})
+
+	t.Run("waits for the propagation delay before activating", func(t *testing.T) {
+		cli := &fakeSpireServerCLI{show: show}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerCLI = cli.run
+		prepared := metav1.NewTime(time.Now().Add(-4 * time.Minute))
+		server := newServer(&v1alpha1.CARotationStatus{Phase: v1alpha1.CARotationPrepared, PhaseTransitionTime: &prepared, Request: "2026-10-15"})
+
+		next := reconciler.reconcileCARotation(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}))
+		if len(cli.commands) != 0 {
+			t.Errorf("Expected no command before the propagation delay, got %v", cli.commands)
+		}
+		if next <= 0 || next > 6*time.Minute {
+			t.Errorf("Expected the next step when the propagation delay elapses, got %s", next)
+		}
+	})
+
+	t.Run("activates the prepared authorities", func(t *testing.T) {
+		cli := &fakeSpireServerCLI{show: show}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerCLI = cli.run
+		prepared := metav1.NewTime(time.Now().Add(-11 * time.Minute))
+		server := newServer(&v1alpha1.CARotationStatus{Phase: v1alpha1.CARotationPrepared, PhaseTransitionTime: &prepared, Request: "2026-10-15"})
+
+		reconciler.reconcileCARotation(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}))
+		expected := []string{
+			"localauthority x509 show -output json", "localauthority x509 activate -authorityID prepared-id -output json",
+			"localauthority jwt show -output json", "localauthority jwt activate -authorityID prepared-id -output json",
+		}
+		if strings.Join(cli.commands, ";") != strings.Join(expected, ";") {
+			t.Errorf("Expected commands %v, got %v", expected, cli.commands)
+		}
+		if server.Status.CARotation.Phase != v1alpha1.CARotationActivated {
+			t.Errorf("Expected the rotation to be activated, got %+v", server.Status.CARotation)
+		}
+	})
+
+	t.Run("taints the previous authorities and completes", func(t *testing.T) {
+		cli := &fakeSpireServerCLI{show: show}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerCLI = cli.run
+		activated := metav1.NewTime(time.Now().Add(-11 * time.Minute))
+		server := newServer(&v1alpha1.CARotationStatus{Phase: v1alpha1.CARotationActivated, PhaseTransitionTime: &activated, Request: "2026-10-15"})
+		statusMgr := status.NewManager(&fakes.FakeCustomCtrlClient{})
+
+		if next := reconciler.reconcileCARotation(context.Background(), server, statusMgr); next != 0 {
+			t.Errorf("Expected no next step without a schedule, got %s", next)
+		}
+		if !strings.Contains(strings.Join(cli.commands, ";"), "localauthority jwt taint -authorityID old-id") {
+			t.Errorf("Expected the previous JWT authority to be tainted, got %v", cli.commands)
+		}
+		if server.Status.CARotation.Phase != v1alpha1.CARotationCompleted || server.Status.CARotation.LastCompletionTime == nil {
+			t.Errorf("Expected the rotation to be completed, got %+v", server.Status.CARotation)
+		}
+
+		applyConditions(t, server, statusMgr)
+		cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.CARotationProgressingStatusType)
+		if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "RotationCompleted" {
+			t.Errorf("Expected CARotationProgressing False with RotationCompleted, got %+v", cond)
+		}
+		if ready := apimeta.FindStatusCondition(server.Status.Conditions, v1alpha1.Ready); ready == nil || ready.Status != metav1.ConditionTrue {
+			t.Errorf("Expected a completed rotation not to affect the Ready condition, got %+v", ready)
+		}
+	})
+
+	t.Run("retries a failed step", func(t *testing.T) {
+		cli := &fakeSpireServerCLI{err: errors.New("no running SPIRE server pod found")}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerCLI = cli.run
+		server := newServer(nil)
+		statusMgr := status.NewManager(&fakes.FakeCustomCtrlClient{})
+
+		if next := reconciler.reconcileCARotation(context.Background(), server, statusMgr); next != caRotationRetryInterval {
+			t.Errorf("Expected a retry in %s, got %s", caRotationRetryInterval, next)
+		}
+		if server.Status.CARotation != nil {
+			t.Errorf("Expected the rotation not to progress, got %+v", server.Status.CARotation)
+		}
+
+		applyConditions(t, server, statusMgr)
+		cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.CARotationProgressingStatusType)
+		if cond == nil || cond.Reason != "RotationStepFailed" || !strings.Contains(cond.Message, "no running SPIRE server pod found") {
+			t.Errorf("Expected CARotationProgressing with RotationStepFailed, got %+v", cond)
+		}
+	})
+
+	t.Run("does not rotate in dry-run mode", func(t *testing.T) {
+		cli := &fakeSpireServerCLI{show: show}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerCLI = cli.run
+		server := newServer(nil)
+		server.Spec.ReconcileMode = v1alpha1.ReconcileModeDryRun
+
+		reconciler.reconcileCARotation(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}))
+		if len(cli.commands) != 0 {
+			t.Errorf("Expected no command in dry-run mode, got %v", cli.commands)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package spire_server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	bundlev1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/bundle/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// caStatusRefreshInterval is how often the CA and the trust bundle are read from the SPIRE server
+	caStatusRefreshInterval = 5 * time.Minute
+
+	// caStatusFetchTimeout bounds the call to the SPIRE server API
+	caStatusFetchTimeout = 10 * time.Second
+)
+
+// fetchServerCAState reads the trust bundle and the serving certificate chain of the SPIRE server.
+// It is a variable so that the tests don't reach the network.
+var fetchServerCAState = fetchCAStateFromServer
+
+// serverCAState is the trust bundle of the SPIRE server and the certificate chain it serves its API with
+type serverCAState struct {
+	authorities      []*x509.Certificate
+	peerCertificates []*x509.Certificate
+}
+
+// fetchCAStateFromServer calls the Bundle API of the SPIRE server, which any caller is authorized to,
+// and records the certificate chain the server serves. The chain is not authenticated during the
+// handshake as the operator has no bundle to bootstrap from; it is checked against the returned
+// bundle and the SPIFFE ID of the SPIRE server instead.
+func fetchCAStateFromServer(ctx context.Context, address, trustDomain string) (*serverCAState, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+	})))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var p peer.Peer
+	bundle, err := bundlev1.NewBundleClient(conn).GetBundle(ctx, &bundlev1.GetBundleRequest{}, grpc.Peer(&p))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the bundle from the SPIRE server: %w", err)
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, fmt.Errorf("SPIRE server did not serve a certificate")
+	}
+
+	state := &serverCAState{peerCertificates: tlsInfo.State.PeerCertificates}
+	for _, authority := range bundle.X509Authorities {
+		cert, err := x509.ParseCertificate(authority.Asn1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid X.509 authority in the SPIRE server bundle: %w", err)
+		}
+		state.authorities = append(state.authorities, cert)
+	}
+	if len(state.authorities) == 0 {
+		return nil, fmt.Errorf("SPIRE server bundle has no X.509 authorities")
+	}
+	if err := verifyBundleEndpointSVID(state.peerCertificates, state.authorities, fmt.Sprintf("spiffe://%s/spire/server", trustDomain)); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// newX509AuthorityStatus describes cert in the status
+func newX509AuthorityStatus(cert *x509.Certificate) *v1alpha1.X509AuthorityStatus {
+	return &v1alpha1.X509AuthorityStatus{
+		Subject:   cert.Subject.String(),
+		NotBefore: metav1.NewTime(cert.NotBefore),
+		NotAfter:  metav1.NewTime(cert.NotAfter),
+	}
+}
+
+// certificateAuthorityStatus derives the CA status from the state read from the SPIRE server. The
+// active CA signed the SVID the SPIRE server serves: it is served in the chain when the SPIRE server
+// CA is an intermediate of an upstream authority, and is a bundle authority otherwise. The prepared CA
+// is the most recent bundle authority issued after the active CA.
+func certificateAuthorityStatus(state *serverCAState, now metav1.Time) *v1alpha1.CertificateAuthorityStatus {
+	digest := sha256.New()
+	for _, authority := range state.authorities {
+		digest.Write(authority.Raw)
+	}
+	result := &v1alpha1.CertificateAuthorityStatus{
+		BundleCertificateCount: int32(len(state.authorities)),
+		BundleSHA256:           hex.EncodeToString(digest.Sum(nil)),
+		LastRefreshTime:        &now,
+	}
+	if len(state.peerCertificates) == 0 {
+		return result
+	}
+
+	leaf := state.peerCertificates[0]
+	var active *x509.Certificate
+	if len(state.peerCertificates) > 1 {
+		active = state.peerCertificates[1]
+	} else {
+		for _, authority := range state.authorities {
+			if len(leaf.AuthorityKeyId) > 0 && string(authority.SubjectKeyId) == string(leaf.AuthorityKeyId) {
+				active = authority
+				break
+			}
+		}
+	}
+	if active == nil {
+		return result
+	}
+	result.Active = newX509AuthorityStatus(active)
+
+	var prepared *x509.Certificate
+	for _, authority := range state.authorities {
+		if authority.Equal(active) || !authority.NotBefore.After(active.NotBefore) {
+			continue
+		}
+		if prepared == nil || authority.NotBefore.After(prepared.NotBefore) {
+			prepared = authority
+		}
+	}
+	if prepared != nil {
+		result.Prepared = newX509AuthorityStatus(prepared)
+	}
+	return result
+}
+
+// reconcileCAStatus reads the CA and the trust bundle of the SPIRE server and reports them in
+// status.certificateAuthority. They are not read again within caStatusRefreshInterval of the last
+// successful read. A failed read is reported in the message, keeping the last values read. It
+// returns when the CA is due for a refresh.
+func (r *SpireServerReconciler) reconcileCAStatus(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) time.Duration {
+	now := metav1.Now()
+	previous := server.Status.CertificateAuthority
+	if previous != nil && previous.Message == "" && previous.LastRefreshTime != nil {
+		if elapsed := now.Sub(previous.LastRefreshTime.Time); elapsed < caStatusRefreshInterval {
+			return caStatusRefreshInterval - elapsed
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, caStatusFetchTimeout)
+	defer cancel()
+	address := fmt.Sprintf("spire-server.%s.svc:443", utils.GetOperandNamespace())
+	var desired *v1alpha1.CertificateAuthorityStatus
+	state, err := fetchServerCAState(fetchCtx, address, ztwim.Spec.TrustDomain)
+	if err != nil {
+		r.log.Info("failed to read the CA of the SPIRE server", "error", err.Error())
+		desired = &v1alpha1.CertificateAuthorityStatus{}
+		if previous != nil {
+			desired = previous.DeepCopy()
+		}
+		desired.Message = err.Error()
+	} else {
+		desired = certificateAuthorityStatus(state, now)
+	}
+
+	if !equality.Semantic.DeepEqual(server.Status.CertificateAuthority, desired) {
+		server.Status.CertificateAuthority = desired
+		statusMgr.ForceStatusUpdate()
+	}
+	return caStatusRefreshInterval
+}
@@ -0,0 +1,272 @@
+package spire_server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	bundlev1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/bundle/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+// testCA is a CA certificate and its key
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCA returns a CA valid from notBefore, signed by parent or self-signed when parent is nil
+func newTestCA(t *testing.T, commonName string, notBefore time.Time, parent *testCA) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(notBefore.UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	issuer, issuerKey := template, key
+	if parent != nil {
+		issuer, issuerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create the CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key}
+}
+
+// newTestServerSVID returns an SVID for spiffeID signed by ca
+func newTestServerSVID(t *testing.T, ca *testCA, spiffeID string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the SVID key: %v", err)
+	}
+	id, _ := url.Parse(spiffeID)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to create the SVID: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+// fakeBundleServer serves a fixed bundle
+type fakeBundleServer struct {
+	bundlev1.UnimplementedBundleServer
+	bundle *types.Bundle
+}
+
+func (s *fakeBundleServer) GetBundle(context.Context, *bundlev1.GetBundleRequest) (*types.Bundle, error) {
+	return s.bundle, nil
+}
+
+func TestFetchCAStateFromServer(t *testing.T) {
+	ca := newTestCA(t, "spire-ca", time.Now().Add(-time.Hour), nil)
+	svid, key := newTestServerSVID(t, ca, "spiffe://example.org/spire/server")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{svid.Raw}, PrivateKey: key}},
+	})))
+	bundlev1.RegisterBundleServer(grpcServer, &fakeBundleServer{bundle: &types.Bundle{
+		TrustDomain:     "example.org",
+		X509Authorities: []*types.X509Certificate{{Asn1: ca.cert.Raw}},
+	}})
+	go func() { _ = grpcServer.Serve(listener) }()
+	defer grpcServer.Stop()
+
+	tests := []struct {
+		name        string
+		trustDomain string
+		expectedErr string
+	}{
+		{
+			name:        "SPIRE server serving an SVID of its trust domain",
+			trustDomain: "example.org",
+		},
+		{
+			name:        "SPIRE server serving an SVID of another trust domain",
+			trustDomain: "other.org",
+			expectedErr: "is not an SVID for spiffe://other.org/spire/server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := fetchCAStateFromServer(context.Background(), listener.Addr().String(), tt.trustDomain)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(state.authorities) != 1 || !state.authorities[0].Equal(ca.cert) {
+				t.Errorf("Expected the bundle authority, got %v", state.authorities)
+			}
+			if len(state.peerCertificates) != 1 || !state.peerCertificates[0].Equal(svid) {
+				t.Errorf("Expected the served SVID, got %v", state.peerCertificates)
+			}
+		})
+	}
+}
+
+func TestCertificateAuthorityStatus(t *testing.T) {
+	now := metav1.Now()
+	active := newTestCA(t, "active", time.Now().Add(-2*time.Hour), nil)
+	prepared := newTestCA(t, "prepared", time.Now().Add(-time.Hour), nil)
+	old := newTestCA(t, "old", time.Now().Add(-3*time.Hour), nil)
+	svid, _ := newTestServerSVID(t, active, "spiffe://example.org/spire/server")
+
+	t.Run("self-signed CA with a prepared CA in the bundle", func(t *testing.T) {
+		result := certificateAuthorityStatus(&serverCAState{
+			authorities:      []*x509.Certificate{old.cert, active.cert, prepared.cert},
+			peerCertificates: []*x509.Certificate{svid},
+		}, now)
+
+		if result.BundleCertificateCount != 3 || len(result.BundleSHA256) != 64 {
+			t.Errorf("Expected 3 bundle certificates with a digest, got %+v", result)
+		}
+		if result.Active == nil || result.Active.Subject != "CN=active" || !result.Active.NotAfter.Time.Equal(active.cert.NotAfter) {
+			t.Errorf("Expected the active CA, got %+v", result.Active)
+		}
+		if result.Prepared == nil || result.Prepared.Subject != "CN=prepared" {
+			t.Errorf("Expected the prepared CA, got %+v", result.Prepared)
+		}
+		if result.LastRefreshTime == nil || !result.LastRefreshTime.Equal(&now) {
+			t.Errorf("Expected the refresh time to be set, got %v", result.LastRefreshTime)
+		}
+	})
+
+	t.Run("intermediate CA of an upstream authority", func(t *testing.T) {
+		upstream := newTestCA(t, "upstream", time.Now().Add(-4*time.Hour), nil)
+		intermediate := newTestCA(t, "intermediate", time.Now().Add(-time.Hour), upstream)
+		leaf, _ := newTestServerSVID(t, intermediate, "spiffe://example.org/spire/server")
+
+		result := certificateAuthorityStatus(&serverCAState{
+			authorities:      []*x509.Certificate{upstream.cert},
+			peerCertificates: []*x509.Certificate{leaf, intermediate.cert},
+		}, now)
+
+		if result.Active == nil || result.Active.Subject != "CN=intermediate" {
+			t.Errorf("Expected the intermediate CA to be active, got %+v", result.Active)
+		}
+		if result.Prepared != nil {
+			t.Errorf("Expected no prepared CA, got %+v", result.Prepared)
+		}
+	})
+
+	t.Run("bundle digest changes when a CA is added", func(t *testing.T) {
+		before := certificateAuthorityStatus(&serverCAState{authorities: []*x509.Certificate{active.cert}}, now)
+		after := certificateAuthorityStatus(&serverCAState{authorities: []*x509.Certificate{active.cert, prepared.cert}}, now)
+		if before.BundleSHA256 == after.BundleSHA256 {
+			t.Error("Expected the bundle digest to change")
+		}
+	})
+}
+
+func TestReconcileCAStatus(t *testing.T) {
+	originalFetch := fetchServerCAState
+	t.Cleanup(func() { fetchServerCAState = originalFetch })
+
+	ca := newTestCA(t, "spire-ca", time.Now().Add(-time.Hour), nil)
+	svid, _ := newTestServerSVID(t, ca, "spiffe://example.org/spire/server")
+	fetched := 0
+	var fetchErr error
+	fetchServerCAState = func(ctx context.Context, address, trustDomain string) (*serverCAState, error) {
+		fetched++
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return &serverCAState{authorities: []*x509.Certificate{ca.cert}, peerCertificates: []*x509.Certificate{svid}}, nil
+	}
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"}}
+
+	t.Run("reports the CA read from the SPIRE server", func(t *testing.T) {
+		fetched, fetchErr = 0, nil
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		server := createTestSpireServer()
+
+		if next := reconciler.reconcileCAStatus(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}), ztwim); next != caStatusRefreshInterval {
+			t.Errorf("Expected the next refresh in %s, got %s", caStatusRefreshInterval, next)
+		}
+		ca := server.Status.CertificateAuthority
+		if ca == nil || ca.Active == nil || ca.Active.Subject != "CN=spire-ca" || ca.BundleCertificateCount != 1 || ca.Message != "" {
+			t.Errorf("Expected the CA to be reported, got %+v", ca)
+		}
+	})
+
+	t.Run("keeps the last CA read when the SPIRE server is unreachable", func(t *testing.T) {
+		fetched, fetchErr = 0, errors.New("connection refused")
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		server := createTestSpireServer()
+		lastRefresh := metav1.NewTime(time.Now().Add(-time.Hour))
+		server.Status.CertificateAuthority = &v1alpha1.CertificateAuthorityStatus{
+			Active:          &v1alpha1.X509AuthorityStatus{Subject: "CN=previous"},
+			BundleSHA256:    "previous",
+			LastRefreshTime: &lastRefresh,
+		}
+
+		reconciler.reconcileCAStatus(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}), ztwim)
+		ca := server.Status.CertificateAuthority
+		if ca.Message != "connection refused" || ca.Active.Subject != "CN=previous" || ca.BundleSHA256 != "previous" {
+			t.Errorf("Expected the previous CA with the error message, got %+v", ca)
+		}
+	})
+
+	t.Run("does not read the CA refreshed recently", func(t *testing.T) {
+		fetched, fetchErr = 0, nil
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		server := createTestSpireServer()
+		lastRefresh := metav1.NewTime(time.Now().Add(-time.Minute))
+		server.Status.CertificateAuthority = &v1alpha1.CertificateAuthorityStatus{LastRefreshTime: &lastRefresh}
+
+		next := reconciler.reconcileCAStatus(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}), ztwim)
+		if fetched != 0 {
+			t.Errorf("Expected the recently refreshed CA not to be read")
+		}
+		if next <= 0 || next > caStatusRefreshInterval-time.Minute+time.Second {
+			t.Errorf("Expected the next refresh when the CA is due, got %s", next)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/inspect"
+)
+
+// configReloadRetryInterval is how long a failed reload of the running SPIRE server pods is retried after
+const configReloadRetryInterval = time.Minute
+
+// reloadableServerSettings are the settings of the SPIRE server config applied to the running SPIRE server
+// pods when spec.configReload is enabled, instead of rolling out the StatefulSet
+var reloadableServerSettings = []string{"log_level"}
+
+// spireServerPodsCLI runs the SPIRE server CLI with args in every running SPIRE server pod
+type spireServerPodsCLI func(ctx context.Context, args ...string) error
+
+// newSpireServerPodsCLI returns a spireServerPodsCLI running the CLI through the exec subresource of the SPIRE server pods
+func newSpireServerPodsCLI(clientset kubernetes.Interface, executor inspect.Executor) spireServerPodsCLI {
+	return func(ctx context.Context, args ...string) error {
+		namespace := utils.GetOperandNamespace()
+		pods, err := runningSpireServerPods(ctx, clientset, namespace)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, pod := range pods {
+			if _, err := executor.Exec(ctx, namespace, pod, spireServerContainerName, append([]string{spireServerBinary}, args...)); err != nil {
+				errs = append(errs, fmt.Errorf("pod %s: %w", pod, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// withoutReloadableSettings removes the reloadable settings from the SPIRE server config conf when
+// spec.configReload is enabled, so that changing them doesn't change the config hash of the StatefulSet
+func withoutReloadableSettings(config *v1alpha1.SpireServerSpec, conf map[string]interface{}) map[string]interface{} {
+	if !utils.StringToBool(config.ConfigReload) {
+		return conf
+	}
+	if serverConfig, ok := conf["server"].(map[string]interface{}); ok {
+		for _, setting := range reloadableServerSettings {
+			delete(serverConfig, setting)
+		}
+	}
+	return conf
+}
+
+// reconcileConfigReload applies the log level of the SPIRE server to the running SPIRE server pods through
+// the logger API of the SPIRE server when spec.configReload is enabled. The log level applied is recorded in
+// status.logLevel, and the pods started since run with the log level of the updated ConfigMap. It returns
+// when a failed reload is retried, zero when none is.
+func (r *SpireServerReconciler) reconcileConfigReload(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) time.Duration {
+	if !utils.StringToBool(server.Spec.ConfigReload) {
+		if server.Status.LogLevel != "" {
+			server.Status.LogLevel = ""
+			statusMgr.ForceStatusUpdate()
+		}
+		return 0
+	}
+	if utils.IsDryRunMode(server.Spec.ReconcileMode) || createOnlyMode {
+		r.log.Info("Skipping the SPIRE server config reload", "dryRun", utils.IsDryRunMode(server.Spec.ReconcileMode), "createOnlyMode", createOnlyMode)
+		return 0
+	}
+
+	logLevel := utils.GetLogLevelFromString(server.Spec.LogLevel)
+	if server.Status.LogLevel == logLevel {
+		return 0
+	}
+	// Enabling the config reload rolls out the StatefulSet, whose pods start with the current log level
+	if server.Status.LogLevel == "" {
+		server.Status.LogLevel = logLevel
+		statusMgr.ForceStatusUpdate()
+		return 0
+	}
+
+	if err := r.spireServerPodsCLI(ctx, "logger", "set", "-level", logLevel, "-output", "json"); err != nil {
+		r.log.Error(err, "failed to reload the log level of the SPIRE server pods", "logLevel", logLevel)
+		statusMgr.AddCondition(utils.ConfigReloadedStatusType, "ReloadFailed",
+			fmt.Sprintf("Failed to apply the log level %s to the running SPIRE server pods: %v", logLevel, err),
+			metav1.ConditionFalse)
+		return configReloadRetryInterval
+	}
+
+	r.log.Info("Reloaded the log level of the SPIRE server pods", "logLevel", logLevel)
+	statusMgr.AddCondition(utils.ConfigReloadedStatusType, "LogLevelReloaded",
+		fmt.Sprintf("The log level %s was applied to the running SPIRE server pods without a rollout", logLevel),
+		metav1.ConditionTrue)
+	server.Status.LogLevel = logLevel
+	statusMgr.ForceStatusUpdate()
+	return 0
+}
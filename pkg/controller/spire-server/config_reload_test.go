@@ -0,0 +1,148 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func TestConfigHashWithoutReloadableSettings(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"}}
+	configHash := func(spec *v1alpha1.SpireServerSpec) string {
+		t.Helper()
+		confJSON, err := marshalToJSON(withoutReloadableSettings(spec, generateServerConfMap(spec, ztwim)))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return generateConfigHash(confJSON)
+	}
+
+	server := createTestSpireServer()
+	server.Spec.LogLevel = "info"
+	debug := server.Spec.DeepCopy()
+	debug.LogLevel = "debug"
+	if configHash(&server.Spec) == configHash(debug) {
+		t.Error("Expected a log level change to roll out the StatefulSet without config reload")
+	}
+
+	server.Spec.ConfigReload = "true"
+	debug.ConfigReload = "true"
+	if configHash(&server.Spec) != configHash(debug) {
+		t.Error("Expected a log level change not to roll out the StatefulSet with config reload")
+	}
+	if conf := generateServerConfMap(debug, ztwim); conf["server"].(map[string]interface{})["log_level"] != "debug" {
+		t.Errorf("Expected the ConfigMap to keep the log level, got %v", conf["server"])
+	}
+	debug.CASubject.CommonName = "example.org"
+	if configHash(&server.Spec) == configHash(debug) {
+		t.Error("Expected a change of another setting to roll out the StatefulSet with config reload")
+	}
+}
+
+func TestReconcileConfigReload(t *testing.T) {
+	newServer := func(logLevel, appliedLogLevel string) *v1alpha1.SpireServer {
+		server := createTestSpireServer()
+		server.Spec.ConfigReload = "true"
+		server.Spec.LogLevel = logLevel
+		server.Status.LogLevel = appliedLogLevel
+		return server
+	}
+	newReconciler := func(err error) (*SpireServerReconciler, *[]string) {
+		var commands []string
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		reconciler.spireServerPodsCLI = func(ctx context.Context, args ...string) error {
+			commands = append(commands, strings.Join(args, " "))
+			return err
+		}
+		return reconciler, &commands
+	}
+
+	t.Run("reloads a changed log level", func(t *testing.T) {
+		reconciler, commands := newReconciler(nil)
+		server := newServer("debug", "info")
+		statusMgr := status.NewManager(&fakes.FakeCustomCtrlClient{})
+
+		if retry := reconciler.reconcileConfigReload(context.Background(), server, statusMgr, false); retry != 0 {
+			t.Errorf("Expected no retry, got %s", retry)
+		}
+		if expected := "logger set -level debug -output json"; strings.Join(*commands, ";") != expected {
+			t.Errorf("Expected command %q, got %v", expected, *commands)
+		}
+		if server.Status.LogLevel != "debug" {
+			t.Errorf("Expected the applied log level to be recorded, got %q", server.Status.LogLevel)
+		}
+		if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+			return &server.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.ConfigReloadedStatusType)
+		if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "LogLevelReloaded" {
+			t.Errorf("Expected ConfigReloaded True with LogLevelReloaded, got %+v", cond)
+		}
+	})
+
+	t.Run("retries a failed reload", func(t *testing.T) {
+		reconciler, _ := newReconciler(errors.New("unknown command \"logger\""))
+		server := newServer("debug", "info")
+		statusMgr := status.NewManager(&fakes.FakeCustomCtrlClient{})
+
+		if retry := reconciler.reconcileConfigReload(context.Background(), server, statusMgr, false); retry != configReloadRetryInterval {
+			t.Errorf("Expected a retry after %s, got %s", configReloadRetryInterval, retry)
+		}
+		if server.Status.LogLevel != "info" {
+			t.Errorf("Expected the applied log level to be kept, got %q", server.Status.LogLevel)
+		}
+		if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+			return &server.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.ConfigReloadedStatusType); cond == nil || cond.Status != metav1.ConditionFalse {
+			t.Errorf("Expected ConfigReloaded False, got %+v", cond)
+		}
+		if ready := apimeta.FindStatusCondition(server.Status.Conditions, v1alpha1.Ready); ready == nil || ready.Status != metav1.ConditionTrue {
+			t.Errorf("Expected a failed reload not to affect Ready, got %+v", ready)
+		}
+	})
+
+	t.Run("records the log level the pods start with once enabled", func(t *testing.T) {
+		reconciler, commands := newReconciler(nil)
+		server := newServer("warn", "")
+
+		reconciler.reconcileConfigReload(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}), false)
+		if len(*commands) != 0 || server.Status.LogLevel != "warn" {
+			t.Errorf("Expected the log level to be recorded without a reload, got %v and %q", *commands, server.Status.LogLevel)
+		}
+	})
+
+	t.Run("clears the applied log level once disabled", func(t *testing.T) {
+		reconciler, commands := newReconciler(nil)
+		server := newServer("debug", "info")
+		server.Spec.ConfigReload = "false"
+
+		reconciler.reconcileConfigReload(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}), false)
+		if len(*commands) != 0 || server.Status.LogLevel != "" {
+			t.Errorf("Expected no reload and no applied log level, got %v and %q", *commands, server.Status.LogLevel)
+		}
+	})
+
+	t.Run("skips the reload in create-only mode", func(t *testing.T) {
+		reconciler, commands := newReconciler(nil)
+		server := newServer("debug", "info")
+
+		reconciler.reconcileConfigReload(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}), true)
+		if len(*commands) != 0 || server.Status.LogLevel != "info" {
+			t.Errorf("Expected no reload in create-only mode, got %v and %q", *commands, server.Status.LogLevel)
+		}
+	})
+}
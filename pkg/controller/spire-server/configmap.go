@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -21,6 +23,7 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 	spiffev1alpha "github.com/spiffe/spire-controller-manager/api/v1alpha1"
 )
 
@@ -35,7 +38,9 @@ type ControllerManagerConfigYAML struct {
 
 // reconcileSpireServerConfigMap reconciles the Spire Server ConfigMap
 func (r *SpireServerReconciler) reconcileSpireServerConfigMap(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) (string, error) {
+	_, renderSpan := tracing.Start(ctx, "Render SPIRE server ConfigMap")
 	spireServerConfigMap, err := generateSpireServerConfigMap(&server.Spec, ztwim)
+	tracing.End(renderSpan, err)
 	if err != nil {
 		r.log.Error(err, "failed to generate spire server config map")
 		statusMgr.AddCondition(ServerConfigMapAvailable, "SpireServerConfigMapGenerationFailed",
@@ -51,6 +56,7 @@ func (r *SpireServerReconciler) reconcileSpireServerConfigMap(ctx context.Contex
 			metav1.ConditionFalse)
 		return "", err
 	}
+	statusMgr.TrackResource(spireServerConfigMap)
 
 	var existingSpireServerCM corev1.ConfigMap
 	err = r.ctrlClient.Get(ctx, types.NamespacedName{Name: spireServerConfigMap.Name, Namespace: spireServerConfigMap.Namespace}, &existingSpireServerCM)
@@ -62,6 +68,7 @@ func (r *SpireServerReconciler) reconcileSpireServerConfigMap(ctx context.Contex
 			return "", fmt.Errorf("failed to create ConfigMap: %w", err)
 		}
 		r.log.Info("Created spire server ConfigMap")
+		statusMgr.RecordResourceCreated(spireServerConfigMap)
 	} else if err == nil && (existingSpireServerCM.Data["server.conf"] != spireServerConfigMap.Data["server.conf"] ||
 		!equality.Semantic.DeepEqual(existingSpireServerCM.Labels, spireServerConfigMap.Labels)) {
 		if createOnlyMode {
@@ -75,6 +82,7 @@ func (r *SpireServerReconciler) reconcileSpireServerConfigMap(ctx context.Contex
 				return "", fmt.Errorf("failed to update ConfigMap: %w", err)
 			}
 			r.log.Info("Updated ConfigMap with new config")
+			statusMgr.RecordDriftRepaired(spireServerConfigMap)
 		}
 	} else if err != nil {
 		statusMgr.AddCondition(ServerConfigMapAvailable, "SpireServerConfigMapGenerationFailed",
@@ -88,7 +96,7 @@ func (r *SpireServerReconciler) reconcileSpireServerConfigMap(ctx context.Contex
 		metav1.ConditionTrue)
 
 	// Generate config hash
-	spireServerConfJSON, err := marshalToJSON(generateServerConfMap(&server.Spec, ztwim))
+	spireServerConfJSON, err := marshalToJSON(withoutReloadableSettings(&server.Spec, generateServerConfMap(&server.Spec, ztwim)))
 	if err != nil {
 		r.log.Error(err, "failed to marshal spire server config map to JSON")
 		return "", err
@@ -99,7 +107,9 @@ func (r *SpireServerReconciler) reconcileSpireServerConfigMap(ctx context.Contex
 
 // reconcileSpireControllerManagerConfigMap reconciles the Spire Controller Manager ConfigMap
 func (r *SpireServerReconciler) reconcileSpireControllerManagerConfigMap(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) (string, error) {
+	_, renderSpan := tracing.Start(ctx, "Render SPIRE controller manager ConfigMap")
 	spireControllerManagerConfig, err := generateSpireControllerManagerConfigYaml(&server.Spec, ztwim)
+	tracing.End(renderSpan, err)
 	if err != nil {
 		r.log.Error(err, "Failed to generate spire controller manager config")
 		statusMgr.AddCondition(ControllerManagerConfigAvailable, "SpireControllerManagerConfigMapGenerationFailed",
@@ -116,6 +126,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerConfigMap(ctx con
 			metav1.ConditionFalse)
 		return "", err
 	}
+	statusMgr.TrackResource(spireControllerManagerConfigMap)
 
 	var existingSpireControllerManagerCM corev1.ConfigMap
 	err = r.ctrlClient.Get(ctx, types.NamespacedName{Name: spireControllerManagerConfigMap.Name, Namespace: spireControllerManagerConfigMap.Namespace}, &existingSpireControllerManagerCM)
@@ -128,6 +139,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerConfigMap(ctx con
 			return "", fmt.Errorf("failed to create ConfigMap: %w", err)
 		}
 		r.log.Info("Created spire controller manager ConfigMap")
+		statusMgr.RecordResourceCreated(spireControllerManagerConfigMap)
 	} else if err == nil && (existingSpireControllerManagerCM.Data["controller-manager-config.yaml"] != spireControllerManagerConfigMap.Data["controller-manager-config.yaml"] ||
 		!equality.Semantic.DeepEqual(existingSpireControllerManagerCM.Labels, spireControllerManagerConfigMap.Labels)) {
 		if createOnlyMode {
@@ -140,6 +152,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerConfigMap(ctx con
 					metav1.ConditionFalse)
 				return "", fmt.Errorf("failed to update ConfigMap: %w", err)
 			}
+			statusMgr.RecordDriftRepaired(spireControllerManagerConfigMap)
 		}
 		r.log.Info("Updated ConfigMap with new config")
 	} else if err != nil {
@@ -172,6 +185,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleConfigMap(ctx context.Contex
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(spireBundleCM)
 
 	err = r.ctrlClient.Create(ctx, spireBundleCM)
 	if err != nil && !kerrors.IsAlreadyExists(err) {
@@ -208,7 +222,7 @@ func generateSpireServerConfigMap(config *v1alpha1.SpireServerSpec, ztwim *v1alp
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-server",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    utils.SpireServerLabels(config.Labels),
 		},
 		Data: map[string]string{
@@ -219,11 +233,63 @@ func generateSpireServerConfigMap(config *v1alpha1.SpireServerSpec, ztwim *v1alp
 	return cm, nil
 }
 
+// validateRenderedServerConfig checks that the SPIRE server accepts the config rendered from the spec, e.g.
+// with the plugins added with extraConfig, before it is written to the ConfigMap. A config that can't be
+// rendered is reported when the ConfigMap is reconciled.
+func validateRenderedServerConfig(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	cm, err := generateSpireServerConfigMap(config, ztwim)
+	if err != nil {
+		return nil
+	}
+	return utils.ValidateSpireServerConfig(cm.Data["server.conf"])
+}
+
+// auditLogEnabled reports whether the audit logging of the SPIRE server API calls is enabled
+func auditLogEnabled(auditLog *v1alpha1.AuditLogConfig) bool {
+	return auditLog != nil && utils.StringToBool(auditLog.Enabled)
+}
+
+// serverLogFormat returns the log format of the SPIRE server, the audit log format taking precedence when audit logging is enabled
+func serverLogFormat(config *v1alpha1.SpireServerSpec) string {
+	if auditLogEnabled(config.AuditLog) && config.AuditLog.Format != "" {
+		return utils.GetLogFormatFromString(config.AuditLog.Format)
+	}
+	return utils.GetLogFormatFromString(config.LogFormat)
+}
+
+// generateRateLimitConfig returns the ratelimit section of the server config, or nil to keep the SPIRE defaults
+func generateRateLimitConfig(rateLimit *v1alpha1.RateLimitConfig) map[string]interface{} {
+	if rateLimit == nil {
+		return nil
+	}
+	// SPIRE rate limits unless disabled, so unset limits stay enabled
+	return map[string]interface{}{
+		"attestation": rateLimit.Attestation != "false",
+		"signing":     rateLimit.Signing != "false",
+	}
+}
+
+// k8sPSATAudience returns the audiences accepted by the k8s_psat node attestor
+func k8sPSATAudience(nodeAttestor *v1alpha1.ServerNodeAttestor) []string {
+	if nodeAttestor != nil && len(nodeAttestor.K8sPSATAudience) > 0 {
+		return nodeAttestor.K8sPSATAudience
+	}
+	return []string{utils.DefaultK8sPSATAudience}
+}
+
+// k8sPSATServiceAccountAllowList returns the service accounts the agents are allowed to attest with
+func k8sPSATServiceAccountAllowList(nodeAttestor *v1alpha1.ServerNodeAttestor) []string {
+	if nodeAttestor != nil && len(nodeAttestor.K8sPSATServiceAccountAllowList) > 0 {
+		return nodeAttestor.K8sPSATServiceAccountAllowList
+	}
+	return []string{fmt.Sprintf("%s:spire-agent", utils.GetOperandNamespace())}
+}
+
 // generateServerConfMap builds the server.conf structure as a Go map
 func generateServerConfMap(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) map[string]interface{} {
 	// Build the server config
 	serverConfig := map[string]interface{}{
-		"audit_log_enabled": false,
+		"audit_log_enabled": auditLogEnabled(config.AuditLog),
 		"bind_address":      "0.0.0.0",
 		"bind_port":         "8081",
 		"ca_key_type":       getCAKeyType(config.CAKeyType),
@@ -240,19 +306,32 @@ func generateServerConfMap(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.Zer
 		"default_x509_svid_ttl": config.DefaultX509Validity,
 		"jwt_issuer":            config.JwtIssuer,
 		"log_level":             utils.GetLogLevelFromString(config.LogLevel),
-		"log_format":            utils.GetLogFormatFromString(config.LogFormat),
+		"log_format":            serverLogFormat(config),
 		"trust_domain":          ztwim.Spec.TrustDomain,
 	}
 
+	// Only add agent_ttl if it's explicitly set, SPIRE defaults it to default_x509_svid_ttl
+	if config.AgentValidity.Duration > 0 {
+		serverConfig["agent_ttl"] = config.AgentValidity
+	}
+
 	// Only add jwt_key_type if it's explicitly set
 	if config.JWTKeyType != "" {
 		serverConfig["jwt_key_type"] = config.JWTKeyType
 	}
 
+	if rateLimit := generateRateLimitConfig(config.RateLimit); rateLimit != nil {
+		serverConfig["ratelimit"] = rateLimit
+	}
+
+	if experimental := utils.ExperimentalConfig(config.Experimental); experimental != nil {
+		serverConfig["experimental"] = experimental
+	}
+
 	configMap := map[string]interface{}{
 		"health_checks": map[string]interface{}{
 			"bind_address":     "0.0.0.0",
-			"bind_port":        "8080",
+			"bind_port":        strconv.Itoa(int(getServerHealthPort(config))),
 			"listener_enabled": true,
 			"live_path":        "/live",
 			"ready_path":       "/ready",
@@ -265,15 +344,7 @@ func generateServerConfMap(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.Zer
 					},
 				},
 			},
-			"KeyManager": []map[string]interface{}{
-				{
-					"disk": map[string]interface{}{
-						"plugin_data": map[string]interface{}{
-							"keys_path": "/run/spire/data/keys.json",
-						},
-					},
-				},
-			},
+			"KeyManager": buildKeyManagerPlugin(config.KeyManager),
 			"NodeAttestor": []map[string]interface{}{
 				{
 					"k8s_psat": map[string]interface{}{
@@ -281,12 +352,10 @@ func generateServerConfMap(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.Zer
 							"clusters": []map[string]interface{}{
 								{
 									ztwim.Spec.ClusterName: map[string]interface{}{
-										"allowed_node_label_keys": []string{},
-										"allowed_pod_label_keys":  []string{},
-										"audience":                []string{"spire-server"},
-										"service_account_allow_list": []string{
-											fmt.Sprintf("%s:spire-agent", utils.GetOperatorNamespace()),
-										},
+										"allowed_node_label_keys":    []string{},
+										"allowed_pod_label_keys":     []string{},
+										"audience":                   k8sPSATAudience(config.NodeAttestor),
+										"service_account_allow_list": k8sPSATServiceAccountAllowList(config.NodeAttestor),
 									},
 								},
 							},
@@ -299,7 +368,7 @@ func generateServerConfMap(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.Zer
 					"k8sbundle": map[string]interface{}{
 						"plugin_data": map[string]interface{}{
 							"config_map": ztwim.Spec.BundleConfigMap,
-							"namespace":  utils.GetOperatorNamespace(),
+							"namespace":  utils.GetOperandNamespace(),
 						},
 					},
 				},
@@ -320,9 +389,74 @@ func generateServerConfMap(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.Zer
 		serverSection["federation"] = generateFederationConfig(config.Federation)
 	}
 
+	// Have the server CA signed by the upstream SPIRE server if configured
+	if config.UpstreamAuthority != nil && config.UpstreamAuthority.Spire != nil {
+		plugins := configMap["plugins"].(map[string]interface{})
+		plugins["UpstreamAuthority"] = []map[string]interface{}{
+			{
+				"spire": map[string]interface{}{
+					"plugin_data": generateSpireUpstreamAuthorityPluginData(config.UpstreamAuthority.Spire),
+				},
+			},
+		}
+	}
+
+	// Publish the trust bundle to the locations read by the relying parties outside of the cluster
+	if config.BundlePublishers != nil {
+		if bundlePublishers := generateBundlePublisherPlugins(config.BundlePublishers); len(bundlePublishers) > 0 {
+			plugins := configMap["plugins"].(map[string]interface{})
+			plugins["BundlePublisher"] = bundlePublishers
+		}
+	}
+
+	// Customize the issued SVIDs with the CredentialComposer plugins
+	if config.CredentialComposers != nil {
+		if credentialComposers := generateCredentialComposerPlugins(config.CredentialComposers); len(credentialComposers) > 0 {
+			plugins := configMap["plugins"].(map[string]interface{})
+			plugins["CredentialComposer"] = credentialComposers
+		}
+	}
+
+	// Let the agents outside of the cluster attest with a join token
+	if config.Exposure != nil && utils.StringToBool(config.Exposure.JoinToken) {
+		plugins := configMap["plugins"].(map[string]interface{})
+		plugins["NodeAttestor"] = append(plugins["NodeAttestor"].([]map[string]interface{}), map[string]interface{}{
+			"join_token": map[string]interface{}{"plugin_data": map[string]interface{}{}},
+		})
+	}
+
+	// Serve the agents of the hosted cluster from the management cluster
+	if utils.GetTopologyMode(ztwim) == v1alpha1.TopologyManagementCluster {
+		configureHostedClusterPlugins(configMap, ztwim.Spec.ClusterName)
+	}
+
+	// Merge the user provided settings last so that the keys set above win. The extra config
+	// is validated before the config is generated, so a decoding error cannot happen here.
+	if extraConfig, err := utils.DecodeExtraConfig(config.ExtraConfig); err == nil {
+		utils.MergeExtraConfig(configMap, extraConfig)
+	}
+
 	return configMap
 }
 
+// generateSpireUpstreamAuthorityPluginData generates the plugin data of the spire UpstreamAuthority,
+// which reaches the upstream agent through the Workload API socket mounted into the server container
+func generateSpireUpstreamAuthorityPluginData(upstream *v1alpha1.SpireUpstreamAuthorityConfig) map[string]interface{} {
+	serverPort := upstream.ServerPort
+	if serverPort == 0 {
+		serverPort = 8081
+	}
+	socketName := upstream.WorkloadAPISocketName
+	if socketName == "" {
+		socketName = "spire-agent.sock"
+	}
+	return map[string]interface{}{
+		"server_address":      upstream.ServerAddress,
+		"server_port":         strconv.Itoa(int(serverPort)),
+		"workload_api_socket": path.Join(UpstreamAgentSocketMountPath, socketName),
+	}
+}
+
 // generateFederationConfig generates the federation configuration for SPIRE server
 func generateFederationConfig(federation *v1alpha1.FederationConfig) map[string]interface{} {
 	federationConf := map[string]interface{}{
@@ -449,6 +583,34 @@ func getCAKeyType(keyType string) string {
 	return keyType
 }
 
+// buildKeyManagerPlugin builds the KeyManager plugin, which keeps the keys on the data volume
+// unless only the memory key manager is enabled
+func buildKeyManagerPlugin(keyManager *v1alpha1.KeyManager) []map[string]interface{} {
+	if usesMemoryKeyManager(keyManager) {
+		return []map[string]interface{}{
+			{
+				"memory": map[string]interface{}{
+					"plugin_data": map[string]interface{}{},
+				},
+			},
+		}
+	}
+	return []map[string]interface{}{
+		{
+			"disk": map[string]interface{}{
+				"plugin_data": map[string]interface{}{
+					"keys_path": "/run/spire/data/keys.json",
+				},
+			},
+		},
+	}
+}
+
+// usesMemoryKeyManager reports whether the memory key manager is enabled instead of the disk one
+func usesMemoryKeyManager(keyManager *v1alpha1.KeyManager) bool {
+	return keyManager != nil && utils.StringToBool(keyManager.MemoryEnabled) && !utils.StringToBool(keyManager.DiskEnabled)
+}
+
 // buildDataStorePluginData builds the plugin_data map for the DataStore plugin
 func buildDataStorePluginData(datastore v1alpha1.DataStore) map[string]interface{} {
 	pluginData := map[string]interface{}{
@@ -485,7 +647,7 @@ func generateControllerManagerConfig(config *v1alpha1.SpireServerSpec, ztwim *v1
 		APIVersion: "spire.spiffe.io/v1alpha1",
 		Metadata: metav1.ObjectMeta{
 			Name:      "spire-controller-manager",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    utils.SpireControllerManagerLabels(config.Labels),
 		},
 		ControllerManagerConfig: spiffev1alpha.ControllerManagerConfig{
@@ -500,7 +662,7 @@ func generateControllerManagerConfig(config *v1alpha1.SpireServerSpec, ztwim *v1
 				},
 				EntryIDPrefix:    ztwim.Spec.ClusterName,
 				WatchClassless:   false,
-				ClassName:        "zero-trust-workload-identity-manager-spire",
+				ClassName:        utils.GetControllerManagerClassName(config.ControllerManager),
 				ParentIDTemplate: "spiffe://{{ .TrustDomain }}/spire/agent/k8s_psat/{{ .ClusterName }}/{{ .NodeMeta.UID }}",
 				Reconcile: &spiffev1alpha.ReconcileConfig{
 					ClusterSPIFFEIDs:             true,
@@ -536,7 +698,7 @@ func generateControllerManagerConfigMap(configYAML string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-controller-manager",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    utils.SpireControllerManagerLabels(nil),
 		},
 		Data: map[string]string{
@@ -552,7 +714,7 @@ func generateSpireBundleConfigMap(config *v1alpha1.SpireServerSpec, ztwim *v1alp
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ztwim.Spec.BundleConfigMap,
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    utils.SpireServerLabels(config.Labels),
 		},
 	}, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -198,6 +200,17 @@ func TestGenerateServerConfMap(t *testing.T) {
 		t.Errorf("Expected default_jwt_svid_ttl %v, got %v", validConfig.DefaultJWTValidity, server["default_jwt_svid_ttl"])
 	}
 
+	// agent_ttl is only rendered when set
+	if _, ok := server["agent_ttl"]; ok {
+		t.Errorf("Expected no agent_ttl when agentValidity is unset, got %v", server["agent_ttl"])
+	}
+	agentConfig := createValidConfig()
+	agentConfig.AgentValidity = metav1.Duration{Duration: 2 * time.Hour}
+	agentServer := generateServerConfMap(agentConfig, validZTWIM)["server"].(map[string]interface{})
+	if agentServer["agent_ttl"] != agentConfig.AgentValidity {
+		t.Errorf("Expected agent_ttl %v, got %v", agentConfig.AgentValidity, agentServer["agent_ttl"])
+	}
+
 	// Test CA subject
 	caSubjects, ok := server["ca_subject"].([]map[string]interface{})
 	if !ok || len(caSubjects) == 0 {
@@ -336,6 +349,100 @@ func TestGenerateServerConfMapTTLFields(t *testing.T) {
 	}
 }
 
+func TestGenerateServerConfMapWithExtraConfig(t *testing.T) {
+	config := createValidConfig()
+	config.ExtraConfig = &apiextensionsv1.JSON{Raw: []byte(`{"server":{"trust_domain":"other.org","audit_log_enabled":true,"ratelimit":{"attestation":false}}}`)}
+
+	validZTWIM := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	confMap := generateServerConfMap(config, validZTWIM)
+
+	server, ok := confMap["server"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Failed to get server section")
+	}
+	if server["trust_domain"] != "example.org" {
+		t.Errorf("Expected the operator trust_domain to win, got %v", server["trust_domain"])
+	}
+	if server["audit_log_enabled"] != false {
+		t.Errorf("Expected the operator audit_log_enabled to win, got %v", server["audit_log_enabled"])
+	}
+	if _, ok := server["ratelimit"].(map[string]interface{}); !ok {
+		t.Errorf("Expected ratelimit to be merged from extraConfig, got %v", server["ratelimit"])
+	}
+}
+
+func TestGenerateServerConfMapWithExperimental(t *testing.T) {
+	config := createValidConfig()
+	config.Experimental = map[string]string{"events_based_cache": "true", "cache_reload_interval": "10s"}
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"}}
+
+	server := generateServerConfMap(config, ztwim)["server"].(map[string]interface{})
+	expected := map[string]interface{}{"events_based_cache": true, "cache_reload_interval": "10s"}
+	if !reflect.DeepEqual(server["experimental"], expected) {
+		t.Errorf("Expected the experimental block %v, got %v", expected, server["experimental"])
+	}
+
+	config.Experimental = nil
+	if experimental, ok := generateServerConfMap(config, ztwim)["server"].(map[string]interface{})["experimental"]; ok {
+		t.Errorf("Expected no experimental block, got %v", experimental)
+	}
+}
+
+func TestGenerateServerConfMapWithHealthPort(t *testing.T) {
+	config := createValidConfig()
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"}}
+
+	if port := generateServerConfMap(config, ztwim)["health_checks"].(map[string]interface{})["bind_port"]; port != "8080" {
+		t.Errorf("Expected the default health port 8080, got %v", port)
+	}
+	config.HealthPort = 8090
+	if port := generateServerConfMap(config, ztwim)["health_checks"].(map[string]interface{})["bind_port"]; port != "8090" {
+		t.Errorf("Expected the health port 8090, got %v", port)
+	}
+}
+
+func TestGenerateServerConfMapWithUpstreamAuthority(t *testing.T) {
+	validZTWIM := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	plugins := generateServerConfMap(createValidConfig(), validZTWIM)["plugins"].(map[string]interface{})
+	if _, ok := plugins["UpstreamAuthority"]; ok {
+		t.Error("Expected no UpstreamAuthority without upstreamAuthority")
+	}
+
+	config := createValidConfig()
+	config.UpstreamAuthority = &v1alpha1.UpstreamAuthorityConfig{
+		Spire: &v1alpha1.SpireUpstreamAuthorityConfig{
+			ServerAddress:        "spire-server.root.example.org",
+			WorkloadAPICSIDriver: "upstream.csi.spiffe.io",
+		},
+	}
+	plugins = generateServerConfMap(config, validZTWIM)["plugins"].(map[string]interface{})
+	upstreamAuthority, ok := plugins["UpstreamAuthority"].([]map[string]interface{})
+	if !ok || len(upstreamAuthority) != 1 {
+		t.Fatalf("Expected one UpstreamAuthority plugin, got %v", plugins["UpstreamAuthority"])
+	}
+	pluginData := upstreamAuthority[0]["spire"].(map[string]interface{})["plugin_data"].(map[string]interface{})
+	expected := map[string]interface{}{
+		"server_address":      "spire-server.root.example.org",
+		"server_port":         "8081",
+		"workload_api_socket": "/run/spire/upstream-agent/spire-agent.sock",
+	}
+	if !reflect.DeepEqual(pluginData, expected) {
+		t.Errorf("Expected plugin data %v, got %v", expected, pluginData)
+	}
+}
+
 func TestGenerateSpireServerConfigMapWithTTLFields(t *testing.T) {
 	// Test that the new TTL fields are properly included in the generated ConfigMap
 	config := createValidConfig()
@@ -596,6 +703,30 @@ func TestGenerateSpireControllerManagerConfigYaml(t *testing.T) {
 	}
 }
 
+func TestGenerateControllerManagerConfigClassName(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+
+	config := createValidConfig()
+	cmConfig, err := generateControllerManagerConfig(config, ztwim)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmConfig.ClassName != utils.DefaultSpireControllerManagerClassName {
+		t.Errorf("Expected the default class name %q, got %q", utils.DefaultSpireControllerManagerClassName, cmConfig.ClassName)
+	}
+
+	config.ControllerManager = &v1alpha1.ControllerManagerConfig{ClassName: "acme-spire"}
+	cmConfig, err = generateControllerManagerConfig(config, ztwim)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmConfig.ClassName != "acme-spire" {
+		t.Errorf("Expected class name %q, got %q", "acme-spire", cmConfig.ClassName)
+	}
+}
+
 func TestGenerateControllerManagerConfigMap(t *testing.T) {
 	testYAML := "test: yaml\nkey: value"
 
@@ -1772,3 +1903,175 @@ func createTestZTWIM() *v1alpha1.ZeroTrustWorkloadIdentityManager {
 		},
 	}
 }
+
+func TestBuildKeyManagerPlugin(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyManager *v1alpha1.KeyManager
+		expected   string
+	}{
+		{name: "unset key manager uses the disk key manager", expected: "disk"},
+		{name: "disk key manager", keyManager: &v1alpha1.KeyManager{DiskEnabled: "true", MemoryEnabled: "false"}, expected: "disk"},
+		{name: "disk takes precedence over memory", keyManager: &v1alpha1.KeyManager{DiskEnabled: "true", MemoryEnabled: "true"}, expected: "disk"},
+		{name: "memory key manager", keyManager: &v1alpha1.KeyManager{DiskEnabled: "false", MemoryEnabled: "true"}, expected: "memory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := buildKeyManagerPlugin(tt.keyManager)
+			if len(plugin) != 1 {
+				t.Fatalf("Expected one KeyManager plugin, got %v", plugin)
+			}
+			if _, ok := plugin[0][tt.expected]; !ok {
+				t.Errorf("Expected the %s key manager, got %v", tt.expected, plugin[0])
+			}
+		})
+	}
+}
+
+func TestGenerateServerConfMapWithAuditLog(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+
+	tests := []struct {
+		name              string
+		auditLog          *v1alpha1.AuditLogConfig
+		expectEnabled     bool
+		expectedLogFormat string
+	}{
+		{
+			name:              "audit logging unset",
+			expectedLogFormat: "text",
+		},
+		{
+			name:              "audit logging disabled ignores the format",
+			auditLog:          &v1alpha1.AuditLogConfig{Enabled: "false", Format: "json"},
+			expectedLogFormat: "text",
+		},
+		{
+			name:              "audit logging enabled keeps logFormat",
+			auditLog:          &v1alpha1.AuditLogConfig{Enabled: "true"},
+			expectEnabled:     true,
+			expectedLogFormat: "text",
+		},
+		{
+			name:              "audit logging enabled with a format",
+			auditLog:          &v1alpha1.AuditLogConfig{Enabled: "true", Format: "json"},
+			expectEnabled:     true,
+			expectedLogFormat: "json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createValidConfig()
+			config.LogFormat = "text"
+			config.AuditLog = tt.auditLog
+
+			server := generateServerConfMap(config, ztwim)["server"].(map[string]interface{})
+			if server["audit_log_enabled"] != tt.expectEnabled {
+				t.Errorf("Expected audit_log_enabled %v, got %v", tt.expectEnabled, server["audit_log_enabled"])
+			}
+			if server["log_format"] != tt.expectedLogFormat {
+				t.Errorf("Expected log_format %q, got %v", tt.expectedLogFormat, server["log_format"])
+			}
+		})
+	}
+}
+
+func TestGenerateServerConfMapWithRateLimit(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+
+	tests := []struct {
+		name      string
+		rateLimit *v1alpha1.RateLimitConfig
+		expected  interface{}
+	}{
+		{
+			name: "rate limits unset keep the SPIRE defaults",
+		},
+		{
+			name:      "empty rate limits stay enabled",
+			rateLimit: &v1alpha1.RateLimitConfig{},
+			expected:  map[string]interface{}{"attestation": true, "signing": true},
+		},
+		{
+			name:      "attestation rate limit disabled",
+			rateLimit: &v1alpha1.RateLimitConfig{Attestation: "false", Signing: "true"},
+			expected:  map[string]interface{}{"attestation": false, "signing": true},
+		},
+		{
+			name:      "all rate limits disabled",
+			rateLimit: &v1alpha1.RateLimitConfig{Attestation: "false", Signing: "false"},
+			expected:  map[string]interface{}{"attestation": false, "signing": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createValidConfig()
+			config.RateLimit = tt.rateLimit
+
+			server := generateServerConfMap(config, ztwim)["server"].(map[string]interface{})
+			rateLimit, ok := server["ratelimit"]
+			if tt.expected == nil {
+				if ok {
+					t.Errorf("Expected no ratelimit, got %v", rateLimit)
+				}
+				return
+			}
+			if !reflect.DeepEqual(rateLimit, tt.expected) {
+				t.Errorf("Expected ratelimit %v, got %v", tt.expected, rateLimit)
+			}
+		})
+	}
+}
+
+func TestGenerateServerConfMapWithK8sPSAT(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+
+	tests := []struct {
+		name                 string
+		nodeAttestor         *v1alpha1.ServerNodeAttestor
+		expectedAudience     []string
+		expectedAllowedUsers []string
+	}{
+		{
+			name:                 "defaults",
+			expectedAudience:     []string{"spire-server"},
+			expectedAllowedUsers: []string{utils.GetOperandNamespace() + ":spire-agent"},
+		},
+		{
+			name: "configured audience and service accounts",
+			nodeAttestor: &v1alpha1.ServerNodeAttestor{
+				K8sPSATAudience:                []string{"spire-server", "fleet.example.org"},
+				K8sPSATServiceAccountAllowList: []string{"spire-agents:hardened-agent"},
+			},
+			expectedAudience:     []string{"spire-server", "fleet.example.org"},
+			expectedAllowedUsers: []string{"spire-agents:hardened-agent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createValidConfig()
+			config.NodeAttestor = tt.nodeAttestor
+
+			plugins := generateServerConfMap(config, ztwim)["plugins"].(map[string]interface{})
+			psat := plugins["NodeAttestor"].([]map[string]interface{})[0]["k8s_psat"].(map[string]interface{})
+			clusters := psat["plugin_data"].(map[string]interface{})["clusters"].([]map[string]interface{})
+			cluster := clusters[0]["test-cluster"].(map[string]interface{})
+			if !reflect.DeepEqual(cluster["audience"], tt.expectedAudience) {
+				t.Errorf("Expected audience %v, got %v", tt.expectedAudience, cluster["audience"])
+			}
+			if !reflect.DeepEqual(cluster["service_account_allow_list"], tt.expectedAllowedUsers) {
+				t.Errorf("Expected service_account_allow_list %v, got %v", tt.expectedAllowedUsers, cluster["service_account_allow_list"])
+			}
+		})
+	}
+}
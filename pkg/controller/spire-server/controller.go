@@ -3,16 +3,21 @@ package spire_server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -21,8 +26,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 
@@ -31,6 +38,9 @@ import (
 	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/inspect"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/operatorconfig"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 const (
@@ -39,6 +49,7 @@ const (
 	ServerConfigMapAvailable         = "ServerConfigMapAvailable"
 	ControllerManagerConfigAvailable = "ControllerManagerConfigAvailable"
 	BundleConfigAvailable            = "BundleConfigAvailable"
+	BundleDistributionAvailable      = "BundleDistributionAvailable"
 	TTLConfigurationValid            = "TTLConfigurationValid"
 	ConfigurationValid               = "ConfigurationValid"
 	ServiceAccountAvailable          = "ServiceAccountAvailable"
@@ -46,6 +57,11 @@ const (
 	RBACAvailable                    = "RBACAvailable"
 	ValidatingWebhookAvailable       = "ValidatingWebhookAvailable"
 	RouteAvailable                   = "RouteAvailable"
+	PodDisruptionBudgetAvailable     = "PodDisruptionBudgetAvailable"
+	NetworkPolicyAvailable           = "NetworkPolicyAvailable"
+	DatastoreBackupAvailable         = "DatastoreBackupAvailable"
+	TornjakAvailable                 = "TornjakAvailable"
+	ServerExposureAvailable          = "ServerExposureAvailable"
 )
 
 // SpireServerReconciler reconciles a SpireServer object
@@ -55,24 +71,41 @@ type SpireServerReconciler struct {
 	eventRecorder record.EventRecorder
 	log           logr.Logger
 	scheme        *runtime.Scheme
+
+	// failures counts the consecutive failed reconciles, to report the CRs that keep failing as Degraded
+	failures *utils.FailureTracker
+
+	// spireServerCLI runs the SPIRE server CLI in the SPIRE server pods, to rotate the authorities
+	spireServerCLI spireServerCLI
+
+	// spireServerPodsCLI runs the SPIRE server CLI in every SPIRE server pod, to reload their config
+	spireServerPodsCLI spireServerPodsCLI
 }
 
 // New returns a new Reconciler instance.
-func New(mgr ctrl.Manager) (*SpireServerReconciler, error) {
-	c, err := customClient.NewCustomClient(mgr)
+func New(mgr ctrl.Manager, clientOpts ...customClient.Option) (*SpireServerReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	executor := inspect.NewPodExecutor(mgr.GetConfig(), clientset)
 	return &SpireServerReconciler{
-		ctrlClient:    c,
-		ctx:           context.Background(),
-		eventRecorder: mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName),
-		log:           ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName),
-		scheme:        mgr.GetScheme(),
+		ctrlClient:         c,
+		ctx:                context.Background(),
+		eventRecorder:      mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName),
+		log:                ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName),
+		scheme:             mgr.GetScheme(),
+		failures:           utils.NewFailureTracker(),
+		spireServerCLI:     newSpireServerCLI(clientset, executor),
+		spireServerPodsCLI: newSpireServerPodsCLI(clientset, executor),
 	}, nil
 }
 
-func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
 	r.log.Info(fmt.Sprintf("reconciling %s", utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName))
 	var server v1alpha1.SpireServer
 	if err := r.ctrlClient.Get(ctx, req.NamespacedName, &server); err != nil {
@@ -83,13 +116,42 @@ func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Tear down the managed resources in order when the CR is being deleted. The operand namespace
+	// is resolved first, as the operator may have restarted since the namespace was last configured.
+	if !server.DeletionTimestamp.IsZero() {
+		namespace, err := utils.ResolveOperandNamespace(ctx, r.ctrlClient, server.Status.ManagedResources)
+		if err != nil {
+			r.log.Error(err, "failed to resolve the operand namespace")
+			return ctrl.Result{}, err
+		}
+		return r.reconcileDeletion(ctx, &server, namespace)
+	}
+
+	// Leave the managed resources untouched while reconciliation is paused
+	if utils.StringToBool(server.Spec.Paused) {
+		r.log.Info("SpireServer reconciliation is paused, skipping", "name", server.Name)
+		if err := status.SetPausedStatus(ctx, r.ctrlClient, &server, func() *v1alpha1.ConditionalStatus {
+			return &server.Status.ConditionalStatus
+		}); err != nil {
+			r.log.Error(err, "failed to update status")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Set Ready to false at the start of reconciliation
 	status.SetInitialReconciliationStatus(ctx, r.ctrlClient, &server, func() *v1alpha1.ConditionalStatus {
 		return &server.Status.ConditionalStatus
 	}, "SpireServer")
 
+	var dryRun *customClient.DryRunClient
+	var adoption *customClient.AdoptionClient
+	var exclusion *customClient.ExclusionClient
 	statusMgr := status.NewManager(r.ctrlClient)
 	defer func() {
+		statusMgr.SetDryRunStatus(r.eventRecorder, &server, server.Status.ConditionalStatus.Conditions, dryRun)
+		statusMgr.SetExcludedResourcesStatus(server.Status.ConditionalStatus.Conditions, exclusion)
+		statusMgr.SetDegradedCondition(server.Status.ConditionalStatus.Conditions, r.failures.Observe(req.NamespacedName, reconcileErr), reconcileErr)
+		statusMgr.RecordResourcesAdopted(adoption)
 		if err := statusMgr.ApplyStatus(ctx, &server, func() *v1alpha1.ConditionalStatus {
 			return &server.Status.ConditionalStatus
 		}); err != nil {
@@ -109,6 +171,22 @@ func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Install the operands in the namespace configured on the ZTWIM
+	if err := utils.ConfigureOperandNamespace(ztwim.Spec.OperandNamespace); err != nil {
+		r.log.Error(err, "invalid operand namespace")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed, err.Error(), metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
+	// Leave the operand to the operator running in the other cluster of the topology
+	if !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireServer) {
+		r.log.Info("SpireServer is not deployed in this cluster, skipping", "topology", utils.GetTopologyMode(&ztwim))
+		statusMgr.AddCondition(v1alpha1.Ready, utils.ConditionReasonNotDeployedInTopology,
+			fmt.Sprintf("SpireServer is not deployed in the %s topology", utils.GetTopologyMode(&ztwim)),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Set ZTWIM as the owner of SpireServer only if needed
 	if utils.NeedsOwnerReferenceUpdate(&server, &ztwim) {
 		if err := controllerutil.SetControllerReference(&ztwim, &server, r.scheme); err != nil {
@@ -129,9 +207,68 @@ func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
+	// Add the cleanup finalizer so that deleting the CR tears down the managed resources in order
+	if controllerutil.AddFinalizer(&server, utils.OperandCleanupFinalizer) {
+		if err := r.ctrlClient.Update(ctx, &server); err != nil {
+			r.log.Error(err, "failed to add finalizer to SpireServer")
+			statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+				fmt.Sprintf("Failed to add finalizer to SpireServer: %v", err),
+				metav1.ConditionFalse)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// In dry-run mode, record the writes made below as planned changes instead of applying them
+	if utils.IsDryRunMode(server.Spec.ReconcileMode) {
+		r.log.Info("Running in dry-run mode - planned changes are reported in status and not applied")
+		dryRun = customClient.NewDryRunClient(r.ctrlClient, r.scheme)
+		dryRunReconciler := *r
+		dryRunReconciler.ctrlClient = dryRun
+		r = &dryRunReconciler
+	}
+
+	// Adopt the resources of the same name created by another installer, e.g. the SPIRE Helm charts
+	if utils.StringToBool(server.Spec.AdoptExistingResources) {
+		adoption = customClient.NewAdoptionClient(r.ctrlClient)
+		adoptionReconciler := *r
+		adoptionReconciler.ctrlClient = adoption
+		r = &adoptionReconciler
+	}
+
+	// Leave the resources excluded from management to their owner
+	if len(server.Spec.ExcludedResources) > 0 {
+		exclusion = customClient.NewExclusionClient(r.ctrlClient, r.scheme, server.Spec.ExcludedResources)
+		exclusionReconciler := *r
+		exclusionReconciler.ctrlClient = exclusion
+		r = &exclusionReconciler
+	}
+
+	// Explain the significant reconcile actions in events on the CR
+	statusMgr.SetEventRecorder(r.eventRecorder, &server, dryRun != nil)
+
+	// Leave the managed resources as they are or delete them as set by the management state
+	managementState := utils.GetManagementState(server.Spec.ManagementState, &ztwim)
+	statusMgr.SetManagementStateCondition(managementState, "SpireServer", server.Status.Conditions)
+	switch managementState {
+	case v1alpha1.ManagementStateUnmanaged:
+		r.log.Info("SpireServer is unmanaged, skipping", "name", server.Name)
+		return ctrl.Result{}, nil
+	case v1alpha1.ManagementStateRemoved:
+		return ctrl.Result{}, r.reconcileRemoval(ctx, &server, utils.OperandNamespaceOf(&ztwim), statusMgr)
+	}
+
 	// Handle create-only mode
 	createOnlyMode := r.handleCreateOnlyMode(&server, statusMgr)
 
+	// Wait for the cluster to pass the preflight checks of the ZeroTrustWorkloadIdentityManager before the first deployment
+	if message, blocked := utils.PreflightChecksBlockDeployment(&ztwim, server.Status.Conditions, StatefulSetAvailable); blocked {
+		r.log.Info("Waiting for the preflight checks to pass before deploying SpireServer", "message", message)
+		statusMgr.AddCondition(v1alpha1.Ready, utils.PreflightChecksFailed,
+			fmt.Sprintf("Waiting for the preflight checks of the ZeroTrustWorkloadIdentityManager: %s", message),
+			metav1.ConditionFalse)
+		return ctrl.Result{}, nil
+	}
+
 	// Validate configuration
 	if err := r.validateConfiguration(ctx, &server, statusMgr, &ztwim); err != nil {
 		return ctrl.Result{}, nil
@@ -157,9 +294,11 @@ func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	// Reconcile Webhook
-	if err := r.reconcileWebhook(ctx, &server, statusMgr, createOnlyMode); err != nil {
-		return ctrl.Result{}, err
+	// Reconcile Webhook, unless the controller manager serves the hosted cluster, which can't reach it
+	if utils.GetTopologyMode(&ztwim) != v1alpha1.TopologyManagementCluster {
+		if err := r.reconcileWebhook(ctx, &server, statusMgr, createOnlyMode); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Reconcile ConfigMaps
@@ -179,8 +318,33 @@ func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Create the ConfigMaps the trust bundle is published to
+	if err := r.reconcileBundlePublisherConfigMaps(ctx, &server, statusMgr, &ztwim); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Distribute the trust bundle to the selected namespaces if enabled
+	if err := r.reconcileBundleDistribution(ctx, &server, statusMgr, &ztwim, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile StatefulSet
-	if err := r.reconcileStatefulSet(ctx, &server, statusMgr, createOnlyMode, spireServerConfigMapHash, spireControllerManagerConfigMapHash); err != nil {
+	if err := r.reconcileStatefulSet(ctx, &server, statusMgr, &ztwim, createOnlyMode, spireServerConfigMapHash, spireControllerManagerConfigMapHash); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile PodDisruptionBudget
+	if err := r.reconcilePodDisruptionBudget(ctx, &server, statusMgr, &ztwim, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile NetworkPolicy if enabled
+	if err := r.reconcileNetworkPolicy(ctx, &server, statusMgr, &ztwim, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile the datastore backup CronJob if enabled
+	if err := r.reconcileDatastoreBackup(ctx, &server, statusMgr, createOnlyMode); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -189,7 +353,48 @@ func (r *SpireServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// Publish the SPIRE server API outside of the cluster if configured
+	if err := r.reconcileServerExposure(ctx, &server, statusMgr, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile the Tornjak UI if enabled
+	if err := r.reconcileTornjak(ctx, &server, statusMgr, &ztwim, createOnlyMode); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Report the health of the bundle endpoints of the federated trust domains
+	federationRefresh := r.reconcileFederationStatus(ctx, &server, statusMgr)
+
+	// Report the CA and the trust bundle of the SPIRE server
+	caRefresh := r.reconcileCAStatus(ctx, &server, statusMgr, &ztwim)
+
+	// Rotate the authorities of the SPIRE server if scheduled or requested
+	caRotationStep := r.reconcileCARotation(ctx, &server, statusMgr)
+
+	// Apply the settings changed at runtime to the running SPIRE server pods if enabled
+	configReloadRetry := r.reconcileConfigReload(ctx, &server, statusMgr, createOnlyMode)
+
+	// Prune resources from the previous inventory that the current spec no longer generates
+	pruned, err := statusMgr.PruneOrphanedResources(ctx, r.ctrlClient, r.scheme, server.Status.ManagedResources, createOnlyMode, dryRun != nil)
+	if err != nil {
+		r.log.Error(err, "failed to prune orphaned resources")
+		return ctrl.Result{}, err
+	}
+	for _, resource := range pruned {
+		r.log.Info("Pruned orphaned resource", "kind", resource.Kind, "namespace", resource.Namespace, "name", resource.Name)
+	}
+
+	// Requeue periodically so that drift from the desired state is repaired, and sooner when the
+	// bundles of the federated trust domains or the CA status are due for a refresh, the next step
+	// of the CA rotation is due, or a failed config reload is retried
+	requeueAfter := utils.ResyncInterval(server.Spec.ResyncInterval, ztwim.Spec.ResyncInterval)
+	for _, refresh := range []time.Duration{federationRefresh, caRefresh, caRotationStep, configReloadRetry} {
+		if refresh > 0 && (requeueAfter == 0 || refresh < requeueAfter) {
+			requeueAfter = refresh
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *SpireServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -210,7 +415,12 @@ func (r *SpireServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.SpireServer{}, builder.WithPredicates(utils.GenerationOrOwnerReferenceChangedPredicate)).
 		Named(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: utils.MaxConcurrentReconciles(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName),
+			RateLimiter:             utils.NewRateLimiter(),
+		}).
 		Watches(&appsv1.StatefulSet{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
@@ -221,7 +431,12 @@ func (r *SpireServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&admissionregistrationv1.ValidatingWebhookConfiguration{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
 		Watches(&v1alpha1.ZeroTrustWorkloadIdentityManager{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(utils.ZTWIMSpecChangedPredicate)).
 		Watches(&routev1.Route{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
-		Complete(r)
+		Watches(&policyv1.PodDisruptionBudget{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&networkingv1.NetworkPolicy{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&batchv1.CronJob{}, handler.EnqueueRequestsFromMapFunc(mapFunc), controllerManagedResourcePredicates).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(namespaceLabelsChangedPredicate)).
+		WatchesRawSource(source.Channel(operatorconfig.Subscribe(), handler.EnqueueRequestsFromMapFunc(mapFunc))).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerSpireServerControllerName, r))
 	if err != nil {
 		return err
 	}
@@ -268,6 +483,179 @@ func (r *SpireServerReconciler) validateConfiguration(ctx context.Context, serve
 		return err
 	}
 
+	// Validate datastore database type
+	if err := validateDatastore(server.Spec.Datastore); err != nil {
+		r.log.Error(err, "Invalid datastore configuration", "databaseType", server.Spec.Datastore.DatabaseType)
+		statusMgr.AddCondition(ConfigurationValid, "InvalidDatastoreConfiguration",
+			fmt.Sprintf("Datastore configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the datastore and key manager against the persistence type
+	if err := validatePersistence(&server.Spec); err != nil {
+		r.log.Error(err, "Invalid persistence configuration", "type", server.Spec.Persistence.Type)
+		statusMgr.AddCondition(ConfigurationValid, "InvalidPersistenceConfiguration",
+			fmt.Sprintf("Persistence configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the datastore backup against the datastore and the persistence
+	if err := validateDatastoreBackup(&server.Spec); err != nil {
+		r.log.Error(err, "Invalid datastore backup configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidDatastoreBackup",
+			fmt.Sprintf("Datastore backup validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// The health checks are served next to the other listeners of the SPIRE server pods
+	if err := validateHealthPort(&server.Spec); err != nil {
+		r.log.Error(err, "Invalid ports in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidPorts",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the identity templating of the spire-controller-manager
+	if err := validateControllerManager(server.Spec.ControllerManager); err != nil {
+		r.log.Error(err, "Invalid controller manager configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidControllerManagerConfiguration",
+			fmt.Sprintf("Controller manager configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the free-form extra config merged into server.conf
+	if _, err := utils.DecodeExtraConfig(server.Spec.ExtraConfig); err != nil {
+		r.log.Error(err, "Invalid extra configuration in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidExtraConfig",
+			fmt.Sprintf("Extra configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the experimental settings rendered into the experimental block of server.conf
+	if err := utils.ValidateExperimentalConfig(server.Spec.Experimental); err != nil {
+		r.log.Error(err, "Invalid experimental settings in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidExperimentalConfig",
+			fmt.Sprintf("Experimental settings validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// The trust domain is owned by the ZeroTrustWorkloadIdentityManager, extraConfig must not override it
+	if err := validateServerExtraConfigGlobals(&server.Spec, ztwim); err != nil {
+		r.log.Error(err, "Extra configuration conflicts with the ZeroTrustWorkloadIdentityManager")
+		statusMgr.AddCondition(ConfigurationValid, "ConflictingExtraConfig",
+			fmt.Sprintf("Extra configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// The ManagementCluster topology requires the kubeconfig of the hosted cluster
+	if err := utils.ValidateTopology(ztwim.Spec.Topology); err != nil {
+		r.log.Error(err, "Invalid topology configuration")
+		statusMgr.AddCondition(ConfigurationValid, utils.ConditionReasonInvalidTopology,
+			fmt.Sprintf("Topology validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the namespace selector of the trust bundle distribution
+	if err := validateBundleDistribution(server.Spec.BundleDistribution); err != nil {
+		r.log.Error(err, "Invalid bundle distribution in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidBundleDistribution",
+			fmt.Sprintf("Bundle distribution validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the ConfigMaps the trust bundle is published to
+	if err := validateBundlePublishers(server.Spec.BundlePublishers); err != nil {
+		r.log.Error(err, "Invalid bundle publishers in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidBundlePublishers",
+			fmt.Sprintf("Bundle publishers validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the external CredentialComposer plugins against the extra volumes
+	if err := validateCredentialComposers(&server.Spec); err != nil {
+		r.log.Error(err, "Invalid credential composers in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidCredentialComposers",
+			fmt.Sprintf("Credential composers validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the address advertised to the agents outside of the cluster
+	if err := validateServerExposure(server.Spec.Exposure); err != nil {
+		r.log.Error(err, "Invalid exposure in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidExposure",
+			fmt.Sprintf("Exposure validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the schedule of the CA rotations
+	if err := validateCARotation(server.Spec.CARotation); err != nil {
+		r.log.Error(err, "Invalid CA rotation in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidCARotation",
+			fmt.Sprintf("CA rotation validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the schedule of the pruning of the stale registration entries
+	if err := validateEntryPruning(server.Spec.EntryPruning); err != nil {
+		r.log.Error(err, "Invalid entry pruning in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidEntryPruning",
+			fmt.Sprintf("Entry pruning validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the SPIRE version the SPIRE server is pinned to
+	if err := utils.SpireServerOperand.ValidateVersion(server.Spec.Version); err != nil {
+		r.log.Error(err, "Invalid version in SpireServer configuration")
+		statusMgr.AddCondition(ConfigurationValid, utils.ConditionReasonInvalidVersion,
+			fmt.Sprintf("Version validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Refuse to fall back to the non-FIPS images when running in FIPS mode
+	if err := utils.ValidateFIPSImages(utils.SpireServerOperand.FIPSImageEnvFor(server.Spec.Version), utils.SpireControllerManagerFIPSImageEnv); err != nil {
+		r.log.Error(err, "FIPS images of the SPIRE server are not set")
+		statusMgr.AddCondition(ConfigurationValid, utils.ConditionReasonFIPSImageNotSet,
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate key types against FIPS approved algorithms when running in FIPS mode
+	if utils.IsFIPSModeEnabled() {
+		if err := validateFIPSCompliance(&server.Spec); err != nil {
+			r.log.Error(err, "SpireServer configuration is not FIPS compliant")
+			statusMgr.AddCondition(ConfigurationValid, "FIPSIncompatibleConfiguration",
+				fmt.Sprintf("FIPS compliance validation failed: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+	}
+
+	// Reject the capabilities whose feature gate is disabled on this cluster
+	if err := validateFeatureGates(&server.Spec, ztwim); err != nil {
+		r.log.Error(err, "SpireServer configuration uses a disabled feature gate")
+		statusMgr.AddCondition(ConfigurationValid, "FeatureGateDisabled",
+			fmt.Sprintf("Feature gate validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	if server.Spec.Federation != nil {
 		if err := validateFederationConfig(server.Spec.Federation, ztwim.Spec.TrustDomain); err != nil {
 			r.log.Error(err, "Invalid federation configuration", "trustDomain", ztwim.Spec.TrustDomain)
@@ -278,6 +666,15 @@ func (r *SpireServerReconciler) validateConfiguration(ctx context.Context, serve
 		}
 	}
 
+	// Validate the rendered config last, so that the invalid settings of the spec are reported first
+	if err := validateRenderedServerConfig(&server.Spec, ztwim); err != nil {
+		r.log.Error(err, "Rendered SPIRE server configuration is invalid")
+		statusMgr.AddCondition(ConfigurationValid, "InvalidRenderedConfiguration",
+			fmt.Sprintf("Rendered SPIRE server configuration validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	// Only set to true if the condition previously existed as false
 	existingCondition := apimeta.FindStatusCondition(server.Status.ConditionalStatus.Conditions, ConfigurationValid)
 	if existingCondition != nil && existingCondition.Status == metav1.ConditionFalse {
@@ -288,8 +685,80 @@ func (r *SpireServerReconciler) validateConfiguration(ctx context.Context, serve
 	return nil
 }
 
-// validateCommonConfig validates common configuration fields (affinity, tolerations, nodeSelector, resources, labels)
+// validateCommonConfig validates common configuration fields (affinity, tolerations, nodeSelector, resources, labels, extra volumes, env, security context, extra containers, image pull secrets, ServiceAccount annotations, node platform, topology spread constraints)
 func (r *SpireServerReconciler) validateCommonConfig(server *v1alpha1.SpireServer, statusMgr *status.Manager) error {
+	// Validate the extra volumes mounted into the SPIRE server pods
+	if err := utils.ValidateExtraVolumes(server.Spec.ExtraVolumes, server.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "Invalid extra volumes in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraVolumes,
+			fmt.Sprintf("Extra volumes validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the environment variables set in the SPIRE server container
+	if err := utils.ValidateContainerEnv(server.Spec.Env, server.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "Invalid environment variables in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidEnv,
+			fmt.Sprintf("Environment variables validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the security context overrides of the SPIRE server pods
+	if err := utils.ValidateSecurityContext(server.Spec.SecurityContext, server.Spec.PodSecurityContext, false); err != nil {
+		r.log.Error(err, "Invalid security context in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidSecurityContext,
+			fmt.Sprintf("Security context validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the extra containers added to the SPIRE server pods
+	if err := utils.ValidateExtraContainers(server.Spec.ExtraContainers, server.Spec.ExtraInitContainers); err != nil {
+		r.log.Error(err, "Invalid extra containers in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidExtraContainers,
+			fmt.Sprintf("Extra containers validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the image pull secrets of the SPIRE server pods
+	if err := utils.ValidateImagePullSecrets(server.Spec.ImagePullSecrets); err != nil {
+		r.log.Error(err, "Invalid image pull secrets in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidImagePullSecrets,
+			fmt.Sprintf("Image pull secrets validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the annotations of the SPIRE server ServiceAccount
+	if err := utils.ValidateServiceAccountAnnotations(server.Spec.ServiceAccountAnnotations); err != nil {
+		r.log.Error(err, "Invalid ServiceAccount annotations in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidServiceAccountAnnotations,
+			fmt.Sprintf("ServiceAccount annotations validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the node platform of the SPIRE server pods against the operand images and their node selector
+	if err := utils.ValidateNodePlatform(server.Spec.NodeSelector, server.Spec.NodePlatform); err != nil {
+		r.log.Error(err, "Invalid node platform in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidNodePlatform,
+			fmt.Sprintf("Node platform validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	// Validate the topology spread constraints of the SPIRE server pods
+	if err := utils.ValidateTopologySpreadConstraints(server.Spec.TopologySpreadConstraints); err != nil {
+		r.log.Error(err, "Invalid topology spread constraints in SpireServer configuration")
+		statusMgr.AddCondition(utils.ConditionTypeConfigurationValid, utils.ConditionReasonInvalidTopologySpreadConstraints,
+			fmt.Sprintf("Topology spread constraints validation failed: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
 	return utils.ValidateAndUpdateStatus(
 		r.log,
 		statusMgr,
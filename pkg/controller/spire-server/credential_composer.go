@@ -0,0 +1,111 @@
+package spire_server
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// CredentialComposerPluginsMountPath is where the volumes holding the external CredentialComposer
+// plugins are mounted into the spire-server container, in a directory named after the plugin
+const CredentialComposerPluginsMountPath = "/run/spire/credential-composers"
+
+// uniqueIDCredentialComposer is the name of the built-in CredentialComposer plugin
+const uniqueIDCredentialComposer = "uniqueid"
+
+// decodeCredentialComposerPluginData returns the plugin data of an external CredentialComposer plugin
+func decodeCredentialComposerPluginData(pluginData *apiextensionsv1.JSON) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	if pluginData == nil || len(pluginData.Raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(pluginData.Raw, &data); err != nil {
+		return nil, fmt.Errorf("pluginData must be an object: %w", err)
+	}
+	return data, nil
+}
+
+// validateCredentialComposers checks that the external CredentialComposer plugins are loaded from an
+// extra volume of the SPIRE server and that their plugin data is an object
+func validateCredentialComposers(config *v1alpha1.SpireServerSpec) error {
+	if config.CredentialComposers == nil {
+		return nil
+	}
+	for i, plugin := range config.CredentialComposers.External {
+		if plugin.Name == uniqueIDCredentialComposer {
+			return fmt.Errorf("credentialComposers.external[%d]: name %s is reserved for the built-in plugin", i, plugin.Name)
+		}
+		if !hasExtraVolume(config.ExtraVolumes, plugin.VolumeName) {
+			return fmt.Errorf("credentialComposers.external[%d]: volume %s is not an extra volume of the SPIRE server", i, plugin.VolumeName)
+		}
+		if cleaned := path.Clean(plugin.Path); path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return fmt.Errorf("credentialComposers.external[%d]: path %s must be a file path relative to the volume", i, plugin.Path)
+		}
+		if _, err := decodeCredentialComposerPluginData(plugin.PluginData); err != nil {
+			return fmt.Errorf("credentialComposers.external[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// hasExtraVolume reports whether volumes has a volume named name
+func hasExtraVolume(volumes []corev1.Volume, name string) bool {
+	for _, volume := range volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialComposerPluginCmd returns the path of the binary of an external CredentialComposer plugin
+// in the spire-server container
+func credentialComposerPluginCmd(plugin v1alpha1.ExternalCredentialComposerConfig) string {
+	return path.Join(CredentialComposerPluginsMountPath, plugin.Name, path.Clean(plugin.Path))
+}
+
+// generateCredentialComposerPlugins generates the CredentialComposer plugins of the SPIRE server. The
+// spec is validated before the config is generated, so the plugin data always decodes.
+func generateCredentialComposerPlugins(config *v1alpha1.CredentialComposersConfig) []map[string]interface{} {
+	var plugins []map[string]interface{}
+	if utils.StringToBool(config.UniqueID) {
+		plugins = append(plugins, map[string]interface{}{
+			uniqueIDCredentialComposer: map[string]interface{}{
+				"plugin_data": map[string]interface{}{},
+			},
+		})
+	}
+	for _, plugin := range config.External {
+		pluginData, _ := decodeCredentialComposerPluginData(plugin.PluginData)
+		pluginConfig := map[string]interface{}{
+			"plugin_cmd":  credentialComposerPluginCmd(plugin),
+			"plugin_data": pluginData,
+		}
+		if plugin.Checksum != "" {
+			pluginConfig["plugin_checksum"] = plugin.Checksum
+		}
+		plugins = append(plugins, map[string]interface{}{plugin.Name: pluginConfig})
+	}
+	return plugins
+}
+
+// addCredentialComposersToStatefulSet mounts the extra volumes holding the external CredentialComposer
+// plugins into the spire-server container. The volumes themselves are added with the extra volumes.
+func addCredentialComposersToStatefulSet(sts *appsv1.StatefulSet, config *v1alpha1.CredentialComposersConfig) {
+	container := &sts.Spec.Template.Spec.Containers[0]
+	for _, plugin := range config.External {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      plugin.VolumeName,
+			MountPath: path.Join(CredentialComposerPluginsMountPath, plugin.Name),
+			ReadOnly:  true,
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package spire_server
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func newCredentialComposerTestSpec(plugins ...v1alpha1.ExternalCredentialComposerConfig) *v1alpha1.SpireServerSpec {
+	spec := &createTestSpireServer().Spec
+	spec.ExtraVolumes = []corev1.Volume{
+		{Name: "plugins", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	spec.CredentialComposers = &v1alpha1.CredentialComposersConfig{External: plugins}
+	return spec
+}
+
+func TestValidateCredentialComposers(t *testing.T) {
+	validPlugin := v1alpha1.ExternalCredentialComposerConfig{
+		Name:       "custom-claims",
+		VolumeName: "plugins",
+		Path:       "bin/custom-claims",
+		PluginData: &apiextensionsv1.JSON{Raw: []byte(`{"claims":{"department":"payments"}}`)},
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(plugin *v1alpha1.ExternalCredentialComposerConfig)
+		expectedErr string
+	}{
+		{
+			name:   "valid plugin",
+			modify: func(plugin *v1alpha1.ExternalCredentialComposerConfig) {},
+		},
+		{
+			name:        "reserved name",
+			modify:      func(plugin *v1alpha1.ExternalCredentialComposerConfig) { plugin.Name = "uniqueid" },
+			expectedErr: "reserved for the built-in plugin",
+		},
+		{
+			name:        "unknown volume",
+			modify:      func(plugin *v1alpha1.ExternalCredentialComposerConfig) { plugin.VolumeName = "missing" },
+			expectedErr: "volume missing is not an extra volume",
+		},
+		{
+			name:        "absolute path",
+			modify:      func(plugin *v1alpha1.ExternalCredentialComposerConfig) { plugin.Path = "/bin/custom-claims" },
+			expectedErr: "must be a file path relative to the volume",
+		},
+		{
+			name:        "path escaping the volume",
+			modify:      func(plugin *v1alpha1.ExternalCredentialComposerConfig) { plugin.Path = "bin/../../custom-claims" },
+			expectedErr: "must be a file path relative to the volume",
+		},
+		{
+			name: "plugin data not an object",
+			modify: func(plugin *v1alpha1.ExternalCredentialComposerConfig) {
+				plugin.PluginData = &apiextensionsv1.JSON{Raw: []byte(`["claims"]`)}
+			},
+			expectedErr: "pluginData must be an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := validPlugin
+			tt.modify(&plugin)
+			err := validateCredentialComposers(newCredentialComposerTestSpec(plugin))
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	if err := validateCredentialComposers(&createTestSpireServer().Spec); err != nil {
+		t.Errorf("Expected no error without credential composers, got %v", err)
+	}
+}
+
+func TestGenerateCredentialComposerPlugins(t *testing.T) {
+	config := &v1alpha1.CredentialComposersConfig{
+		UniqueID: "true",
+		External: []v1alpha1.ExternalCredentialComposerConfig{
+			{
+				Name:       "custom-claims",
+				VolumeName: "plugins",
+				Path:       "./bin/custom-claims",
+				Checksum:   strings.Repeat("a", 64),
+				PluginData: &apiextensionsv1.JSON{Raw: []byte(`{"organizational_unit":"payments"}`)},
+			},
+			{Name: "audit", VolumeName: "plugins", Path: "audit"},
+		},
+	}
+
+	expected := []map[string]interface{}{
+		{"uniqueid": map[string]interface{}{"plugin_data": map[string]interface{}{}}},
+		{
+			"custom-claims": map[string]interface{}{
+				"plugin_cmd":      "/run/spire/credential-composers/custom-claims/bin/custom-claims",
+				"plugin_checksum": strings.Repeat("a", 64),
+				"plugin_data":     map[string]interface{}{"organizational_unit": "payments"},
+			},
+		},
+		{
+			"audit": map[string]interface{}{
+				"plugin_cmd":  "/run/spire/credential-composers/audit/audit",
+				"plugin_data": map[string]interface{}{},
+			},
+		},
+	}
+
+	plugins := generateCredentialComposerPlugins(config)
+	if !reflect.DeepEqual(plugins, expected) {
+		t.Errorf("Expected plugins %v, got %v", expected, plugins)
+	}
+
+	if plugins := generateCredentialComposerPlugins(&v1alpha1.CredentialComposersConfig{UniqueID: "false"}); len(plugins) != 0 {
+		t.Errorf("Expected no plugin, got %v", plugins)
+	}
+}
+
+func TestGenerateServerConfMapCredentialComposers(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			ClusterName:     "test-cluster",
+			BundleConfigMap: "spire-bundle",
+		},
+	}
+
+	spec := newCredentialComposerTestSpec()
+	plugins := generateServerConfMap(spec, ztwim)["plugins"].(map[string]interface{})
+	if _, ok := plugins["CredentialComposer"]; ok {
+		t.Error("Expected no CredentialComposer plugin without plugins enabled")
+	}
+
+	spec.CredentialComposers.UniqueID = "true"
+	plugins = generateServerConfMap(spec, ztwim)["plugins"].(map[string]interface{})
+	if composers, ok := plugins["CredentialComposer"].([]map[string]interface{}); !ok || len(composers) != 1 {
+		t.Errorf("Expected the uniqueid CredentialComposer plugin, got %v", plugins["CredentialComposer"])
+	}
+}
+
+func TestAddCredentialComposersToStatefulSet(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "spire-server"}}},
+			},
+		},
+	}
+	addCredentialComposersToStatefulSet(sts, &v1alpha1.CredentialComposersConfig{
+		External: []v1alpha1.ExternalCredentialComposerConfig{
+			{Name: "custom-claims", VolumeName: "plugins", Path: "custom-claims"},
+		},
+	})
+
+	expected := []corev1.VolumeMount{
+		{Name: "plugins", MountPath: "/run/spire/credential-composers/custom-claims", ReadOnly: true},
+	}
+	if mounts := sts.Spec.Template.Spec.Containers[0].VolumeMounts; !reflect.DeepEqual(mounts, expected) {
+		t.Errorf("Expected volume mounts %v, got %v", expected, mounts)
+	}
+}
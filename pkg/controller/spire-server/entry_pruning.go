@@ -0,0 +1,332 @@
+package spire_server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/inspect"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
+)
+
+const (
+	// defaultEntryPruningInterval is the time between two sweeps when spec.entryPruning.interval is unset
+	defaultEntryPruningInterval = time.Hour
+
+	// minEntryPruningInterval is the shortest time between two sweeps
+	minEntryPruningInterval = time.Minute
+
+	// k8sWorkloadSelectorType is the type of the selectors of the k8s workload attestor
+	k8sWorkloadSelectorType = "k8s"
+
+	// prunedEntriesReported is the number of entries listed in the pruning events
+	prunedEntriesReported = 5
+
+	// workloadListPageSize is the number of pods listed per request to the API server
+	workloadListPageSize = 500
+)
+
+// registrationEntry is an entry in the output of the "entry show" command
+type registrationEntry struct {
+	ID        string          `json:"id"`
+	ParentID  spiffeID        `json:"parent_id"`
+	Selectors []spireSelector `json:"selectors"`
+}
+
+// workloadInventory holds the namespaces and pods the k8s selectors of the entries are checked against
+type workloadInventory struct {
+	namespaces map[string]bool
+	podUIDs    map[string]bool
+	podNames   map[string]bool
+	// namespacedPodNames are keyed by <namespace>/<name>
+	namespacedPodNames map[string]bool
+}
+
+// EntryPruningReconciler periodically deletes the registration entries of the SPIRE server whose selectors
+// reference pods or namespaces that no longer exist
+type EntryPruningReconciler struct {
+	ctrlClient    customClient.CustomCtrlClient
+	clientset     kubernetes.Interface
+	eventRecorder record.EventRecorder
+	log           logr.Logger
+
+	// spireServerCLI runs the SPIRE server CLI in the SPIRE server pods, to list and delete the entries
+	spireServerCLI spireServerCLI
+
+	// staleSince holds when each stale entry was first found stale, keyed by entry ID. It is only used by
+	// the single worker of the controller, and restarting the operator restarts the grace periods.
+	staleSince map[string]time.Time
+}
+
+// NewEntryPruningReconciler returns a new EntryPruningReconciler instance.
+func NewEntryPruningReconciler(mgr ctrl.Manager, clientOpts ...customClient.Option) (*EntryPruningReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &EntryPruningReconciler{
+		ctrlClient:     c,
+		clientset:      clientset,
+		eventRecorder:  mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerEntryPruningControllerName),
+		log:            ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerEntryPruningControllerName),
+		spireServerCLI: newSpireServerCLI(clientset, inspect.NewPodExecutor(mgr.GetConfig(), clientset)),
+	}, nil
+}
+
+// entryPruningInterval returns the time between two sweeps
+func entryPruningInterval(config *v1alpha1.EntryPruningConfig) time.Duration {
+	if config.Interval.Duration > 0 {
+		return config.Interval.Duration
+	}
+	return defaultEntryPruningInterval
+}
+
+// validateEntryPruning validates the schedule of the pruning of the stale registration entries
+func validateEntryPruning(config *v1alpha1.EntryPruningConfig) error {
+	if config == nil {
+		return nil
+	}
+	if interval := config.Interval.Duration; interval != 0 && interval < minEntryPruningInterval {
+		return fmt.Errorf("entryPruning.interval %s must be at least %s", interval, minEntryPruningInterval)
+	}
+	if config.GracePeriod.Duration < 0 {
+		return fmt.Errorf("entryPruning.gracePeriod must not be negative")
+	}
+	return nil
+}
+
+// Reconcile sweeps the registration entries of the SPIRE server every entryPruning.interval
+func (r *EntryPruningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var server v1alpha1.SpireServer
+	if err := r.ctrlClient.Get(ctx, req.NamespacedName, &server); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.staleSince = nil
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	config := server.Spec.EntryPruning
+	if config == nil || !server.DeletionTimestamp.IsZero() || utils.StringToBool(server.Spec.Paused) {
+		r.staleSince = nil
+		return ctrl.Result{}, nil
+	}
+	// The invalid schedules are reported in the ConfigurationValid condition of the SpireServer
+	if err := validateEntryPruning(config); err != nil {
+		return ctrl.Result{}, nil
+	}
+
+	var ztwim v1alpha1.ZeroTrustWorkloadIdentityManager
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, &ztwim); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	// The pods and namespaces of this cluster only run the workloads of the server when both the server and
+	// the agents are deployed here
+	if !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireServer) || !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireAgent) {
+		return ctrl.Result{}, nil
+	}
+	if managementState := utils.GetManagementState(server.Spec.ManagementState, &ztwim); managementState != v1alpha1.ManagementStateManaged {
+		return ctrl.Result{}, nil
+	}
+	if err := utils.ConfigureOperandNamespace(ztwim.Spec.OperandNamespace); err != nil {
+		return ctrl.Result{}, nil
+	}
+
+	dryRun := utils.StringToBool(config.DryRun) || utils.IsDryRunMode(server.Spec.ReconcileMode)
+	if err := r.pruneStaleEntries(ctx, &server, &ztwim, dryRun, time.Now()); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: entryPruningInterval(config)}, nil
+}
+
+// pruneStaleEntries deletes the entries parented to the agents of the cluster that have been stale for the
+// grace period, or only reports them in dry-run mode. The entries are listed before the pods and
+// namespaces, so that the entry of a pod created meanwhile is never found stale.
+func (r *EntryPruningReconciler) pruneStaleEntries(ctx context.Context, server *v1alpha1.SpireServer,
+	ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, dryRun bool, now time.Time) error {
+	entries, err := r.listEntries(ctx)
+	if err != nil {
+		return err
+	}
+	inventory, err := r.listWorkloads(ctx)
+	if err != nil {
+		return err
+	}
+
+	agentPathPrefix := "/spire/agent/" + k8sPSATAttestationType + "/" + ztwim.Spec.ClusterName + "/"
+	staleSince := make(map[string]time.Time)
+	var due []string
+	for _, entry := range entries {
+		if entry.ParentID.TrustDomain != ztwim.Spec.TrustDomain || !strings.HasPrefix(entry.ParentID.Path, agentPathPrefix) {
+			continue
+		}
+		reason := inventory.staleReason(entry)
+		if reason == "" {
+			continue
+		}
+		since, seen := r.staleSince[entry.ID]
+		if !seen {
+			since = now
+		}
+		staleSince[entry.ID] = since
+		if now.Sub(since) >= server.Spec.EntryPruning.GracePeriod.Duration {
+			r.log.Info("Found a stale registration entry", "entry", entry.ID, "reason", reason, "dryRun", dryRun)
+			due = append(due, entry.ID)
+		}
+	}
+	// Forget the entries deleted or no longer stale
+	r.staleSince = staleSince
+	if len(due) == 0 {
+		return nil
+	}
+
+	if dryRun {
+		r.eventRecorder.Eventf(server, corev1.EventTypeNormal, "StaleEntriesFound",
+			"Found %d stale registration entries, not pruned in dry-run mode: %s", len(due), summarizeEntries(due))
+		return nil
+	}
+	var pruned []string
+	var pruneErr error
+	for _, entryID := range due {
+		if _, err := r.spireServerCLI(ctx, "entry", "delete", "-entryID", entryID, "-output", "json"); err != nil {
+			pruneErr = fmt.Errorf("failed to delete the stale entry %s: %w", entryID, err)
+			break
+		}
+		delete(r.staleSince, entryID)
+		pruned = append(pruned, entryID)
+	}
+	if len(pruned) > 0 {
+		r.eventRecorder.Eventf(server, corev1.EventTypeNormal, "StaleEntriesPruned",
+			"Pruned %d stale registration entries: %s", len(pruned), summarizeEntries(pruned))
+	}
+	return pruneErr
+}
+
+// listEntries returns the registration entries of the SPIRE server
+func (r *EntryPruningReconciler) listEntries(ctx context.Context) ([]registrationEntry, error) {
+	output, err := r.spireServerCLI(ctx, "entry", "show", "-output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the entries: %w", err)
+	}
+	var list struct {
+		Entries []registrationEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode the entries: %w", err)
+	}
+	return list.Entries, nil
+}
+
+// listWorkloads returns the namespaces and the pods of the cluster. The namespaces are read from the cache
+// of the operator, which watches all of them. Only the metadata of the pods is needed, and the operator
+// doesn't otherwise watch the workloads of the cluster, so they are listed from the API server in pages
+// instead of being cached.
+func (r *EntryPruningReconciler) listWorkloads(ctx context.Context) (*workloadInventory, error) {
+	var namespaces corev1.NamespaceList
+	if err := r.ctrlClient.List(ctx, &namespaces); err != nil {
+		return nil, fmt.Errorf("failed to list the namespaces: %w", err)
+	}
+
+	inventory := &workloadInventory{
+		namespaces:         make(map[string]bool, len(namespaces.Items)),
+		podUIDs:            map[string]bool{},
+		podNames:           map[string]bool{},
+		namespacedPodNames: map[string]bool{},
+	}
+	for _, namespace := range namespaces.Items {
+		inventory.namespaces[namespace.Name] = true
+	}
+
+	continueToken := ""
+	for {
+		pods := &metav1.PartialObjectMetadataList{}
+		pods.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+		if err := r.ctrlClient.APIReader().List(ctx, pods, client.Limit(workloadListPageSize), client.Continue(continueToken)); err != nil {
+			return nil, fmt.Errorf("failed to list the pods: %w", err)
+		}
+		for _, pod := range pods.Items {
+			inventory.podUIDs[string(pod.UID)] = true
+			inventory.podNames[pod.Name] = true
+			inventory.namespacedPodNames[pod.Namespace+"/"+pod.Name] = true
+		}
+		if continueToken = pods.Continue; continueToken == "" {
+			return inventory, nil
+		}
+	}
+}
+
+// staleReason returns why the entry can't match any workload, or "" when it can. The selectors of an entry
+// must all match, so a single selector referencing a missing pod or namespace makes it stale.
+func (i *workloadInventory) staleReason(entry registrationEntry) string {
+	values := map[string]string{}
+	for _, selector := range entry.Selectors {
+		if selector.Type != k8sWorkloadSelectorType {
+			continue
+		}
+		if key, value, ok := strings.Cut(selector.Value, ":"); ok {
+			values[key] = value
+		}
+	}
+
+	namespace, hasNamespace := values["ns"]
+	if hasNamespace && !i.namespaces[namespace] {
+		return fmt.Sprintf("namespace %s doesn't exist", namespace)
+	}
+	if uid, ok := values["pod-uid"]; ok && !i.podUIDs[uid] {
+		return fmt.Sprintf("pod with UID %s doesn't exist", uid)
+	}
+	if name, ok := values["pod-name"]; ok {
+		if hasNamespace && !i.namespacedPodNames[namespace+"/"+name] {
+			return fmt.Sprintf("pod %s/%s doesn't exist", namespace, name)
+		}
+		if !hasNamespace && !i.podNames[name] {
+			return fmt.Sprintf("pod %s doesn't exist", name)
+		}
+	}
+	return ""
+}
+
+// summarizeEntries lists the first entry IDs, followed by the number of the other entries
+func summarizeEntries(entryIDs []string) string {
+	if len(entryIDs) <= prunedEntriesReported {
+		return strings.Join(entryIDs, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(entryIDs[:prunedEntriesReported], ", "), len(entryIDs)-prunedEntriesReported)
+}
+
+func (r *EntryPruningReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The sweeps are scheduled by requeueing the "cluster" SpireServer, and rescheduled when it changes
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.SpireServer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named(utils.ZeroTrustWorkloadIdentityManagerEntryPruningControllerName).
+		WithOptions(controller.Options{
+			RateLimiter: utils.NewRateLimiter(),
+		}).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerEntryPruningControllerName, r))
+}
@@ -0,0 +1,274 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+)
+
+// fakeEntryCLI records the SPIRE server CLI commands and serves the entries of a SPIRE server
+type fakeEntryCLI struct {
+	commands  []string
+	entries   string
+	deleteErr error
+}
+
+func (f *fakeEntryCLI) run(ctx context.Context, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+	if strings.HasPrefix(command, "entry show") {
+		return []byte(f.entries), nil
+	}
+	if f.deleteErr != nil {
+		return nil, f.deleteErr
+	}
+	return []byte(`{}`), nil
+}
+
+// deletedEntries returns the IDs of the entries deleted with the CLI
+func (f *fakeEntryCLI) deletedEntries() []string {
+	var deleted []string
+	for _, command := range f.commands {
+		if entryID, ok := strings.CutPrefix(command, "entry delete -entryID "); ok {
+			deleted = append(deleted, strings.TrimSuffix(entryID, " -output json"))
+		}
+	}
+	return deleted
+}
+
+func TestValidateEntryPruning(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *v1alpha1.EntryPruningConfig
+		expectedErr string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:   "defaults",
+			config: &v1alpha1.EntryPruningConfig{},
+		},
+		{
+			name:        "interval too short",
+			config:      &v1alpha1.EntryPruningConfig{Interval: metav1.Duration{Duration: 30 * time.Second}},
+			expectedErr: "must be at least 1m0s",
+		},
+		{
+			name:        "negative grace period",
+			config:      &v1alpha1.EntryPruningConfig{GracePeriod: metav1.Duration{Duration: -time.Minute}},
+			expectedErr: "must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEntryPruning(tt.config)
+			if tt.expectedErr == "" && err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+			if tt.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestStaleReason(t *testing.T) {
+	inventory := &workloadInventory{
+		namespaces:         map[string]bool{"apps": true},
+		podUIDs:            map[string]bool{"uid-1": true},
+		podNames:           map[string]bool{"web-0": true},
+		namespacedPodNames: map[string]bool{"apps/web-0": true},
+	}
+	tests := []struct {
+		name      string
+		selectors []string
+		stale     bool
+	}{
+		{name: "existing namespace", selectors: []string{"ns:apps", "sa:default"}},
+		{name: "missing namespace", selectors: []string{"ns:gone", "sa:default"}, stale: true},
+		{name: "existing pod UID", selectors: []string{"pod-uid:uid-1"}},
+		{name: "missing pod UID", selectors: []string{"ns:apps", "pod-uid:uid-2"}, stale: true},
+		{name: "existing pod name", selectors: []string{"ns:apps", "pod-name:web-0"}},
+		{name: "pod name in another namespace", selectors: []string{"ns:default", "pod-name:web-0"}, stale: true},
+		{name: "missing pod name without namespace", selectors: []string{"pod-name:web-1"}, stale: true},
+		{name: "no workload reference", selectors: []string{"sa:default", "pod-label:app:web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := registrationEntry{ID: "entry"}
+			for _, value := range tt.selectors {
+				entry.Selectors = append(entry.Selectors, spireSelector{Type: k8sWorkloadSelectorType, Value: value})
+			}
+			if reason := inventory.staleReason(entry); (reason != "") != tt.stale {
+				t.Errorf("Expected stale %t, got reason %q", tt.stale, reason)
+			}
+		})
+	}
+}
+
+func TestPruneStaleEntries(t *testing.T) {
+	const entries = `{"entries":[
+		{"id":"live","parent_id":{"trust_domain":"example.org","path":"/spire/agent/k8s_psat/demo/node-1"},"selectors":[{"type":"k8s","value":"ns:apps"}]},
+		{"id":"stale","parent_id":{"trust_domain":"example.org","path":"/spire/agent/k8s_psat/demo/node-1"},"selectors":[{"type":"k8s","value":"ns:gone"}]},
+		{"id":"other-cluster","parent_id":{"trust_domain":"example.org","path":"/spire/agent/k8s_psat/other/node-1"},"selectors":[{"type":"k8s","value":"ns:gone"}]}
+	]}`
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "demo"},
+	}
+	newReconciler := func(cli *fakeEntryCLI) (*EntryPruningReconciler, *record.FakeRecorder) {
+		recorder := record.NewFakeRecorder(10)
+		scheme := runtime.NewScheme()
+		_ = corev1.AddToScheme(scheme)
+		workloads := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "apps", UID: types.UID("uid-1")}},
+		).Build()
+		ctrlClient := &fakes.FakeCustomCtrlClient{}
+		ctrlClient.ListStub = workloads.List
+		ctrlClient.APIReaderReturns(workloads)
+		return &EntryPruningReconciler{
+			ctrlClient:     ctrlClient,
+			eventRecorder:  recorder,
+			log:            ctrl.Log.WithName("test"),
+			spireServerCLI: cli.run,
+		}, recorder
+	}
+	newServer := func(dryRun string) *v1alpha1.SpireServer {
+		server := createTestSpireServer()
+		server.Spec.EntryPruning = &v1alpha1.EntryPruningConfig{GracePeriod: metav1.Duration{Duration: time.Hour}, DryRun: dryRun}
+		return server
+	}
+
+	t.Run("prunes the entries stale for the grace period", func(t *testing.T) {
+		cli := &fakeEntryCLI{entries: entries}
+		reconciler, recorder := newReconciler(cli)
+		server := newServer("false")
+
+		if err := reconciler.pruneStaleEntries(context.Background(), server, ztwim, false, now); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if deleted := cli.deletedEntries(); len(deleted) != 0 {
+			t.Errorf("Expected no entry to be pruned within the grace period, got %v", deleted)
+		}
+		if err := reconciler.pruneStaleEntries(context.Background(), server, ztwim, false, now.Add(time.Hour)); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if deleted := cli.deletedEntries(); strings.Join(deleted, ",") != "stale" {
+			t.Errorf("Expected the stale entry to be pruned, got %v", deleted)
+		}
+		if event := <-recorder.Events; !strings.Contains(event, "StaleEntriesPruned") {
+			t.Errorf("Expected a StaleEntriesPruned event, got %q", event)
+		}
+		if _, tracked := reconciler.staleSince["stale"]; tracked {
+			t.Error("Expected the pruned entry to be forgotten")
+		}
+	})
+
+	t.Run("only reports the stale entries in dry-run mode", func(t *testing.T) {
+		cli := &fakeEntryCLI{entries: entries}
+		reconciler, recorder := newReconciler(cli)
+		server := newServer("true")
+		reconciler.staleSince = map[string]time.Time{"stale": now.Add(-2 * time.Hour)}
+
+		if err := reconciler.pruneStaleEntries(context.Background(), server, ztwim, true, now); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if deleted := cli.deletedEntries(); len(deleted) != 0 {
+			t.Errorf("Expected no entry to be pruned in dry-run mode, got %v", deleted)
+		}
+		if event := <-recorder.Events; !strings.Contains(event, "StaleEntriesFound") || !strings.Contains(event, "stale") {
+			t.Errorf("Expected a StaleEntriesFound event, got %q", event)
+		}
+	})
+
+	t.Run("retries a failed deletion", func(t *testing.T) {
+		cli := &fakeEntryCLI{entries: entries, deleteErr: errors.New("datastore unavailable")}
+		reconciler, _ := newReconciler(cli)
+		reconciler.staleSince = map[string]time.Time{"stale": now.Add(-2 * time.Hour)}
+
+		if err := reconciler.pruneStaleEntries(context.Background(), newServer("false"), ztwim, false, now); err == nil {
+			t.Fatal("Expected the failed deletion to be returned")
+		}
+		if since := reconciler.staleSince["stale"]; !since.Equal(now.Add(-2 * time.Hour)) {
+			t.Errorf("Expected the entry to stay due, got stale since %s", since)
+		}
+	})
+}
+
+// pagedPodReader serves the pod metadata in pages of one pod, and records the list options it receives
+type pagedPodReader struct {
+	client.Reader
+	pods    []string
+	options []client.ListOptions
+}
+
+func (r *pagedPodReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	options := client.ListOptions{}
+	options.ApplyOptions(opts)
+	r.options = append(r.options, options)
+
+	page := 0
+	if options.Continue != "" {
+		page = int(options.Continue[0] - '0')
+	}
+	pods := list.(*metav1.PartialObjectMetadataList)
+	pods.Items = []metav1.PartialObjectMetadata{{ObjectMeta: metav1.ObjectMeta{
+		Name: r.pods[page], Namespace: "apps", UID: types.UID("uid-" + r.pods[page]),
+	}}}
+	if page+1 < len(r.pods) {
+		pods.Continue = string(rune('0' + page + 1))
+	}
+	return nil
+}
+
+func TestListWorkloadsPages(t *testing.T) {
+	reader := &pagedPodReader{pods: []string{"web-0", "web-1", "web-2"}}
+	ctrlClient := &fakes.FakeCustomCtrlClient{}
+	ctrlClient.APIReaderReturns(reader)
+	reconciler := &EntryPruningReconciler{ctrlClient: ctrlClient}
+
+	inventory, err := reconciler.listWorkloads(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(reader.options) != 3 {
+		t.Fatalf("Expected a request per page, got %d", len(reader.options))
+	}
+	for i, options := range reader.options {
+		if options.Limit != workloadListPageSize {
+			t.Errorf("Expected request %d to be limited to %d pods, got %d", i, workloadListPageSize, options.Limit)
+		}
+	}
+	for _, pod := range reader.pods {
+		if !inventory.namespacedPodNames["apps/"+pod] || !inventory.podUIDs["uid-"+pod] {
+			t.Errorf("Expected pod %s of every page in the inventory", pod)
+		}
+	}
+}
+
+func TestSummarizeEntries(t *testing.T) {
+	if got := summarizeEntries([]string{"a", "b"}); got != "a, b" {
+		t.Errorf("Expected all the entries to be listed, got %q", got)
+	}
+	if got := summarizeEntries([]string{"a", "b", "c", "d", "e", "f", "g"}); got != "a, b, c, d, e and 2 more" {
+		t.Errorf("Expected the first entries to be listed, got %q", got)
+	}
+}
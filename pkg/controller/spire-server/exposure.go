@@ -0,0 +1,180 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// serverExposureName is the name of the LoadBalancer Service or the Route publishing the SPIRE server API
+const serverExposureName = "spire-server-external"
+
+// validateServerExposure checks that the advertised address can be used by the agents outside of the cluster
+func validateServerExposure(config *v1alpha1.ServerExposureConfig) error {
+	if config == nil || config.AdvertisedAddress == "" {
+		return nil
+	}
+	address := config.AdvertisedAddress
+	if net.ParseIP(address) != nil {
+		if config.Type == v1alpha1.ServerExposureRoute {
+			return fmt.Errorf("exposure.advertisedAddress %s must be a host name with the Route type", address)
+		}
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(address); len(errs) > 0 {
+		return fmt.Errorf("exposure.advertisedAddress %s must be a host name or an IP address: %v", address, errs)
+	}
+	return nil
+}
+
+// serverExposurePort returns the port the agents outside of the cluster connect to
+func serverExposurePort(config *v1alpha1.ServerExposureConfig) int32 {
+	if config.Type == v1alpha1.ServerExposureLoadBalancer && config.LoadBalancer != nil && config.LoadBalancer.Port > 0 {
+		return config.LoadBalancer.Port
+	}
+	return 443
+}
+
+// generateServerExposureService returns the LoadBalancer Service publishing the agent-facing API of the SPIRE server
+func generateServerExposureService(config *v1alpha1.SpireServerSpec) *corev1.Service {
+	exposure := config.Exposure
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serverExposureName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.SpireServerLabels(config.Labels),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeLoadBalancer,
+			Selector: getSpireServerService(config).Spec.Selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "grpc",
+					Port:       serverExposurePort(exposure),
+					TargetPort: intstr.FromString("grpc"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+	if exposure.LoadBalancer != nil {
+		svc.Annotations = exposure.LoadBalancer.Annotations
+		svc.Spec.LoadBalancerSourceRanges = exposure.LoadBalancer.SourceRanges
+	}
+	return svc
+}
+
+// generateServerExposureRoute returns the Route publishing the agent-facing API of the SPIRE server. The
+// TLS connections are passed through to the SPIRE server, which authenticates the agents itself.
+func generateServerExposureRoute(config *v1alpha1.SpireServerSpec) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serverExposureName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.SpireServerLabels(config.Labels),
+		},
+		Spec: routev1.RouteSpec{
+			Host: config.Exposure.AdvertisedAddress,
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   "spire-server",
+				Weight: ptr.To(int32(100)),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString("grpc"),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationPassthrough,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyNone,
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+}
+
+// loadBalancerAddress returns the address assigned to the LoadBalancer Service, empty while pending
+func loadBalancerAddress(svc *corev1.Service) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return ""
+}
+
+// reconcileServerExposure publishes the agent-facing API of the SPIRE server outside of the cluster when
+// configured, and reports the address the agents connect to in status.advertisedAddress. The resources of a
+// previous configuration are no longer tracked and are pruned with the other orphaned resources.
+func (r *SpireServerReconciler) reconcileServerExposure(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) error {
+	exposure := server.Spec.Exposure
+	if exposure == nil {
+		// Exposure disabled - clear the advertised address, don't set status
+		if server.Status.AdvertisedAddress != "" {
+			server.Status.AdvertisedAddress = ""
+			statusMgr.ForceStatusUpdate()
+		}
+		return nil
+	}
+
+	var address, pendingMessage string
+	switch exposure.Type {
+	case v1alpha1.ServerExposureRoute:
+		route := generateServerExposureRoute(&server.Spec)
+		existingRoute := &routev1.Route{}
+		if err := r.reconcileOwnedResource(ctx, server, statusMgr, ServerExposureAvailable, route, existingRoute, func() bool {
+			// Keep the host generated by the router when none is configured
+			if route.Spec.Host == "" {
+				route.Spec.Host = existingRoute.Spec.Host
+			}
+			return checkFederationRouteConflict(existingRoute, route)
+		}, createOnlyMode); err != nil {
+			return err
+		}
+		address = existingRoute.Spec.Host
+		pendingMessage = "Waiting for the router to assign a host to the Route"
+	default:
+		svc := generateServerExposureService(&server.Spec)
+		existingService := &corev1.Service{}
+		if err := r.reconcileOwnedResource(ctx, server, statusMgr, ServerExposureAvailable, svc, existingService, func() bool {
+			preserveServiceFields(existingService, svc)
+			return utils.ResourceNeedsUpdate(existingService, svc)
+		}, createOnlyMode); err != nil {
+			return err
+		}
+		address = loadBalancerAddress(existingService)
+		pendingMessage = "Waiting for the load balancer to assign an address to the Service"
+	}
+	if exposure.AdvertisedAddress != "" {
+		address = exposure.AdvertisedAddress
+	}
+
+	advertisedAddress := ""
+	if address != "" {
+		advertisedAddress = net.JoinHostPort(address, strconv.Itoa(int(serverExposurePort(exposure))))
+		statusMgr.AddCondition(ServerExposureAvailable, "ServerExposed",
+			fmt.Sprintf("The SPIRE server API is published at %s", advertisedAddress),
+			metav1.ConditionTrue)
+	} else {
+		statusMgr.AddCondition(ServerExposureAvailable, "ExposureAddressPending", pendingMessage, metav1.ConditionFalse)
+	}
+	if server.Status.AdvertisedAddress != advertisedAddress {
+		server.Status.AdvertisedAddress = advertisedAddress
+		statusMgr.ForceStatusUpdate()
+	}
+	return nil
+}
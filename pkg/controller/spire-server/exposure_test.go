@@ -0,0 +1,272 @@
+package spire_server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func newExposureTestServer(exposure *v1alpha1.ServerExposureConfig) *v1alpha1.SpireServer {
+	server := createTestSpireServer()
+	server.UID = "test-uid"
+	server.Spec.Persistence = v1alpha1.Persistence{Size: "1Gi", AccessMode: "ReadWriteOnce"}
+	server.Spec.Exposure = exposure
+	return server
+}
+
+func TestValidateServerExposure(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *v1alpha1.ServerExposureConfig
+		expectedErr string
+	}{
+		{name: "unset"},
+		{
+			name:   "load balancer without advertised address",
+			config: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer},
+		},
+		{
+			name:   "load balancer with an IP address",
+			config: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer, AdvertisedAddress: "203.0.113.10"},
+		},
+		{
+			name:   "route with a host name",
+			config: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureRoute, AdvertisedAddress: "spire.apps.example.com"},
+		},
+		{
+			name:        "route with an IP address",
+			config:      &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureRoute, AdvertisedAddress: "203.0.113.10"},
+			expectedErr: "must be a host name with the Route type",
+		},
+		{
+			name:        "invalid host name",
+			config:      &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer, AdvertisedAddress: "Spire_Server"},
+			expectedErr: "must be a host name or an IP address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateServerExposure(tt.config)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGenerateServerExposureService(t *testing.T) {
+	server := newExposureTestServer(&v1alpha1.ServerExposureConfig{
+		Type: v1alpha1.ServerExposureLoadBalancer,
+		LoadBalancer: &v1alpha1.LoadBalancerExposureConfig{
+			Port:         8081,
+			Annotations:  map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+			SourceRanges: []string{"10.0.0.0/8"},
+		},
+	})
+	svc := generateServerExposureService(&server.Spec)
+
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		t.Errorf("Expected a LoadBalancer Service, got %s", svc.Spec.Type)
+	}
+	if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Port != 8081 || svc.Spec.Ports[0].TargetPort.String() != "grpc" {
+		t.Errorf("Expected port 8081 targeting the grpc port, got %v", svc.Spec.Ports)
+	}
+	if svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"] != "true" {
+		t.Errorf("Expected the load balancer annotations, got %v", svc.Annotations)
+	}
+	if len(svc.Spec.LoadBalancerSourceRanges) != 1 || svc.Spec.LoadBalancerSourceRanges[0] != "10.0.0.0/8" {
+		t.Errorf("Expected the source ranges, got %v", svc.Spec.LoadBalancerSourceRanges)
+	}
+	sts := GenerateSpireServerStatefulSet(&server.Spec, "", "")
+	for k, v := range svc.Spec.Selector {
+		if sts.Spec.Template.Labels[k] != v {
+			t.Errorf("Expected selector label %s=%s to match the SPIRE server pods", k, v)
+		}
+	}
+}
+
+func TestGenerateServerExposureRoute(t *testing.T) {
+	server := newExposureTestServer(&v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureRoute, AdvertisedAddress: "spire.apps.example.com"})
+	route := generateServerExposureRoute(&server.Spec)
+
+	if route.Spec.Host != "spire.apps.example.com" {
+		t.Errorf("Expected host spire.apps.example.com, got %s", route.Spec.Host)
+	}
+	if route.Spec.To.Name != "spire-server" || route.Spec.Port.TargetPort.String() != "grpc" {
+		t.Errorf("Expected the Route to target the grpc port of the spire-server Service, got %v", route.Spec)
+	}
+	if route.Spec.TLS == nil || route.Spec.TLS.Termination != routev1.TLSTerminationPassthrough {
+		t.Errorf("Expected passthrough TLS termination, got %v", route.Spec.TLS)
+	}
+}
+
+func TestGenerateServerConfMapJoinToken(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+	nodeAttestors := func(spec *v1alpha1.SpireServerSpec) []string {
+		var names []string
+		for _, attestor := range generateServerConfMap(spec, ztwim)["plugins"].(map[string]interface{})["NodeAttestor"].([]map[string]interface{}) {
+			for name := range attestor {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	server := newExposureTestServer(&v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer, JoinToken: "false"})
+	if names := nodeAttestors(&server.Spec); len(names) != 1 || names[0] != "k8s_psat" {
+		t.Errorf("Expected only the k8s_psat node attestor, got %v", names)
+	}
+
+	server.Spec.Exposure.JoinToken = "true"
+	if names := nodeAttestors(&server.Spec); len(names) != 2 || names[1] != "join_token" {
+		t.Errorf("Expected the k8s_psat and join_token node attestors, got %v", names)
+	}
+}
+
+func TestGenerateSpireServerNetworkPolicyExposure(t *testing.T) {
+	server := newExposureTestServer(nil)
+	rules := len(generateSpireServerNetworkPolicy(&server.Spec).Spec.Ingress)
+
+	server.Spec.Exposure = &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer}
+	policy := generateSpireServerNetworkPolicy(&server.Spec)
+	if len(policy.Spec.Ingress) != rules+1 {
+		t.Fatalf("Expected one more ingress rule, got %d", len(policy.Spec.Ingress))
+	}
+	rule := policy.Spec.Ingress[rules]
+	if len(rule.From) != 0 {
+		t.Errorf("Expected the published API to be open to any client, got %v", rule.From)
+	}
+	if len(rule.Ports) != 1 || rule.Ports[0].Port.String() != "grpc" {
+		t.Errorf("Expected the grpc port, got %v", rule.Ports)
+	}
+}
+
+func TestReconcileServerExposure(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, serverExposureName)
+
+	tests := []struct {
+		name            string
+		exposure        *v1alpha1.ServerExposureConfig
+		previousAddress string
+		setupClient     func(*fakes.FakeCustomCtrlClient, *v1alpha1.SpireServer)
+		expectCreate    int
+		expectUpdate    int
+		expectAddress   string
+		expectReason    string
+	}{
+		{
+			name:            "disabled clears the advertised address",
+			previousAddress: "203.0.113.10:443",
+		},
+		{
+			name:     "load balancer created, address pending",
+			exposure: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, _ *v1alpha1.SpireServer) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+			expectReason: "ExposureAddressPending",
+		},
+		{
+			name:     "load balancer address assigned",
+			exposure: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer, LoadBalancer: &v1alpha1.LoadBalancerExposureConfig{Port: 8081}},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, server *v1alpha1.SpireServer) {
+				fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					svc := obj.(*corev1.Service)
+					*svc = *generateServerExposureService(&server.Spec)
+					svc.Spec.Ports[0].NodePort = 31000
+					svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+					return nil
+				}
+			},
+			expectAddress: "203.0.113.10:8081",
+			expectReason:  "ServerExposed",
+		},
+		{
+			name:     "route keeps the host generated by the router",
+			exposure: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureRoute},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, server *v1alpha1.SpireServer) {
+				fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					route := obj.(*routev1.Route)
+					*route = *generateServerExposureRoute(&server.Spec)
+					route.Spec.Host = "spire-server-external.apps.example.com"
+					return nil
+				}
+			},
+			expectAddress: "spire-server-external.apps.example.com:443",
+			expectReason:  "ServerExposed",
+		},
+		{
+			name:     "advertised address overrides the assigned address",
+			exposure: &v1alpha1.ServerExposureConfig{Type: v1alpha1.ServerExposureLoadBalancer, AdvertisedAddress: "spire.example.com"},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, _ *v1alpha1.SpireServer) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate:  1,
+			expectAddress: "spire.example.com:443",
+			expectReason:  "ServerExposed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			server := newExposureTestServer(tt.exposure)
+			server.Status.AdvertisedAddress = tt.previousAddress
+			if tt.setupClient != nil {
+				tt.setupClient(fakeClient, server)
+			}
+			reconciler := newTornjakTestReconciler(fakeClient)
+			statusMgr := status.NewManager(fakeClient)
+
+			if err := reconciler.reconcileServerExposure(context.Background(), server, statusMgr, false); err != nil {
+				t.Fatalf("reconcileServerExposure() error = %v", err)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if server.Status.AdvertisedAddress != tt.expectAddress {
+				t.Errorf("Expected advertised address %q, got %q", tt.expectAddress, server.Status.AdvertisedAddress)
+			}
+
+			if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+				return &server.Status.ConditionalStatus
+			}); err != nil {
+				t.Fatalf("ApplyStatus() error = %v", err)
+			}
+			cond := apimeta.FindStatusCondition(server.Status.Conditions, ServerExposureAvailable)
+			if tt.expectReason == "" {
+				if cond != nil {
+					t.Errorf("Expected no ServerExposureAvailable condition, got %+v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Reason != tt.expectReason {
+				t.Errorf("Expected ServerExposureAvailable with reason %s, got %+v", tt.expectReason, cond)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package spire_server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// federatedBundleRefreshInterval is how often the bundle endpoints of the federated trust domains
+	// are probed, matching the default refresh hint of the SPIRE bundle endpoints
+	federatedBundleRefreshInterval = 5 * time.Minute
+
+	// federatedBundleFetchTimeout bounds the fetch of the bundle of a federated trust domain
+	federatedBundleFetchTimeout = 10 * time.Second
+
+	// maxFederatedBundleSize bounds the size of the bundle read from a bundle endpoint
+	maxFederatedBundleSize = 1 << 20
+)
+
+// fetchFederatedBundle fetches the bundle served by the bundle endpoint of a federated trust domain.
+// It is a variable so that the tests don't reach the network.
+var fetchFederatedBundle = fetchBundleFromEndpoint
+
+// spiffeBundle is the part of a SPIFFE bundle document read to check the bundle endpoint certificate
+type spiffeBundle struct {
+	Keys []struct {
+		Use string   `json:"use"`
+		X5c []string `json:"x5c"`
+	} `json:"keys"`
+}
+
+// fetchBundleFromEndpoint fetches the bundle served by the bundle endpoint of federatesWith. The
+// https_web endpoints are authenticated with the system roots. The https_spiffe endpoints serve an
+// SVID of the federated trust domain, which the operator has no bundle to authenticate: the SVID is
+// only checked against the fetched bundle and the endpoint SPIFFE ID, as the status is informational
+// and the SPIRE server authenticates the endpoint with the bundle it already trusts.
+func fetchBundleFromEndpoint(ctx context.Context, federatesWith v1alpha1.FederatesWithConfig) ([]byte, error) {
+	spiffeProfile := federatesWith.BundleEndpointProfile == v1alpha1.HttpsSpiffeProfile
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				MinVersion:         tls.VersionTLS12,
+				InsecureSkipVerify: spiffeProfile,
+			},
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, federatesWith.BundleEndpointUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bundle endpoint returned %s", resp.Status)
+	}
+	bundle, err := io.ReadAll(io.LimitReader(resp.Body, maxFederatedBundleSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the bundle: %w", err)
+	}
+
+	authorities, err := parseX509Authorities(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if spiffeProfile {
+		if resp.TLS == nil {
+			return nil, fmt.Errorf("bundle endpoint did not serve a certificate")
+		}
+		if err := verifyBundleEndpointSVID(resp.TLS.PeerCertificates, authorities, federatesWith.EndpointSpiffeId); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+// parseX509Authorities returns the X.509 authorities of a SPIFFE bundle document
+func parseX509Authorities(bundle []byte) ([]*x509.Certificate, error) {
+	var document spiffeBundle
+	if err := json.Unmarshal(bundle, &document); err != nil {
+		return nil, fmt.Errorf("invalid SPIFFE bundle: %w", err)
+	}
+	var authorities []*x509.Certificate
+	for _, key := range document.Keys {
+		if key.Use != "x509-svid" {
+			continue
+		}
+		for _, encoded := range key.X5c {
+			der, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid X.509 authority in the SPIFFE bundle: %w", err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return nil, fmt.Errorf("invalid X.509 authority in the SPIFFE bundle: %w", err)
+			}
+			authorities = append(authorities, cert)
+		}
+	}
+	if len(authorities) == 0 {
+		return nil, fmt.Errorf("SPIFFE bundle has no X.509 authorities")
+	}
+	return authorities, nil
+}
+
+// verifyBundleEndpointSVID checks that the certificate served by an https_spiffe bundle endpoint is
+// an SVID for endpointSpiffeID chaining to the authorities of the served bundle
+func verifyBundleEndpointSVID(peerCertificates, authorities []*x509.Certificate, endpointSpiffeID string) error {
+	if len(peerCertificates) == 0 {
+		return fmt.Errorf("bundle endpoint did not serve a certificate")
+	}
+	roots := x509.NewCertPool()
+	for _, authority := range authorities {
+		roots.AddCert(authority)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range peerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	leaf := peerCertificates[0]
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("bundle endpoint certificate is not signed by the served bundle: %w", err)
+	}
+	for _, uri := range leaf.URIs {
+		if uri.String() == endpointSpiffeID {
+			return nil
+		}
+	}
+	return fmt.Errorf("bundle endpoint certificate is not an SVID for %s", endpointSpiffeID)
+}
+
+// probeFederatedTrustDomain fetches the bundle of federatesWith and returns its status. The last
+// successful refresh of the same endpoint is kept when the fetch fails, so that stale bundles can be
+// alerted on.
+func probeFederatedTrustDomain(ctx context.Context, federatesWith v1alpha1.FederatesWithConfig, previous *v1alpha1.FederatedTrustDomainStatus, now metav1.Time) v1alpha1.FederatedTrustDomainStatus {
+	result := v1alpha1.FederatedTrustDomainStatus{
+		TrustDomain:       federatesWith.TrustDomain,
+		BundleEndpointUrl: federatesWith.BundleEndpointUrl,
+	}
+	if previous != nil && previous.BundleEndpointUrl == federatesWith.BundleEndpointUrl {
+		result.LastSuccessfulRefreshTime = previous.LastSuccessfulRefreshTime
+		result.BundleSHA256 = previous.BundleSHA256
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, federatedBundleFetchTimeout)
+	defer cancel()
+	bundle, err := fetchFederatedBundle(fetchCtx, federatesWith)
+	if err != nil {
+		result.Healthy = "false"
+		result.Message = err.Error()
+		return result
+	}
+	sum := sha256.Sum256(bundle)
+	result.Healthy = "true"
+	result.LastSuccessfulRefreshTime = &now
+	result.BundleSHA256 = hex.EncodeToString(sum[:])
+	return result
+}
+
+// reconcileFederationStatus probes the bundle endpoint of each federated trust domain and reports
+// their health in status.federatedTrustDomains and in the FederatedBundlesHealthy condition. The
+// bundles refreshed within federatedBundleRefreshInterval are not fetched again. It returns when the
+// bundles are due for a refresh, zero when no trust domain is federated.
+func (r *SpireServerReconciler) reconcileFederationStatus(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager) time.Duration {
+	var federatesWith []v1alpha1.FederatesWithConfig
+	if server.Spec.Federation != nil {
+		federatesWith = server.Spec.Federation.FederatesWith
+	}
+	if len(federatesWith) == 0 {
+		if len(server.Status.FederatedTrustDomains) > 0 {
+			server.Status.FederatedTrustDomains = nil
+			statusMgr.ForceStatusUpdate()
+		}
+		return 0
+	}
+
+	previous := make(map[string]*v1alpha1.FederatedTrustDomainStatus, len(server.Status.FederatedTrustDomains))
+	for i := range server.Status.FederatedTrustDomains {
+		previous[server.Status.FederatedTrustDomains[i].TrustDomain] = &server.Status.FederatedTrustDomains[i]
+	}
+
+	now := metav1.Now()
+	statuses := make([]v1alpha1.FederatedTrustDomainStatus, len(federatesWith))
+	var wg sync.WaitGroup
+	for i, fedTrust := range federatesWith {
+		prev := previous[fedTrust.TrustDomain]
+		if prev != nil && prev.BundleEndpointUrl == fedTrust.BundleEndpointUrl && prev.Healthy == "true" &&
+			prev.LastSuccessfulRefreshTime != nil && now.Sub(prev.LastSuccessfulRefreshTime.Time) < federatedBundleRefreshInterval {
+			statuses[i] = *prev
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = probeFederatedTrustDomain(ctx, fedTrust, prev, now)
+		}()
+	}
+	wg.Wait()
+
+	nextRefresh := federatedBundleRefreshInterval
+	var unhealthy []string
+	for _, fedStatus := range statuses {
+		if fedStatus.Healthy != "true" {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", fedStatus.TrustDomain, fedStatus.Message))
+			continue
+		}
+		if due := federatedBundleRefreshInterval - now.Sub(fedStatus.LastSuccessfulRefreshTime.Time); due < nextRefresh {
+			nextRefresh = due
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		r.log.Info("failed to refresh the bundles of federated trust domains", "trustDomains", unhealthy)
+		statusMgr.AddCondition(utils.FederatedBundlesHealthyStatusType, "FederatedBundleEndpointUnhealthy",
+			fmt.Sprintf("Failed to refresh the bundles of %d/%d federated trust domains: %s",
+				len(unhealthy), len(statuses), strings.Join(unhealthy, "; ")),
+			metav1.ConditionFalse)
+	} else {
+		statusMgr.AddCondition(utils.FederatedBundlesHealthyStatusType, "FederatedBundlesRefreshed",
+			fmt.Sprintf("The bundles of all %d federated trust domains were refreshed", len(statuses)),
+			metav1.ConditionTrue)
+	}
+
+	if !equality.Semantic.DeepEqual(server.Status.FederatedTrustDomains, statuses) {
+		server.Status.FederatedTrustDomains = statuses
+		statusMgr.ForceStatusUpdate()
+	}
+	return nextRefresh
+}
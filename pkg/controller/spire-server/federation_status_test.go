@@ -0,0 +1,273 @@
+package spire_server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// newFederatedTrustDomainCredentials returns the bundle document of a trust domain with a single
+// X.509 authority, and a serving certificate of its bundle endpoint with the SPIFFE ID endpointID
+func newFederatedTrustDomainCredentials(t *testing.T, endpointID string) ([]byte, tls.Certificate) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the CA key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create the CA certificate: %v", err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the leaf key: %v", err)
+	}
+	spiffeID, _ := url.Parse(endpointID)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{spiffeID},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create the leaf certificate: %v", err)
+	}
+
+	bundle, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{"use": "x509-svid", "kty": "EC", "x5c": []string{base64.StdEncoding.EncodeToString(caDER)}},
+			{"use": "jwt-svid", "kty": "EC", "kid": "jwt-key"},
+		},
+		"spiffe_refresh_hint": 300,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal the bundle: %v", err)
+	}
+	return bundle, tls.Certificate{Certificate: [][]byte{leafDER}, PrivateKey: leafKey}
+}
+
+func TestFetchBundleFromEndpoint(t *testing.T) {
+	const endpointID = "spiffe://partner.example.org/spire/server"
+	bundle, cert := newFederatedTrustDomainCredentials(t, endpointID)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bundle)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		federatesWith v1alpha1.FederatesWithConfig
+		expectedErr   string
+	}{
+		{
+			name: "https_spiffe endpoint serving an SVID of its bundle",
+			federatesWith: v1alpha1.FederatesWithConfig{
+				BundleEndpointUrl:     server.URL,
+				BundleEndpointProfile: v1alpha1.HttpsSpiffeProfile,
+				EndpointSpiffeId:      endpointID,
+			},
+		},
+		{
+			name: "https_spiffe endpoint serving an SVID for another SPIFFE ID",
+			federatesWith: v1alpha1.FederatesWithConfig{
+				BundleEndpointUrl:     server.URL,
+				BundleEndpointProfile: v1alpha1.HttpsSpiffeProfile,
+				EndpointSpiffeId:      "spiffe://partner.example.org/other",
+			},
+			expectedErr: "is not an SVID for spiffe://partner.example.org/other",
+		},
+		{
+			name: "https_web endpoint with a certificate not signed by the system roots",
+			federatesWith: v1alpha1.FederatesWithConfig{
+				BundleEndpointUrl:     server.URL,
+				BundleEndpointProfile: v1alpha1.HttpsWebProfile,
+			},
+			expectedErr: "certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched, err := fetchBundleFromEndpoint(context.Background(), tt.federatesWith)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if string(fetched) != string(bundle) {
+				t.Errorf("Expected the served bundle, got %s", fetched)
+			}
+		})
+	}
+}
+
+func TestParseX509Authorities(t *testing.T) {
+	bundle, _ := newFederatedTrustDomainCredentials(t, "spiffe://partner.example.org/spire/server")
+	authorities, err := parseX509Authorities(bundle)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(authorities) != 1 {
+		t.Errorf("Expected 1 X.509 authority, got %d", len(authorities))
+	}
+
+	if _, err := parseX509Authorities([]byte(`{"keys":[{"use":"jwt-svid"}]}`)); err == nil {
+		t.Error("Expected an error for a bundle without X.509 authorities")
+	}
+	if _, err := parseX509Authorities([]byte(`<html></html>`)); err == nil {
+		t.Error("Expected an error for a document that is not a SPIFFE bundle")
+	}
+}
+
+func TestReconcileFederationStatus(t *testing.T) {
+	originalFetch := fetchFederatedBundle
+	t.Cleanup(func() { fetchFederatedBundle = originalFetch })
+
+	var fetchedMu sync.Mutex
+	fetched := map[string]int{}
+	fetchFederatedBundle = func(ctx context.Context, federatesWith v1alpha1.FederatesWithConfig) ([]byte, error) {
+		fetchedMu.Lock()
+		defer fetchedMu.Unlock()
+		fetched[federatesWith.TrustDomain]++
+		if federatesWith.TrustDomain == "down.example.org" {
+			return nil, errors.New("connection refused")
+		}
+		return []byte(`{"keys":[]}`), nil
+	}
+
+	newServer := func(federatesWith ...v1alpha1.FederatesWithConfig) *v1alpha1.SpireServer {
+		server := createTestSpireServer()
+		server.Spec.Federation = &v1alpha1.FederationConfig{
+			BundleEndpoint: v1alpha1.BundleEndpointConfig{Profile: v1alpha1.HttpsSpiffeProfile},
+			FederatesWith:  federatesWith,
+		}
+		return server
+	}
+	up := v1alpha1.FederatesWithConfig{TrustDomain: "up.example.org", BundleEndpointUrl: "https://up.example.org:8443"}
+	down := v1alpha1.FederatesWithConfig{TrustDomain: "down.example.org", BundleEndpointUrl: "https://down.example.org:8443"}
+
+	t.Run("reports the health of each federated trust domain", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newConfigMapTestReconciler(fakeClient)
+		server := newServer(up, down)
+		lastRefresh := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		server.Status.FederatedTrustDomains = []v1alpha1.FederatedTrustDomainStatus{{
+			TrustDomain:               down.TrustDomain,
+			BundleEndpointUrl:         down.BundleEndpointUrl,
+			Healthy:                   "true",
+			LastSuccessfulRefreshTime: &lastRefresh,
+			BundleSHA256:              "previous",
+		}}
+
+		statusMgr := status.NewManager(fakeClient)
+		if next := reconciler.reconcileFederationStatus(context.Background(), server, statusMgr); next != federatedBundleRefreshInterval {
+			t.Errorf("Expected the next refresh in %s, got %s", federatedBundleRefreshInterval, next)
+		}
+
+		statuses := server.Status.FederatedTrustDomains
+		if len(statuses) != 2 {
+			t.Fatalf("Expected 2 federated trust domain statuses, got %d", len(statuses))
+		}
+		if statuses[0].Healthy != "true" || statuses[0].LastSuccessfulRefreshTime == nil || len(statuses[0].BundleSHA256) != 64 {
+			t.Errorf("Expected up.example.org to be healthy with a bundle digest, got %+v", statuses[0])
+		}
+		if statuses[1].Healthy != "false" || statuses[1].Message != "connection refused" {
+			t.Errorf("Expected down.example.org to be unhealthy, got %+v", statuses[1])
+		}
+		if !statuses[1].LastSuccessfulRefreshTime.Equal(&lastRefresh) || statuses[1].BundleSHA256 != "previous" {
+			t.Errorf("Expected the last successful refresh of down.example.org to be kept, got %+v", statuses[1])
+		}
+
+		if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+			return &server.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.FederatedBundlesHealthyStatusType)
+		if cond == nil || cond.Status != metav1.ConditionFalse || !strings.Contains(cond.Message, "down.example.org (connection refused)") {
+			t.Errorf("Expected FederatedBundlesHealthy False naming down.example.org, got %+v", cond)
+		}
+		if ready := apimeta.FindStatusCondition(server.Status.Conditions, v1alpha1.Ready); ready == nil || ready.Status != metav1.ConditionTrue {
+			t.Errorf("Expected an unhealthy partner not to affect the Ready condition, got %+v", ready)
+		}
+		if fakeClient.StatusUpdateWithRetryCallCount() != 1 {
+			t.Errorf("Expected the status to be updated, got %d calls", fakeClient.StatusUpdateWithRetryCallCount())
+		}
+	})
+
+	t.Run("does not fetch the bundles refreshed recently", func(t *testing.T) {
+		fetched = map[string]int{}
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		server := newServer(up)
+		lastRefresh := metav1.NewTime(time.Now().Add(-time.Minute))
+		server.Status.FederatedTrustDomains = []v1alpha1.FederatedTrustDomainStatus{{
+			TrustDomain:               up.TrustDomain,
+			BundleEndpointUrl:         up.BundleEndpointUrl,
+			Healthy:                   "true",
+			LastSuccessfulRefreshTime: &lastRefresh,
+		}}
+
+		next := reconciler.reconcileFederationStatus(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{}))
+		if fetched[up.TrustDomain] != 0 {
+			t.Errorf("Expected the recently refreshed bundle not to be fetched")
+		}
+		if next <= 0 || next > federatedBundleRefreshInterval-time.Minute+time.Second {
+			t.Errorf("Expected the next refresh when the bundle is due, got %s", next)
+		}
+	})
+
+	t.Run("clears the status when no trust domain is federated", func(t *testing.T) {
+		reconciler := newConfigMapTestReconciler(&fakes.FakeCustomCtrlClient{})
+		server := newServer()
+		server.Status.FederatedTrustDomains = []v1alpha1.FederatedTrustDomainStatus{{TrustDomain: up.TrustDomain}}
+
+		if next := reconciler.reconcileFederationStatus(context.Background(), server, status.NewManager(&fakes.FakeCustomCtrlClient{})); next != 0 {
+			t.Errorf("Expected no refresh, got %s", next)
+		}
+		if server.Status.FederatedTrustDomains != nil {
+			t.Errorf("Expected the federated trust domains to be cleared, got %+v", server.Status.FederatedTrustDomains)
+		}
+	})
+}
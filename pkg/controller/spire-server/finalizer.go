@@ -0,0 +1,90 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// reconcileDeletion tears down the SPIRE server before the SpireServer CR is removed.
+// The spire-controller-manager ValidatingWebhookConfiguration is deleted right away, so that
+// admission of spire.spiffe.io resources does not fail once the server is gone. The server
+// itself is kept until the agents, the CSI driver and the OIDC discovery provider being deleted
+// alongside it are gone. The StatefulSet is then deleted, and the remaining resources are
+// garbage collected through their owner references.
+func (r *SpireServerReconciler) reconcileDeletion(ctx context.Context, server *v1alpha1.SpireServer, namespace string) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(server, utils.OperandCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	getStatus := func() *v1alpha1.ConditionalStatus {
+		return &server.Status.ConditionalStatus
+	}
+
+	if err := utils.DeleteObjects(ctx, r.ctrlClient, getSpireControllerManagerValidatingWebhookConfiguration(nil)); err != nil {
+		r.log.Error(err, "failed to delete spire-controller-manager webhook")
+		return ctrl.Result{}, err
+	}
+
+	waiting, err := utils.ObjectsBeingDeleted(ctx, r.ctrlClient,
+		&v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&v1alpha1.SpireOIDCDiscoveryProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+	)
+	if err != nil {
+		r.log.Error(err, "failed to check dependent operands")
+		return ctrl.Result{}, err
+	}
+	if len(waiting) > 0 {
+		r.log.Info("Waiting for dependent operands to be deleted", "operands", waiting)
+		if err := status.SetDeletionStatus(ctx, r.ctrlClient, server, getStatus, utils.DeletionWaitingForDependents,
+			fmt.Sprintf("Webhook deleted, waiting for %s to be deleted", strings.Join(waiting, ", "))); err != nil {
+			r.log.Error(err, "failed to update status")
+		}
+		return ctrl.Result{RequeueAfter: utils.DeletionRequeueInterval}, nil
+	}
+
+	if err := status.SetDeletionStatus(ctx, r.ctrlClient, server, getStatus, utils.DeletionDeletingResources,
+		"Deleting the SPIRE server resources"); err != nil {
+		r.log.Error(err, "failed to update status")
+	}
+	if err := utils.DeleteObjects(ctx, r.ctrlClient,
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: namespace}},
+	); err != nil {
+		r.log.Error(err, "failed to delete SPIRE server resources")
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(server, utils.OperandCleanupFinalizer)
+	if err := r.ctrlClient.Update(ctx, server); err != nil {
+		r.log.Error(err, "failed to remove finalizer from SpireServer")
+		return ctrl.Result{}, err
+	}
+	r.log.Info("SPIRE server resources deleted, removed finalizer", "name", server.Name)
+	return ctrl.Result{}, nil
+}
+
+// reconcileRemoval deletes the resources managed for the SpireServer while keeping the CR, when its
+// management state is Removed. The spire-controller-manager webhook and the StatefulSet are deleted first, as in the teardown.
+func (r *SpireServerReconciler) reconcileRemoval(ctx context.Context, server *v1alpha1.SpireServer, namespace string, statusMgr *status.Manager) error {
+	if err := statusMgr.RemoveManagedResources(ctx, r.ctrlClient, r.scheme, server.Status.ManagedResources,
+		getSpireControllerManagerValidatingWebhookConfiguration(nil),
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: namespace}},
+	); err != nil {
+		r.log.Error(err, "failed to remove SPIRE server resources")
+		statusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonFailed,
+			fmt.Sprintf("Failed to remove the SPIRE server resources: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	r.log.Info("SPIRE server resources removed", "name", server.Name)
+	return nil
+}
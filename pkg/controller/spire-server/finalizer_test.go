@@ -0,0 +1,114 @@
+package spire_server
+
+import (
+	"context"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func newDeletingSpireServer() *v1alpha1.SpireServer {
+	now := metav1.Now()
+	return &v1alpha1.SpireServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{utils.OperandCleanupFinalizer},
+		},
+	}
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	t.Run("waits for agents being deleted after removing the webhook", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		now := metav1.Now()
+		fakeClient.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if agent, ok := obj.(*v1alpha1.SpireAgent); ok {
+				agent.Name = key.Name
+				agent.DeletionTimestamp = &now
+				return nil
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		reconciler := newTestReconciler(fakeClient)
+		server := newDeletingSpireServer()
+
+		result, err := reconciler.reconcileDeletion(context.Background(), server, "operand-ns")
+		if err != nil {
+			t.Fatalf("reconcileDeletion() error = %v", err)
+		}
+		if result.RequeueAfter != utils.DeletionRequeueInterval {
+			t.Errorf("Expected requeue after %v, got %v", utils.DeletionRequeueInterval, result.RequeueAfter)
+		}
+		if fakeClient.DeleteCallCount() != 1 {
+			t.Fatalf("Expected only the webhook to be deleted, got %d deletes", fakeClient.DeleteCallCount())
+		}
+		if _, obj, _ := fakeClient.DeleteArgsForCall(0); !isValidatingWebhookConfiguration(obj) {
+			t.Error("Expected the ValidatingWebhookConfiguration to be deleted first")
+		}
+		if fakeClient.UpdateCallCount() != 0 {
+			t.Error("Expected the finalizer to be kept while waiting")
+		}
+		if !controllerutil.ContainsFinalizer(server, utils.OperandCleanupFinalizer) {
+			t.Error("Expected the finalizer to be kept while waiting")
+		}
+	})
+
+	t.Run("deletes the statefulset and removes the finalizer", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, "cluster"))
+		reconciler := newTestReconciler(fakeClient)
+		server := newDeletingSpireServer()
+
+		result, err := reconciler.reconcileDeletion(context.Background(), server, "operand-ns")
+		if err != nil {
+			t.Fatalf("reconcileDeletion() error = %v", err)
+		}
+		if result.RequeueAfter != 0 {
+			t.Errorf("Expected no requeue, got %v", result.RequeueAfter)
+		}
+		if fakeClient.DeleteCallCount() != 2 {
+			t.Fatalf("Expected 2 deletes, got %d", fakeClient.DeleteCallCount())
+		}
+		if _, obj, _ := fakeClient.DeleteArgsForCall(1); !isStatefulSet(obj) || obj.GetName() != "spire-server" {
+			t.Errorf("Expected the spire-server StatefulSet to be deleted last, got %s", obj.GetName())
+		}
+		if fakeClient.UpdateCallCount() != 1 || controllerutil.ContainsFinalizer(server, utils.OperandCleanupFinalizer) {
+			t.Error("Expected the finalizer to be removed")
+		}
+	})
+
+	t.Run("no finalizer is a no-op", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newTestReconciler(fakeClient)
+		server := newDeletingSpireServer()
+		server.Finalizers = nil
+
+		if _, err := reconciler.reconcileDeletion(context.Background(), server, "operand-ns"); err != nil {
+			t.Fatalf("reconcileDeletion() error = %v", err)
+		}
+		if fakeClient.DeleteCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+			t.Error("Expected no writes without the finalizer")
+		}
+	})
+}
+
+func isValidatingWebhookConfiguration(obj client.Object) bool {
+	_, ok := obj.(*admissionregistrationv1.ValidatingWebhookConfiguration)
+	return ok
+}
+
+func isStatefulSet(obj client.Object) bool {
+	_, ok := obj.(*appsv1.StatefulSet)
+	return ok
+}
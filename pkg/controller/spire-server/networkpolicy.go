@@ -0,0 +1,147 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// generateSpireServerNetworkPolicy returns the NetworkPolicy of the SPIRE server pods. The ports are those of
+// the generated Services: the SPIRE server API is restricted to the SPIRE agents and the OIDC discovery provider,
+// the agents running in the host network being matched by the host-network policy group, while the controller
+// manager webhook is called by the kube-apiserver and the federation endpoint by the federated trust domains.
+// When the API is published outside of the cluster, it is open to the load balancer or router clients.
+func generateSpireServerNetworkPolicy(config *v1alpha1.SpireServerSpec) *networkingv1.NetworkPolicy {
+	labels := utils.SpireServerLabels(config.Labels)
+
+	var apiPorts, metricsPorts, federationPorts []corev1.ServicePort
+	for _, port := range getSpireServerService(config).Spec.Ports {
+		switch port.Name {
+		case "metrics":
+			metricsPorts = append(metricsPorts, port)
+		case "federation":
+			federationPorts = append(federationPorts, port)
+		default:
+			apiPorts = append(apiPorts, port)
+		}
+	}
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: utils.NetworkPolicyPorts(apiPorts),
+			From: []networkingv1.NetworkPolicyPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: utils.PodSelectorLabels(utils.SpireAgentLabels(nil))}},
+				{PodSelector: &metav1.LabelSelector{MatchLabels: utils.PodSelectorLabels(utils.SpireOIDCDiscoveryProviderLabels(nil))}},
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{utils.HostNetworkPolicyGroupLabelKey: ""}}},
+			},
+		},
+		{
+			Ports: utils.NetworkPolicyPorts(getSpireControllerManagerWebhookService(config.Labels).Spec.Ports),
+		},
+		{
+			Ports: utils.NetworkPolicyPorts(metricsPorts),
+			From: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{utils.PolicyGroupLabelKey: utils.MonitoringPolicyGroup}}},
+			},
+		},
+	}
+	if config.Exposure != nil {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: utils.NetworkPolicyPorts(apiPorts),
+		})
+	}
+	if len(federationPorts) > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			Ports: utils.NetworkPolicyPorts(federationPorts),
+		})
+	}
+
+	return utils.GenerateNetworkPolicy("spire-server", labels, utils.PodSelectorLabels(labels), ingress)
+}
+
+// reconcileNetworkPolicy reconciles the NetworkPolicy of the SPIRE server pods
+func (r *SpireServerReconciler) reconcileNetworkPolicy(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) error {
+	desired := generateSpireServerNetworkPolicy(&server.Spec)
+	if utils.IsTornjakEnabled(ztwim.Spec.Tornjak) {
+		addTornjakToNetworkPolicy(desired)
+	}
+
+	existing := &networkingv1.NetworkPolicy{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get NetworkPolicy")
+		statusMgr.AddCondition(NetworkPolicyAvailable, "SpireServerNetworkPolicyGetFailed",
+			fmt.Sprintf("Failed to get NetworkPolicy: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	exists := err == nil
+
+	if !utils.IsNetworkPolicyEnabled(ztwim.Spec.NetworkPolicy) {
+		if exists {
+			if err := r.ctrlClient.Delete(ctx, existing); err != nil && !kerrors.IsNotFound(err) {
+				r.log.Error(err, "failed to delete NetworkPolicy")
+				statusMgr.AddCondition(NetworkPolicyAvailable, "SpireServerNetworkPolicyDeletionFailed",
+					fmt.Sprintf("Failed to delete NetworkPolicy: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Deleted NetworkPolicy", "name", desired.Name, "namespace", desired.Namespace)
+		}
+		statusMgr.AddCondition(NetworkPolicyAvailable, "SpireServerNetworkPolicyDisabled",
+			"NetworkPolicy management disabled",
+			metav1.ConditionTrue)
+		return nil
+	}
+
+	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on NetworkPolicy")
+		statusMgr.AddCondition(NetworkPolicyAvailable, "SpireServerNetworkPolicyGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on NetworkPolicy: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	if !exists {
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create NetworkPolicy")
+			statusMgr.AddCondition(NetworkPolicyAvailable, "SpireServerNetworkPolicyCreationFailed",
+				fmt.Sprintf("Failed to create NetworkPolicy: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created NetworkPolicy", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+	} else if utils.ResourceNeedsUpdate(existing, desired) {
+		if createOnlyMode {
+			r.log.Info("Skipping NetworkPolicy update due to create-only mode")
+		} else {
+			desired.ResourceVersion = existing.ResourceVersion
+			if err := r.ctrlClient.Update(ctx, desired); err != nil {
+				r.log.Error(err, "failed to update NetworkPolicy")
+				statusMgr.AddCondition(NetworkPolicyAvailable, "SpireServerNetworkPolicyUpdateFailed",
+					fmt.Sprintf("Failed to update NetworkPolicy: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Updated NetworkPolicy", "name", desired.Name, "namespace", desired.Namespace)
+			statusMgr.RecordDriftRepaired(desired)
+		}
+	}
+
+	statusMgr.AddCondition(NetworkPolicyAvailable, v1alpha1.ReasonReady,
+		"NetworkPolicy available",
+		metav1.ConditionTrue)
+	return nil
+}
@@ -0,0 +1,215 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func TestGenerateSpireServerNetworkPolicy(t *testing.T) {
+	t.Run("restricts the SPIRE server API to the agents and the OIDC discovery provider", func(t *testing.T) {
+		spec := &v1alpha1.SpireServerSpec{
+			Persistence:  v1alpha1.Persistence{Size: "1Gi", AccessMode: "ReadWriteOnce"},
+			CommonConfig: v1alpha1.CommonConfig{Labels: map[string]string{"custom": "label"}},
+		}
+		policy := generateSpireServerNetworkPolicy(spec)
+		if policy.Name != "spire-server" {
+			t.Errorf("Expected name 'spire-server', got '%s'", policy.Name)
+		}
+		if policy.Labels["custom"] != "label" {
+			t.Error("Expected custom label on NetworkPolicy")
+		}
+		sts := GenerateSpireServerStatefulSet(spec, "", "")
+		for k, v := range policy.Spec.PodSelector.MatchLabels {
+			if sts.Spec.Template.Labels[k] != v {
+				t.Errorf("Expected pod selector label %s=%s to match the SPIRE server pods", k, v)
+			}
+		}
+		if len(policy.Spec.PolicyTypes) != 1 || policy.Spec.PolicyTypes[0] != networkingv1.PolicyTypeIngress {
+			t.Errorf("Expected an ingress policy, got %v", policy.Spec.PolicyTypes)
+		}
+		if len(policy.Spec.Ingress) != 3 {
+			t.Fatalf("Expected 3 ingress rules, got %d", len(policy.Spec.Ingress))
+		}
+
+		api := policy.Spec.Ingress[0]
+		if len(api.Ports) != 1 || *api.Ports[0].Port != intstr.FromString("grpc") {
+			t.Errorf("Expected the grpc port, got %v", api.Ports)
+		}
+		if len(api.From) != 3 {
+			t.Fatalf("Expected 3 peers, got %v", api.From)
+		}
+		if api.From[0].PodSelector.MatchLabels["app.kubernetes.io/name"] != "spire-agent" {
+			t.Errorf("Expected the SPIRE agent pods, got %v", api.From[0].PodSelector)
+		}
+		if api.From[1].PodSelector.MatchLabels["app.kubernetes.io/name"] != "spiffe-oidc-discovery-provider" {
+			t.Errorf("Expected the OIDC discovery provider pods, got %v", api.From[1].PodSelector)
+		}
+		if _, ok := api.From[2].NamespaceSelector.MatchLabels[utils.HostNetworkPolicyGroupLabelKey]; !ok {
+			t.Errorf("Expected the host-network namespaces, got %v", api.From[2].NamespaceSelector)
+		}
+
+		webhook := policy.Spec.Ingress[1]
+		if len(webhook.Ports) != 1 || *webhook.Ports[0].Port != intstr.FromString("https") || len(webhook.From) != 0 {
+			t.Errorf("Expected the webhook port from any client, got %v", webhook)
+		}
+
+		metrics := policy.Spec.Ingress[2]
+		if len(metrics.Ports) != 1 || *metrics.Ports[0].Port != intstr.FromInt32(9402) {
+			t.Errorf("Expected the metrics port, got %v", metrics.Ports)
+		}
+		if len(metrics.From) != 1 || metrics.From[0].NamespaceSelector.MatchLabels[utils.PolicyGroupLabelKey] != utils.MonitoringPolicyGroup {
+			t.Errorf("Expected the monitoring namespaces, got %v", metrics.From)
+		}
+	})
+
+	t.Run("allows the federation endpoint when federation is configured", func(t *testing.T) {
+		policy := generateSpireServerNetworkPolicy(&v1alpha1.SpireServerSpec{
+			Federation: &v1alpha1.FederationConfig{
+				BundleEndpoint: v1alpha1.BundleEndpointConfig{Profile: v1alpha1.HttpsSpiffeProfile},
+			},
+		})
+		if len(policy.Spec.Ingress) != 4 {
+			t.Fatalf("Expected 4 ingress rules, got %d", len(policy.Spec.Ingress))
+		}
+		federation := policy.Spec.Ingress[3]
+		if len(federation.Ports) != 1 || *federation.Ports[0].Port != intstr.FromInt32(8443) || *federation.Ports[0].Protocol != corev1.ProtocolTCP {
+			t.Errorf("Expected the federation port, got %v", federation.Ports)
+		}
+		if len(federation.From) != 0 {
+			t.Errorf("Expected the federation endpoint to accept any client, got %v", federation.From)
+		}
+	})
+}
+
+func TestReconcileNetworkPolicy(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "spire-server")
+	enabled := &v1alpha1.NetworkPolicyConfig{Enabled: "true"}
+	existingPolicy := func(fc *fakes.FakeCustomCtrlClient, mutate func(*networkingv1.NetworkPolicy)) {
+		fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if policy, ok := obj.(*networkingv1.NetworkPolicy); ok {
+				*policy = *generateSpireServerNetworkPolicy(&v1alpha1.SpireServerSpec{})
+				policy.ResourceVersion = "123"
+				mutate(policy)
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name           string
+		networkPolicy  *v1alpha1.NetworkPolicyConfig
+		setupClient    func(*fakes.FakeCustomCtrlClient)
+		createOnlyMode bool
+		expectError    bool
+		expectCreate   int
+		expectUpdate   int
+		expectDelete   int
+	}{
+		{
+			name:          "create when not found",
+			networkPolicy: enabled,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+		},
+		{
+			name:          "create error",
+			networkPolicy: enabled,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+				fc.CreateReturns(errors.New("create failed"))
+			},
+			expectError:  true,
+			expectCreate: 1,
+		},
+		{
+			name:          "get error",
+			networkPolicy: enabled,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(errors.New("connection refused"))
+			},
+			expectError: true,
+		},
+		{
+			name:          "up to date",
+			networkPolicy: enabled,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPolicy(fc, func(*networkingv1.NetworkPolicy) {})
+			},
+		},
+		{
+			name:          "update when the rules drifted",
+			networkPolicy: enabled,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPolicy(fc, func(policy *networkingv1.NetworkPolicy) { policy.Spec.Ingress = nil })
+			},
+			expectUpdate: 1,
+		},
+		{
+			name:          "create only mode skips update",
+			networkPolicy: enabled,
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPolicy(fc, func(policy *networkingv1.NetworkPolicy) { policy.Spec.Ingress = nil })
+			},
+			createOnlyMode: true,
+		},
+		{
+			name: "disabled by default deletes existing",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPolicy(fc, func(*networkingv1.NetworkPolicy) {})
+			},
+			expectDelete: 1,
+		},
+		{
+			name:          "disabled and absent",
+			networkPolicy: &v1alpha1.NetworkPolicyConfig{Enabled: "false"},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			server := &v1alpha1.SpireServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+			}
+			ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+				Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{NetworkPolicy: tt.networkPolicy},
+			}
+			statusMgr := status.NewManager(fakeClient)
+
+			err := reconciler.reconcileNetworkPolicy(context.Background(), server, statusMgr, ztwim, tt.createOnlyMode)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcileNetworkPolicy() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package spire_server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/inspect"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
+)
+
+const (
+	// k8sPSATAttestationType is the attestation type of the SPIRE agents of the cluster
+	k8sPSATAttestationType = "k8s_psat"
+
+	// agentNodeUIDSelector and agentNodeNameSelector prefix the values of the k8s_psat selectors of the
+	// node an agent runs on, and clusterSelector the value of the selector of its cluster
+	agentNodeUIDSelector  = "agent_node_uid:"
+	agentNodeNameSelector = "agent_node_name:"
+	clusterSelector       = "cluster:"
+)
+
+// nodeSweepRequest is the request all the events of the node lifecycle controller are queued as, so that
+// the agents of the nodes deleted at once, e.g. by a scale down, are evicted in a single sweep
+var nodeSweepRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+
+// nodeLifecyclePredicate passes the created and deleted nodes. The nodes listed when the controller
+// starts are passed as created, so that the nodes deleted while the operator was down are swept too.
+var nodeLifecyclePredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// NodeLifecycleReconciler evicts the SPIRE agents of the deleted nodes from the SPIRE server
+type NodeLifecycleReconciler struct {
+	ctrlClient    customClient.CustomCtrlClient
+	clientset     kubernetes.Interface
+	eventRecorder record.EventRecorder
+	log           logr.Logger
+
+	// spireServerCLI runs the SPIRE server CLI in the SPIRE server pods, to evict the agents
+	spireServerCLI spireServerCLI
+}
+
+// NewNodeLifecycleReconciler returns a new NodeLifecycleReconciler instance.
+func NewNodeLifecycleReconciler(mgr ctrl.Manager, clientOpts ...customClient.Option) (*NodeLifecycleReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &NodeLifecycleReconciler{
+		ctrlClient:     c,
+		clientset:      clientset,
+		eventRecorder:  mgr.GetEventRecorderFor(utils.ZeroTrustWorkloadIdentityManagerNodeLifecycleControllerName),
+		log:            ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerNodeLifecycleControllerName),
+		spireServerCLI: newSpireServerCLI(clientset, inspect.NewPodExecutor(mgr.GetConfig(), clientset)),
+	}, nil
+}
+
+// spireAgent is an agent in the output of the "agent list" command
+type spireAgent struct {
+	ID              spiffeID        `json:"id"`
+	AttestationType string          `json:"attestation_type"`
+	Selectors       []spireSelector `json:"selectors"`
+}
+
+// spiffeID is a SPIFFE ID in the output of the SPIRE server CLI
+type spiffeID struct {
+	TrustDomain string `json:"trust_domain"`
+	Path        string `json:"path"`
+}
+
+func (id spiffeID) String() string {
+	return "spiffe://" + id.TrustDomain + id.Path
+}
+
+// spireSelector is a selector in the output of the SPIRE server CLI
+type spireSelector struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// selectorValue returns the value of the k8s_psat selector of the agent starting with prefix
+func (a *spireAgent) selectorValue(prefix string) string {
+	for _, selector := range a.Selectors {
+		if selector.Type == k8sPSATAttestationType && strings.HasPrefix(selector.Value, prefix) {
+			return strings.TrimPrefix(selector.Value, prefix)
+		}
+	}
+	return ""
+}
+
+// Reconcile evicts the SPIRE agents of the cluster attested on nodes that no longer exist, and deletes the
+// registration entries parented to them or aliasing their nodes
+func (r *NodeLifecycleReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	var server v1alpha1.SpireServer
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, &server); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !server.DeletionTimestamp.IsZero() || utils.StringToBool(server.Spec.Paused) ||
+		utils.IsDryRunMode(server.Spec.ReconcileMode) || !utils.StringToBool(server.Spec.AgentEviction) {
+		return ctrl.Result{}, nil
+	}
+
+	var ztwim v1alpha1.ZeroTrustWorkloadIdentityManager
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, &ztwim); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	// The nodes of this cluster only run the agents of the server when both are deployed here
+	if !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireServer) || !utils.IsOperandDeployed(&ztwim, utils.ResourceKindSpireAgent) {
+		return ctrl.Result{}, nil
+	}
+	if managementState := utils.GetManagementState(server.Spec.ManagementState, &ztwim); managementState != v1alpha1.ManagementStateManaged {
+		return ctrl.Result{}, nil
+	}
+	if err := utils.ConfigureOperandNamespace(ztwim.Spec.OperandNamespace); err != nil {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.evictDeletedNodeAgents(ctx, &server, ztwim.Spec.ClusterName)
+}
+
+// evictDeletedNodeAgents evicts the agents of clusterName whose node no longer exists. The agents are
+// listed before the nodes, so that an agent attested on a node created meanwhile is never evicted.
+func (r *NodeLifecycleReconciler) evictDeletedNodeAgents(ctx context.Context, server *v1alpha1.SpireServer, clusterName string) error {
+	agents, err := r.listAgents(ctx)
+	if err != nil {
+		return err
+	}
+	nodes, err := r.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	// An empty list is more likely a transient API server answer than a cluster without nodes
+	if len(nodes.Items) == 0 {
+		r.log.Info("No node found, skipping the eviction of the SPIRE agents")
+		return nil
+	}
+	nodeUIDs := make(map[string]bool, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeUIDs[string(node.UID)] = true
+	}
+
+	var errs []error
+	for _, agent := range agents {
+		if agent.AttestationType != k8sPSATAttestationType || agent.selectorValue(clusterSelector) != clusterName {
+			continue
+		}
+		nodeUID := agent.selectorValue(agentNodeUIDSelector)
+		if nodeUID == "" || nodeUIDs[nodeUID] {
+			continue
+		}
+		if err := r.evictAgent(ctx, server, agent, nodeUID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evictAgent deletes the registration entries of the agent of the deleted node nodeUID and evicts it. The
+// entries are deleted first, so that a failed eviction finds them again on the next sweep.
+func (r *NodeLifecycleReconciler) evictAgent(ctx context.Context, server *v1alpha1.SpireServer, agent spireAgent, nodeUID string) error {
+	agentID := agent.ID.String()
+	parented, err := r.showEntries(ctx, "-parentID", agentID)
+	if err != nil {
+		return err
+	}
+	aliases, err := r.showEntries(ctx, "-selector", k8sPSATAttestationType+":"+agentNodeUIDSelector+nodeUID)
+	if err != nil {
+		return err
+	}
+	for _, entryID := range append(parented, aliases...) {
+		if _, err := r.spireServerCLI(ctx, "entry", "delete", "-entryID", entryID, "-output", "json"); err != nil {
+			return fmt.Errorf("failed to delete the entry %s of the agent %s: %w", entryID, agentID, err)
+		}
+	}
+	if _, err := r.spireServerCLI(ctx, "agent", "evict", "-spiffeID", agentID, "-output", "json"); err != nil {
+		return fmt.Errorf("failed to evict the agent %s: %w", agentID, err)
+	}
+
+	nodeName := agent.selectorValue(agentNodeNameSelector)
+	r.log.Info("Evicted the SPIRE agent of a deleted node", "agent", agentID, "node", nodeName, "entries", len(parented)+len(aliases))
+	r.eventRecorder.Eventf(server, corev1.EventTypeNormal, "AgentEvicted",
+		"Evicted the SPIRE agent %s of the deleted node %s and deleted its %d registration entries", agentID, nodeName, len(parented)+len(aliases))
+	return nil
+}
+
+// listAgents returns the agents attested to the SPIRE server
+func (r *NodeLifecycleReconciler) listAgents(ctx context.Context) ([]spireAgent, error) {
+	output, err := r.spireServerCLI(ctx, "agent", "list", "-output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the agents: %w", err)
+	}
+	var list struct {
+		Agents []spireAgent `json:"agents"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode the agents: %w", err)
+	}
+	return list.Agents, nil
+}
+
+// showEntries returns the IDs of the registration entries matching the "entry show" filter args
+func (r *NodeLifecycleReconciler) showEntries(ctx context.Context, args ...string) ([]string, error) {
+	output, err := r.spireServerCLI(ctx, append(append([]string{"entry", "show"}, args...), "-output", "json")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show the entries %s: %w", strings.Join(args, " "), err)
+	}
+	var list struct {
+		Entries []struct {
+			ID string `json:"id"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode the entries: %w", err)
+	}
+	ids := make([]string, 0, len(list.Entries))
+	for _, entry := range list.Entries {
+		ids = append(ids, entry.ID)
+	}
+	return ids, nil
+}
+
+func (r *NodeLifecycleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	mapFunc := func(ctx context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{nodeSweepRequest}
+	}
+
+	// Only the metadata of the nodes is cached, the sweep reads the nodes from the API server
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(utils.ZeroTrustWorkloadIdentityManagerNodeLifecycleControllerName).
+		WithOptions(controller.Options{
+			RateLimiter: utils.NewRateLimiter(),
+		}).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.OnlyMetadata, builder.WithPredicates(nodeLifecyclePredicate)).
+		Watches(&v1alpha1.SpireServer{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerNodeLifecycleControllerName, r))
+}
@@ -0,0 +1,157 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+)
+
+// fakeAgentCLI records the SPIRE server CLI commands and serves the agents and entries of a SPIRE server
+type fakeAgentCLI struct {
+	commands []string
+	agents   string
+	entries  map[string]string
+	err      error
+}
+
+func (f *fakeAgentCLI) run(ctx context.Context, args ...string) ([]byte, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+	switch {
+	case strings.HasPrefix(command, "agent list"):
+		return []byte(f.agents), nil
+	case strings.HasPrefix(command, "entry show"):
+		if entries, ok := f.entries[args[3]]; ok {
+			return []byte(entries), nil
+		}
+		return []byte(`{"entries":[]}`), nil
+	case f.err != nil:
+		return nil, f.err
+	}
+	return []byte(`{}`), nil
+}
+
+// testAgent returns an agent of cluster attested on the node nodeUID in the output of "agent list"
+func testAgent(cluster, nodeUID string) string {
+	return fmt.Sprintf(`{"id":{"trust_domain":"example.org","path":"/spire/agent/k8s_psat/%[1]s/%[2]s"},"attestation_type":"k8s_psat",`+
+		`"selectors":[{"type":"k8s_psat","value":"cluster:%[1]s"},{"type":"k8s_psat","value":"agent_node_uid:%[2]s"},`+
+		`{"type":"k8s_psat","value":"agent_node_name:node-%[2]s"}]}`, cluster, nodeUID)
+}
+
+func newNodeLifecycleTestReconciler(server *v1alpha1.SpireServer, cli *fakeAgentCLI, nodeUIDs ...string) (*NodeLifecycleReconciler, *record.FakeRecorder) {
+	var nodes []runtime.Object
+	for _, uid := range nodeUIDs {
+		nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + uid, UID: types.UID(uid)}})
+	}
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	fakeClient.GetStub = func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch v := obj.(type) {
+		case *v1alpha1.SpireServer:
+			*v = *server
+		case *v1alpha1.ZeroTrustWorkloadIdentityManager:
+			*v = v1alpha1.ZeroTrustWorkloadIdentityManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "demo"},
+			}
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		return nil
+	}
+	recorder := record.NewFakeRecorder(10)
+	return &NodeLifecycleReconciler{
+		ctrlClient:     fakeClient,
+		clientset:      k8sfake.NewSimpleClientset(nodes...),
+		eventRecorder:  recorder,
+		log:            ctrl.Log.WithName("test"),
+		spireServerCLI: cli.run,
+	}, recorder
+}
+
+func TestNodeLifecycleReconcile(t *testing.T) {
+	agents := `{"agents":[` + strings.Join([]string{testAgent("demo", "live"), testAgent("demo", "gone"), testAgent("other", "remote")}, ",") + `]}`
+	entries := map[string]string{
+		"spiffe://example.org/spire/agent/k8s_psat/demo/gone": `{"entries":[{"id":"workload-entry"}]}`,
+		"k8s_psat:agent_node_uid:gone":                        `{"entries":[{"id":"alias-entry"}]}`,
+	}
+	newServer := func(agentEviction string) *v1alpha1.SpireServer {
+		server := createTestSpireServer()
+		server.Spec.AgentEviction = agentEviction
+		return server
+	}
+
+	t.Run("evicts the agents of the deleted nodes", func(t *testing.T) {
+		cli := &fakeAgentCLI{agents: agents, entries: entries}
+		reconciler, recorder := newNodeLifecycleTestReconciler(newServer("true"), cli, "live")
+
+		if _, err := reconciler.Reconcile(context.Background(), nodeSweepRequest); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		expected := []string{
+			"agent list -output json",
+			"entry show -parentID spiffe://example.org/spire/agent/k8s_psat/demo/gone -output json",
+			"entry show -selector k8s_psat:agent_node_uid:gone -output json",
+			"entry delete -entryID workload-entry -output json",
+			"entry delete -entryID alias-entry -output json",
+			"agent evict -spiffeID spiffe://example.org/spire/agent/k8s_psat/demo/gone -output json",
+		}
+		if strings.Join(cli.commands, ";") != strings.Join(expected, ";") {
+			t.Errorf("Expected commands %v, got %v", expected, cli.commands)
+		}
+		if event := <-recorder.Events; !strings.Contains(event, "AgentEvicted") || !strings.Contains(event, "node-gone") {
+			t.Errorf("Expected an AgentEvicted event for node-gone, got %q", event)
+		}
+	})
+
+	t.Run("keeps the agent when its eviction fails", func(t *testing.T) {
+		cli := &fakeAgentCLI{agents: agents, entries: entries, err: errors.New("permission denied")}
+		reconciler, _ := newNodeLifecycleTestReconciler(newServer("true"), cli, "live")
+
+		if _, err := reconciler.Reconcile(context.Background(), nodeSweepRequest); err == nil || !strings.Contains(err.Error(), "permission denied") {
+			t.Errorf("Expected the failed deletion to be retried, got: %v", err)
+		}
+		if strings.Contains(strings.Join(cli.commands, ";"), "agent evict") {
+			t.Errorf("Expected the agent not to be evicted before its entries are deleted, got %v", cli.commands)
+		}
+	})
+
+	t.Run("skips the sweep without nodes", func(t *testing.T) {
+		cli := &fakeAgentCLI{agents: agents, entries: entries}
+		reconciler, _ := newNodeLifecycleTestReconciler(newServer("true"), cli)
+
+		if _, err := reconciler.Reconcile(context.Background(), nodeSweepRequest); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if strings.Join(cli.commands, ";") != "agent list -output json" {
+			t.Errorf("Expected no agent to be evicted, got %v", cli.commands)
+		}
+	})
+
+	t.Run("skips the sweep when disabled", func(t *testing.T) {
+		cli := &fakeAgentCLI{agents: agents, entries: entries}
+		reconciler, _ := newNodeLifecycleTestReconciler(newServer("false"), cli, "live")
+
+		if _, err := reconciler.Reconcile(context.Background(), nodeSweepRequest); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(cli.commands) != 0 {
+			t.Errorf("Expected no command, got %v", cli.commands)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+// preserveServiceFields copies the fields of the existing Service allocated by Kubernetes to the desired Service
+func preserveServiceFields(existing, desired *corev1.Service) {
+	desired.Spec.ClusterIP = existing.Spec.ClusterIP
+	desired.Spec.ClusterIPs = existing.Spec.ClusterIPs
+	desired.Spec.IPFamilies = existing.Spec.IPFamilies
+	desired.Spec.IPFamilyPolicy = existing.Spec.IPFamilyPolicy
+	desired.Spec.InternalTrafficPolicy = existing.Spec.InternalTrafficPolicy
+	desired.Spec.SessionAffinity = existing.Spec.SessionAffinity
+	if existing.Spec.HealthCheckNodePort != 0 {
+		desired.Spec.HealthCheckNodePort = existing.Spec.HealthCheckNodePort
+	}
+	// The node ports of LoadBalancer Services are allocated unless set
+	for i := range desired.Spec.Ports {
+		for _, port := range existing.Spec.Ports {
+			if port.Name == desired.Spec.Ports[i].Name && desired.Spec.Ports[i].NodePort == 0 {
+				desired.Spec.Ports[i].NodePort = port.NodePort
+			}
+		}
+	}
+}
+
+// reconcileOwnedResource creates desired when it does not exist, or updates it when needsUpdate reports
+// a drift from existing, which is filled with the current resource before needsUpdate is called. Failures
+// are reported in the conditionType condition.
+func (r *SpireServerReconciler) reconcileOwnedResource(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, conditionType string, desired, existing client.Object, needsUpdate func() bool, createOnlyMode bool) error {
+	gvk, _ := apiutil.GVKForObject(desired, r.scheme)
+	kind := gvk.Kind
+	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on resource", "kind", kind, "name", desired.GetName())
+		statusMgr.AddCondition(conditionType, kind+"GenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on %s %s: %v", kind, desired.GetName(), err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, existing)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			r.log.Error(err, "failed to get resource", "kind", kind, "name", desired.GetName())
+			statusMgr.AddCondition(conditionType, kind+"GetFailed",
+				fmt.Sprintf("Failed to get %s %s: %v", kind, desired.GetName(), err),
+				metav1.ConditionFalse)
+			return err
+		}
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create resource", "kind", kind, "name", desired.GetName())
+			statusMgr.AddCondition(conditionType, kind+"CreationFailed",
+				fmt.Sprintf("Failed to create %s %s: %v", kind, desired.GetName(), err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created resource", "kind", kind, "name", desired.GetName(), "namespace", desired.GetNamespace())
+		statusMgr.RecordResourceCreated(desired)
+		return nil
+	}
+
+	if !needsUpdate() {
+		return nil
+	}
+	if createOnlyMode {
+		r.log.Info("Skipping resource update due to create-only mode", "kind", kind, "name", desired.GetName())
+		return nil
+	}
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	if err := r.ctrlClient.Update(ctx, desired); err != nil {
+		r.log.Error(err, "failed to update resource", "kind", kind, "name", desired.GetName())
+		statusMgr.AddCondition(conditionType, kind+"UpdateFailed",
+			fmt.Sprintf("Failed to update %s %s: %v", kind, desired.GetName(), err),
+			metav1.ConditionFalse)
+		return err
+	}
+	r.log.Info("Updated resource", "kind", kind, "name", desired.GetName(), "namespace", desired.GetNamespace())
+	statusMgr.RecordDriftRepaired(desired)
+	return nil
+}
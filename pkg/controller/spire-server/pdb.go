@@ -0,0 +1,102 @@
+package spire_server
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// generateSpireServerPodDisruptionBudget returns the PodDisruptionBudget for the SPIRE server StatefulSet
+func generateSpireServerPodDisruptionBudget(config *v1alpha1.SpireServerSpec) *policyv1.PodDisruptionBudget {
+	labels := utils.SpireServerLabels(config.Labels)
+	selectorLabels := map[string]string{
+		"app.kubernetes.io/name":      labels["app.kubernetes.io/name"],
+		"app.kubernetes.io/instance":  labels["app.kubernetes.io/instance"],
+		"app.kubernetes.io/component": labels["app.kubernetes.io/component"],
+	}
+	return utils.GeneratePodDisruptionBudget("spire-server", labels, selectorLabels, config.PodDisruptionBudget, 1)
+}
+
+// reconcilePodDisruptionBudget reconciles the PodDisruptionBudget for the SPIRE server StatefulSet
+func (r *SpireServerReconciler) reconcilePodDisruptionBudget(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) error {
+	desired := generateSpireServerPodDisruptionBudget(&server.Spec)
+
+	existing := &policyv1.PodDisruptionBudget{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Error(err, "failed to get PodDisruptionBudget")
+		statusMgr.AddCondition(PodDisruptionBudgetAvailable, "SpireServerPodDisruptionBudgetGetFailed",
+			fmt.Sprintf("Failed to get PodDisruptionBudget: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	exists := err == nil
+
+	if !utils.IsPodDisruptionBudgetManaged(server.Spec.PodDisruptionBudget, ztwim) {
+		if exists {
+			if err := r.ctrlClient.Delete(ctx, existing); err != nil && !kerrors.IsNotFound(err) {
+				r.log.Error(err, "failed to delete PodDisruptionBudget")
+				statusMgr.AddCondition(PodDisruptionBudgetAvailable, "SpireServerPodDisruptionBudgetDeletionFailed",
+					fmt.Sprintf("Failed to delete PodDisruptionBudget: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Deleted PodDisruptionBudget", "name", desired.Name, "namespace", desired.Namespace)
+		}
+		// Disabling the PodDisruptionBudget is a valid configuration and must not mark the operand as not ready
+		statusMgr.AddCondition(PodDisruptionBudgetAvailable, "SpireServerPodDisruptionBudgetDisabled",
+			"PodDisruptionBudget management disabled",
+			metav1.ConditionTrue)
+		return nil
+	}
+
+	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on PodDisruptionBudget")
+		statusMgr.AddCondition(PodDisruptionBudgetAvailable, "SpireServerPodDisruptionBudgetGenerationFailed",
+			fmt.Sprintf("Failed to set owner reference on PodDisruptionBudget: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	if !exists {
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create PodDisruptionBudget")
+			statusMgr.AddCondition(PodDisruptionBudgetAvailable, "SpireServerPodDisruptionBudgetCreationFailed",
+				fmt.Sprintf("Failed to create PodDisruptionBudget: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+		r.log.Info("Created PodDisruptionBudget", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+	} else if utils.ResourceNeedsUpdate(existing, desired) {
+		if createOnlyMode {
+			r.log.Info("Skipping PodDisruptionBudget update due to create-only mode")
+		} else {
+			desired.ResourceVersion = existing.ResourceVersion
+			if err := r.ctrlClient.Update(ctx, desired); err != nil {
+				r.log.Error(err, "failed to update PodDisruptionBudget")
+				statusMgr.AddCondition(PodDisruptionBudgetAvailable, "SpireServerPodDisruptionBudgetUpdateFailed",
+					fmt.Sprintf("Failed to update PodDisruptionBudget: %v", err),
+					metav1.ConditionFalse)
+				return err
+			}
+			r.log.Info("Updated PodDisruptionBudget", "name", desired.Name, "namespace", desired.Namespace)
+			statusMgr.RecordDriftRepaired(desired)
+		}
+	}
+
+	statusMgr.AddCondition(PodDisruptionBudgetAvailable, v1alpha1.ReasonReady,
+		"PodDisruptionBudget available",
+		metav1.ConditionTrue)
+	return nil
+}
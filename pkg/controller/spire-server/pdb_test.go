@@ -0,0 +1,182 @@
+package spire_server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+)
+
+func TestGenerateSpireServerPodDisruptionBudget(t *testing.T) {
+	t.Run("defaults to maxUnavailable 1 for the single replica server", func(t *testing.T) {
+		pdb := generateSpireServerPodDisruptionBudget(&v1alpha1.SpireServerSpec{})
+		if pdb.Name != "spire-server" {
+			t.Errorf("Expected name 'spire-server', got '%s'", pdb.Name)
+		}
+		if pdb.Spec.MaxUnavailable == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+			t.Errorf("Expected maxUnavailable 1, got %v", pdb.Spec.MaxUnavailable)
+		}
+		if pdb.Spec.MinAvailable != nil {
+			t.Errorf("Expected minAvailable to be unset, got %v", pdb.Spec.MinAvailable)
+		}
+	})
+
+	t.Run("selector matches the StatefulSet selector", func(t *testing.T) {
+		spec := &v1alpha1.SpireServerSpec{
+			Persistence:  v1alpha1.Persistence{Size: "1Gi", AccessMode: "ReadWriteOnce"},
+			CommonConfig: v1alpha1.CommonConfig{Labels: map[string]string{"custom": "label"}},
+		}
+		pdb := generateSpireServerPodDisruptionBudget(spec)
+		sts := GenerateSpireServerStatefulSet(spec, "", "")
+		if pdb.Labels["custom"] != "label" {
+			t.Error("Expected custom label on PodDisruptionBudget")
+		}
+		for k, v := range sts.Spec.Selector.MatchLabels {
+			if pdb.Spec.Selector.MatchLabels[k] != v {
+				t.Errorf("Expected selector label %s=%s, got %s", k, v, pdb.Spec.Selector.MatchLabels[k])
+			}
+		}
+		if len(pdb.Spec.Selector.MatchLabels) != len(sts.Spec.Selector.MatchLabels) {
+			t.Errorf("Expected selector %v, got %v", sts.Spec.Selector.MatchLabels, pdb.Spec.Selector.MatchLabels)
+		}
+	})
+
+	t.Run("uses configured minAvailable", func(t *testing.T) {
+		pdb := generateSpireServerPodDisruptionBudget(&v1alpha1.SpireServerSpec{
+			PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{MinAvailable: ptr.To(intstr.FromInt32(1))},
+		})
+		if pdb.Spec.MinAvailable == nil || pdb.Spec.MinAvailable.IntValue() != 1 {
+			t.Errorf("Expected minAvailable 1, got %v", pdb.Spec.MinAvailable)
+		}
+		if pdb.Spec.MaxUnavailable != nil {
+			t.Errorf("Expected maxUnavailable to be unset, got %v", pdb.Spec.MaxUnavailable)
+		}
+	})
+}
+
+func TestReconcilePodDisruptionBudget(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "spire-server")
+	existingPDB := func(fc *fakes.FakeCustomCtrlClient, maxUnavailable intstr.IntOrString) {
+		fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			if pdb, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+				desired := generateSpireServerPodDisruptionBudget(&v1alpha1.SpireServerSpec{})
+				*pdb = *desired
+				pdb.ResourceVersion = "123"
+				pdb.Spec.MaxUnavailable = &maxUnavailable
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name           string
+		spec           v1alpha1.SpireServerSpec
+		setupClient    func(*fakes.FakeCustomCtrlClient)
+		createOnlyMode bool
+		expectError    bool
+		expectCreate   int
+		expectUpdate   int
+		expectDelete   int
+	}{
+		{
+			name: "create when not found",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+			expectCreate: 1,
+		},
+		{
+			name: "create error",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+				fc.CreateReturns(errors.New("create failed"))
+			},
+			expectError:  true,
+			expectCreate: 1,
+		},
+		{
+			name: "get error",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(errors.New("connection refused"))
+			},
+			expectError: true,
+		},
+		{
+			name: "up to date",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPDB(fc, intstr.FromInt32(1))
+			},
+		},
+		{
+			name: "update when limits differ",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPDB(fc, intstr.FromInt32(2))
+			},
+			expectUpdate: 1,
+		},
+		{
+			name: "create only mode skips update",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPDB(fc, intstr.FromInt32(2))
+			},
+			createOnlyMode: true,
+		},
+		{
+			name: "disabled deletes existing",
+			spec: v1alpha1.SpireServerSpec{
+				PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{Enabled: "false"},
+			},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				existingPDB(fc, intstr.FromInt32(1))
+			},
+			expectDelete: 1,
+		},
+		{
+			name: "disabled and absent",
+			spec: v1alpha1.SpireServerSpec{
+				PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetConfig{Enabled: "false"},
+			},
+			setupClient: func(fc *fakes.FakeCustomCtrlClient) {
+				fc.GetReturns(notFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			tt.setupClient(fakeClient)
+			reconciler := newSATestReconciler(fakeClient)
+			server := &v1alpha1.SpireServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "test-uid"},
+				Spec:       tt.spec,
+			}
+			statusMgr := status.NewManager(fakeClient)
+
+			err := reconciler.reconcilePodDisruptionBudget(context.Background(), server, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, tt.createOnlyMode)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("reconcilePodDisruptionBudget() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if fakeClient.DeleteCallCount() != tt.expectDelete {
+				t.Errorf("Expected %d Delete calls, got %d", tt.expectDelete, fakeClient.DeleteCallCount())
+			}
+		})
+	}
+}
@@ -55,6 +55,11 @@ func (r *SpireServerReconciler) reconcileRBAC(ctx context.Context, server *v1alp
 		return err
 	}
 
+	// Exec access of the operator to the SPIRE server pod, for the SPIRE server CLI
+	if err := r.reconcileSpireServerExecRoleBinding(ctx, server, statusMgr, createOnlyMode); err != nil {
+		return err
+	}
+
 	// External cert RBAC (for federation route with externalSecretRef)
 	if err := r.reconcileExternalCertRBAC(ctx, server, statusMgr, createOnlyMode); err != nil {
 		return err
@@ -78,6 +83,7 @@ func (r *SpireServerReconciler) reconcileClusterRole(ctx context.Context, server
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.ClusterRole{}
@@ -103,6 +109,7 @@ func (r *SpireServerReconciler) reconcileClusterRole(ctx context.Context, server
 		}
 
 		r.log.Info("Created ClusterRole", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -129,6 +136,7 @@ func (r *SpireServerReconciler) reconcileClusterRole(ctx context.Context, server
 	}
 
 	r.log.Info("Updated ClusterRole", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -143,6 +151,7 @@ func (r *SpireServerReconciler) reconcileClusterRoleBinding(ctx context.Context,
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.ClusterRoleBinding{}
@@ -168,6 +177,7 @@ func (r *SpireServerReconciler) reconcileClusterRoleBinding(ctx context.Context,
 		}
 
 		r.log.Info("Created ClusterRoleBinding", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -194,12 +204,14 @@ func (r *SpireServerReconciler) reconcileClusterRoleBinding(ctx context.Context,
 	}
 
 	r.log.Info("Updated ClusterRoleBinding", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
 // reconcileSpireBundleRole reconciles the Spire Bundle Role
 func (r *SpireServerReconciler) reconcileSpireBundleRole(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) error {
 	desired := getSpireBundleRole(server.Spec.Labels)
+	addBundlePublisherConfigMapsToRole(desired, server.Spec.BundlePublishers)
 
 	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on spire-bundle role")
@@ -208,6 +220,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleRole(ctx context.Context, se
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.Role{}
@@ -233,6 +246,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleRole(ctx context.Context, se
 		}
 
 		r.log.Info("Created Role", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -259,6 +273,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleRole(ctx context.Context, se
 	}
 
 	r.log.Info("Updated Role", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -273,6 +288,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleRoleBinding(ctx context.Cont
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.RoleBinding{}
@@ -298,6 +314,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleRoleBinding(ctx context.Cont
 		}
 
 		r.log.Info("Created RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -324,6 +341,7 @@ func (r *SpireServerReconciler) reconcileSpireBundleRoleBinding(ctx context.Cont
 	}
 
 	r.log.Info("Updated RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -338,6 +356,7 @@ func (r *SpireServerReconciler) reconcileControllerManagerClusterRole(ctx contex
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.ClusterRole{}
@@ -363,6 +382,7 @@ func (r *SpireServerReconciler) reconcileControllerManagerClusterRole(ctx contex
 		}
 
 		r.log.Info("Created ClusterRole", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -389,6 +409,7 @@ func (r *SpireServerReconciler) reconcileControllerManagerClusterRole(ctx contex
 	}
 
 	r.log.Info("Updated ClusterRole", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -403,6 +424,7 @@ func (r *SpireServerReconciler) reconcileControllerManagerClusterRoleBinding(ctx
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.ClusterRoleBinding{}
@@ -428,6 +450,7 @@ func (r *SpireServerReconciler) reconcileControllerManagerClusterRoleBinding(ctx
 		}
 
 		r.log.Info("Created ClusterRoleBinding", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -454,6 +477,7 @@ func (r *SpireServerReconciler) reconcileControllerManagerClusterRoleBinding(ctx
 	}
 
 	r.log.Info("Updated ClusterRoleBinding", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -468,6 +492,7 @@ func (r *SpireServerReconciler) reconcileLeaderElectionRole(ctx context.Context,
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.Role{}
@@ -493,6 +518,7 @@ func (r *SpireServerReconciler) reconcileLeaderElectionRole(ctx context.Context,
 		}
 
 		r.log.Info("Created Role", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -519,6 +545,7 @@ func (r *SpireServerReconciler) reconcileLeaderElectionRole(ctx context.Context,
 	}
 
 	r.log.Info("Updated Role", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -533,6 +560,7 @@ func (r *SpireServerReconciler) reconcileLeaderElectionRoleBinding(ctx context.C
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.RoleBinding{}
@@ -558,6 +586,7 @@ func (r *SpireServerReconciler) reconcileLeaderElectionRoleBinding(ctx context.C
 		}
 
 		r.log.Info("Created RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -584,6 +613,7 @@ func (r *SpireServerReconciler) reconcileLeaderElectionRoleBinding(ctx context.C
 	}
 
 	r.log.Info("Updated RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -600,7 +630,7 @@ func getSpireServerClusterRoleBinding(customLabels map[string]string) *rbacv1.Cl
 	crb.Labels = utils.SpireServerLabels(customLabels)
 	// Update the subject namespace
 	for i := range crb.Subjects {
-		crb.Subjects[i].Namespace = utils.GetOperatorNamespace()
+		crb.Subjects[i].Namespace = utils.GetOperandNamespace()
 	}
 	return crb
 }
@@ -608,17 +638,17 @@ func getSpireServerClusterRoleBinding(customLabels map[string]string) *rbacv1.Cl
 func getSpireBundleRole(customLabels map[string]string) *rbacv1.Role {
 	role := utils.DecodeRoleObjBytes(assets.MustAsset(utils.SpireBundleRoleAssetName))
 	role.Labels = utils.SpireServerLabels(customLabels)
-	role.Namespace = utils.GetOperatorNamespace()
+	role.Namespace = utils.GetOperandNamespace()
 	return role
 }
 
 func getSpireBundleRoleBinding(customLabels map[string]string) *rbacv1.RoleBinding {
 	rb := utils.DecodeRoleBindingObjBytes(assets.MustAsset(utils.SpireBundleRoleBindingAssetName))
 	rb.Labels = utils.SpireServerLabels(customLabels)
-	rb.Namespace = utils.GetOperatorNamespace()
+	rb.Namespace = utils.GetOperandNamespace()
 	// Update the subject namespace
 	for i := range rb.Subjects {
-		rb.Subjects[i].Namespace = utils.GetOperatorNamespace()
+		rb.Subjects[i].Namespace = utils.GetOperandNamespace()
 	}
 	return rb
 }
@@ -634,7 +664,7 @@ func getSpireControllerManagerClusterRoleBinding(customLabels map[string]string)
 	crb.Labels = utils.SpireControllerManagerLabels(customLabels)
 	// Update the subject namespace
 	for i := range crb.Subjects {
-		crb.Subjects[i].Namespace = utils.GetOperatorNamespace()
+		crb.Subjects[i].Namespace = utils.GetOperandNamespace()
 	}
 	return crb
 }
@@ -642,21 +672,112 @@ func getSpireControllerManagerClusterRoleBinding(customLabels map[string]string)
 func getSpireControllerManagerLeaderElectionRole(customLabels map[string]string) *rbacv1.Role {
 	role := utils.DecodeRoleObjBytes(assets.MustAsset(utils.SpireControllerManagerLeaderElectionRoleAssetName))
 	role.Labels = utils.SpireControllerManagerLabels(customLabels)
-	role.Namespace = utils.GetOperatorNamespace()
+	role.Namespace = utils.GetOperandNamespace()
 	return role
 }
 
 func getSpireControllerManagerLeaderElectionRoleBinding(customLabels map[string]string) *rbacv1.RoleBinding {
 	rb := utils.DecodeRoleBindingObjBytes(assets.MustAsset(utils.SpireControllerManagerLeaderElectionRoleBindingAssetName))
 	rb.Labels = utils.SpireControllerManagerLabels(customLabels)
-	rb.Namespace = utils.GetOperatorNamespace()
+	rb.Namespace = utils.GetOperandNamespace()
 	// Update the subject namespace
 	for i := range rb.Subjects {
-		rb.Subjects[i].Namespace = utils.GetOperatorNamespace()
+		rb.Subjects[i].Namespace = utils.GetOperandNamespace()
 	}
 	return rb
 }
 
+// reconcileSpireServerExecRoleBinding reconciles the RoleBinding granting the operator the exec subresource
+// of the SPIRE server pod in the operand namespace
+func (r *SpireServerReconciler) reconcileSpireServerExecRoleBinding(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) error {
+	desired := getSpireServerExecRoleBinding(server.Spec.Labels)
+
+	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
+		r.log.Error(err, "failed to set controller reference on spire-server exec role binding")
+		statusMgr.AddCondition(RBACAvailable, v1alpha1.ReasonFailed,
+			fmt.Sprintf("Failed to set owner reference on exec RoleBinding: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(desired)
+
+	// Get existing resource (from cache)
+	existing := &rbacv1.RoleBinding{}
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			// Unexpected error
+			r.log.Error(err, "failed to get spire-server exec role binding")
+			statusMgr.AddCondition(RBACAvailable, v1alpha1.ReasonFailed,
+				fmt.Sprintf("Failed to get exec RoleBinding: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+
+		// Resource doesn't exist, create it
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			r.log.Error(err, "failed to create spire-server exec role binding")
+			statusMgr.AddCondition(RBACAvailable, v1alpha1.ReasonFailed,
+				fmt.Sprintf("Failed to create exec RoleBinding: %v", err),
+				metav1.ConditionFalse)
+			return err
+		}
+
+		r.log.Info("Created RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
+		return nil
+	}
+
+	// Resource exists, check if we need to update
+	if createOnlyMode {
+		r.log.V(1).Info("RoleBinding exists, skipping update due to create-only mode", "name", desired.Name)
+		return nil
+	}
+
+	// Check if update is needed
+	if !utils.ResourceNeedsUpdate(existing, desired) {
+		r.log.V(1).Info("RoleBinding is up to date", "name", desired.Name)
+		return nil
+	}
+
+	// Update the resource
+	desired.ResourceVersion = existing.ResourceVersion
+	if err := r.ctrlClient.Update(ctx, desired); err != nil {
+		r.log.Error(err, "failed to update spire-server exec role binding")
+		statusMgr.AddCondition(RBACAvailable, v1alpha1.ReasonFailed,
+			fmt.Sprintf("Failed to update exec RoleBinding: %v", err),
+			metav1.ConditionFalse)
+		return err
+	}
+
+	r.log.Info("Updated RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
+	return nil
+}
+
+// getSpireServerExecRoleBinding binds the exec ClusterRole installed with the operator to the operator
+// ServiceAccount in the operand namespace only, so that the operator can't exec into other pods
+func getSpireServerExecRoleBinding(customLabels map[string]string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.SpireServerExecRoleBindingName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.SpireServerLabels(customLabels),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     utils.SpireServerExecClusterRoleName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      utils.GetOperatorServiceAccount(),
+			Namespace: utils.GetOperatorNamespace(),
+		}},
+	}
+}
+
 // reconcileExternalCertRBAC reconciles RBAC resources for router access to external certificate secret
 func (r *SpireServerReconciler) reconcileExternalCertRBAC(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) error {
 	// Only create RBAC if federation is enabled with https_web profile and externalSecretRef is configured
@@ -692,6 +813,7 @@ func (r *SpireServerReconciler) reconcileExternalCertRole(ctx context.Context, s
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.Role{}
@@ -717,6 +839,7 @@ func (r *SpireServerReconciler) reconcileExternalCertRole(ctx context.Context, s
 		}
 
 		r.log.Info("Created external cert Role", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -743,6 +866,7 @@ func (r *SpireServerReconciler) reconcileExternalCertRole(ctx context.Context, s
 	}
 
 	r.log.Info("Updated external cert Role", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -757,6 +881,7 @@ func (r *SpireServerReconciler) reconcileExternalCertRoleBinding(ctx context.Con
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &rbacv1.RoleBinding{}
@@ -782,6 +907,7 @@ func (r *SpireServerReconciler) reconcileExternalCertRoleBinding(ctx context.Con
 		}
 
 		r.log.Info("Created external cert RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -808,6 +934,7 @@ func (r *SpireServerReconciler) reconcileExternalCertRoleBinding(ctx context.Con
 	}
 
 	r.log.Info("Updated external cert RoleBinding", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -830,14 +957,14 @@ func getExternalSecretRefFromServer(server *v1alpha1.SpireServer) string {
 func getSpireServerExternalCertRole(customLabels map[string]string) *rbacv1.Role {
 	role := utils.DecodeRoleObjBytes(assets.MustAsset(utils.SpireServerExternalCertRoleAssetName))
 	role.Labels = utils.SpireServerLabels(customLabels)
-	role.Namespace = utils.GetOperatorNamespace()
+	role.Namespace = utils.GetOperandNamespace()
 	return role
 }
 
 func getSpireServerExternalCertRoleBinding(customLabels map[string]string) *rbacv1.RoleBinding {
 	rb := utils.DecodeRoleBindingObjBytes(assets.MustAsset(utils.SpireServerExternalCertRoleBindingAssetName))
 	rb.Labels = utils.SpireServerLabels(customLabels)
-	rb.Namespace = utils.GetOperatorNamespace()
+	rb.Namespace = utils.GetOperandNamespace()
 	// Note: subjects namespace (openshift-ingress) is already set in the template
 	return rb
 }
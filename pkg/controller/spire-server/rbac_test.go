@@ -137,7 +137,7 @@ func newFakeClient(store *testStore) customClient.CustomCtrlClient {
 		return true, nil
 	}
 
-	fake.CreateOrUpdateObjectStub = func(ctx context.Context, obj client.Object) error {
+	fake.ApplyStub = func(ctx context.Context, obj client.Object, opts ...client.PatchOption) error {
 		err := store.Create(ctx, obj)
 		if err != nil && kerrors.IsAlreadyExists(err) {
 			return store.Update(ctx, obj)
@@ -1477,6 +1477,44 @@ func TestReconcileLeaderElectionRoleBinding(t *testing.T) {
 
 // Tests for newly added external cert RBAC functions
 
+func TestGetSpireServerExecRoleBinding(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "ztwim-operator")
+	t.Setenv("OPERATOR_SERVICE_ACCOUNT", "ztwim-controller-manager")
+
+	rb := getSpireServerExecRoleBinding(map[string]string{"team": "platform"})
+	if rb.Name != utils.SpireServerExecRoleBindingName || rb.Namespace != utils.GetOperandNamespace() {
+		t.Errorf("Expected RoleBinding %s in the operand namespace, got %s/%s", utils.SpireServerExecRoleBindingName, rb.Namespace, rb.Name)
+	}
+	if rb.RoleRef.Kind != "ClusterRole" || rb.RoleRef.Name != utils.SpireServerExecClusterRoleName {
+		t.Errorf("Expected the exec ClusterRole to be bound, got %+v", rb.RoleRef)
+	}
+	if len(rb.Subjects) != 1 || rb.Subjects[0].Kind != rbacv1.ServiceAccountKind ||
+		rb.Subjects[0].Name != "ztwim-controller-manager" || rb.Subjects[0].Namespace != "ztwim-operator" {
+		t.Errorf("Expected the operator ServiceAccount as the only subject, got %+v", rb.Subjects)
+	}
+	if rb.Labels["team"] != "platform" {
+		t.Errorf("Expected custom label 'team=platform', got %v", rb.Labels)
+	}
+}
+
+func TestReconcileSpireServerExecRoleBinding(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newRBACTestReconciler(fakeClient)
+	fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, utils.SpireServerExecRoleBindingName))
+
+	statusMgr := status.NewManager(fakeClient)
+	if err := reconciler.reconcileSpireServerExecRoleBinding(context.Background(), createRBACTestServer(), statusMgr, false); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if fakeClient.CreateCallCount() != 1 {
+		t.Fatalf("Expected Create to be called once, called %d times", fakeClient.CreateCallCount())
+	}
+	_, obj, _ := fakeClient.CreateArgsForCall(0)
+	if rb, ok := obj.(*rbacv1.RoleBinding); !ok || rb.Name != utils.SpireServerExecRoleBindingName || len(rb.OwnerReferences) != 1 {
+		t.Errorf("Expected the exec RoleBinding owned by the SpireServer to be created, got %v", obj)
+	}
+}
+
 func TestGetSpireServerExternalCertRole(t *testing.T) {
 	tests := []struct {
 		name         string
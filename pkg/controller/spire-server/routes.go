@@ -99,6 +99,7 @@ func (r *SpireServerReconciler) reconcileRoute(ctx context.Context, server *v1al
 	if utils.StringToBool(server.Spec.Federation.ManagedRoute) {
 		// Create Route for federation endpoint
 		route := generateFederationRoute(server, ztwim)
+		statusMgr.TrackResource(route)
 
 		var existingRoute routev1.Route
 		err := r.ctrlClient.Get(ctx, types.NamespacedName{
@@ -121,6 +122,7 @@ func (r *SpireServerReconciler) reconcileRoute(ctx context.Context, server *v1al
 					metav1.ConditionTrue)
 
 				r.log.Info("Created federation route", "Namespace", route.Namespace, "Name", route.Name)
+				statusMgr.RecordResourceCreated(route)
 			} else {
 				r.log.Error(err, "Failed to get existing federation route")
 				statusMgr.AddCondition(RouteAvailable, "FederationRouteRetrievalFailed",
@@ -149,6 +151,7 @@ func (r *SpireServerReconciler) reconcileRoute(ctx context.Context, server *v1al
 					metav1.ConditionTrue)
 
 				r.log.Info("Updated federation route", "Namespace", route.Namespace, "Name", route.Name)
+				statusMgr.RecordDriftRepaired(route)
 			}
 		} else {
 			// Route exists and is up to date - only update status if it's currently not ready
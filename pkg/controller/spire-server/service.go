@@ -47,6 +47,7 @@ func (r *SpireServerReconciler) reconcileSpireServerService(ctx context.Context,
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.Service{}
@@ -72,6 +73,7 @@ func (r *SpireServerReconciler) reconcileSpireServerService(ctx context.Context,
 		}
 
 		r.log.Info("Created Service", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -116,6 +118,7 @@ func (r *SpireServerReconciler) reconcileSpireServerService(ctx context.Context,
 	}
 
 	r.log.Info("Updated Service", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -130,6 +133,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerService(ctx conte
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.Service{}
@@ -155,6 +159,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerService(ctx conte
 		}
 
 		r.log.Info("Created Service", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		return nil
 	}
 
@@ -199,6 +204,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerService(ctx conte
 	}
 
 	r.log.Info("Updated Service", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	return nil
 }
 
@@ -206,7 +212,7 @@ func (r *SpireServerReconciler) reconcileSpireControllerManagerService(ctx conte
 func getSpireServerService(config *v1alpha1.SpireServerSpec) *corev1.Service {
 	svc := utils.DecodeServiceObjBytes(assets.MustAsset(utils.SpireServerServiceAssetName))
 	svc.Labels = utils.SpireServerLabels(config.Labels)
-	svc.Namespace = utils.GetOperatorNamespace()
+	svc.Namespace = utils.GetOperandNamespace()
 	svc.Spec.Selector = map[string]string{
 		"app.kubernetes.io/name":     "spire-server",
 		"app.kubernetes.io/instance": utils.StandardInstance,
@@ -250,7 +256,7 @@ func getSpireServerService(config *v1alpha1.SpireServerSpec) *corev1.Service {
 func getSpireControllerManagerWebhookService(customLabels map[string]string) *corev1.Service {
 	svc := utils.DecodeServiceObjBytes(assets.MustAsset(utils.SpireControllerManagerWebhookServiceAssetName))
 	svc.Labels = utils.SpireControllerManagerLabels(customLabels)
-	svc.Namespace = utils.GetOperatorNamespace()
+	svc.Namespace = utils.GetOperandNamespace()
 	svc.Spec.Selector = map[string]string{
 		"app.kubernetes.io/name":     "spire-controller-manager",
 		"app.kubernetes.io/instance": utils.StandardInstance,
@@ -3,6 +3,7 @@ package spire_server
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -20,7 +21,7 @@ import (
 
 // reconcileServiceAccount reconciles the Spire Server ServiceAccount
 func (r *SpireServerReconciler) reconcileServiceAccount(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool) error {
-	desired := getSpireServerServiceAccount(server.Spec.Labels)
+	desired := getSpireServerServiceAccount(server.Spec.Labels, server.Spec.ServiceAccountAnnotations)
 
 	if err := controllerutil.SetControllerReference(server, desired, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on service account")
@@ -29,6 +30,7 @@ func (r *SpireServerReconciler) reconcileServiceAccount(ctx context.Context, ser
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &corev1.ServiceAccount{}
@@ -54,6 +56,7 @@ func (r *SpireServerReconciler) reconcileServiceAccount(ctx context.Context, ser
 		}
 
 		r.log.Info("Created ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 			"All ServiceAccount resources available",
 			metav1.ConditionTrue)
@@ -89,16 +92,18 @@ func (r *SpireServerReconciler) reconcileServiceAccount(ctx context.Context, ser
 	}
 
 	r.log.Info("Updated ServiceAccount", "name", desired.Name, "namespace", desired.Namespace)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(ServiceAccountAvailable, v1alpha1.ReasonReady,
 		"All ServiceAccount resources available",
 		metav1.ConditionTrue)
 	return nil
 }
 
-// getSpireServerServiceAccount returns the Spire Server ServiceAccount with proper labels
-func getSpireServerServiceAccount(customLabels map[string]string) *corev1.ServiceAccount {
+// getSpireServerServiceAccount returns the Spire Server ServiceAccount with proper labels and annotations
+func getSpireServerServiceAccount(customLabels, annotations map[string]string) *corev1.ServiceAccount {
 	sa := utils.DecodeServiceAccountObjBytes(assets.MustAsset(utils.SpireServerServiceAccountAssetName))
 	sa.Labels = utils.SpireServerLabels(customLabels)
-	sa.Namespace = utils.GetOperatorNamespace()
+	sa.Namespace = utils.GetOperandNamespace()
+	sa.Annotations = maps.Clone(annotations)
 	return sa
 }
@@ -39,7 +39,7 @@ func TestGetSpireServerServiceAccount(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sa := getSpireServerServiceAccount(tt.customLabels)
+			sa := getSpireServerServiceAccount(tt.customLabels, nil)
 
 			if sa == nil {
 				t.Fatal("Expected ServiceAccount, got nil")
@@ -77,14 +77,14 @@ func TestGetSpireServerServiceAccount(t *testing.T) {
 	}
 
 	t.Run("preserves all asset labels", func(t *testing.T) {
-		saWithoutCustom := getSpireServerServiceAccount(nil)
+		saWithoutCustom := getSpireServerServiceAccount(nil, nil)
 		assetLabels := make(map[string]string)
 		for k, v := range saWithoutCustom.Labels {
 			assetLabels[k] = v
 		}
 
 		customLabels := map[string]string{"deployment-id": "prod-123"}
-		saWithCustom := getSpireServerServiceAccount(customLabels)
+		saWithCustom := getSpireServerServiceAccount(customLabels, nil)
 
 		for k, v := range assetLabels {
 			if saWithCustom.Labels[k] != v {
@@ -96,6 +96,19 @@ func TestGetSpireServerServiceAccount(t *testing.T) {
 			t.Errorf("Custom label was not added")
 		}
 	})
+
+	t.Run("sets the configured annotations", func(t *testing.T) {
+		annotations := map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::111122223333:role/spire-server"}
+		sa := getSpireServerServiceAccount(nil, annotations)
+		if sa.Annotations["eks.amazonaws.com/role-arn"] != "arn:aws:iam::111122223333:role/spire-server" {
+			t.Errorf("Expected the role annotation, got %v", sa.Annotations)
+		}
+
+		existing := getSpireServerServiceAccount(nil, nil)
+		if !utils.ResourceNeedsUpdate(existing, sa) {
+			t.Error("Expected an update when an annotation is added")
+		}
+	})
 }
 
 // newSATestReconciler creates a reconciler for ServiceAccount tests
@@ -20,6 +20,7 @@ import (
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 const (
@@ -30,8 +31,50 @@ const (
 )
 
 // reconcileStatefulSet reconciles the Spire Server StatefulSet
-func (r *SpireServerReconciler) reconcileStatefulSet(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, createOnlyMode bool, spireServerConfigMapHash, spireControllerManagerConfigMapHash string) error {
+func (r *SpireServerReconciler) reconcileStatefulSet(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool, spireServerConfigMapHash, spireControllerManagerConfigMapHash string) error {
+	_, renderSpan := tracing.Start(ctx, "Render SPIRE server StatefulSet")
 	sts := GenerateSpireServerStatefulSet(&server.Spec, spireServerConfigMapHash, spireControllerManagerConfigMapHash)
+	tracing.End(renderSpan, nil)
+	// Size the pods for the profile of the cluster before the user provided containers are added
+	utils.ApplyDeploymentProfile(&sts.Spec.Template.Spec, ztwim)
+	// The liveness probe timings of the spec take precedence over the profile
+	utils.ApplyProbeTimings(sts.Spec.Template.Spec.Containers[0].LivenessProbe, server.Spec.LivenessProbe)
+	if err := utils.AddExtraVolumes(&sts.Spec.Template.Spec, "spire-server", server.Spec.ExtraVolumes, server.Spec.ExtraVolumeMounts); err != nil {
+		r.log.Error(err, "failed to add the extra volumes to the spire server stateful set resource")
+		statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.AddContainerEnv(&sts.Spec.Template.Spec, "spire-server", server.Spec.Env, server.Spec.EnvFrom); err != nil {
+		r.log.Error(err, "failed to add the environment variables to the spire server stateful set resource")
+		statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.ApplySecurityContext(&sts.Spec.Template.Spec, "spire-server", server.Spec.SecurityContext, server.Spec.PodSecurityContext); err != nil {
+		r.log.Error(err, "failed to apply the security context overrides to the spire server stateful set resource")
+		statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	if err := utils.AddExtraContainers(&sts.Spec.Template.Spec, server.Spec.ExtraContainers, server.Spec.ExtraInitContainers); err != nil {
+		r.log.Error(err, "failed to add the extra containers to the spire server stateful set resource")
+		statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetGenerationFailed",
+			err.Error(),
+			metav1.ConditionFalse)
+		return err
+	}
+	// The Tornjak backend runs alongside the SPIRE server to reach its admin socket
+	if utils.IsTornjakEnabled(ztwim.Spec.Tornjak) {
+		addTornjakBackendToStatefulSet(sts, ztwim.Spec.Tornjak)
+	}
+	// The SPIRE server reaches the hosted cluster through its kubeconfig
+	if utils.GetTopologyMode(ztwim) == v1alpha1.TopologyManagementCluster {
+		addHostedClusterKubeconfigToStatefulSet(sts, ztwim.Spec.Topology)
+	}
 	if err := controllerutil.SetControllerReference(server, sts, r.scheme); err != nil {
 		r.log.Error(err, "failed to set controller reference on spire server stateful set resource")
 		statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetGenerationFailed",
@@ -39,29 +82,34 @@ func (r *SpireServerReconciler) reconcileStatefulSet(ctx context.Context, server
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(sts)
 
 	var existingSTS appsv1.StatefulSet
 	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: sts.Name, Namespace: sts.Namespace}, &existingSTS)
 	if err != nil && kerrors.IsNotFound(err) {
-		if err = r.ctrlClient.Create(ctx, sts); err != nil {
+		if err = r.ctrlClient.Apply(ctx, sts); err != nil {
 			statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetCreationFailed",
 				err.Error(),
 				metav1.ConditionFalse)
 			return fmt.Errorf("failed to create StatefulSet: %w", err)
 		}
 		r.log.Info("Created spire server StatefulSet")
+		statusMgr.RecordResourceCreated(sts)
 	} else if err == nil && needsUpdate(existingSTS, *sts) {
 		if createOnlyMode {
 			r.log.Info("Skipping StatefulSet update due to create-only mode")
+		} else if !r.upgradeAllowed(server, &existingSTS, sts, statusMgr) {
+			r.log.Info("Skipping StatefulSet update due to an unsafe SPIRE server version change")
 		} else {
-			sts.ResourceVersion = existingSTS.ResourceVersion
-			if err = r.ctrlClient.Update(ctx, sts); err != nil {
+			if err = r.ctrlClient.Apply(ctx, sts); err != nil {
 				statusMgr.AddCondition(StatefulSetAvailable, "SpireServerStatefulSetUpdateFailed",
 					err.Error(),
 					metav1.ConditionFalse)
 				return fmt.Errorf("failed to update StatefulSet: %w", err)
 			}
 			r.log.Info("Updated spire server StatefulSet")
+			statusMgr.RecordWorkloadUpdated(sts, &existingSTS.Spec.Template, &sts.Spec.Template,
+				spireServerStatefulSetSpireServerConfigHashAnnotationKey, spireServerStatefulSetSpireControllerManagerConfigHashAnnotationKey)
 		}
 	} else if err != nil {
 		r.log.Error(err, "failed to get spire server stateful set resource")
@@ -77,9 +125,62 @@ func (r *SpireServerReconciler) reconcileStatefulSet(ctx context.Context, server
 	return nil
 }
 
+// defaultServerHealthPort is the port of the SPIRE server health checks used when the spec does not set one
+const defaultServerHealthPort int32 = 8080
+
+// getServerHealthPort returns the port the SPIRE server serves its health checks on
+func getServerHealthPort(config *v1alpha1.SpireServerSpec) int32 {
+	if config.HealthPort != 0 {
+		return config.HealthPort
+	}
+	return defaultServerHealthPort
+}
+
+// getServerLivenessProbe returns the liveness probe of the SPIRE server with the timings of the spec applied
+// over the defaults
+func getServerLivenessProbe(config *v1alpha1.SpireServerSpec) *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/live", Port: intstr.FromString(spireServerHealthPort)}},
+		InitialDelaySeconds: 15,
+		PeriodSeconds:       60,
+		TimeoutSeconds:      3,
+		FailureThreshold:    2,
+	}
+	utils.ApplyProbeTimings(probe, config.LivenessProbe)
+	return probe
+}
+
+// getServerReadinessProbe returns the readiness probe of the SPIRE server with the timings of the spec applied
+// over the defaults
+func getServerReadinessProbe(config *v1alpha1.SpireServerSpec) *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString(spireServerHealthPort)}},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       5,
+	}
+	utils.ApplyProbeTimings(probe, config.ReadinessProbe)
+	return probe
+}
+
+// getServerStartupProbe returns the startup probe of the SPIRE server, only set when the spec configures it
+func getServerStartupProbe(config *v1alpha1.SpireServerSpec) *corev1.Probe {
+	if config.StartupProbe == nil {
+		return nil
+	}
+	probe := &corev1.Probe{
+		ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/live", Port: intstr.FromString(spireServerHealthPort)}},
+		PeriodSeconds:    10,
+		FailureThreshold: 30,
+	}
+	utils.ApplyProbeTimings(probe, config.StartupProbe)
+	return probe
+}
+
 const (
 	// DBTLSMountPath is the fixed mount path for database TLS certificates
 	DBTLSMountPath = "/run/spire/db/certs"
+	// UpstreamAgentSocketMountPath is the fixed mount path for the Workload API socket of the upstream agent
+	UpstreamAgentSocketMountPath = "/run/spire/upstream-agent"
 )
 
 func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
@@ -88,6 +189,7 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 
 	// Generate standardized labels once and reuse them
 	labels := utils.SpireServerLabels(config.Labels)
+	labels[utils.VersionLabelKey] = utils.SpireServerOperand.Version(config.Version)
 
 	// For selectors, we need only the core identifying labels (without custom user labels)
 	selectorLabels := map[string]string{
@@ -97,7 +199,7 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 	}
 
 	// Persistence is required, so we can directly access its fields.
-	// Fields have defaults: Size="1Gi", AccessMode="ReadWriteOnce", StorageClass=""
+	// Fields have defaults: Type="PersistentVolumeClaim", Size="1Gi", AccessMode="ReadWriteOnce", StorageClass=""
 	volumeResourceRequest := config.Persistence.Size
 	volumeAccessMode := corev1.PersistentVolumeAccessMode(config.Persistence.AccessMode)
 
@@ -123,6 +225,24 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 		{Name: "controller-manager-config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "spire-controller-manager"}}}},
 	}
 
+	// Add the Workload API volume of the upstream agent if the CA is signed by an upstream SPIRE server
+	if config.UpstreamAuthority != nil && config.UpstreamAuthority.Spire != nil {
+		spireServerVolumeMounts = append(spireServerVolumeMounts, corev1.VolumeMount{
+			Name:      "upstream-agent-socket",
+			MountPath: UpstreamAgentSocketMountPath,
+			ReadOnly:  true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "upstream-agent-socket",
+			VolumeSource: corev1.VolumeSource{
+				CSI: &corev1.CSIVolumeSource{
+					Driver:   config.UpstreamAuthority.Spire.WorkloadAPICSIDriver,
+					ReadOnly: ptr.To(true),
+				},
+			},
+		})
+	}
+
 	// Add database TLS Secret volume and mount if configured
 	if config.Datastore.TLSSecretName != "" {
 		// Add volume mount for the TLS secret at fixed path
@@ -144,7 +264,7 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 	sts := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "spire-server",
-			Namespace: utils.GetOperatorNamespace(),
+			Namespace: utils.GetOperandNamespace(),
 			Labels:    labels,
 		},
 		Spec: appsv1.StatefulSetSpec{
@@ -164,13 +284,15 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName:    "spire-server",
+					PriorityClassName:     config.PriorityClassName,
+					ImagePullSecrets:      config.ImagePullSecrets,
 					Containers: []corev1.Container{
 						{
 							SecurityContext: &corev1.SecurityContext{
 								ReadOnlyRootFilesystem: ptr.To(true),
 							},
 							Name:            "spire-server",
-							Image:           utils.GetSpireServerImage(),
+							Image:           utils.SpireServerOperand.Image(config.Version),
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							Args:            []string{"-expandEnv", "-config", "/run/spire/config/server.conf"},
 							Env: []corev1.EnvVar{
@@ -178,22 +300,13 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 							},
 							Ports: []corev1.ContainerPort{
 								{Name: "grpc", ContainerPort: 8081, Protocol: corev1.ProtocolTCP},
-								{Name: spireServerHealthPort, ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
-							},
-							LivenessProbe: &corev1.Probe{
-								ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/live", Port: intstr.FromString(spireServerHealthPort)}},
-								InitialDelaySeconds: 15,
-								PeriodSeconds:       60,
-								TimeoutSeconds:      3,
-								FailureThreshold:    2,
-							},
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString(spireServerHealthPort)}},
-								InitialDelaySeconds: 5,
-								PeriodSeconds:       5,
+								{Name: spireServerHealthPort, ContainerPort: getServerHealthPort(config), Protocol: corev1.ProtocolTCP},
 							},
-							Resources:    utils.DerefResourceRequirements(config.Resources),
-							VolumeMounts: spireServerVolumeMounts,
+							LivenessProbe:  getServerLivenessProbe(config),
+							ReadinessProbe: getServerReadinessProbe(config),
+							StartupProbe:   getServerStartupProbe(config),
+							Resources:      utils.DerefResourceRequirements(config.Resources),
+							VolumeMounts:   spireServerVolumeMounts,
 						},
 						{
 							SecurityContext: &corev1.SecurityContext{
@@ -224,27 +337,54 @@ func GenerateSpireServerStatefulSet(config *v1alpha1.SpireServerSpec,
 							Resources: utils.DerefResourceRequirements(config.Resources),
 						},
 					},
-					Volumes:      volumes,
-					Affinity:     config.Affinity,
-					NodeSelector: utils.DerefNodeSelector(config.NodeSelector),
-					Tolerations:  utils.DerefTolerations(config.Tolerations),
+					Volumes:                   volumes,
+					Affinity:                  utils.PlatformAffinity(config.Affinity, config.NodePlatform),
+					NodeSelector:              utils.PlatformNodeSelector(config.NodeSelector, config.NodePlatform),
+					TopologySpreadConstraints: utils.TopologySpreadConstraints(config.TopologySpreadConstraints, selectorLabels),
+					Tolerations:               utils.DerefTolerations(config.Tolerations),
 				},
 			},
-			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
-				{
-					ObjectMeta: metav1.ObjectMeta{Name: "spire-data"},
-					Spec: corev1.PersistentVolumeClaimSpec{
-						AccessModes:      []corev1.PersistentVolumeAccessMode{volumeAccessMode},
-						StorageClassName: storageClassName,
-						Resources: corev1.VolumeResourceRequirements{
-							Requests: corev1.ResourceList{
-								corev1.ResourceStorage: resource.MustParse(volumeResourceRequest),
-							},
+		},
+	}
+
+	// The data directory is an emptyDir without persistent storage, otherwise a PVC is provisioned for it
+	if config.Persistence.Type == utils.PersistenceTypeEmptyDir {
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "spire-data",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: ptr.To(resource.MustParse(volumeResourceRequest))},
+			},
+		})
+	} else {
+		sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "spire-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes:      []corev1.PersistentVolumeAccessMode{volumeAccessMode},
+					StorageClassName: storageClassName,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(volumeResourceRequest),
 						},
 					},
 				},
 			},
-		},
+		}
+	}
+
+	// Restore the datastore from a backup before the SPIRE server starts
+	if config.Backup != nil && config.Backup.RestoreFrom != "" {
+		addDatastoreRestoreToStatefulSet(sts, config)
+	}
+
+	// Expose the credentials of the bundle publishers to the SPIRE server
+	if config.BundlePublishers != nil {
+		addBundlePublishersToStatefulSet(sts, config.BundlePublishers)
+	}
+
+	// Mount the binaries of the external CredentialComposer plugins
+	if config.CredentialComposers != nil {
+		addCredentialComposersToStatefulSet(sts, config.CredentialComposers)
 	}
 
 	// Add proxy configuration if enabled
@@ -940,11 +940,15 @@ func TestReconcileStatefulSet(t *testing.T) {
 					return nil
 				}
 			}
-			fakeClient.CreateReturns(tt.createError)
-			fakeClient.UpdateReturns(tt.updateError)
+			// Both the create and the update go through server-side apply
+			if tt.createError != nil {
+				fakeClient.ApplyReturns(tt.createError)
+			} else {
+				fakeClient.ApplyReturns(tt.updateError)
+			}
 
 			statusMgr := status.NewManager(fakeClient)
-			err := reconciler.reconcileStatefulSet(context.Background(), server, statusMgr, tt.createOnlyMode, "server-hash", "controller-hash")
+			err := reconciler.reconcileStatefulSet(context.Background(), server, statusMgr, &v1alpha1.ZeroTrustWorkloadIdentityManager{}, tt.createOnlyMode, "server-hash", "controller-hash")
 
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -952,15 +956,127 @@ func TestReconcileStatefulSet(t *testing.T) {
 			if !tt.expectError && err != nil {
 				t.Errorf("Expected no error, got: %v", err)
 			}
-			if tt.expectCreate && fakeClient.CreateCallCount() != 1 {
-				t.Errorf("Expected Create called once, got %d", fakeClient.CreateCallCount())
+			if (tt.expectCreate || tt.expectUpdate) && fakeClient.ApplyCallCount() != 1 {
+				t.Errorf("Expected Apply called once, got %d", fakeClient.ApplyCallCount())
 			}
-			if tt.expectUpdate && fakeClient.UpdateCallCount() != 1 {
-				t.Errorf("Expected Update called once, got %d", fakeClient.UpdateCallCount())
+			if fakeClient.CreateCallCount() != 0 || fakeClient.UpdateCallCount() != 0 {
+				t.Error("Expected the StatefulSet to be applied instead of created or updated")
 			}
-			if tt.createOnlyMode && fakeClient.UpdateCallCount() != 0 {
-				t.Error("Expected Update not called in create-only mode")
+			if tt.createOnlyMode && fakeClient.ApplyCallCount() != 0 {
+				t.Error("Expected Apply not called in create-only mode")
 			}
 		})
 	}
 }
+
+func TestGenerateSpireServerStatefulSetWithUpstreamAuthority(t *testing.T) {
+	config := &v1alpha1.SpireServerSpec{
+		Persistence: v1alpha1.Persistence{
+			Size:       "1Gi",
+			AccessMode: "ReadWriteOnce",
+		},
+		UpstreamAuthority: &v1alpha1.UpstreamAuthorityConfig{
+			Spire: &v1alpha1.SpireUpstreamAuthorityConfig{
+				ServerAddress:        "spire-server.root.example.org",
+				WorkloadAPICSIDriver: "upstream.csi.spiffe.io",
+			},
+		},
+	}
+
+	sts := GenerateSpireServerStatefulSet(config, "test-hash", "test-hash")
+
+	var upstreamVolume *corev1.Volume
+	for i, vol := range sts.Spec.Template.Spec.Volumes {
+		if vol.Name == "upstream-agent-socket" {
+			upstreamVolume = &sts.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if upstreamVolume == nil || upstreamVolume.CSI == nil || upstreamVolume.CSI.Driver != "upstream.csi.spiffe.io" {
+		t.Fatalf("Expected a CSI volume of the upstream agent driver, got %v", upstreamVolume)
+	}
+
+	spireServerContainer := findContainerByName(sts.Spec.Template.Spec.Containers, "spire-server")
+	if spireServerContainer == nil {
+		t.Fatal("spire-server container not found")
+	}
+	mountFound := false
+	for _, mount := range spireServerContainer.VolumeMounts {
+		if mount.Name == "upstream-agent-socket" && mount.MountPath == UpstreamAgentSocketMountPath && mount.ReadOnly {
+			mountFound = true
+		}
+	}
+	if !mountFound {
+		t.Errorf("Expected the upstream agent socket to be mounted read-only at %s", UpstreamAgentSocketMountPath)
+	}
+}
+
+func TestGenerateSpireServerStatefulSetWithEmptyDirPersistence(t *testing.T) {
+	config := &v1alpha1.SpireServerSpec{
+		Persistence: v1alpha1.Persistence{
+			Type:       "EmptyDir",
+			Size:       "2Gi",
+			AccessMode: "ReadWriteOnce",
+		},
+	}
+
+	sts := GenerateSpireServerStatefulSet(config, "test-hash", "test-hash")
+
+	if len(sts.Spec.VolumeClaimTemplates) != 0 {
+		t.Errorf("Expected no volume claim templates, got %d", len(sts.Spec.VolumeClaimTemplates))
+	}
+	var dataVolume *corev1.Volume
+	for i, vol := range sts.Spec.Template.Spec.Volumes {
+		if vol.Name == "spire-data" {
+			dataVolume = &sts.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if dataVolume == nil || dataVolume.EmptyDir == nil {
+		t.Fatalf("Expected an emptyDir spire-data volume, got %v", dataVolume)
+	}
+	if dataVolume.EmptyDir.SizeLimit == nil || dataVolume.EmptyDir.SizeLimit.String() != "2Gi" {
+		t.Errorf("Expected the emptyDir to be limited to 2Gi, got %v", dataVolume.EmptyDir.SizeLimit)
+	}
+}
+
+func TestGenerateSpireServerStatefulSetWithProbes(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		sts := GenerateSpireServerStatefulSet(&v1alpha1.SpireServerSpec{Persistence: v1alpha1.Persistence{Size: "1Gi"}}, "test-hash", "test-hash")
+		container := sts.Spec.Template.Spec.Containers[0]
+
+		if container.Ports[1].ContainerPort != 8080 {
+			t.Errorf("Expected the health port 8080, got %d", container.Ports[1].ContainerPort)
+		}
+		if container.LivenessProbe.PeriodSeconds != 60 || container.LivenessProbe.FailureThreshold != 2 {
+			t.Errorf("Expected the default liveness probe, got %+v", container.LivenessProbe)
+		}
+		if container.StartupProbe != nil {
+			t.Errorf("Expected no startup probe, got %+v", container.StartupProbe)
+		}
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		config := &v1alpha1.SpireServerSpec{
+			Persistence:    v1alpha1.Persistence{Size: "1Gi"},
+			HealthPort:     8090,
+			LivenessProbe:  &v1alpha1.ProbeTimings{FailureThreshold: ptr.To(int32(5))},
+			ReadinessProbe: &v1alpha1.ProbeTimings{PeriodSeconds: ptr.To(int32(15))},
+			StartupProbe:   &v1alpha1.ProbeTimings{FailureThreshold: ptr.To(int32(60))},
+		}
+		sts := GenerateSpireServerStatefulSet(config, "test-hash", "test-hash")
+		container := sts.Spec.Template.Spec.Containers[0]
+
+		if container.Ports[1].ContainerPort != 8090 {
+			t.Errorf("Expected the health port 8090, got %d", container.Ports[1].ContainerPort)
+		}
+		if container.LivenessProbe.FailureThreshold != 5 || container.LivenessProbe.PeriodSeconds != 60 {
+			t.Errorf("Expected the failure threshold to be set over the default timings, got %+v", container.LivenessProbe)
+		}
+		if container.ReadinessProbe.PeriodSeconds != 15 {
+			t.Errorf("Expected a readiness period of 15s, got %d", container.ReadinessProbe.PeriodSeconds)
+		}
+		if container.StartupProbe == nil || container.StartupProbe.FailureThreshold != 60 || container.StartupProbe.PeriodSeconds != 10 ||
+			container.StartupProbe.HTTPGet.Path != "/live" {
+			t.Errorf("Expected a startup probe on /live with a failure threshold of 60, got %+v", container.StartupProbe)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package spire_server
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// hostedClusterKubeconfigVolumeName is the volume of the kubeconfig of the hosted cluster in the SPIRE server pods
+const hostedClusterKubeconfigVolumeName = "hosted-cluster-kubeconfig"
+
+// configureHostedClusterPlugins has the k8s_psat node attestor review the tokens of the agents, and the
+// k8sbundle notifier publish the trust bundle, in the hosted cluster through its kubeconfig
+func configureHostedClusterPlugins(configMap map[string]interface{}, clusterName string) {
+	plugins := configMap["plugins"].(map[string]interface{})
+
+	psat := plugins["NodeAttestor"].([]map[string]interface{})[0]["k8s_psat"].(map[string]interface{})
+	clusters := psat["plugin_data"].(map[string]interface{})["clusters"].([]map[string]interface{})
+	clusters[0][clusterName].(map[string]interface{})["kube_config_file"] = utils.HostedClusterKubeconfigPath()
+
+	notifier := plugins["Notifier"].([]map[string]interface{})[0]["k8sbundle"].(map[string]interface{})
+	notifier["plugin_data"].(map[string]interface{})["kube_config_file_path"] = utils.HostedClusterKubeconfigPath()
+}
+
+// addHostedClusterKubeconfigToStatefulSet mounts the kubeconfig of the hosted cluster into the SPIRE server
+// and the SPIRE controller manager. The controller manager reconciles the ClusterSPIFFEIDs and the workloads
+// of the hosted cluster, whose API server can't reach its webhook, so the webhook is disabled.
+func addHostedClusterKubeconfigToStatefulSet(sts *appsv1.StatefulSet, topology *v1alpha1.TopologyConfig) {
+	podSpec := &sts.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: hostedClusterKubeconfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: topology.HostedClusterKubeconfigSecretName,
+				Items:      []corev1.KeyToPath{{Key: utils.HostedClusterKubeconfigKey, Path: utils.HostedClusterKubeconfigKey}},
+			},
+		},
+	})
+
+	mount := corev1.VolumeMount{Name: hostedClusterKubeconfigVolumeName, MountPath: utils.HostedClusterKubeconfigMountPath, ReadOnly: true}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		switch container.Name {
+		case spireServerContainerName:
+			container.VolumeMounts = append(container.VolumeMounts, mount)
+		case "spire-controller-manager":
+			container.VolumeMounts = append(container.VolumeMounts, mount)
+			for j := range container.Env {
+				if container.Env[j].Name == "ENABLE_WEBHOOKS" {
+					container.Env[j].Value = "false"
+				}
+			}
+			container.Env = append(container.Env, corev1.EnvVar{Name: "KUBECONFIG", Value: utils.HostedClusterKubeconfigPath()})
+		}
+	}
+}
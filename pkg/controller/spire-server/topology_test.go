@@ -0,0 +1,84 @@
+package spire_server
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func newManagementClusterZTWIM() *v1alpha1.ZeroTrustWorkloadIdentityManager {
+	return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:     "example.org",
+			ClusterName:     "hosted-cluster",
+			BundleConfigMap: "spire-bundle",
+			Topology: &v1alpha1.TopologyConfig{
+				Mode:                              v1alpha1.TopologyManagementCluster,
+				HostedClusterKubeconfigSecretName: "service-network-admin-kubeconfig",
+			},
+		},
+	}
+}
+
+func TestGenerateServerConfMapWithManagementClusterTopology(t *testing.T) {
+	ztwim := newManagementClusterZTWIM()
+	plugins := generateServerConfMap(createValidConfig(), ztwim)["plugins"].(map[string]interface{})
+
+	psat := plugins["NodeAttestor"].([]map[string]interface{})[0]["k8s_psat"].(map[string]interface{})
+	cluster := psat["plugin_data"].(map[string]interface{})["clusters"].([]map[string]interface{})[0]["hosted-cluster"].(map[string]interface{})
+	if cluster["kube_config_file"] != "/run/spire/hosted-cluster/kubeconfig" {
+		t.Errorf("Expected the k8s_psat attestor to use the hosted cluster kubeconfig, got %v", cluster["kube_config_file"])
+	}
+
+	notifier := plugins["Notifier"].([]map[string]interface{})[0]["k8sbundle"].(map[string]interface{})
+	if path := notifier["plugin_data"].(map[string]interface{})["kube_config_file_path"]; path != "/run/spire/hosted-cluster/kubeconfig" {
+		t.Errorf("Expected the bundle to be published in the hosted cluster, got %v", path)
+	}
+
+	ztwim.Spec.Topology = nil
+	plugins = generateServerConfMap(createValidConfig(), ztwim)["plugins"].(map[string]interface{})
+	notifier = plugins["Notifier"].([]map[string]interface{})[0]["k8sbundle"].(map[string]interface{})
+	if _, ok := notifier["plugin_data"].(map[string]interface{})["kube_config_file_path"]; ok {
+		t.Error("Expected the bundle to be published in the local cluster without topology")
+	}
+}
+
+func TestAddHostedClusterKubeconfigToStatefulSet(t *testing.T) {
+	ztwim := newManagementClusterZTWIM()
+	sts := GenerateSpireServerStatefulSet(&newTornjakTestServer().Spec, "", "")
+	addHostedClusterKubeconfigToStatefulSet(sts, ztwim.Spec.Topology)
+
+	var volume *corev1.Volume
+	for i := range sts.Spec.Template.Spec.Volumes {
+		if sts.Spec.Template.Spec.Volumes[i].Name == hostedClusterKubeconfigVolumeName {
+			volume = &sts.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if volume == nil || volume.Secret == nil || volume.Secret.SecretName != "service-network-admin-kubeconfig" {
+		t.Fatalf("Expected the kubeconfig Secret volume, got %v", volume)
+	}
+
+	for _, name := range []string{"spire-server", "spire-controller-manager"} {
+		container := findContainer(sts.Spec.Template.Spec.Containers, name)
+		mounted := false
+		for _, mount := range container.VolumeMounts {
+			mounted = mounted || (mount.Name == hostedClusterKubeconfigVolumeName && mount.ReadOnly)
+		}
+		if !mounted {
+			t.Errorf("Expected the kubeconfig to be mounted read-only in the %s container", name)
+		}
+	}
+
+	env := map[string]string{}
+	for _, e := range findContainer(sts.Spec.Template.Spec.Containers, "spire-controller-manager").Env {
+		env[e.Name] = e.Value
+	}
+	if env["KUBECONFIG"] != "/run/spire/hosted-cluster/kubeconfig" {
+		t.Errorf("Expected the controller manager to use the hosted cluster kubeconfig, got %q", env["KUBECONFIG"])
+	}
+	if env["ENABLE_WEBHOOKS"] != "false" {
+		t.Errorf("Expected the controller manager webhook to be disabled, got %q", env["ENABLE_WEBHOOKS"])
+	}
+}
@@ -0,0 +1,437 @@
+package spire_server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// tornjakName is the name of the Tornjak frontend Deployment, Service, Route and ServiceAccount,
+	// and of the ConfigMap of the Tornjak backend
+	tornjakName = "spire-tornjak"
+	// tornjakBackendServiceName is the name of the Service of the Tornjak backend running in the SPIRE server pods
+	tornjakBackendServiceName = "spire-tornjak-backend"
+	// tornjakProxySecretName is the name of the Secret holding the session secret of the OAuth proxy
+	tornjakProxySecretName = "spire-tornjak-proxy"
+	// tornjakTLSSecretName is the name of the serving certificate Secret of the OAuth proxy, issued by the service CA
+	tornjakTLSSecretName = "spire-tornjak-tls"
+
+	tornjakBackendPort  = 10000
+	tornjakFrontendPort = 3000
+	tornjakProxyPort    = 8443
+
+	// tornjakConfigMountPath is where the Tornjak backend config is mounted into the tornjak-backend container
+	tornjakConfigMountPath = "/run/spire/tornjak-config"
+	// tornjakDataMountPath is where the Tornjak backend keeps its database
+	tornjakDataMountPath = "/run/spire/tornjak"
+)
+
+// tornjakBackendConfig is the config of the Tornjak backend. The backend talks to the SPIRE
+// server over the admin socket shared by the containers of the SPIRE server pod.
+var tornjakBackendConfig = `server {
+  spire_socket_path = "unix:///tmp/spire-server/private/api.sock"
+
+  http {
+    enabled = true
+    port = ` + strconv.Itoa(tornjakBackendPort) + `
+  }
+}
+
+plugins {
+  DataStore "sql" {
+    plugin_data {
+      drivername = "sqlite3"
+      filename = "` + tornjakDataMountPath + `/tornjak.sqlite3"
+    }
+  }
+}
+`
+
+// tornjakContainerSecurityContext returns the restricted security context of the Tornjak containers
+func tornjakContainerSecurityContext(readOnlyRootFilesystem bool) *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		ReadOnlyRootFilesystem:   ptr.To(readOnlyRootFilesystem),
+		RunAsNonRoot:             ptr.To(true),
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+}
+
+// addTornjakBackendToStatefulSet adds the Tornjak backend sidecar to the SPIRE server pods
+func addTornjakBackendToStatefulSet(sts *appsv1.StatefulSet, config *v1alpha1.TornjakConfig) {
+	podSpec := &sts.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: "tornjak-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: tornjakName}},
+			},
+		},
+		corev1.Volume{Name: "tornjak-data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	)
+
+	probe := func(initialDelaySeconds int32) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler:        corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("tornjak-http")}},
+			InitialDelaySeconds: initialDelaySeconds,
+			PeriodSeconds:       10,
+		}
+	}
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:            "tornjak-backend",
+		Image:           utils.GetTornjakBackendImage(),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args: []string{
+			"--spire-config", "/run/spire/config/server.conf",
+			"--tornjak-config", tornjakConfigMountPath + "/server.conf",
+			"--expandEnv",
+			"http",
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "tornjak-http", ContainerPort: tornjakBackendPort, Protocol: corev1.ProtocolTCP},
+		},
+		LivenessProbe:  probe(15),
+		ReadinessProbe: probe(5),
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "spire-server-socket", MountPath: "/tmp/spire-server/private", ReadOnly: true},
+			{Name: "spire-config", MountPath: "/run/spire/config", ReadOnly: true},
+			{Name: "tornjak-config", MountPath: tornjakConfigMountPath, ReadOnly: true},
+			{Name: "tornjak-data", MountPath: tornjakDataMountPath},
+		},
+		Resources:       utils.DerefResourceRequirements(config.Resources),
+		SecurityContext: tornjakContainerSecurityContext(true),
+	})
+}
+
+// addTornjakToNetworkPolicy allows the Tornjak frontend pods to reach the Tornjak backend of the SPIRE server pods
+func addTornjakToNetworkPolicy(policy *networkingv1.NetworkPolicy) {
+	policy.Spec.Ingress = append(policy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+		Ports: utils.NetworkPolicyPorts([]corev1.ServicePort{
+			{Port: tornjakBackendPort, Protocol: corev1.ProtocolTCP},
+		}),
+		From: []networkingv1.NetworkPolicyPeer{
+			{PodSelector: &metav1.LabelSelector{MatchLabels: utils.PodSelectorLabels(utils.TornjakLabels(nil))}},
+		},
+	})
+}
+
+// generateTornjakConfigMap returns the ConfigMap holding the config of the Tornjak backend
+func generateTornjakConfigMap(server *v1alpha1.SpireServer) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tornjakName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.TornjakLabels(server.Spec.Labels),
+		},
+		Data: map[string]string{"server.conf": tornjakBackendConfig},
+	}
+}
+
+// generateTornjakBackendService returns the Service of the Tornjak backend running in the SPIRE server pods
+func generateTornjakBackendService(server *v1alpha1.SpireServer) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tornjakBackendServiceName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.TornjakLabels(server.Spec.Labels),
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: utils.PodSelectorLabels(utils.SpireServerLabels(server.Spec.Labels)),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "tornjak-http",
+					Port:       tornjakBackendPort,
+					TargetPort: intstr.FromString("tornjak-http"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// generateTornjakServiceAccount returns the ServiceAccount of the Tornjak frontend. The OAuth proxy
+// uses it as an OAuth client, redirecting the users back to the Tornjak Route once logged in.
+func generateTornjakServiceAccount(server *v1alpha1.SpireServer) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tornjakName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.TornjakLabels(server.Spec.Labels),
+			Annotations: map[string]string{
+				"serviceaccounts.openshift.io/oauth-redirectreference.primary": `{"kind":"OAuthRedirectReference","apiVersion":"v1","reference":{"kind":"Route","name":"` + tornjakName + `"}}`,
+			},
+		},
+	}
+}
+
+// generateTornjakProxySecret returns the Secret holding a random session secret of the OAuth proxy
+func generateTornjakProxySecret(server *v1alpha1.SpireServer) (*corev1.Secret, error) {
+	sessionSecret := make([]byte, 32)
+	if _, err := rand.Read(sessionSecret); err != nil {
+		return nil, fmt.Errorf("failed to generate the OAuth proxy session secret: %w", err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tornjakProxySecretName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.TornjakLabels(server.Spec.Labels),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"session_secret": []byte(base64.StdEncoding.EncodeToString(sessionSecret))},
+	}, nil
+}
+
+// generateTornjakService returns the Service of the OAuth proxy in front of the Tornjak frontend
+func generateTornjakService(server *v1alpha1.SpireServer) *corev1.Service {
+	labels := utils.TornjakLabels(server.Spec.Labels)
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        tornjakName,
+			Namespace:   utils.GetOperandNamespace(),
+			Labels:      labels,
+			Annotations: map[string]string{utils.ServiceCAAnnotationKey: tornjakTLSSecretName},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: utils.PodSelectorLabels(labels),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "https",
+					Port:       443,
+					TargetPort: intstr.FromString("https"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// generateTornjakDeployment returns the Deployment of the Tornjak frontend. The OAuth proxy serves
+// the frontend and the API of the backend from the same origin, and only lets in the users allowed
+// to update the SpireServer.
+func generateTornjakDeployment(server *v1alpha1.SpireServer, config *v1alpha1.TornjakConfig) *appsv1.Deployment {
+	labels := utils.TornjakLabels(server.Spec.Labels)
+	backendURL := fmt.Sprintf("http://%s.%s.svc:%d/api/", tornjakBackendServiceName, utils.GetOperandNamespace(), tornjakBackendPort)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tornjakName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: utils.PodSelectorLabels(labels)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: tornjakName,
+					ImagePullSecrets:   server.Spec.ImagePullSecrets,
+					NodeSelector:       utils.DerefNodeSelector(server.Spec.NodeSelector),
+					Tolerations:        utils.DerefTolerations(server.Spec.Tolerations),
+					Containers: []corev1.Container{
+						{
+							Name:            "tornjak-frontend",
+							Image:           utils.GetTornjakFrontendImage(),
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Env: []corev1.EnvVar{
+								{Name: "REACT_APP_API_SERVER_URI", Value: "/"},
+								{Name: "PORT_FE", Value: strconv.Itoa(tornjakFrontendPort)},
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "http", ContainerPort: tornjakFrontendPort, Protocol: corev1.ProtocolTCP},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler:        corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("http")}},
+								InitialDelaySeconds: 5,
+								PeriodSeconds:       10,
+							},
+							Resources: utils.DerefResourceRequirements(config.Resources),
+							// The frontend writes its runtime config at startup
+							SecurityContext: tornjakContainerSecurityContext(false),
+						},
+						{
+							Name:            "oauth-proxy",
+							Image:           utils.GetOAuthProxyImage(),
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Args: []string{
+								fmt.Sprintf("--https-address=:%d", tornjakProxyPort),
+								"--provider=openshift",
+								"--openshift-service-account=" + tornjakName,
+								fmt.Sprintf("--upstream=http://localhost:%d/", tornjakFrontendPort),
+								"--upstream=" + backendURL,
+								"--tls-cert=/etc/tls/private/tls.crt",
+								"--tls-key=/etc/tls/private/tls.key",
+								"--cookie-secret-file=/etc/proxy/secrets/session_secret",
+								`--openshift-sar={"group":"operator.openshift.io","resource":"spireservers","resourceName":"` + server.Name + `","verb":"update"}`,
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "https", ContainerPort: tornjakProxyPort, Protocol: corev1.ProtocolTCP},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+									Path:   "/oauth/healthz",
+									Port:   intstr.FromString("https"),
+									Scheme: corev1.URISchemeHTTPS,
+								}},
+								InitialDelaySeconds: 5,
+								PeriodSeconds:       10,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "proxy-tls", MountPath: "/etc/tls/private", ReadOnly: true},
+								{Name: "proxy-session", MountPath: "/etc/proxy/secrets", ReadOnly: true},
+							},
+							SecurityContext: tornjakContainerSecurityContext(true),
+						},
+					},
+					Volumes: []corev1.Volume{
+						{Name: "proxy-tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: tornjakTLSSecretName}}},
+						{Name: "proxy-session", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: tornjakProxySecretName}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// generateTornjakRoute returns the Route exposing the OAuth proxy of the Tornjak frontend
+func generateTornjakRoute(server *v1alpha1.SpireServer, config *v1alpha1.TornjakConfig) *routev1.Route {
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tornjakName,
+			Namespace: utils.GetOperandNamespace(),
+			Labels:    utils.TornjakLabels(server.Spec.Labels),
+		},
+		Spec: routev1.RouteSpec{
+			Host: config.RouteHost,
+			To: routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   tornjakName,
+				Weight: ptr.To(int32(100)),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromString("https"),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationReencrypt,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			},
+			WildcardPolicy: routev1.WildcardPolicyNone,
+		},
+	}
+}
+
+// reconcileTornjak reconciles the Tornjak UI when enabled. The Tornjak backend runs in the SPIRE server
+// pods, see reconcileStatefulSet. When disabled, the Tornjak resources are no longer tracked and are
+// pruned with the other orphaned resources.
+func (r *SpireServerReconciler) reconcileTornjak(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, createOnlyMode bool) error {
+	if !utils.IsTornjakEnabled(ztwim.Spec.Tornjak) {
+		// Tornjak disabled - don't set status
+		return nil
+	}
+	config := ztwim.Spec.Tornjak
+
+	configMap := generateTornjakConfigMap(server)
+	existingConfigMap := &corev1.ConfigMap{}
+	if err := r.reconcileOwnedResource(ctx, server, statusMgr, TornjakAvailable, configMap, existingConfigMap, func() bool {
+		return !equality.Semantic.DeepEqual(existingConfigMap.Data, configMap.Data) || !equality.Semantic.DeepEqual(existingConfigMap.Labels, configMap.Labels)
+	}, createOnlyMode); err != nil {
+		return err
+	}
+
+	for _, service := range []*corev1.Service{generateTornjakBackendService(server), generateTornjakService(server)} {
+		existingService := &corev1.Service{}
+		if err := r.reconcileOwnedResource(ctx, server, statusMgr, TornjakAvailable, service, existingService, func() bool {
+			preserveServiceFields(existingService, service)
+			return utils.ResourceNeedsUpdate(existingService, service)
+		}, createOnlyMode); err != nil {
+			return err
+		}
+	}
+
+	serviceAccount := generateTornjakServiceAccount(server)
+	existingServiceAccount := &corev1.ServiceAccount{}
+	if err := r.reconcileOwnedResource(ctx, server, statusMgr, TornjakAvailable, serviceAccount, existingServiceAccount, func() bool {
+		return utils.ResourceNeedsUpdate(existingServiceAccount, serviceAccount)
+	}, createOnlyMode); err != nil {
+		return err
+	}
+
+	if err := r.reconcileTornjakProxySecret(ctx, server, statusMgr); err != nil {
+		return err
+	}
+
+	deployment := generateTornjakDeployment(server, config)
+	existingDeployment := &appsv1.Deployment{}
+	if err := r.reconcileOwnedResource(ctx, server, statusMgr, TornjakAvailable, deployment, existingDeployment, func() bool {
+		return utils.ResourceNeedsUpdate(existingDeployment, deployment)
+	}, createOnlyMode); err != nil {
+		return err
+	}
+
+	route := generateTornjakRoute(server, config)
+	existingRoute := &routev1.Route{}
+	if err := r.reconcileOwnedResource(ctx, server, statusMgr, TornjakAvailable, route, existingRoute, func() bool {
+		// Keep the host generated by the router when none is configured
+		if route.Spec.Host == "" {
+			route.Spec.Host = existingRoute.Spec.Host
+		}
+		return checkFederationRouteConflict(existingRoute, route)
+	}, createOnlyMode); err != nil {
+		return err
+	}
+
+	statusMgr.CheckDeploymentHealth(ctx, deployment.Name, deployment.Namespace, TornjakAvailable)
+	return nil
+}
+
+// reconcileTornjakProxySecret creates the session secret of the OAuth proxy. Secrets are not cached,
+// so the Secret is only created, keeping the session secret of an existing Secret.
+func (r *SpireServerReconciler) reconcileTornjakProxySecret(ctx context.Context, server *v1alpha1.SpireServer, statusMgr *status.Manager) error {
+	secret, err := generateTornjakProxySecret(server)
+	if err == nil {
+		err = controllerutil.SetControllerReference(server, secret, r.scheme)
+	}
+	if err != nil {
+		r.log.Error(err, "failed to generate the Tornjak OAuth proxy secret")
+		statusMgr.AddCondition(TornjakAvailable, "TornjakResourceGenerationFailed",
+			fmt.Sprintf("Failed to generate %s: %v", tornjakProxySecretName, err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.TrackResource(secret)
+
+	if err := r.ctrlClient.Create(ctx, secret); err != nil {
+		if kerrors.IsAlreadyExists(err) {
+			return nil
+		}
+		r.log.Error(err, "failed to create the Tornjak OAuth proxy secret")
+		statusMgr.AddCondition(TornjakAvailable, "TornjakResourceCreationFailed",
+			fmt.Sprintf("Failed to create %s: %v", tornjakProxySecretName, err),
+			metav1.ConditionFalse)
+		return err
+	}
+	r.log.Info("Created Tornjak OAuth proxy secret", "name", secret.Name, "namespace", secret.Namespace)
+	statusMgr.RecordResourceCreated(secret)
+	return nil
+}
@@ -0,0 +1,276 @@
+package spire_server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// newTornjakTestReconciler creates a reconciler for Tornjak tests
+func newTornjakTestReconciler(fakeClient *fakes.FakeCustomCtrlClient) *SpireServerReconciler {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = routev1.AddToScheme(scheme)
+	return &SpireServerReconciler{
+		ctrlClient:    fakeClient,
+		ctx:           context.Background(),
+		log:           logr.Discard(),
+		scheme:        scheme,
+		eventRecorder: record.NewFakeRecorder(100),
+	}
+}
+
+func newTornjakTestZTWIM(enabled string) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+	return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain: "example.org",
+			Tornjak:     &v1alpha1.TornjakConfig{Enabled: enabled},
+		},
+	}
+}
+
+func newTornjakTestServer() *v1alpha1.SpireServer {
+	server := createTestSpireServer()
+	server.Spec.Persistence = v1alpha1.Persistence{Size: "1Gi", AccessMode: "ReadWriteOnce"}
+	return server
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+func TestAddTornjakBackendToStatefulSet(t *testing.T) {
+	server := newTornjakTestServer()
+	sts := GenerateSpireServerStatefulSet(&server.Spec, "", "")
+	addTornjakBackendToStatefulSet(sts, &v1alpha1.TornjakConfig{Enabled: "true"})
+
+	backend := findContainer(sts.Spec.Template.Spec.Containers, "tornjak-backend")
+	if backend == nil {
+		t.Fatal("Expected the tornjak-backend container in the SPIRE server pods")
+	}
+	mounts := map[string]corev1.VolumeMount{}
+	for _, mount := range backend.VolumeMounts {
+		mounts[mount.Name] = mount
+	}
+	if socket, ok := mounts["spire-server-socket"]; !ok || !socket.ReadOnly || socket.MountPath != "/tmp/spire-server/private" {
+		t.Errorf("Expected the SPIRE server socket mounted read-only, got %v", socket)
+	}
+	if _, ok := mounts["tornjak-config"]; !ok {
+		t.Error("Expected the Tornjak config volume to be mounted")
+	}
+
+	volumes := map[string]corev1.Volume{}
+	for _, volume := range sts.Spec.Template.Spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	for name := range mounts {
+		if _, ok := volumes[name]; !ok {
+			t.Errorf("Expected volume %s mounted by the tornjak-backend container in the pod", name)
+		}
+	}
+	if config := volumes["tornjak-config"].ConfigMap; config == nil || config.Name != tornjakName {
+		t.Errorf("Expected the tornjak-config volume to use the %s ConfigMap", tornjakName)
+	}
+}
+
+func TestGenerateTornjakBackendService(t *testing.T) {
+	server := newTornjakTestServer()
+	service := generateTornjakBackendService(server)
+	sts := GenerateSpireServerStatefulSet(&server.Spec, "", "")
+	for k, v := range service.Spec.Selector {
+		if sts.Spec.Template.Labels[k] != v {
+			t.Errorf("Expected selector label %s=%s to match the SPIRE server pods", k, v)
+		}
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].Port != tornjakBackendPort {
+		t.Errorf("Expected the Tornjak backend port, got %v", service.Spec.Ports)
+	}
+}
+
+func TestGenerateTornjakDeployment(t *testing.T) {
+	server := newTornjakTestServer()
+	deployment := generateTornjakDeployment(server, &v1alpha1.TornjakConfig{Enabled: "true"})
+
+	if deployment.Spec.Template.Spec.ServiceAccountName != tornjakName {
+		t.Errorf("Expected service account %s, got %s", tornjakName, deployment.Spec.Template.Spec.ServiceAccountName)
+	}
+	for k, v := range deployment.Spec.Selector.MatchLabels {
+		if deployment.Spec.Template.Labels[k] != v {
+			t.Errorf("Expected selector label %s=%s on the pod template", k, v)
+		}
+	}
+
+	proxy := findContainer(deployment.Spec.Template.Spec.Containers, "oauth-proxy")
+	if proxy == nil {
+		t.Fatal("Expected the oauth-proxy container")
+	}
+	args := strings.Join(proxy.Args, " ")
+	for _, expected := range []string{
+		"--provider=openshift",
+		"--openshift-service-account=spire-tornjak",
+		"--upstream=http://localhost:3000/",
+		"--upstream=http://spire-tornjak-backend." + utils.GetOperandNamespace() + ".svc:10000/api/",
+		`"resource":"spireservers","resourceName":"cluster","verb":"update"`,
+	} {
+		if !strings.Contains(args, expected) {
+			t.Errorf("Expected oauth-proxy args to contain %q, got %s", expected, args)
+		}
+	}
+	if findContainer(deployment.Spec.Template.Spec.Containers, "tornjak-frontend") == nil {
+		t.Error("Expected the tornjak-frontend container")
+	}
+}
+
+func TestGenerateTornjakRoute(t *testing.T) {
+	server := newTornjakTestServer()
+	route := generateTornjakRoute(server, &v1alpha1.TornjakConfig{Enabled: "true", RouteHost: "tornjak.apps.example.com"})
+	if route.Spec.Host != "tornjak.apps.example.com" {
+		t.Errorf("Expected host tornjak.apps.example.com, got %s", route.Spec.Host)
+	}
+	if route.Spec.To.Name != tornjakName || route.Spec.Port.TargetPort.String() != "https" {
+		t.Errorf("Expected the Route to target the https port of the %s Service, got %v", tornjakName, route.Spec)
+	}
+	if route.Spec.TLS == nil || route.Spec.TLS.Termination != routev1.TLSTerminationReencrypt {
+		t.Errorf("Expected reencrypt TLS termination, got %v", route.Spec.TLS)
+	}
+}
+
+func TestAddTornjakToNetworkPolicy(t *testing.T) {
+	policy := generateSpireServerNetworkPolicy(&newTornjakTestServer().Spec)
+	rules := len(policy.Spec.Ingress)
+	addTornjakToNetworkPolicy(policy)
+	if len(policy.Spec.Ingress) != rules+1 {
+		t.Fatalf("Expected one more ingress rule, got %d", len(policy.Spec.Ingress))
+	}
+	rule := policy.Spec.Ingress[rules]
+	if len(rule.Ports) != 1 || rule.Ports[0].Port.IntValue() != tornjakBackendPort {
+		t.Errorf("Expected the Tornjak backend port, got %v", rule.Ports)
+	}
+	deployment := generateTornjakDeployment(newTornjakTestServer(), &v1alpha1.TornjakConfig{})
+	for k, v := range rule.From[0].PodSelector.MatchLabels {
+		if deployment.Spec.Template.Labels[k] != v {
+			t.Errorf("Expected peer label %s=%s to match the Tornjak pods", k, v)
+		}
+	}
+}
+
+func TestReconcileTornjak(t *testing.T) {
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, tornjakName)
+	existingResources := func(fc *fakes.FakeCustomCtrlClient, server *v1alpha1.SpireServer, config *v1alpha1.TornjakConfig, routeHost string) {
+		fc.GetStub = func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *corev1.ConfigMap:
+				*o = *generateTornjakConfigMap(server)
+			case *corev1.Service:
+				if key.Name == tornjakBackendServiceName {
+					*o = *generateTornjakBackendService(server)
+				} else {
+					*o = *generateTornjakService(server)
+				}
+				o.Spec.ClusterIP = "10.0.0.1"
+			case *corev1.ServiceAccount:
+				*o = *generateTornjakServiceAccount(server)
+			case *appsv1.Deployment:
+				*o = *generateTornjakDeployment(server, config)
+			case *routev1.Route:
+				*o = *generateTornjakRoute(server, config)
+				o.Spec.Host = routeHost
+			}
+			obj.SetResourceVersion("123")
+			return nil
+		}
+		fc.CreateReturns(kerrors.NewAlreadyExists(schema.GroupResource{}, tornjakProxySecretName))
+	}
+
+	tests := []struct {
+		name         string
+		enabled      string
+		routeHost    string
+		setupClient  func(*fakes.FakeCustomCtrlClient, *v1alpha1.SpireServer, *v1alpha1.TornjakConfig)
+		expectCreate int
+		expectUpdate int
+	}{
+		{
+			name:    "disabled",
+			enabled: "false",
+		},
+		{
+			name:    "create when not found",
+			enabled: "true",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, _ *v1alpha1.SpireServer, _ *v1alpha1.TornjakConfig) {
+				fc.GetReturns(notFound)
+			},
+			// ConfigMap, backend and proxy Services, ServiceAccount, Secret, Deployment and Route
+			expectCreate: 7,
+		},
+		{
+			name:    "up to date keeps the host generated by the router",
+			enabled: "true",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, server *v1alpha1.SpireServer, config *v1alpha1.TornjakConfig) {
+				existingResources(fc, server, config, "spire-tornjak.apps.example.com")
+			},
+			expectCreate: 1,
+		},
+		{
+			name:      "update the Route when the host differs",
+			enabled:   "true",
+			routeHost: "tornjak.apps.example.com",
+			setupClient: func(fc *fakes.FakeCustomCtrlClient, server *v1alpha1.SpireServer, config *v1alpha1.TornjakConfig) {
+				existingResources(fc, server, config, "spire-tornjak.apps.example.com")
+			},
+			expectCreate: 1,
+			expectUpdate: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			reconciler := newTornjakTestReconciler(fakeClient)
+			server := newTornjakTestServer()
+			server.UID = "test-uid"
+			ztwim := newTornjakTestZTWIM(tt.enabled)
+			ztwim.Spec.Tornjak.RouteHost = tt.routeHost
+			if tt.setupClient != nil {
+				tt.setupClient(fakeClient, server, ztwim.Spec.Tornjak)
+			}
+
+			if err := reconciler.reconcileTornjak(context.Background(), server, status.NewManager(fakeClient), ztwim, false); err != nil {
+				t.Fatalf("reconcileTornjak() error = %v", err)
+			}
+			if fakeClient.CreateCallCount() != tt.expectCreate {
+				t.Errorf("Expected %d Create calls, got %d", tt.expectCreate, fakeClient.CreateCallCount())
+			}
+			if fakeClient.UpdateCallCount() != tt.expectUpdate {
+				t.Errorf("Expected %d Update calls, got %d", tt.expectUpdate, fakeClient.UpdateCallCount())
+			}
+			if tt.enabled == "false" && fakeClient.GetCallCount() != 0 {
+				t.Errorf("Expected no Get call when disabled, got %d", fakeClient.GetCallCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package spire_server
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// upgradeAllowed reports whether the existing SPIRE server StatefulSet can be rolled to the version of
+// the desired one without breaking the datastore schema, and records the outcome in the UpgradeAllowed
+// condition. The existing StatefulSet is left untouched while the version change is unsafe.
+func (r *SpireServerReconciler) upgradeAllowed(server *v1alpha1.SpireServer, existing, desired *appsv1.StatefulSet, statusMgr *status.Manager) bool {
+	current, target := existing.Labels[utils.VersionLabelKey], desired.Labels[utils.VersionLabelKey]
+	if err := utils.CheckSpireServerUpgrade(current, target, utils.StringToBool(server.Spec.Datastore.DisableMigration)); err != nil {
+		r.log.Error(err, "unsafe SPIRE server version change", "current", current, "desired", target)
+		statusMgr.AddCondition(utils.UpgradeAllowedStatusType, utils.UnsafeVersionSkew,
+			err.Error(),
+			metav1.ConditionFalse)
+		return false
+	}
+
+	// Only set to true if the condition previously existed as false
+	existingCondition := apimeta.FindStatusCondition(server.Status.ConditionalStatus.Conditions, utils.UpgradeAllowedStatusType)
+	if existingCondition != nil && existingCondition.Status == metav1.ConditionFalse {
+		statusMgr.AddCondition(utils.UpgradeAllowedStatusType, utils.UpgradeAllowed,
+			"SPIRE server version change passed the upgrade checks",
+			metav1.ConditionTrue)
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package spire_server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newVersionedStatefulSet(version string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "spire-server",
+			Labels: map[string]string{utils.VersionLabelKey: version},
+		},
+	}
+}
+
+func TestUpgradeAllowed(t *testing.T) {
+	tests := []struct {
+		name              string
+		current           string
+		desired           string
+		migrationDisabled string
+		existingCondition *metav1.Condition
+		expectAllowed     bool
+		expectCondition   *metav1.Condition
+	}{
+		{
+			name:          "one minor version upgrade",
+			current:       "1.12.4",
+			desired:       "1.13.3",
+			expectAllowed: true,
+		},
+		{
+			name:            "skipped minor version",
+			current:         "1.11.2",
+			desired:         "1.13.3",
+			expectCondition: &metav1.Condition{Status: metav1.ConditionFalse, Reason: utils.UnsafeVersionSkew},
+		},
+		{
+			name:              "migration disabled",
+			current:           "1.12.4",
+			desired:           "1.13.3",
+			migrationDisabled: "true",
+			expectCondition:   &metav1.Condition{Status: metav1.ConditionFalse, Reason: utils.UnsafeVersionSkew},
+		},
+		{
+			name:              "previously blocked upgrade becomes safe",
+			current:           "1.13.3",
+			desired:           "1.13.3",
+			existingCondition: &metav1.Condition{Type: utils.UpgradeAllowedStatusType, Status: metav1.ConditionFalse, Reason: utils.UnsafeVersionSkew},
+			expectAllowed:     true,
+			expectCondition:   &metav1.Condition{Status: metav1.ConditionTrue, Reason: utils.UpgradeAllowed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			reconciler := newStatefulSetTestReconciler(fakeClient)
+			server := createTestSpireServer()
+			server.Spec.Datastore.DisableMigration = tt.migrationDisabled
+			if tt.existingCondition != nil {
+				server.Status.Conditions = []metav1.Condition{*tt.existingCondition}
+			}
+
+			statusMgr := status.NewManager(fakeClient)
+			allowed := reconciler.upgradeAllowed(server, newVersionedStatefulSet(tt.current), newVersionedStatefulSet(tt.desired), statusMgr)
+			if allowed != tt.expectAllowed {
+				t.Errorf("Expected allowed=%v, got %v", tt.expectAllowed, allowed)
+			}
+
+			if err := statusMgr.ApplyStatus(context.Background(), server, func() *v1alpha1.ConditionalStatus {
+				return &server.Status.ConditionalStatus
+			}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			cond := apimeta.FindStatusCondition(server.Status.Conditions, utils.UpgradeAllowedStatusType)
+			if tt.expectCondition == nil {
+				if cond != nil {
+					t.Errorf("Expected no UpgradeAllowed condition, got %+v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Status != tt.expectCondition.Status || cond.Reason != tt.expectCondition.Reason {
+				t.Errorf("Expected UpgradeAllowed %s with reason %s, got %+v", tt.expectCondition.Status, tt.expectCondition.Reason, cond)
+			}
+		})
+	}
+}
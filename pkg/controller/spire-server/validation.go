@@ -3,8 +3,11 @@ package spire_server
 import (
 	"fmt"
 	"strings"
+	"text/template"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 )
@@ -85,20 +88,29 @@ func validateTTLDurationsWithWarnings(config *v1alpha1.SpireServerSpec) TTLValid
 		result.Error = fmt.Errorf("default_jwt_svid_ttl must be a positive duration")
 		return result
 	}
+	if config.AgentValidity.Duration < 0 {
+		result.Error = fmt.Errorf("agent_ttl must be a positive duration")
+		return result
+	}
 
 	if config.CAValidity.Duration < config.DefaultJWTValidity.Duration {
 		result.Error = fmt.Errorf("ca_validity must be greater than default_jwt_svid_ttl")
 		return result
 	}
 	if config.CAValidity.Duration < config.DefaultX509Validity.Duration {
-		result.Error = fmt.Errorf("ca_validity must be greater than default_ca_ttl")
+		result.Error = fmt.Errorf("ca_validity must be greater than default_x509_svid_ttl")
+		return result
+	}
+	if config.CAValidity.Duration < config.AgentValidity.Duration {
+		result.Error = fmt.Errorf("ca_validity must be greater than agent_ttl")
 		return result
 	}
 
-	ttlChecks := []struct {
+	type namedTTL struct {
 		name string
 		ttl  time.Duration
-	}{
+	}
+	ttlChecks := []namedTTL{
 		{
 			name: "default_x509_svid_ttl",
 			ttl:  config.DefaultX509Validity.Duration,
@@ -108,6 +120,9 @@ func validateTTLDurationsWithWarnings(config *v1alpha1.SpireServerSpec) TTLValid
 			ttl:  config.DefaultJWTValidity.Duration,
 		},
 	}
+	if config.AgentValidity.Duration > 0 {
+		ttlChecks = append(ttlChecks, namedTTL{name: "agent_ttl", ttl: config.AgentValidity.Duration})
+	}
 
 	for _, ttlCheck := range ttlChecks {
 		if !hasCompatibleTTL(config.CAValidity.Duration, ttlCheck.ttl) {
@@ -300,3 +315,198 @@ func validateFederatedTrustDomain(fedTrust *v1alpha1.FederatesWithConfig, index
 
 	return nil
 }
+
+// validateFIPSCompliance validates that the CA and JWT key types use FIPS approved algorithms
+func validateFIPSCompliance(config *v1alpha1.SpireServerSpec) error {
+	if err := utils.ValidateFIPSKeyType("caKeyType", config.CAKeyType); err != nil {
+		return err
+	}
+	if err := utils.ValidateFIPSKeyType("jwtKeyType", config.JWTKeyType); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateServerExtraConfigGlobals rejects an extraConfig overriding the trust domain of the SPIRE server
+func validateServerExtraConfigGlobals(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	extraConfig, err := utils.DecodeExtraConfig(config.ExtraConfig)
+	if err != nil {
+		return err
+	}
+	return utils.ValidateExtraConfigGlobals(extraConfig, map[string]string{
+		"server.trust_domain": ztwim.Spec.TrustDomain,
+	})
+}
+
+// supportedDatabaseTypes lists the database types understood by the SPIRE sql datastore plugin
+var supportedDatabaseTypes = []string{"sqlite3", "postgres", "mysql", "aws_postgresql", "aws_mysql"}
+
+// validateDatastore validates that the datastore configuration refers to a database type SPIRE can load
+func validateDatastore(datastore v1alpha1.DataStore) error {
+	if datastore.DatabaseType == "" {
+		return nil
+	}
+	for _, supported := range supportedDatabaseTypes {
+		if datastore.DatabaseType == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("databaseType %q is not supported by the SPIRE sql datastore plugin, must be one of %v", datastore.DatabaseType, supportedDatabaseTypes)
+}
+
+// validatePersistence validates that the datastore and the key manager don't keep their data on
+// the ephemeral data volume of the emptyDir persistence
+func validatePersistence(config *v1alpha1.SpireServerSpec) error {
+	if config.Persistence.Type != utils.PersistenceTypeEmptyDir {
+		return nil
+	}
+	if config.Datastore.DatabaseType == "sqlite3" {
+		return fmt.Errorf("persistence.type %q requires an external datastore, the sqlite3 database would be lost when the pod restarts", utils.PersistenceTypeEmptyDir)
+	}
+	if !usesMemoryKeyManager(config.KeyManager) {
+		return fmt.Errorf("persistence.type %q requires the memory key manager, set keyManager.diskEnabled to \"false\" and keyManager.memoryEnabled to \"true\"", utils.PersistenceTypeEmptyDir)
+	}
+	return nil
+}
+
+// serverPodPorts are the ports of the other listeners of the SPIRE server pods, which the health checks must not use
+var serverPodPorts = map[int32]string{
+	8081:               "the SPIRE server API",
+	8082:               "the controller manager metrics",
+	8083:               "the controller manager health checks",
+	8443:               "the federation bundle endpoint",
+	9402:               "the SPIRE server metrics",
+	9443:               "the controller manager webhook",
+	tornjakBackendPort: "the Tornjak backend",
+}
+
+// validateHealthPort validates that the health checks of the SPIRE server don't use the port of another listener
+func validateHealthPort(config *v1alpha1.SpireServerSpec) error {
+	port := getServerHealthPort(config)
+	if listener, ok := serverPodPorts[port]; ok {
+		return fmt.Errorf("healthPort %d is used by %s", port, listener)
+	}
+	return nil
+}
+
+// validateFeatureGates validates that the spec only sets the capabilities whose feature gate is enabled
+func validateFeatureGates(config *v1alpha1.SpireServerSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	if config.Federation != nil && !utils.FeatureGateEnabled(ztwim, v1alpha1.FeatureGateFederation) {
+		return fmt.Errorf("federation requires the %s feature gate", v1alpha1.FeatureGateFederation)
+	}
+	if config.UpstreamAuthority != nil && !utils.FeatureGateEnabled(ztwim, v1alpha1.FeatureGateNestedTopology) {
+		return fmt.Errorf("upstreamAuthority requires the %s feature gate", v1alpha1.FeatureGateNestedTopology)
+	}
+	return nil
+}
+
+// validateControllerManager validates the SPIFFE ID and DNS name templates and the label selectors of the default ClusterSPIFFEID
+func validateControllerManager(controllerManager *v1alpha1.ControllerManagerConfig) error {
+	if controllerManager == nil {
+		return nil
+	}
+	if controllerManager.DefaultSPIFFEIDTemplate != "" {
+		if !strings.HasPrefix(controllerManager.DefaultSPIFFEIDTemplate, "spiffe://") {
+			return fmt.Errorf("controllerManager.defaultSPIFFEIDTemplate must start with spiffe://")
+		}
+		if _, err := template.New("spiffeID").Parse(controllerManager.DefaultSPIFFEIDTemplate); err != nil {
+			return fmt.Errorf("controllerManager.defaultSPIFFEIDTemplate: %w", err)
+		}
+	}
+	for i, dnsNameTemplate := range controllerManager.DNSNameTemplates {
+		if strings.TrimSpace(dnsNameTemplate) == "" {
+			return fmt.Errorf("controllerManager.dnsNameTemplates[%d] is empty", i)
+		}
+		if _, err := template.New("dnsName").Parse(dnsNameTemplate); err != nil {
+			return fmt.Errorf("controllerManager.dnsNameTemplates[%d]: %w", i, err)
+		}
+	}
+	if _, err := metav1.LabelSelectorAsSelector(controllerManager.NamespaceSelector); err != nil {
+		return fmt.Errorf("controllerManager.namespaceSelector: %w", err)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(controllerManager.PodSelector); err != nil {
+		return fmt.Errorf("controllerManager.podSelector: %w", err)
+	}
+	return nil
+}
+
+// ValidateSpec runs the SpireServer spec validations the reconciler performs, so that the
+// admission webhook can reject invalid specs up front. It returns the TTL warnings alongside
+// the first validation error found.
+func ValidateSpec(config *v1alpha1.SpireServerSpec, trustDomain string) ([]string, error) {
+	if err := utils.IsValidURL(config.JwtIssuer); err != nil {
+		return nil, fmt.Errorf("jwtIssuer: %w", err)
+	}
+
+	ttlResult := validateTTLDurationsWithWarnings(config)
+	if ttlResult.Error != nil {
+		return nil, ttlResult.Error
+	}
+
+	if err := validateDatastore(config.Datastore); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validatePersistence(config); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateDatastoreBackup(config); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateHealthPort(config); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateControllerManager(config.ControllerManager); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if utils.IsFIPSModeEnabled() {
+		if err := validateFIPSCompliance(config); err != nil {
+			return ttlResult.Warnings, err
+		}
+	}
+
+	if _, err := utils.DecodeExtraConfig(config.ExtraConfig); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := utils.ValidateExperimentalConfig(config.Experimental); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateBundleDistribution(config.BundleDistribution); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateBundlePublishers(config.BundlePublishers); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateCredentialComposers(config); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := validateServerExposure(config.Exposure); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if err := utils.SpireServerOperand.ValidateVersion(config.Version); err != nil {
+		return ttlResult.Warnings, err
+	}
+
+	if config.Federation != nil {
+		for i, fedTrust := range config.Federation.FederatesWith {
+			if err := utils.IsValidTrustDomain(fedTrust.TrustDomain); err != nil {
+				return ttlResult.Warnings, fmt.Errorf("federatesWith[%d]: %w", i, err)
+			}
+		}
+		if err := validateFederationConfig(config.Federation, trustDomain); err != nil {
+			return ttlResult.Warnings, err
+		}
+	}
+
+	return ttlResult.Warnings, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -181,6 +182,32 @@ func TestValidateTTLDurationsWithWarnings(t *testing.T) {
 			},
 			statusMessage: "TTL configuration warnings: 2 issues found",
 		},
+		{
+			name: "incompatible agent SVID TTL - generates warning",
+			config: &v1alpha1.SpireServerSpec{
+				CAValidity:          metav1.Duration{Duration: 24 * time.Hour}, // 24h / 6 = 4h max SVID TTL
+				DefaultX509Validity: metav1.Duration{Duration: 1 * time.Hour},
+				DefaultJWTValidity:  metav1.Duration{Duration: 5 * time.Minute},
+				AgentValidity:       metav1.Duration{Duration: 12 * time.Hour}, // 12h > 4h (incompatible)
+			},
+			expectError:    false,
+			expectWarnings: 1,
+			warningContains: []string{
+				"agent_ttl is too high for the configured ca_ttl value",
+			},
+			statusMessage: "TTL configuration warnings: 1 issues found",
+		},
+		{
+			name: "error - agent SVID TTL greater than CA TTL",
+			config: &v1alpha1.SpireServerSpec{
+				CAValidity:          metav1.Duration{Duration: 24 * time.Hour},
+				DefaultX509Validity: metav1.Duration{Duration: 1 * time.Hour},
+				DefaultJWTValidity:  metav1.Duration{Duration: 5 * time.Minute},
+				AgentValidity:       metav1.Duration{Duration: 48 * time.Hour},
+			},
+			expectError:    true,
+			expectWarnings: 0,
+		},
 		{
 			name: "error - zero CA TTL",
 			config: &v1alpha1.SpireServerSpec{
@@ -785,3 +812,388 @@ func TestValidateFederatedTrustDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFIPSCompliance(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        *v1alpha1.SpireServerSpec
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:        "Empty key types fall back to defaults",
+			spec:        &v1alpha1.SpireServerSpec{},
+			expectError: false,
+		},
+		{
+			name: "Approved CA and JWT key types",
+			spec: &v1alpha1.SpireServerSpec{
+				CAKeyType:  "ec-p384",
+				JWTKeyType: "rsa-4096",
+			},
+			expectError: false,
+		},
+		{
+			name: "Unapproved CA key type",
+			spec: &v1alpha1.SpireServerSpec{
+				CAKeyType: "ed25519",
+			},
+			expectError: true,
+			errorMsg:    "caKeyType \"ed25519\" is not a FIPS approved key type",
+		},
+		{
+			name: "Unapproved JWT key type",
+			spec: &v1alpha1.SpireServerSpec{
+				CAKeyType:  "ec-p256",
+				JWTKeyType: "rsa-1024",
+			},
+			expectError: true,
+			errorMsg:    "jwtKeyType \"rsa-1024\" is not a FIPS approved key type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFIPSCompliance(tt.spec)
+
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateFIPSCompliance() error = %v, expectError = %v", err, tt.expectError)
+				return
+			}
+
+			if tt.expectError && err != nil && !containsString(err.Error(), tt.errorMsg) {
+				t.Errorf("validateFIPSCompliance() error = %q, expected to contain %q", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestValidateDatastore(t *testing.T) {
+	tests := []struct {
+		name         string
+		databaseType string
+		expectError  bool
+	}{
+		{name: "Empty database type uses the default", databaseType: ""},
+		{name: "sqlite3", databaseType: "sqlite3"},
+		{name: "postgres", databaseType: "postgres"},
+		{name: "Plugin name instead of database type", databaseType: "sql", expectError: true},
+		{name: "Unknown database type", databaseType: "mongodb", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDatastore(v1alpha1.DataStore{DatabaseType: tt.databaseType})
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateDatastore() error = %v, expectError = %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateServerExtraConfigGlobals(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	tests := []struct {
+		name        string
+		extraConfig string
+		expectError bool
+	}{
+		{name: "Unset extra config"},
+		{name: "Other server settings", extraConfig: `{"server":{"ca_ttl":"48h"}}`},
+		{name: "Same trust domain", extraConfig: `{"server":{"trust_domain":"example.org"}}`},
+		{name: "Conflicting trust domain", extraConfig: `{"server":{"trust_domain":"other.org"}}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &v1alpha1.SpireServerSpec{}
+			if tt.extraConfig != "" {
+				config.ExtraConfig = &apiextensionsv1.JSON{Raw: []byte(tt.extraConfig)}
+			}
+			err := validateServerExtraConfigGlobals(config, ztwim)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateServerExtraConfigGlobals() error = %v, expectError = %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateRenderedServerConfig(t *testing.T) {
+	ztwim := createTestZTWIM()
+
+	tests := []struct {
+		name        string
+		extraConfig string
+		expectError bool
+	}{
+		{name: "Unset extra config"},
+		{name: "Built-in upstream authority", extraConfig: `{"plugins":{"UpstreamAuthority":[{"disk":{"plugin_data":{"cert_file_path":"/ca/tls.crt","key_file_path":"/ca/tls.key"}}}]}}`},
+		{name: "Misspelled upstream authority", extraConfig: `{"plugins":{"UpstreamAuthority":[{"aws_secret":{"plugin_data":{}}}]}}`, expectError: true},
+		{name: "Unknown top level key", extraConfig: `{"sever":{"ca_ttl":"48h"}}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestSpireServer()
+			if tt.extraConfig != "" {
+				server.Spec.ExtraConfig = &apiextensionsv1.JSON{Raw: []byte(tt.extraConfig)}
+			}
+			err := validateRenderedServerConfig(&server.Spec, ztwim)
+			if (err != nil) != tt.expectError {
+				t.Errorf("validateRenderedServerConfig() error = %v, expectError = %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateHealthPort(t *testing.T) {
+	if err := validateHealthPort(&v1alpha1.SpireServerSpec{}); err != nil {
+		t.Errorf("Expected the default health port to be valid, got: %v", err)
+	}
+	if err := validateHealthPort(&v1alpha1.SpireServerSpec{HealthPort: 8090}); err != nil {
+		t.Errorf("Expected a free health port to be valid, got: %v", err)
+	}
+	if err := validateHealthPort(&v1alpha1.SpireServerSpec{HealthPort: 8081}); err == nil || !strings.Contains(err.Error(), "SPIRE server API") {
+		t.Errorf("Expected the port of the SPIRE server API to be refused, got: %v", err)
+	}
+}
+
+func TestValidatePersistence(t *testing.T) {
+	memoryKeyManager := &v1alpha1.KeyManager{DiskEnabled: "false", MemoryEnabled: "true"}
+	diskKeyManager := &v1alpha1.KeyManager{DiskEnabled: "true", MemoryEnabled: "false"}
+
+	tests := []struct {
+		name            string
+		persistenceType string
+		databaseType    string
+		keyManager      *v1alpha1.KeyManager
+		expectError     bool
+	}{
+		{name: "PVC with sqlite3 and the disk key manager", persistenceType: "PersistentVolumeClaim", databaseType: "sqlite3", keyManager: diskKeyManager},
+		{name: "Unset type defaults to a PVC", databaseType: "sqlite3"},
+		{name: "emptyDir with an external datastore and the memory key manager", persistenceType: "EmptyDir", databaseType: "postgres", keyManager: memoryKeyManager},
+		{name: "emptyDir with sqlite3", persistenceType: "EmptyDir", databaseType: "sqlite3", keyManager: memoryKeyManager, expectError: true},
+		{name: "emptyDir with the disk key manager", persistenceType: "EmptyDir", databaseType: "postgres", keyManager: diskKeyManager, expectError: true},
+		{name: "emptyDir with the default key manager", persistenceType: "EmptyDir", databaseType: "postgres", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePersistence(&v1alpha1.SpireServerSpec{
+				Persistence: v1alpha1.Persistence{Type: tt.persistenceType},
+				Datastore:   v1alpha1.DataStore{DatabaseType: tt.databaseType},
+				KeyManager:  tt.keyManager,
+			})
+			if (err != nil) != tt.expectError {
+				t.Errorf("validatePersistence() error = %v, expectError = %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateControllerManager(t *testing.T) {
+	tests := []struct {
+		name              string
+		controllerManager *v1alpha1.ControllerManagerConfig
+		expectError       string
+	}{
+		{name: "Unset"},
+		{
+			name: "Custom template and selectors",
+			controllerManager: &v1alpha1.ControllerManagerConfig{
+				ClassName:               "acme-spire",
+				DefaultSPIFFEIDTemplate: "spiffe://{{ .TrustDomain }}/team/{{ index .PodMeta.Labels \"team\" }}/sa/{{ .PodSpec.ServiceAccountName }}",
+				NamespaceSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"spiffe": "enabled"}},
+			},
+		},
+		{
+			name:              "Template without the spiffe scheme",
+			controllerManager: &v1alpha1.ControllerManagerConfig{DefaultSPIFFEIDTemplate: "{{ .TrustDomain }}/ns/{{ .PodMeta.Namespace }}"},
+			expectError:       "must start with spiffe://",
+		},
+		{
+			name:              "Unparsable template",
+			controllerManager: &v1alpha1.ControllerManagerConfig{DefaultSPIFFEIDTemplate: "spiffe://{{ .TrustDomain }/ns"},
+			expectError:       "controllerManager.defaultSPIFFEIDTemplate",
+		},
+		{
+			name:              "DNS name templates",
+			controllerManager: &v1alpha1.ControllerManagerConfig{DNSNameTemplates: []string{"{{ .PodMeta.Name }}", "{{ .PodMeta.Name }}.{{ .PodMeta.Namespace }}.svc"}},
+		},
+		{
+			name:              "Empty DNS name template",
+			controllerManager: &v1alpha1.ControllerManagerConfig{DNSNameTemplates: []string{"{{ .PodMeta.Name }}", " "}},
+			expectError:       "controllerManager.dnsNameTemplates[1] is empty",
+		},
+		{
+			name:              "Unparsable DNS name template",
+			controllerManager: &v1alpha1.ControllerManagerConfig{DNSNameTemplates: []string{"{{ .PodMeta.Name"}},
+			expectError:       "controllerManager.dnsNameTemplates[0]",
+		},
+		{
+			name: "Invalid pod selector",
+			controllerManager: &v1alpha1.ControllerManagerConfig{PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Between"}},
+			}},
+			expectError: "controllerManager.podSelector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateControllerManager(tt.controllerManager)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	t.Setenv("FIPS_MODE", "false")
+
+	validSpec := func() *v1alpha1.SpireServerSpec {
+		return &v1alpha1.SpireServerSpec{
+			JwtIssuer:           "https://oidc.example.org",
+			CAValidity:          metav1.Duration{Duration: 24 * time.Hour},
+			DefaultX509Validity: metav1.Duration{Duration: time.Hour},
+			DefaultJWTValidity:  metav1.Duration{Duration: 5 * time.Minute},
+			Datastore:           v1alpha1.DataStore{DatabaseType: "sqlite3"},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		mutate         func(*v1alpha1.SpireServerSpec)
+		expectError    string
+		expectWarnings int
+	}{
+		{
+			name:   "Valid spec",
+			mutate: func(*v1alpha1.SpireServerSpec) {},
+		},
+		{
+			name:        "Invalid JWT issuer",
+			mutate:      func(s *v1alpha1.SpireServerSpec) { s.JwtIssuer = "oidc.example.org" },
+			expectError: "jwtIssuer",
+		},
+		{
+			name:        "SVID TTL longer than CA TTL",
+			mutate:      func(s *v1alpha1.SpireServerSpec) { s.DefaultX509Validity = metav1.Duration{Duration: 48 * time.Hour} },
+			expectError: "ca_validity must be greater than",
+		},
+		{
+			name:           "SVID TTL too high for CA TTL is a warning",
+			mutate:         func(s *v1alpha1.SpireServerSpec) { s.DefaultX509Validity = metav1.Duration{Duration: 12 * time.Hour} },
+			expectWarnings: 1,
+		},
+		{
+			name:        "Unknown database type",
+			mutate:      func(s *v1alpha1.SpireServerSpec) { s.Datastore.DatabaseType = "sql" },
+			expectError: "databaseType",
+		},
+		{
+			name: "Malformed federated trust domain",
+			mutate: func(s *v1alpha1.SpireServerSpec) {
+				s.Federation = &v1alpha1.FederationConfig{
+					BundleEndpoint: v1alpha1.BundleEndpointConfig{Profile: v1alpha1.HttpsSpiffeProfile},
+					FederatesWith: []v1alpha1.FederatesWithConfig{
+						{TrustDomain: "Remote.Example", BundleEndpointUrl: "https://remote.example", BundleEndpointProfile: v1alpha1.HttpsWebProfile},
+					},
+				}
+			},
+			expectError: "federatesWith[0]",
+		},
+		{
+			name: "Self federation",
+			mutate: func(s *v1alpha1.SpireServerSpec) {
+				s.Federation = &v1alpha1.FederationConfig{
+					BundleEndpoint: v1alpha1.BundleEndpointConfig{Profile: v1alpha1.HttpsSpiffeProfile},
+					FederatesWith: []v1alpha1.FederatesWithConfig{
+						{TrustDomain: "example.org", BundleEndpointUrl: "https://remote.example", BundleEndpointProfile: v1alpha1.HttpsWebProfile},
+					},
+				}
+			},
+			expectError: "cannot federate with own trust domain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			tt.mutate(spec)
+			warnings, err := ValidateSpec(spec, "example.org")
+			if tt.expectError == "" && err != nil {
+				t.Fatalf("ValidateSpec() unexpected error = %v", err)
+			}
+			if tt.expectError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectError)) {
+				t.Fatalf("ValidateSpec() error = %v, expected to contain %q", err, tt.expectError)
+			}
+			if len(warnings) != tt.expectWarnings {
+				t.Errorf("ValidateSpec() returned %d warnings, expected %d: %v", len(warnings), tt.expectWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateFeatureGates(t *testing.T) {
+	disabled := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			FeatureGates: []v1alpha1.FeatureGate{
+				{Name: v1alpha1.FeatureGateFederation, Enabled: false},
+				{Name: v1alpha1.FeatureGateNestedTopology, Enabled: false},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		spec        *v1alpha1.SpireServerSpec
+		ztwim       *v1alpha1.ZeroTrustWorkloadIdentityManager
+		expectError string
+	}{
+		{
+			name:  "gated capabilities unused",
+			spec:  &v1alpha1.SpireServerSpec{},
+			ztwim: disabled,
+		},
+		{
+			name:  "federation with the default gates",
+			spec:  &v1alpha1.SpireServerSpec{Federation: &v1alpha1.FederationConfig{}},
+			ztwim: &v1alpha1.ZeroTrustWorkloadIdentityManager{},
+		},
+		{
+			name:        "federation with the gate disabled",
+			spec:        &v1alpha1.SpireServerSpec{Federation: &v1alpha1.FederationConfig{}},
+			ztwim:       disabled,
+			expectError: "federation requires the Federation feature gate",
+		},
+		{
+			name:        "upstream authority with the gate disabled",
+			spec:        &v1alpha1.SpireServerSpec{UpstreamAuthority: &v1alpha1.UpstreamAuthorityConfig{}},
+			ztwim:       disabled,
+			expectError: "upstreamAuthority requires the NestedTopology feature gate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFeatureGates(tt.spec, tt.ztwim)
+			if tt.expectError == "" && err != nil {
+				t.Fatalf("validateFeatureGates() unexpected error = %v", err)
+			}
+			if tt.expectError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectError)) {
+				t.Fatalf("validateFeatureGates() error = %v, expected to contain %q", err, tt.expectError)
+			}
+		})
+	}
+}
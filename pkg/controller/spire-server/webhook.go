@@ -29,6 +29,7 @@ func (r *SpireServerReconciler) reconcileWebhook(ctx context.Context, server *v1
 			metav1.ConditionFalse)
 		return err
 	}
+	statusMgr.TrackResource(desired)
 
 	// Get existing resource (from cache)
 	existing := &admissionregistrationv1.ValidatingWebhookConfiguration{}
@@ -54,6 +55,7 @@ func (r *SpireServerReconciler) reconcileWebhook(ctx context.Context, server *v1
 		}
 
 		r.log.Info("Created ValidatingWebhookConfiguration", "name", desired.Name)
+		statusMgr.RecordResourceCreated(desired)
 		statusMgr.AddCondition(ValidatingWebhookAvailable, v1alpha1.ReasonReady,
 			"All ValidatingWebhookConfiguration resources available",
 			metav1.ConditionTrue)
@@ -125,6 +127,7 @@ func (r *SpireServerReconciler) reconcileWebhook(ctx context.Context, server *v1
 	}
 
 	r.log.Info("Updated ValidatingWebhookConfiguration", "name", desired.Name)
+	statusMgr.RecordDriftRepaired(desired)
 	statusMgr.AddCondition(ValidatingWebhookAvailable, v1alpha1.ReasonReady,
 		"All ValidatingWebhookConfiguration resources available",
 		metav1.ConditionTrue)
@@ -138,7 +141,7 @@ func getSpireControllerManagerValidatingWebhookConfiguration(customLabels map[st
 	// Update webhook service namespaces dynamically
 	for i := range webhook.Webhooks {
 		if webhook.Webhooks[i].ClientConfig.Service != nil {
-			webhook.Webhooks[i].ClientConfig.Service.Namespace = utils.GetOperatorNamespace()
+			webhook.Webhooks[i].ClientConfig.Service.Namespace = utils.GetOperandNamespace()
 		}
 	}
 	return webhook
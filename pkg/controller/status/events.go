@@ -0,0 +1,111 @@
+package status
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// Reasons of the events recorded on the operand CRs
+const (
+	EventReasonResourceCreated  = "ResourceCreated"
+	EventReasonDriftRepaired    = "DriftRepaired"
+	EventReasonRolloutTriggered = "RolloutTriggered"
+	EventReasonValidationFailed = "ValidationFailed"
+	EventReasonResourceAdopted  = "ResourceAdopted"
+)
+
+// SetEventRecorder records the significant reconcile actions as events on obj.
+// In dry-run mode the resource writes are not recorded, since SetDryRunStatus already
+// emits them as planned changes; validation failures are recorded in both modes.
+func (m *Manager) SetEventRecorder(recorder record.EventRecorder, obj runtime.Object, dryRun bool) {
+	m.recorder = recorder
+	m.eventObject = obj
+	m.resourceEventsDisabled = dryRun
+}
+
+// RecordResourceCreated records that the operator created resource
+func (m *Manager) RecordResourceCreated(resource client.Object) {
+	m.recordResourceEvent(EventReasonResourceCreated, "Created %s", resource)
+}
+
+// RecordDriftRepaired records that the operator updated resource back to the desired state
+func (m *Manager) RecordDriftRepaired(resource client.Object) {
+	m.recordResourceEvent(EventReasonDriftRepaired, "Updated %s to match the desired state", resource)
+}
+
+// RecordRolloutTriggered records that updating resource restarts its pods, with the cause
+// of the rollout, e.g. a configuration change
+func (m *Manager) RecordRolloutTriggered(resource client.Object, cause string) {
+	m.recordResourceEvent(EventReasonRolloutTriggered, "Rolling out %s: "+strings.ReplaceAll(cause, "%", "%%"), resource)
+}
+
+// RecordResourcesAdopted records the resources created by another installer that the operator adopted
+func (m *Manager) RecordResourcesAdopted(adoption *customClient.AdoptionClient) {
+	if adoption == nil {
+		return
+	}
+	for _, resource := range adoption.Adopted() {
+		m.recordResourceEvent(EventReasonResourceAdopted, "Adopted %s", resource)
+	}
+}
+
+// RecordWorkloadUpdated records the update of a workload as a rollout when one of the given
+// config hash annotations of its pod template changed, and as a repaired drift otherwise
+func (m *Manager) RecordWorkloadUpdated(resource client.Object, existing, desired *corev1.PodTemplateSpec, configHashAnnotations ...string) {
+	for _, key := range configHashAnnotations {
+		if existing.Annotations[key] != desired.Annotations[key] {
+			m.RecordRolloutTriggered(resource, fmt.Sprintf("the configuration tracked by %s changed", key))
+			return
+		}
+	}
+	m.RecordDriftRepaired(resource)
+}
+
+// recordResourceEvent records a Normal event whose message formats the kind and key of resource
+func (m *Manager) recordResourceEvent(reason, messageFmt string, resource client.Object) {
+	if m.recorder == nil || m.resourceEventsDisabled {
+		return
+	}
+	m.recorder.Eventf(m.eventObject, corev1.EventTypeNormal, reason, messageFmt,
+		resourceKind(resource)+" "+objectKeyString(resource.GetNamespace(), resource.GetName()))
+}
+
+// recordValidationEvents records a warning for each configuration validation condition that
+// turned False, or whose failure message changed, compared to the previous conditions
+func (m *Manager) recordValidationEvents(previous []metav1.Condition) {
+	if m.recorder == nil {
+		return
+	}
+	for condType, cond := range m.conditions {
+		// Covers ConfigurationValid and the operand specific variants such as TTLConfigurationValid
+		if !strings.HasSuffix(condType, utils.ConditionTypeConfigurationValid) || cond.Status != metav1.ConditionFalse {
+			continue
+		}
+		if existing := apimeta.FindStatusCondition(previous, condType); existing != nil &&
+			existing.Status == metav1.ConditionFalse && existing.Message == cond.Message {
+			continue
+		}
+		m.recorder.Event(m.eventObject, corev1.EventTypeWarning, EventReasonValidationFailed,
+			fmt.Sprintf("%s: %s", cond.Reason, cond.Message))
+	}
+}
+
+// resourceKind returns the kind of resource, falling back to its Go type name for typed
+// objects that do not carry type information
+func resourceKind(resource client.Object) string {
+	if kind := resource.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(resource)).Type().Name()
+}
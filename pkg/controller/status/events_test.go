@@ -0,0 +1,221 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordResourceEvents(t *testing.T) {
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: "ns"}}
+
+	t.Run("resource writes are recorded on the CR", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+		mgr.SetEventRecorder(recorder, &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}, false)
+
+		mgr.RecordResourceCreated(serviceAccount)
+		mgr.RecordDriftRepaired(serviceAccount)
+
+		if event := <-recorder.Events; event != "Normal ResourceCreated Created ServiceAccount ns/spire-agent" {
+			t.Errorf("Unexpected event %q", event)
+		}
+		if event := <-recorder.Events; event != "Normal DriftRepaired Updated ServiceAccount ns/spire-agent to match the desired state" {
+			t.Errorf("Unexpected event %q", event)
+		}
+	})
+
+	t.Run("resource writes are not recorded in dry-run mode", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+		mgr.SetEventRecorder(recorder, &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}, true)
+
+		mgr.RecordResourceCreated(serviceAccount)
+
+		if len(recorder.Events) != 0 {
+			t.Errorf("Expected no events, got %d", len(recorder.Events))
+		}
+	})
+
+	t.Run("no events without a recorder", func(t *testing.T) {
+		mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+		mgr.RecordResourceCreated(serviceAccount)
+	})
+}
+
+func TestRecordResourcesAdopted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	helmConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "ns",
+		Labels: map[string]string{utils.AppManagedByLabelKey: "Helm"}}}
+	helmStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "ns"},
+		Spec: appsv1.StatefulSetSpec{
+			PersistentVolumeClaimRetentionPolicy: &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+				WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+			},
+		},
+	}
+	managedServiceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "ns",
+		Labels: map[string]string{utils.AppManagedByLabelKey: utils.AppManagedByLabelValue}}}
+
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	fakeClient.APIReaderReturns(fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(helmConfigMap, helmStatefulSet, managedServiceAccount).Build())
+	fakeClient.CreateReturns(kerrors.NewAlreadyExists(schema.GroupResource{}, "spire-server"))
+	// The first apply of the StatefulSet is rejected as its selector can't be changed
+	fakeClient.ApplyReturnsOnCall(1, kerrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, "spire-server",
+		field.ErrorList{field.Forbidden(field.NewPath("spec", "selector"), "field is immutable")}))
+	adoption := customClient.NewAdoptionClient(fakeClient)
+	ctx := context.Background()
+
+	if err := adoption.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "ns"}}); err != nil {
+		t.Fatalf("Expected the Helm ConfigMap to be adopted, got %v", err)
+	}
+	if err := adoption.Apply(ctx, &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "ns"}}); err != nil {
+		t.Fatalf("Expected the Helm StatefulSet to be adopted, got %v", err)
+	}
+	if err := adoption.Create(ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "ns"}}); !kerrors.IsAlreadyExists(err) {
+		t.Errorf("Expected a managed resource to keep the create conflict, got %v", err)
+	}
+
+	if fakeClient.ApplyCallCount() != 3 {
+		t.Errorf("Expected the ConfigMap to be applied once and the StatefulSet twice, got %d applies", fakeClient.ApplyCallCount())
+	}
+	if fakeClient.DeleteCallCount() != 1 {
+		t.Fatalf("Expected the StatefulSet to be recreated, got %d deletes", fakeClient.DeleteCallCount())
+	}
+	if fakeClient.UpdateCallCount() != 1 {
+		t.Fatalf("Expected the volume claims to be retained before the StatefulSet is deleted, got %d updates", fakeClient.UpdateCallCount())
+	}
+	_, updated, _ := fakeClient.UpdateArgsForCall(0)
+	if policy := updated.(*appsv1.StatefulSet).Spec.PersistentVolumeClaimRetentionPolicy; policy.WhenDeleted != appsv1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("Expected the volume claims to be retained, got %v", policy.WhenDeleted)
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	mgr := NewManager(fakeClient)
+	mgr.SetEventRecorder(recorder, &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}, false)
+	mgr.RecordResourcesAdopted(adoption)
+	mgr.RecordResourcesAdopted(nil)
+
+	if event := <-recorder.Events; event != "Normal ResourceAdopted Adopted ConfigMap ns/spire-server" {
+		t.Errorf("Unexpected event %q", event)
+	}
+	if event := <-recorder.Events; event != "Normal ResourceAdopted Adopted StatefulSet ns/spire-server" {
+		t.Errorf("Unexpected event %q", event)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected only the adopted resources to be recorded, got %d more events", len(recorder.Events))
+	}
+}
+
+func TestRecordWorkloadUpdated(t *testing.T) {
+	const hashKey = "ztwim.openshift.io/spire-agent-config-hash"
+	daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: "ns"}}
+
+	tests := []struct {
+		name     string
+		existing string
+		desired  string
+		expected string
+	}{
+		{
+			name:     "config hash change triggers a rollout",
+			existing: "old",
+			desired:  "new",
+			expected: "Normal RolloutTriggered Rolling out DaemonSet ns/spire-agent: the configuration tracked by " + hashKey + " changed",
+		},
+		{
+			name:     "unchanged config hash repairs drift",
+			existing: "same",
+			desired:  "same",
+			expected: "Normal DriftRepaired Updated DaemonSet ns/spire-agent to match the desired state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+			mgr.SetEventRecorder(recorder, &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}, false)
+
+			existing := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{hashKey: tt.existing}}}
+			desired := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{hashKey: tt.desired}}}
+			mgr.RecordWorkloadUpdated(daemonSet, existing, desired, hashKey)
+
+			if event := <-recorder.Events; event != tt.expected {
+				t.Errorf("Expected event %q, got %q", tt.expected, event)
+			}
+		})
+	}
+}
+
+func TestValidationFailedEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		previous       []metav1.Condition
+		message        string
+		expectedEvents int
+	}{
+		{
+			name:           "new validation failure is recorded",
+			previous:       []metav1.Condition{{Type: utils.ConditionTypeConfigurationValid, Status: metav1.ConditionTrue}},
+			message:        "Affinity validation failed",
+			expectedEvents: 1,
+		},
+		{
+			name:           "unchanged validation failure is not recorded again",
+			previous:       []metav1.Condition{{Type: utils.ConditionTypeConfigurationValid, Status: metav1.ConditionFalse, Message: "Affinity validation failed"}},
+			message:        "Affinity validation failed",
+			expectedEvents: 0,
+		},
+		{
+			name:           "changed validation failure is recorded",
+			previous:       []metav1.Condition{{Type: utils.ConditionTypeConfigurationValid, Status: metav1.ConditionFalse, Message: "Affinity validation failed"}},
+			message:        "Tolerations validation failed",
+			expectedEvents: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			obj := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+			obj.Status.Conditions = tt.previous
+
+			mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+			mgr.SetEventRecorder(recorder, obj, true)
+			mgr.AddCondition(utils.ConditionTypeConfigurationValid, "InvalidAffinity", tt.message, metav1.ConditionFalse)
+			if err := mgr.ApplyStatus(context.Background(), obj, func() *v1alpha1.ConditionalStatus {
+				return &obj.Status.ConditionalStatus
+			}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(recorder.Events) != tt.expectedEvents {
+				t.Fatalf("Expected %d event(s), got %d", tt.expectedEvents, len(recorder.Events))
+			}
+			if tt.expectedEvents > 0 {
+				if event := <-recorder.Events; event != "Warning ValidationFailed InvalidAffinity: "+tt.message {
+					t.Errorf("Unexpected event %q", event)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// TrackResource records resources generated by the current reconciliation in the inventory
+func (m *Manager) TrackResource(objs ...client.Object) {
+	m.trackedResources = append(m.trackedResources, objs...)
+}
+
+// PruneOrphanedResources deletes the resources listed in the previous inventory that were
+// not generated by the current reconciliation, and publishes the new inventory in
+// status.managedResources. It must only be called once every resource of the spec was
+// reconciled, so that an early return never prunes resources that are still wanted.
+// In create-only mode nothing is deleted and the previous inventory is kept alongside the
+// current one, so the orphans can still be pruned once create-only mode is disabled.
+// In dry-run mode the deletes are only recorded by the dry-run client c, so the orphans are
+// kept in the inventory as well, to be pruned once the reconcile mode is switched to Apply.
+func (m *Manager) PruneOrphanedResources(ctx context.Context, c customClient.CustomCtrlClient, scheme *runtime.Scheme, previous []v1alpha1.ManagedResource, createOnlyMode, dryRun bool) ([]v1alpha1.ManagedResource, error) {
+	current := make([]v1alpha1.ManagedResource, 0, len(m.trackedResources))
+	seen := make(map[v1alpha1.ManagedResource]bool, len(m.trackedResources))
+	for _, obj := range m.trackedResources {
+		resource, err := managedResourceFor(obj, scheme)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[resource] {
+			seen[resource] = true
+			current = append(current, resource)
+		}
+	}
+
+	var pruned []v1alpha1.ManagedResource
+	for _, resource := range previous {
+		if seen[resource] {
+			continue
+		}
+		if createOnlyMode {
+			seen[resource] = true
+			current = append(current, resource)
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(resource.APIVersion)
+		obj.SetKind(resource.Kind)
+		obj.SetNamespace(resource.Namespace)
+		obj.SetName(resource.Name)
+		if err := utils.DeleteObjects(ctx, c, obj); err != nil {
+			return nil, err
+		}
+		if dryRun {
+			seen[resource] = true
+			current = append(current, resource)
+			continue
+		}
+		pruned = append(pruned, resource)
+	}
+
+	m.managedResources = current
+	m.managedResourcesSet = true
+	return pruned, nil
+}
+
+// RemoveManagedResources deletes objs and the resources listed in the previous inventory, and clears
+// status.managedResources. objs are deleted first, so that the workloads are gone before the resources
+// they use, and regardless of the inventory, which is empty for resources created by older versions.
+func (m *Manager) RemoveManagedResources(ctx context.Context, c customClient.CustomCtrlClient, scheme *runtime.Scheme, previous []v1alpha1.ManagedResource, objs ...client.Object) error {
+	if err := utils.DeleteObjects(ctx, c, objs...); err != nil {
+		return err
+	}
+	m.trackedResources = nil
+	_, err := m.PruneOrphanedResources(ctx, c, scheme, previous, false, false)
+	return err
+}
+
+// managedResourceFor returns the inventory entry for obj, resolving its kind from the scheme
+// when the object does not carry type information
+func managedResourceFor(obj client.Object, scheme *runtime.Scheme) (v1alpha1.ManagedResource, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		var err error
+		if gvk, err = apiutil.GVKForObject(obj, scheme); err != nil {
+			return v1alpha1.ManagedResource{}, fmt.Errorf("failed to resolve the kind of %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+	}
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return v1alpha1.ManagedResource{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}, nil
+}
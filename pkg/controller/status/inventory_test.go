@@ -0,0 +1,220 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPruneOrphanedResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	serviceAccount := v1alpha1.ManagedResource{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "ns", Name: "sa"}
+	daemonSet := v1alpha1.ManagedResource{APIVersion: "apps/v1", Kind: "DaemonSet", Namespace: "ns", Name: "ds"}
+	orphan := v1alpha1.ManagedResource{APIVersion: "v1", Kind: "Service", Namespace: "ns", Name: "old"}
+
+	tracked := func() []client.Object {
+		return []client.Object{
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sa"}},
+			&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ds"}},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		previous       []v1alpha1.ManagedResource
+		createOnlyMode bool
+		dryRun         bool
+		deleteErr      error
+		expectPruned   []v1alpha1.ManagedResource
+		expectCurrent  []v1alpha1.ManagedResource
+		expectDeletes  int
+		expectError    string
+	}{
+		{
+			name:          "first reconciliation records the inventory",
+			expectCurrent: []v1alpha1.ManagedResource{serviceAccount, daemonSet},
+		},
+		{
+			name:          "unchanged inventory prunes nothing",
+			previous:      []v1alpha1.ManagedResource{serviceAccount, daemonSet},
+			expectCurrent: []v1alpha1.ManagedResource{serviceAccount, daemonSet},
+		},
+		{
+			name:          "resource no longer generated is pruned",
+			previous:      []v1alpha1.ManagedResource{serviceAccount, orphan},
+			expectPruned:  []v1alpha1.ManagedResource{orphan},
+			expectCurrent: []v1alpha1.ManagedResource{serviceAccount, daemonSet},
+			expectDeletes: 1,
+		},
+		{
+			name:          "already deleted orphan is dropped from the inventory",
+			previous:      []v1alpha1.ManagedResource{orphan},
+			deleteErr:     kerrors.NewNotFound(schema.GroupResource{Resource: "services"}, "old"),
+			expectPruned:  []v1alpha1.ManagedResource{orphan},
+			expectCurrent: []v1alpha1.ManagedResource{serviceAccount, daemonSet},
+			expectDeletes: 1,
+		},
+		{
+			name:           "create-only mode keeps orphans in the inventory",
+			previous:       []v1alpha1.ManagedResource{orphan},
+			createOnlyMode: true,
+			expectCurrent:  []v1alpha1.ManagedResource{serviceAccount, daemonSet, orphan},
+		},
+		{
+			name:          "dry-run mode records the delete and keeps orphans in the inventory",
+			previous:      []v1alpha1.ManagedResource{orphan},
+			dryRun:        true,
+			expectCurrent: []v1alpha1.ManagedResource{serviceAccount, daemonSet, orphan},
+			expectDeletes: 1,
+		},
+		{
+			name:          "delete failure is returned",
+			previous:      []v1alpha1.ManagedResource{orphan},
+			deleteErr:     errors.New("forbidden"),
+			expectDeletes: 1,
+			expectError:   "forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			fakeClient.DeleteReturns(tt.deleteErr)
+
+			m := NewManager(fakeClient)
+			m.TrackResource(tracked()...)
+			// Tracking the same object twice must not duplicate the entry
+			m.TrackResource(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sa"}})
+
+			pruned, err := m.PruneOrphanedResources(context.Background(), fakeClient, scheme, tt.previous, tt.createOnlyMode, tt.dryRun)
+
+			if fakeClient.DeleteCallCount() != tt.expectDeletes {
+				t.Errorf("Expected %d deletes, got %d", tt.expectDeletes, fakeClient.DeleteCallCount())
+			}
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				if m.managedResourcesSet {
+					t.Error("Expected the inventory not to be published after a failed prune")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !equalResources(pruned, tt.expectPruned) {
+				t.Errorf("Expected pruned %v, got %v", tt.expectPruned, pruned)
+			}
+			if !m.managedResourcesSet || !equalResources(m.managedResources, tt.expectCurrent) {
+				t.Errorf("Expected inventory %v, got %v", tt.expectCurrent, m.managedResources)
+			}
+			if tt.expectDeletes > 0 {
+				_, obj, _ := fakeClient.DeleteArgsForCall(0)
+				if obj.GetObjectKind().GroupVersionKind().Kind != orphan.Kind || obj.GetName() != orphan.Name {
+					t.Errorf("Expected delete of %s/%s, got %s/%s", orphan.Kind, orphan.Name, obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveManagedResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	m := NewManager(fakeClient)
+	m.TrackResource(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sa"}})
+
+	previous := []v1alpha1.ManagedResource{
+		{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "ns", Name: "sa"},
+		{APIVersion: "apps/v1", Kind: "DaemonSet", Namespace: "ns", Name: "ds"},
+	}
+	workload := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ds"}}
+	if err := m.RemoveManagedResources(context.Background(), fakeClient, scheme, previous, workload); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if fakeClient.DeleteCallCount() != 3 {
+		t.Fatalf("Expected the workload and the whole inventory to be deleted, got %d deletes", fakeClient.DeleteCallCount())
+	}
+	if _, obj, _ := fakeClient.DeleteArgsForCall(0); obj != workload {
+		t.Errorf("Expected the workload to be deleted first, got %s", obj.GetName())
+	}
+	if !m.managedResourcesSet || len(m.managedResources) != 0 {
+		t.Errorf("Expected an empty inventory, got %v", m.managedResources)
+	}
+
+	fakeClient.DeleteReturns(errors.New("forbidden"))
+	if err := NewManager(fakeClient).RemoveManagedResources(context.Background(), fakeClient, scheme, previous, workload); err == nil {
+		t.Error("Expected the delete failure to be returned")
+	}
+}
+
+func TestManagedResourceFor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	t.Run("type meta takes precedence over the scheme", func(t *testing.T) {
+		obj := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "deploy"},
+		}
+		resource, err := managedResourceFor(obj, scheme)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := v1alpha1.ManagedResource{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "ns", Name: "deploy"}
+		if resource != expected {
+			t.Errorf("Expected %v, got %v", expected, resource)
+		}
+	})
+
+	t.Run("kind is resolved from the scheme", func(t *testing.T) {
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"}}
+		resource, err := managedResourceFor(obj, scheme)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := v1alpha1.ManagedResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns", Name: "cm"}
+		if resource != expected {
+			t.Errorf("Expected %v, got %v", expected, resource)
+		}
+	})
+
+	t.Run("unregistered type is an error", func(t *testing.T) {
+		obj := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sts"}}
+		if _, err := managedResourceFor(obj, scheme); err == nil {
+			t.Error("Expected an error for a type missing from the scheme")
+		}
+	})
+}
+
+func equalResources(a, b []v1alpha1.ManagedResource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
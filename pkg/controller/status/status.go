@@ -3,12 +3,16 @@ package status
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
@@ -27,6 +31,24 @@ type Condition struct {
 type Manager struct {
 	customClient customClient.CustomCtrlClient
 	conditions   map[string]Condition
+
+	// plannedChanges is applied to status.plannedChanges when plannedChangesSet is true
+	plannedChanges    []v1alpha1.PlannedChange
+	plannedChangesSet bool
+
+	// trackedResources are the resources generated by the current reconciliation, and
+	// managedResources is applied to status.managedResources when managedResourcesSet is true
+	trackedResources    []client.Object
+	managedResources    []v1alpha1.ManagedResource
+	managedResourcesSet bool
+
+	// forceUpdate makes ApplyStatus update the status even when the conditional status is unchanged
+	forceUpdate bool
+
+	// recorder records the reconcile actions as events on eventObject, see SetEventRecorder
+	recorder               record.EventRecorder
+	eventObject            runtime.Object
+	resourceEventsDisabled bool
 }
 
 // NewManager creates a new status manager
@@ -45,11 +67,66 @@ func SetInitialReconciliationStatus(ctx context.Context, customClient customClie
 	initialStatusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonInProgress,
 		fmt.Sprintf("Reconciling %s", resourceName),
 		metav1.ConditionFalse)
+	pausedCondition := apimeta.FindStatusCondition(getStatus().Conditions, utils.PausedStatusType)
+	if pausedCondition != nil && pausedCondition.Status == metav1.ConditionTrue {
+		initialStatusMgr.AddCondition(utils.PausedStatusType, utils.ReconciliationResumed,
+			"Reconciliation is resumed",
+			metav1.ConditionFalse)
+	}
 	if err := initialStatusMgr.ApplyStatus(ctx, obj, getStatus); err != nil {
 		fmt.Printf("cannot apply the initial status %v", err)
 	}
 }
 
+// SetPausedStatus reports that reconciliation of obj is paused by setting the Paused condition.
+// The managed resources are not inspected while paused, so the Ready condition from the last
+// reconciliation is kept as is.
+func SetPausedStatus(ctx context.Context, customClient customClient.CustomCtrlClient, obj client.Object, getStatus func() *v1alpha1.ConditionalStatus) error {
+	pausedStatusMgr := NewManager(customClient)
+	pausedStatusMgr.AddCondition(utils.PausedStatusType, utils.ReconciliationPaused,
+		"Reconciliation is paused: changes to managed resources are not reverted",
+		metav1.ConditionTrue)
+	if ready := apimeta.FindStatusCondition(getStatus().Conditions, v1alpha1.Ready); ready != nil {
+		pausedStatusMgr.AddCondition(ready.Type, ready.Reason, ready.Message, ready.Status)
+	} else {
+		pausedStatusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonInProgress,
+			"Reconciliation is paused",
+			metav1.ConditionFalse)
+	}
+	return pausedStatusMgr.ApplyStatus(ctx, obj, getStatus)
+}
+
+// SetManagementStateCondition reports the management state in effect for the resources of resourceName.
+// The Ready condition is set to False while the resources are Unmanaged or Removed. The ManagementState
+// condition is only reported for Managed resources once they were in another state.
+func (m *Manager) SetManagementStateCondition(state v1alpha1.ManagementState, resourceName string, existingConditions []metav1.Condition) {
+	var message string
+	switch state {
+	case v1alpha1.ManagementStateUnmanaged:
+		message = fmt.Sprintf("%s is unmanaged: changes to managed resources are not reverted", resourceName)
+	case v1alpha1.ManagementStateRemoved:
+		message = fmt.Sprintf("%s is removed: the managed resources are deleted", resourceName)
+	default:
+		if apimeta.FindStatusCondition(existingConditions, utils.ManagementStateStatusType) != nil {
+			m.AddCondition(utils.ManagementStateStatusType, string(v1alpha1.ManagementStateManaged),
+				fmt.Sprintf("%s is managed", resourceName),
+				metav1.ConditionTrue)
+		}
+		return
+	}
+	m.AddCondition(utils.ManagementStateStatusType, string(state), message, metav1.ConditionFalse)
+	m.AddCondition(v1alpha1.Ready, string(state), message, metav1.ConditionFalse)
+}
+
+// SetDeletionStatus reports the progress of the teardown of obj. The Deleting condition carries
+// the given reason and message, and Ready is set to False while the teardown is in progress.
+func SetDeletionStatus(ctx context.Context, customClient customClient.CustomCtrlClient, obj client.Object, getStatus func() *v1alpha1.ConditionalStatus, reason, message string) error {
+	deletionStatusMgr := NewManager(customClient)
+	deletionStatusMgr.AddCondition(utils.DeletionStatusType, reason, message, metav1.ConditionTrue)
+	deletionStatusMgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonInProgress, message, metav1.ConditionFalse)
+	return deletionStatusMgr.ApplyStatus(ctx, obj, getStatus)
+}
+
 // AddCondition adds or updates a condition
 func (m *Manager) AddCondition(conditionType, reason, message string, status metav1.ConditionStatus) {
 	m.conditions[conditionType] = Condition{
@@ -60,11 +137,109 @@ func (m *Manager) AddCondition(conditionType, reason, message string, status met
 	}
 }
 
+// SetPlannedChanges sets the changes published in status.plannedChanges by ApplyStatus.
+// Passing nil clears any previously published changes.
+func (m *Manager) SetPlannedChanges(changes []v1alpha1.PlannedChange) {
+	m.plannedChanges = changes
+	m.plannedChangesSet = true
+}
+
+// ForceStatusUpdate makes ApplyStatus update the status when only fields outside of the
+// conditional status changed, such as the federated trust domains of the SpireServer
+func (m *Manager) ForceStatusUpdate() {
+	m.forceUpdate = true
+}
+
+// SetDryRunStatus publishes the outcome of a dry-run reconciliation. The planned changes are
+// set in status and emitted as events on obj, and the DryRunMode condition summarizes them.
+// When dryRun is nil the planned changes are cleared, and a DryRunMode condition left over from
+// a previous dry run is reset.
+func (m *Manager) SetDryRunStatus(recorder record.EventRecorder, obj runtime.Object, conditions []metav1.Condition, dryRun *customClient.DryRunClient) {
+	if dryRun == nil {
+		m.SetPlannedChanges(nil)
+		existingCondition := apimeta.FindStatusCondition(conditions, utils.DryRunModeStatusType)
+		if existingCondition != nil && existingCondition.Status == metav1.ConditionTrue {
+			m.AddCondition(utils.DryRunModeStatusType, utils.DryRunModeDisabled,
+				"Dry-run mode is disabled",
+				metav1.ConditionFalse)
+		}
+		return
+	}
+
+	changes := dryRun.Changes()
+	m.SetPlannedChanges(changes)
+	for _, change := range changes {
+		recorder.Eventf(obj, corev1.EventTypeNormal, "DryRunPlannedChange", "Would %s %s %s",
+			strings.ToLower(change.Action), change.Kind, objectKeyString(change.Namespace, change.Name))
+	}
+	m.AddCondition(utils.DryRunModeStatusType, utils.DryRunModeEnabled,
+		fmt.Sprintf("Dry-run mode is active: %d change(s) planned and not applied, see status.plannedChanges", len(changes)),
+		metav1.ConditionTrue)
+}
+
+// SetExcludedResourcesStatus reports the writes skipped for the resources excluded from management in
+// the ExcludedResources condition. When exclusion is nil no resource is excluded, and an
+// ExcludedResources condition left over from previous exclusions is reset.
+func (m *Manager) SetExcludedResourcesStatus(conditions []metav1.Condition, exclusion *customClient.ExclusionClient) {
+	if exclusion == nil {
+		existingCondition := apimeta.FindStatusCondition(conditions, utils.ExcludedResourcesStatusType)
+		if existingCondition != nil && existingCondition.Status == metav1.ConditionTrue {
+			m.AddCondition(utils.ExcludedResourcesStatusType, utils.NoResourcesExcluded,
+				"No resource is excluded from management",
+				metav1.ConditionFalse)
+		}
+		return
+	}
+
+	skipped := exclusion.Skipped()
+	message := "Resources are excluded from management, no change skipped"
+	if len(skipped) > 0 {
+		resources := make([]string, 0, len(skipped))
+		for _, change := range skipped {
+			resources = append(resources, fmt.Sprintf("%s %s", change.Kind, objectKeyString(change.Namespace, change.Name)))
+		}
+		message = fmt.Sprintf("%d change(s) left to the owner of the excluded resources: %s", len(skipped), strings.Join(resources, ", "))
+	}
+	m.AddCondition(utils.ExcludedResourcesStatusType, utils.ResourcesExcluded, message, metav1.ConditionTrue)
+}
+
+// SetDegradedCondition reports a reconciliation that keeps failing in the Degraded condition, once it failed
+// the given number of consecutive times, at least the --degraded-failure-threshold, with err. When err is nil
+// the reconciliation succeeded, and a Degraded condition left over from previous failures is reset.
+func (m *Manager) SetDegradedCondition(conditions []metav1.Condition, failures int, err error) {
+	if err == nil {
+		existingCondition := apimeta.FindStatusCondition(conditions, v1alpha1.Degraded)
+		if existingCondition != nil && existingCondition.Status == metav1.ConditionTrue {
+			m.AddCondition(v1alpha1.Degraded, v1alpha1.ReasonReady,
+				"Reconciliation succeeded",
+				metav1.ConditionFalse)
+		}
+		return
+	}
+	if failures < utils.DegradedFailureThreshold() {
+		return
+	}
+	m.AddCondition(v1alpha1.Degraded, v1alpha1.ReasonFailed,
+		fmt.Sprintf("Reconciliation keeps failing and is retried with backoff: %v", err),
+		metav1.ConditionTrue)
+}
+
+// objectKeyString formats a namespace and name the way kubectl does
+func objectKeyString(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
 // SetReadyCondition sets the Ready condition based on all other conditions
 // Distinguishes between "Progressing" (normal startup/rollout) and "Failed" (actual errors)
 func (m *Manager) SetReadyCondition() {
-	// Check if any condition (except Ready, Degraded, and CreateOnlyMode) is False
-	// Note: CreateOnlyMode=False is normal (disabled state), not a failure
+	// Check if any condition (except Ready, Degraded, CreateOnlyMode, DryRunMode, Paused, ExcludedResources, FederatedBundlesHealthy,
+	// CARotationProgressing and ConfigReloaded) is False
+	// Note: CreateOnlyMode=False, DryRunMode=False, Paused=False, ExcludedResources=False and CARotationProgressing=False are
+	// normal (disabled or completed state), not a failure, FederatedBundlesHealthy=False reports the health of the
+	// federated trust domains, not of the operand, and ConfigReloaded=False leaves the operand running with its previous settings
 	hasProgressing := false
 	hasFailure := false
 	failureMessages := []string{}
@@ -75,11 +250,19 @@ func (m *Manager) SetReadyCondition() {
 		"StatefulSetNotReady": true,
 		"DaemonSetNotReady":   true,
 		"DeploymentNotReady":  true,
+		// Agent upgrades resume on their own once the SPIRE server rollout completes
+		"WaitingForSpireServer": true,
+		// Staged rollouts of the SPIRE agents wait for the canary nodes to be verified
+		"CanaryRolloutInProgress": true,
+		"CanarySoaking":           true,
+		"WaitingForApproval":      true,
+		// The address of the published SPIRE server API is assigned by the load balancer or the router
+		"ExposureAddressPending": true,
 	}
 
 	for condType, cond := range m.conditions {
 		// Skip conditions that don't indicate operational health
-		if condType == v1alpha1.Ready || condType == v1alpha1.Degraded || condType == utils.CreateOnlyModeStatusType {
+		if condType == v1alpha1.Ready || condType == v1alpha1.Degraded || condType == utils.CreateOnlyModeStatusType || condType == utils.DryRunModeStatusType || condType == utils.PausedStatusType || condType == utils.ExcludedResourcesStatusType || condType == utils.FederatedBundlesHealthyStatusType || condType == utils.CARotationProgressingStatusType || condType == utils.ConfigReloadedStatusType {
 			continue
 		}
 		if cond.Status == metav1.ConditionFalse {
@@ -142,8 +325,17 @@ func (m *Manager) ApplyStatus(ctx context.Context, obj client.Object, getStatus
 		apimeta.SetStatusCondition(&status.Conditions, newCondition)
 	}
 
+	m.recordValidationEvents(originalStatus.Conditions)
+
+	if m.plannedChangesSet {
+		status.PlannedChanges = m.plannedChanges
+	}
+	if m.managedResourcesSet {
+		status.ManagedResources = m.managedResources
+	}
+
 	// Only update if status has changed
-	if !equality.Semantic.DeepEqual(originalStatus, status) {
+	if m.forceUpdate || !equality.Semantic.DeepEqual(originalStatus, status) {
 		if err := m.customClient.StatusUpdateWithRetry(ctx, obj); err != nil {
 			return fmt.Errorf("failed to update status: %w", err)
 		}
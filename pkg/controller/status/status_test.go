@@ -7,9 +7,17 @@ import (
 	"testing"
 
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -436,6 +444,38 @@ func TestApplyStatus(t *testing.T) {
 	}
 }
 
+func TestApplyStatusForceStatusUpdate(t *testing.T) {
+	obj := &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	getStatus := func() *v1alpha1.ConditionalStatus { return &obj.Status.ConditionalStatus }
+
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	mgr := NewManager(fakeClient)
+	mgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonReady, "All components are ready", metav1.ConditionTrue)
+	if err := mgr.ApplyStatus(context.Background(), obj, getStatus); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The conditions are unchanged, so the status is only updated when forced
+	mgr = NewManager(fakeClient)
+	mgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonReady, "All components are ready", metav1.ConditionTrue)
+	if err := mgr.ApplyStatus(context.Background(), obj, getStatus); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fakeClient.StatusUpdateWithRetryCallCount() != 1 {
+		t.Errorf("Expected 1 status update for unchanged conditions, got %d", fakeClient.StatusUpdateWithRetryCallCount())
+	}
+
+	mgr = NewManager(fakeClient)
+	mgr.AddCondition(v1alpha1.Ready, v1alpha1.ReasonReady, "All components are ready", metav1.ConditionTrue)
+	mgr.ForceStatusUpdate()
+	if err := mgr.ApplyStatus(context.Background(), obj, getStatus); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fakeClient.StatusUpdateWithRetryCallCount() != 2 {
+		t.Errorf("Expected the forced status update, got %d updates", fakeClient.StatusUpdateWithRetryCallCount())
+	}
+}
+
 func TestCheckStatefulSetHealth(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -821,3 +861,253 @@ func TestGetStatefulSetStatusMessage_AllScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDryRunStatus(t *testing.T) {
+	t.Run("dry run publishes planned changes", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		dryRun := customClient.NewDryRunClient(fakeClient, nil)
+		_ = dryRun.Create(context.Background(), &corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: "ns"},
+		})
+		_ = dryRun.Delete(context.Background(), &storagev1.CSIDriver{
+			TypeMeta:   metav1.TypeMeta{Kind: "CSIDriver"},
+			ObjectMeta: metav1.ObjectMeta{Name: "csi.spiffe.io"},
+		})
+		_ = dryRun.DeleteAllOf(context.Background(), &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{Kind: "ConfigMap"}},
+			client.InNamespace("ns"), client.MatchingLabels{"app": "spire"})
+
+		recorder := record.NewFakeRecorder(10)
+		obj := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		mgr := NewManager(fakeClient)
+		mgr.SetDryRunStatus(recorder, obj, nil, dryRun)
+
+		cond, ok := mgr.conditions[utils.DryRunModeStatusType]
+		if !ok || cond.Status != metav1.ConditionTrue || cond.Reason != utils.DryRunModeEnabled {
+			t.Fatalf("Expected DryRunMode condition to be True, got %+v", cond)
+		}
+		if !strings.Contains(cond.Message, "3 change(s)") {
+			t.Errorf("Expected the condition message to count the planned changes, got %q", cond.Message)
+		}
+		if len(recorder.Events) != 3 {
+			t.Fatalf("Expected 3 events, got %d", len(recorder.Events))
+		}
+		if event := <-recorder.Events; event != "Normal DryRunPlannedChange Would create ServiceAccount ns/spire-agent" {
+			t.Errorf("Unexpected event %q", event)
+		}
+		if event := <-recorder.Events; event != "Normal DryRunPlannedChange Would delete CSIDriver csi.spiffe.io" {
+			t.Errorf("Unexpected event %q", event)
+		}
+		if event := <-recorder.Events; event != "Normal DryRunPlannedChange Would delete ConfigMap ns/app=spire" {
+			t.Errorf("Unexpected event %q", event)
+		}
+
+		if err := mgr.ApplyStatus(context.Background(), obj, func() *v1alpha1.ConditionalStatus {
+			return &obj.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(obj.Status.PlannedChanges) != 3 || obj.Status.PlannedChanges[0].Action != customClient.PlannedActionCreate {
+			t.Errorf("Unexpected planned changes %+v", obj.Status.PlannedChanges)
+		}
+	})
+
+	t.Run("leaving dry run clears planned changes", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		obj := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		obj.Status.PlannedChanges = []v1alpha1.PlannedChange{{Action: "Create", Kind: "ServiceAccount", Name: "spire-agent"}}
+		obj.Status.Conditions = []metav1.Condition{{Type: utils.DryRunModeStatusType, Status: metav1.ConditionTrue}}
+
+		mgr := NewManager(fakeClient)
+		mgr.SetDryRunStatus(record.NewFakeRecorder(10), obj, obj.Status.Conditions, nil)
+
+		cond, ok := mgr.conditions[utils.DryRunModeStatusType]
+		if !ok || cond.Status != metav1.ConditionFalse || cond.Reason != utils.DryRunModeDisabled {
+			t.Fatalf("Expected DryRunMode condition to be reset, got %+v", cond)
+		}
+		if err := mgr.ApplyStatus(context.Background(), obj, func() *v1alpha1.ConditionalStatus {
+			return &obj.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if obj.Status.PlannedChanges != nil {
+			t.Errorf("Expected planned changes to be cleared, got %+v", obj.Status.PlannedChanges)
+		}
+		if ready := mgr.conditions[v1alpha1.Ready]; ready.Status != metav1.ConditionTrue {
+			t.Errorf("Expected DryRunMode=False not to affect Ready, got %+v", ready)
+		}
+	})
+}
+
+func TestSetPausedStatus(t *testing.T) {
+	t.Run("keeps the last Ready condition", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		obj := &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		obj.Status.Conditions = []metav1.Condition{{Type: v1alpha1.Ready, Status: metav1.ConditionTrue, Reason: v1alpha1.ReasonReady, Message: "All components are ready"}}
+
+		if err := SetPausedStatus(context.Background(), fakeClient, obj, func() *v1alpha1.ConditionalStatus {
+			return &obj.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		paused := apimeta.FindStatusCondition(obj.Status.Conditions, utils.PausedStatusType)
+		if paused == nil || paused.Status != metav1.ConditionTrue || paused.Reason != utils.ReconciliationPaused {
+			t.Errorf("Expected Paused=True, got %+v", paused)
+		}
+		ready := apimeta.FindStatusCondition(obj.Status.Conditions, v1alpha1.Ready)
+		if ready == nil || ready.Status != metav1.ConditionTrue || ready.Reason != v1alpha1.ReasonReady {
+			t.Errorf("Expected Ready to be kept, got %+v", ready)
+		}
+	})
+
+	t.Run("reports not ready when never reconciled", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		obj := &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+		if err := SetPausedStatus(context.Background(), fakeClient, obj, func() *v1alpha1.ConditionalStatus {
+			return &obj.Status.ConditionalStatus
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		ready := apimeta.FindStatusCondition(obj.Status.Conditions, v1alpha1.Ready)
+		if ready == nil || ready.Status != metav1.ConditionFalse {
+			t.Errorf("Expected Ready=False, got %+v", ready)
+		}
+	})
+
+	t.Run("resuming resets the Paused condition", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		obj := &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		obj.Status.Conditions = []metav1.Condition{{Type: utils.PausedStatusType, Status: metav1.ConditionTrue, Reason: utils.ReconciliationPaused}}
+
+		SetInitialReconciliationStatus(context.Background(), fakeClient, obj, func() *v1alpha1.ConditionalStatus {
+			return &obj.Status.ConditionalStatus
+		}, "SpireServer")
+
+		paused := apimeta.FindStatusCondition(obj.Status.Conditions, utils.PausedStatusType)
+		if paused == nil || paused.Status != metav1.ConditionFalse || paused.Reason != utils.ReconciliationResumed {
+			t.Errorf("Expected Paused=False after resuming, got %+v", paused)
+		}
+	})
+}
+
+func TestSetManagementStateCondition(t *testing.T) {
+	for _, state := range []v1alpha1.ManagementState{v1alpha1.ManagementStateUnmanaged, v1alpha1.ManagementStateRemoved} {
+		t.Run(string(state), func(t *testing.T) {
+			mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+			mgr.SetManagementStateCondition(state, "SpireServer", nil)
+
+			condition := mgr.conditions[utils.ManagementStateStatusType]
+			if condition.Status != metav1.ConditionFalse || condition.Reason != string(state) {
+				t.Errorf("Expected ManagementState=False with reason %s, got %+v", state, condition)
+			}
+			if ready := mgr.conditions[v1alpha1.Ready]; ready.Status != metav1.ConditionFalse || ready.Reason != string(state) {
+				t.Errorf("Expected Ready=False with reason %s, got %+v", state, ready)
+			}
+		})
+	}
+
+	t.Run("Managed is only reported after another state", func(t *testing.T) {
+		mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+		mgr.SetManagementStateCondition(v1alpha1.ManagementStateManaged, "SpireServer", nil)
+		if len(mgr.conditions) != 0 {
+			t.Errorf("Expected no condition, got %+v", mgr.conditions)
+		}
+
+		mgr.SetManagementStateCondition(v1alpha1.ManagementStateManaged, "SpireServer", []metav1.Condition{
+			{Type: utils.ManagementStateStatusType, Status: metav1.ConditionFalse, Reason: string(v1alpha1.ManagementStateUnmanaged)},
+		})
+		condition := mgr.conditions[utils.ManagementStateStatusType]
+		if condition.Status != metav1.ConditionTrue || condition.Reason != string(v1alpha1.ManagementStateManaged) {
+			t.Errorf("Expected ManagementState=True, got %+v", condition)
+		}
+		if _, ok := mgr.conditions[v1alpha1.Ready]; ok {
+			t.Error("Expected Ready to be left to the reconciliation")
+		}
+	})
+}
+
+func TestSetExcludedResourcesStatus(t *testing.T) {
+	t.Run("reports the skipped writes", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		fakeClient.CreateReturns(kerrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, "spire-server"))
+		exclusion := customClient.NewExclusionClient(fakeClient, nil, []v1alpha1.ResourceExclusion{
+			{Kind: "ConfigMap", Name: "spire-server", Policy: v1alpha1.ResourceExclusionCreateOnly},
+			{Kind: "StatefulSet", Policy: v1alpha1.ResourceExclusionIgnore},
+		})
+		ctx := context.Background()
+
+		configMap := &corev1.ConfigMap{TypeMeta: metav1.TypeMeta{Kind: "ConfigMap"}, ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "spire-server"}}
+		statefulSet := &appsv1.StatefulSet{TypeMeta: metav1.TypeMeta{Kind: "StatefulSet"}, ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "spire-server"}}
+		managed := &corev1.Service{TypeMeta: metav1.TypeMeta{Kind: "Service"}, ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "spire-server"}}
+		if err := exclusion.Create(ctx, configMap); err != nil {
+			t.Fatalf("Expected an existing CreateOnly resource not to fail the create, got %v", err)
+		}
+		_ = exclusion.Update(ctx, configMap)
+		_ = exclusion.Create(ctx, statefulSet)
+		_ = exclusion.Update(ctx, managed)
+
+		if fakeClient.CreateCallCount() != 1 || fakeClient.UpdateCallCount() != 1 {
+			t.Errorf("Expected the CreateOnly create and the managed update to be passed through, got %d creates and %d updates",
+				fakeClient.CreateCallCount(), fakeClient.UpdateCallCount())
+		}
+
+		mgr := NewManager(fakeClient)
+		mgr.SetExcludedResourcesStatus(nil, exclusion)
+		condition := mgr.conditions[utils.ExcludedResourcesStatusType]
+		if condition.Status != metav1.ConditionTrue || !strings.Contains(condition.Message, "2 change(s)") ||
+			!strings.Contains(condition.Message, "StatefulSet ns/spire-server") {
+			t.Errorf("Expected the skipped writes to be reported, got %+v", condition)
+		}
+	})
+
+	t.Run("resets the condition once no resource is excluded", func(t *testing.T) {
+		mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+		mgr.SetExcludedResourcesStatus(nil, nil)
+		if len(mgr.conditions) != 0 {
+			t.Errorf("Expected no condition, got %+v", mgr.conditions)
+		}
+
+		mgr.SetExcludedResourcesStatus([]metav1.Condition{
+			{Type: utils.ExcludedResourcesStatusType, Status: metav1.ConditionTrue, Reason: utils.ResourcesExcluded},
+		}, nil)
+		if condition := mgr.conditions[utils.ExcludedResourcesStatusType]; condition.Status != metav1.ConditionFalse {
+			t.Errorf("Expected ExcludedResources=False, got %+v", condition)
+		}
+		mgr.SetReadyCondition()
+		if ready := mgr.conditions[v1alpha1.Ready]; ready.Status != metav1.ConditionTrue {
+			t.Errorf("Expected ExcludedResources=False not to affect Ready, got %+v", ready)
+		}
+	})
+}
+
+func TestSetDegradedCondition(t *testing.T) {
+	failure := errors.New("admission webhook denied the request")
+
+	mgr := NewManager(&fakes.FakeCustomCtrlClient{})
+	mgr.SetDegradedCondition(nil, utils.DefaultDegradedFailureThreshold-1, failure)
+	if len(mgr.conditions) != 0 {
+		t.Errorf("Expected no condition below the threshold, got %+v", mgr.conditions)
+	}
+
+	mgr.SetDegradedCondition(nil, utils.DefaultDegradedFailureThreshold, failure)
+	condition := mgr.conditions[v1alpha1.Degraded]
+	if condition.Status != metav1.ConditionTrue || condition.Reason != v1alpha1.ReasonFailed ||
+		!strings.Contains(condition.Message, failure.Error()) {
+		t.Errorf("Expected Degraded=True with the error, got %+v", condition)
+	}
+
+	mgr = NewManager(&fakes.FakeCustomCtrlClient{})
+	mgr.SetDegradedCondition(nil, 0, nil)
+	if len(mgr.conditions) != 0 {
+		t.Errorf("Expected no condition without a previous failure, got %+v", mgr.conditions)
+	}
+	mgr.SetDegradedCondition([]metav1.Condition{
+		{Type: v1alpha1.Degraded, Status: metav1.ConditionTrue, Reason: v1alpha1.ReasonFailed},
+	}, 0, nil)
+	if condition := mgr.conditions[v1alpha1.Degraded]; condition.Status != metav1.ConditionFalse {
+		t.Errorf("Expected Degraded=False after a success, got %+v", condition)
+	}
+}
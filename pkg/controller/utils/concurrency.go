@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultMaxConcurrentReconciles is the number of concurrent reconciles of each controller when
+	// the operator --max-concurrent-reconciles flag is not set
+	DefaultMaxConcurrentReconciles = 1
+
+	// MaxConcurrentReconcilesEnvName is the environment variable holding the per controller overrides
+	// of the concurrent reconciles, used when the --controller-max-concurrent-reconciles flag is not set.
+	// It lets the overrides be set from the config of the OLM Subscription.
+	MaxConcurrentReconcilesEnvName = "MAX_CONCURRENT_RECONCILES"
+)
+
+// controllerShortNames maps the names accepted in the concurrent reconciles overrides to the controller names
+var controllerShortNames = map[string]string{
+	"zero-trust-workload-identity-manager": ZeroTrustWorkloadIdentityManagerControllerName,
+	"spire-server":                         ZeroTrustWorkloadIdentityManagerSpireServerControllerName,
+	"spire-agent":                          ZeroTrustWorkloadIdentityManagerSpireAgentControllerName,
+	"spiffe-csi-driver":                    ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName,
+	"spire-oidc-discovery-provider":        ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName,
+}
+
+// maxConcurrentReconciles holds the concurrent reconciles of each controller, keyed by controller name.
+// The reconcilers only keep synchronized failure counts between reconciles and every request is keyed on the singleton
+// cluster object, which the workqueue never hands to two workers at once, so any value is safe.
+var maxConcurrentReconciles sync.Map
+
+// SetMaxConcurrentReconciles sets the number of concurrent reconciles of a controller
+func SetMaxConcurrentReconciles(controllerName string, count int) {
+	maxConcurrentReconciles.Store(controllerName, count)
+}
+
+// MaxConcurrentReconciles returns the number of concurrent reconciles of a controller
+func MaxConcurrentReconciles(controllerName string) int {
+	if count, ok := maxConcurrentReconciles.Load(controllerName); ok {
+		return count.(int)
+	}
+	return DefaultMaxConcurrentReconciles
+}
+
+// ParseMaxConcurrentReconciles returns the concurrent reconciles of every controller, keyed by controller
+// name. Each controller gets defaultCount unless overridden in overrides, a comma separated list of
+// <controller>=<count> pairs such as spire-agent=2,spire-server=4.
+func ParseMaxConcurrentReconciles(defaultCount int, overrides string) (map[string]int, error) {
+	if defaultCount < 1 {
+		return nil, fmt.Errorf("max concurrent reconciles must be at least 1, got %d", defaultCount)
+	}
+
+	counts := make(map[string]int, len(controllerShortNames))
+	for _, controllerName := range controllerShortNames {
+		counts[controllerName] = defaultCount
+	}
+
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+		name, value, found := strings.Cut(override, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid max concurrent reconciles override %q, expected <controller>=<count>", override)
+		}
+		controllerName, ok := controllerShortNames[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown controller %q in max concurrent reconciles override, valid controllers are %s",
+				strings.TrimSpace(name), strings.Join(supportedControllerShortNames(), ", "))
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid max concurrent reconciles override %q, the count must be an integer of at least 1", override)
+		}
+		counts[controllerName] = count
+	}
+
+	return counts, nil
+}
+
+// supportedControllerShortNames returns the sorted names accepted in the concurrent reconciles overrides
+func supportedControllerShortNames() []string {
+	names := make([]string, 0, len(controllerShortNames))
+	for name := range controllerShortNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
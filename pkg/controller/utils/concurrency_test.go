@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMaxConcurrentReconciles(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultCount int
+		overrides    string
+		expected     map[string]int
+		expectedErr  string
+	}{
+		{
+			name:         "default applies to every controller",
+			defaultCount: 2,
+			expected: map[string]int{
+				ZeroTrustWorkloadIdentityManagerControllerName:                           2,
+				ZeroTrustWorkloadIdentityManagerSpireServerControllerName:                2,
+				ZeroTrustWorkloadIdentityManagerSpireAgentControllerName:                 2,
+				ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName:            2,
+				ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName: 2,
+			},
+		},
+		{
+			name:         "overrides take precedence",
+			defaultCount: 1,
+			overrides:    " spire-agent=3, spire-server = 4,",
+			expected: map[string]int{
+				ZeroTrustWorkloadIdentityManagerControllerName:                           1,
+				ZeroTrustWorkloadIdentityManagerSpireServerControllerName:                4,
+				ZeroTrustWorkloadIdentityManagerSpireAgentControllerName:                 3,
+				ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName:            1,
+				ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName: 1,
+			},
+		},
+		{
+			name:         "default below 1",
+			defaultCount: 0,
+			expectedErr:  "must be at least 1",
+		},
+		{
+			name:         "override without count",
+			defaultCount: 1,
+			overrides:    "spire-agent",
+			expectedErr:  "expected <controller>=<count>",
+		},
+		{
+			name:         "unknown controller",
+			defaultCount: 1,
+			overrides:    "spire-proxy=2",
+			expectedErr:  `unknown controller "spire-proxy"`,
+		},
+		{
+			name:         "count below 1",
+			defaultCount: 1,
+			overrides:    "spire-agent=0",
+			expectedErr:  "integer of at least 1",
+		},
+		{
+			name:         "count not an integer",
+			defaultCount: 1,
+			overrides:    "spire-agent=two",
+			expectedErr:  "integer of at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, err := ParseMaxConcurrentReconciles(tt.defaultCount, tt.overrides)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(counts) != len(tt.expected) {
+				t.Fatalf("expected %d controllers, got %d", len(tt.expected), len(counts))
+			}
+			for controllerName, count := range tt.expected {
+				if counts[controllerName] != count {
+					t.Errorf("expected %d concurrent reconciles for %s, got %d", count, controllerName, counts[controllerName])
+				}
+			}
+		})
+	}
+}
+
+func TestMaxConcurrentReconciles(t *testing.T) {
+	const controllerName = "test-controller"
+	t.Cleanup(func() { maxConcurrentReconciles.Delete(controllerName) })
+
+	if got := MaxConcurrentReconciles(controllerName); got != DefaultMaxConcurrentReconciles {
+		t.Errorf("expected the default of %d, got %d", DefaultMaxConcurrentReconciles, got)
+	}
+	SetMaxConcurrentReconciles(controllerName, 5)
+	if got := MaxConcurrentReconciles(controllerName); got != 5 {
+		t.Errorf("expected 5, got %d", got)
+	}
+}
@@ -8,6 +8,8 @@ const (
 	ZeroTrustWorkloadIdentityManagerSpireAgentControllerName                 = "zero-trust-workload-identity-manager-spire-agent-controller"
 	ZeroTrustWorkloadIdentityManagerSpiffeCsiDriverControllerName            = "zero-trust-workload-identity-manager-spiffe-csi-driver-controller"
 	ZeroTrustWorkloadIdentityManagerSpireOIDCDiscoveryProviderControllerName = "zero-trust-workload-identity-manager-spire-oidc-discovery-provider-controller"
+	ZeroTrustWorkloadIdentityManagerNodeLifecycleControllerName              = "zero-trust-workload-identity-manager-node-lifecycle-controller"
+	ZeroTrustWorkloadIdentityManagerEntryPruningControllerName               = "zero-trust-workload-identity-manager-entry-pruning-controller"
 
 	OperatorNamespace = "zero-trust-workload-identity-manager"
 
@@ -33,6 +35,11 @@ const (
 	SpireOIDCExternalCertRoleAssetName                       = "spire-oidc-discovery-provider/spire-oidc-external-cert-role.yaml"
 	SpireOIDCExternalCertRoleBindingAssetName                = "spire-oidc-discovery-provider/spire-oidc-external-cert-role-binding.yaml"
 
+	// SpireServerExecClusterRoleName is installed with the operator and allows the exec subresource of the
+	// SPIRE server pod only. It is bound to the operator in the operand namespace, never cluster wide.
+	SpireServerExecClusterRoleName = "zero-trust-workload-identity-manager-spire-server-exec"
+	SpireServerExecRoleBindingName = "spire-server-exec"
+
 	// Service Accounts
 	SpiffeCsiDriverServiceAccountAssetName            = "spiffe-csi/spiffe-csi-service-account.yaml"
 	SpireAgentServiceAccountAssetName                 = "spire-agent/spire-agent-service-account.yaml"
@@ -48,6 +55,9 @@ const (
 	// Validating Webhook Configurations
 	SpireControllerManagerValidatingWebhookConfigurationAssetName = "spire-controller-manager/spire-controller-manager-webhook-validating-webhook.yaml"
 
+	// DefaultK8sPSATAudience is the audience of the projected service account tokens the SPIRE agents attest with
+	DefaultK8sPSATAudience = "spire-server"
+
 	// Service CA Certificate
 	ServiceCAAnnotationKey     = "service.beta.openshift.io/serving-cert-secret-name"
 	SpireServerServingCertName = "spire-server-serving-cert"
@@ -60,6 +70,24 @@ const (
 	SpireControllerManagerImageEnv     = "RELATED_IMAGE_SPIRE_CONTROLLER_MANAGER"
 	NodeDriverRegistrarImageEnv        = "RELATED_IMAGE_NODE_DRIVER_REGISTRAR"
 	SpiffeCSIInitContainerImageEnv     = "RELATED_IMAGE_SPIFFE_CSI_INIT_CONTAINER"
+	SpiffeHelperImageEnv               = "RELATED_IMAGE_SPIFFE_HELPER"
+	DatastoreBackupImageEnv            = "RELATED_IMAGE_DATASTORE_BACKUP"
+	TornjakBackendImageEnv             = "RELATED_IMAGE_TORNJAK_BACKEND"
+	TornjakFrontendImageEnv            = "RELATED_IMAGE_TORNJAK_FRONTEND"
+	OAuthProxyImageEnv                 = "RELATED_IMAGE_OAUTH_PROXY"
+
+	// Architectures supported by the operand images, as a comma-separated list
+	OperandImageArchitecturesEnv = "OPERAND_IMAGE_ARCHITECTURES"
+
+	// FIPS Image Reference, used instead of the default images when FIPS mode is enabled
+	SpireServerFIPSImageEnv                = "RELATED_IMAGE_SPIRE_SERVER_FIPS"
+	SpireAgentFIPSImageEnv                 = "RELATED_IMAGE_SPIRE_AGENT_FIPS"
+	SpiffeCSIDriverFIPSImageEnv            = "RELATED_IMAGE_SPIFFE_CSI_DRIVER_FIPS"
+	SpireOIDCDiscoveryProviderFIPSImageEnv = "RELATED_IMAGE_SPIRE_OIDC_DISCOVERY_PROVIDER_FIPS"
+	SpireControllerManagerFIPSImageEnv     = "RELATED_IMAGE_SPIRE_CONTROLLER_MANAGER_FIPS"
+	NodeDriverRegistrarFIPSImageEnv        = "RELATED_IMAGE_NODE_DRIVER_REGISTRAR_FIPS"
+	SpiffeHelperFIPSImageEnv               = "RELATED_IMAGE_SPIFFE_HELPER_FIPS"
+	SpiffeCSIInitContainerFIPSImageEnv     = "RELATED_IMAGE_SPIFFE_CSI_INIT_CONTAINER_FIPS"
 
 	// Resource Kinds - used for validation and logging
 	ResourceKindSpireServer                = "SpireServer"
@@ -71,18 +99,41 @@ const (
 	ConditionTypeConfigurationValid = "ConfigurationValid"
 
 	// Validation Condition Reasons
-	ConditionReasonConfigurationValid  = "ConfigurationValid"
-	ConditionReasonInvalidAffinity     = "InvalidAffinity"
-	ConditionReasonInvalidTolerations  = "InvalidTolerations"
-	ConditionReasonInvalidNodeSelector = "InvalidNodeSelector"
-	ConditionReasonInvalidResources    = "InvalidResources"
-	ConditionReasonInvalidLabels       = "InvalidLabels"
+	ConditionReasonConfigurationValid               = "ConfigurationValid"
+	ConditionReasonInvalidAffinity                  = "InvalidAffinity"
+	ConditionReasonInvalidTolerations               = "InvalidTolerations"
+	ConditionReasonInvalidNodeSelector              = "InvalidNodeSelector"
+	ConditionReasonInvalidResources                 = "InvalidResources"
+	ConditionReasonInvalidLabels                    = "InvalidLabels"
+	ConditionReasonInvalidExtraVolumes              = "InvalidExtraVolumes"
+	ConditionReasonInvalidExtraContainers           = "InvalidExtraContainers"
+	ConditionReasonInvalidEnv                       = "InvalidEnv"
+	ConditionReasonInvalidSecurityContext           = "InvalidSecurityContext"
+	ConditionReasonInvalidImagePullSecrets          = "InvalidImagePullSecrets"
+	ConditionReasonInvalidServiceAccountAnnotations = "InvalidServiceAccountAnnotations"
+	ConditionReasonInvalidNodePlatform              = "InvalidNodePlatform"
+	ConditionReasonInvalidTopologySpreadConstraints = "InvalidTopologySpreadConstraints"
+	ConditionReasonInvalidVersion                   = "InvalidVersion"
+	ConditionReasonInvalidTopology                  = "InvalidTopology"
+	ConditionReasonFIPSImageNotSet                  = "FIPSImageNotSet"
+
+	// ConditionReasonNotDeployedInTopology is the reason of the Ready condition of the operands left to the
+	// operator of the other cluster of the topology
+	ConditionReasonNotDeployedInTopology = "NotDeployedInTopology"
 
 	// Workload Attestor Verification Types
 	WorkloadAttestorVerificationTypeSkip     = "skip"
 	WorkloadAttestorVerificationTypeAuto     = "auto"
 	WorkloadAttestorVerificationTypeHostCert = "hostCert"
 
+	// SPIRE Server Persistence Types
+	PersistenceTypePersistentVolumeClaim = "PersistentVolumeClaim"
+	PersistenceTypeEmptyDir              = "EmptyDir"
+
+	// spire-controller-manager identity defaults
+	DefaultSpireControllerManagerClassName = "zero-trust-workload-identity-manager-spire"
+	DefaultSPIFFEIDTemplate                = "spiffe://{{ .TrustDomain }}/ns/{{ .PodMeta.Namespace }}/sa/{{ .PodSpec.ServiceAccountName }}"
+
 	// Default Kubelet CA Paths (for OpenShift clusters)
 	// These are used as defaults for 'auto' mode when no explicit paths are provided.
 	DefaultKubeletCABasePath = "/etc/kubernetes"
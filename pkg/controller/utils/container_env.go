@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/core"
+	k8scorev1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	corevalidation "k8s.io/kubernetes/pkg/apis/core/validation"
+)
+
+// Maximum number of environment variables and environment sources of an operand, enforced here as the
+// CRD schema leaves them unvalidated
+const (
+	maxContainerEnv     = 50
+	maxContainerEnvFrom = 10
+)
+
+// ValidateContainerEnv validates the environment variables and environment sources of an operand
+// using Kubernetes validation functions.
+func ValidateContainerEnv(env []corev1.EnvVar, envFrom []corev1.EnvFromSource) error {
+	if len(env) == 0 && len(envFrom) == 0 {
+		return nil
+	}
+	if len(env) > maxContainerEnv {
+		return fmt.Errorf("env: must have at most %d items", maxContainerEnv)
+	}
+	if len(envFrom) > maxContainerEnvFrom {
+		return fmt.Errorf("envFrom: must have at most %d items", maxContainerEnvFrom)
+	}
+
+	internalEnv := make([]core.EnvVar, len(env))
+	for i := range env {
+		if err := k8scorev1.Convert_v1_EnvVar_To_core_EnvVar(&env[i], &internalEnv[i], nil); err != nil {
+			return fmt.Errorf("env[%d]: %w", i, err)
+		}
+	}
+	internalEnvFrom := make([]core.EnvFromSource, len(envFrom))
+	for i := range envFrom {
+		if err := k8scorev1.Convert_v1_EnvFromSource_To_core_EnvFromSource(&envFrom[i], &internalEnvFrom[i], nil); err != nil {
+			return fmt.Errorf("envFrom[%d]: %w", i, err)
+		}
+	}
+
+	opts := corevalidation.PodValidationOptions{}
+	errs := corevalidation.ValidateEnv(internalEnv, field.NewPath("env"), opts)
+	errs = append(errs, corevalidation.ValidateEnvFrom(internalEnvFrom, field.NewPath("envFrom"), opts)...)
+	return fieldErrorListToError(errs)
+}
+
+// AddContainerEnv sets the environment variables and environment sources of an operand in its
+// container named containerName. A variable replaces the variable of the same name set by the
+// operator, so that e.g. the NO_PROXY exceptions can be changed.
+func AddContainerEnv(podSpec *corev1.PodSpec, containerName string, env []corev1.EnvVar, envFrom []corev1.EnvFromSource) error {
+	if len(env) == 0 && len(envFrom) == 0 {
+		return nil
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != containerName {
+			continue
+		}
+		for _, envVar := range env {
+			replaced := false
+			for j := range container.Env {
+				if container.Env[j].Name == envVar.Name {
+					container.Env[j] = envVar
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				container.Env = append(container.Env, envVar)
+			}
+		}
+		container.EnvFrom = append(container.EnvFrom, envFrom...)
+		return nil
+	}
+	return fmt.Errorf("container %q not found", containerName)
+}
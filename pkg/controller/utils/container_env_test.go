@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateContainerEnv(t *testing.T) {
+	credentials := corev1.EnvVar{
+		Name: "AWS_ACCESS_KEY_ID",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "kms"}, Key: "access-key-id"},
+		},
+	}
+	settings := corev1.EnvFromSource{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "spire-env"}}}
+
+	tests := []struct {
+		name        string
+		env         []corev1.EnvVar
+		envFrom     []corev1.EnvFromSource
+		expectedErr string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name:    "valid variables and sources",
+			env:     []corev1.EnvVar{credentials, {Name: "NO_PROXY", Value: ".example.com"}},
+			envFrom: []corev1.EnvFromSource{settings},
+		},
+		{
+			name:        "variable without name",
+			env:         []corev1.EnvVar{{Value: "true"}},
+			expectedErr: "env[0].name",
+		},
+		{
+			name: "value and valueFrom",
+			env: []corev1.EnvVar{{
+				Name:      "AWS_ACCESS_KEY_ID",
+				Value:     "key",
+				ValueFrom: credentials.ValueFrom,
+			}},
+			expectedErr: "env[0].valueFrom",
+		},
+		{
+			name:        "source without Secret or ConfigMap",
+			envFrom:     []corev1.EnvFromSource{{Prefix: "SPIRE_"}},
+			expectedErr: "configMapRef",
+		},
+		{
+			name:        "too many sources",
+			envFrom:     slices.Repeat([]corev1.EnvFromSource{settings}, 11),
+			expectedErr: "envFrom: must have at most 10 items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContainerEnv(tt.env, tt.envFrom)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestAddContainerEnv(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "spire-server", Env: []corev1.EnvVar{{Name: "PATH", Value: "/opt/spire/bin:/bin"}, {Name: "NO_PROXY", Value: "spire-server"}}},
+				{Name: "spire-controller-manager"},
+			},
+		}
+	}
+	source := corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "kms"}}}
+
+	t.Run("sets the variables in the container", func(t *testing.T) {
+		podSpec := newPodSpec()
+		env := []corev1.EnvVar{{Name: "NO_PROXY", Value: "spire-server,.example.com"}, {Name: "SPIRE_DEBUG", Value: "true"}}
+		if err := AddContainerEnv(podSpec, "spire-server", env, []corev1.EnvFromSource{source}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		expected := []corev1.EnvVar{
+			{Name: "PATH", Value: "/opt/spire/bin:/bin"},
+			{Name: "NO_PROXY", Value: "spire-server,.example.com"},
+			{Name: "SPIRE_DEBUG", Value: "true"},
+		}
+		if got := podSpec.Containers[0].Env; len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] || got[2] != expected[2] {
+			t.Errorf("Expected env %v, got %v", expected, got)
+		}
+		if len(podSpec.Containers[0].EnvFrom) != 1 {
+			t.Errorf("Expected the env source in spire-server, got %v", podSpec.Containers[0].EnvFrom)
+		}
+		if len(podSpec.Containers[1].Env) != 0 || len(podSpec.Containers[1].EnvFrom) != 0 {
+			t.Errorf("Expected the other containers to be left unchanged, got %v", podSpec.Containers[1])
+		}
+	})
+
+	t.Run("unknown container", func(t *testing.T) {
+		err := AddContainerEnv(newPodSpec(), "spire-agent", []corev1.EnvVar{{Name: "SPIRE_DEBUG", Value: "true"}}, nil)
+		if err == nil || !strings.Contains(err.Error(), "spire-agent") {
+			t.Errorf("Expected a container not found error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// FindResourceExclusion returns the exclusion matching the generated resource of the given kind and name,
+// or nil when the resource is managed by the operator. An exclusion without name matches every resource
+// of its kind, and the first matching exclusion wins.
+func FindResourceExclusion(exclusions []v1alpha1.ResourceExclusion, kind, name string) *v1alpha1.ResourceExclusion {
+	for i := range exclusions {
+		exclusion := &exclusions[i]
+		if exclusion.Kind == kind && (exclusion.Name == "" || exclusion.Name == name) {
+			return exclusion
+		}
+	}
+	return nil
+}
+
+// IsCreateOnlyExclusion returns whether the excluded resource is still created when missing
+func IsCreateOnlyExclusion(exclusion *v1alpha1.ResourceExclusion) bool {
+	return exclusion != nil && exclusion.Policy == v1alpha1.ResourceExclusionCreateOnly
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestFindResourceExclusion(t *testing.T) {
+	exclusions := []v1alpha1.ResourceExclusion{
+		{Kind: "ValidatingWebhookConfiguration", Name: "spire-controller-manager-webhook", Policy: v1alpha1.ResourceExclusionCreateOnly},
+		{Kind: "ClusterRole"},
+	}
+
+	if exclusion := FindResourceExclusion(exclusions, "ValidatingWebhookConfiguration", "spire-controller-manager-webhook"); !IsCreateOnlyExclusion(exclusion) {
+		t.Errorf("Expected the named CreateOnly exclusion, got %+v", exclusion)
+	}
+	if exclusion := FindResourceExclusion(exclusions, "ValidatingWebhookConfiguration", "other"); exclusion != nil {
+		t.Errorf("Expected a resource of another name not to be excluded, got %+v", exclusion)
+	}
+	if exclusion := FindResourceExclusion(exclusions, "ClusterRole", "spire-agent"); exclusion == nil || IsCreateOnlyExclusion(exclusion) {
+		t.Errorf("Expected every ClusterRole to be ignored, got %+v", exclusion)
+	}
+	if exclusion := FindResourceExclusion(exclusions, "ClusterRoleBinding", "spire-agent"); exclusion != nil {
+		t.Errorf("Expected another kind not to be excluded, got %+v", exclusion)
+	}
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DecodeExtraConfig decodes a free-form extraConfig block. It returns nil when extra is unset.
+func DecodeExtraConfig(extra *apiextensionsv1.JSON) (map[string]interface{}, error) {
+	if extra == nil || len(extra.Raw) == 0 {
+		return nil, nil
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(extra.Raw, &config); err != nil {
+		return nil, fmt.Errorf("extraConfig must be an object: %w", err)
+	}
+	return config, nil
+}
+
+// MergeExtraConfig deep-merges extra into the rendered operand config. Keys already set in
+// config are owned by the operator and win; nested objects are merged key by key, while any
+// other value, including lists, is only added when the key is missing from config.
+func MergeExtraConfig(config, extra map[string]interface{}) {
+	for key, extraValue := range extra {
+		value, ok := config[key]
+		if !ok {
+			config[key] = extraValue
+			continue
+		}
+		nested, isMap := value.(map[string]interface{})
+		extraNested, extraIsMap := extraValue.(map[string]interface{})
+		if isMap && extraIsMap {
+			MergeExtraConfig(nested, extraNested)
+		}
+	}
+}
+
+// ValidateExtraConfigGlobals rejects an extraConfig that conflicts with the settings derived from the
+// ZeroTrustWorkloadIdentityManager, the single source of the trust domain, the cluster name and the
+// operand namespace. globals maps the dot-separated path of each such setting in the operand config
+// to the value it is rendered with; extraConfig may only repeat that value.
+func ValidateExtraConfigGlobals(extra map[string]interface{}, globals map[string]string) error {
+	paths := make([]string, 0, len(globals))
+	for path := range globals {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		value, ok := lookupExtraConfig(extra, strings.Split(path, "."))
+		if !ok {
+			continue
+		}
+		if value != globals[path] {
+			return fmt.Errorf("extraConfig sets %s to %v, which conflicts with %q set from the ZeroTrustWorkloadIdentityManager", path, value, globals[path])
+		}
+	}
+	return nil
+}
+
+// lookupExtraConfig returns the value at keys in the nested extraConfig objects
+func lookupExtraConfig(extra map[string]interface{}, keys []string) (interface{}, bool) {
+	var value interface{} = extra
+	for _, key := range keys {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = nested[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// experimentalKeyPattern matches the keys of the experimental block of the SPIRE configs
+var experimentalKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidateExperimentalConfig rejects the experimental settings whose keys can't be SPIRE config keys
+func ValidateExperimentalConfig(experimental map[string]string) error {
+	keys := make([]string, 0, len(experimental))
+	for key := range experimental {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !experimentalKeyPattern.MatchString(key) {
+			return fmt.Errorf("experimental key %q must consist of lower case letters, digits and underscores, starting with a letter", key)
+		}
+	}
+	return nil
+}
+
+// ExperimentalConfig returns the experimental block of a SPIRE config rendered from the experimental
+// settings, or nil when there are none. SPIRE only decodes the booleans from boolean values, while it
+// parses the numbers and durations from strings, so "true" and "false" are rendered as booleans.
+func ExperimentalConfig(experimental map[string]string) map[string]interface{} {
+	if len(experimental) == 0 {
+		return nil
+	}
+	block := make(map[string]interface{}, len(experimental))
+	for key, value := range experimental {
+		switch value {
+		case "true":
+			block[key] = true
+		case "false":
+			block[key] = false
+		default:
+			block[key] = value
+		}
+	}
+	return block
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestDecodeExtraConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		extra       *apiextensionsv1.JSON
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:     "unset",
+			extra:    nil,
+			expected: nil,
+		},
+		{
+			name:     "object",
+			extra:    &apiextensionsv1.JSON{Raw: []byte(`{"server":{"audit_log_enabled":true}}`)},
+			expected: map[string]interface{}{"server": map[string]interface{}{"audit_log_enabled": true}},
+		},
+		{
+			name:        "not an object",
+			extra:       &apiextensionsv1.JSON{Raw: []byte(`["server"]`)},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeExtraConfig(tt.extra)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeExtraConfig(t *testing.T) {
+	config := map[string]interface{}{
+		"server": map[string]interface{}{
+			"trust_domain": "example.org",
+		},
+		"plugins": map[string]interface{}{
+			"KeyManager": []map[string]interface{}{{"disk": map[string]interface{}{}}},
+		},
+	}
+	extra := map[string]interface{}{
+		"server": map[string]interface{}{
+			"trust_domain":      "other.org",
+			"audit_log_enabled": true,
+		},
+		"plugins": map[string]interface{}{
+			"KeyManager":        []interface{}{map[string]interface{}{"memory": map[string]interface{}{}}},
+			"UpstreamAuthority": []interface{}{map[string]interface{}{"disk": map[string]interface{}{}}},
+		},
+		"telemetry": map[string]interface{}{"Prometheus": map[string]interface{}{"port": "9988"}},
+	}
+
+	MergeExtraConfig(config, extra)
+
+	expected := map[string]interface{}{
+		"server": map[string]interface{}{
+			"trust_domain":      "example.org",
+			"audit_log_enabled": true,
+		},
+		"plugins": map[string]interface{}{
+			"KeyManager":        []map[string]interface{}{{"disk": map[string]interface{}{}}},
+			"UpstreamAuthority": []interface{}{map[string]interface{}{"disk": map[string]interface{}{}}},
+		},
+		"telemetry": map[string]interface{}{"Prometheus": map[string]interface{}{"port": "9988"}},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected %v, got %v", expected, config)
+	}
+}
+
+func TestValidateExtraConfigGlobals(t *testing.T) {
+	globals := map[string]string{
+		"server.trust_domain": "example.org",
+	}
+
+	tests := []struct {
+		name        string
+		extra       map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:  "unset",
+			extra: nil,
+		},
+		{
+			name:  "other settings",
+			extra: map[string]interface{}{"server": map[string]interface{}{"ca_ttl": "48h"}},
+		},
+		{
+			name:  "same trust domain",
+			extra: map[string]interface{}{"server": map[string]interface{}{"trust_domain": "example.org"}},
+		},
+		{
+			name:        "conflicting trust domain",
+			extra:       map[string]interface{}{"server": map[string]interface{}{"trust_domain": "other.org"}},
+			expectError: true,
+		},
+		{
+			name:        "trust domain of another type",
+			extra:       map[string]interface{}{"server": map[string]interface{}{"trust_domain": map[string]interface{}{}}},
+			expectError: true,
+		},
+		{
+			name:  "parent of another type",
+			extra: map[string]interface{}{"server": "example.org"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtraConfigGlobals(tt.extra, globals)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestExperimentalConfig(t *testing.T) {
+	if got := ExperimentalConfig(nil); got != nil {
+		t.Errorf("Expected no experimental block, got %v", got)
+	}
+	got := ExperimentalConfig(map[string]string{"events_based_cache": "true", "disabled": "false", "cache_reload_interval": "5s", "batch_size": "10"})
+	expected := map[string]interface{}{"events_based_cache": true, "disabled": false, "cache_reload_interval": "5s", "batch_size": "10"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestValidateExperimentalConfig(t *testing.T) {
+	if err := ValidateExperimentalConfig(map[string]string{"events_based_cache": "true", "prune_events_older_than2": "1h"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for _, key := range []string{"EventsBasedCache", "events-based-cache", "_cache", ""} {
+		if err := ValidateExperimentalConfig(map[string]string{key: "true"}); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
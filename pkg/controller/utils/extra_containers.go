@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Maximum number of extra containers and init containers of an operand, enforced here as the CRD schema
+// leaves them unvalidated
+const (
+	maxExtraContainers     = 10
+	maxExtraInitContainers = 10
+)
+
+// ValidateExtraContainers validates the extra containers and init containers of an operand. The complete
+// validation of the containers is left to the API server when the operand workload is applied.
+func ValidateExtraContainers(containers, initContainers []corev1.Container) error {
+	if len(containers) > maxExtraContainers {
+		return fmt.Errorf("extraContainers: must have at most %d items", maxExtraContainers)
+	}
+	if len(initContainers) > maxExtraInitContainers {
+		return fmt.Errorf("extraInitContainers: must have at most %d items", maxExtraInitContainers)
+	}
+	names := map[string]bool{}
+	for _, list := range []struct {
+		field      string
+		containers []corev1.Container
+	}{{"extraContainers", containers}, {"extraInitContainers", initContainers}} {
+		field := list.field
+		for i, container := range list.containers {
+			if errs := validation.IsDNS1123Label(container.Name); len(errs) > 0 {
+				return fmt.Errorf("%s[%d].name: invalid name %q: %s", field, i, container.Name, strings.Join(errs, "; "))
+			}
+			if names[container.Name] {
+				return fmt.Errorf("%s[%d].name: duplicate container name %q", field, i, container.Name)
+			}
+			names[container.Name] = true
+			if container.Image == "" {
+				return fmt.Errorf("%s[%d].image: required", field, i)
+			}
+		}
+	}
+	return nil
+}
+
+// AddExtraContainers appends the extra containers and init containers of an operand to podSpec. It returns
+// an error when a name is already used by the containers generated by the operator.
+func AddExtraContainers(podSpec *corev1.PodSpec, containers, initContainers []corev1.Container) error {
+	names := map[string]bool{}
+	for _, container := range podSpec.Containers {
+		names[container.Name] = true
+	}
+	for _, container := range podSpec.InitContainers {
+		names[container.Name] = true
+	}
+	for _, list := range [][]corev1.Container{containers, initContainers} {
+		for _, container := range list {
+			if names[container.Name] {
+				return fmt.Errorf("extra container %q conflicts with a container of the operator", container.Name)
+			}
+		}
+	}
+
+	podSpec.Containers = append(podSpec.Containers, containers...)
+	podSpec.InitContainers = append(podSpec.InitContainers, initContainers...)
+	return nil
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateExtraContainers(t *testing.T) {
+	proxy := corev1.Container{Name: "egress-proxy", Image: "registry.example.com/proxy:1.0"}
+	init := corev1.Container{Name: "fetch-plugins", Image: "registry.example.com/plugins:1.0"}
+
+	tests := []struct {
+		name           string
+		containers     []corev1.Container
+		initContainers []corev1.Container
+		expectedErr    string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name:           "valid containers and init containers",
+			containers:     []corev1.Container{proxy},
+			initContainers: []corev1.Container{init},
+		},
+		{
+			name:        "invalid name",
+			containers:  []corev1.Container{{Name: "Egress_Proxy", Image: "proxy"}},
+			expectedErr: "extraContainers[0].name",
+		},
+		{
+			name:        "duplicate container names",
+			containers:  []corev1.Container{proxy, proxy},
+			expectedErr: "extraContainers[1].name",
+		},
+		{
+			name:           "init container named as a container",
+			containers:     []corev1.Container{proxy},
+			initContainers: []corev1.Container{{Name: "egress-proxy", Image: "proxy"}},
+			expectedErr:    "extraInitContainers[0].name",
+		},
+		{
+			name:           "missing image",
+			initContainers: []corev1.Container{{Name: "fetch-plugins"}},
+			expectedErr:    "extraInitContainers[0].image",
+		},
+		{
+			name:        "too many containers",
+			containers:  slices.Repeat([]corev1.Container{proxy}, 11),
+			expectedErr: "extraContainers: must have at most 10 items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtraContainers(tt.containers, tt.initContainers)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestAddExtraContainers(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init-spire-agent"}},
+			Containers:     []corev1.Container{{Name: "spire-agent"}},
+		}
+	}
+	proxy := corev1.Container{Name: "egress-proxy", Image: "proxy"}
+	init := corev1.Container{Name: "fetch-plugins", Image: "plugins"}
+
+	t.Run("appends the containers after the operator containers", func(t *testing.T) {
+		podSpec := newPodSpec()
+		if err := AddExtraContainers(podSpec, []corev1.Container{proxy}, []corev1.Container{init}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(podSpec.Containers) != 2 || podSpec.Containers[0].Name != "spire-agent" || podSpec.Containers[1].Name != "egress-proxy" {
+			t.Errorf("Expected the extra container after spire-agent, got %v", podSpec.Containers)
+		}
+		if len(podSpec.InitContainers) != 2 || podSpec.InitContainers[1].Name != "fetch-plugins" {
+			t.Errorf("Expected the extra init container after init-spire-agent, got %v", podSpec.InitContainers)
+		}
+	})
+
+	t.Run("rejects a container name of the operator", func(t *testing.T) {
+		err := AddExtraContainers(newPodSpec(), []corev1.Container{{Name: "spire-agent", Image: "proxy"}}, nil)
+		if err == nil || !strings.Contains(err.Error(), "spire-agent") {
+			t.Errorf("Expected a container name conflict, got: %v", err)
+		}
+	})
+
+	t.Run("rejects an init container name of the operator", func(t *testing.T) {
+		err := AddExtraContainers(newPodSpec(), nil, []corev1.Container{{Name: "init-spire-agent", Image: "init"}})
+		if err == nil || !strings.Contains(err.Error(), "init-spire-agent") {
+			t.Errorf("Expected a container name conflict, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/core"
+	k8scorev1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	corevalidation "k8s.io/kubernetes/pkg/apis/core/validation"
+)
+
+// Maximum number of extra volumes and volume mounts of an operand, enforced here as the CRD schema
+// leaves them unvalidated
+const (
+	maxExtraVolumes      = 20
+	maxExtraVolumeMounts = 20
+)
+
+// ValidateExtraVolumes validates the extra volumes and volume mounts of an operand using Kubernetes
+// validation functions. The extra volume mounts can only mount extra volumes.
+func ValidateExtraVolumes(volumes []corev1.Volume, mounts []corev1.VolumeMount) error {
+	if len(volumes) == 0 && len(mounts) == 0 {
+		return nil
+	}
+	if len(volumes) > maxExtraVolumes {
+		return fmt.Errorf("extraVolumes: must have at most %d items", maxExtraVolumes)
+	}
+	if len(mounts) > maxExtraVolumeMounts {
+		return fmt.Errorf("extraVolumeMounts: must have at most %d items", maxExtraVolumeMounts)
+	}
+
+	internalVolumes := make([]core.Volume, len(volumes))
+	for i := range volumes {
+		if err := k8scorev1.Convert_v1_Volume_To_core_Volume(&volumes[i], &internalVolumes[i], nil); err != nil {
+			return fmt.Errorf("extraVolumes[%d]: %w", i, err)
+		}
+	}
+	internalMounts := make([]core.VolumeMount, len(mounts))
+	for i := range mounts {
+		if err := k8scorev1.Convert_v1_VolumeMount_To_core_VolumeMount(&mounts[i], &internalMounts[i], nil); err != nil {
+			return fmt.Errorf("extraVolumeMounts[%d]: %w", i, err)
+		}
+	}
+
+	opts := corevalidation.PodValidationOptions{}
+	volumeSources, errs := corevalidation.ValidateVolumes(internalVolumes, nil, field.NewPath("extraVolumes"), opts)
+	errs = append(errs, corevalidation.ValidateVolumeMounts(internalMounts, nil, volumeSources, nil, field.NewPath("extraVolumeMounts"), opts)...)
+	return fieldErrorListToError(errs)
+}
+
+// AddExtraVolumes adds the extra volumes of an operand to podSpec, and mounts the extra volume mounts
+// into its container named containerName. It returns an error when a name or a mount path is already
+// used by the volumes and mounts generated by the operator.
+func AddExtraVolumes(podSpec *corev1.PodSpec, containerName string, volumes []corev1.Volume, mounts []corev1.VolumeMount) error {
+	for _, volume := range volumes {
+		for _, existing := range podSpec.Volumes {
+			if existing.Name == volume.Name {
+				return fmt.Errorf("extra volume %q conflicts with a volume of the operator", volume.Name)
+			}
+		}
+	}
+	podSpec.Volumes = append(podSpec.Volumes, volumes...)
+
+	if len(mounts) == 0 {
+		return nil
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != containerName {
+			continue
+		}
+		for _, mount := range mounts {
+			for _, existing := range container.VolumeMounts {
+				if existing.MountPath == mount.MountPath {
+					return fmt.Errorf("extra volume mount path %q conflicts with a volume mount of the operator", mount.MountPath)
+				}
+			}
+		}
+		container.VolumeMounts = append(container.VolumeMounts, mounts...)
+		return nil
+	}
+	return fmt.Errorf("container %q not found", containerName)
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateExtraVolumes(t *testing.T) {
+	caBundle := corev1.Volume{
+		Name: "site-ca",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "site-ca"}},
+		},
+	}
+	plugins := corev1.Volume{
+		Name: "plugins",
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{Path: "/opt/spire/plugins"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		volumes     []corev1.Volume
+		mounts      []corev1.VolumeMount
+		expectedErr string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name:    "valid volumes and mounts",
+			volumes: []corev1.Volume{caBundle, plugins},
+			mounts: []corev1.VolumeMount{
+				{Name: "site-ca", MountPath: "/etc/pki/site", ReadOnly: true},
+				{Name: "plugins", MountPath: "/opt/spire/plugins"},
+			},
+		},
+		{
+			name:        "duplicate volume names",
+			volumes:     []corev1.Volume{caBundle, caBundle},
+			expectedErr: "extraVolumes[1].name",
+		},
+		{
+			name:        "volume without source",
+			volumes:     []corev1.Volume{{Name: "empty"}},
+			expectedErr: "extraVolumes[0]",
+		},
+		{
+			name:        "mount of an unknown volume",
+			volumes:     []corev1.Volume{caBundle},
+			mounts:      []corev1.VolumeMount{{Name: "spire-config", MountPath: "/etc/pki/site"}},
+			expectedErr: "extraVolumeMounts[0].name",
+		},
+		{
+			name:    "duplicate mount paths",
+			volumes: []corev1.Volume{caBundle, plugins},
+			mounts: []corev1.VolumeMount{
+				{Name: "site-ca", MountPath: "/etc/pki/site"},
+				{Name: "plugins", MountPath: "/etc/pki/site"},
+			},
+			expectedErr: "extraVolumeMounts[1].mountPath",
+		},
+		{
+			name:        "subPath traversal",
+			volumes:     []corev1.Volume{caBundle},
+			mounts:      []corev1.VolumeMount{{Name: "site-ca", MountPath: "/etc/pki/site", SubPath: "../other"}},
+			expectedErr: "subPath",
+		},
+		{
+			name:        "too many volumes",
+			volumes:     slices.Repeat([]corev1.Volume{caBundle}, 21),
+			expectedErr: "extraVolumes: must have at most 20 items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtraVolumes(tt.volumes, tt.mounts)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestAddExtraVolumes(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Volumes: []corev1.Volume{{Name: "spire-config"}},
+			Containers: []corev1.Container{
+				{Name: "spire-server", VolumeMounts: []corev1.VolumeMount{{Name: "spire-config", MountPath: "/run/spire/config"}}},
+				{Name: "spire-controller-manager"},
+			},
+		}
+	}
+	volume := corev1.Volume{Name: "site-ca", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	mount := corev1.VolumeMount{Name: "site-ca", MountPath: "/etc/pki/site"}
+
+	t.Run("adds the volumes and mounts them into the container", func(t *testing.T) {
+		podSpec := newPodSpec()
+		if err := AddExtraVolumes(podSpec, "spire-server", []corev1.Volume{volume}, []corev1.VolumeMount{mount}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(podSpec.Volumes) != 2 || podSpec.Volumes[1].Name != "site-ca" {
+			t.Errorf("Expected the extra volume to be added, got %v", podSpec.Volumes)
+		}
+		if mounts := podSpec.Containers[0].VolumeMounts; len(mounts) != 2 || mounts[1] != mount {
+			t.Errorf("Expected the extra volume mount in spire-server, got %v", mounts)
+		}
+		if len(podSpec.Containers[1].VolumeMounts) != 0 {
+			t.Errorf("Expected the other containers to be left unchanged, got %v", podSpec.Containers[1].VolumeMounts)
+		}
+	})
+
+	t.Run("rejects a volume name of the operator", func(t *testing.T) {
+		err := AddExtraVolumes(newPodSpec(), "spire-server", []corev1.Volume{{Name: "spire-config"}}, nil)
+		if err == nil || !strings.Contains(err.Error(), "spire-config") {
+			t.Errorf("Expected a volume name conflict, got: %v", err)
+		}
+	})
+
+	t.Run("rejects a mount path of the operator", func(t *testing.T) {
+		err := AddExtraVolumes(newPodSpec(), "spire-server", []corev1.Volume{volume}, []corev1.VolumeMount{{Name: "site-ca", MountPath: "/run/spire/config"}})
+		if err == nil || !strings.Contains(err.Error(), "/run/spire/config") {
+			t.Errorf("Expected a mount path conflict, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// defaultFeatureGates holds the state of each feature gate when neither the operator --feature-gates
+// flag nor the ZeroTrustWorkloadIdentityManager sets it. Only the baseline capabilities are enabled,
+// new experimental capabilities are added disabled, so that they ship dark until enabled on a cluster.
+var defaultFeatureGates = map[v1alpha1.FeatureGateName]bool{
+	v1alpha1.FeatureGateFederation:            true,
+	v1alpha1.FeatureGateNestedTopology:        false,
+	v1alpha1.FeatureGateSpiffeHelperInjection: false,
+}
+
+// operatorFeatureGates holds the operator wide feature gates set from the --feature-gates flag.
+// It is nil until SetOperatorFeatureGates is called, in which case the defaults apply.
+var operatorFeatureGates atomic.Pointer[map[v1alpha1.FeatureGateName]bool]
+
+// ParseFeatureGates parses a comma separated list of <gate>=<true|false> pairs, such as
+// Federation=false,NestedTopology=true
+func ParseFeatureGates(value string) (map[v1alpha1.FeatureGateName]bool, error) {
+	gates := map[v1alpha1.FeatureGateName]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, state, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid feature gate %q, expected <gate>=<true|false>", pair)
+		}
+		gate := v1alpha1.FeatureGateName(strings.TrimSpace(name))
+		if _, ok := defaultFeatureGates[gate]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q, valid feature gates are %s", gate, strings.Join(featureGateNames(), ", "))
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(state))
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature gate %q, the state must be true or false", pair)
+		}
+		gates[gate] = enabled
+	}
+	return gates, nil
+}
+
+// SetOperatorFeatureGates sets the operator wide feature gates, overriding the defaults
+func SetOperatorFeatureGates(gates map[v1alpha1.FeatureGateName]bool) {
+	operatorFeatureGates.Store(&gates)
+}
+
+// FeatureGateEnabled reports whether a feature gate is enabled. The featureGates of the
+// ZeroTrustWorkloadIdentityManager take precedence over the operator flag, which takes
+// precedence over the default of the gate. ztwim may be nil.
+func FeatureGateEnabled(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, gate v1alpha1.FeatureGateName) bool {
+	if ztwim != nil {
+		for _, featureGate := range ztwim.Spec.FeatureGates {
+			if featureGate.Name == gate {
+				return featureGate.Enabled
+			}
+		}
+	}
+	if gates := operatorFeatureGates.Load(); gates != nil {
+		if enabled, ok := (*gates)[gate]; ok {
+			return enabled
+		}
+	}
+	return defaultFeatureGates[gate]
+}
+
+// FeatureGatesSummary returns the sorted names of the enabled and of the disabled feature gates
+func FeatureGatesSummary(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) (enabled, disabled []string) {
+	for _, name := range featureGateNames() {
+		if FeatureGateEnabled(ztwim, v1alpha1.FeatureGateName(name)) {
+			enabled = append(enabled, name)
+		} else {
+			disabled = append(disabled, name)
+		}
+	}
+	return enabled, disabled
+}
+
+// featureGateNames returns the sorted names of the known feature gates
+func featureGateNames() []string {
+	names := make([]string, 0, len(defaultFeatureGates))
+	for gate := range defaultFeatureGates {
+		names = append(names, string(gate))
+	}
+	sort.Strings(names)
+	return names
+}
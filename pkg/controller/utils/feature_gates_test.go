@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestParseFeatureGates(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    map[v1alpha1.FeatureGateName]bool
+		expectedErr string
+	}{
+		{
+			name:     "empty",
+			expected: map[v1alpha1.FeatureGateName]bool{},
+		},
+		{
+			name:  "gates",
+			value: " Federation=false, NestedTopology=true,",
+			expected: map[v1alpha1.FeatureGateName]bool{
+				v1alpha1.FeatureGateFederation:     false,
+				v1alpha1.FeatureGateNestedTopology: true,
+			},
+		},
+		{
+			name:        "missing state",
+			value:       "Federation",
+			expectedErr: "expected <gate>=<true|false>",
+		},
+		{
+			name:        "unknown gate",
+			value:       "Teleport=true",
+			expectedErr: `unknown feature gate "Teleport"`,
+		},
+		{
+			name:        "invalid state",
+			value:       "Federation=maybe",
+			expectedErr: "must be true or false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gates, err := ParseFeatureGates(tt.value)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(gates, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, gates)
+			}
+		})
+	}
+}
+
+func TestDefaultFeatureGates(t *testing.T) {
+	t.Cleanup(func() { operatorFeatureGates.Store(nil) })
+	operatorFeatureGates.Store(nil)
+
+	// Only the baseline capabilities are enabled by default, the experimental ones ship dark
+	expected := map[v1alpha1.FeatureGateName]bool{
+		v1alpha1.FeatureGateFederation:            true,
+		v1alpha1.FeatureGateNestedTopology:        false,
+		v1alpha1.FeatureGateSpiffeHelperInjection: false,
+	}
+	if !reflect.DeepEqual(defaultFeatureGates, expected) {
+		t.Errorf("expected the default feature gates %v, got %v", expected, defaultFeatureGates)
+	}
+	for gate, enabled := range expected {
+		if FeatureGateEnabled(nil, gate) != enabled {
+			t.Errorf("expected feature gate %s to default to enabled=%v", gate, enabled)
+		}
+	}
+}
+
+func TestFeatureGateEnabled(t *testing.T) {
+	t.Cleanup(func() { operatorFeatureGates.Store(nil) })
+
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			FeatureGates: []v1alpha1.FeatureGate{
+				{Name: v1alpha1.FeatureGateNestedTopology, Enabled: true},
+			},
+		},
+	}
+
+	if !FeatureGateEnabled(nil, v1alpha1.FeatureGateFederation) {
+		t.Error("expected the default of the gate to apply")
+	}
+
+	SetOperatorFeatureGates(map[v1alpha1.FeatureGateName]bool{
+		v1alpha1.FeatureGateFederation:     false,
+		v1alpha1.FeatureGateNestedTopology: false,
+	})
+	if FeatureGateEnabled(ztwim, v1alpha1.FeatureGateFederation) {
+		t.Error("expected the operator flag to override the default")
+	}
+	if !FeatureGateEnabled(ztwim, v1alpha1.FeatureGateNestedTopology) {
+		t.Error("expected the ztwim setting to override the operator flag")
+	}
+	if FeatureGateEnabled(ztwim, v1alpha1.FeatureGateSpiffeHelperInjection) {
+		t.Error("expected the default of a gate unset by the flag to apply")
+	}
+
+	enabled, disabled := FeatureGatesSummary(ztwim)
+	if !reflect.DeepEqual(enabled, []string{"NestedTopology"}) {
+		t.Errorf("unexpected enabled gates %v", enabled)
+	}
+	if !reflect.DeepEqual(disabled, []string{"Federation", "SpiffeHelperInjection"}) {
+		t.Errorf("unexpected disabled gates %v", disabled)
+	}
+}
+
+func TestZTWIMSpecChangedPredicateFeatureGates(t *testing.T) {
+	newZTWIM := func(gates ...v1alpha1.FeatureGate) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		return &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{FeatureGates: gates}}
+	}
+	disabled := v1alpha1.FeatureGate{Name: v1alpha1.FeatureGateFederation, Enabled: false}
+
+	if !ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newZTWIM(), ObjectNew: newZTWIM(disabled)}) {
+		t.Error("expected a feature gate change to trigger reconciliation")
+	}
+	if ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newZTWIM(disabled), ObjectNew: newZTWIM(disabled)}) {
+		t.Error("expected unchanged feature gates not to trigger reconciliation")
+	}
+}
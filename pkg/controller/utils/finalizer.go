@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OperandCleanupFinalizer is added to the operand CRs so that their managed resources are
+	// torn down in dependency order before the CR is removed
+	OperandCleanupFinalizer = "operator.openshift.io/operand-cleanup"
+
+	DeletionStatusType           = "Deleting"
+	DeletionWaitingForDependents = "WaitingForDependents"
+	DeletionDeletingResources    = "DeletingResources"
+
+	// DeletionRequeueInterval is how often a teardown waiting on other operands is retried
+	DeletionRequeueInterval = 5 * time.Second
+)
+
+// ObjectReaderDeleter is the subset of the controller client needed for teardown
+type ObjectReaderDeleter interface {
+	Get(context.Context, client.ObjectKey, client.Object) error
+	Delete(context.Context, client.Object, ...client.DeleteOption) error
+}
+
+// DeleteObjects deletes the given objects in order, ignoring the ones that are already gone
+func DeleteObjects(ctx context.Context, c ObjectReaderDeleter, objs ...client.Object) error {
+	for _, obj := range objs {
+		if err := c.Delete(ctx, obj); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s %s: %w", objectKindName(obj), client.ObjectKeyFromObject(obj), err)
+		}
+	}
+	return nil
+}
+
+// ObjectsBeingDeleted returns the kinds of the given objects that still exist and are being deleted.
+// Teardown uses it to wait for the operands that depend on the one being deleted, while operands
+// that are kept in place do not block it.
+func ObjectsBeingDeleted(ctx context.Context, c ObjectReaderDeleter, objs ...client.Object) ([]string, error) {
+	var deleting []string
+	for _, obj := range objs {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !obj.GetDeletionTimestamp().IsZero() {
+			deleting = append(deleting, objectKindName(obj))
+		}
+	}
+	return deleting, nil
+}
+
+// objectKindName returns the Go type name of obj, which matches the kind for typed objects
+func objectKindName(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+}
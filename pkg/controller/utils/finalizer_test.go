@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// readerDeleter adapts a controller-runtime client to ObjectReaderDeleter
+type readerDeleter struct {
+	client.Client
+}
+
+func (r readerDeleter) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return r.Client.Get(ctx, key, obj)
+}
+
+func newFinalizerTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	return scheme
+}
+
+func TestDeleteObjects(t *testing.T) {
+	existing := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: "ns"}}
+	c := fake.NewClientBuilder().WithScheme(newFinalizerTestScheme()).WithObjects(existing).Build()
+
+	err := DeleteObjects(context.Background(), readerDeleter{c},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "spire-agent", Namespace: "ns"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "ns"}},
+	)
+	if err != nil {
+		t.Fatalf("DeleteObjects() error = %v", err)
+	}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(existing), &appsv1.DaemonSet{}); err == nil {
+		t.Error("Expected the DaemonSet to be deleted")
+	}
+
+	failing := fake.NewClientBuilder().WithScheme(newFinalizerTestScheme()).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			return errors.New("delete failed")
+		},
+	}).Build()
+	if err := DeleteObjects(context.Background(), readerDeleter{failing}, existing); err == nil {
+		t.Error("Expected the delete error to be returned")
+	}
+}
+
+func TestObjectsBeingDeleted(t *testing.T) {
+	now := metav1.Now()
+	deleting := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster", DeletionTimestamp: &now, Finalizers: []string{OperandCleanupFinalizer}}}
+	kept := &v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	c := fake.NewClientBuilder().WithScheme(newFinalizerTestScheme()).WithObjects(deleting, kept).Build()
+
+	got, err := ObjectsBeingDeleted(context.Background(), readerDeleter{c},
+		&v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&v1alpha1.SpireOIDCDiscoveryProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+	)
+	if err != nil {
+		t.Fatalf("ObjectsBeingDeleted() error = %v", err)
+	}
+	if want := []string{"SpireAgent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ObjectsBeingDeleted() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const fipsModeEnvName = "FIPS_MODE"
+
+// fipsEnabledProcPath is the kernel setting reporting whether the host runs in FIPS mode.
+// It is a variable so that tests can point it at a temporary file.
+var fipsEnabledProcPath = "/proc/sys/crypto/fips_enabled"
+
+// logInvalidFIPSModeOnce ensures we only log the warning once
+var logInvalidFIPSModeOnce sync.Once
+
+// FIPSApprovedKeyTypes lists the SPIRE key types backed by FIPS 186 approved algorithms.
+var FIPSApprovedKeyTypes = []string{"rsa-2048", "rsa-4096", "ec-p256", "ec-p384"}
+
+// IsFIPSModeEnabled checks if FIPS mode is enabled.
+// It accepts case-insensitive values for the FIPS_MODE environment variable:
+//   - "true" -> returns true (enabled)
+//   - "false" -> returns false (disabled)
+//   - "auto", empty, or invalid -> detected from the host kernel (/proc/sys/crypto/fips_enabled)
+//
+// On OpenShift, cluster nodes installed with fips: true boot with the kernel in FIPS mode,
+// so auto-detection reflects the cluster configuration.
+func IsFIPSModeEnabled() bool {
+	value := strings.TrimSpace(os.Getenv(fipsModeEnvName))
+	normalized := strings.ToUpper(value)
+
+	switch normalized {
+	case "TRUE":
+		return true
+	case "FALSE":
+		return false
+	case "AUTO", "":
+		return isHostFIPSEnabled()
+	default:
+		// Log warning once for invalid value
+		logInvalidFIPSModeOnce.Do(func() {
+			ctrl.Log.WithName("fips-mode").Info("Invalid FIPS_MODE value, falling back to auto-detection",
+				"value", value,
+				"validValues", "true, false, auto (case-insensitive)")
+		})
+		return isHostFIPSEnabled()
+	}
+}
+
+// isHostFIPSEnabled reports whether the host kernel runs in FIPS mode
+func isHostFIPSEnabled() bool {
+	data, err := os.ReadFile(fipsEnabledProcPath)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(data)) == "1"
+}
+
+// ValidateFIPSKeyType returns an error if the key type is not FIPS approved.
+// Empty key types are accepted, as they fall back to the SPIRE default.
+func ValidateFIPSKeyType(field, keyType string) error {
+	if keyType == "" {
+		return nil
+	}
+	for _, approved := range FIPSApprovedKeyTypes {
+		if keyType == approved {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q is not a FIPS approved key type, must be one of %v", field, keyType, FIPSApprovedKeyTypes)
+}
+
+// ValidateFIPSImages returns an error listing the FIPS image environment variables that are not set
+// when FIPS mode is enabled. The controllers refuse to deploy their operands in that case, instead of
+// falling back to the non-FIPS images.
+func ValidateFIPSImages(fipsEnvs ...string) error {
+	if !IsFIPSModeEnabled() {
+		return nil
+	}
+	var missing []string
+	for _, env := range fipsEnvs {
+		if imageFromEnv(env) == "" {
+			missing = append(missing, env)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("FIPS mode is enabled but the FIPS images are not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// selectImage returns the FIPS image from fipsEnv when FIPS mode is enabled and the image is set,
+// otherwise it returns the image from env. The callers validate with ValidateFIPSImages that the FIPS
+// image is set before deploying it.
+func selectImage(env, fipsEnv string) string {
+	if IsFIPSModeEnabled() {
+		if fipsImage := imageFromEnv(fipsEnv); fipsImage != "" {
+			return fipsImage
+		}
+	}
+	return imageFromEnv(env)
+}
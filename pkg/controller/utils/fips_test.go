@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setFIPSProcFile points fipsEnabledProcPath at a temporary file with the given content
+func setFIPSProcFile(t *testing.T, content string) {
+	t.Helper()
+	original := fipsEnabledProcPath
+	t.Cleanup(func() { fipsEnabledProcPath = original })
+
+	if content == "" {
+		fipsEnabledProcPath = filepath.Join(t.TempDir(), "missing")
+		return
+	}
+	path := filepath.Join(t.TempDir(), "fips_enabled")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write fips file: %v", err)
+	}
+	fipsEnabledProcPath = path
+}
+
+func TestIsFIPSModeEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		hostFIPS string
+		expected bool
+	}{
+		{name: "explicitly enabled", envValue: "true", hostFIPS: "0\n", expected: true},
+		{name: "explicitly enabled (uppercase)", envValue: "TRUE", expected: true},
+		{name: "explicitly disabled on FIPS host", envValue: "false", hostFIPS: "1\n", expected: false},
+		{name: "unset on FIPS host", envValue: "", hostFIPS: "1\n", expected: true},
+		{name: "unset on non-FIPS host", envValue: "", hostFIPS: "0\n", expected: false},
+		{name: "auto without kernel setting", envValue: "auto", expected: false},
+		{name: "auto on FIPS host", envValue: "Auto", hostFIPS: "1", expected: true},
+		{name: "invalid value falls back to detection", envValue: "yes", hostFIPS: "1\n", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(fipsModeEnvName, tt.envValue)
+			setFIPSProcFile(t, tt.hostFIPS)
+			if result := IsFIPSModeEnabled(); result != tt.expected {
+				t.Errorf("IsFIPSModeEnabled() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateFIPSKeyType(t *testing.T) {
+	tests := []struct {
+		keyType     string
+		expectError bool
+	}{
+		{keyType: "", expectError: false},
+		{keyType: "rsa-2048", expectError: false},
+		{keyType: "rsa-4096", expectError: false},
+		{keyType: "ec-p256", expectError: false},
+		{keyType: "ec-p384", expectError: false},
+		{keyType: "rsa-1024", expectError: true},
+		{keyType: "ed25519", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType, func(t *testing.T) {
+			err := ValidateFIPSKeyType("caKeyType", tt.keyType)
+			if (err != nil) != tt.expectError {
+				t.Errorf("ValidateFIPSKeyType(%q) error = %v, expectError = %v", tt.keyType, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestSelectImageFIPS(t *testing.T) {
+	t.Setenv(SpireServerImageEnv, "registry.example.com/spire-server:v1")
+	t.Setenv(SpireServerFIPSImageEnv, "registry.example.com/spire-server-fips:v1")
+	setFIPSProcFile(t, "")
+
+	t.Setenv(fipsModeEnvName, "false")
+	if image := GetSpireServerImage(); image != "registry.example.com/spire-server:v1" {
+		t.Errorf("expected default image when FIPS mode is disabled, got %q", image)
+	}
+
+	t.Setenv(fipsModeEnvName, "true")
+	if image := GetSpireServerImage(); image != "registry.example.com/spire-server-fips:v1" {
+		t.Errorf("expected FIPS image when FIPS mode is enabled, got %q", image)
+	}
+
+	t.Setenv(SpireServerFIPSImageEnv, "")
+	if image := GetSpireServerImage(); image != "registry.example.com/spire-server:v1" {
+		t.Errorf("expected default image when FIPS image is unset, got %q", image)
+	}
+}
+
+func TestSpiffeCsiInitContainerImageFIPS(t *testing.T) {
+	t.Setenv(SpiffeCSIInitContainerImageEnv, "registry.example.com/ubi9:latest")
+	t.Setenv(SpiffeCSIInitContainerFIPSImageEnv, "registry.example.com/ubi9-fips:latest")
+	setFIPSProcFile(t, "")
+
+	t.Setenv(fipsModeEnvName, "false")
+	if image := GetSpiffeCsiInitContainerImage(); image != "registry.example.com/ubi9:latest" {
+		t.Errorf("expected default image when FIPS mode is disabled, got %q", image)
+	}
+
+	t.Setenv(fipsModeEnvName, "true")
+	if image := GetSpiffeCsiInitContainerImage(); image != "registry.example.com/ubi9-fips:latest" {
+		t.Errorf("expected FIPS image when FIPS mode is enabled, got %q", image)
+	}
+}
+
+func TestValidateFIPSImages(t *testing.T) {
+	t.Setenv(SpireServerFIPSImageEnv, "registry.example.com/spire-server-fips:v1")
+	t.Setenv(SpireControllerManagerFIPSImageEnv, "")
+	setFIPSProcFile(t, "")
+
+	t.Setenv(fipsModeEnvName, "false")
+	if err := ValidateFIPSImages(SpireServerFIPSImageEnv, SpireControllerManagerFIPSImageEnv); err != nil {
+		t.Errorf("expected no error when FIPS mode is disabled, got %v", err)
+	}
+
+	t.Setenv(fipsModeEnvName, "true")
+	if err := ValidateFIPSImages(SpireServerFIPSImageEnv); err != nil {
+		t.Errorf("expected no error when the FIPS image is set, got %v", err)
+	}
+	err := ValidateFIPSImages(SpireServerFIPSImageEnv, SpireControllerManagerFIPSImageEnv)
+	if err == nil || !strings.Contains(err.Error(), SpireControllerManagerFIPSImageEnv) {
+		t.Errorf("expected an error naming %s, got %v", SpireControllerManagerFIPSImageEnv, err)
+	}
+}
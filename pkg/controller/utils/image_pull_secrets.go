@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateImagePullSecrets validates that the image pull secrets of an operand reference distinct Secrets
+func ValidateImagePullSecrets(secrets []corev1.LocalObjectReference) error {
+	names := make(map[string]bool, len(secrets))
+	for i, secret := range secrets {
+		if secret.Name == "" {
+			return fmt.Errorf("imagePullSecrets[%d].name: required", i)
+		}
+		if errs := validation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+			return fmt.Errorf("imagePullSecrets[%d].name: %s", i, strings.Join(errs, "; "))
+		}
+		if names[secret.Name] {
+			return fmt.Errorf("imagePullSecrets[%d].name: duplicate Secret %q", i, secret.Name)
+		}
+		names[secret.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateImagePullSecrets(t *testing.T) {
+	tests := []struct {
+		name        string
+		secrets     []corev1.LocalObjectReference
+		expectedErr string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name:    "valid secrets",
+			secrets: []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}, {Name: "quay.example.com"}},
+		},
+		{
+			name:        "missing name",
+			secrets:     []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}, {}},
+			expectedErr: "imagePullSecrets[1].name: required",
+		},
+		{
+			name:        "invalid name",
+			secrets:     []corev1.LocalObjectReference{{Name: "Mirror_Pull_Secret"}},
+			expectedErr: "imagePullSecrets[0].name",
+		},
+		{
+			name:        "duplicate secret",
+			secrets:     []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}, {Name: "mirror-pull-secret"}},
+			expectedErr: "imagePullSecrets[1].name: duplicate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImagePullSecrets(tt.secrets)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDaemonSetNeedsUpdateImagePullSecrets(t *testing.T) {
+	newDaemonSet := func(secrets ...string) *appsv1.DaemonSet {
+		ds := &appsv1.DaemonSet{}
+		for _, name := range secrets {
+			ds.Spec.Template.Spec.ImagePullSecrets = append(ds.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+		return ds
+	}
+
+	if DaemonSetNeedsUpdate(newDaemonSet("mirror-pull-secret"), newDaemonSet("mirror-pull-secret")) {
+		t.Error("Expected no update for identical image pull secrets")
+	}
+	if !DaemonSetNeedsUpdate(newDaemonSet(), newDaemonSet("mirror-pull-secret")) {
+		t.Error("Expected an update when an image pull secret is added")
+	}
+}
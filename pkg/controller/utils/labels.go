@@ -43,11 +43,26 @@ func StandardizedLabels(name, component, version string, customLabels map[string
 	return labels
 }
 
+// PodSelectorLabels returns the subset of the standardized labels of an operand selecting its pods,
+// which custom labels can't override
+func PodSelectorLabels(labels map[string]string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":      labels["app.kubernetes.io/name"],
+		"app.kubernetes.io/instance":  labels["app.kubernetes.io/instance"],
+		"app.kubernetes.io/component": labels["app.kubernetes.io/component"],
+	}
+}
+
 // Component-specific label generators
 func SpireServerLabels(customLabels map[string]string) map[string]string {
 	return StandardizedLabels("spire-server", ComponentControlPlane, version.SpireServerVersion, customLabels)
 }
 
+// TornjakLabels returns the labels of the Tornjak UI resources, which are part of the control plane
+func TornjakLabels(customLabels map[string]string) map[string]string {
+	return StandardizedLabels("spire-tornjak", ComponentControlPlane, version.TornjakVersion, customLabels)
+}
+
 func SpireAgentLabels(customLabels map[string]string) map[string]string {
 	return StandardizedLabels("spire-agent", ComponentNodeAgent, version.SpireAgentVersion, customLabels)
 }
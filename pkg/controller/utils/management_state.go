@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// GetManagementState returns the management state in effect for the resources of an operand. The state
+// of the ZeroTrustWorkloadIdentityManager takes precedence over the state of the operand when not Managed.
+func GetManagementState(operandState v1alpha1.ManagementState, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) v1alpha1.ManagementState {
+	if ztwim != nil && ztwim.Spec.ManagementState != "" && ztwim.Spec.ManagementState != v1alpha1.ManagementStateManaged {
+		return ztwim.Spec.ManagementState
+	}
+	if operandState == "" {
+		return v1alpha1.ManagementStateManaged
+	}
+	return operandState
+}
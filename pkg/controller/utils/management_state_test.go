@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestGetManagementState(t *testing.T) {
+	newZTWIM := func(state v1alpha1.ManagementState) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		return &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{ManagementState: state}}
+	}
+
+	tests := []struct {
+		name         string
+		operandState v1alpha1.ManagementState
+		ztwim        *v1alpha1.ZeroTrustWorkloadIdentityManager
+		expected     v1alpha1.ManagementState
+	}{
+		{"defaults to Managed", "", newZTWIM(""), v1alpha1.ManagementStateManaged},
+		{"operand state when the ZTWIM is managed", v1alpha1.ManagementStateUnmanaged, newZTWIM(v1alpha1.ManagementStateManaged), v1alpha1.ManagementStateUnmanaged},
+		{"operand state without ZTWIM", v1alpha1.ManagementStateRemoved, nil, v1alpha1.ManagementStateRemoved},
+		{"ZTWIM state takes precedence", v1alpha1.ManagementStateUnmanaged, newZTWIM(v1alpha1.ManagementStateRemoved), v1alpha1.ManagementStateRemoved},
+		{"ZTWIM state overrides a managed operand", v1alpha1.ManagementStateManaged, newZTWIM(v1alpha1.ManagementStateUnmanaged), v1alpha1.ManagementStateUnmanaged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetManagementState(tt.operandState, tt.ztwim); got != tt.expected {
+				t.Errorf("GetManagementState() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// WatchNamespaceEnvName is the comma separated list of namespaces the manager cache is restricted to.
+// OLM sets it from the target namespaces of the operator group; when empty all namespaces are watched.
+const WatchNamespaceEnvName = "WATCH_NAMESPACE"
+
+// operandNamespace holds the namespace configured on the ZeroTrustWorkloadIdentityManager.
+// The field is immutable, so it is safe to share it between the operand controllers.
+var operandNamespace atomic.Value
+
+// defaultOperandNamespace holds the operand namespace set on the ZeroTrustWorkloadIdentityManager
+// created without one, configured in the operator configuration ConfigMap
+var defaultOperandNamespace atomic.Value
+
+// SetDefaultOperandNamespace sets the operand namespace defaulted on the ZeroTrustWorkloadIdentityManager
+// at creation. An empty value keeps the operands in the operator namespace.
+func SetDefaultOperandNamespace(namespace string) {
+	defaultOperandNamespace.Store(namespace)
+}
+
+// GetDefaultOperandNamespace returns the operand namespace defaulted on the ZeroTrustWorkloadIdentityManager
+// at creation, empty when none is configured
+func GetDefaultOperandNamespace() string {
+	namespace, _ := defaultOperandNamespace.Load().(string)
+	return namespace
+}
+
+// GetWatchNamespaces returns the namespaces the manager cache is restricted to.
+// The operator namespace is always included so that the operator can read its own resources.
+// Returns nil when all namespaces are watched.
+func GetWatchNamespaces() []string {
+	value := strings.TrimSpace(os.Getenv(WatchNamespaceEnvName))
+	if value == "" {
+		return nil
+	}
+
+	namespaces := []string{GetOperatorNamespace()}
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// IsWatchedNamespace reports whether the manager cache watches the given namespace
+func IsWatchedNamespace(namespace string) bool {
+	namespaces := GetWatchNamespaces()
+	return namespaces == nil || slices.Contains(namespaces, namespace)
+}
+
+// ConfigureOperandNamespace sets the namespace the operands are installed in from the
+// ZeroTrustWorkloadIdentityManager spec. An empty value selects the operator namespace.
+// It returns an error when the namespace is not watched by the manager cache.
+func ConfigureOperandNamespace(namespace string) error {
+	if namespace != "" && !IsWatchedNamespace(namespace) {
+		return fmt.Errorf("operand namespace %q is not watched by the operator, watched namespaces: %s",
+			namespace, strings.Join(GetWatchNamespaces(), ","))
+	}
+	operandNamespace.Store(namespace)
+	return nil
+}
+
+// GetOperandNamespace returns the namespace where the operand resources are installed.
+// It falls back to the operator namespace until ConfigureOperandNamespace is called.
+func GetOperandNamespace() string {
+	if namespace, ok := operandNamespace.Load().(string); ok && namespace != "" {
+		return namespace
+	}
+	return GetOperatorNamespace()
+}
+
+// ObjectGetter is the subset of the controller client needed to read the ZeroTrustWorkloadIdentityManager
+type ObjectGetter interface {
+	Get(context.Context, client.ObjectKey, client.Object) error
+}
+
+// OperandNamespaceOf returns the namespace the operands of ztwim are installed in
+func OperandNamespaceOf(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) string {
+	if ztwim.Spec.OperandNamespace != "" {
+		return ztwim.Spec.OperandNamespace
+	}
+	return GetOperatorNamespace()
+}
+
+// ResolveOperandNamespace returns the namespace of the resources managed for an operand CR without
+// relying on ConfigureOperandNamespace, as the teardown can be the first reconcile after an operator
+// restart: the namespace configured on the ZeroTrustWorkloadIdentityManager or, once it is deleted,
+// the namespace recorded in the inventory of the CR.
+func ResolveOperandNamespace(ctx context.Context, c ObjectGetter, inventory []v1alpha1.ManagedResource) (string, error) {
+	var ztwim v1alpha1.ZeroTrustWorkloadIdentityManager
+	err := c.Get(ctx, types.NamespacedName{Name: "cluster"}, &ztwim)
+	if err == nil {
+		return OperandNamespaceOf(&ztwim), nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get ZeroTrustWorkloadIdentityManager: %w", err)
+	}
+	for _, resource := range inventory {
+		if resource.Namespace != "" {
+			return resource.Namespace, nil
+		}
+	}
+	return GetOperatorNamespace(), nil
+}
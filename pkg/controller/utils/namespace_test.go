@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestGetWatchNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "all namespaces when unset",
+			value:    "",
+			expected: nil,
+		},
+		{
+			name:     "operator namespace is always watched",
+			value:    "layered-product",
+			expected: []string{"operator-ns", "layered-product"},
+		},
+		{
+			name:     "list is trimmed and deduplicated",
+			value:    " layered-product, operator-ns,,other ",
+			expected: []string{"operator-ns", "layered-product", "other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPERATOR_NAMESPACE", "operator-ns")
+			t.Setenv(WatchNamespaceEnvName, tt.value)
+
+			if got := GetWatchNamespaces(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConfigureOperandNamespace(t *testing.T) {
+	tests := []struct {
+		name            string
+		watchNamespaces string
+		namespace       string
+		expectError     bool
+		expected        string
+	}{
+		{
+			name:     "empty selects the operator namespace",
+			expected: "operator-ns",
+		},
+		{
+			name:      "any namespace when all namespaces are watched",
+			namespace: "layered-product",
+			expected:  "layered-product",
+		},
+		{
+			name:            "watched namespace is accepted",
+			watchNamespaces: "layered-product",
+			namespace:       "layered-product",
+			expected:        "layered-product",
+		},
+		{
+			name:            "unwatched namespace is rejected",
+			watchNamespaces: "layered-product",
+			namespace:       "other",
+			expectError:     true,
+			expected:        "operator-ns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPERATOR_NAMESPACE", "operator-ns")
+			t.Setenv(WatchNamespaceEnvName, tt.watchNamespaces)
+			operandNamespace.Store("")
+			t.Cleanup(func() { operandNamespace.Store("") })
+
+			err := ConfigureOperandNamespace(tt.namespace)
+			if tt.expectError && err == nil {
+				t.Error("Expected an error")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if got := GetOperandNamespace(); got != tt.expected {
+				t.Errorf("Expected operand namespace %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolveOperandNamespace(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "operator-ns")
+	inventory := []v1alpha1.ManagedResource{
+		{APIVersion: "storage.k8s.io/v1", Kind: "CSIDriver", Name: "csi.spiffe.io"},
+		{APIVersion: "apps/v1", Kind: "DaemonSet", Namespace: "layered-product", Name: "spire-agent"},
+	}
+
+	tests := []struct {
+		name      string
+		ztwim     *v1alpha1.ZeroTrustWorkloadIdentityManager
+		inventory []v1alpha1.ManagedResource
+		expected  string
+	}{
+		{
+			name:     "namespace configured on the ZTWIM",
+			ztwim:    &v1alpha1.ZeroTrustWorkloadIdentityManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{OperandNamespace: "configured"}},
+			expected: "configured",
+		},
+		{
+			name:      "operator namespace when the ZTWIM sets none",
+			ztwim:     &v1alpha1.ZeroTrustWorkloadIdentityManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			inventory: inventory,
+			expected:  "operator-ns",
+		},
+		{
+			name:      "inventory once the ZTWIM is deleted",
+			inventory: inventory,
+			expected:  "layered-product",
+		},
+		{
+			name:     "operator namespace without ZTWIM nor inventory",
+			expected: "operator-ns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(newFinalizerTestScheme())
+			if tt.ztwim != nil {
+				builder = builder.WithObjects(tt.ztwim)
+			}
+			namespace, err := ResolveOperandNamespace(context.Background(), readerDeleter{builder.Build()}, tt.inventory)
+			if err != nil {
+				t.Fatalf("ResolveOperandNamespace() error = %v", err)
+			}
+			if namespace != tt.expected {
+				t.Errorf("ResolveOperandNamespace() = %q, want %q", namespace, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+const (
+	// HostNetworkPolicyGroupLabelKey labels the namespaces whose traffic stands for the traffic of the
+	// host-network pods, e.g. the SPIRE agents, on OpenShift
+	HostNetworkPolicyGroupLabelKey = "policy-group.network.openshift.io/host-network"
+
+	// PolicyGroupLabelKey and MonitoringPolicyGroup label the namespaces of the cluster monitoring on OpenShift
+	PolicyGroupLabelKey   = "network.openshift.io/policy-group"
+	MonitoringPolicyGroup = "monitoring"
+)
+
+// IsNetworkPolicyEnabled returns true only if the NetworkPolicies are explicitly enabled
+func IsNetworkPolicyEnabled(config *v1alpha1.NetworkPolicyConfig) bool {
+	return config != nil && StringToBool(config.Enabled)
+}
+
+// GenerateNetworkPolicy returns the NetworkPolicy restricting the ingress traffic of the pods matched by
+// selectorLabels to the traffic allowed by ingress
+func GenerateNetworkPolicy(name string, labels, selectorLabels map[string]string, ingress []networkingv1.NetworkPolicyIngressRule) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: GetOperandNamespace(),
+			Labels:    labels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			Ingress:     ingress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+// NetworkPolicyPorts returns the ports of a NetworkPolicy allowing the traffic of ports, the ports of a
+// Service. The policy ports refer to the pods, so the target ports of the Service are used.
+func NetworkPolicyPorts(ports []corev1.ServicePort) []networkingv1.NetworkPolicyPort {
+	result := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		targetPort := port.TargetPort
+		if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+			targetPort = intstr.FromInt32(port.Port)
+		}
+		result = append(result, networkingv1.NetworkPolicyPort{
+			Protocol: &protocol,
+			Port:     &targetPort,
+		})
+	}
+	return result
+}
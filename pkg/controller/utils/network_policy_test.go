@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestIsNetworkPolicyEnabled(t *testing.T) {
+	if IsNetworkPolicyEnabled(nil) {
+		t.Error("Expected the NetworkPolicies to be disabled by default")
+	}
+	if IsNetworkPolicyEnabled(&v1alpha1.NetworkPolicyConfig{Enabled: "false"}) {
+		t.Error("Expected the NetworkPolicies to be disabled")
+	}
+	if !IsNetworkPolicyEnabled(&v1alpha1.NetworkPolicyConfig{Enabled: "true"}) {
+		t.Error("Expected the NetworkPolicies to be enabled")
+	}
+}
+
+func TestNetworkPolicyPorts(t *testing.T) {
+	ports := []corev1.ServicePort{
+		{Name: "grpc", Port: 443, TargetPort: intstr.FromString("grpc"), Protocol: corev1.ProtocolTCP},
+		{Name: "metrics", Port: 9402, TargetPort: intstr.FromInt32(9402)},
+		{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+	}
+	expected := []networkingv1.NetworkPolicyPort{
+		{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromString("grpc"))},
+		{Protocol: ptr.To(corev1.ProtocolTCP), Port: ptr.To(intstr.FromInt32(9402))},
+		{Protocol: ptr.To(corev1.ProtocolUDP), Port: ptr.To(intstr.FromInt32(53))},
+	}
+	if got := NetworkPolicyPorts(ports); !equality.Semantic.DeepEqual(expected, got) {
+		t.Errorf("Expected ports %v, got %v", expected, got)
+	}
+}
+
+func TestNetworkPolicyNeedsUpdate(t *testing.T) {
+	newPolicy := func(ports []corev1.ServicePort) *networkingv1.NetworkPolicy {
+		return GenerateNetworkPolicy("spire-server", nil, map[string]string{"app.kubernetes.io/name": "spire-server"},
+			[]networkingv1.NetworkPolicyIngressRule{{Ports: NetworkPolicyPorts(ports)}})
+	}
+	grpc := []corev1.ServicePort{{Name: "grpc", Port: 443, TargetPort: intstr.FromString("grpc")}}
+
+	if NetworkPolicyNeedsUpdate(newPolicy(grpc), newPolicy(grpc)) {
+		t.Error("Expected no update for identical NetworkPolicies")
+	}
+	if !NetworkPolicyNeedsUpdate(newPolicy(grpc), newPolicy(append(grpc, corev1.ServicePort{Name: "federation", Port: 8443}))) {
+		t.Error("Expected an update when a port is added")
+	}
+}
+
+func TestZTWIMSpecChangedPredicateNetworkPolicy(t *testing.T) {
+	newZTWIM := func(enabled string) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+			Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{NetworkPolicy: &v1alpha1.NetworkPolicyConfig{Enabled: enabled}},
+		}
+	}
+
+	if !ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newZTWIM("false"), ObjectNew: newZTWIM("true")}) {
+		t.Error("Expected enabling the NetworkPolicies to trigger reconciliation")
+	}
+	if ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newZTWIM("true"), ObjectNew: newZTWIM("true")}) {
+		t.Error("Expected no reconciliation when the NetworkPolicy configuration is unchanged")
+	}
+}
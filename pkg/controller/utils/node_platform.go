@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultNodeOS is the operating system of the nodes running the node-level operands
+const DefaultNodeOS = "linux"
+
+// getNodeOS returns the operating system selected by platform, defaulting to Linux
+func getNodeOS(platform *v1alpha1.NodePlatformConfig) string {
+	if platform == nil || platform.OS == "" {
+		return DefaultNodeOS
+	}
+	return platform.OS
+}
+
+// GetOperandImageArchitectures returns the architectures supported by the operand images, declared on the
+// operator since they depend on the manifest lists of the images it is released with. An empty result
+// means the architectures are not declared and the nodes of any architecture can run the operands.
+func GetOperandImageArchitectures() []v1alpha1.NodeArchitecture {
+	var architectures []v1alpha1.NodeArchitecture
+	for _, arch := range strings.Split(os.Getenv(OperandImageArchitecturesEnv), ",") {
+		if arch = strings.TrimSpace(arch); arch != "" {
+			architectures = append(architectures, v1alpha1.NodeArchitecture(arch))
+		}
+	}
+	return architectures
+}
+
+// getNodeArchitectures returns the architectures selected by platform, defaulting to the architectures
+// of the operand images
+func getNodeArchitectures(platform *v1alpha1.NodePlatformConfig) []v1alpha1.NodeArchitecture {
+	if platform != nil && len(platform.Architectures) > 0 {
+		return platform.Architectures
+	}
+	return GetOperandImageArchitectures()
+}
+
+// ValidateNodePlatform validates that the node platform of an operand is supported by the operand images,
+// and that its node selector doesn't contradict it, the pods could not be scheduled on any node
+func ValidateNodePlatform(nodeSelector map[string]string, platform *v1alpha1.NodePlatformConfig) error {
+	if nodeOS, ok := nodeSelector[corev1.LabelOSStable]; ok && nodeOS != getNodeOS(platform) {
+		return fmt.Errorf("nodeSelector %s=%s: operating system %q is not supported by the operand",
+			corev1.LabelOSStable, nodeOS, nodeOS)
+	}
+	if imageArchitectures := GetOperandImageArchitectures(); platform != nil && len(imageArchitectures) > 0 {
+		for _, arch := range platform.Architectures {
+			if !slices.Contains(imageArchitectures, arch) {
+				return fmt.Errorf("nodePlatform.architectures: architecture %q is not supported by the operand images, supported: %v",
+					arch, imageArchitectures)
+			}
+		}
+	}
+	architectures := getNodeArchitectures(platform)
+	if len(architectures) == 0 {
+		return nil
+	}
+	if arch, ok := nodeSelector[corev1.LabelArchStable]; ok && !slices.Contains(architectures, v1alpha1.NodeArchitecture(arch)) {
+		return fmt.Errorf("nodeSelector %s=%s: architecture %q is not in the selected architectures %v",
+			corev1.LabelArchStable, arch, arch, architectures)
+	}
+	return nil
+}
+
+// PlatformNodeSelector returns the node selector of an operand with the kubernetes.io/os label of its
+// node platform, so that the pods are never scheduled on the Windows nodes of mixed clusters
+func PlatformNodeSelector(nodeSelector map[string]string, platform *v1alpha1.NodePlatformConfig) map[string]string {
+	result := DerefNodeSelector(nodeSelector)
+	if _, ok := result[corev1.LabelOSStable]; !ok {
+		result[corev1.LabelOSStable] = getNodeOS(platform)
+	}
+	return result
+}
+
+// PlatformAffinity returns the affinity of an operand requiring the architectures of its node platform,
+// or of the operand images when the node platform doesn't set them. The terms of the required node
+// affinity are ORed, so the requirement is added to each of them. affinity is returned as is when no
+// architectures are selected.
+func PlatformAffinity(affinity *corev1.Affinity, platform *v1alpha1.NodePlatformConfig) *corev1.Affinity {
+	nodeArchitectures := getNodeArchitectures(platform)
+	if len(nodeArchitectures) == 0 {
+		return affinity
+	}
+	result := affinity.DeepCopy()
+	if result == nil {
+		result = &corev1.Affinity{}
+	}
+	if result.NodeAffinity == nil {
+		result.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		required = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}
+	}
+	architectures := make([]string, 0, len(nodeArchitectures))
+	for _, arch := range nodeArchitectures {
+		architectures = append(architectures, string(arch))
+	}
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelArchStable,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   architectures,
+		})
+	}
+	result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	return result
+}
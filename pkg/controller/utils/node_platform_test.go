@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestValidateNodePlatform(t *testing.T) {
+	tests := []struct {
+		name               string
+		nodeSelector       map[string]string
+		platform           *v1alpha1.NodePlatformConfig
+		imageArchitectures string
+		expectedErr        string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name:         "linux node selector",
+			nodeSelector: map[string]string{corev1.LabelOSStable: "linux"},
+		},
+		{
+			name:         "windows node selector",
+			nodeSelector: map[string]string{corev1.LabelOSStable: "windows"},
+			expectedErr:  "operating system \"windows\"",
+		},
+		{
+			name:         "architecture selected by both",
+			nodeSelector: map[string]string{corev1.LabelArchStable: "arm64"},
+			platform:     &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"amd64", "arm64"}},
+		},
+		{
+			name:         "architecture excluded by the node platform",
+			nodeSelector: map[string]string{corev1.LabelArchStable: "s390x"},
+			platform:     &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"amd64", "arm64"}},
+			expectedErr:  "architecture \"s390x\"",
+		},
+		{
+			name:               "architectures supported by the images",
+			platform:           &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"arm64"}},
+			imageArchitectures: "amd64,arm64",
+		},
+		{
+			name:               "architecture not supported by the images",
+			platform:           &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"amd64", "s390x"}},
+			imageArchitectures: "amd64,arm64",
+			expectedErr:        "nodePlatform.architectures",
+		},
+		{
+			name:               "node selector of an architecture not supported by the images",
+			nodeSelector:       map[string]string{corev1.LabelArchStable: "ppc64le"},
+			imageArchitectures: "amd64, arm64",
+			expectedErr:        "architecture \"ppc64le\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(OperandImageArchitecturesEnv, tt.imageArchitectures)
+			err := ValidateNodePlatform(tt.nodeSelector, tt.platform)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPlatformNodeSelector(t *testing.T) {
+	nodeSelector := map[string]string{"node-role.kubernetes.io/worker": ""}
+	got := PlatformNodeSelector(nodeSelector, nil)
+	expected := map[string]string{"node-role.kubernetes.io/worker": "", corev1.LabelOSStable: "linux"}
+	if !equality.Semantic.DeepEqual(expected, got) {
+		t.Errorf("Expected node selector %v, got %v", expected, got)
+	}
+	if len(nodeSelector) != 1 {
+		t.Errorf("Expected the node selector of the spec to be left unchanged, got %v", nodeSelector)
+	}
+
+	got = PlatformNodeSelector(nil, &v1alpha1.NodePlatformConfig{OS: "linux"})
+	if len(got) != 1 || got[corev1.LabelOSStable] != "linux" {
+		t.Errorf("Expected the os node selector, got %v", got)
+	}
+}
+
+func TestPlatformAffinity(t *testing.T) {
+	platform := &v1alpha1.NodePlatformConfig{Architectures: []v1alpha1.NodeArchitecture{"amd64", "arm64"}}
+	archRequirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelArchStable,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"amd64", "arm64"},
+	}
+
+	t.Run("no architectures", func(t *testing.T) {
+		t.Setenv(OperandImageArchitecturesEnv, "")
+		affinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+		if got := PlatformAffinity(affinity, &v1alpha1.NodePlatformConfig{}); got != affinity {
+			t.Errorf("Expected the affinity to be returned as is, got %v", got)
+		}
+	})
+
+	t.Run("no affinity", func(t *testing.T) {
+		got := PlatformAffinity(nil, platform)
+		expected := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement}},
+			}},
+		}}
+		if !equality.Semantic.DeepEqual(expected, got) {
+			t.Errorf("Expected affinity %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("defaults to the architectures of the images", func(t *testing.T) {
+		t.Setenv(OperandImageArchitecturesEnv, "amd64,arm64")
+		got := PlatformAffinity(nil, nil)
+		expected := []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement}}}
+		if !equality.Semantic.DeepEqual(expected, got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+			t.Errorf("Expected terms %v, got %v", expected, got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+		}
+	})
+
+	t.Run("requirement added to each term", func(t *testing.T) {
+		zone := corev1.NodeSelectorRequirement{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+		pool := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpExists}
+		affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{zone}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{pool}},
+			}},
+		}}
+		got := PlatformAffinity(affinity, platform)
+		expected := []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{zone, archRequirement}},
+			{MatchExpressions: []corev1.NodeSelectorRequirement{pool, archRequirement}},
+		}
+		if !equality.Semantic.DeepEqual(expected, got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+			t.Errorf("Expected terms %v, got %v", expected, got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+		}
+		if len(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions) != 1 {
+			t.Error("Expected the affinity of the spec to be left unchanged")
+		}
+	})
+}
+
+func TestGetOperandImageArchitectures(t *testing.T) {
+	t.Setenv(OperandImageArchitecturesEnv, "")
+	if got := GetOperandImageArchitectures(); len(got) != 0 {
+		t.Errorf("Expected no architectures, got %v", got)
+	}
+
+	t.Setenv(OperandImageArchitecturesEnv, " amd64,,s390x ")
+	expected := []v1alpha1.NodeArchitecture{"amd64", "s390x"}
+	if got := GetOperandImageArchitectures(); !equality.Semantic.DeepEqual(expected, got) {
+		t.Errorf("Expected architectures %v, got %v", expected, got)
+	}
+}
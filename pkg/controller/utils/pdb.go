@@ -0,0 +1,49 @@
+package utils
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// IsPodDisruptionBudgetEnabled returns true unless the PodDisruptionBudget is explicitly disabled
+func IsPodDisruptionBudgetEnabled(config *v1alpha1.PodDisruptionBudgetConfig) bool {
+	return config == nil || config.Enabled == "" || StringToBool(config.Enabled)
+}
+
+// GeneratePodDisruptionBudget returns the PodDisruptionBudget protecting the pods matched by selectorLabels.
+// If the configuration sets neither minAvailable nor maxUnavailable, minAvailable is set to 1 when more
+// than one replica is running, otherwise maxUnavailable is set to 1 so that node drains are not blocked.
+func GeneratePodDisruptionBudget(name string, labels, selectorLabels map[string]string, config *v1alpha1.PodDisruptionBudgetConfig, replicas int32) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: GetOperandNamespace(),
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+		},
+	}
+
+	switch {
+	case config != nil && config.MinAvailable != nil:
+		minAvailable := *config.MinAvailable
+		pdb.Spec.MinAvailable = &minAvailable
+	case config != nil && config.MaxUnavailable != nil:
+		maxUnavailable := *config.MaxUnavailable
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	case replicas > 1:
+		minAvailable := intstr.FromInt32(1)
+		pdb.Spec.MinAvailable = &minAvailable
+	default:
+		maxUnavailable := intstr.FromInt32(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return pdb
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestIsPodDisruptionBudgetEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *v1alpha1.PodDisruptionBudgetConfig
+		expected bool
+	}{
+		{name: "nil config", config: nil, expected: true},
+		{name: "enabled unset", config: &v1alpha1.PodDisruptionBudgetConfig{}, expected: true},
+		{name: "enabled", config: &v1alpha1.PodDisruptionBudgetConfig{Enabled: "true"}, expected: true},
+		{name: "disabled", config: &v1alpha1.PodDisruptionBudgetConfig{Enabled: "false"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsPodDisruptionBudgetEnabled(tt.config); result != tt.expected {
+				t.Errorf("IsPodDisruptionBudgetEnabled() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGeneratePodDisruptionBudget(t *testing.T) {
+	labels := map[string]string{"app": "test", "custom": "label"}
+	selectorLabels := map[string]string{"app": "test"}
+
+	tests := []struct {
+		name                   string
+		config                 *v1alpha1.PodDisruptionBudgetConfig
+		replicas               int32
+		expectedMinAvailable   *intstr.IntOrString
+		expectedMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:                   "single replica defaults to maxUnavailable 1",
+			replicas:               1,
+			expectedMaxUnavailable: ptr.To(intstr.FromInt32(1)),
+		},
+		{
+			name:                 "multiple replicas default to minAvailable 1",
+			replicas:             3,
+			expectedMinAvailable: ptr.To(intstr.FromInt32(1)),
+		},
+		{
+			name:                 "explicit minAvailable",
+			config:               &v1alpha1.PodDisruptionBudgetConfig{MinAvailable: ptr.To(intstr.FromString("50%"))},
+			replicas:             2,
+			expectedMinAvailable: ptr.To(intstr.FromString("50%")),
+		},
+		{
+			name:                   "explicit maxUnavailable",
+			config:                 &v1alpha1.PodDisruptionBudgetConfig{MaxUnavailable: ptr.To(intstr.FromInt32(2))},
+			replicas:               5,
+			expectedMaxUnavailable: ptr.To(intstr.FromInt32(2)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdb := GeneratePodDisruptionBudget("test-pdb", labels, selectorLabels, tt.config, tt.replicas)
+
+			if pdb.Name != "test-pdb" {
+				t.Errorf("expected name test-pdb, got %s", pdb.Name)
+			}
+			if pdb.Labels["custom"] != "label" {
+				t.Errorf("expected custom labels to be set, got %v", pdb.Labels)
+			}
+			if pdb.Spec.Selector == nil || len(pdb.Spec.Selector.MatchLabels) != 1 || pdb.Spec.Selector.MatchLabels["app"] != "test" {
+				t.Errorf("unexpected selector %v", pdb.Spec.Selector)
+			}
+			if !intOrStringPtrsEqual(pdb.Spec.MinAvailable, tt.expectedMinAvailable) {
+				t.Errorf("expected minAvailable %v, got %v", tt.expectedMinAvailable, pdb.Spec.MinAvailable)
+			}
+			if !intOrStringPtrsEqual(pdb.Spec.MaxUnavailable, tt.expectedMaxUnavailable) {
+				t.Errorf("expected maxUnavailable %v, got %v", tt.expectedMaxUnavailable, pdb.Spec.MaxUnavailable)
+			}
+		})
+	}
+}
+
+func intOrStringPtrsEqual(a, b *intstr.IntOrString) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
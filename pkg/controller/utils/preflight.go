@@ -0,0 +1,30 @@
+package utils
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// PreflightChecksBlockDeployment returns the message of the failed preflight checks of the ZeroTrustWorkloadIdentityManager
+// when they hold back the deployment of an operand. An operand reporting its workloadConditionType condition is already
+// deployed, and keeps being reconciled so that a check failing later doesn't leave it unmanaged.
+func PreflightChecksBlockDeployment(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, operandConditions []metav1.Condition, workloadConditionType string) (string, bool) {
+	preflight := apimeta.FindStatusCondition(ztwim.Status.Conditions, PreflightChecksPassedStatusType)
+	if preflight == nil || preflight.Status != metav1.ConditionFalse {
+		return "", false
+	}
+	if apimeta.FindStatusCondition(operandConditions, workloadConditionType) != nil {
+		return "", false
+	}
+	return preflight.Message, true
+}
+
+// preflightChecksStatus returns the status of the PreflightChecksPassed condition, empty before the checks ran
+func preflightChecksStatus(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) metav1.ConditionStatus {
+	if preflight := apimeta.FindStatusCondition(ztwim.Status.Conditions, PreflightChecksPassedStatusType); preflight != nil {
+		return preflight.Status
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func newPreflightZTWIM(status metav1.ConditionStatus) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{}
+	if status != "" {
+		ztwim.Status.Conditions = []metav1.Condition{
+			{Type: PreflightChecksPassedStatusType, Status: status, Message: "PreflightPodSecurity"},
+		}
+	}
+	return ztwim
+}
+
+func TestPreflightChecksBlockDeployment(t *testing.T) {
+	deployed := []metav1.Condition{{Type: "DaemonSetAvailable", Status: metav1.ConditionFalse}}
+
+	tests := []struct {
+		name        string
+		preflight   metav1.ConditionStatus
+		conditions  []metav1.Condition
+		expectBlock bool
+	}{
+		{name: "checks not run"},
+		{name: "checks passed", preflight: metav1.ConditionTrue},
+		{name: "checks failed before the deployment", preflight: metav1.ConditionFalse, expectBlock: true},
+		{name: "checks failed once deployed", preflight: metav1.ConditionFalse, conditions: deployed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, blocked := PreflightChecksBlockDeployment(newPreflightZTWIM(tt.preflight), tt.conditions, "DaemonSetAvailable")
+			if blocked != tt.expectBlock {
+				t.Errorf("Expected blocked to be %t, got %t", tt.expectBlock, blocked)
+			}
+			if blocked && message != "PreflightPodSecurity" {
+				t.Errorf("Expected the message of the failed checks, got %q", message)
+			}
+		})
+	}
+}
+
+func TestZTWIMSpecChangedPredicatePreflightChecks(t *testing.T) {
+	if !ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newPreflightZTWIM(metav1.ConditionFalse), ObjectNew: newPreflightZTWIM(metav1.ConditionTrue)}) {
+		t.Error("Expected passing preflight checks to trigger reconciliation")
+	}
+	if ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newPreflightZTWIM(metav1.ConditionTrue), ObjectNew: newPreflightZTWIM(metav1.ConditionTrue)}) {
+		t.Error("Expected no reconciliation when the outcome of the preflight checks is unchanged")
+	}
+}
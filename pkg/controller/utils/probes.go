@@ -0,0 +1,27 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// ApplyProbeTimings sets the timings configured on an operand CR over the operator defaults of probe.
+// The unset timings keep their defaults.
+func ApplyProbeTimings(probe *corev1.Probe, timings *v1alpha1.ProbeTimings) {
+	if probe == nil || timings == nil {
+		return
+	}
+	if timings.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *timings.InitialDelaySeconds
+	}
+	if timings.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *timings.TimeoutSeconds
+	}
+	if timings.PeriodSeconds != nil {
+		probe.PeriodSeconds = *timings.PeriodSeconds
+	}
+	if timings.FailureThreshold != nil {
+		probe.FailureThreshold = *timings.FailureThreshold
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// singleNodeLivenessProbePeriodSeconds is the shortest period of the liveness probes in the SingleNode profile
+const singleNodeLivenessProbePeriodSeconds = 30
+
+// singleNodeResourceRequests are requested by the containers without resources in the SingleNode profile
+var singleNodeResourceRequests = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("10m"),
+	corev1.ResourceMemory: resource.MustParse("32Mi"),
+}
+
+// IsSingleNodeProfile returns whether the operands are sized for single-node and resource-constrained clusters
+func IsSingleNodeProfile(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) bool {
+	return ztwim != nil && ztwim.Spec.Profile == v1alpha1.DeploymentProfileSingleNode
+}
+
+// IsPodDisruptionBudgetManaged returns whether the operator manages the PodDisruptionBudget of an operand.
+// In the SingleNode profile, draining the node always disrupts the operands, so the PodDisruptionBudget is
+// only managed when configured on the operand.
+func IsPodDisruptionBudgetManaged(config *v1alpha1.PodDisruptionBudgetConfig, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) bool {
+	if config == nil && IsSingleNodeProfile(ztwim) {
+		return false
+	}
+	return IsPodDisruptionBudgetEnabled(config)
+}
+
+// ApplyDeploymentProfile tunes the containers of podSpec for the profile of the ZeroTrustWorkloadIdentityManager.
+// In the SingleNode profile, the containers without resources request singleNodeResourceRequests and the
+// liveness probes run at most every singleNodeLivenessProbePeriodSeconds. It must be called before the user
+// provided containers are added, so that they are left as configured.
+func ApplyDeploymentProfile(podSpec *corev1.PodSpec, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) {
+	if !IsSingleNodeProfile(ztwim) {
+		return
+	}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			container := &containers[i]
+			if len(container.Resources.Requests) == 0 && len(container.Resources.Limits) == 0 {
+				container.Resources.Requests = singleNodeResourceRequests.DeepCopy()
+			}
+			if probe := container.LivenessProbe; probe != nil && probe.PeriodSeconds < singleNodeLivenessProbePeriodSeconds {
+				probe.PeriodSeconds = singleNodeLivenessProbePeriodSeconds
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func newProfileZTWIM(profile v1alpha1.DeploymentProfile) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+	return &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{Profile: profile}}
+}
+
+func TestIsPodDisruptionBudgetManaged(t *testing.T) {
+	singleNode := newProfileZTWIM(v1alpha1.DeploymentProfileSingleNode)
+
+	if !IsPodDisruptionBudgetManaged(nil, newProfileZTWIM(v1alpha1.DeploymentProfileDefault)) {
+		t.Error("Expected the PodDisruptionBudget to be managed by default")
+	}
+	if IsPodDisruptionBudgetManaged(nil, singleNode) {
+		t.Error("Expected the PodDisruptionBudget not to be managed in the SingleNode profile")
+	}
+	if !IsPodDisruptionBudgetManaged(&v1alpha1.PodDisruptionBudgetConfig{Enabled: "true"}, singleNode) {
+		t.Error("Expected a configured PodDisruptionBudget to be managed in the SingleNode profile")
+	}
+}
+
+func TestApplyDeploymentProfile(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:          "unset",
+					LivenessProbe: &corev1.Probe{PeriodSeconds: 10},
+				},
+				{
+					Name: "configured",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+					LivenessProbe: &corev1.Probe{PeriodSeconds: 60},
+				},
+			},
+		}
+	}
+
+	podSpec := newPodSpec()
+	ApplyDeploymentProfile(podSpec, newProfileZTWIM(v1alpha1.DeploymentProfileDefault))
+	if len(podSpec.Containers[0].Resources.Requests) != 0 || podSpec.Containers[0].LivenessProbe.PeriodSeconds != 10 {
+		t.Error("Expected the Default profile to leave the pods as is")
+	}
+
+	podSpec = newPodSpec()
+	ApplyDeploymentProfile(podSpec, newProfileZTWIM(v1alpha1.DeploymentProfileSingleNode))
+	unset, configured := podSpec.Containers[0], podSpec.Containers[1]
+	if !unset.Resources.Requests.Cpu().Equal(resource.MustParse("10m")) || !unset.Resources.Requests.Memory().Equal(resource.MustParse("32Mi")) {
+		t.Errorf("Expected the minimal requests, got %v", unset.Resources.Requests)
+	}
+	if unset.LivenessProbe.PeriodSeconds != singleNodeLivenessProbePeriodSeconds {
+		t.Errorf("Expected the liveness probe period %d, got %d", singleNodeLivenessProbePeriodSeconds, unset.LivenessProbe.PeriodSeconds)
+	}
+	if !configured.Resources.Requests.Memory().Equal(resource.MustParse("512Mi")) || configured.Resources.Requests.Cpu().Sign() != 0 {
+		t.Errorf("Expected the configured resources to be kept, got %v", configured.Resources.Requests)
+	}
+	if configured.LivenessProbe.PeriodSeconds != 60 {
+		t.Errorf("Expected a longer liveness probe period to be kept, got %d", configured.LivenessProbe.PeriodSeconds)
+	}
+}
@@ -159,7 +159,7 @@ func InjectProxyEnvVarsWithNoProxyAdditions(container *corev1.Container, additio
 // These should be added to NO_PROXY for components that need proxy for external access
 // but must bypass proxy for internal cluster communication.
 func GetInternalNoProxyEntries() []string {
-	namespace := GetOperatorNamespace()
+	namespace := GetOperandNamespace()
 	return []string{
 		// Internal service names used by SPIRE components
 		"spire-server." + namespace,
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RateLimiterOptions configures how the controllers requeue failed reconciles. Failing requests are
+// retried with an exponential backoff from BaseDelay up to MaxDelay, and retries of all requests are
+// throttled to QPS with bursts of BucketSize.
+type RateLimiterOptions struct {
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	QPS        float64
+	BucketSize int
+}
+
+// DefaultRateLimiterOptions retry a failed reconcile after a second and back off up to five minutes, so that
+// a resource that keeps failing to apply, e.g. because an admission policy rejects it, doesn't hot-loop the
+// controller. The throttling matches the controller-runtime default rate limiter.
+var DefaultRateLimiterOptions = RateLimiterOptions{
+	BaseDelay:  time.Second,
+	MaxDelay:   5 * time.Minute,
+	QPS:        10,
+	BucketSize: 100,
+}
+
+// operatorRateLimiterOptions holds the operator wide options set from the rate limiter flags.
+// It is nil until SetRateLimiterOptions is called, in which case the defaults are used.
+var operatorRateLimiterOptions atomic.Pointer[RateLimiterOptions]
+
+// Validate returns an error when the options cannot configure a rate limiter
+func (o RateLimiterOptions) Validate() error {
+	if o.BaseDelay <= 0 {
+		return fmt.Errorf("rate limiter base delay must be positive, got %s", o.BaseDelay)
+	}
+	if o.MaxDelay < o.BaseDelay {
+		return fmt.Errorf("rate limiter max delay %s must not be less than the base delay %s", o.MaxDelay, o.BaseDelay)
+	}
+	if o.QPS <= 0 {
+		return fmt.Errorf("rate limiter qps must be positive, got %v", o.QPS)
+	}
+	if o.BucketSize < 1 {
+		return fmt.Errorf("rate limiter bucket size must be at least 1, got %d", o.BucketSize)
+	}
+	return nil
+}
+
+// SetRateLimiterOptions sets the operator wide rate limiter options of the controllers
+func SetRateLimiterOptions(options RateLimiterOptions) {
+	operatorRateLimiterOptions.Store(&options)
+}
+
+// NewRateLimiter returns a rate limiter for the workqueue of a controller, configured with the
+// operator wide rate limiter options. Each controller needs its own rate limiter.
+func NewRateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	options := DefaultRateLimiterOptions
+	if configured := operatorRateLimiterOptions.Load(); configured != nil {
+		options = *configured
+	}
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](options.BaseDelay, options.MaxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(options.QPS), options.BucketSize)},
+	)
+}
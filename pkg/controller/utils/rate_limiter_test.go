@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRateLimiterOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(*RateLimiterOptions)
+		expectedErr string
+	}{
+		{
+			name:   "defaults are valid",
+			mutate: func(*RateLimiterOptions) {},
+		},
+		{
+			name:        "base delay not positive",
+			mutate:      func(o *RateLimiterOptions) { o.BaseDelay = 0 },
+			expectedErr: "base delay must be positive",
+		},
+		{
+			name:        "max delay below base delay",
+			mutate:      func(o *RateLimiterOptions) { o.MaxDelay = time.Millisecond },
+			expectedErr: "must not be less than the base delay",
+		},
+		{
+			name:        "qps not positive",
+			mutate:      func(o *RateLimiterOptions) { o.QPS = 0 },
+			expectedErr: "qps must be positive",
+		},
+		{
+			name:        "bucket size below 1",
+			mutate:      func(o *RateLimiterOptions) { o.BucketSize = 0 },
+			expectedErr: "bucket size must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultRateLimiterOptions
+			tt.mutate(&options)
+			err := options.Validate()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	t.Cleanup(func() { operatorRateLimiterOptions.Store(nil) })
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+
+	limiter := NewRateLimiter()
+	if got := limiter.When(request); got != DefaultRateLimiterOptions.BaseDelay {
+		t.Errorf("expected the default base delay %s, got %s", DefaultRateLimiterOptions.BaseDelay, got)
+	}
+
+	SetRateLimiterOptions(RateLimiterOptions{
+		BaseDelay:  time.Second,
+		MaxDelay:   3 * time.Second,
+		QPS:        100,
+		BucketSize: 100,
+	})
+	limiter = NewRateLimiter()
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for i, delay := range expected {
+		if got := limiter.When(request); got != delay {
+			t.Errorf("retry %d: expected a delay of %s, got %s", i, delay, got)
+		}
+	}
+
+	limiter.Forget(request)
+	if got := limiter.NumRequeues(request); got != 0 {
+		t.Errorf("expected no requeues after Forget, got %d", got)
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultDegradedFailureThreshold is the number of consecutive failed reconciles of a CR after which
+// its Degraded condition is set, when the operator --degraded-failure-threshold flag is not set
+const DefaultDegradedFailureThreshold = 5
+
+// degradedFailureThreshold holds the operator wide threshold set from the --degraded-failure-threshold flag
+var degradedFailureThreshold atomic.Int64
+
+// SetDegradedFailureThreshold sets the number of consecutive failed reconciles after which a CR is Degraded
+func SetDegradedFailureThreshold(threshold int) error {
+	if threshold < 1 {
+		return fmt.Errorf("degraded failure threshold must be at least 1, got %d", threshold)
+	}
+	degradedFailureThreshold.Store(int64(threshold))
+	return nil
+}
+
+// DegradedFailureThreshold returns the number of consecutive failed reconciles after which a CR is Degraded
+func DegradedFailureThreshold() int {
+	if threshold := degradedFailureThreshold.Load(); threshold > 0 {
+		return int(threshold)
+	}
+	return DefaultDegradedFailureThreshold
+}
+
+// FailureTracker counts the consecutive failed reconciles of each CR of a controller. The failed
+// reconciles are retried by the workqueue with an exponential backoff, see NewRateLimiter, and the
+// count tells when a CR keeps failing long enough to be reported as Degraded.
+type FailureTracker struct {
+	mu       sync.Mutex
+	failures map[types.NamespacedName]int
+}
+
+// NewFailureTracker returns a FailureTracker with no failure recorded
+func NewFailureTracker() *FailureTracker {
+	return &FailureTracker{failures: make(map[types.NamespacedName]int)}
+}
+
+// Observe records the outcome of a reconcile of key and returns its number of consecutive failures,
+// which is reset when err is nil. A nil FailureTracker records nothing and always returns 0.
+func (t *FailureTracker) Observe(key types.NamespacedName, err error) int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		delete(t.failures, key)
+		return 0
+	}
+	t.failures[key]++
+	return t.failures[key]
+}
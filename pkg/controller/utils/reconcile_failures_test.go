@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFailureTracker(t *testing.T) {
+	tracker := NewFailureTracker()
+	server := types.NamespacedName{Name: "cluster"}
+	agent := types.NamespacedName{Name: "agent"}
+	failure := errors.New("denied by admission policy")
+
+	for i := 1; i <= 3; i++ {
+		if got := tracker.Observe(server, failure); got != i {
+			t.Errorf("failure %d: expected %d consecutive failures, got %d", i, i, got)
+		}
+	}
+	if got := tracker.Observe(agent, failure); got != 1 {
+		t.Errorf("expected the failures to be counted per key, got %d", got)
+	}
+	if got := tracker.Observe(server, nil); got != 0 {
+		t.Errorf("expected a success to reset the failures, got %d", got)
+	}
+	if got := tracker.Observe(server, failure); got != 1 {
+		t.Errorf("expected the failures to be counted again from 1, got %d", got)
+	}
+
+	var untracked *FailureTracker
+	if got := untracked.Observe(server, failure); got != 0 {
+		t.Errorf("expected a nil tracker to record nothing, got %d", got)
+	}
+}
+
+func TestSetDegradedFailureThreshold(t *testing.T) {
+	t.Cleanup(func() { degradedFailureThreshold.Store(0) })
+
+	if got := DegradedFailureThreshold(); got != DefaultDegradedFailureThreshold {
+		t.Errorf("expected the default threshold %d, got %d", DefaultDegradedFailureThreshold, got)
+	}
+	if err := SetDegradedFailureThreshold(0); err == nil {
+		t.Error("expected a threshold below 1 to be rejected")
+	}
+	if err := SetDegradedFailureThreshold(3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := DegradedFailureThreshold(); got != 3 {
+		t.Errorf("expected the threshold 3, got %d", got)
+	}
+}
@@ -1,59 +1,86 @@
 package utils
 
-import "os"
+import (
+	"os"
+	"sync/atomic"
+)
 
-func GetSpireServerImage() string {
-	spireServerImage := os.Getenv(SpireServerImageEnv)
-	if spireServerImage == "" {
-		return ""
+// imageOverrides holds the images set in the operator configuration ConfigMap, keyed by the name of
+// the environment variable they override. It is nil until SetImageOverrides is called.
+var imageOverrides atomic.Pointer[map[string]string]
+
+// SetImageOverrides overrides the image environment variables, keyed by environment variable name
+func SetImageOverrides(images map[string]string) {
+	imageOverrides.Store(&images)
+}
+
+// imageFromEnv returns the image overriding the environment variable, or the value of the variable
+func imageFromEnv(env string) string {
+	if overrides := imageOverrides.Load(); overrides != nil {
+		if image := (*overrides)[env]; image != "" {
+			return image
+		}
 	}
-	return spireServerImage
+	return os.Getenv(env)
+}
+
+func GetSpireServerImage() string {
+	return selectImage(SpireServerImageEnv, SpireServerFIPSImageEnv)
 }
 
 func GetSpireAgentImage() string {
-	spireAgentImage := os.Getenv(SpireAgentImageEnv)
-	if spireAgentImage == "" {
-		return ""
-	}
-	return spireAgentImage
+	return selectImage(SpireAgentImageEnv, SpireAgentFIPSImageEnv)
 }
 
 func GetSpiffeCSIDriverImage() string {
-	spiffeCSIDriverImage := os.Getenv(SpiffeCSIDriverImageEnv)
-	if spiffeCSIDriverImage == "" {
-		return ""
-	}
-	return spiffeCSIDriverImage
+	return selectImage(SpiffeCSIDriverImageEnv, SpiffeCSIDriverFIPSImageEnv)
 }
 
 func GetSpireControllerManagerImage() string {
-	spireControllerManagerImage := os.Getenv(SpireControllerManagerImageEnv)
-	if spireControllerManagerImage == "" {
-		return ""
-	}
-	return spireControllerManagerImage
+	return selectImage(SpireControllerManagerImageEnv, SpireControllerManagerFIPSImageEnv)
 }
 
 func GetSpireOIDCDiscoveryProviderImage() string {
-	spireOIDCDiscoveryProviderImage := os.Getenv(SpireOIDCDiscoveryProviderImageEnv)
-	if spireOIDCDiscoveryProviderImage == "" {
-		return ""
-	}
-	return spireOIDCDiscoveryProviderImage
+	return selectImage(SpireOIDCDiscoveryProviderImageEnv, SpireOIDCDiscoveryProviderFIPSImageEnv)
 }
 
 func GetNodeDriverRegistrarImage() string {
-	nodeDriverRegistrarImage := os.Getenv(NodeDriverRegistrarImageEnv)
-	if nodeDriverRegistrarImage == "" {
-		return ""
-	}
-	return nodeDriverRegistrarImage
+	return selectImage(NodeDriverRegistrarImageEnv, NodeDriverRegistrarFIPSImageEnv)
+}
+
+func GetSpiffeHelperImage() string {
+	return selectImage(SpiffeHelperImageEnv, SpiffeHelperFIPSImageEnv)
 }
 
 func GetSpiffeCsiInitContainerImage() string {
-	containerImage := os.Getenv(SpiffeCSIInitContainerImageEnv)
+	containerImage := selectImage(SpiffeCSIInitContainerImageEnv, SpiffeCSIInitContainerFIPSImageEnv)
 	if containerImage == "" {
 		return "registry.access.redhat.com/ubi9:latest"
 	}
 	return containerImage
 }
+
+// GetDatastoreBackupImage returns the image of the datastore backup and restore containers,
+// which must provide a shell, pg_dump and, to back up the sqlite3 datastore, sqlite3
+func GetDatastoreBackupImage() string {
+	containerImage := imageFromEnv(DatastoreBackupImageEnv)
+	if containerImage == "" {
+		return "registry.redhat.io/rhel9/postgresql-16:latest"
+	}
+	return containerImage
+}
+
+// GetTornjakBackendImage returns the image of the Tornjak backend running alongside the SPIRE server
+func GetTornjakBackendImage() string {
+	return imageFromEnv(TornjakBackendImageEnv)
+}
+
+// GetTornjakFrontendImage returns the image of the Tornjak UI
+func GetTornjakFrontendImage() string {
+	return imageFromEnv(TornjakFrontendImageEnv)
+}
+
+// GetOAuthProxyImage returns the image of the OAuth proxy authenticating the users of the Tornjak UI
+func GetOAuthProxyImage() string {
+	return imageFromEnv(OAuthProxyImageEnv)
+}
@@ -5,7 +5,11 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 
@@ -58,12 +62,22 @@ func ResourceNeedsUpdate(existing, desired client.Object) bool {
 		typeSpecificResult = SecurityContextConstraintsNeedsUpdate(existingTyped, desired.(*securityv1.SecurityContextConstraints))
 	case *spiffev1alpha1.ClusterSPIFFEID:
 		typeSpecificResult = ClusterSPIFFEIDNeedsUpdate(existingTyped, desired.(*spiffev1alpha1.ClusterSPIFFEID))
+	case *policyv1.PodDisruptionBudget:
+		typeSpecificResult = PodDisruptionBudgetNeedsUpdate(existingTyped, desired.(*policyv1.PodDisruptionBudget))
+	case *networkingv1.NetworkPolicy:
+		typeSpecificResult = NetworkPolicyNeedsUpdate(existingTyped, desired.(*networkingv1.NetworkPolicy))
+	case *networkingv1.Ingress:
+		typeSpecificResult = IngressNeedsUpdate(existingTyped, desired.(*networkingv1.Ingress))
+	case *autoscalingv2.HorizontalPodAutoscaler:
+		typeSpecificResult = HorizontalPodAutoscalerNeedsUpdate(existingTyped, desired.(*autoscalingv2.HorizontalPodAutoscaler))
 	case *appsv1.StatefulSet:
 		typeSpecificResult = StatefulSetNeedsUpdate(existingTyped, desired.(*appsv1.StatefulSet))
 	case *appsv1.Deployment:
 		typeSpecificResult = DeploymentNeedsUpdate(existingTyped, desired.(*appsv1.Deployment))
 	case *appsv1.DaemonSet:
 		typeSpecificResult = DaemonSetNeedsUpdate(existingTyped, desired.(*appsv1.DaemonSet))
+	case *batchv1.CronJob:
+		typeSpecificResult = CronJobNeedsUpdate(existingTyped, desired.(*batchv1.CronJob))
 	default:
 		// For unknown types, just compare labels and annotations (already done above)
 		typeSpecificResult = false
@@ -124,6 +138,9 @@ func ServiceNeedsUpdate(existing, desired *corev1.Service) bool {
 	if !equality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
 		return true
 	}
+	if !equality.Semantic.DeepEqual(existing.Spec.LoadBalancerSourceRanges, desired.Spec.LoadBalancerSourceRanges) {
+		return true
+	}
 	return false
 }
 
@@ -408,6 +425,74 @@ func ClusterSPIFFEIDNeedsUpdate(existing, desired *spiffev1alpha1.ClusterSPIFFEI
 	return false
 }
 
+// PodDisruptionBudgetNeedsUpdate checks if a PodDisruptionBudget needs updating
+func PodDisruptionBudgetNeedsUpdate(existing, desired *policyv1.PodDisruptionBudget) bool {
+	if !equality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) ||
+		!equality.Semantic.DeepEqual(existing.Spec.MinAvailable, desired.Spec.MinAvailable) ||
+		!equality.Semantic.DeepEqual(existing.Spec.MaxUnavailable, desired.Spec.MaxUnavailable) {
+		return true
+	}
+	return false
+}
+
+// NetworkPolicyNeedsUpdate checks if a NetworkPolicy needs updating
+func NetworkPolicyNeedsUpdate(existing, desired *networkingv1.NetworkPolicy) bool {
+	if !equality.Semantic.DeepEqual(existing.Spec.PodSelector, desired.Spec.PodSelector) ||
+		!equality.Semantic.DeepEqual(existing.Spec.Ingress, desired.Spec.Ingress) ||
+		!equality.Semantic.DeepEqual(existing.Spec.Egress, desired.Spec.Egress) ||
+		!equality.Semantic.DeepEqual(existing.Spec.PolicyTypes, desired.Spec.PolicyTypes) {
+		return true
+	}
+	return false
+}
+
+// IngressNeedsUpdate checks if an Ingress needs updating
+func IngressNeedsUpdate(existing, desired *networkingv1.Ingress) bool {
+	if !ptr.Equal(existing.Spec.IngressClassName, desired.Spec.IngressClassName) ||
+		!equality.Semantic.DeepEqual(existing.Spec.Rules, desired.Spec.Rules) ||
+		!equality.Semantic.DeepEqual(existing.Spec.TLS, desired.Spec.TLS) {
+		return true
+	}
+	return false
+}
+
+// HorizontalPodAutoscalerNeedsUpdate checks if a HorizontalPodAutoscaler needs updating
+func HorizontalPodAutoscalerNeedsUpdate(existing, desired *autoscalingv2.HorizontalPodAutoscaler) bool {
+	if !equality.Semantic.DeepEqual(existing.Spec.ScaleTargetRef, desired.Spec.ScaleTargetRef) ||
+		!ptr.Equal(existing.Spec.MinReplicas, desired.Spec.MinReplicas) ||
+		existing.Spec.MaxReplicas != desired.Spec.MaxReplicas ||
+		!equality.Semantic.DeepEqual(existing.Spec.Metrics, desired.Spec.Metrics) {
+		return true
+	}
+	return false
+}
+
+// CronJobNeedsUpdate checks if a CronJob needs updating
+func CronJobNeedsUpdate(existing, desired *batchv1.CronJob) bool {
+	if existing.Spec.Schedule != desired.Spec.Schedule ||
+		existing.Spec.ConcurrencyPolicy != desired.Spec.ConcurrencyPolicy ||
+		!ptr.Equal(existing.Spec.Suspend, desired.Spec.Suspend) {
+		return true
+	}
+	dPod := desired.Spec.JobTemplate.Spec.Template.Spec
+	fPod := existing.Spec.JobTemplate.Spec.Template.Spec
+	if !equality.Semantic.DeepEqual(dPod.Affinity, fPod.Affinity) || !volumesEqual(fPod.Volumes, dPod.Volumes) {
+		return true
+	}
+	if !localObjectReferencesEqual(fPod.ImagePullSecrets, dPod.ImagePullSecrets) {
+		return true
+	}
+	if len(dPod.Containers) != len(fPod.Containers) {
+		return true
+	}
+	for i := range dPod.Containers {
+		if dPod.Containers[i].Name != fPod.Containers[i].Name || containerSpecModified(&fPod.Containers[i], &dPod.Containers[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 // volumesEqual compares two volume slices for equality
 func volumesEqual(fetched, desired []corev1.Volume) bool {
 	if len(desired) == 0 && len(fetched) == 0 {
@@ -519,6 +604,12 @@ func volumesEqual(fetched, desired []corev1.Volume) bool {
 }
 
 // containerSpecModified checks if a container spec has been modified
+// podSecurityContextEqual compares pod security contexts, an unset pod security context is defaulted to
+// an empty one by the API server
+func podSecurityContextEqual(fetched, desired *corev1.PodSecurityContext) bool {
+	return equality.Semantic.DeepEqual(ptr.Deref(fetched, corev1.PodSecurityContext{}), ptr.Deref(desired, corev1.PodSecurityContext{}))
+}
+
 func containerSpecModified(fetched, desired *corev1.Container) bool {
 	// Check basic container properties
 	if desired.Name != fetched.Name ||
@@ -541,6 +632,9 @@ func containerSpecModified(fetched, desired *corev1.Container) bool {
 	if !equality.Semantic.DeepEqual(desired.Env, fetched.Env) {
 		return true
 	}
+	if !equality.Semantic.DeepEqual(desired.EnvFrom, fetched.EnvFrom) {
+		return true
+	}
 
 	// Check ports
 	if len(desired.Ports) != len(fetched.Ports) {
@@ -559,21 +653,10 @@ func containerSpecModified(fetched, desired *corev1.Container) bool {
 		}
 	}
 
-	// ReadinessProbe nil checks
-	if (desired.ReadinessProbe == nil) != (fetched.ReadinessProbe == nil) {
-		return true
-	}
-	if desired.ReadinessProbe != nil && fetched.ReadinessProbe != nil &&
-		!equality.Semantic.DeepEqual(desired.ReadinessProbe.HTTPGet, fetched.ReadinessProbe.HTTPGet) {
-		return true
-	}
-
-	// LivenessProbe nil checks
-	if (desired.LivenessProbe == nil) != (fetched.LivenessProbe == nil) {
-		return true
-	}
-	if desired.LivenessProbe != nil && fetched.LivenessProbe != nil &&
-		!equality.Semantic.DeepEqual(desired.LivenessProbe.HTTPGet, fetched.LivenessProbe.HTTPGet) {
+	// Probe checks
+	if probeModified(fetched.ReadinessProbe, desired.ReadinessProbe) ||
+		probeModified(fetched.LivenessProbe, desired.LivenessProbe) ||
+		probeModified(fetched.StartupProbe, desired.StartupProbe) {
 		return true
 	}
 
@@ -598,6 +681,32 @@ func containerSpecModified(fetched, desired *corev1.Container) bool {
 	return false
 }
 
+// probeModified checks if the handler or the timings of a probe have been modified. The timings left unset
+// in the desired probe are defaulted by the API server.
+func probeModified(fetched, desired *corev1.Probe) bool {
+	if (desired == nil) != (fetched == nil) {
+		return true
+	}
+	if desired == nil {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(desired.HTTPGet, fetched.HTTPGet) {
+		return true
+	}
+	return desired.InitialDelaySeconds != fetched.InitialDelaySeconds ||
+		probeTiming(desired.TimeoutSeconds, 1) != probeTiming(fetched.TimeoutSeconds, 1) ||
+		probeTiming(desired.PeriodSeconds, 10) != probeTiming(fetched.PeriodSeconds, 10) ||
+		probeTiming(desired.FailureThreshold, 3) != probeTiming(fetched.FailureThreshold, 3)
+}
+
+// probeTiming returns the value of a probe timing, or its API server default when unset
+func probeTiming(value, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
 // StatefulSetNeedsUpdate checks if a StatefulSet needs updating
 func StatefulSetNeedsUpdate(fetched, desired *appsv1.StatefulSet) bool {
 	if desired == nil || fetched == nil {
@@ -634,6 +743,15 @@ func StatefulSetNeedsUpdate(fetched, desired *appsv1.StatefulSet) bool {
 	if dPod.ServiceAccountName != fPod.ServiceAccountName {
 		return true
 	}
+	if dPod.PriorityClassName != fPod.PriorityClassName {
+		return true
+	}
+	if !podSecurityContextEqual(fPod.SecurityContext, dPod.SecurityContext) {
+		return true
+	}
+	if !localObjectReferencesEqual(fPod.ImagePullSecrets, dPod.ImagePullSecrets) {
+		return true
+	}
 	if !ptr.Equal(dPod.ShareProcessNamespace, fPod.ShareProcessNamespace) {
 		return true
 	}
@@ -650,6 +768,9 @@ func StatefulSetNeedsUpdate(fetched, desired *appsv1.StatefulSet) bool {
 	if !equality.Semantic.DeepEqual(dPod.Affinity, fPod.Affinity) {
 		return true
 	}
+	if !equality.Semantic.DeepEqual(dPod.TopologySpreadConstraints, fPod.TopologySpreadConstraints) {
+		return true
+	}
 	if len(dPod.Tolerations) != len(fPod.Tolerations) {
 		return true
 	}
@@ -744,6 +865,15 @@ func DeploymentNeedsUpdate(fetched, desired *appsv1.Deployment) bool {
 	if dPod.ServiceAccountName != fPod.ServiceAccountName {
 		return true
 	}
+	if dPod.PriorityClassName != fPod.PriorityClassName {
+		return true
+	}
+	if !podSecurityContextEqual(fPod.SecurityContext, dPod.SecurityContext) {
+		return true
+	}
+	if !localObjectReferencesEqual(fPod.ImagePullSecrets, dPod.ImagePullSecrets) {
+		return true
+	}
 	if !ptr.Equal(dPod.ShareProcessNamespace, fPod.ShareProcessNamespace) {
 		return true
 	}
@@ -760,6 +890,9 @@ func DeploymentNeedsUpdate(fetched, desired *appsv1.Deployment) bool {
 	if !equality.Semantic.DeepEqual(dPod.Affinity, fPod.Affinity) {
 		return true
 	}
+	if !equality.Semantic.DeepEqual(dPod.TopologySpreadConstraints, fPod.TopologySpreadConstraints) {
+		return true
+	}
 	if len(dPod.Tolerations) != len(fPod.Tolerations) {
 		return true
 	}
@@ -815,6 +948,24 @@ func DeploymentNeedsUpdate(fetched, desired *appsv1.Deployment) bool {
 	return false
 }
 
+// daemonSetUpdateStrategyModified compares the update strategy fields set in desired, ignoring
+// the maxSurge defaulted by the API server
+func daemonSetUpdateStrategyModified(fetched, desired appsv1.DaemonSetUpdateStrategy) bool {
+	if desired.Type == "" {
+		return false
+	}
+	if desired.Type != fetched.Type {
+		return true
+	}
+	if desired.RollingUpdate == nil || desired.RollingUpdate.MaxUnavailable == nil {
+		return false
+	}
+	if fetched.RollingUpdate == nil || fetched.RollingUpdate.MaxUnavailable == nil {
+		return true
+	}
+	return desired.RollingUpdate.MaxUnavailable.String() != fetched.RollingUpdate.MaxUnavailable.String()
+}
+
 // DaemonSetNeedsUpdate checks if a DaemonSet needs updating
 func DaemonSetNeedsUpdate(fetched, desired *appsv1.DaemonSet) bool {
 	if desired == nil || fetched == nil {
@@ -828,11 +979,26 @@ func DaemonSetNeedsUpdate(fetched, desired *appsv1.DaemonSet) bool {
 	if !equality.Semantic.DeepEqual(ds.Template.Labels, fs.Template.Labels) {
 		return true
 	}
+	if ds.Template.Annotations[RequiredSCCAnnotationKey] != fs.Template.Annotations[RequiredSCCAnnotationKey] {
+		return true
+	}
+	if daemonSetUpdateStrategyModified(fs.UpdateStrategy, ds.UpdateStrategy) {
+		return true
+	}
 	dPod := ds.Template.Spec
 	fPod := fs.Template.Spec
 	if dPod.ServiceAccountName != fPod.ServiceAccountName {
 		return true
 	}
+	if dPod.PriorityClassName != fPod.PriorityClassName {
+		return true
+	}
+	if !podSecurityContextEqual(fPod.SecurityContext, dPod.SecurityContext) {
+		return true
+	}
+	if !localObjectReferencesEqual(fPod.ImagePullSecrets, dPod.ImagePullSecrets) {
+		return true
+	}
 	if !ptr.Equal(dPod.ShareProcessNamespace, fPod.ShareProcessNamespace) {
 		return true
 	}
@@ -7,7 +7,11 @@ import (
 	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -782,6 +786,35 @@ func TestContainerSpecModified(t *testing.T) {
 		}
 	})
 
+	t.Run("LivenessProbe timings modified", func(t *testing.T) {
+		fetched := createContainer()
+		desired := createContainer()
+		desired.LivenessProbe.FailureThreshold = 10
+		if !containerSpecModified(fetched, desired) {
+			t.Error("Expected true when LivenessProbe timings differ")
+		}
+	})
+
+	t.Run("LivenessProbe timings defaulted by the API server", func(t *testing.T) {
+		fetched := createContainer()
+		desired := createContainer()
+		fetched.LivenessProbe.TimeoutSeconds = 1
+		fetched.LivenessProbe.PeriodSeconds = 10
+		fetched.LivenessProbe.FailureThreshold = 3
+		if containerSpecModified(fetched, desired) {
+			t.Error("Expected false when the fetched LivenessProbe only has the default timings")
+		}
+	})
+
+	t.Run("StartupProbe nil vs non-nil", func(t *testing.T) {
+		fetched := createContainer()
+		desired := createContainer()
+		desired.StartupProbe = desired.LivenessProbe.DeepCopy()
+		if !containerSpecModified(fetched, desired) {
+			t.Error("Expected true when StartupProbe nil state differs")
+		}
+	})
+
 	t.Run("SecurityContext nil vs non-nil", func(t *testing.T) {
 		fetched := createContainer()
 		desired := createContainer()
@@ -961,6 +994,15 @@ func TestStatefulSetNeedsUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("PriorityClassName modified", func(t *testing.T) {
+		desired := createStatefulSet()
+		fetched := createStatefulSet()
+		desired.Spec.Template.Spec.PriorityClassName = "system-node-critical"
+		if !StatefulSetNeedsUpdate(fetched, desired) {
+			t.Error("Expected true when PriorityClassName differs")
+		}
+	})
+
 	t.Run("Template labels modified", func(t *testing.T) {
 		desired := createStatefulSet()
 		fetched := createStatefulSet()
@@ -1188,6 +1230,15 @@ func TestDeploymentNeedsUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("PriorityClassName modified", func(t *testing.T) {
+		desired := createDeployment()
+		fetched := createDeployment()
+		desired.Spec.Template.Spec.PriorityClassName = "system-node-critical"
+		if !DeploymentNeedsUpdate(fetched, desired) {
+			t.Error("Expected true when PriorityClassName differs")
+		}
+	})
+
 	t.Run("Template labels modified", func(t *testing.T) {
 		desired := createDeployment()
 		fetched := createDeployment()
@@ -1350,6 +1401,15 @@ func TestDaemonSetNeedsUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("PriorityClassName modified", func(t *testing.T) {
+		desired := createDaemonSet()
+		fetched := createDaemonSet()
+		desired.Spec.Template.Spec.PriorityClassName = "system-node-critical"
+		if !DaemonSetNeedsUpdate(fetched, desired) {
+			t.Error("Expected true when PriorityClassName differs")
+		}
+	})
+
 	t.Run("Template labels modified", func(t *testing.T) {
 		desired := createDaemonSet()
 		fetched := createDaemonSet()
@@ -1630,6 +1690,24 @@ func TestServiceNeedsUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("different load balancer source ranges needs update", func(t *testing.T) {
+		current := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			},
+		}
+		desired := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				LoadBalancerSourceRanges: []string{"192.168.0.0/16"},
+			},
+		}
+		if !ServiceNeedsUpdate(current, desired) {
+			t.Error("Expected true when the load balancer source ranges differ")
+		}
+	})
+
 	t.Run("different ports needs update", func(t *testing.T) {
 		current := &corev1.Service{
 			Spec: corev1.ServiceSpec{
@@ -2014,6 +2092,121 @@ func TestClusterSPIFFEIDNeedsUpdate(t *testing.T) {
 	})
 }
 
+func TestPodDisruptionBudgetNeedsUpdate(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+
+	t.Run("same PodDisruptionBudget no update", func(t *testing.T) {
+		current := &policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: ptr.To(intstr.FromInt32(1))},
+		}
+		desired := &policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: ptr.To(intstr.FromInt32(1))},
+		}
+		if PodDisruptionBudgetNeedsUpdate(current, desired) {
+			t.Error("Expected false when PodDisruptionBudgets are the same")
+		}
+	})
+
+	t.Run("switch from minAvailable to maxUnavailable needs update", func(t *testing.T) {
+		current := &policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: ptr.To(intstr.FromInt32(1))},
+		}
+		desired := &policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{Selector: selector, MaxUnavailable: ptr.To(intstr.FromString("50%"))},
+		}
+		if !PodDisruptionBudgetNeedsUpdate(current, desired) {
+			t.Error("Expected true when disruption limits differ")
+		}
+	})
+
+	t.Run("different selector needs update", func(t *testing.T) {
+		current := &policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: ptr.To(intstr.FromInt32(1))},
+		}
+		desired := &policyv1.PodDisruptionBudget{
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}},
+				MinAvailable: ptr.To(intstr.FromInt32(1)),
+			},
+		}
+		if !PodDisruptionBudgetNeedsUpdate(current, desired) {
+			t.Error("Expected true when selector differs")
+		}
+	})
+}
+
+func TestIngressNeedsUpdate(t *testing.T) {
+	newIngress := func(className, secretName string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: ptr.To(className),
+				Rules:            []networkingv1.IngressRule{{Host: "oidc.example.com"}},
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{"oidc.example.com"}, SecretName: secretName}},
+			},
+		}
+	}
+
+	t.Run("same Ingress no update", func(t *testing.T) {
+		if IngressNeedsUpdate(newIngress("nginx", "oidc-tls"), newIngress("nginx", "oidc-tls")) {
+			t.Error("Expected false when Ingresses are the same")
+		}
+	})
+
+	t.Run("different class needs update", func(t *testing.T) {
+		if !IngressNeedsUpdate(newIngress("nginx", "oidc-tls"), newIngress("haproxy", "oidc-tls")) {
+			t.Error("Expected true when IngressClass differs")
+		}
+	})
+
+	t.Run("different TLS secret needs update", func(t *testing.T) {
+		if !IngressNeedsUpdate(newIngress("nginx", "oidc-tls"), newIngress("nginx", "other-tls")) {
+			t.Error("Expected true when TLS differs")
+		}
+	})
+}
+
+func TestHorizontalPodAutoscalerNeedsUpdate(t *testing.T) {
+	newHPA := func(minReplicas, maxReplicas, cpu int32) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "test"},
+				MinReplicas:    ptr.To(minReplicas),
+				MaxReplicas:    maxReplicas,
+				Metrics: []autoscalingv2.MetricSpec{
+					{
+						Type: autoscalingv2.ResourceMetricSourceType,
+						Resource: &autoscalingv2.ResourceMetricSource{
+							Name:   corev1.ResourceCPU,
+							Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To(cpu)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("same HorizontalPodAutoscaler no update", func(t *testing.T) {
+		if HorizontalPodAutoscalerNeedsUpdate(newHPA(1, 5, 80), newHPA(1, 5, 80)) {
+			t.Error("Expected false when HorizontalPodAutoscalers are the same")
+		}
+	})
+
+	t.Run("different replica bounds needs update", func(t *testing.T) {
+		if !HorizontalPodAutoscalerNeedsUpdate(newHPA(1, 5, 80), newHPA(2, 5, 80)) {
+			t.Error("Expected true when minReplicas differs")
+		}
+		if !HorizontalPodAutoscalerNeedsUpdate(newHPA(1, 5, 80), newHPA(1, 6, 80)) {
+			t.Error("Expected true when maxReplicas differs")
+		}
+	})
+
+	t.Run("different CPU target needs update", func(t *testing.T) {
+		if !HorizontalPodAutoscalerNeedsUpdate(newHPA(1, 5, 80), newHPA(1, 5, 50)) {
+			t.Error("Expected true when CPU utilization target differs")
+		}
+	})
+}
+
 // TestResourceNeedsUpdate_AllScenarios tests ResourceNeedsUpdate with table-driven tests
 func TestResourceNeedsUpdate_AllScenarios(t *testing.T) {
 	tests := []struct {
@@ -2671,3 +2864,86 @@ func TestSecurityContextConstraintsNeedsUpdate_AllScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestDaemonSetUpdateStrategyModified(t *testing.T) {
+	rollingUpdate := func(maxUnavailable intstr.IntOrString) appsv1.DaemonSetUpdateStrategy {
+		return appsv1.DaemonSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+		}
+	}
+	defaulted := rollingUpdate(intstr.FromInt32(1))
+	defaulted.RollingUpdate.MaxSurge = ptr.To(intstr.FromInt32(0))
+
+	tests := []struct {
+		name     string
+		fetched  appsv1.DaemonSetUpdateStrategy
+		desired  appsv1.DaemonSetUpdateStrategy
+		expected bool
+	}{
+		{name: "unset desired strategy", fetched: rollingUpdate(intstr.FromInt32(1)), expected: false},
+		{name: "defaulted maxSurge is ignored", fetched: defaulted, desired: rollingUpdate(intstr.FromInt32(1)), expected: false},
+		{name: "maxUnavailable changed", fetched: rollingUpdate(intstr.FromInt32(1)), desired: rollingUpdate(intstr.FromString("10%")), expected: true},
+		{
+			name:     "type changed",
+			fetched:  rollingUpdate(intstr.FromInt32(1)),
+			desired:  appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daemonSetUpdateStrategyModified(tt.fetched, tt.desired); got != tt.expected {
+				t.Errorf("daemonSetUpdateStrategyModified() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCronJobNeedsUpdate(t *testing.T) {
+	createCronJob := func() *batchv1.CronJob {
+		return &batchv1.CronJob{
+			Spec: batchv1.CronJobSpec{
+				Schedule:          "0 2 * * *",
+				ConcurrencyPolicy: batchv1.ForbidConcurrent,
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "backup", Image: "backup:v1"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*batchv1.CronJob)
+		expected bool
+	}{
+		{name: "no changes", modify: func(c *batchv1.CronJob) {}, expected: false},
+		{name: "schedule changed", modify: func(c *batchv1.CronJob) { c.Spec.Schedule = "0 3 * * *" }, expected: true},
+		{name: "suspended", modify: func(c *batchv1.CronJob) { c.Spec.Suspend = ptr.To(true) }, expected: true},
+		{
+			name: "image changed",
+			modify: func(c *batchv1.CronJob) {
+				c.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image = "backup:v2"
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := createCronJob()
+			tt.modify(desired)
+			if got := CronJobNeedsUpdate(createCronJob(), desired); got != tt.expected {
+				t.Errorf("CronJobNeedsUpdate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
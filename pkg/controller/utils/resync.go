@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultResyncInterval matches the controller-runtime cache resync period used when the
+// operator --resync-interval flag is not set
+const DefaultResyncInterval = 10 * time.Hour
+
+// operatorResyncInterval holds the operator wide interval set from the --resync-interval flag.
+// It is zero until SetOperatorResyncInterval is called, in which case reconciliations rely
+// on the cache resync alone.
+var operatorResyncInterval atomic.Int64
+
+// SetOperatorResyncInterval sets the operator wide resync interval used when neither the
+// operand nor the ZeroTrustWorkloadIdentityManager configures one
+func SetOperatorResyncInterval(interval time.Duration) {
+	operatorResyncInterval.Store(int64(interval))
+}
+
+// ResyncInterval returns how long to wait before re-reconciling an operand to repair drift.
+// The operand override takes precedence over the ZeroTrustWorkloadIdentityManager setting,
+// which takes precedence over the operator flag. Zero means no periodic requeue.
+func ResyncInterval(override, global *metav1.Duration) time.Duration {
+	for _, interval := range []*metav1.Duration{override, global} {
+		if interval != nil && interval.Duration > 0 {
+			return interval.Duration
+		}
+	}
+	return time.Duration(operatorResyncInterval.Load())
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResyncInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *metav1.Duration
+		global   *metav1.Duration
+		operator time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "nothing configured",
+			expected: 0,
+		},
+		{
+			name:     "operator flag is the fallback",
+			operator: time.Hour,
+			expected: time.Hour,
+		},
+		{
+			name:     "ztwim setting overrides the operator flag",
+			global:   &metav1.Duration{Duration: 30 * time.Minute},
+			operator: time.Hour,
+			expected: 30 * time.Minute,
+		},
+		{
+			name:     "operand override takes precedence",
+			override: &metav1.Duration{Duration: 5 * time.Minute},
+			global:   &metav1.Duration{Duration: 30 * time.Minute},
+			operator: time.Hour,
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "zero override is ignored",
+			override: &metav1.Duration{},
+			global:   &metav1.Duration{Duration: 30 * time.Minute},
+			expected: 30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetOperatorResyncInterval(tt.operator)
+			t.Cleanup(func() { SetOperatorResyncInterval(0) })
+
+			if got := ResyncInterval(tt.override, tt.global); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+
+	securityv1 "github.com/openshift/api/security/v1"
+)
+
+// RequiredSCCAnnotationKey is the pod annotation requiring the SecurityContextConstraints the pod is admitted with
+const RequiredSCCAnnotationKey = "openshift.io/required-scc"
+
+// GetSecurityContextConstraintsName returns the name of the SecurityContextConstraints the pods of an operand are
+// admitted with: the SecurityContextConstraints referenced by the operand, or the one created by the operator
+func GetSecurityContextConstraintsName(referenced, defaultName string) string {
+	if referenced != "" {
+		return referenced
+	}
+	return defaultName
+}
+
+// ValidateSecurityContextConstraints validates that scc, referenced by an operand, grants the host access and
+// the volume types granted by required, the SecurityContextConstraints the operator creates for the operand
+func ValidateSecurityContextConstraints(scc, required *securityv1.SecurityContextConstraints) error {
+	checks := []struct {
+		field    string
+		required bool
+		allowed  bool
+	}{
+		{"allowHostDirVolumePlugin", required.AllowHostDirVolumePlugin, scc.AllowHostDirVolumePlugin},
+		{"allowPrivilegedContainer", required.AllowPrivilegedContainer, scc.AllowPrivilegedContainer},
+		{"allowHostPID", required.AllowHostPID, scc.AllowHostPID},
+		{"allowHostNetwork", required.AllowHostNetwork, scc.AllowHostNetwork},
+		{"allowHostPorts", required.AllowHostPorts, scc.AllowHostPorts},
+	}
+	for _, check := range checks {
+		if check.required && !check.allowed {
+			return fmt.Errorf("SecurityContextConstraints %q: %s must be true for the operand pods", scc.Name, check.field)
+		}
+	}
+	if slices.Contains(scc.Volumes, securityv1.FSTypeAll) {
+		return nil
+	}
+	for _, volume := range required.Volumes {
+		if !slices.Contains(scc.Volumes, volume) {
+			return fmt.Errorf("SecurityContextConstraints %q: volumes must allow %s for the operand pods", scc.Name, volume)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateSecurityContextConstraints(t *testing.T) {
+	required := &securityv1.SecurityContextConstraints{
+		AllowHostDirVolumePlugin: true,
+		AllowPrivilegedContainer: true,
+		AllowHostPID:             true,
+		Volumes:                  []securityv1.FSType{securityv1.FSTypeHostPath, securityv1.FSTypeSecret},
+	}
+
+	tests := []struct {
+		name        string
+		scc         securityv1.SecurityContextConstraints
+		expectedErr string
+	}{
+		{
+			name: "same access",
+			scc:  *required,
+		},
+		{
+			name: "any volume type",
+			scc: securityv1.SecurityContextConstraints{
+				AllowHostDirVolumePlugin: true,
+				AllowPrivilegedContainer: true,
+				AllowHostPID:             true,
+				AllowHostNetwork:         true,
+				Volumes:                  []securityv1.FSType{securityv1.FSTypeAll},
+			},
+		},
+		{
+			name: "no host PID",
+			scc: securityv1.SecurityContextConstraints{
+				AllowHostDirVolumePlugin: true,
+				AllowPrivilegedContainer: true,
+				Volumes:                  []securityv1.FSType{securityv1.FSTypeAll},
+			},
+			expectedErr: "allowHostPID",
+		},
+		{
+			name: "missing volume type",
+			scc: securityv1.SecurityContextConstraints{
+				AllowHostDirVolumePlugin: true,
+				AllowPrivilegedContainer: true,
+				AllowHostPID:             true,
+				Volumes:                  []securityv1.FSType{securityv1.FSTypeSecret},
+			},
+			expectedErr: "volumes must allow hostPath",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.scc.ObjectMeta = metav1.ObjectMeta{Name: "custom"}
+			err := ValidateSecurityContextConstraints(&tt.scc, required)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGetSecurityContextConstraintsName(t *testing.T) {
+	if got := GetSecurityContextConstraintsName("", "spire-agent"); got != "spire-agent" {
+		t.Errorf("Expected the SCC of the operator, got %q", got)
+	}
+	if got := GetSecurityContextConstraintsName("node-agents", "spire-agent"); got != "node-agents" {
+		t.Errorf("Expected the referenced SCC, got %q", got)
+	}
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateSecurityContext validates the security context overrides of an operand. privileged is set for
+// the operands accessing host paths, whose main container must stay privileged and run as root.
+func ValidateSecurityContext(sc *corev1.SecurityContext, podSC *corev1.PodSecurityContext, privileged bool) error {
+	if sc != nil {
+		if err := validateIDs("securityContext", sc.RunAsUser, sc.RunAsGroup); err != nil {
+			return err
+		}
+		if err := validateSeccompProfile("securityContext.seccompProfile", sc.SeccompProfile); err != nil {
+			return err
+		}
+		if sc.AllowPrivilegeEscalation != nil && !*sc.AllowPrivilegeEscalation && sc.Privileged != nil && *sc.Privileged {
+			return fmt.Errorf("securityContext: allowPrivilegeEscalation can't be false for a privileged container")
+		}
+		if privileged {
+			switch {
+			case sc.Privileged != nil && !*sc.Privileged:
+				return fmt.Errorf("securityContext.privileged: must not be false, the container requires privileged access to host paths")
+			case sc.AllowPrivilegeEscalation != nil && !*sc.AllowPrivilegeEscalation:
+				return fmt.Errorf("securityContext.allowPrivilegeEscalation: must not be false, the container requires privileged access to host paths")
+			case sc.RunAsNonRoot != nil && *sc.RunAsNonRoot:
+				return fmt.Errorf("securityContext.runAsNonRoot: must not be true, the container requires root access to host paths")
+			}
+		}
+	}
+
+	if podSC != nil {
+		if err := validateIDs("podSecurityContext", podSC.RunAsUser, podSC.RunAsGroup); err != nil {
+			return err
+		}
+		if podSC.FSGroup != nil {
+			if errs := validation.IsValidGroupID(*podSC.FSGroup); len(errs) > 0 {
+				return fmt.Errorf("podSecurityContext.fsGroup: %s", strings.Join(errs, "; "))
+			}
+		}
+		if err := validateSeccompProfile("podSecurityContext.seccompProfile", podSC.SeccompProfile); err != nil {
+			return err
+		}
+		if privileged && podSC.RunAsNonRoot != nil && *podSC.RunAsNonRoot {
+			return fmt.Errorf("podSecurityContext.runAsNonRoot: must not be true, the container requires root access to host paths")
+		}
+	}
+	return nil
+}
+
+// validateIDs validates the user and group IDs of a security context
+func validateIDs(path string, runAsUser, runAsGroup *int64) error {
+	if runAsUser != nil {
+		if errs := validation.IsValidUserID(*runAsUser); len(errs) > 0 {
+			return fmt.Errorf("%s.runAsUser: %s", path, strings.Join(errs, "; "))
+		}
+	}
+	if runAsGroup != nil {
+		if errs := validation.IsValidGroupID(*runAsGroup); len(errs) > 0 {
+			return fmt.Errorf("%s.runAsGroup: %s", path, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// validateSeccompProfile validates that a localhost profile is set for, and only for, the Localhost type
+func validateSeccompProfile(path string, profile *corev1.SeccompProfile) error {
+	if profile == nil {
+		return nil
+	}
+	switch profile.Type {
+	case corev1.SeccompProfileTypeLocalhost:
+		if profile.LocalhostProfile == nil || *profile.LocalhostProfile == "" {
+			return fmt.Errorf("%s.localhostProfile: required for the Localhost type", path)
+		}
+	case corev1.SeccompProfileTypeRuntimeDefault, corev1.SeccompProfileTypeUnconfined:
+		if profile.LocalhostProfile != nil {
+			return fmt.Errorf("%s.localhostProfile: only allowed for the Localhost type", path)
+		}
+	default:
+		return fmt.Errorf("%s.type: unsupported type %q", path, profile.Type)
+	}
+	return nil
+}
+
+// ApplySecurityContext sets the pod security context of an operand in podSpec, and overrides the security
+// context of its container named containerName: the fields set in sc replace the fields set by the operator.
+func ApplySecurityContext(podSpec *corev1.PodSpec, containerName string, sc *corev1.SecurityContext, podSC *corev1.PodSecurityContext) error {
+	if podSC != nil {
+		podSpec.SecurityContext = podSC.DeepCopy()
+	}
+	if sc == nil {
+		return nil
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != containerName {
+			continue
+		}
+		container.SecurityContext = mergeSecurityContext(container.SecurityContext, sc)
+		return nil
+	}
+	return fmt.Errorf("container %q not found", containerName)
+}
+
+// mergeSecurityContext returns base with the fields set in override replaced
+func mergeSecurityContext(base, override *corev1.SecurityContext) *corev1.SecurityContext {
+	merged := base.DeepCopy()
+	if merged == nil {
+		merged = &corev1.SecurityContext{}
+	}
+	o := override.DeepCopy()
+	if o.Capabilities != nil {
+		merged.Capabilities = o.Capabilities
+	}
+	if o.Privileged != nil {
+		merged.Privileged = o.Privileged
+	}
+	if o.SELinuxOptions != nil {
+		merged.SELinuxOptions = o.SELinuxOptions
+	}
+	if o.WindowsOptions != nil {
+		merged.WindowsOptions = o.WindowsOptions
+	}
+	if o.RunAsUser != nil {
+		merged.RunAsUser = o.RunAsUser
+	}
+	if o.RunAsGroup != nil {
+		merged.RunAsGroup = o.RunAsGroup
+	}
+	if o.RunAsNonRoot != nil {
+		merged.RunAsNonRoot = o.RunAsNonRoot
+	}
+	if o.ReadOnlyRootFilesystem != nil {
+		merged.ReadOnlyRootFilesystem = o.ReadOnlyRootFilesystem
+	}
+	if o.AllowPrivilegeEscalation != nil {
+		merged.AllowPrivilegeEscalation = o.AllowPrivilegeEscalation
+	}
+	if o.ProcMount != nil {
+		merged.ProcMount = o.ProcMount
+	}
+	if o.SeccompProfile != nil {
+		merged.SeccompProfile = o.SeccompProfile
+	}
+	if o.AppArmorProfile != nil {
+		merged.AppArmorProfile = o.AppArmorProfile
+	}
+	return merged
+}
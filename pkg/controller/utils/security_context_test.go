@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateSecurityContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		sc          *corev1.SecurityContext
+		podSC       *corev1.PodSecurityContext
+		privileged  bool
+		expectedErr string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name: "restricted profile",
+			sc: &corev1.SecurityContext{
+				RunAsNonRoot:             ptr.To(true),
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			},
+			podSC: &corev1.PodSecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
+		},
+		{
+			name:       "seccomp profile of a privileged container",
+			sc:         &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: ptr.To("spire-agent.json")}},
+			privileged: true,
+		},
+		{
+			name:        "invalid user",
+			sc:          &corev1.SecurityContext{RunAsUser: ptr.To(int64(-1))},
+			expectedErr: "securityContext.runAsUser",
+		},
+		{
+			name:        "invalid fsGroup",
+			podSC:       &corev1.PodSecurityContext{FSGroup: ptr.To(int64(-1))},
+			expectedErr: "podSecurityContext.fsGroup",
+		},
+		{
+			name:        "localhost seccomp profile without profile",
+			podSC:       &corev1.PodSecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost}},
+			expectedErr: "podSecurityContext.seccompProfile.localhostProfile",
+		},
+		{
+			name:        "unsupported seccomp profile type",
+			sc:          &corev1.SecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: "Custom"}},
+			expectedErr: "securityContext.seccompProfile.type",
+		},
+		{
+			name:        "privilege escalation of a privileged container",
+			sc:          &corev1.SecurityContext{Privileged: ptr.To(true), AllowPrivilegeEscalation: ptr.To(false)},
+			expectedErr: "allowPrivilegeEscalation",
+		},
+		{
+			name:        "unprivileged host path container",
+			sc:          &corev1.SecurityContext{Privileged: ptr.To(false)},
+			privileged:  true,
+			expectedErr: "securityContext.privileged",
+		},
+		{
+			name:        "non root host path container",
+			sc:          &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)},
+			privileged:  true,
+			expectedErr: "securityContext.runAsNonRoot",
+		},
+		{
+			name:        "non root host path pod",
+			podSC:       &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true)},
+			privileged:  true,
+			expectedErr: "podSecurityContext.runAsNonRoot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecurityContext(tt.sc, tt.podSC, tt.privileged)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplySecurityContext(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "spiffe-csi-driver", SecurityContext: &corev1.SecurityContext{
+					Privileged:   ptr.To(true),
+					Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"all"}},
+				}},
+				{Name: "node-driver-registrar"},
+			},
+		}
+	}
+	seccomp := &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+
+	t.Run("overrides the fields set", func(t *testing.T) {
+		podSpec := newPodSpec()
+		sc := &corev1.SecurityContext{SeccompProfile: seccomp, ReadOnlyRootFilesystem: ptr.To(true)}
+		podSC := &corev1.PodSecurityContext{SeccompProfile: seccomp}
+		if err := ApplySecurityContext(podSpec, "spiffe-csi-driver", sc, podSC); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		expected := &corev1.SecurityContext{
+			Privileged:             ptr.To(true),
+			Capabilities:           &corev1.Capabilities{Drop: []corev1.Capability{"all"}},
+			ReadOnlyRootFilesystem: ptr.To(true),
+			SeccompProfile:         seccomp,
+		}
+		if !equality.Semantic.DeepEqual(expected, podSpec.Containers[0].SecurityContext) {
+			t.Errorf("Expected security context %v, got %v", expected, podSpec.Containers[0].SecurityContext)
+		}
+		if podSpec.Containers[1].SecurityContext != nil {
+			t.Errorf("Expected the other containers to be left unchanged, got %v", podSpec.Containers[1].SecurityContext)
+		}
+		if !equality.Semantic.DeepEqual(podSC, podSpec.SecurityContext) {
+			t.Errorf("Expected pod security context %v, got %v", podSC, podSpec.SecurityContext)
+		}
+	})
+
+	t.Run("nothing configured", func(t *testing.T) {
+		podSpec := newPodSpec()
+		if err := ApplySecurityContext(podSpec, "spiffe-csi-driver", nil, nil); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if !equality.Semantic.DeepEqual(newPodSpec(), podSpec) {
+			t.Errorf("Expected the pod spec to be left unchanged, got %v", podSpec)
+		}
+	})
+
+	t.Run("unknown container", func(t *testing.T) {
+		err := ApplySecurityContext(newPodSpec(), "spire-agent", &corev1.SecurityContext{}, nil)
+		if err == nil || !strings.Contains(err.Error(), "spire-agent") {
+			t.Errorf("Expected a container not found error, got: %v", err)
+		}
+	})
+}
+
+func TestPodSecurityContextEqual(t *testing.T) {
+	if !podSecurityContextEqual(&corev1.PodSecurityContext{}, nil) {
+		t.Error("Expected an empty pod security context to match an unset one")
+	}
+	if podSecurityContextEqual(&corev1.PodSecurityContext{}, &corev1.PodSecurityContext{RunAsNonRoot: ptr.To(true)}) {
+		t.Error("Expected a changed pod security context to be detected")
+	}
+}
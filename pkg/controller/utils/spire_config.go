@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+)
+
+// pluginTypeRule constrains the plugins of a type in a SPIRE config
+type pluginTypeRule struct {
+	// min and max are the number of enabled plugins of the type accepted, max is unbounded when negative.
+	// The plugins the operator only renders when enabled in the spec are not required.
+	min, max int
+	// builtins are the plugins of the type built into SPIRE, the other plugins must set plugin_cmd
+	builtins []string
+}
+
+// spireNodeAttestors are the node attestors built into both the SPIRE server and agent
+var spireNodeAttestors = []string{
+	"aws_iid", "azure_imds", "azure_msi", "gcp_iit", "http_challenge", "join_token", "k8s_psat", "k8s_sat",
+	"sshpop", "tpm_devid", "x509pop",
+}
+
+// spireServerPlugins are the plugin types of the SPIRE server
+var spireServerPlugins = map[string]pluginTypeRule{
+	"BundlePublisher":    {min: 0, max: -1, builtins: []string{"aws_rolesanywhere_trustanchor", "aws_s3", "gcp_cloudstorage", "k8s_configmap"}},
+	"CredentialComposer": {min: 0, max: -1, builtins: []string{"uniqueid"}},
+	"DataStore":          {min: 1, max: 1, builtins: []string{"sql"}},
+	"KeyManager":         {min: 1, max: 1, builtins: []string{"aws_kms", "azure_key_vault", "disk", "gcp_kms", "memory"}},
+	"NodeAttestor":       {min: 0, max: -1, builtins: spireNodeAttestors},
+	"Notifier":           {min: 0, max: -1, builtins: []string{"gcs_bundle", "k8sbundle"}},
+	"UpstreamAuthority":  {min: 0, max: 1, builtins: []string{"aws_pca", "awssecret", "cert-manager", "disk", "ejbca", "gcp_cas", "spire", "vault"}},
+}
+
+// spireAgentPlugins are the plugin types of the SPIRE agent
+var spireAgentPlugins = map[string]pluginTypeRule{
+	"KeyManager":       {min: 1, max: 1, builtins: []string{"disk", "memory"}},
+	"NodeAttestor":     {min: 0, max: 1, builtins: spireNodeAttestors},
+	"SVIDStore":        {min: 0, max: -1, builtins: []string{"aws_secretsmanager", "gcp_secretmanager"}},
+	"WorkloadAttestor": {min: 0, max: -1, builtins: []string{"docker", "k8s", "systemd", "unix", "windows"}},
+}
+
+// ValidateSpireServerConfig checks that the rendered SPIRE server config is accepted by the SPIRE server,
+// so that an invalid config is reported instead of crash-looping the SPIRE server pods
+func ValidateSpireServerConfig(data string) error {
+	return validateSpireConfig(data, "server", spireServerPlugins)
+}
+
+// ValidateSpireAgentConfig checks that the rendered SPIRE agent config is accepted by the SPIRE agent,
+// so that an invalid config is reported instead of crash-looping the SPIRE agent pods
+func ValidateSpireAgentConfig(data string) error {
+	return validateSpireConfig(data, "agent", spireAgentPlugins)
+}
+
+// validateSpireConfig parses data with the HCL parser of SPIRE, and checks its top level keys, its
+// section and its plugins against pluginTypes
+func validateSpireConfig(data, section string, pluginTypes map[string]pluginTypeRule) error {
+	// The SPIRE config is rendered as JSON, which SPIRE parses as HCL
+	if _, err := hcl.Parse(data); err != nil {
+		return fmt.Errorf("failed to parse the config: %w", err)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		return fmt.Errorf("failed to parse the config: %w", err)
+	}
+
+	var problems []string
+	for key := range config {
+		if key != section && key != "plugins" && key != "telemetry" && key != "health_checks" {
+			problems = append(problems, fmt.Sprintf("unknown top level key %q", key))
+		}
+	}
+	if _, ok := config[section].(map[string]interface{}); !ok {
+		problems = append(problems, fmt.Sprintf("the %s section must be an object", section))
+	}
+	problems = append(problems, validateSpirePlugins(config["plugins"], pluginTypes)...)
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateSpirePlugins returns the problems of the plugins of a SPIRE config. The plugins of a type are
+// either a list of objects or an object, keyed by the plugin name.
+func validateSpirePlugins(plugins interface{}, pluginTypes map[string]pluginTypeRule) []string {
+	pluginsByType, ok := plugins.(map[string]interface{})
+	if !ok {
+		return []string{"plugins must be an object"}
+	}
+
+	var problems []string
+	enabled := make(map[string]int, len(pluginTypes))
+	for pluginType, value := range pluginsByType {
+		rule, known := pluginTypes[pluginType]
+		if !known {
+			problems = append(problems, fmt.Sprintf("unknown plugin type %q", pluginType))
+			continue
+		}
+		var entries []interface{}
+		switch typed := value.(type) {
+		case []interface{}:
+			entries = typed
+		case map[string]interface{}:
+			entries = []interface{}{typed}
+		default:
+			problems = append(problems, fmt.Sprintf("%s plugins must be a list of objects", pluginType))
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, entry := range entries {
+			namedPlugins, ok := entry.(map[string]interface{})
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s plugins must be a list of objects", pluginType))
+				continue
+			}
+			for name, pluginValue := range namedPlugins {
+				if seen[name] {
+					problems = append(problems, fmt.Sprintf("%s plugin %q is configured more than once", pluginType, name))
+					continue
+				}
+				seen[name] = true
+				plugin, ok := pluginValue.(map[string]interface{})
+				if !ok {
+					problems = append(problems, fmt.Sprintf("%s plugin %q must be an object", pluginType, name))
+					continue
+				}
+				if isEnabled, set := plugin["enabled"].(bool); set && !isEnabled {
+					continue
+				}
+				enabled[pluginType]++
+				problems = append(problems, validateSpirePlugin(pluginType, name, plugin, rule)...)
+			}
+		}
+	}
+
+	for pluginType, rule := range pluginTypes {
+		count := enabled[pluginType]
+		switch {
+		case rule.min == rule.max && count != rule.min:
+			problems = append(problems, fmt.Sprintf("exactly %d %s plugin must be configured, got %d", rule.min, pluginType, count))
+		case count < rule.min:
+			problems = append(problems, fmt.Sprintf("at least %d %s plugin must be configured, got %d", rule.min, pluginType, count))
+		case rule.max >= 0 && count > rule.max:
+			problems = append(problems, fmt.Sprintf("at most %d %s plugin may be configured, got %d", rule.max, pluginType, count))
+		}
+	}
+	return problems
+}
+
+// validateSpirePlugin returns the problems of the settings of an enabled plugin
+func validateSpirePlugin(pluginType, name string, plugin map[string]interface{}, rule pluginTypeRule) []string {
+	var problems []string
+	pluginCmd, _ := plugin["plugin_cmd"].(string)
+	if pluginCmd == "" && !slices.Contains(rule.builtins, name) {
+		problems = append(problems, fmt.Sprintf("%s plugin %q is not built into SPIRE, plugin_cmd must be set for an external plugin", pluginType, name))
+	}
+	if _, set := plugin["plugin_checksum"]; set && pluginCmd == "" {
+		problems = append(problems, fmt.Sprintf("%s plugin %q sets plugin_checksum without plugin_cmd", pluginType, name))
+	}
+	if pluginData := plugin["plugin_data"]; pluginData != nil {
+		if _, isObject := pluginData.(map[string]interface{}); !isObject {
+			problems = append(problems, fmt.Sprintf("%s plugin %q plugin_data must be an object", pluginType, name))
+		}
+		if _, setFile := plugin["plugin_data_file"]; setFile {
+			problems = append(problems, fmt.Sprintf("%s plugin %q sets both plugin_data and plugin_data_file", pluginType, name))
+		}
+	}
+	return problems
+}
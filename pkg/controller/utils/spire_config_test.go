@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSpireServerConfig(t *testing.T) {
+	const validPlugins = `"DataStore":[{"sql":{"plugin_data":{"database_type":"postgres"}}}],"KeyManager":[{"disk":{"plugin_data":{"keys_path":"/run/spire/data/keys.json"}}}]`
+
+	tests := []struct {
+		name        string
+		config      string
+		expectedErr string
+	}{
+		{
+			name:   "valid config",
+			config: `{"server":{"trust_domain":"example.org"},"plugins":{` + validPlugins + `,"NodeAttestor":[{"k8s_psat":{"plugin_data":{}}}]}}`,
+		},
+		{
+			name:   "external plugin with plugin_cmd",
+			config: `{"server":{},"plugins":{` + validPlugins + `,"UpstreamAuthority":[{"custom_ca":{"plugin_cmd":"/plugins/custom_ca","plugin_checksum":"abc"}}]}}`,
+		},
+		{
+			name:   "disabled plugin is not counted",
+			config: `{"server":{},"plugins":{` + validPlugins + `,"UpstreamAuthority":[{"vault":{}},{"disk":{"enabled":false}}]}}`,
+		},
+		{
+			name:        "unparsable config",
+			config:      `{"server":`,
+			expectedErr: "failed to parse the config",
+		},
+		{
+			name:        "unknown top level key",
+			config:      `{"server":{},"servers":{},"plugins":{` + validPlugins + `}}`,
+			expectedErr: `unknown top level key "servers"`,
+		},
+		{
+			name:        "unknown plugin type",
+			config:      `{"server":{},"plugins":{` + validPlugins + `,"Notifiers":[{"k8sbundle":{}}]}}`,
+			expectedErr: `unknown plugin type "Notifiers"`,
+		},
+		{
+			name:        "external plugin without plugin_cmd",
+			config:      `{"server":{},"plugins":{` + validPlugins + `,"UpstreamAuthority":[{"aws_secret":{}}]}}`,
+			expectedErr: `UpstreamAuthority plugin "aws_secret" is not built into SPIRE`,
+		},
+		{
+			name:        "second key manager",
+			config:      `{"server":{},"plugins":{` + validPlugins + `,"KeyManager":[{"disk":{}},{"memory":{}}]}}`,
+			expectedErr: "exactly 1 KeyManager plugin must be configured, got 2",
+		},
+		{
+			name:        "missing datastore",
+			config:      `{"server":{},"plugins":{"KeyManager":[{"memory":{}}]}}`,
+			expectedErr: "exactly 1 DataStore plugin must be configured, got 0",
+		},
+		{
+			name:        "plugin_data and plugin_data_file",
+			config:      `{"server":{},"plugins":{` + validPlugins + `,"Notifier":[{"k8sbundle":{"plugin_data":{},"plugin_data_file":"/etc/k8sbundle.conf"}}]}}`,
+			expectedErr: `Notifier plugin "k8sbundle" sets both plugin_data and plugin_data_file`,
+		},
+		{
+			name:        "plugin_checksum without plugin_cmd",
+			config:      `{"server":{},"plugins":{` + validPlugins + `,"Notifier":[{"k8sbundle":{"plugin_checksum":"abc"}}]}}`,
+			expectedErr: `Notifier plugin "k8sbundle" sets plugin_checksum without plugin_cmd`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSpireServerConfig(tt.config)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSpireAgentConfig(t *testing.T) {
+	if err := ValidateSpireAgentConfig(`{"agent":{"trust_domain":"example.org"},"plugins":{"KeyManager":[{"memory":{"plugin_data":null}}],"WorkloadAttestor":[{"k8s":{}},{"unix":{}}]}}`); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	err := ValidateSpireAgentConfig(`{"agent":"example.org","plugins":{"KeyManager":[{"memory":{}}],"NodeAttestor":[{"k8s_psat":{}},{"join_token":{}}]}}`)
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	for _, expected := range []string{"the agent section must be an object", "at most 1 NodeAttestor plugin may be configured, got 2"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error containing %q, got: %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/version"
+)
+
+// SpireOperand is an operand running a SPIRE release, whose version can be pinned on its CR
+type SpireOperand struct {
+	// Name is the name of the operand in the validation errors
+	Name string
+	// DefaultVersion is the SPIRE version of the images set by ImageEnv and FIPSImageEnv
+	DefaultVersion string
+	// ImageEnv and FIPSImageEnv are the image environment variables of the default version
+	ImageEnv, FIPSImageEnv string
+}
+
+// The operands running a SPIRE release
+var (
+	SpireServerOperand = SpireOperand{
+		Name:           "SPIRE server",
+		DefaultVersion: version.SpireServerVersion,
+		ImageEnv:       SpireServerImageEnv,
+		FIPSImageEnv:   SpireServerFIPSImageEnv,
+	}
+	SpireAgentOperand = SpireOperand{
+		Name:           "SPIRE agent",
+		DefaultVersion: version.SpireAgentVersion,
+		ImageEnv:       SpireAgentImageEnv,
+		FIPSImageEnv:   SpireAgentFIPSImageEnv,
+	}
+	SpireOIDCDiscoveryProviderOperand = SpireOperand{
+		Name:           "SPIRE OIDC discovery provider",
+		DefaultVersion: version.SpireOIDCDiscoveryProviderVersion,
+		ImageEnv:       SpireOIDCDiscoveryProviderImageEnv,
+		FIPSImageEnv:   SpireOIDCDiscoveryProviderFIPSImageEnv,
+	}
+)
+
+// SupportedSpireVersions returns the SPIRE versions the operands can be pinned to
+func SupportedSpireVersions() []string {
+	var versions []string
+	for _, v := range strings.Split(version.SupportedSpireVersions, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// VersionedImageEnv returns the environment variable setting the image of env for a SPIRE version,
+// e.g. RELATED_IMAGE_SPIRE_SERVER_1_12_4
+func VersionedImageEnv(env, spireVersion string) string {
+	return env + "_" + strings.ReplaceAll(spireVersion, ".", "_")
+}
+
+// VersionedImageEnvNames returns the environment variables setting the images of the supported SPIRE
+// versions of each operand, other than its default version
+func VersionedImageEnvNames() []string {
+	var names []string
+	for _, operand := range []SpireOperand{SpireServerOperand, SpireAgentOperand, SpireOIDCDiscoveryProviderOperand} {
+		for _, v := range SupportedSpireVersions() {
+			if v != operand.DefaultVersion {
+				names = append(names, VersionedImageEnv(operand.ImageEnv, v), VersionedImageEnv(operand.FIPSImageEnv, v))
+			}
+		}
+	}
+	return names
+}
+
+// patchVersion returns the patch part of a version like "1.13.3", or -1 when there is none
+func patchVersion(v string) int {
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) < 3 {
+		return -1
+	}
+	patch, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return -1
+	}
+	return patch
+}
+
+// ResolveVersion returns the supported SPIRE version selected by the requested version of the operand CR:
+// the default version when empty, the version itself when it is supported, or the latest supported patch
+// release of a requested minor version like "1.12"
+func (o SpireOperand) ResolveVersion(requested string) (string, error) {
+	if requested == "" || requested == o.DefaultVersion {
+		return o.DefaultVersion, nil
+	}
+	supported := SupportedSpireVersions()
+	requestedMinor, err := parseMinorVersion(requested)
+	if err != nil {
+		return "", fmt.Errorf("%s version %q is invalid: %w", o.Name, requested, err)
+	}
+	resolved := ""
+	for _, v := range supported {
+		if v == requested {
+			return v, nil
+		}
+		minor, err := parseMinorVersion(v)
+		if err != nil || minor != requestedMinor || patchVersion(requested) >= 0 {
+			continue
+		}
+		if resolved == "" || patchVersion(v) > patchVersion(resolved) {
+			resolved = v
+		}
+	}
+	if resolved == "" {
+		return "", fmt.Errorf("%s version %s is not supported, supported versions are %s", o.Name, requested, strings.Join(supported, ", "))
+	}
+	return resolved, nil
+}
+
+// ValidateVersion returns an error when the requested version of the operand CR is not supported,
+// or when no image is set for the version it selects
+func (o SpireOperand) ValidateVersion(requested string) error {
+	resolved, err := o.ResolveVersion(requested)
+	if err != nil {
+		return err
+	}
+	if resolved == o.DefaultVersion {
+		return nil
+	}
+	if env := VersionedImageEnv(o.ImageEnv, resolved); imageFromEnv(env) == "" {
+		return fmt.Errorf("%s version %s is not available, the %s image is not set", o.Name, resolved, env)
+	}
+	return nil
+}
+
+// FIPSImageEnvFor returns the FIPS image environment variable of the SPIRE version selected by the
+// requested version of the operand CR, to be checked with ValidateFIPSImages
+func (o SpireOperand) FIPSImageEnvFor(requested string) string {
+	resolved := o.Version(requested)
+	if resolved == o.DefaultVersion {
+		return o.FIPSImageEnv
+	}
+	return VersionedImageEnv(o.FIPSImageEnv, resolved)
+}
+
+// Version returns the SPIRE version selected by the requested version of the operand CR, falling back
+// to the default version when it is not supported
+func (o SpireOperand) Version(requested string) string {
+	resolved, err := o.ResolveVersion(requested)
+	if err != nil {
+		return o.DefaultVersion
+	}
+	return resolved
+}
+
+// Image returns the image of the SPIRE version selected by the requested version of the operand CR,
+// the FIPS image when FIPS mode is enabled and it is set
+func (o SpireOperand) Image(requested string) string {
+	resolved := o.Version(requested)
+	if resolved == o.DefaultVersion {
+		return selectImage(o.ImageEnv, o.FIPSImageEnv)
+	}
+	return selectImage(VersionedImageEnv(o.ImageEnv, resolved), VersionedImageEnv(o.FIPSImageEnv, resolved))
+}
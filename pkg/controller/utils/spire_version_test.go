@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/version"
+)
+
+func TestVersionedImageEnv(t *testing.T) {
+	if env := VersionedImageEnv(SpireServerImageEnv, "1.12.4"); env != "RELATED_IMAGE_SPIRE_SERVER_1_12_4" {
+		t.Errorf("VersionedImageEnv() = %q, want RELATED_IMAGE_SPIRE_SERVER_1_12_4", env)
+	}
+}
+
+func TestSpireOperandResolveVersion(t *testing.T) {
+	original := version.SupportedSpireVersions
+	t.Cleanup(func() { version.SupportedSpireVersions = original })
+	version.SupportedSpireVersions = "1.12.2, 1.12.4,1.13.3"
+	operand := SpireOperand{Name: "SPIRE server", DefaultVersion: "1.13.3"}
+
+	tests := []struct {
+		name      string
+		requested string
+		expected  string
+		expectErr string
+	}{
+		{name: "empty uses the default version", requested: "", expected: "1.13.3"},
+		{name: "pinned supported version", requested: "1.12.2", expected: "1.12.2"},
+		{name: "minor version selects the latest patch", requested: "1.12", expected: "1.12.4"},
+		{name: "minor version of the default", requested: "1.13", expected: "1.13.3"},
+		{name: "unsupported patch", requested: "1.12.3", expectErr: "SPIRE server version 1.12.3 is not supported"},
+		{name: "unsupported minor", requested: "1.11", expectErr: "supported versions are 1.12.2, 1.12.4, 1.13.3"},
+		{name: "invalid version", requested: "latest", expectErr: "is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := operand.ResolveVersion(tt.requested)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("ResolveVersion() error = %v, want it to contain %q", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveVersion() unexpected error = %v", err)
+			}
+			if resolved != tt.expected {
+				t.Errorf("ResolveVersion() = %q, want %q", resolved, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSpireOperandValidateVersion(t *testing.T) {
+	if err := SpireServerOperand.ValidateVersion(""); err != nil {
+		t.Errorf("ValidateVersion() unexpected error for the default version: %v", err)
+	}
+	if err := SpireServerOperand.ValidateVersion("1.12.4"); err == nil || !strings.Contains(err.Error(), "RELATED_IMAGE_SPIRE_SERVER_1_12_4") {
+		t.Errorf("ValidateVersion() error = %v, want the missing image to be reported", err)
+	}
+
+	t.Setenv(VersionedImageEnv(SpireServerImageEnv, "1.12.4"), "ghcr.io/spiffe/spire-server:1.12.4")
+	if err := SpireServerOperand.ValidateVersion("1.12.4"); err != nil {
+		t.Errorf("ValidateVersion() unexpected error once the image is set: %v", err)
+	}
+	if err := SpireServerOperand.ValidateVersion("1.9.0"); err == nil {
+		t.Error("ValidateVersion() expected an error for an unsupported version")
+	}
+}
+
+func TestSpireOperandImage(t *testing.T) {
+	t.Setenv(fipsModeEnvName, "false")
+	t.Setenv(SpireServerImageEnv, "ghcr.io/spiffe/spire-server:1.13.3")
+	t.Setenv(VersionedImageEnv(SpireServerImageEnv, "1.12.4"), "ghcr.io/spiffe/spire-server:1.12.4")
+	t.Setenv(VersionedImageEnv(SpireServerFIPSImageEnv, "1.12.4"), "registry.example.com/spire-server-fips:1.12.4")
+
+	if image := SpireServerOperand.Image(""); image != "ghcr.io/spiffe/spire-server:1.13.3" {
+		t.Errorf("Image() = %q, want the default image", image)
+	}
+	if image := SpireServerOperand.Image("1.12"); image != "ghcr.io/spiffe/spire-server:1.12.4" {
+		t.Errorf("Image() = %q, want the image of the pinned version", image)
+	}
+	if image := SpireServerOperand.Image("1.9"); image != "ghcr.io/spiffe/spire-server:1.13.3" {
+		t.Errorf("Image() = %q, want the default image for an unsupported version", image)
+	}
+
+	t.Setenv(fipsModeEnvName, "true")
+	if image := SpireServerOperand.Image("1.12.4"); image != "registry.example.com/spire-server-fips:1.12.4" {
+		t.Errorf("Image() = %q, want the FIPS image of the pinned version", image)
+	}
+}
+
+func TestSpireOperandFIPSImageEnvFor(t *testing.T) {
+	if env := SpireServerOperand.FIPSImageEnvFor(""); env != SpireServerFIPSImageEnv {
+		t.Errorf("FIPSImageEnvFor() = %q, want %q", env, SpireServerFIPSImageEnv)
+	}
+	if env := SpireServerOperand.FIPSImageEnvFor("1.12.4"); env != "RELATED_IMAGE_SPIRE_SERVER_FIPS_1_12_4" {
+		t.Errorf("FIPSImageEnvFor() = %q, want the FIPS image of the pinned version", env)
+	}
+}
+
+func TestVersionedImageEnvNames(t *testing.T) {
+	names := VersionedImageEnvNames()
+	for _, expected := range []string{
+		"RELATED_IMAGE_SPIRE_SERVER_1_12_4",
+		"RELATED_IMAGE_SPIRE_AGENT_FIPS_1_12_4",
+		"RELATED_IMAGE_SPIRE_OIDC_DISCOVERY_PROVIDER_1_12_4",
+	} {
+		found := false
+		for _, name := range names {
+			found = found || name == expected
+		}
+		if !found {
+			t.Errorf("VersionedImageEnvNames() = %v, want it to contain %s", names, expected)
+		}
+	}
+	for _, name := range names {
+		if strings.HasSuffix(name, "_1_13_3") {
+			t.Errorf("VersionedImageEnvNames() = %v, want no environment variable for the default version", names)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+const (
+	// HostedClusterKubeconfigKey is the key of the kubeconfig in the Secret named by topology.hostedClusterKubeconfigSecretName
+	HostedClusterKubeconfigKey = "kubeconfig"
+
+	// HostedClusterKubeconfigMountPath is where the kubeconfig of the hosted cluster is mounted in the SPIRE server pods
+	HostedClusterKubeconfigMountPath = "/run/spire/hosted-cluster"
+)
+
+// GetTopologyMode returns the placement of the operands configured on the ZeroTrustWorkloadIdentityManager,
+// defaulting to the Standalone mode
+func GetTopologyMode(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) v1alpha1.TopologyMode {
+	if ztwim == nil || ztwim.Spec.Topology == nil || ztwim.Spec.Topology.Mode == "" {
+		return v1alpha1.TopologyStandalone
+	}
+	return ztwim.Spec.Topology.Mode
+}
+
+// IsOperandDeployed reports whether the operand of kind runs in this cluster in the topology of the
+// ZeroTrustWorkloadIdentityManager. The operands of the other cluster are left to the operator running there.
+func IsOperandDeployed(ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager, kind string) bool {
+	switch GetTopologyMode(ztwim) {
+	case v1alpha1.TopologyManagementCluster:
+		return kind == ResourceKindSpireServer
+	case v1alpha1.TopologyHostedCluster:
+		return kind != ResourceKindSpireServer
+	default:
+		return true
+	}
+}
+
+// HostedClusterKubeconfigPath returns the path of the kubeconfig of the hosted cluster in the SPIRE server pods
+func HostedClusterKubeconfigPath() string {
+	return HostedClusterKubeconfigMountPath + "/" + HostedClusterKubeconfigKey
+}
+
+// SplitServerAddress splits the host:port address of the SPIRE server into its host and port
+func SplitServerAddress(address string) (string, string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SPIRE server address %q: %w", address, err)
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("invalid SPIRE server address %q: missing host", address)
+	}
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+		return "", "", fmt.Errorf("invalid SPIRE server address %q: port must be between 1 and 65535", address)
+	}
+	return host, port, nil
+}
+
+// ValidateTopology validates the settings required by the topology mode
+func ValidateTopology(topology *v1alpha1.TopologyConfig) error {
+	if topology == nil {
+		return nil
+	}
+	switch topology.Mode {
+	case v1alpha1.TopologyManagementCluster:
+		if topology.HostedClusterKubeconfigSecretName == "" {
+			return fmt.Errorf("topology.hostedClusterKubeconfigSecretName is required in the %s mode", topology.Mode)
+		}
+	case v1alpha1.TopologyHostedCluster:
+		if topology.ServerAddress == "" {
+			return fmt.Errorf("topology.serverAddress is required in the %s mode", topology.Mode)
+		}
+		if _, _, err := SplitServerAddress(topology.ServerAddress); err != nil {
+			return fmt.Errorf("topology.serverAddress: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/core"
+	k8scorev1 "k8s.io/kubernetes/pkg/apis/core/v1"
+)
+
+// maxTopologySpreadConstraints is the maximum number of topology spread constraints of an operand
+const maxTopologySpreadConstraints = 10
+
+// ValidateTopologySpreadConstraints validates the topology spread constraints of an operand
+// using Kubernetes validation functions.
+func ValidateTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	if len(constraints) > maxTopologySpreadConstraints {
+		return fmt.Errorf("topologySpreadConstraints: must have at most %d items", maxTopologySpreadConstraints)
+	}
+
+	internalConstraints := make([]core.TopologySpreadConstraint, len(constraints))
+	for i := range constraints {
+		constraint := constraints[i].DeepCopy()
+		// The label selector is defaulted to the pods of the operand, so matchLabelKeys are allowed without it
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{}
+		}
+		if err := k8scorev1.Convert_v1_TopologySpreadConstraint_To_core_TopologySpreadConstraint(constraint, &internalConstraints[i], nil); err != nil {
+			return fmt.Errorf("topologySpreadConstraints[%d]: %w", i, err)
+		}
+	}
+	return fieldErrorListToError(validateTopologySpreadConstraints(internalConstraints, field.NewPath("topologySpreadConstraints")))
+}
+
+// TopologySpreadConstraints returns the topology spread constraints of an operand, the constraints without
+// label selector select the pods of the operand through selectorLabels
+func TopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint, selectorLabels map[string]string) []corev1.TopologySpreadConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+	result := make([]corev1.TopologySpreadConstraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		constraint := *constraint.DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{MatchLabels: maps.Clone(selectorLabels)}
+		}
+		result = append(result, constraint)
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateTopologySpreadConstraints(t *testing.T) {
+	zones := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       corev1.LabelTopologyZone,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+
+	tests := []struct {
+		name        string
+		constraints []corev1.TopologySpreadConstraint
+		expectedErr string
+	}{
+		{
+			name: "nothing configured",
+		},
+		{
+			name: "zones and nodes",
+			constraints: []corev1.TopologySpreadConstraint{
+				zones,
+				{MaxSkew: 1, TopologyKey: corev1.LabelHostname, WhenUnsatisfiable: corev1.ScheduleAnyway, MatchLabelKeys: []string{"pod-template-hash"}},
+			},
+		},
+		{
+			name:        "invalid maxSkew",
+			constraints: []corev1.TopologySpreadConstraint{{TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule}},
+			expectedErr: "topologySpreadConstraints[0].maxSkew",
+		},
+		{
+			name:        "missing topologyKey",
+			constraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, WhenUnsatisfiable: corev1.DoNotSchedule}},
+			expectedErr: "topologySpreadConstraints[0].topologyKey",
+		},
+		{
+			name:        "unsupported whenUnsatisfiable",
+			constraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: "Never"}},
+			expectedErr: "topologySpreadConstraints[0].whenUnsatisfiable",
+		},
+		{
+			name:        "duplicate constraint",
+			constraints: []corev1.TopologySpreadConstraint{zones, zones},
+			expectedErr: "Duplicate value",
+		},
+		{
+			name:        "too many constraints",
+			constraints: slices.Repeat([]corev1.TopologySpreadConstraint{zones}, 11),
+			expectedErr: "topologySpreadConstraints: must have at most 10 items",
+		},
+		{
+			name: "minDomains with ScheduleAnyway",
+			constraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.ScheduleAnyway, MinDomains: ptr.To(int32(3))},
+			},
+			expectedErr: "topologySpreadConstraints[0].minDomains",
+		},
+		{
+			name: "invalid label selector",
+			constraints: []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       corev1.LabelTopologyZone,
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "spire server"}},
+				},
+			},
+			expectedErr: "topologySpreadConstraints[0].labelSelector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTopologySpreadConstraints(tt.constraints)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTopologySpreadConstraints(t *testing.T) {
+	selectorLabels := map[string]string{"app.kubernetes.io/name": "spire-server"}
+	custom := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "custom"}}
+	constraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule},
+		{MaxSkew: 1, TopologyKey: corev1.LabelHostname, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: custom},
+	}
+
+	got := TopologySpreadConstraints(constraints, selectorLabels)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 constraints, got %v", got)
+	}
+	if !equality.Semantic.DeepEqual(&metav1.LabelSelector{MatchLabels: selectorLabels}, got[0].LabelSelector) {
+		t.Errorf("Expected the label selector to default to the operand pods, got %v", got[0].LabelSelector)
+	}
+	if !equality.Semantic.DeepEqual(custom, got[1].LabelSelector) {
+		t.Errorf("Expected the configured label selector to be kept, got %v", got[1].LabelSelector)
+	}
+	if constraints[0].LabelSelector != nil {
+		t.Errorf("Expected the constraints of the spec to be left unchanged, got %v", constraints[0].LabelSelector)
+	}
+
+	if got := TopologySpreadConstraints(nil, selectorLabels); got != nil {
+		t.Errorf("Expected no constraints, got %v", got)
+	}
+}
+
+func TestDeploymentNeedsUpdateTopologySpreadConstraints(t *testing.T) {
+	newDeployment := func(constraints []corev1.TopologySpreadConstraint) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{TopologySpreadConstraints: constraints},
+				},
+			},
+		}
+	}
+	zones := []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule}}
+
+	if DeploymentNeedsUpdate(newDeployment(zones), newDeployment(zones)) {
+		t.Error("Expected no update for identical topology spread constraints")
+	}
+	if !DeploymentNeedsUpdate(newDeployment(nil), newDeployment(zones)) {
+		t.Error("Expected an update when topology spread constraints are added")
+	}
+}
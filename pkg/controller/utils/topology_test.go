@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestIsOperandDeployed(t *testing.T) {
+	kinds := []string{ResourceKindSpireServer, ResourceKindSpireAgent, ResourceKindSpiffeCSIDriver, ResourceKindSpireOIDCDiscoveryProvider}
+	tests := []struct {
+		name     string
+		topology *v1alpha1.TopologyConfig
+		expected []string
+	}{
+		{
+			name:     "standalone by default",
+			expected: kinds,
+		},
+		{
+			name:     "management cluster",
+			topology: &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyManagementCluster},
+			expected: []string{ResourceKindSpireServer},
+		},
+		{
+			name:     "hosted cluster",
+			topology: &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster},
+			expected: []string{ResourceKindSpireAgent, ResourceKindSpiffeCSIDriver, ResourceKindSpireOIDCDiscoveryProvider},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{Topology: tt.topology}}
+			var deployed []string
+			for _, kind := range kinds {
+				if IsOperandDeployed(ztwim, kind) {
+					deployed = append(deployed, kind)
+				}
+			}
+			if strings.Join(deployed, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected the operands %v to be deployed, got %v", tt.expected, deployed)
+			}
+		})
+	}
+}
+
+func TestValidateTopology(t *testing.T) {
+	tests := []struct {
+		name        string
+		topology    *v1alpha1.TopologyConfig
+		expectedErr string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "management cluster",
+			topology: &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyManagementCluster, HostedClusterKubeconfigSecretName: "service-network-admin-kubeconfig"},
+		},
+		{
+			name:        "management cluster without kubeconfig",
+			topology:    &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyManagementCluster},
+			expectedErr: "hostedClusterKubeconfigSecretName is required",
+		},
+		{
+			name:     "hosted cluster",
+			topology: &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster, ServerAddress: "spire-server.apps.example.com:443"},
+		},
+		{
+			name:        "hosted cluster without server address",
+			topology:    &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster},
+			expectedErr: "serverAddress is required",
+		},
+		{
+			name:        "server address without port",
+			topology:    &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster, ServerAddress: "spire-server.apps.example.com"},
+			expectedErr: "topology.serverAddress",
+		},
+		{
+			name:        "server address with an invalid port",
+			topology:    &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster, ServerAddress: "spire-server.apps.example.com:70000"},
+			expectedErr: "port must be between 1 and 65535",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTopology(tt.topology)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
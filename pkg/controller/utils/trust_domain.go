@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+)
+
+// IsValidTrustDomain validates that the given name is a well-formed SPIFFE trust domain name.
+// The spiffe:// URI form is rejected, as SPIRE expects the bare trust domain name.
+func IsValidTrustDomain(trustDomain string) error {
+	if trustDomain == "" {
+		return fmt.Errorf("trust domain cannot be empty")
+	}
+	td, err := spiffeid.TrustDomainFromString(trustDomain)
+	if err != nil {
+		return fmt.Errorf("invalid trust domain %q: %w", trustDomain, err)
+	}
+	if td.Name() != trustDomain {
+		return fmt.Errorf("invalid trust domain %q: must be a trust domain name, not a SPIFFE ID", trustDomain)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestIsValidTrustDomain(t *testing.T) {
+	tests := []struct {
+		name        string
+		trustDomain string
+		expectError bool
+	}{
+		{name: "simple domain", trustDomain: "example.org"},
+		{name: "domain with hyphens", trustDomain: "prod-cluster.example.org"},
+		{name: "empty", trustDomain: "", expectError: true},
+		{name: "uppercase characters", trustDomain: "Example.org", expectError: true},
+		{name: "spiffe URI instead of name", trustDomain: "spiffe://example.org", expectError: true},
+		{name: "contains a path", trustDomain: "example.org/path", expectError: true},
+		{name: "invalid characters", trustDomain: "example$org", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsValidTrustDomain(tt.trustDomain)
+			if (err != nil) != tt.expectError {
+				t.Errorf("IsValidTrustDomain(%q) error = %v, expectError = %v", tt.trustDomain, err, tt.expectError)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	routev1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 	spiffev1alpha1 "github.com/spiffe/spire-controller-manager/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +22,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 )
@@ -38,6 +40,43 @@ const (
 	CreateOnlyModeStatusType = "CreateOnlyMode"
 	CreateOnlyModeEnabled    = "CreateOnlyModeEnabled"
 	CreateOnlyModeDisabled   = "CreateOnlyModeDisabled"
+
+	DryRunModeStatusType = "DryRunMode"
+	DryRunModeEnabled    = "DryRunModeEnabled"
+	DryRunModeDisabled   = "DryRunModeDisabled"
+
+	PausedStatusType      = "Paused"
+	ReconciliationPaused  = "ReconciliationPaused"
+	ReconciliationResumed = "ReconciliationResumed"
+
+	// ManagementStateStatusType reports the management state in effect for the resources of a CR.
+	// Its reason is the state, and it is False while the resources are Unmanaged or Removed.
+	ManagementStateStatusType = "ManagementState"
+
+	// ExcludedResourcesStatusType reports the writes skipped for the resources excluded from management.
+	// It is False once no resource is excluded, which is not a failure.
+	ExcludedResourcesStatusType = "ExcludedResources"
+	ResourcesExcluded           = "ResourcesExcluded"
+	NoResourcesExcluded         = "NoResourcesExcluded"
+
+	// FederatedBundlesHealthyStatusType reports the health of the bundle endpoints of the federated
+	// trust domains. An unreachable partner does not affect the local components, so it is not
+	// taken into account in the Ready condition.
+	FederatedBundlesHealthyStatusType = "FederatedBundlesHealthy"
+
+	// CARotationProgressingStatusType reports the progress of the rotation of the SPIRE server authorities
+	// driven by the operator. It is False once a rotation completed, which is not a failure.
+	CARotationProgressingStatusType = "CARotationProgressing"
+
+	// ConfigReloadedStatusType reports whether the settings changed at runtime were applied to the running
+	// SPIRE server pods. The pods keep serving with the previous settings when it is False, so it is not taken
+	// into account in the Ready condition.
+	ConfigReloadedStatusType = "ConfigReloaded"
+
+	// PreflightChecksPassedStatusType reports on the ZeroTrustWorkloadIdentityManager whether the cluster meets the
+	// prerequisites of the operands. The operands not deployed yet wait for it while it is False.
+	PreflightChecksPassedStatusType = "PreflightChecksPassed"
+	PreflightChecksFailed           = "PreflightChecksFailed"
 )
 
 func init() {
@@ -57,6 +96,9 @@ func init() {
 const (
 	LogLevelInfo  = "info"
 	LogFormatText = "text"
+
+	// SystemNodeCriticalPriorityClassName is the default PriorityClass for node-level operands
+	SystemNodeCriticalPriorityClassName = "system-node-critical"
 )
 
 // GetOperatorNamespace returns the namespace where the operator resources should be installed.
@@ -66,6 +108,16 @@ func GetOperatorNamespace() string {
 	return os.Getenv("OPERATOR_NAMESPACE")
 }
 
+// GetOperatorServiceAccount returns the name of the ServiceAccount the operator runs as.
+// It reads from the OPERATOR_SERVICE_ACCOUNT environment variable, falling back to the name
+// of the ServiceAccount installed with the operator.
+func GetOperatorServiceAccount() string {
+	if name := os.Getenv("OPERATOR_SERVICE_ACCOUNT"); name != "" {
+		return name
+	}
+	return "zero-trust-workload-identity-manager-controller-manager"
+}
+
 func DecodeClusterRoleObjBytes(objBytes []byte) *rbacv1.ClusterRole {
 	obj, err := runtime.Decode(codecs.UniversalDecoder(rbacv1.SchemeGroupVersion), objBytes)
 	if err != nil {
@@ -181,6 +233,11 @@ func StringToBool(s string) bool {
 	return s == "true"
 }
 
+// IsTornjakEnabled returns true only if the Tornjak UI is explicitly enabled
+func IsTornjakEnabled(config *v1alpha1.TornjakConfig) bool {
+	return config != nil && StringToBool(config.Enabled)
+}
+
 func DerefResourceRequirements(r *corev1.ResourceRequirements) corev1.ResourceRequirements {
 	if r != nil {
 		return *r
@@ -216,6 +273,22 @@ func DerefNodeSelector(selector map[string]string) map[string]string {
 	return result
 }
 
+// GetPriorityClassName returns the configured PriorityClass name, or defaultName if none is configured
+func GetPriorityClassName(priorityClassName, defaultName string) string {
+	if priorityClassName == "" {
+		return defaultName
+	}
+	return priorityClassName
+}
+
+// GetControllerManagerClassName returns the class name of the spire-controller-manager, falling back to its default
+func GetControllerManagerClassName(config *v1alpha1.ControllerManagerConfig) string {
+	if config == nil || config.ClassName == "" {
+		return DefaultSpireControllerManagerClassName
+	}
+	return config.ClassName
+}
+
 func GetLogLevelFromString(logLevel string) string {
 	if logLevel == "" {
 		return LogLevelInfo
@@ -254,14 +327,28 @@ func IsInCreateOnlyMode() bool {
 	}
 }
 
-// ZTWIMSpecChangedPredicate triggers reconciliation when ZTWIM spec is created
-// while avoiding unnecessary reconciliations when only non-critical fields change
+// IsDryRunMode reports whether the reconcileMode of an operand CR requests a dry run
+func IsDryRunMode(reconcileMode string) bool {
+	return reconcileMode == v1alpha1.ReconcileModeDryRun
+}
+
+// ZTWIMSpecChangedPredicate triggers reconciliation when ZTWIM spec is created, or when the NetworkPolicy
+// configuration, the Tornjak UI, the feature gates shared by the operands or the outcome of the preflight checks
+// change, while avoiding unnecessary reconciliations when only non-critical fields change
 var ZTWIMSpecChangedPredicate = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
 		return true
 	},
 	UpdateFunc: func(e event.UpdateEvent) bool {
-		return false
+		oldZTWIM, okOld := e.ObjectOld.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+		newZTWIM, okNew := e.ObjectNew.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+		if !okOld || !okNew {
+			return false
+		}
+		return IsNetworkPolicyEnabled(oldZTWIM.Spec.NetworkPolicy) != IsNetworkPolicyEnabled(newZTWIM.Spec.NetworkPolicy) ||
+			preflightChecksStatus(oldZTWIM) != preflightChecksStatus(newZTWIM) ||
+			!equality.Semantic.DeepEqual(oldZTWIM.Spec.Tornjak, newZTWIM.Spec.Tornjak) ||
+			!equality.Semantic.DeepEqual(oldZTWIM.Spec.FeatureGates, newZTWIM.Spec.FeatureGates)
 	},
 	DeleteFunc: func(e event.DeleteEvent) bool {
 		return true
@@ -336,11 +423,21 @@ func NeedsOwnerReferenceUpdate(obj client.Object, expectedOwner client.Object) b
 	return true
 }
 
+// DeletionStartedPredicate triggers reconciliation when the deletion timestamp of a resource is set,
+// so that its finalizer runs
+var DeletionStartedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+	},
+}
+
 // GenerationOrOwnerReferenceChangedPredicate triggers reconciliation when either:
 // 1. The resource generation changes (spec/status changes)
 // 2. Owner references change (removed/modified)
+// 3. The resource starts being deleted
 // This is the standard predicate for all operand controllers
 var GenerationOrOwnerReferenceChangedPredicate = predicate.Or(
 	predicate.GenerationChangedPredicate{},
 	OwnerReferenceChangedPredicate,
+	DeletionStartedPredicate,
 )
@@ -8,6 +8,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
 )
 
 // Helper function to set environment variable and return cleanup function
@@ -555,6 +558,18 @@ func TestDerefTolerations(t *testing.T) {
 	}
 }
 
+func TestGetPriorityClassName(t *testing.T) {
+	if got := GetPriorityClassName("", SystemNodeCriticalPriorityClassName); got != SystemNodeCriticalPriorityClassName {
+		t.Errorf("GetPriorityClassName() = %q, expected default %q", got, SystemNodeCriticalPriorityClassName)
+	}
+	if got := GetPriorityClassName("custom-priority", SystemNodeCriticalPriorityClassName); got != "custom-priority" {
+		t.Errorf("GetPriorityClassName() = %q, expected %q", got, "custom-priority")
+	}
+	if got := GetPriorityClassName("", ""); got != "" {
+		t.Errorf("GetPriorityClassName() = %q, expected empty", got)
+	}
+}
+
 func TestDerefNodeSelector(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -993,3 +1008,49 @@ func TestNeedsOwnerReferenceUpdate(t *testing.T) {
 		}
 	})
 }
+
+func TestImageOverrides(t *testing.T) {
+	t.Cleanup(func() { imageOverrides.Store(nil) })
+	cleanup := setEnvVar(SpireServerImageEnv, "spire-server:v1.2.3")
+	defer cleanup()
+
+	SetImageOverrides(map[string]string{SpireServerImageEnv: "registry.example.com/spire-server:v1.2.4"})
+	if image := GetSpireServerImage(); image != "registry.example.com/spire-server:v1.2.4" {
+		t.Errorf("GetSpireServerImage() = %q, want the override", image)
+	}
+	if image := GetDatastoreBackupImage(); image != "registry.redhat.io/rhel9/postgresql-16:latest" {
+		t.Errorf("GetDatastoreBackupImage() = %q, want the default", image)
+	}
+
+	SetImageOverrides(nil)
+	if image := GetSpireServerImage(); image != "spire-server:v1.2.3" {
+		t.Errorf("GetSpireServerImage() = %q, want the environment variable once the override is removed", image)
+	}
+}
+
+func TestIsTornjakEnabled(t *testing.T) {
+	if IsTornjakEnabled(nil) {
+		t.Error("Expected Tornjak to be disabled by default")
+	}
+	if IsTornjakEnabled(&v1alpha1.TornjakConfig{Enabled: "false"}) {
+		t.Error("Expected Tornjak to be disabled")
+	}
+	if !IsTornjakEnabled(&v1alpha1.TornjakConfig{Enabled: "true"}) {
+		t.Error("Expected Tornjak to be enabled")
+	}
+}
+
+func TestZTWIMSpecChangedPredicateTornjak(t *testing.T) {
+	newZTWIM := func(routeHost string) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+			Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{Tornjak: &v1alpha1.TornjakConfig{Enabled: "true", RouteHost: routeHost}},
+		}
+	}
+
+	if !ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newZTWIM(""), ObjectNew: newZTWIM("tornjak.apps.example.com")}) {
+		t.Error("Expected a Tornjak configuration change to trigger reconciliation")
+	}
+	if ZTWIMSpecChangedPredicate.Update(event.UpdateEvent{ObjectOld: newZTWIM(""), ObjectNew: newZTWIM("")}) {
+		t.Error("Expected no reconciliation when the Tornjak configuration is unchanged")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -212,6 +213,14 @@ func ValidateCommonConfigLabels(labels map[string]string) error {
 	return nil
 }
 
+// ValidateServiceAccountAnnotations validates the ServiceAccount annotations of an operand using Kubernetes validation functions.
+func ValidateServiceAccountAnnotations(annotations map[string]string) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	return fieldErrorListToError(apivalidation.ValidateAnnotations(annotations, field.NewPath("serviceAccountAnnotations")))
+}
+
 // ValidateCommonConfig validates all common configuration fields
 func ValidateCommonConfig(affinity *corev1.Affinity, tolerations []*corev1.Toleration, nodeSelector map[string]string, resources *corev1.ResourceRequirements, labels map[string]string) error {
 	// Validate affinity
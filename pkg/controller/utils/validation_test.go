@@ -505,6 +505,44 @@ func TestValidateCommonConfigLabels(t *testing.T) {
 	}
 }
 
+func TestValidateServiceAccountAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantError   bool
+	}{
+		{
+			name:        "nil annotations is valid",
+			annotations: nil,
+			wantError:   false,
+		},
+		{
+			name: "valid cloud IAM annotations",
+			annotations: map[string]string{
+				"eks.amazonaws.com/role-arn":     "arn:aws:iam::111122223333:role/spire-server",
+				"iam.gke.io/gcp-service-account": "spire-server@project.iam.gserviceaccount.com",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid annotations - invalid key",
+			annotations: map[string]string{
+				"eks.amazonaws.com/role arn": "value",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateServiceAccountAnnotations(tt.annotations)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateServiceAccountAnnotations() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
 func TestValidateCommonConfig(t *testing.T) {
 	tests := []struct {
 		name         string
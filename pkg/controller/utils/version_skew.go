@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionLabelKey is the label recording the operand version on the managed workloads
+const VersionLabelKey = "app.kubernetes.io/version"
+
+// Condition reporting whether the operand image upgrades pass the SPIRE version skew checks
+const (
+	UpgradeAllowedStatusType = "UpgradeAllowed"
+	UpgradeAllowed           = "UpgradeAllowed"
+	UnsafeVersionSkew        = "UnsafeVersionSkew"
+	WaitingForSpireServer    = "WaitingForSpireServer"
+)
+
+// minorVersion is the major and minor part of a SPIRE version, which the skew rules are defined on
+type minorVersion struct {
+	major, minor int
+}
+
+// parseMinorVersion parses the major and minor version of a version like "1.13.3" or "v1.13.3"
+func parseMinorVersion(v string) (minorVersion, error) {
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) < 2 {
+		return minorVersion{}, fmt.Errorf("invalid version %q", v)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return minorVersion{}, fmt.Errorf("invalid version %q: %w", v, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return minorVersion{}, fmt.Errorf("invalid version %q: %w", v, err)
+	}
+	return minorVersion{major: major, minor: minor}, nil
+}
+
+// less reports whether v is an older minor version than other
+func (v minorVersion) less(other minorVersion) bool {
+	return v.major < other.major || (v.major == other.major && v.minor < other.minor)
+}
+
+// CheckSpireServerUpgrade returns an error when the SPIRE server can't safely move from the current to the
+// desired version: the datastore migrations only support upgrading one minor version at a time, and a
+// migrated datastore schema can't be used by older servers. Unknown versions are not checked.
+func CheckSpireServerUpgrade(current, desired string, migrationDisabled bool) error {
+	if current == "" || current == desired {
+		return nil
+	}
+	currentVersion, err := parseMinorVersion(current)
+	if err != nil {
+		return nil
+	}
+	desiredVersion, err := parseMinorVersion(desired)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case desiredVersion.less(currentVersion):
+		return fmt.Errorf("downgrading the SPIRE server from %s to %s is not supported, the datastore schema may already be migrated", current, desired)
+	case desiredVersion.major != currentVersion.major || desiredVersion.minor > currentVersion.minor+1:
+		return fmt.Errorf("upgrading the SPIRE server from %s to %s skips minor versions, the datastore schema can only be migrated one minor version at a time", current, desired)
+	case migrationDisabled && desiredVersion != currentVersion:
+		return fmt.Errorf("upgrading the SPIRE server from %s to %s requires a datastore schema migration, which datastore.disableMigration prevents", current, desired)
+	}
+	return nil
+}
+
+// CheckSpireAgentVersionSkew returns an error when SPIRE agents of the agent version can't attest to a
+// SPIRE server of the server version: agents must not be newer than the server, and must be at most one
+// minor version behind it. Unknown versions are not checked.
+func CheckSpireAgentVersionSkew(server, agent string) error {
+	serverVersion, err := parseMinorVersion(server)
+	if err != nil {
+		return nil
+	}
+	agentVersion, err := parseMinorVersion(agent)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case serverVersion.less(agentVersion):
+		return fmt.Errorf("SPIRE agent %s is newer than the SPIRE server %s, the server must be upgraded first", agent, server)
+	case agentVersion.major != serverVersion.major || agentVersion.minor+1 < serverVersion.minor:
+		return fmt.Errorf("SPIRE agent %s is more than one minor version behind the SPIRE server %s", agent, server)
+	}
+	return nil
+}
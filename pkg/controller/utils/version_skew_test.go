@@ -0,0 +1,59 @@
+package utils
+
+import "testing"
+
+func TestCheckSpireServerUpgrade(t *testing.T) {
+	tests := []struct {
+		name              string
+		current           string
+		desired           string
+		migrationDisabled bool
+		expectErr         bool
+	}{
+		{name: "initial install", desired: "1.13.3"},
+		{name: "same version", current: "1.13.3", desired: "1.13.3", migrationDisabled: true},
+		{name: "patch upgrade", current: "1.13.0", desired: "1.13.3", migrationDisabled: true},
+		{name: "patch downgrade", current: "1.13.3", desired: "1.13.0"},
+		{name: "one minor version upgrade", current: "1.12.4", desired: "v1.13.3"},
+		{name: "skipped minor version", current: "1.11.2", desired: "1.13.3", expectErr: true},
+		{name: "minor version downgrade", current: "1.13.3", desired: "1.12.4", expectErr: true},
+		{name: "major version change", current: "1.13.3", desired: "2.0.0", expectErr: true},
+		{name: "migration disabled", current: "1.12.4", desired: "1.13.3", migrationDisabled: true, expectErr: true},
+		{name: "unknown version", current: "latest", desired: "1.13.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSpireServerUpgrade(tt.current, tt.desired, tt.migrationDisabled)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("CheckSpireServerUpgrade(%q, %q, %v) error = %v, expectErr %v", tt.current, tt.desired, tt.migrationDisabled, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestCheckSpireAgentVersionSkew(t *testing.T) {
+	tests := []struct {
+		name      string
+		server    string
+		agent     string
+		expectErr bool
+	}{
+		{name: "same version", server: "1.13.3", agent: "1.13.3"},
+		{name: "agent one minor version behind", server: "1.13.3", agent: "1.12.4"},
+		{name: "agent newer patch", server: "1.13.0", agent: "1.13.3"},
+		{name: "agent two minor versions behind", server: "1.13.3", agent: "1.11.0", expectErr: true},
+		{name: "agent newer than the server", server: "1.12.4", agent: "1.13.3", expectErr: true},
+		{name: "different major version", server: "2.0.0", agent: "1.13.3", expectErr: true},
+		{name: "unknown server version", agent: "1.13.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSpireAgentVersionSkew(tt.server, tt.agent)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("CheckSpireAgentVersionSkew(%q, %q) error = %v, expectErr %v", tt.server, tt.agent, err, tt.expectErr)
+			}
+		})
+	}
+}
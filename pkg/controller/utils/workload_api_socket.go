@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"path"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// Defaults of the directory and the file name of the SPIRE agent Workload API socket
+const (
+	DefaultAgentSocketPath = "/run/spire/agent-sockets"
+	DefaultAgentSocketName = "spire-agent.sock"
+)
+
+// GetAgentSocketName returns the file name of the Workload API socket of the SPIRE agents, which is the
+// default one when agent is nil
+func GetAgentSocketName(agent *v1alpha1.SpireAgent) string {
+	if agent == nil || agent.Spec.SocketName == "" {
+		return DefaultAgentSocketName
+	}
+	return agent.Spec.SocketName
+}
+
+// AgentSocketPathsMatch reports whether two directories of the Workload API socket are the same,
+// an unset directory being the default one
+func AgentSocketPathsMatch(a, b string) bool {
+	normalize := func(dir string) string {
+		if dir == "" {
+			return DefaultAgentSocketPath
+		}
+		return path.Clean(dir)
+	}
+	return normalize(a) == normalize(b)
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestGetAgentSocketName(t *testing.T) {
+	if got := GetAgentSocketName(nil); got != DefaultAgentSocketName {
+		t.Errorf("Expected %q without a SpireAgent, got %q", DefaultAgentSocketName, got)
+	}
+	if got := GetAgentSocketName(&v1alpha1.SpireAgent{}); got != DefaultAgentSocketName {
+		t.Errorf("Expected %q when unset, got %q", DefaultAgentSocketName, got)
+	}
+	agent := &v1alpha1.SpireAgent{Spec: v1alpha1.SpireAgentSpec{SocketName: "socket"}}
+	if got := GetAgentSocketName(agent); got != "socket" {
+		t.Errorf("Expected %q, got %q", "socket", got)
+	}
+}
+
+func TestAgentSocketPathsMatch(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"", "", true},
+		{"", DefaultAgentSocketPath, true},
+		{"/run/spire/agent-sockets/", DefaultAgentSocketPath, true},
+		{"/run/secrets/workload-spiffe-uds", "/run/secrets/workload-spiffe-uds", true},
+		{"/run/secrets/workload-spiffe-uds", "", false},
+	}
+	for _, tt := range tests {
+		if got := AgentSocketPathsMatch(tt.a, tt.b); got != tt.expected {
+			t.Errorf("AgentSocketPathsMatch(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
 	operatorv1 "github.com/operator-framework/api/pkg/operators/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierror "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -16,15 +19,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 
@@ -32,12 +38,18 @@ import (
 	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/operatorconfig"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/tracing"
 )
 
 const (
 	// Condition types for ZTWIM
 	OperandsAvailable = "OperandsAvailable"
 	CreateOnlyMode    = "CreateOnlyMode"
+	FeatureGates      = "FeatureGates"
+
+	// OperandNamespaceAvailable reports the operand namespace managed by the operator
+	OperandNamespaceAvailable = "OperandNamespaceAvailable"
 )
 
 // Operand state constants for structured state tracking
@@ -123,6 +135,9 @@ type ZeroTrustWorkloadIdentityManagerReconciler struct {
 	log                   logr.Logger
 	scheme                *runtime.Scheme
 	operatorConditionName string
+	// clientset and lookupHost run the preflight checks, which are skipped when clientset is nil
+	clientset  kubernetes.Interface
+	lookupHost func(ctx context.Context, host string) ([]string, error)
 }
 
 // +kubebuilder:rbac:groups=operator.openshift.io,resources=zerotrustworkloadidentitymanagers,verbs=list;watch
@@ -147,26 +162,29 @@ type ZeroTrustWorkloadIdentityManagerReconciler struct {
 // +kubebuilder:rbac:groups=operator.openshift.io,resources=spireservers/finalizers,verbs=update,resourceNames=cluster
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=list;watch;create
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;update;delete,resourceNames=spire-server;spire-agent;spire-controller-manager
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=zero-trust-workload-identity-manager-spire-server-exec
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=list;watch;create
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;update;delete,resourceNames=spire-server;spire-agent;spire-controller-manager
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=list;watch;create
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;update;delete,resourceNames=spire-bundle;spire-controller-manager-leader-election;spire-server-external-cert-reader;spire-oidc-external-cert-reader
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=list;watch;create
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;update;delete,resourceNames=spire-bundle;spire-controller-manager-leader-election;spire-server-external-cert-reader;spire-oidc-external-cert-reader
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;update;delete,resourceNames=spire-bundle;spire-controller-manager-leader-election;spire-server-external-cert-reader;spire-oidc-external-cert-reader;spire-server-exec
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=update;delete,resourceNames=spire-controller-manager-webhook
 // +kubebuilder:rbac:groups="",resources=services,verbs=list;watch;create
-// +kubebuilder:rbac:groups="",resources=services,verbs=get;update;delete,resourceNames=spire-server;spire-controller-manager-webhook;spire-agent;spire-spiffe-oidc-discovery-provider
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;update;delete,resourceNames=spire-server;spire-controller-manager-webhook;spire-agent;spire-spiffe-oidc-discovery-provider;spire-tornjak;spire-tornjak-backend;spire-server-external
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=list;watch;create
-// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;update;delete,resourceNames=spire-server;spire-agent;spire-spiffe-csi-driver;spire-spiffe-oidc-discovery-provider
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;update;delete,resourceNames=spire-server;spire-agent;spire-spiffe-csi-driver;spire-spiffe-oidc-discovery-provider;spire-tornjak
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;update;patch
-// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=nodes/proxy,verbs=get
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csinodes,verbs=get;list
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
 // +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups=spire.spiffe.io,resources=clusterfederatedtrustdomains,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=spire.spiffe.io,resources=clusterfederatedtrustdomains/finalizers,verbs=update
@@ -178,24 +196,36 @@ type ZeroTrustWorkloadIdentityManagerReconciler struct {
 // +kubebuilder:rbac:groups=spire.spiffe.io,resources=clusterstaticentries/finalizers,verbs=update
 // +kubebuilder:rbac:groups=spire.spiffe.io,resources=clusterstaticentries/status,verbs=get;patch;update
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=list;watch;create
-// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;update;delete,resourceNames=spire-agent;spire-spiffe-csi-driver
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;update;patch;delete,resourceNames=spire-agent;spire-agent-canary;spire-spiffe-csi-driver
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=list;watch;create
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;update;delete,resourceNames=spire-spiffe-oidc-discovery-provider
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;update;patch;delete,resourceNames=spire-spiffe-oidc-discovery-provider;spire-tornjak
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=list;watch;create
-// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;update;delete,resourceNames=spire-server
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;update;patch;delete,resourceNames=spire-server
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=list;watch;create
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;update;delete,resourceNames=spire-server;spire-spiffe-oidc-discovery-provider
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=list;watch;create
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;update;delete,resourceNames=spire-server;spire-spiffe-oidc-discovery-provider
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=list;watch;create
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;update;delete,resourceNames=spire-oidc-discovery-provider
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=list;watch;create
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;update;delete,resourceNames=spire-server-datastore-backup
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=list;watch;create
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;update;delete,resourceNames=spire-spiffe-oidc-discovery-provider
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=list;watch;create
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;update;delete,resourceNames=spire-agent;spire-spiffe-csi-driver
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=list;watch;create
-// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;update;delete,resourceNames=spire-server-federation;spire-oidc-discovery-provider
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;update;delete,resourceNames=spire-server-federation;spire-oidc-discovery-provider;spire-tornjak;spire-server-external
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=delete,resourceNames=spire-tornjak-proxy
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=patch;delete,resourceNames=spire-server-datastore-credentials
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create;update
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=operatorconditions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=operatorconditions/status,verbs=update
 
 // New returns a new Reconciler instance.
-func New(mgr ctrl.Manager) (*ZeroTrustWorkloadIdentityManagerReconciler, error) {
-	c, err := customClient.NewCustomClient(mgr)
+func New(mgr ctrl.Manager, clientOpts ...customClient.Option) (*ZeroTrustWorkloadIdentityManagerReconciler, error) {
+	c, err := customClient.NewCustomClient(mgr, clientOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -203,6 +233,10 @@ func New(mgr ctrl.Manager) (*ZeroTrustWorkloadIdentityManagerReconciler, error)
 	if operatorConditionName == "" {
 		return nil, errors.New("operator condition CR name is empty")
 	}
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the clientset of the preflight checks: %w", err)
+	}
 	return &ZeroTrustWorkloadIdentityManagerReconciler{
 		ctrlClient:            c,
 		ctx:                   context.Background(),
@@ -210,6 +244,8 @@ func New(mgr ctrl.Manager) (*ZeroTrustWorkloadIdentityManagerReconciler, error)
 		log:                   ctrl.Log.WithName(utils.ZeroTrustWorkloadIdentityManagerControllerName),
 		scheme:                mgr.GetScheme(),
 		operatorConditionName: operatorConditionName,
+		clientset:             clientset,
+		lookupHost:            net.DefaultResolver.LookupHost,
 	}, nil
 }
 
@@ -232,6 +268,25 @@ func setCreateOnlyModeCondition(statusMgr *status.Manager, existingConditions []
 	}
 }
 
+// setFeatureGatesCondition reports the effective state of the feature gates
+func setFeatureGatesCondition(statusMgr *status.Manager, config *v1alpha1.ZeroTrustWorkloadIdentityManager) {
+	statusMgr.AddCondition(FeatureGates, "FeatureGatesResolved",
+		featureGatesMessage(config),
+		metav1.ConditionTrue)
+}
+
+// featureGatesMessage lists the enabled and the disabled feature gates
+func featureGatesMessage(config *v1alpha1.ZeroTrustWorkloadIdentityManager) string {
+	enabled, disabled := utils.FeatureGatesSummary(config)
+	format := func(gates []string) string {
+		if len(gates) == 0 {
+			return "none"
+		}
+		return strings.Join(gates, ", ")
+	}
+	return fmt.Sprintf("Enabled: %s; Disabled: %s", format(enabled), format(disabled))
+}
+
 // Reconcile ensures the ZeroTrustWorkloadIdentityManager 'cluster' instance exists
 // and aggregates status from all managed operand CRs
 func (r *ZeroTrustWorkloadIdentityManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -249,6 +304,15 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) Reconcile(ctx context.Conte
 		}
 		return ctrl.Result{}, err
 	}
+	// Delete the operand namespace created by the operator with the ZeroTrustWorkloadIdentityManager
+	if !config.DeletionTimestamp.IsZero() {
+		if err := r.reconcileDeletion(ctx, &config); err != nil {
+			r.log.Error(err, "failed to clean up the operand namespace")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Set Ready to false at the start of reconciliation
 	status.SetInitialReconciliationStatus(ctx, r.ctrlClient, &config, func() *v1alpha1.ConditionalStatus {
 		return &config.Status.ConditionalStatus
@@ -264,8 +328,31 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) Reconcile(ctx context.Conte
 		}
 	}()
 
+	// Create the operand namespace before the operands are installed in it, leave it as it is while
+	// unmanaged, or delete it with the operands once removed
+	switch config.Spec.ManagementState {
+	case v1alpha1.ManagementStateUnmanaged:
+		r.log.Info("ZeroTrustWorkloadIdentityManager is unmanaged, leaving the operand namespace as it is")
+	case v1alpha1.ManagementStateRemoved:
+		if err := r.removeOperandNamespace(ctx, &config, statusMgr); err != nil {
+			r.log.Error(err, "failed to remove the operand namespace")
+			return ctrl.Result{}, err
+		}
+	default:
+		if err := r.reconcileOperandNamespace(ctx, &config, statusMgr); err != nil {
+			r.log.Error(err, "failed to reconcile the operand namespace")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Verify the prerequisites of the operands, the operands not deployed yet wait for the checks to pass
+	var preflightRetry time.Duration
+	if config.Spec.ManagementState != v1alpha1.ManagementStateUnmanaged && config.Spec.ManagementState != v1alpha1.ManagementStateRemoved {
+		preflightRetry = r.runPreflightChecks(ctx, &config, statusMgr)
+	}
+
 	// Aggregate status from all operand CRs
-	result := r.aggregateOperandStatus(ctx)
+	result := r.aggregateOperandStatus(ctx, &config)
 	config.Status.Operands = result.operandStatuses
 
 	// Set operands availability condition and manually control Ready condition
@@ -326,9 +413,15 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) Reconcile(ctx context.Conte
 			metav1.ConditionFalse)
 	}
 
+	// Report the management state, which overrides Ready while the operands are not managed
+	statusMgr.SetManagementStateCondition(config.Spec.ManagementState, "ZeroTrustWorkloadIdentityManager", config.Status.ConditionalStatus.Conditions)
+
 	// Set CreateOnlyMode condition based on environment variable (simpler than aggregating from operands)
 	setCreateOnlyModeCondition(statusMgr, config.Status.ConditionalStatus.Conditions)
 
+	// Report the feature gates in effect, set from the operator flag and the spec
+	setFeatureGatesCondition(statusMgr, &config)
+
 	// Check create-only mode from environment variable for logging and OLM update
 	createOnlyModeEnabled := utils.IsInCreateOnlyMode()
 	r.log.Info("Aggregated operand status", "allReady", result.allReady, "notCreated", result.notCreatedCount, "failed", result.failedCount, "createOnlyModeEnabled", createOnlyModeEnabled, "anyOperandExists", result.anyOperandExists)
@@ -336,10 +429,15 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) Reconcile(ctx context.Conte
 	// Update OperatorCondition for OLM integration (best effort - don't fail reconciliation if it fails)
 	// Upgradeable condition is only set on OperatorCondition, not on ZTWIM CR
 	if err := r.updateOperatorCondition(ctx, createOnlyModeEnabled, result.operandStatuses); err != nil {
+		// Back off instead of failing when the OperatorCondition keeps conflicting with other writers
+		if customClient.IsRetryExhausted(err) {
+			r.log.Info("OperatorCondition update kept conflicting, retrying later", "error", err.Error())
+			return ctrl.Result{RequeueAfter: customClient.RetryExhaustedRequeueInterval}, nil
+		}
 		r.log.Error(err, "failed to update OperatorCondition, continuing (operator may be running outside OLM)")
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: preflightRetry}, nil
 }
 
 // operandAggregateState holds the aggregate state tracked across all operands
@@ -368,9 +466,13 @@ func processOperandStatus(operand v1alpha1.OperandStatus, state *operandAggregat
 
 	// Check if operand is ready
 	if !utils.StringToBool(operand.Ready) {
+		// Operands left unmanaged or removed on purpose do not affect the aggregate state
+		readyCondition := apimeta.FindStatusCondition(operand.Conditions, v1alpha1.Ready)
+		if readyCondition != nil && (readyCondition.Reason == string(v1alpha1.ManagementStateUnmanaged) || readyCondition.Reason == string(v1alpha1.ManagementStateRemoved)) {
+			return
+		}
 		state.allReady = false
 		// Use structured state classification
-		readyCondition := apimeta.FindStatusCondition(operand.Conditions, v1alpha1.Ready)
 		classification := classifyOperandState(operand, readyCondition)
 		if classification == operandProgressing {
 			state.notCreatedCount++
@@ -380,19 +482,24 @@ func processOperandStatus(operand v1alpha1.OperandStatus, state *operandAggregat
 	}
 }
 
-// aggregateOperandStatus collects status from all managed operand CRs
-func (r *ZeroTrustWorkloadIdentityManagerReconciler) aggregateOperandStatus(ctx context.Context) operandAggregateResult {
+// aggregateOperandStatus collects status from the operand CRs deployed in this cluster of the topology
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) aggregateOperandStatus(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) operandAggregateResult {
 	// Initialize aggregate state
 	state := &operandAggregateState{
 		allReady: true,
 	}
 
-	// Collect status from all operands
-	operandStatuses := []v1alpha1.OperandStatus{
-		r.getSpireServerStatus(ctx),
-		r.getSpireAgentStatus(ctx),
-		r.getSpiffeCSIDriverStatus(ctx),
-		r.getSpireOIDCDiscoveryProviderStatus(ctx),
+	// Collect status from the operands, the operands of the other cluster of the topology are reported there
+	var operandStatuses []v1alpha1.OperandStatus
+	for _, getStatus := range []func(context.Context) v1alpha1.OperandStatus{
+		r.getSpireServerStatus,
+		r.getSpireAgentStatus,
+		r.getSpiffeCSIDriverStatus,
+		r.getSpireOIDCDiscoveryProviderStatus,
+	} {
+		if operand := getStatus(ctx); utils.IsOperandDeployed(config, operand.Kind) {
+			operandStatuses = append(operandStatuses, operand)
+		}
 	}
 
 	// Process each operand status
@@ -490,7 +597,7 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) getSpireOIDCDiscoveryProvid
 }
 
 // extractKeyConditions extracts key conditions from operand status
-// Includes CreateOnlyMode condition when enabled (for visibility on operand status)
+// Includes CreateOnlyMode, DryRunMode and Paused conditions when enabled (for visibility on operand status)
 // When operand is not ready, also includes Ready condition and other failed conditions
 func extractKeyConditions(conditions []metav1.Condition, isReady bool) []metav1.Condition {
 	keyConditions := []metav1.Condition{}
@@ -502,7 +609,19 @@ func extractKeyConditions(conditions []metav1.Condition, isReady bool) []metav1.
 		keyConditions = append(keyConditions, *createOnlyCondition)
 	}
 
-	// If operand is ready, return only the CreateOnlyMode condition if present (reduces clutter)
+	// Include DryRunMode condition only when enabled, so previews are visible on the ZTWIM status
+	dryRunCondition := apimeta.FindStatusCondition(conditions, utils.DryRunModeStatusType)
+	if dryRunCondition != nil && dryRunCondition.Status == metav1.ConditionTrue {
+		keyConditions = append(keyConditions, *dryRunCondition)
+	}
+
+	// Include Paused condition only when reconciliation of the operand is paused
+	pausedCondition := apimeta.FindStatusCondition(conditions, utils.PausedStatusType)
+	if pausedCondition != nil && pausedCondition.Status == metav1.ConditionTrue {
+		keyConditions = append(keyConditions, *pausedCondition)
+	}
+
+	// If operand is ready, return only the CreateOnlyMode, DryRunMode and Paused conditions if present (reduces clutter)
 	if isReady {
 		return keyConditions
 	}
@@ -516,7 +635,7 @@ func extractKeyConditions(conditions []metav1.Condition, isReady bool) []metav1.
 	// Also include other failed conditions to show what's wrong
 	for _, cond := range conditions {
 		// Skip conditions we've already checked
-		if cond.Type == v1alpha1.Ready || cond.Type == utils.CreateOnlyModeStatusType {
+		if cond.Type == v1alpha1.Ready || cond.Type == utils.CreateOnlyModeStatusType || cond.Type == utils.DryRunModeStatusType || cond.Type == utils.PausedStatusType {
 			continue
 		}
 
@@ -572,6 +691,35 @@ var operandStatusChangedPredicate = predicate.Funcs{
 	},
 }
 
+// operandRollouts are the operand conditions reporting a rollout in flight. The SPIRE server applies the
+// CA changes and runs the datastore schema migrations while it restarts, so its rollout covers both.
+var operandRollouts = []struct {
+	kind          string
+	conditionType string
+	reason        string
+	operation     string
+}{
+	{kind: "SpireServer", conditionType: "StatefulSetAvailable", reason: "StatefulSetNotReady", operation: "SPIRE server rollout"},
+	{kind: "SpireAgent", conditionType: "DaemonSetAvailable", reason: "DaemonSetNotReady", operation: "SPIRE agent rollout"},
+}
+
+// inFlightOperations returns the operand rollouts that must complete before OLM replaces the operator
+func inFlightOperations(operandStatuses []v1alpha1.OperandStatus) []string {
+	var operations []string
+	for _, operand := range operandStatuses {
+		for _, rollout := range operandRollouts {
+			if operand.Kind != rollout.kind {
+				continue
+			}
+			condition := apimeta.FindStatusCondition(operand.Conditions, rollout.conditionType)
+			if condition != nil && condition.Status == metav1.ConditionFalse && condition.Reason == rollout.reason {
+				operations = append(operations, rollout.operation)
+			}
+		}
+	}
+	return operations
+}
+
 // updateOperatorCondition syncs the Upgradeable condition to the OperatorCondition resource for OLM
 // The Upgradeable condition is only set on OperatorCondition, not on the ZTWIM CR
 func (r *ZeroTrustWorkloadIdentityManagerReconciler) updateOperatorCondition(ctx context.Context, anyCreateOnlyModeEnabled bool, operandStatuses []v1alpha1.OperandStatus) error {
@@ -596,6 +744,11 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) updateOperatorCondition(ctx
 		upgradeableStatus = metav1.ConditionFalse
 		upgradeableReason = v1alpha1.ReasonOperandsNotReady
 		upgradeableMessage = "Not safe to upgrade - create-only mode is enabled on one or more operands"
+	} else if operations := inFlightOperations(operandStatuses); len(operations) > 0 {
+		// Replacing the operator mid-rollout could leave the identity plane half-migrated
+		upgradeableStatus = metav1.ConditionFalse
+		upgradeableReason = v1alpha1.ReasonOperationInProgress
+		upgradeableMessage = fmt.Sprintf("Not safe to upgrade - operations are in progress: %v", operations)
 	} else {
 		// Check if any operands exist but are not ready
 		// CRs that don't exist (CR not found) are OK for upgrade
@@ -680,12 +833,18 @@ func (r *ZeroTrustWorkloadIdentityManagerReconciler) SetupWithManager(mgr ctrl.M
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.ZeroTrustWorkloadIdentityManager{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Named(utils.ZeroTrustWorkloadIdentityManagerControllerName).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: utils.MaxConcurrentReconciles(utils.ZeroTrustWorkloadIdentityManagerControllerName),
+			RateLimiter:             utils.NewRateLimiter(),
+		}).
 		Watches(&operatorv1.OperatorCondition{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(operandStatusChangedPredicate)).
 		Watches(&v1alpha1.SpireServer{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(operandStatusChangedPredicate)).
 		Watches(&v1alpha1.SpireAgent{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(operandStatusChangedPredicate)).
 		Watches(&v1alpha1.SpiffeCSIDriver{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(operandStatusChangedPredicate)).
 		Watches(&v1alpha1.SpireOIDCDiscoveryProvider{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(operandStatusChangedPredicate)).
-		Complete(r)
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(managedNamespacePredicate)).
+		WatchesRawSource(source.Channel(operatorconfig.Subscribe(), handler.EnqueueRequestsFromMapFunc(mapFunc))).
+		Complete(tracing.WrapReconciler(utils.ZeroTrustWorkloadIdentityManagerControllerName, r))
 	if err != nil {
 		return err
 	}
@@ -8,10 +8,13 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	customClient "github.com/openshift/zero-trust-workload-identity-manager/pkg/client"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
 	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	operatorv1 "github.com/operator-framework/api/pkg/operators/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -762,6 +765,96 @@ func TestUpdateOperatorCondition_OperandsNotReady(t *testing.T) {
 	}
 }
 
+// TestUpdateOperatorCondition_OperationInProgress tests that an operand rollout blocks the upgrade with its own reason
+func TestUpdateOperatorCondition_OperationInProgress(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newTestReconciler(fakeClient)
+
+	fakeClient.GetReturns(nil)
+	fakeClient.StatusUpdateWithRetryReturns(nil)
+
+	operandStatuses := []v1alpha1.OperandStatus{
+		{
+			Kind:  "SpireAgent",
+			Name:  "cluster",
+			Ready: "false",
+			Conditions: []metav1.Condition{
+				{Type: "DaemonSetAvailable", Status: metav1.ConditionFalse, Reason: "DaemonSetNotReady"},
+			},
+		},
+	}
+
+	if err := reconciler.updateOperatorCondition(context.Background(), false, operandStatuses); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if fakeClient.StatusUpdateWithRetryCallCount() != 1 {
+		t.Fatal("Expected StatusUpdateWithRetry to be called once")
+	}
+	_, obj, _ := fakeClient.StatusUpdateWithRetryArgsForCall(0)
+	operatorCondition := obj.(*operatorv1.OperatorCondition)
+	condition := apimeta.FindStatusCondition(operatorCondition.Status.Conditions, v1alpha1.Upgradeable)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != v1alpha1.ReasonOperationInProgress {
+		t.Errorf("Expected Upgradeable=False with reason %s, got %v", v1alpha1.ReasonOperationInProgress, condition)
+	}
+}
+
+// TestInFlightOperations tests the detection of the operand rollouts
+func TestInFlightOperations(t *testing.T) {
+	rolling := func(kind, conditionType, reason string) v1alpha1.OperandStatus {
+		return v1alpha1.OperandStatus{
+			Kind:       kind,
+			Name:       "cluster",
+			Ready:      "false",
+			Conditions: []metav1.Condition{{Type: conditionType, Status: metav1.ConditionFalse, Reason: reason}},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		operandStatuses []v1alpha1.OperandStatus
+		expected        []string
+	}{
+		{
+			name: "no operands rolling out",
+			operandStatuses: []v1alpha1.OperandStatus{
+				{Kind: "SpireServer", Name: "cluster", Ready: "true"},
+				{Kind: "SpireAgent", Name: "cluster", Ready: "true"},
+			},
+		},
+		{
+			name:            "SPIRE server rollout",
+			operandStatuses: []v1alpha1.OperandStatus{rolling("SpireServer", "StatefulSetAvailable", "StatefulSetNotReady")},
+			expected:        []string{"SPIRE server rollout"},
+		},
+		{
+			name: "SPIRE server and agent rollouts",
+			operandStatuses: []v1alpha1.OperandStatus{
+				rolling("SpireServer", "StatefulSetAvailable", "StatefulSetNotReady"),
+				rolling("SpireAgent", "DaemonSetAvailable", "DaemonSetNotReady"),
+			},
+			expected: []string{"SPIRE server rollout", "SPIRE agent rollout"},
+		},
+		{
+			name:            "failure that is not a rollout",
+			operandStatuses: []v1alpha1.OperandStatus{rolling("SpireServer", "StatefulSetAvailable", "SpireServerStatefulSetUpdateFailed")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operations := inFlightOperations(tt.operandStatuses)
+			if len(operations) != len(tt.expected) {
+				t.Fatalf("Expected operations %v, got %v", tt.expected, operations)
+			}
+			for i := range operations {
+				if operations[i] != tt.expected[i] {
+					t.Errorf("Expected operations %v, got %v", tt.expected, operations)
+				}
+			}
+		})
+	}
+}
+
 // TestUpdateOperatorCondition_AllOperandsReady tests updateOperatorCondition with all ready operands
 func TestUpdateOperatorCondition_AllOperandsReady(t *testing.T) {
 	fakeClient := &fakes.FakeCustomCtrlClient{}
@@ -813,6 +906,28 @@ func TestUpdateOperatorCondition_StatusUpdateError(t *testing.T) {
 	}
 }
 
+// TestUpdateOperatorCondition_RetryExhausted tests that conflict retry exhaustion stays detectable by the caller
+func TestUpdateOperatorCondition_RetryExhausted(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	reconciler := newTestReconciler(fakeClient)
+
+	fakeClient.GetReturns(nil)
+	fakeClient.StatusUpdateWithRetryReturns(&customClient.RetryExhaustedError{
+		Key:      types.NamespacedName{Name: "test-operator-condition"},
+		Attempts: 5,
+		Err:      kerrors.NewConflict(schema.GroupResource{Resource: "operatorconditions"}, "test-operator-condition", errors.New("conflict")),
+	})
+
+	err := reconciler.updateOperatorCondition(context.Background(), false, []v1alpha1.OperandStatus{})
+
+	if !customClient.IsRetryExhausted(err) {
+		t.Errorf("Expected a retry exhausted error, got %v", err)
+	}
+	if !kerrors.IsConflict(err) {
+		t.Errorf("Expected the conflict to be unwrapped, got %v", err)
+	}
+}
+
 // TestUpdateOperatorCondition_CRNotFoundExcluded tests that CR not found operands don't block upgrade
 func TestUpdateOperatorCondition_CRNotFoundExcluded(t *testing.T) {
 	fakeClient := &fakes.FakeCustomCtrlClient{}
@@ -1085,6 +1200,25 @@ func TestProcessOperandStatus_NotReady(t *testing.T) {
 	}
 }
 
+// TestProcessOperandStatus_NotManaged tests that operands left unmanaged or removed do not affect the aggregate state
+func TestProcessOperandStatus_NotManaged(t *testing.T) {
+	for _, state := range []v1alpha1.ManagementState{v1alpha1.ManagementStateUnmanaged, v1alpha1.ManagementStateRemoved} {
+		operand := v1alpha1.OperandStatus{
+			Ready: "false",
+			Conditions: []metav1.Condition{
+				{Type: v1alpha1.Ready, Status: metav1.ConditionFalse, Reason: string(state)},
+			},
+		}
+
+		aggregate := &operandAggregateState{allReady: true}
+		processOperandStatus(operand, aggregate)
+
+		if !aggregate.allReady || aggregate.failedCount != 0 || aggregate.notCreatedCount != 0 {
+			t.Errorf("Expected a %s operand not to affect the aggregate state, got %+v", state, aggregate)
+		}
+	}
+}
+
 // TestExtractKeyConditions_MultipleConditions tests extractKeyConditions with multiple conditions
 func TestExtractKeyConditions_MultipleConditions(t *testing.T) {
 	conditions := []metav1.Condition{
@@ -1560,7 +1694,7 @@ func TestAggregateOperandStatus_AllNotFound(t *testing.T) {
 	// Return NotFound for all CRs
 	fakeClient.GetReturns(kerrors.NewNotFound(schema.GroupResource{}, "cluster"))
 
-	result := reconciler.aggregateOperandStatus(context.Background())
+	result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 	// Should have 4 operand statuses
 	if len(result.operandStatuses) != 4 {
@@ -1633,7 +1767,7 @@ func TestAggregateOperandStatus_AllReady(t *testing.T) {
 		return nil
 	}
 
-	result := reconciler.aggregateOperandStatus(context.Background())
+	result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 	// All should be ready
 	if !result.allReady {
@@ -1698,7 +1832,7 @@ func TestAggregateOperandStatus_MixedStates(t *testing.T) {
 		return nil
 	}
 
-	result := reconciler.aggregateOperandStatus(context.Background())
+	result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 	// Should not be all ready
 	if result.allReady {
@@ -1762,7 +1896,7 @@ func TestAggregateOperandStatus_WithCreateOnlyMode(t *testing.T) {
 		return nil
 	}
 
-	result := reconciler.aggregateOperandStatus(context.Background())
+	result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 	// All operands are ready and exist
 	if !result.allReady {
@@ -2611,7 +2745,7 @@ func TestAggregateOperandStatus(t *testing.T) {
 			return nil
 		}
 
-		result := reconciler.aggregateOperandStatus(context.Background())
+		result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 		if !result.allReady {
 			t.Error("Expected allReady to be true")
@@ -2638,7 +2772,7 @@ func TestAggregateOperandStatus(t *testing.T) {
 			return nil
 		}
 
-		result := reconciler.aggregateOperandStatus(context.Background())
+		result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 		if result.allReady {
 			t.Error("Expected allReady to be false")
@@ -3250,7 +3384,7 @@ func TestReconcile_ProgressingBranch(t *testing.T) {
 
 			tt.setupOperands(fakeClient)
 
-			result := reconciler.aggregateOperandStatus(context.Background())
+			result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 			if result.allReady != tt.expectAllReady {
 				t.Errorf("allReady = %v, expected %v", result.allReady, tt.expectAllReady)
@@ -3428,7 +3562,7 @@ func TestReconcile_ClassificationBranches(t *testing.T) {
 
 			tt.setupOperands(fakeClient)
 
-			result := reconciler.aggregateOperandStatus(context.Background())
+			result := reconciler.aggregateOperandStatus(context.Background(), &v1alpha1.ZeroTrustWorkloadIdentityManager{})
 
 			// Verify counts match expected
 			if len(tt.expectProgressing) > 0 && result.notCreatedCount == 0 {
@@ -3640,3 +3774,24 @@ func TestFindOperatorCondition_GetSucceeds(t *testing.T) {
 		t.Errorf("Expected no error when Get succeeds, got: %v", err)
 	}
 }
+
+// TestFeatureGatesMessage tests the message of the FeatureGates condition
+func TestFeatureGatesMessage(t *testing.T) {
+	config := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			FeatureGates: []v1alpha1.FeatureGate{
+				{Name: v1alpha1.FeatureGateFederation, Enabled: false},
+				{Name: v1alpha1.FeatureGateNestedTopology, Enabled: false},
+				{Name: v1alpha1.FeatureGateSpiffeHelperInjection, Enabled: false},
+			},
+		},
+	}
+	if got, expected := featureGatesMessage(config), "Enabled: none; Disabled: Federation, NestedTopology, SpiffeHelperInjection"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	config.Spec.FeatureGates[0].Enabled = true
+	if got, expected := featureGatesMessage(config), "Enabled: Federation; Disabled: NestedTopology, SpiffeHelperInjection"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,203 @@
+package zero_trust_workload_identity_manager
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// operandNamespaceFinalizer is added to the ZeroTrustWorkloadIdentityManager while the operand
+	// namespace is managed, so that the namespace created by the operator is deleted with it
+	operandNamespaceFinalizer = "operator.openshift.io/operand-namespace-cleanup"
+
+	// nodeSelectorAnnotationKey restricts the nodes the pods of the namespace are scheduled on
+	nodeSelectorAnnotationKey = "openshift.io/node-selector"
+)
+
+// operandNamespaceLabels are the labels the operands require on their namespace: the SPIRE agents and
+// the SPIFFE CSI driver mount host paths, which only the privileged pod security level admits, and the
+// operand metrics are scraped by the cluster monitoring stack.
+var operandNamespaceLabels = map[string]string{
+	"pod-security.kubernetes.io/enforce":             "privileged",
+	"pod-security.kubernetes.io/audit":               "privileged",
+	"pod-security.kubernetes.io/warn":                "privileged",
+	"security.openshift.io/scc.podSecurityLabelSync": "false",
+	"openshift.io/cluster-monitoring":                "true",
+}
+
+// managedNamespacePredicate triggers a reconcile when the operand namespace created by the operator changes
+var managedNamespacePredicate = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	return obj.GetLabels()[utils.AppManagedByLabelKey] == utils.AppManagedByLabelValue
+})
+
+// isOperandNamespaceManaged reports whether the operator manages the operand namespace. The operator
+// namespace is never managed, as deleting it would remove the operator.
+func isOperandNamespaceManaged(config *v1alpha1.ZeroTrustWorkloadIdentityManager) bool {
+	management := config.Spec.OperandNamespaceManagement
+	return management != nil && utils.StringToBool(management.Enabled) &&
+		config.Spec.OperandNamespace != "" && config.Spec.OperandNamespace != utils.GetOperatorNamespace()
+}
+
+// generateOperandNamespace returns the desired labels and annotations of the operand namespace
+func generateOperandNamespace(config *v1alpha1.ZeroTrustWorkloadIdentityManager) *corev1.Namespace {
+	labels := maps.Clone(config.Spec.OperandNamespaceManagement.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	maps.Copy(labels, operandNamespaceLabels)
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   config.Spec.OperandNamespace,
+			Labels: labels,
+			Annotations: map[string]string{
+				nodeSelectorAnnotationKey: config.Spec.OperandNamespaceManagement.NodeSelector,
+			},
+		},
+	}
+}
+
+// reconcileOperandNamespace creates the operand namespace when the operator manages it, and keeps its
+// labels and annotations in place. Only the namespaces created by the operator are labeled as managed
+// by it, so that a pre-existing namespace is kept when the ZeroTrustWorkloadIdentityManager is deleted.
+// Disabling the management leaves the namespace as is.
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) reconcileOperandNamespace(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager, statusMgr *status.Manager) error {
+	if !isOperandNamespaceManaged(config) {
+		if controllerutil.RemoveFinalizer(config, operandNamespaceFinalizer) {
+			if err := r.ctrlClient.Update(ctx, config); err != nil {
+				return fmt.Errorf("failed to remove finalizer from ZeroTrustWorkloadIdentityManager: %w", err)
+			}
+		}
+		return nil
+	}
+
+	if controllerutil.AddFinalizer(config, operandNamespaceFinalizer) {
+		if err := r.ctrlClient.Update(ctx, config); err != nil {
+			return fmt.Errorf("failed to add finalizer to ZeroTrustWorkloadIdentityManager: %w", err)
+		}
+	}
+
+	desired := generateOperandNamespace(config)
+	var existing corev1.Namespace
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: desired.Name}, &existing)
+	if err != nil && apierror.IsNotFound(err) {
+		desired.Labels[utils.AppManagedByLabelKey] = utils.AppManagedByLabelValue
+		if err := r.ctrlClient.Create(ctx, desired); err != nil {
+			statusMgr.AddCondition(OperandNamespaceAvailable, "NamespaceCreationFailed",
+				fmt.Sprintf("Failed to create the operand namespace %s: %v", desired.Name, err),
+				metav1.ConditionFalse)
+			return fmt.Errorf("failed to create the operand namespace: %w", err)
+		}
+		r.log.Info("Created the operand namespace", "namespace", desired.Name)
+	} else if err != nil {
+		statusMgr.AddCondition(OperandNamespaceAvailable, "NamespaceCreationFailed",
+			fmt.Sprintf("Failed to get the operand namespace %s: %v", desired.Name, err),
+			metav1.ConditionFalse)
+		return fmt.Errorf("failed to get the operand namespace: %w", err)
+	} else if needsNamespaceUpdate(&existing, desired) {
+		if existing.Labels == nil {
+			existing.Labels = map[string]string{}
+		}
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		maps.Copy(existing.Labels, desired.Labels)
+		maps.Copy(existing.Annotations, desired.Annotations)
+		if err := r.ctrlClient.Update(ctx, &existing); err != nil {
+			statusMgr.AddCondition(OperandNamespaceAvailable, "NamespaceUpdateFailed",
+				fmt.Sprintf("Failed to update the operand namespace %s: %v", desired.Name, err),
+				metav1.ConditionFalse)
+			return fmt.Errorf("failed to update the operand namespace: %w", err)
+		}
+		r.log.Info("Updated the operand namespace", "namespace", desired.Name)
+	}
+
+	statusMgr.AddCondition(OperandNamespaceAvailable, "NamespaceReady",
+		fmt.Sprintf("The operand namespace %s is managed by the operator", desired.Name),
+		metav1.ConditionTrue)
+	return nil
+}
+
+// needsNamespaceUpdate reports whether a label or an annotation of desired is missing from existing
+func needsNamespaceUpdate(existing, desired *corev1.Namespace) bool {
+	for key, value := range desired.Labels {
+		if existing.Labels[key] != value {
+			return true
+		}
+	}
+	for key, value := range desired.Annotations {
+		if current, ok := existing.Annotations[key]; !ok || current != value {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileDeletion deletes the operand namespace created by the operator before the
+// ZeroTrustWorkloadIdentityManager is removed. The operand CRs are garbage collected
+// through their owner references and tear down their resources on their own.
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) reconcileDeletion(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	if !controllerutil.ContainsFinalizer(config, operandNamespaceFinalizer) {
+		return nil
+	}
+
+	if err := r.deleteOperandNamespace(ctx, config); err != nil {
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(config, operandNamespaceFinalizer)
+	if err := r.ctrlClient.Update(ctx, config); err != nil {
+		return fmt.Errorf("failed to remove finalizer from ZeroTrustWorkloadIdentityManager: %w", err)
+	}
+	return nil
+}
+
+// removeOperandNamespace deletes the operand namespace created by the operator when the management state
+// of the ZeroTrustWorkloadIdentityManager is Removed. The finalizer is kept, so that the namespace is
+// managed again once the state is set back to Managed.
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) removeOperandNamespace(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager, statusMgr *status.Manager) error {
+	if !controllerutil.ContainsFinalizer(config, operandNamespaceFinalizer) {
+		return nil
+	}
+	if err := r.deleteOperandNamespace(ctx, config); err != nil {
+		statusMgr.AddCondition(OperandNamespaceAvailable, "NamespaceDeletionFailed",
+			fmt.Sprintf("Failed to delete the operand namespace %s: %v", config.Spec.OperandNamespace, err),
+			metav1.ConditionFalse)
+		return err
+	}
+	statusMgr.AddCondition(OperandNamespaceAvailable, "NamespaceRemoved",
+		fmt.Sprintf("The operand namespace %s is removed", config.Spec.OperandNamespace),
+		metav1.ConditionFalse)
+	return nil
+}
+
+// deleteOperandNamespace deletes the operand namespace when it was created by the operator
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) deleteOperandNamespace(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) error {
+	if config.Spec.OperandNamespace == "" || config.Spec.OperandNamespace == utils.GetOperatorNamespace() {
+		return nil
+	}
+	var namespace corev1.Namespace
+	err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: config.Spec.OperandNamespace}, &namespace)
+	if err != nil && !apierror.IsNotFound(err) {
+		return fmt.Errorf("failed to get the operand namespace: %w", err)
+	}
+	if err == nil && namespace.Labels[utils.AppManagedByLabelKey] == utils.AppManagedByLabelValue && namespace.DeletionTimestamp.IsZero() {
+		if err := utils.DeleteObjects(ctx, r.ctrlClient, &namespace); err != nil {
+			return err
+		}
+		r.log.Info("Deleted the operand namespace", "namespace", namespace.Name)
+	}
+	return nil
+}
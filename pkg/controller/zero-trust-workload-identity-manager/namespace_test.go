@@ -0,0 +1,250 @@
+package zero_trust_workload_identity_manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func newManagedNamespaceZTWIM() *v1alpha1.ZeroTrustWorkloadIdentityManager {
+	return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain:      "example.org",
+			ClusterName:      "test-cluster",
+			OperandNamespace: "spire",
+			OperandNamespaceManagement: &v1alpha1.OperandNamespaceManagementConfig{
+				Enabled: "true",
+				Labels:  map[string]string{"team": "security", "openshift.io/cluster-monitoring": "false"},
+			},
+		},
+	}
+}
+
+func namespaceNotFound() error {
+	return kerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "spire")
+}
+
+func TestIsOperandNamespaceManaged(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "zero-trust-workload-identity-manager")
+
+	config := newManagedNamespaceZTWIM()
+	if !isOperandNamespaceManaged(config) {
+		t.Error("Expected the operand namespace to be managed")
+	}
+
+	config.Spec.OperandNamespaceManagement.Enabled = "false"
+	if isOperandNamespaceManaged(config) {
+		t.Error("Expected the operand namespace not to be managed when disabled")
+	}
+
+	config = newManagedNamespaceZTWIM()
+	config.Spec.OperandNamespace = "zero-trust-workload-identity-manager"
+	if isOperandNamespaceManaged(config) {
+		t.Error("Expected the operator namespace never to be managed")
+	}
+
+	config.Spec.OperandNamespace = ""
+	if isOperandNamespaceManaged(config) {
+		t.Error("Expected the default operand namespace, the operator namespace, not to be managed")
+	}
+}
+
+func TestGenerateOperandNamespace(t *testing.T) {
+	namespace := generateOperandNamespace(newManagedNamespaceZTWIM())
+
+	if namespace.Name != "spire" {
+		t.Errorf("Expected namespace spire, got %s", namespace.Name)
+	}
+	expectedLabels := map[string]string{
+		"team":                                           "security",
+		"pod-security.kubernetes.io/enforce":             "privileged",
+		"pod-security.kubernetes.io/audit":               "privileged",
+		"pod-security.kubernetes.io/warn":                "privileged",
+		"security.openshift.io/scc.podSecurityLabelSync": "false",
+		"openshift.io/cluster-monitoring":                "true",
+	}
+	for key, value := range expectedLabels {
+		if namespace.Labels[key] != value {
+			t.Errorf("Expected label %s=%s, got %q", key, value, namespace.Labels[key])
+		}
+	}
+	if value, ok := namespace.Annotations[nodeSelectorAnnotationKey]; !ok || value != "" {
+		t.Errorf("Expected an empty node selector annotation, got %q (set: %v)", value, ok)
+	}
+}
+
+func TestReconcileOperandNamespace(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "zero-trust-workload-identity-manager")
+
+	t.Run("creates the missing namespace", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newTestReconciler(fakeClient)
+		fakeClient.GetReturns(namespaceNotFound())
+		config := newManagedNamespaceZTWIM()
+
+		if err := reconciler.reconcileOperandNamespace(context.Background(), config, status.NewManager(fakeClient)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !controllerutil.ContainsFinalizer(config, operandNamespaceFinalizer) {
+			t.Error("Expected the namespace finalizer to be added")
+		}
+		if fakeClient.CreateCallCount() != 1 {
+			t.Fatalf("Expected the namespace to be created, got %d creates", fakeClient.CreateCallCount())
+		}
+		_, obj, _ := fakeClient.CreateArgsForCall(0)
+		created := obj.(*corev1.Namespace)
+		if created.Labels[utils.AppManagedByLabelKey] != utils.AppManagedByLabelValue {
+			t.Error("Expected the created namespace to be labeled as managed by the operator")
+		}
+	})
+
+	t.Run("adds the missing labels to an existing namespace", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newTestReconciler(fakeClient)
+		fakeClient.GetStub = func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*corev1.Namespace).ObjectMeta = metav1.ObjectMeta{Name: "spire", Labels: map[string]string{"kubernetes.io/metadata.name": "spire"}}
+			return nil
+		}
+
+		if err := reconciler.reconcileOperandNamespace(context.Background(), newManagedNamespaceZTWIM(), status.NewManager(fakeClient)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fakeClient.CreateCallCount() != 0 {
+			t.Error("Expected no namespace to be created")
+		}
+		// The first update adds the finalizer, the second one the namespace labels
+		if fakeClient.UpdateCallCount() != 2 {
+			t.Fatalf("Expected 2 updates, got %d", fakeClient.UpdateCallCount())
+		}
+		_, obj, _ := fakeClient.UpdateArgsForCall(1)
+		updated := obj.(*corev1.Namespace)
+		if updated.Labels["pod-security.kubernetes.io/enforce"] != "privileged" || updated.Labels["kubernetes.io/metadata.name"] != "spire" {
+			t.Errorf("Expected the labels to be merged, got %v", updated.Labels)
+		}
+		if _, ok := updated.Labels[utils.AppManagedByLabelKey]; ok {
+			t.Error("Expected a pre-existing namespace not to be labeled as managed by the operator")
+		}
+	})
+
+	t.Run("removes the finalizer when disabled", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newTestReconciler(fakeClient)
+		config := newManagedNamespaceZTWIM()
+		config.Spec.OperandNamespaceManagement.Enabled = "false"
+		controllerutil.AddFinalizer(config, operandNamespaceFinalizer)
+
+		if err := reconciler.reconcileOperandNamespace(context.Background(), config, status.NewManager(fakeClient)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if controllerutil.ContainsFinalizer(config, operandNamespaceFinalizer) {
+			t.Error("Expected the namespace finalizer to be removed")
+		}
+		if fakeClient.GetCallCount() != 0 || fakeClient.DeleteCallCount() != 0 {
+			t.Error("Expected the namespace to be left as is")
+		}
+	})
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "zero-trust-workload-identity-manager")
+
+	deletingZTWIM := func() *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		config := newManagedNamespaceZTWIM()
+		now := metav1.NewTime(time.Now())
+		config.DeletionTimestamp = &now
+		controllerutil.AddFinalizer(config, operandNamespaceFinalizer)
+		return config
+	}
+
+	tests := []struct {
+		name         string
+		labels       map[string]string
+		expectDelete bool
+	}{
+		{
+			name:         "namespace created by the operator is deleted",
+			labels:       map[string]string{utils.AppManagedByLabelKey: utils.AppManagedByLabelValue},
+			expectDelete: true,
+		},
+		{
+			name:   "pre-existing namespace is kept",
+			labels: map[string]string{"team": "security"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := &fakes.FakeCustomCtrlClient{}
+			reconciler := newTestReconciler(fakeClient)
+			fakeClient.GetStub = func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				obj.(*corev1.Namespace).ObjectMeta = metav1.ObjectMeta{Name: "spire", Labels: tt.labels}
+				return nil
+			}
+			config := deletingZTWIM()
+
+			if err := reconciler.reconcileDeletion(context.Background(), config); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if deleted := fakeClient.DeleteCallCount() == 1; deleted != tt.expectDelete {
+				t.Errorf("Expected namespace deleted %v, got %v", tt.expectDelete, deleted)
+			}
+			if controllerutil.ContainsFinalizer(config, operandNamespaceFinalizer) {
+				t.Error("Expected the namespace finalizer to be removed")
+			}
+		})
+	}
+}
+
+func TestRemoveOperandNamespace(t *testing.T) {
+	t.Setenv("OPERATOR_NAMESPACE", "zero-trust-workload-identity-manager")
+
+	t.Run("deletes the namespace created by the operator and keeps the finalizer", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newTestReconciler(fakeClient)
+		fakeClient.GetStub = func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*corev1.Namespace).ObjectMeta = metav1.ObjectMeta{Name: "spire", Labels: map[string]string{utils.AppManagedByLabelKey: utils.AppManagedByLabelValue}}
+			return nil
+		}
+		config := newManagedNamespaceZTWIM()
+		config.Spec.ManagementState = v1alpha1.ManagementStateRemoved
+		controllerutil.AddFinalizer(config, operandNamespaceFinalizer)
+		statusMgr := status.NewManager(fakeClient)
+
+		if err := reconciler.removeOperandNamespace(context.Background(), config, statusMgr); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fakeClient.DeleteCallCount() != 1 {
+			t.Errorf("Expected the namespace to be deleted, got %d deletes", fakeClient.DeleteCallCount())
+		}
+		if !controllerutil.ContainsFinalizer(config, operandNamespaceFinalizer) || fakeClient.UpdateCallCount() != 0 {
+			t.Error("Expected the namespace finalizer to be kept")
+		}
+	})
+
+	t.Run("leaves an unmanaged namespace alone", func(t *testing.T) {
+		fakeClient := &fakes.FakeCustomCtrlClient{}
+		reconciler := newTestReconciler(fakeClient)
+		config := newManagedNamespaceZTWIM()
+		config.Spec.ManagementState = v1alpha1.ManagementStateRemoved
+
+		if err := reconciler.removeOperandNamespace(context.Background(), config, status.NewManager(fakeClient)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fakeClient.GetCallCount() != 0 || fakeClient.DeleteCallCount() != 0 {
+			t.Error("Expected the namespace not to be touched without the finalizer")
+		}
+	})
+}
@@ -0,0 +1,316 @@
+package zero_trust_workload_identity_manager
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// Condition types of the preflight checks, aggregated in utils.PreflightChecksPassedStatusType
+	PreflightProjectedServiceAccountTokens = "PreflightProjectedServiceAccountTokens"
+	PreflightCSIHostPath                   = "PreflightCSIHostPath"
+	PreflightPodSecurity                   = "PreflightPodSecurity"
+	PreflightSecurityContextConstraints    = "PreflightSecurityContextConstraints"
+	PreflightOIDCDiscoveryDNS              = "PreflightOIDCDiscoveryDNS"
+
+	// preflightRetryInterval is how long the failed preflight checks are run again after, as the
+	// prerequisites they verify are fixed outside of the resources watched by the operator
+	preflightRetryInterval = time.Minute
+
+	// podSecurityEnforceLabelKey sets the pod security level enforced on the pods of a namespace
+	podSecurityEnforceLabelKey = "pod-security.kubernetes.io/enforce"
+
+	// preflightNodesReported is the number of nodes named in the message of a failed check
+	preflightNodesReported = 5
+)
+
+// preflightResult is the outcome of a preflight check. A check not applicable to the cluster passes.
+type preflightResult struct {
+	passed  bool
+	reason  string
+	message string
+}
+
+// preflightCheck verifies a prerequisite of the operands on the cluster
+type preflightCheck struct {
+	conditionType string
+	// advisory checks are reported without holding back the deployment of the operands, as the
+	// prerequisite they verify may be met once the operands are deployed
+	advisory bool
+	run      func(context.Context, *v1alpha1.ZeroTrustWorkloadIdentityManager) preflightResult
+}
+
+// preflightChecks returns the checks of the preflight phase, in the order they are run
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) preflightChecks() []preflightCheck {
+	return []preflightCheck{
+		{conditionType: PreflightProjectedServiceAccountTokens, run: r.checkProjectedServiceAccountTokens},
+		{conditionType: PreflightPodSecurity, run: r.checkPodSecurity},
+		{conditionType: PreflightSecurityContextConstraints, run: r.checkSecurityContextConstraints},
+		{conditionType: PreflightCSIHostPath, advisory: true, run: r.checkCSIHostPath},
+		{conditionType: PreflightOIDCDiscoveryDNS, advisory: true, run: r.checkOIDCDiscoveryDNS},
+	}
+}
+
+// runPreflightChecks verifies the prerequisites of the operands on the cluster and reports each check in a
+// condition of the ZeroTrustWorkloadIdentityManager. The PreflightChecksPassed condition is False while a check
+// that is not advisory fails, which holds back the operands not deployed yet. It returns when the failed checks
+// are run again, zero when all the checks passed.
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) runPreflightChecks(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager, statusMgr *status.Manager) time.Duration {
+	if r.clientset == nil {
+		return 0
+	}
+
+	var failed, warnings []string
+	for _, check := range r.preflightChecks() {
+		result := check.run(ctx, config)
+		conditionStatus := metav1.ConditionTrue
+		if !result.passed {
+			conditionStatus = metav1.ConditionFalse
+			if check.advisory {
+				warnings = append(warnings, check.conditionType)
+			} else {
+				failed = append(failed, check.conditionType)
+			}
+		}
+		statusMgr.AddCondition(check.conditionType, result.reason, result.message, conditionStatus)
+	}
+
+	if len(failed) > 0 {
+		r.log.Info("Preflight checks failed, the operands not deployed yet are held back", "failed", failed, "warnings", warnings)
+		statusMgr.AddCondition(utils.PreflightChecksPassedStatusType, utils.PreflightChecksFailed,
+			fmt.Sprintf("The cluster does not meet the prerequisites of the operands: %s", strings.Join(failed, ", ")),
+			metav1.ConditionFalse)
+		return preflightRetryInterval
+	}
+	message := "The cluster meets the prerequisites of the operands"
+	if len(warnings) > 0 {
+		message = fmt.Sprintf("%s, with warnings: %s", message, strings.Join(warnings, ", "))
+	}
+	statusMgr.AddCondition(utils.PreflightChecksPassedStatusType, "PreflightChecksPassed", message, metav1.ConditionTrue)
+	if len(warnings) > 0 {
+		return preflightRetryInterval
+	}
+	return 0
+}
+
+// checkProjectedServiceAccountTokens verifies that the API server issues the projected service account tokens the
+// SPIRE agents attest with, and that the SpireServer accepts their audience
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) checkProjectedServiceAccountTokens(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) preflightResult {
+	if !utils.IsOperandDeployed(config, utils.ResourceKindSpireAgent) {
+		return preflightResult{passed: true, reason: "NotApplicable", message: "The SPIRE agents are not deployed in this cluster"}
+	}
+	var agent v1alpha1.SpireAgent
+	agentFound, err := r.getOperand(ctx, &agent)
+	if err != nil {
+		return preflightError(err)
+	}
+	if agentFound && agent.Spec.NodeAttestor != nil && agent.Spec.NodeAttestor.K8sPSATEnabled == "false" {
+		return preflightResult{passed: true, reason: "NotApplicable", message: "The SPIRE agents do not attest with projected service account tokens"}
+	}
+
+	resources, err := r.clientset.Discovery().ServerResourcesForGroupVersion("v1")
+	if err != nil {
+		return preflightError(fmt.Errorf("failed to discover the core API resources: %w", err))
+	}
+	if !slices.ContainsFunc(resources.APIResources, func(resource metav1.APIResource) bool {
+		return resource.Name == "serviceaccounts/token"
+	}) {
+		return preflightResult{reason: "TokenRequestUnavailable",
+			message: "The API server does not serve the TokenRequest API, the SPIRE agents can't attest with projected service account tokens"}
+	}
+
+	var server v1alpha1.SpireServer
+	serverFound, err := r.getOperand(ctx, &server)
+	if err != nil {
+		return preflightError(err)
+	}
+	if agentFound && serverFound {
+		audience := utils.DefaultK8sPSATAudience
+		if agent.Spec.NodeAttestor != nil && agent.Spec.NodeAttestor.K8sPSATAudience != "" {
+			audience = agent.Spec.NodeAttestor.K8sPSATAudience
+		}
+		accepted := []string{utils.DefaultK8sPSATAudience}
+		if server.Spec.NodeAttestor != nil && len(server.Spec.NodeAttestor.K8sPSATAudience) > 0 {
+			accepted = server.Spec.NodeAttestor.K8sPSATAudience
+		}
+		if !slices.Contains(accepted, audience) {
+			return preflightResult{reason: "AudienceNotAccepted",
+				message: fmt.Sprintf("The SpireServer does not accept the audience %q of the projected service account tokens of the SPIRE agents, accepted audiences: %s",
+					audience, strings.Join(accepted, ", "))}
+		}
+	}
+	return preflightResult{passed: true, reason: "TokenRequestAvailable", message: "The API server issues the projected service account tokens of the SPIRE agents"}
+}
+
+// checkPodSecurity verifies that the pod security level enforced on the operand namespace admits the SPIRE agents
+// and the SPIFFE CSI driver, which mount host paths
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) checkPodSecurity(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) preflightResult {
+	if !utils.IsOperandDeployed(config, utils.ResourceKindSpireAgent) && !utils.IsOperandDeployed(config, utils.ResourceKindSpiffeCSIDriver) {
+		return preflightResult{passed: true, reason: "NotApplicable", message: "No privileged operand is deployed in this cluster"}
+	}
+	name := config.Spec.OperandNamespace
+	if name == "" {
+		name = utils.GetOperatorNamespace()
+	}
+	namespace, err := r.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if apierror.IsNotFound(err) {
+		return preflightResult{reason: "NamespaceNotFound", message: fmt.Sprintf("The operand namespace %s does not exist", name)}
+	}
+	if err != nil {
+		return preflightError(fmt.Errorf("failed to get the operand namespace %s: %w", name, err))
+	}
+	if level := namespace.Labels[podSecurityEnforceLabelKey]; level != "" && level != "privileged" {
+		return preflightResult{reason: "PodSecurityRestricted",
+			message: fmt.Sprintf("The operand namespace %s enforces the %s pod security level, the SPIRE agents and the SPIFFE CSI driver mount host paths and require the privileged level",
+				name, level)}
+	}
+	return preflightResult{passed: true, reason: "PodSecurityPrivileged",
+		message: fmt.Sprintf("The operand namespace %s admits the privileged operands", name)}
+}
+
+// checkSecurityContextConstraints verifies that the operator may create the SecurityContextConstraints the SPIRE
+// agents and the SPIFFE CSI driver are admitted with, on the clusters serving them
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) checkSecurityContextConstraints(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) preflightResult {
+	if _, err := r.clientset.Discovery().ServerResourcesForGroupVersion("security.openshift.io/v1"); apierror.IsNotFound(err) {
+		return preflightResult{passed: true, reason: "NotApplicable", message: "The cluster does not serve SecurityContextConstraints"}
+	} else if err != nil {
+		return preflightError(fmt.Errorf("failed to discover the SecurityContextConstraints API: %w", err))
+	}
+
+	review, err := r.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    "security.openshift.io",
+				Resource: "securitycontextconstraints",
+				Verb:     "create",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return preflightError(fmt.Errorf("failed to review the SecurityContextConstraints permissions: %w", err))
+	}
+	if !review.Status.Allowed {
+		return preflightResult{reason: "PermissionDenied",
+			message: "The operator is not allowed to create the SecurityContextConstraints of the SPIRE agents and the SPIFFE CSI driver"}
+	}
+	return preflightResult{passed: true, reason: "PermissionGranted",
+		message: "The operator may create the SecurityContextConstraints of the SPIRE agents and the SPIFFE CSI driver"}
+}
+
+// checkCSIHostPath verifies that the kubelets of the nodes run the CSI plugin manager the SPIFFE CSI driver registers
+// with, through the plugin directory below the kubeletPath of the SpiffeCSIDriver
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) checkCSIHostPath(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) preflightResult {
+	if !utils.IsOperandDeployed(config, utils.ResourceKindSpiffeCSIDriver) {
+		return preflightResult{passed: true, reason: "NotApplicable", message: "The SPIFFE CSI driver is not deployed in this cluster"}
+	}
+	var driver v1alpha1.SpiffeCSIDriver
+	if _, err := r.getOperand(ctx, &driver); err != nil {
+		return preflightError(err)
+	}
+	kubeletPath := driver.Spec.KubeletPath
+	if kubeletPath == "" {
+		kubeletPath = "/var/lib/kubelet"
+	}
+
+	nodes, err := r.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return preflightError(fmt.Errorf("failed to list the nodes: %w", err))
+	}
+	csiNodes, err := r.clientset.StorageV1().CSINodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return preflightError(fmt.Errorf("failed to list the CSINodes: %w", err))
+	}
+	registered := make(map[string]bool, len(csiNodes.Items))
+	for _, csiNode := range csiNodes.Items {
+		registered[csiNode.Name] = true
+	}
+	var missing []string
+	for _, node := range nodes.Items {
+		if !registered[node.Name] {
+			missing = append(missing, node.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return preflightResult{reason: "KubeletPluginsUnavailable",
+			message: fmt.Sprintf("The kubelets of the nodes %s do not report a CSINode, the SPIFFE CSI driver can't register through %s/plugins_registry",
+				summarizeNodes(missing), kubeletPath)}
+	}
+	return preflightResult{passed: true, reason: "KubeletPluginsAvailable",
+		message: fmt.Sprintf("The kubelets of the nodes accept the CSI plugins registered through %s/plugins_registry", kubeletPath)}
+}
+
+// checkOIDCDiscoveryDNS verifies that the host of the JWT issuer served by the SPIRE OIDC discovery provider resolves
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) checkOIDCDiscoveryDNS(ctx context.Context, config *v1alpha1.ZeroTrustWorkloadIdentityManager) preflightResult {
+	notApplicable := preflightResult{passed: true, reason: "NotApplicable", message: "No JWT issuer is served by the SPIRE OIDC discovery provider in this cluster"}
+	if !utils.IsOperandDeployed(config, utils.ResourceKindSpireOIDCDiscoveryProvider) {
+		return notApplicable
+	}
+	var oidc v1alpha1.SpireOIDCDiscoveryProvider
+	found, err := r.getOperand(ctx, &oidc)
+	if err != nil {
+		return preflightError(err)
+	}
+	if !found {
+		return notApplicable
+	}
+	issuer := oidc.Spec.JwtIssuer
+	if issuer == "" {
+		// The SPIRE OIDC discovery provider serves the JWT issuer of the SpireServer when unset
+		var server v1alpha1.SpireServer
+		if _, err := r.getOperand(ctx, &server); err != nil {
+			return preflightError(err)
+		}
+		issuer = server.Spec.JwtIssuer
+	}
+	issuerURL, err := url.Parse(issuer)
+	if err != nil || issuerURL.Hostname() == "" {
+		return notApplicable
+	}
+	host := issuerURL.Hostname()
+	if net.ParseIP(host) != nil {
+		return preflightResult{passed: true, reason: "HostResolved", message: fmt.Sprintf("The JWT issuer host %s is an IP address", host)}
+	}
+	if _, err := r.lookupHost(ctx, host); err != nil {
+		return preflightResult{reason: "HostNotResolved",
+			message: fmt.Sprintf("The JWT issuer host %s does not resolve, the relying parties can't fetch the OIDC discovery document: %v", host, err)}
+	}
+	return preflightResult{passed: true, reason: "HostResolved", message: fmt.Sprintf("The JWT issuer host %s resolves", host)}
+}
+
+// getOperand gets the operand CR named cluster into obj, and reports whether it exists
+func (r *ZeroTrustWorkloadIdentityManagerReconciler) getOperand(ctx context.Context, obj operandStatusGetter) (bool, error) {
+	if err := r.ctrlClient.Get(ctx, types.NamespacedName{Name: "cluster"}, obj); err != nil {
+		if apierror.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get %T: %w", obj, err)
+	}
+	return true, nil
+}
+
+// preflightError reports a check that could not be run, which is retried as a failure
+func preflightError(err error) preflightResult {
+	return preflightResult{reason: "CheckFailed", message: err.Error()}
+}
+
+// summarizeNodes lists the first nodes of names and the count of the others
+func summarizeNodes(names []string) string {
+	if len(names) <= preflightNodesReported {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:preflightNodesReported], ", "), len(names)-preflightNodesReported)
+}
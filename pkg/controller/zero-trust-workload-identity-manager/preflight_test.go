@@ -0,0 +1,245 @@
+package zero_trust_workload_identity_manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/client/fakes"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/status"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// preflightCluster describes the cluster the preflight checks run against
+type preflightCluster struct {
+	namespaceLabels map[string]string
+	nodes           []string
+	csiNodes        []string
+	tokenRequest    bool
+	openShift       bool
+	sccAllowed      bool
+	resolveErr      error
+	agent           *v1alpha1.SpireAgent
+	server          *v1alpha1.SpireServer
+	oidc            *v1alpha1.SpireOIDCDiscoveryProvider
+}
+
+func newPreflightCluster() *preflightCluster {
+	return &preflightCluster{
+		namespaceLabels: map[string]string{podSecurityEnforceLabelKey: "privileged"},
+		nodes:           []string{"worker-0", "worker-1"},
+		csiNodes:        []string{"worker-0", "worker-1"},
+		tokenRequest:    true,
+		openShift:       true,
+		sccAllowed:      true,
+		agent:           &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		server:          &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: v1alpha1.SpireServerSpec{JwtIssuer: "https://oidc-discovery.example.org"}},
+		oidc:            &v1alpha1.SpireOIDCDiscoveryProvider{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+	}
+}
+
+// reconciler returns a reconciler running the preflight checks against the cluster
+func (c *preflightCluster) reconciler() *ZeroTrustWorkloadIdentityManagerReconciler {
+	objects := []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "spire", Labels: c.namespaceLabels}}}
+	for _, node := range c.nodes {
+		objects = append(objects, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: node}})
+	}
+	for _, node := range c.csiNodes {
+		objects = append(objects, &storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: node}})
+	}
+	clientset := k8sfake.NewSimpleClientset(objects...)
+
+	coreResources := &metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "serviceaccounts"}}}
+	if c.tokenRequest {
+		coreResources.APIResources = append(coreResources.APIResources, metav1.APIResource{Name: "serviceaccounts/token"})
+	}
+	clientset.Resources = []*metav1.APIResourceList{coreResources}
+	if c.openShift {
+		clientset.Resources = append(clientset.Resources, &metav1.APIResourceList{
+			GroupVersion: "security.openshift.io/v1",
+			APIResources: []metav1.APIResource{{Name: "securitycontextconstraints"}},
+		})
+	}
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1.SelfSubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: c.sccAllowed}}, nil
+	})
+
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	fakeClient.GetStub = func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch v := obj.(type) {
+		case *v1alpha1.SpireAgent:
+			*v = *c.agent
+		case *v1alpha1.SpireServer:
+			*v = *c.server
+		case *v1alpha1.SpireOIDCDiscoveryProvider:
+			*v = *c.oidc
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		return nil
+	}
+
+	reconciler := newTestReconciler(fakeClient)
+	reconciler.clientset = clientset
+	reconciler.lookupHost = func(context.Context, string) ([]string, error) {
+		if c.resolveErr != nil {
+			return nil, c.resolveErr
+		}
+		return []string{"192.0.2.10"}, nil
+	}
+	return reconciler
+}
+
+// runPreflight runs the preflight checks against the cluster and returns the resulting conditions
+func (c *preflightCluster) runPreflight(t *testing.T) ([]metav1.Condition, time.Duration) {
+	t.Helper()
+	reconciler := c.reconciler()
+	config := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{OperandNamespace: "spire"},
+	}
+	statusMgr := status.NewManager(reconciler.ctrlClient)
+	retry := reconciler.runPreflightChecks(context.Background(), config, statusMgr)
+	if err := statusMgr.ApplyStatus(context.Background(), config, func() *v1alpha1.ConditionalStatus {
+		return &config.Status.ConditionalStatus
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return config.Status.Conditions, retry
+}
+
+func TestRunPreflightChecks(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(*preflightCluster)
+		conditionType  string
+		expectedReason string
+		expectPassed   bool
+	}{
+		{
+			name:           "restricted operand namespace",
+			modify:         func(c *preflightCluster) { c.namespaceLabels[podSecurityEnforceLabelKey] = "restricted" },
+			conditionType:  PreflightPodSecurity,
+			expectedReason: "PodSecurityRestricted",
+		},
+		{
+			name:           "no TokenRequest API",
+			modify:         func(c *preflightCluster) { c.tokenRequest = false },
+			conditionType:  PreflightProjectedServiceAccountTokens,
+			expectedReason: "TokenRequestUnavailable",
+		},
+		{
+			name: "agent audience not accepted by the server",
+			modify: func(c *preflightCluster) {
+				c.agent.Spec.NodeAttestor = &v1alpha1.NodeAttestor{K8sPSATAudience: "spire"}
+			},
+			conditionType:  PreflightProjectedServiceAccountTokens,
+			expectedReason: "AudienceNotAccepted",
+		},
+		{
+			name:           "SecurityContextConstraints not allowed",
+			modify:         func(c *preflightCluster) { c.sccAllowed = false },
+			conditionType:  PreflightSecurityContextConstraints,
+			expectedReason: "PermissionDenied",
+		},
+		{
+			name:           "SecurityContextConstraints not served",
+			modify:         func(c *preflightCluster) { c.openShift = false; c.sccAllowed = false },
+			conditionType:  PreflightSecurityContextConstraints,
+			expectedReason: "NotApplicable",
+			expectPassed:   true,
+		},
+		{
+			name:           "node without a CSINode is a warning",
+			modify:         func(c *preflightCluster) { c.nodes = append(c.nodes, "worker-2") },
+			conditionType:  PreflightCSIHostPath,
+			expectedReason: "KubeletPluginsUnavailable",
+			expectPassed:   true,
+		},
+		{
+			name:           "unresolvable JWT issuer host is a warning",
+			modify:         func(c *preflightCluster) { c.resolveErr = errors.New("no such host") },
+			conditionType:  PreflightOIDCDiscoveryDNS,
+			expectedReason: "HostNotResolved",
+			expectPassed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newPreflightCluster()
+			tt.modify(cluster)
+			conditions, retry := cluster.runPreflight(t)
+
+			check := apimeta.FindStatusCondition(conditions, tt.conditionType)
+			if check == nil || check.Reason != tt.expectedReason {
+				t.Fatalf("Expected %s with reason %s, got %+v", tt.conditionType, tt.expectedReason, check)
+			}
+			preflight := apimeta.FindStatusCondition(conditions, utils.PreflightChecksPassedStatusType)
+			if preflight == nil || (preflight.Status == metav1.ConditionTrue) != tt.expectPassed {
+				t.Errorf("Expected the preflight checks passed to be %t, got %+v", tt.expectPassed, preflight)
+			}
+			if check.Status == metav1.ConditionFalse && retry != preflightRetryInterval {
+				t.Errorf("Expected the failed check to be retried after %s, got %s", preflightRetryInterval, retry)
+			}
+		})
+	}
+}
+
+func TestRunPreflightChecksPassed(t *testing.T) {
+	conditions, retry := newPreflightCluster().runPreflight(t)
+
+	for _, conditionType := range []string{PreflightProjectedServiceAccountTokens, PreflightPodSecurity, PreflightSecurityContextConstraints,
+		PreflightCSIHostPath, PreflightOIDCDiscoveryDNS, utils.PreflightChecksPassedStatusType} {
+		if condition := apimeta.FindStatusCondition(conditions, conditionType); condition == nil || condition.Status != metav1.ConditionTrue {
+			t.Errorf("Expected %s to be True, got %+v", conditionType, condition)
+		}
+	}
+	if retry != 0 {
+		t.Errorf("Expected no retry once the checks passed, got %s", retry)
+	}
+}
+
+func TestRunPreflightChecksWithoutClientset(t *testing.T) {
+	fakeClient := &fakes.FakeCustomCtrlClient{}
+	statusMgr := status.NewManager(fakeClient)
+	config := &v1alpha1.ZeroTrustWorkloadIdentityManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+	if retry := newTestReconciler(fakeClient).runPreflightChecks(context.Background(), config, statusMgr); retry != 0 {
+		t.Errorf("Expected no retry, got %s", retry)
+	}
+	if err := statusMgr.ApplyStatus(context.Background(), config, func() *v1alpha1.ConditionalStatus {
+		return &config.Status.ConditionalStatus
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if condition := apimeta.FindStatusCondition(config.Status.Conditions, utils.PreflightChecksPassedStatusType); condition != nil {
+		t.Errorf("Expected the preflight checks to be skipped, got %+v", condition)
+	}
+	if fakeClient.GetCallCount() != 0 {
+		t.Errorf("Expected no operand to be read, got %d reads", fakeClient.GetCallCount())
+	}
+}
+
+func TestSummarizeNodes(t *testing.T) {
+	if got := summarizeNodes([]string{"a", "b"}); got != "a, b" {
+		t.Errorf("Expected all the nodes to be listed, got %q", got)
+	}
+	if got := summarizeNodes([]string{"a", "b", "c", "d", "e", "f", "g"}); got != "a, b, c, d, e and 2 more" {
+		t.Errorf("Expected the first nodes to be listed, got %q", got)
+	}
+}
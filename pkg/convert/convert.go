@@ -0,0 +1,322 @@
+// Package convert converts the values of the SPIRE Helm charts (spiffe/helm-charts-hardened) into the custom
+// resources of the operator, to migrate a Helm installation of SPIRE to the operator.
+package convert
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// The subcharts of the spiffe/spire Helm chart
+const (
+	serverChart    = "spire-server"
+	agentChart     = "spire-agent"
+	csiDriverChart = "spiffe-csi-driver"
+	oidcChart      = "spiffe-oidc-discovery-provider"
+)
+
+// The defaults of the spiffe/spire Helm chart, kept when the values don't override them
+const (
+	defaultTrustDomain     = "example.org"
+	defaultClusterName     = "example-cluster"
+	defaultBundleConfigMap = "spire-bundle"
+)
+
+// commonMappings map the values shared by the charts to the CommonConfig of the operand CRs
+var commonMappings = []mapping{
+	{from: "resources", to: "resources"},
+	{from: "nodeSelector", to: "nodeSelector"},
+	{from: "affinity", to: "affinity"},
+	{from: "tolerations", to: "tolerations"},
+	{from: "priorityClassName", to: "priorityClassName"},
+	{from: "imagePullSecrets", to: "imagePullSecrets"},
+	{from: "podSecurityContext", to: "podSecurityContext"},
+	{from: "securityContext", to: "securityContext"},
+	{from: "serviceAccount.annotations", to: "serviceAccountAnnotations"},
+	{from: "extraVolumes", to: "extraVolumes"},
+	{from: "extraVolumeMounts", to: "extraVolumeMounts"},
+}
+
+var serverMappings = []mapping{
+	{from: "logLevel", to: "logLevel"},
+	{from: "logFormat", to: "logFormat"},
+	{from: "jwtIssuer", to: "jwtIssuer"},
+	{from: "caTTL", to: "caValidity"},
+	{from: "defaultX509SvidTTL", to: "defaultX509Validity"},
+	{from: "defaultJwtSvidTTL", to: "defaultJWTValidity"},
+	{from: "caKeyType", to: "caKeyType"},
+	{from: "jwtKeyType", to: "jwtKeyType"},
+	{from: "ca_subject.country", to: "caSubject.country"},
+	{from: "ca_subject.organization", to: "caSubject.organization"},
+	{from: "ca_subject.common_name", to: "caSubject.commonName"},
+	{from: "keyManager.disk.enabled", to: "keyManager.diskEnabled", convert: toStringBool},
+	{from: "keyManager.memory.enabled", to: "keyManager.memoryEnabled", convert: toStringBool},
+	{from: "persistence.type", to: "persistence.type", convert: toPersistenceType},
+	{from: "persistence.size", to: "persistence.size"},
+	{from: "persistence.accessMode", to: "persistence.accessMode"},
+	{from: "persistence.storageClass", to: "persistence.storageClass"},
+	{from: "dataStore.sql.databaseType", to: "datastore.databaseType"},
+	{from: "nodeAttestor.k8sPsat.audience", to: "nodeAttestor.k8sPSATAudience"},
+	{from: "nodeAttestor.k8sPsat.serviceAccountAllowList", to: "nodeAttestor.k8sPSATServiceAccountAllowList"},
+	{from: "controllerManager.className", to: "controllerManager.className"},
+	{from: "topologySpreadConstraints", to: "topologySpreadConstraints"},
+	{from: "extraContainers", to: "extraContainers"},
+	{from: "initContainers", to: "extraInitContainers"},
+}
+
+var agentMappings = []mapping{
+	{from: "logLevel", to: "logLevel"},
+	{from: "logFormat", to: "logFormat"},
+	{from: "nodeAttestor.k8sPsat.enabled", to: "nodeAttestor.k8sPSATEnabled", convert: toStringBool},
+	{from: "workloadAttestors.k8s.disableContainerSelectors", to: "workloadAttestors.disableContainerSelectors", convert: toStringBool},
+	{from: "workloadAttestors.k8s.useNewContainerLocator", to: "workloadAttestors.useNewContainerLocator", convert: toStringBool},
+	{from: "workloadAttestors.k8s.verification.type", to: "workloadAttestors.workloadAttestorsVerification.type"},
+	{from: "workloadAttestors.k8s.verification.hostCert.basePath", to: "workloadAttestors.workloadAttestorsVerification.hostCertBasePath"},
+	{from: "workloadAttestors.k8s.verification.hostCert.fileName", to: "workloadAttestors.workloadAttestorsVerification.hostCertFileName"},
+	{from: "sds.defaultSVIDName", to: "sds.defaultSVIDName"},
+	{from: "sds.defaultBundleName", to: "sds.defaultBundleName"},
+	{from: "sds.defaultAllBundlesName", to: "sds.defaultAllBundlesName"},
+	{from: "healthChecks.port", to: "healthPort", convert: toInt64},
+	{from: "telemetry.prometheus.port", to: "metricsPort", convert: toInt64},
+	{from: "extraContainers", to: "extraContainers"},
+	{from: "initContainers", to: "extraInitContainers"},
+}
+
+var csiDriverMappings = []mapping{
+	{from: "pluginName", to: "pluginName"},
+	{from: "kubeletPath", to: "kubeletPath"},
+	{from: "agentSocketPath", to: "agentSocketPath", convert: toSocketDirectory},
+	{from: "nodeDriverRegistrar.resources", to: "nodeDriverRegistrar.resources"},
+}
+
+var oidcMappings = []mapping{
+	{from: "config.logLevel", to: "logLevel"},
+	{from: "csiDriverName", to: "csiDriverName"},
+	{from: "replicaCount", to: "replicaCount", convert: toInt64},
+	{from: "autoscaling.minReplicas", to: "autoscaling.minReplicas", convert: toInt64},
+	{from: "autoscaling.maxReplicas", to: "autoscaling.maxReplicas", convert: toInt64},
+	{from: "autoscaling.targetCPUUtilizationPercentage", to: "autoscaling.targetCPUUtilizationPercentage", convert: toInt64},
+	{from: "ingress.className", to: "ingress.className"},
+	{from: "ingress.host", to: "ingress.host"},
+	{from: "ingress.annotations", to: "ingress.annotations"},
+	{from: "topologySpreadConstraints", to: "topologySpreadConstraints"},
+	{from: "extraContainers", to: "extraContainers"},
+	{from: "initContainers", to: "extraInitContainers"},
+}
+
+// Options configure the conversion
+type Options struct {
+	// AdoptExistingResources sets adoptExistingResources on the operand CRs, so that the operator adopts
+	// the resources of the Helm installation instead of failing on the existing resources
+	AdoptExistingResources bool
+}
+
+// Result are the custom resources converted from the Helm values
+type Result struct {
+	// Objects are the ZeroTrustWorkloadIdentityManager and the CRs of the operands of the enabled charts
+	Objects []*unstructured.Unstructured
+
+	// Unsupported are the dotted paths of the Helm values without equivalent in the custom resources,
+	// which are left out of the conversion
+	Unsupported []string
+}
+
+// Convert converts the values.yaml of the spiffe/spire Helm chart into the custom resources of the operator
+func Convert(data []byte, opts Options) (*Result, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse the Helm values: %w", err)
+	}
+	v := newValues(root)
+
+	trustDomain, err := v.takeString("global.spire.trustDomain", defaultTrustDomain)
+	if err != nil {
+		return nil, err
+	}
+	clusterName, err := v.takeString("global.spire.clusterName", defaultClusterName)
+	if err != nil {
+		return nil, err
+	}
+	bundleConfigMap, err := v.takeString("global.spire.bundleConfigMap", defaultBundleConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	jwtIssuer, err := v.takeString("global.spire.jwtIssuer", "https://oidc-discovery."+trustDomain)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Objects: []*unstructured.Unstructured{
+		newObject("ZeroTrustWorkloadIdentityManager", map[string]interface{}{
+			"trustDomain":     trustDomain,
+			"clusterName":     clusterName,
+			"bundleConfigMap": bundleConfigMap,
+		}),
+	}}
+
+	if v.isEnabled(serverChart) {
+		spec := map[string]interface{}{"jwtIssuer": jwtIssuer}
+		if err := v.apply(spec, serverChart, append(serverMappings, commonMappings...)); err != nil {
+			return nil, err
+		}
+		if err := convertDatastore(v, spec); err != nil {
+			return nil, err
+		}
+		// persistence and caSubject are required, their fields are defaulted
+		for _, field := range []string{"persistence", "caSubject"} {
+			if _, ok := spec[field]; !ok {
+				spec[field] = map[string]interface{}{}
+			}
+		}
+		result.Objects = append(result.Objects, newOperandObject("SpireServer", spec, opts))
+	}
+
+	if v.isEnabled(agentChart) {
+		spec := map[string]interface{}{}
+		if err := v.apply(spec, agentChart, append(agentMappings, commonMappings...)); err != nil {
+			return nil, err
+		}
+		// The chart configures the path of the socket, the CR its directory and file name
+		socketPath, err := v.takeString(agentChart+".socketPath", "")
+		if err != nil {
+			return nil, err
+		}
+		if socketPath != "" {
+			spec["socketPath"], spec["socketName"] = path.Dir(socketPath), path.Base(socketPath)
+		}
+		result.Objects = append(result.Objects, newOperandObject("SpireAgent", spec, opts))
+	}
+
+	if v.isEnabled(csiDriverChart) {
+		spec := map[string]interface{}{}
+		if err := v.apply(spec, csiDriverChart, append(csiDriverMappings, commonMappings...)); err != nil {
+			return nil, err
+		}
+		result.Objects = append(result.Objects, newOperandObject("SpiffeCSIDriver", spec, opts))
+	}
+
+	if v.isEnabled(oidcChart) {
+		spec := map[string]interface{}{"jwtIssuer": jwtIssuer}
+		if err := v.apply(spec, oidcChart, append(oidcMappings, commonMappings...)); err != nil {
+			return nil, err
+		}
+		// autoscaling and ingress are only configured when enabled in the chart
+		for _, field := range []string{"autoscaling", "ingress"} {
+			if value, ok := v.take(oidcChart + "." + field + ".enabled"); !ok || value != true {
+				delete(spec, field)
+			} else if _, ok := spec[field]; !ok {
+				spec[field] = map[string]interface{}{}
+			}
+		}
+		result.Objects = append(result.Objects, newOperandObject("SpireOIDCDiscoveryProvider", spec, opts))
+	}
+
+	result.Unsupported = v.unconverted()
+	return result, nil
+}
+
+// WriteYAML writes the custom resources as a multi-document YAML stream
+func (r *Result) WriteYAML(w io.Writer) error {
+	for _, obj := range r.Objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newObject(kind string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(kind))
+	obj.SetName("cluster")
+	return obj
+}
+
+func newOperandObject(kind string, spec map[string]interface{}, opts Options) *unstructured.Unstructured {
+	if opts.AdoptExistingResources {
+		spec["adoptExistingResources"] = "true"
+	}
+	return newObject(kind, spec)
+}
+
+// convertDatastore builds the connection string of the SQL datastore of the SPIRE server from the
+// database values of the chart. The default sqlite3 datastore keeps the default connection string.
+func convertDatastore(v *values, spec map[string]interface{}) error {
+	databaseType, _, _ := unstructured.NestedString(spec, "datastore", "databaseType")
+	if databaseType == "" || databaseType == "sqlite3" {
+		return nil
+	}
+
+	database := map[string]string{}
+	for _, key := range []string{"databaseName", "host", "username", "password"} {
+		value, err := v.takeString(serverChart+".dataStore.sql."+key, "")
+		if err != nil {
+			return err
+		}
+		database[key] = value
+	}
+	port := ""
+	if value, ok := v.take(serverChart + ".dataStore.sql.port"); ok && value != float64(0) {
+		port = fmt.Sprint(value)
+	}
+	var options []string
+	if value, ok := v.take(serverChart + ".dataStore.sql.options"); ok {
+		list, _ := value.([]interface{})
+		for _, option := range list {
+			options = append(options, fmt.Sprint(option))
+		}
+	}
+
+	var connectionString string
+	switch databaseType {
+	case "postgres", "aws_postgresql":
+		if port == "" {
+			port = "5432"
+		}
+		fields := []string{"dbname=" + database["databaseName"], "user=" + database["username"],
+			"password=" + database["password"], "host=" + database["host"], "port=" + port}
+		connectionString = strings.Join(append(fields, options...), " ")
+	case "mysql", "aws_mysql":
+		if port == "" {
+			port = "3306"
+		}
+		connectionString = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", database["username"], database["password"],
+			database["host"], port, database["databaseName"])
+		if len(options) > 0 {
+			connectionString += "?" + strings.Join(options, "&")
+		}
+	default:
+		return nil
+	}
+	return unstructured.SetNestedField(spec, connectionString, "datastore", "connectionString")
+}
+
+// toPersistenceType converts the persistence type of the chart, the CR has no hostPath persistence
+func toPersistenceType(value interface{}) (interface{}, error) {
+	switch value {
+	case "pvc":
+		return "PersistentVolumeClaim", nil
+	case "emptyDir":
+		return "EmptyDir", nil
+	}
+	return nil, errUnsupported
+}
+
+// toSocketDirectory converts the path of the SPIRE agent socket to its directory
+func toSocketDirectory(value interface{}) (interface{}, error) {
+	socketPath, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string, got %v", value)
+	}
+	return path.Dir(socketPath), nil
+}
@@ -0,0 +1,174 @@
+package convert
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+const testValues = `
+global:
+  spire:
+    trustDomain: prod.example.com
+    clusterName: prod
+    namespaces:
+      create: true
+spire-server:
+  logLevel: debug
+  caTTL: 48h
+  ca_subject:
+    country: US
+    common_name: ""
+  keyManager:
+    disk:
+      enabled: false
+    memory:
+      enabled: true
+  persistence:
+    type: hostPath
+    size: 2Gi
+  dataStore:
+    sql:
+      databaseType: postgres
+      databaseName: spire
+      host: db.example.com
+      username: spire
+      password: secret
+      options:
+        - sslmode=require
+  image:
+    tag: 1.12.0
+  nodeSelector:
+    role: infra
+spire-agent:
+  socketPath: /run/spire/sockets/agent.sock
+  healthChecks:
+    port: 9980
+  workloadAttestors:
+    k8s:
+      disableContainerSelectors: true
+spiffe-csi-driver:
+  agentSocketPath: /run/spire/sockets/agent.sock
+spiffe-oidc-discovery-provider:
+  enabled: false
+`
+
+func findObject(t *testing.T, result *Result, kind string) *unstructured.Unstructured {
+	t.Helper()
+	for _, obj := range result.Objects {
+		if obj.GetKind() == kind {
+			return obj
+		}
+	}
+	return nil
+}
+
+func expectField(t *testing.T, obj *unstructured.Unstructured, expected interface{}, fields ...string) {
+	t.Helper()
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, append([]string{"spec"}, fields...)...)
+	if err != nil || !found || !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected %s spec.%s to be %v, got %v", obj.GetKind(), strings.Join(fields, "."), expected, value)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	result, err := Convert([]byte(testValues), Options{AdoptExistingResources: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Objects) != 4 || findObject(t, result, "SpireOIDCDiscoveryProvider") != nil {
+		t.Fatalf("Expected the CRs of the enabled charts only, got %d objects", len(result.Objects))
+	}
+	for _, obj := range result.Objects {
+		if obj.GetAPIVersion() != v1alpha1.GroupVersion.String() || obj.GetName() != "cluster" {
+			t.Errorf("Unexpected %s %s/%s", obj.GetKind(), obj.GetAPIVersion(), obj.GetName())
+		}
+	}
+
+	ztwim := findObject(t, result, "ZeroTrustWorkloadIdentityManager")
+	expectField(t, ztwim, "prod.example.com", "trustDomain")
+	expectField(t, ztwim, "prod", "clusterName")
+	expectField(t, ztwim, defaultBundleConfigMap, "bundleConfigMap")
+
+	server := findObject(t, result, "SpireServer")
+	expectField(t, server, "https://oidc-discovery.prod.example.com", "jwtIssuer")
+	expectField(t, server, "debug", "logLevel")
+	expectField(t, server, "48h", "caValidity")
+	expectField(t, server, "US", "caSubject", "country")
+	expectField(t, server, "false", "keyManager", "diskEnabled")
+	expectField(t, server, "true", "keyManager", "memoryEnabled")
+	expectField(t, server, "2Gi", "persistence", "size")
+	expectField(t, server, "dbname=spire user=spire password=secret host=db.example.com port=5432 sslmode=require",
+		"datastore", "connectionString")
+	expectField(t, server, map[string]interface{}{"role": "infra"}, "nodeSelector")
+	expectField(t, server, "true", "adoptExistingResources")
+
+	agent := findObject(t, result, "SpireAgent")
+	expectField(t, agent, "/run/spire/sockets", "socketPath")
+	expectField(t, agent, "agent.sock", "socketName")
+	expectField(t, agent, int64(9980), "healthPort")
+	expectField(t, agent, "true", "workloadAttestors", "disableContainerSelectors")
+
+	expectField(t, findObject(t, result, "SpiffeCSIDriver"), "/run/spire/sockets", "agentSocketPath")
+
+	expected := []string{"global.spire.namespaces.create", "spire-server.image.tag", "spire-server.persistence.type"}
+	if !reflect.DeepEqual(result.Unsupported, expected) {
+		t.Errorf("Expected the unsupported values %v, got %v", expected, result.Unsupported)
+	}
+}
+
+func TestConvertDefaults(t *testing.T) {
+	result, err := Convert(nil, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Objects) != 5 || len(result.Unsupported) != 0 {
+		t.Fatalf("Expected all the CRs without unsupported values, got %d objects and %v", len(result.Objects), result.Unsupported)
+	}
+
+	server := findObject(t, result, "SpireServer")
+	expectField(t, server, "https://oidc-discovery."+defaultTrustDomain, "jwtIssuer")
+	expectField(t, server, map[string]interface{}{}, "persistence")
+	if _, found, _ := unstructured.NestedFieldNoCopy(server.Object, "spec", "adoptExistingResources"); found {
+		t.Error("Expected adoptExistingResources not to be set")
+	}
+}
+
+func TestConvertInvalidValues(t *testing.T) {
+	if _, err := Convert([]byte("spire-agent:\n  healthChecks:\n    port: http\n"), Options{}); err == nil {
+		t.Error("Expected an error for a port which is not a number")
+	}
+	if _, err := Convert([]byte("global: ["), Options{}); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	result, err := Convert([]byte(testValues), Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := result.WriteYAML(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	documents := strings.Split(strings.TrimPrefix(buf.String(), "---\n"), "---\n")
+	if len(documents) != len(result.Objects) {
+		t.Fatalf("Expected %d documents, got %d", len(result.Objects), len(documents))
+	}
+	var server v1alpha1.SpireServer
+	if err := yaml.UnmarshalStrict([]byte(documents[1]), &server); err != nil {
+		t.Fatalf("Expected a valid SpireServer, got %v", err)
+	}
+	if server.Spec.CAValidity.Duration.Hours() != 48 || server.Spec.Datastore.DatabaseType != "postgres" {
+		t.Errorf("Unexpected SpireServer spec %+v", server.Spec)
+	}
+}
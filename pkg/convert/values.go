@@ -0,0 +1,153 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// errUnsupported is returned by the conversion of a value without equivalent in the custom resources
+var errUnsupported = errors.New("unsupported value")
+
+// mapping copies the Helm value at the dotted path from, relative to the chart, to the dotted path to of the spec
+type mapping struct {
+	from string
+	to   string
+	// convert converts the Helm value to the value of the spec, when set
+	convert func(interface{}) (interface{}, error)
+}
+
+// values are the Helm values being converted. They track the converted values, so that the values left
+// over can be reported as unsupported.
+type values struct {
+	root        map[string]interface{}
+	converted   map[string]bool
+	unsupported []string
+}
+
+func newValues(root map[string]interface{}) *values {
+	if root == nil {
+		root = map[string]interface{}{}
+	}
+	return &values{root: root, converted: map[string]bool{}}
+}
+
+// take returns the value at the dotted path, and marks it, with the values below it, as converted.
+// Unset and null values are not found.
+func (v *values) take(path string) (interface{}, bool) {
+	var current interface{} = v.root
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	v.converted[path] = true
+	return current, current != nil
+}
+
+// takeString returns the string value at the dotted path, or def when unset
+func (v *values) takeString(path, def string) (string, error) {
+	value, ok := v.take(path)
+	if !ok {
+		return def, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: expected a string, got %v", path, value)
+	}
+	return s, nil
+}
+
+// isEnabled returns whether the chart is enabled, charts are enabled unless enabled is false
+func (v *values) isEnabled(chart string) bool {
+	value, ok := v.take(chart + ".enabled")
+	return !ok || value != false
+}
+
+// apply copies the values of the chart to spec following the mappings
+func (v *values) apply(spec map[string]interface{}, chart string, mappings []mapping) error {
+	for _, m := range mappings {
+		path := chart + "." + m.from
+		value, ok := v.take(path)
+		if !ok {
+			continue
+		}
+		if m.convert != nil {
+			var err error
+			if value, err = m.convert(value); errors.Is(err, errUnsupported) {
+				v.unsupported = append(v.unsupported, path)
+				continue
+			} else if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		if err := unstructured.SetNestedField(spec, value, strings.Split(m.to, ".")...); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// unconverted returns the dotted paths of the values which were not converted, sorted.
+// Empty values are not reported, as they are usually the defaults of the chart.
+func (v *values) unconverted() []string {
+	paths := append([]string{}, v.unsupported...)
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		if v.converted[path] {
+			return
+		}
+		switch value := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			for key, child := range value {
+				if path == "" {
+					walk(key, child)
+				} else {
+					walk(path+"."+key, child)
+				}
+			}
+		case []interface{}:
+			if len(value) > 0 {
+				paths = append(paths, path)
+			}
+		case string:
+			if value != "" {
+				paths = append(paths, path)
+			}
+		default:
+			paths = append(paths, path)
+		}
+	}
+	walk("", v.root)
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// toStringBool converts a boolean to the "true" or "false" string of the custom resources
+func toStringBool(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case bool:
+		return fmt.Sprint(value), nil
+	case string:
+		if value == "true" || value == "false" {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("expected a boolean, got %v", value)
+}
+
+// toInt64 converts a number to an integer of the custom resources
+func toInt64(value interface{}) (interface{}, error) {
+	if number, ok := value.(float64); ok && number == float64(int64(number)) {
+		return int64(number), nil
+	}
+	return nil, fmt.Errorf("expected an integer, got %v", value)
+}
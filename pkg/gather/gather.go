@@ -0,0 +1,395 @@
+package gather
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/version"
+)
+
+// errorsFileName is the file listing the data that could not be collected
+const errorsFileName = "gather-errors.txt"
+
+// Options configures the data collected by the Gatherer
+type Options struct {
+	// Namespace is the namespace of the operator, where the operands are installed
+	// unless the ZeroTrustWorkloadIdentityManager selects another operand namespace
+	Namespace string
+	// LogsSince limits the collected container logs to the given duration; all logs are collected when zero
+	LogsSince time.Duration
+}
+
+// Gatherer collects the support data of the operands: the operand CRs, the generated resources of the
+// operand namespace, the container logs, the healthcheck output of the containers and the metadata of
+// the certificates of the trust bundle. Secrets are never collected.
+type Gatherer struct {
+	client    client.Client
+	clientset kubernetes.Interface
+	scheme    *runtime.Scheme
+	writer    Writer
+	opts      Options
+	errs      []string
+}
+
+// New returns a Gatherer reading the cluster with c and clientset and storing the collected files with w
+func New(c client.Client, clientset kubernetes.Interface, w Writer, opts Options) *Gatherer {
+	return &Gatherer{
+		client:    c,
+		clientset: clientset,
+		scheme:    c.Scheme(),
+		writer:    w,
+		opts:      opts,
+	}
+}
+
+// Gather collects the support data. Data that cannot be read from the cluster is listed in
+// gather-errors.txt rather than failing the collection; an error is returned when the
+// collected data cannot be stored.
+func (g *Gatherer) Gather(ctx context.Context) error {
+	if err := g.writer.WriteFile("version.txt", []byte(fmt.Sprintf("operator version: %s\ncommit: %s\n", version.OperatorVersion, version.COMMIT))); err != nil {
+		return err
+	}
+
+	ztwim, err := g.gatherOperands(ctx)
+	if err != nil {
+		return err
+	}
+
+	namespace := g.opts.Namespace
+	bundleConfigMap := "spire-bundle"
+	if ztwim != nil {
+		if ztwim.Spec.OperandNamespace != "" {
+			namespace = ztwim.Spec.OperandNamespace
+		}
+		if ztwim.Spec.BundleConfigMap != "" {
+			bundleConfigMap = ztwim.Spec.BundleConfigMap
+		}
+	}
+
+	pods, err := g.gatherNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for i := range pods {
+		if err := g.gatherPod(ctx, &pods[i]); err != nil {
+			return err
+		}
+	}
+	if err := g.gatherTrustBundle(ctx, namespace, bundleConfigMap); err != nil {
+		return err
+	}
+
+	if len(g.errs) > 0 {
+		return g.writer.WriteFile(errorsFileName, []byte(strings.Join(g.errs, "\n")+"\n"))
+	}
+	return nil
+}
+
+// recordError lists data that could not be collected
+func (g *Gatherer) recordError(err error, format string, args ...any) {
+	g.errs = append(g.errs, fmt.Sprintf("%s: %v", fmt.Sprintf(format, args...), err))
+}
+
+// gatherOperands collects the operand CRs and returns the ZeroTrustWorkloadIdentityManager, when found
+func (g *Gatherer) gatherOperands(ctx context.Context) (*v1alpha1.ZeroTrustWorkloadIdentityManager, error) {
+	ztwims := &v1alpha1.ZeroTrustWorkloadIdentityManagerList{}
+	lists := []client.ObjectList{
+		ztwims,
+		&v1alpha1.SpireServerList{},
+		&v1alpha1.SpireAgentList{},
+		&v1alpha1.SpiffeCSIDriverList{},
+		&v1alpha1.SpireOIDCDiscoveryProviderList{},
+	}
+	for _, list := range lists {
+		if err := g.gatherList(ctx, list); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range ztwims.Items {
+		if ztwims.Items[i].Name == "cluster" {
+			return &ztwims.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// gatherNamespace collects the resources generated by the operator in the operand namespace and the
+// events of the namespace, and returns the operand pods
+func (g *Gatherer) gatherNamespace(ctx context.Context, namespace string) ([]corev1.Pod, error) {
+	managed := client.MatchingLabels{utils.AppManagedByLabelKey: utils.AppManagedByLabelValue}
+	pods := &corev1.PodList{}
+	lists := []client.ObjectList{
+		&corev1.ConfigMapList{},
+		&corev1.ServiceList{},
+		&corev1.ServiceAccountList{},
+		&appsv1.DeploymentList{},
+		&appsv1.StatefulSetList{},
+		&appsv1.DaemonSetList{},
+		pods,
+	}
+	for _, list := range lists {
+		if err := g.gatherList(ctx, list, client.InNamespace(namespace), managed); err != nil {
+			return nil, err
+		}
+	}
+
+	events := &corev1.EventList{}
+	if err := g.client.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		g.recordError(err, "failed to list events in namespace %s", namespace)
+	} else {
+		data, err := yaml.Marshal(events)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.writer.WriteFile(path.Join("namespaces", namespace, "core", "events.yaml"), data); err != nil {
+			return nil, err
+		}
+	}
+
+	return pods.Items, nil
+}
+
+// gatherList lists the objects of a kind and stores each one in its own file, in the layout of oc adm inspect
+func (g *Gatherer) gatherList(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, g.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	if err := g.client.List(ctx, list, opts...); err != nil {
+		g.recordError(err, "failed to list %s", gvk.Kind)
+		return nil
+	}
+	objects, err := extractObjects(list)
+	if err != nil {
+		return err
+	}
+
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	resource := strings.ToLower(gvk.Kind) + "s"
+	for _, obj := range objects {
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		obj.SetManagedFields(nil)
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		name := path.Join("cluster-scoped-resources", group, resource, obj.GetName()+".yaml")
+		if obj.GetNamespace() != "" {
+			name = path.Join("namespaces", obj.GetNamespace(), group, resource, obj.GetName()+".yaml")
+		}
+		if err := g.writer.WriteFile(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractObjects returns the items of list as client objects
+func extractObjects(list client.ObjectList) ([]client.Object, error) {
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("unexpected list item %T", item)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// gatherPod collects the logs and the healthcheck output of the containers of an operand pod
+func (g *Gatherer) gatherPod(ctx context.Context, pod *corev1.Pod) error {
+	podDir := path.Join("namespaces", pod.Namespace, "pods", pod.Name)
+
+	restarts := map[string]int32{}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		restarts[status.Name] = status.RestartCount
+	}
+
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		containerDir := path.Join(podDir, container.Name)
+		if err := g.gatherLogs(ctx, pod, container.Name, false, path.Join(containerDir, "logs", "current.log")); err != nil {
+			return err
+		}
+		if restarts[container.Name] > 0 {
+			if err := g.gatherLogs(ctx, pod, container.Name, true, path.Join(containerDir, "logs", "previous.log")); err != nil {
+				return err
+			}
+		}
+		if err := g.gatherHealthcheck(ctx, pod, &container, path.Join(containerDir, "healthcheck")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gatherLogs collects the logs of a container
+func (g *Gatherer) gatherLogs(ctx context.Context, pod *corev1.Pod, container string, previous bool, name string) error {
+	logOptions := &corev1.PodLogOptions{Container: container, Previous: previous}
+	if g.opts.LogsSince > 0 {
+		sinceSeconds := int64(g.opts.LogsSince.Seconds())
+		logOptions.SinceSeconds = &sinceSeconds
+	}
+	logs, err := g.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOptions).DoRaw(ctx)
+	if err != nil {
+		g.recordError(err, "failed to get logs of container %s of pod %s/%s", container, pod.Namespace, pod.Name)
+		return nil
+	}
+	return g.writer.WriteFile(name, logs)
+}
+
+// gatherHealthcheck collects the output of the HTTP readiness and liveness endpoints of a container,
+// read through the pod proxy of the kube-apiserver
+func (g *Gatherer) gatherHealthcheck(ctx context.Context, pod *corev1.Pod, container *corev1.Container, dir string) error {
+	probes := map[string]*corev1.Probe{"ready": container.ReadinessProbe, "live": container.LivenessProbe}
+	for _, probeName := range []string{"ready", "live"} {
+		probe := probes[probeName]
+		if probe == nil || probe.HTTPGet == nil {
+			continue
+		}
+		port, err := resolveContainerPort(container, probe.HTTPGet.Port)
+		if err != nil {
+			g.recordError(err, "failed to get %s healthcheck of container %s of pod %s/%s", probeName, container.Name, pod.Namespace, pod.Name)
+			continue
+		}
+		scheme := strings.ToLower(string(probe.HTTPGet.Scheme))
+		if scheme == "" {
+			scheme = "http"
+		}
+		output, err := g.clientset.CoreV1().Pods(pod.Namespace).ProxyGet(scheme, pod.Name, port, probe.HTTPGet.Path, nil).DoRaw(ctx)
+		if err != nil && !kerrors.IsServiceUnavailable(err) {
+			g.recordError(err, "failed to get %s healthcheck of container %s of pod %s/%s", probeName, container.Name, pod.Namespace, pod.Name)
+			continue
+		}
+		if err != nil {
+			// An unready endpoint answers 503; keep its output
+			output = []byte(err.Error())
+		}
+		if err := g.writer.WriteFile(path.Join(dir, probeName+".txt"), output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveContainerPort returns the port number of a probe port, resolving named ports with the container ports
+func resolveContainerPort(container *corev1.Container, port intstr.IntOrString) (string, error) {
+	if port.Type == intstr.Int {
+		return strconv.Itoa(port.IntValue()), nil
+	}
+	for _, containerPort := range container.Ports {
+		if containerPort.Name == port.StrVal {
+			return strconv.Itoa(int(containerPort.ContainerPort)), nil
+		}
+	}
+	return "", fmt.Errorf("container port %q not found", port.StrVal)
+}
+
+// certificateMetadata describes a certificate of the trust bundle without its key material
+type certificateMetadata struct {
+	Subject           string    `json:"subject"`
+	Issuer            string    `json:"issuer"`
+	SerialNumber      string    `json:"serialNumber"`
+	NotBefore         time.Time `json:"notBefore"`
+	NotAfter          time.Time `json:"notAfter"`
+	IsCA              bool      `json:"isCA"`
+	URIs              []string  `json:"uris,omitempty"`
+	SHA256Fingerprint string    `json:"sha256Fingerprint"`
+}
+
+// gatherTrustBundle collects the metadata of the certificates of the trust bundle ConfigMap
+func (g *Gatherer) gatherTrustBundle(ctx context.Context, namespace, name string) error {
+	bundle := &corev1.ConfigMap{}
+	if err := g.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, bundle); err != nil {
+		g.recordError(err, "failed to get trust bundle ConfigMap %s/%s", namespace, name)
+		return nil
+	}
+
+	keys := make([]string, 0, len(bundle.Data))
+	for key := range bundle.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	metadata := map[string][]certificateMetadata{}
+	for _, key := range keys {
+		certificates, err := parseCertificates([]byte(bundle.Data[key]))
+		if err != nil {
+			g.recordError(err, "failed to parse key %s of trust bundle ConfigMap %s/%s", key, namespace, name)
+			continue
+		}
+		metadata[key] = certificates
+	}
+
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return g.writer.WriteFile(path.Join("namespaces", namespace, "trust-bundle", name+".yaml"), data)
+}
+
+// parseCertificates returns the metadata of the PEM encoded certificates of data
+func parseCertificates(data []byte) ([]certificateMetadata, error) {
+	var certificates []certificateMetadata
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certificates, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		fingerprint := sha256.Sum256(certificate.Raw)
+		metadata := certificateMetadata{
+			Subject:           certificate.Subject.String(),
+			Issuer:            certificate.Issuer.String(),
+			SerialNumber:      certificate.SerialNumber.String(),
+			NotBefore:         certificate.NotBefore.UTC(),
+			NotAfter:          certificate.NotAfter.UTC(),
+			IsCA:              certificate.IsCA,
+			SHA256Fingerprint: hex.EncodeToString(fingerprint[:]),
+		}
+		for _, uri := range certificate.URIs {
+			metadata.URIs = append(metadata.URIs, uri.String())
+		}
+		certificates = append(certificates, metadata)
+	}
+}
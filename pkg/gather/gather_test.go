@@ -0,0 +1,234 @@
+package gather
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// memoryWriter collects the gathered files in memory
+type memoryWriter map[string]string
+
+func (w memoryWriter) WriteFile(name string, data []byte) error {
+	w[name] = string(data)
+	return nil
+}
+
+func (w memoryWriter) Close() error {
+	return nil
+}
+
+// proxyResponse is the response of the fake pod proxy
+type proxyResponse struct {
+	body string
+	err  error
+}
+
+func (r proxyResponse) DoRaw(context.Context) ([]byte, error) {
+	return []byte(r.body), r.err
+}
+
+func (r proxyResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(r.body)), r.err
+}
+
+var _ restclient.ResponseWrapper = proxyResponse{}
+
+func newTestCertificate(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(42),
+		Subject:               pkix.Name{CommonName: "spire-ca"},
+		NotBefore:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: "example.org"}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestGather(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	managed := map[string]string{utils.AppManagedByLabelKey: utils.AppManagedByLabelValue}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "spire-server-0", Namespace: "spire", Labels: managed},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "spire-server",
+					Ports: []corev1.ContainerPort{{Name: "healthz", ContainerPort: 8080}},
+					ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("healthz")},
+					}},
+					LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/live", Port: intstr.FromInt32(8080)},
+					}},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "spire-server", RestartCount: 1}},
+		},
+	}
+	objects := []runtime.Object{
+		&v1alpha1.ZeroTrustWorkloadIdentityManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{OperandNamespace: "spire", BundleConfigMap: "trust-bundle"},
+		},
+		&v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "spire", Labels: managed}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "spire"}},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "trust-bundle", Namespace: "spire"},
+			Data:       map[string]string{"bundle.crt": newTestCertificate(t)},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "spire-server", Namespace: "spire", Labels: managed}},
+		pod,
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+
+	clientset := kubefake.NewSimpleClientset(pod.DeepCopy())
+	var proxied []string
+	clientset.PrependProxyReactor("pods", func(action clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+		proxy := action.(clienttesting.ProxyGetAction)
+		proxied = append(proxied, proxy.GetPort()+proxy.GetPath())
+		return true, proxyResponse{body: "ok"}, nil
+	})
+
+	writer := memoryWriter{}
+	if err := New(c, clientset, writer, Options{Namespace: "operator", LogsSince: time.Hour}).Gather(context.Background()); err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	for _, name := range []string{
+		"version.txt",
+		"cluster-scoped-resources/operator.openshift.io/zerotrustworkloadidentitymanagers/cluster.yaml",
+		"cluster-scoped-resources/operator.openshift.io/spireservers/cluster.yaml",
+		"namespaces/spire/core/configmaps/spire-server.yaml",
+		"namespaces/spire/core/pods/spire-server-0.yaml",
+		"namespaces/spire/core/events.yaml",
+		"namespaces/spire/pods/spire-server-0/spire-server/logs/current.log",
+		"namespaces/spire/pods/spire-server-0/spire-server/logs/previous.log",
+		"namespaces/spire/pods/spire-server-0/spire-server/healthcheck/ready.txt",
+		"namespaces/spire/pods/spire-server-0/spire-server/healthcheck/live.txt",
+		"namespaces/spire/trust-bundle/trust-bundle.yaml",
+	} {
+		if _, ok := writer[name]; !ok {
+			t.Errorf("Expected %s to be gathered", name)
+		}
+	}
+	for name := range writer {
+		if strings.Contains(name, "secrets") || strings.Contains(name, "unrelated") {
+			t.Errorf("Expected %s not to be gathered", name)
+		}
+	}
+	if _, ok := writer[errorsFileName]; ok {
+		t.Errorf("Expected no gather errors, got %s", writer[errorsFileName])
+	}
+
+	if !strings.Contains(writer["cluster-scoped-resources/operator.openshift.io/spireservers/cluster.yaml"], "kind: SpireServer") {
+		t.Error("Expected the gathered SpireServer to carry its kind")
+	}
+	if strings.Join(proxied, ",") != "8080/ready,8080/live" {
+		t.Errorf("Expected the healthcheck endpoints to be read on the resolved port, got %v", proxied)
+	}
+
+	var bundle map[string][]certificateMetadata
+	if err := yaml.Unmarshal([]byte(writer["namespaces/spire/trust-bundle/trust-bundle.yaml"]), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	certificates := bundle["bundle.crt"]
+	if len(certificates) != 1 {
+		t.Fatalf("Expected 1 certificate, got %v", bundle)
+	}
+	if certificates[0].Subject != "CN=spire-ca" || !certificates[0].IsCA || certificates[0].SerialNumber != "42" ||
+		len(certificates[0].URIs) != 1 || certificates[0].URIs[0] != "spiffe://example.org" {
+		t.Errorf("Unexpected certificate metadata %+v", certificates[0])
+	}
+}
+
+func TestGatherRecordsErrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	writer := memoryWriter{}
+	if err := New(c, kubefake.NewSimpleClientset(), writer, Options{Namespace: "operator"}).Gather(context.Background()); err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if !strings.Contains(writer[errorsFileName], "trust bundle ConfigMap operator/spire-bundle") {
+		t.Errorf("Expected the missing trust bundle to be recorded, got %q", writer[errorsFileName])
+	}
+}
+
+func TestResolveContainerPort(t *testing.T) {
+	container := &corev1.Container{Ports: []corev1.ContainerPort{{Name: "healthz", ContainerPort: 8080}}}
+	if port, err := resolveContainerPort(container, intstr.FromString("healthz")); err != nil || port != "8080" {
+		t.Errorf("Expected port 8080, got %q, %v", port, err)
+	}
+	if port, err := resolveContainerPort(container, intstr.FromInt32(9402)); err != nil || port != "9402" {
+		t.Errorf("Expected port 9402, got %q, %v", port, err)
+	}
+	if _, err := resolveContainerPort(container, intstr.FromString("metrics")); err == nil {
+		t.Error("Expected an error for an unknown named port")
+	}
+}
+
+func TestParseCertificates(t *testing.T) {
+	certificate := newTestCertificate(t)
+	certificates, err := parseCertificates([]byte(certificate + certificate))
+	if err != nil || len(certificates) != 2 {
+		t.Errorf("Expected 2 certificates, got %d, %v", len(certificates), err)
+	}
+	if _, err := parseCertificates([]byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n")); err == nil {
+		t.Error("Expected an error for an invalid certificate")
+	}
+	if certificates, err := parseCertificates([]byte("not a certificate")); err != nil || len(certificates) != 0 {
+		t.Errorf("Expected no certificates, got %v, %v", certificates, err)
+	}
+}
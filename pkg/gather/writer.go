@@ -0,0 +1,85 @@
+package gather
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Writer stores the files collected by the Gatherer
+type Writer interface {
+	// WriteFile stores data under the slash separated relative name
+	WriteFile(name string, data []byte) error
+	// Close flushes the collected files
+	Close() error
+}
+
+// directoryWriter writes the collected files below a directory, the layout expected by oc adm must-gather
+type directoryWriter struct {
+	dir string
+}
+
+// NewDirectoryWriter returns a Writer storing the collected files below dir
+func NewDirectoryWriter(dir string) (Writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &directoryWriter{dir: dir}, nil
+}
+
+func (w *directoryWriter) WriteFile(name string, data []byte) error {
+	filePath := filepath.Join(w.dir, filepath.FromSlash(path.Clean("/"+name)))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0o644)
+}
+
+func (w *directoryWriter) Close() error {
+	return nil
+}
+
+// archiveWriter writes the collected files to a gzip compressed tar archive
+type archiveWriter struct {
+	out     io.WriteCloser
+	gzip    *gzip.Writer
+	tar     *tar.Writer
+	root    string
+	modTime time.Time
+}
+
+// NewArchiveWriter returns a Writer storing the collected files in a gzip compressed tar archive
+// written to out. The files are stored below the root directory of the archive.
+func NewArchiveWriter(out io.WriteCloser, root string) Writer {
+	gz := gzip.NewWriter(out)
+	return &archiveWriter{
+		out:     out,
+		gzip:    gz,
+		tar:     tar.NewWriter(gz),
+		root:    root,
+		modTime: time.Now(),
+	}
+}
+
+func (w *archiveWriter) WriteFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    path.Join(w.root, path.Clean("/"+name)),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: w.modTime,
+	}
+	if err := w.tar.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := w.tar.Write(data)
+	return err
+}
+
+func (w *archiveWriter) Close() error {
+	return errors.Join(w.tar.Close(), w.gzip.Close(), w.out.Close())
+}
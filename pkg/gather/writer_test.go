@@ -0,0 +1,84 @@
+package gather
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// closeBuffer is an in-memory io.WriteCloser
+type closeBuffer struct {
+	bytes.Buffer
+}
+
+func (b *closeBuffer) Close() error {
+	return nil
+}
+
+func TestDirectoryWriter(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewDirectoryWriter(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteFile("namespaces/spire/core/configmaps/spire-server.yaml", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	// Names cannot escape the directory
+	if err := writer.WriteFile("../escaped.txt", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "namespaces", "spire", "core", "configmaps", "spire-server.yaml"))
+	if err != nil || string(data) != "data" {
+		t.Errorf("Expected the file to be written, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.txt")); err != nil {
+		t.Errorf("Expected the escaping name to be written below the directory: %v", err)
+	}
+}
+
+func TestArchiveWriter(t *testing.T) {
+	out := &closeBuffer{}
+	writer := NewArchiveWriter(out, "gather")
+	if err := writer.WriteFile("version.txt", []byte("operator version: 1.0.0\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteFile("namespaces/spire/core/events.yaml", []byte("items: []\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(&out.Buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+	if files["gather/version.txt"] != "operator version: 1.0.0\n" || files["gather/namespaces/spire/core/events.yaml"] != "items: []\n" {
+		t.Errorf("Unexpected archive content %v", files)
+	}
+}
@@ -0,0 +1,54 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Executor runs a command in a container of a pod and returns its standard output
+type Executor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string) ([]byte, error)
+}
+
+// podExecutor runs commands through the exec subresource of the pods
+type podExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor returns an Executor running commands through the exec subresource of the pods
+func NewPodExecutor(config *rest.Config, clientset kubernetes.Interface) Executor {
+	return &podExecutor{config: config, clientset: clientset}
+}
+
+func (e *podExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) ([]byte, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return nil, fmt.Errorf("failed to run %q in pod %s/%s: %w: %s", strings.Join(command, " "), namespace, pod, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
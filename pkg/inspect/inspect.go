@@ -0,0 +1,233 @@
+package inspect
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+const (
+	// spireServerContainer is the container of the SPIRE server pods running the SPIRE server
+	spireServerContainer = "spire-server"
+
+	// spireServerBinary is the SPIRE server CLI of the SPIRE server image. The SPIRE server only serves the
+	// entry and agent APIs to unauthenticated callers on its local admin socket, so the CLI is run in the pod
+	// rather than called through a port-forward.
+	spireServerBinary = "/opt/spire/bin/spire-server"
+)
+
+// conditionalObject is an operand CR reporting conditions
+type conditionalObject interface {
+	client.Object
+	GetConditionalStatus() v1alpha1.ConditionalStatus
+}
+
+// Inspector prints the state of the operand CRs and of the managed SPIRE server
+type Inspector struct {
+	client    client.Client
+	executor  Executor
+	out       io.Writer
+	namespace string
+}
+
+// New returns an Inspector reading the operand CRs with c, running the SPIRE server CLI with executor and
+// printing to out. namespace is the operator namespace, used unless the ZeroTrustWorkloadIdentityManager
+// selects another operand namespace.
+func New(c client.Client, executor Executor, out io.Writer, namespace string) *Inspector {
+	return &Inspector{client: c, executor: executor, out: out, namespace: namespace}
+}
+
+// Status prints the conditions of the operand CRs
+func (i *Inspector) Status(ctx context.Context) error {
+	operands := []struct {
+		kind string
+		obj  conditionalObject
+	}{
+		{"ZeroTrustWorkloadIdentityManager", &v1alpha1.ZeroTrustWorkloadIdentityManager{}},
+		{"SpireServer", &v1alpha1.SpireServer{}},
+		{"SpireAgent", &v1alpha1.SpireAgent{}},
+		{"SpiffeCSIDriver", &v1alpha1.SpiffeCSIDriver{}},
+		{"SpireOIDCDiscoveryProvider", &v1alpha1.SpireOIDCDiscoveryProvider{}},
+	}
+
+	w := tabwriter.NewWriter(i.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tCONDITION\tSTATUS\tREASON\tMESSAGE")
+	for _, operand := range operands {
+		if err := i.client.Get(ctx, client.ObjectKey{Name: "cluster"}, operand.obj); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t-\t-\tNotFound\t%s/cluster does not exist\n", operand.kind, operand.kind)
+			continue
+		}
+		for _, condition := range operand.obj.GetConditionalStatus().Conditions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", operand.kind, condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+	return w.Flush()
+}
+
+// Entries prints the registration entries of the SPIRE server
+func (i *Inspector) Entries(ctx context.Context, outputJSON bool) error {
+	output, err := i.spireServer(ctx, "entry", "show", "-output", "json")
+	if err != nil || outputJSON {
+		return i.write(output, err)
+	}
+
+	var response struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return fmt.Errorf("failed to decode the registration entries: %w", err)
+	}
+
+	w := tabwriter.NewWriter(i.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ENTRY ID\tSPIFFE ID\tPARENT ID\tSELECTORS")
+	for _, raw := range response.Entries {
+		entry := &types.Entry{}
+		if err := protojson.Unmarshal(raw, entry); err != nil {
+			return fmt.Errorf("failed to decode a registration entry: %w", err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.GetId(), formatSPIFFEID(entry.GetSpiffeId()), formatSPIFFEID(entry.GetParentId()), formatSelectors(entry.GetSelectors()))
+	}
+	return w.Flush()
+}
+
+// Agents prints the agents attested by the SPIRE server
+func (i *Inspector) Agents(ctx context.Context, outputJSON bool) error {
+	output, err := i.spireServer(ctx, "agent", "list", "-output", "json")
+	if err != nil || outputJSON {
+		return i.write(output, err)
+	}
+
+	var response struct {
+		Agents []json.RawMessage `json:"agents"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return fmt.Errorf("failed to decode the attested agents: %w", err)
+	}
+
+	w := tabwriter.NewWriter(i.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SPIFFE ID\tATTESTATION TYPE\tSVID EXPIRES AT\tBANNED")
+	for _, raw := range response.Agents {
+		agent := &types.Agent{}
+		if err := protojson.Unmarshal(raw, agent); err != nil {
+			return fmt.Errorf("failed to decode an attested agent: %w", err)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", formatSPIFFEID(agent.GetId()), agent.GetAttestationType(), formatUnixTime(agent.GetX509SvidExpiresAt()), agent.GetBanned())
+	}
+	return w.Flush()
+}
+
+// Bundle prints the X.509 and JWT authorities of the trust bundle of the SPIRE server
+func (i *Inspector) Bundle(ctx context.Context, outputJSON bool) error {
+	output, err := i.spireServer(ctx, "bundle", "show", "-output", "json")
+	if err != nil || outputJSON {
+		return i.write(output, err)
+	}
+
+	bundle := &types.Bundle{}
+	if err := protojson.Unmarshal(output, bundle); err != nil {
+		return fmt.Errorf("failed to decode the trust bundle: %w", err)
+	}
+
+	fmt.Fprintf(i.out, "Trust domain:    %s\nSequence number: %d\nRefresh hint:    %s\n\n", bundle.GetTrustDomain(), bundle.GetSequenceNumber(), time.Duration(bundle.GetRefreshHint())*time.Second)
+	w := tabwriter.NewWriter(i.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "AUTHORITY\tSUBJECT / KEY ID\tNOT AFTER\tSHA256 FINGERPRINT")
+	for _, authority := range bundle.GetX509Authorities() {
+		certificate, err := x509.ParseCertificate(authority.GetAsn1())
+		if err != nil {
+			return fmt.Errorf("failed to parse an X.509 authority: %w", err)
+		}
+		fingerprint := sha256.Sum256(certificate.Raw)
+		fmt.Fprintf(w, "x509\t%s\t%s\t%s\n", certificate.Subject, certificate.NotAfter.UTC().Format(time.RFC3339), hex.EncodeToString(fingerprint[:]))
+	}
+	for _, authority := range bundle.GetJwtAuthorities() {
+		fmt.Fprintf(w, "jwt\t%s\t%s\t-\n", authority.GetKeyId(), formatUnixTime(authority.GetExpiresAt()))
+	}
+	return w.Flush()
+}
+
+// spireServer runs the SPIRE server CLI in a running SPIRE server pod
+func (i *Inspector) spireServer(ctx context.Context, args ...string) ([]byte, error) {
+	namespace, err := i.operandNamespace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := i.client.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(utils.PodSelectorLabels(utils.SpireServerLabels(nil)))); err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return i.executor.Exec(ctx, namespace, pod.Name, spireServerContainer, append([]string{spireServerBinary}, args...))
+		}
+	}
+	return nil, fmt.Errorf("no running SPIRE server pod found in namespace %s", namespace)
+}
+
+// operandNamespace returns the namespace of the operands
+func (i *Inspector) operandNamespace(ctx context.Context) (string, error) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{}
+	if err := i.client.Get(ctx, client.ObjectKey{Name: "cluster"}, ztwim); err != nil {
+		if kerrors.IsNotFound(err) {
+			return i.namespace, nil
+		}
+		return "", err
+	}
+	if ztwim.Spec.OperandNamespace != "" {
+		return ztwim.Spec.OperandNamespace, nil
+	}
+	return i.namespace, nil
+}
+
+// write prints the raw output of the SPIRE server CLI
+func (i *Inspector) write(output []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	_, err = i.out.Write(output)
+	return err
+}
+
+// formatSPIFFEID returns the URI of a SPIFFE ID
+func formatSPIFFEID(id *types.SPIFFEID) string {
+	if id == nil {
+		return "-"
+	}
+	return "spiffe://" + id.GetTrustDomain() + id.GetPath()
+}
+
+// formatSelectors returns the selectors as comma separated type:value pairs
+func formatSelectors(selectors []*types.Selector) string {
+	formatted := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		formatted = append(formatted, selector.GetType()+":"+selector.GetValue())
+	}
+	return strings.Join(formatted, ",")
+}
+
+// formatUnixTime returns the RFC 3339 representation of a Unix time in seconds
+func formatUnixTime(seconds int64) string {
+	if seconds == 0 {
+		return "-"
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,201 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// fakeExecutor returns canned outputs of the SPIRE server CLI
+type fakeExecutor struct {
+	outputs  map[string]string
+	err      error
+	pod      string
+	commands []string
+}
+
+func (e *fakeExecutor) Exec(_ context.Context, namespace, pod, container string, command []string) ([]byte, error) {
+	e.pod = namespace + "/" + pod + "/" + container
+	key := strings.Join(command[1:3], " ")
+	e.commands = append(e.commands, strings.Join(command, " "))
+	return []byte(e.outputs[key]), e.err
+}
+
+func newTestClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func newServerPod(name, namespace string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    utils.PodSelectorLabels(utils.SpireServerLabels(nil)),
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestStatus(t *testing.T) {
+	server := &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	server.Status.Conditions = []metav1.Condition{
+		{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Failed", Message: "StatefulSet not ready"},
+	}
+	out := &bytes.Buffer{}
+	if err := New(newTestClient(t, server), &fakeExecutor{}, out, "operator").Status(context.Background()); err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Expected a header and 5 rows, got %q", out.String())
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "SpireServer" || fields[1] != "Ready" || fields[2] != "False" || fields[3] != "Failed" {
+		t.Errorf("Expected the SpireServer condition, got %q", lines[2])
+	}
+	if !strings.Contains(lines[1], "ZeroTrustWorkloadIdentityManager/cluster does not exist") {
+		t.Errorf("Expected the missing CR to be reported, got %q", lines[1])
+	}
+}
+
+func TestEntries(t *testing.T) {
+	executor := &fakeExecutor{outputs: map[string]string{
+		"entry show": `{"entries":[{"id":"entry-1","spiffe_id":{"trust_domain":"example.org","path":"/ns/app/sa/web"},` +
+			`"parent_id":{"trust_domain":"example.org","path":"/spire/agent/k8s_psat/cluster/node-1"},` +
+			`"selectors":[{"type":"k8s","value":"ns:app"},{"type":"k8s","value":"sa:web"}],"x509_svid_ttl":3600}],"next_page_token":""}`,
+	}}
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{OperandNamespace: "spire"},
+	}
+	c := newTestClient(t, ztwim, newServerPod("spire-server-0", "spire", corev1.PodPending), newServerPod("spire-server-1", "spire", corev1.PodRunning))
+
+	out := &bytes.Buffer{}
+	if err := New(c, executor, out, "operator").Entries(context.Background(), false); err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if executor.pod != "spire/spire-server-1/spire-server" {
+		t.Errorf("Expected the running SPIRE server pod of the operand namespace, got %s", executor.pod)
+	}
+	if executor.commands[0] != "/opt/spire/bin/spire-server entry show -output json" {
+		t.Errorf("Unexpected command %q", executor.commands[0])
+	}
+	for _, expected := range []string{"entry-1", "spiffe://example.org/ns/app/sa/web", "spiffe://example.org/spire/agent/k8s_psat/cluster/node-1", "k8s:ns:app,k8s:sa:web"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in the output, got %q", expected, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := New(c, executor, out, "operator").Entries(context.Background(), true); err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if out.String() != executor.outputs["entry show"] {
+		t.Errorf("Expected the raw JSON output, got %q", out.String())
+	}
+}
+
+func TestAgents(t *testing.T) {
+	executor := &fakeExecutor{outputs: map[string]string{
+		"agent list": `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/k8s_psat/cluster/node-1"},` +
+			`"attestation_type":"k8s_psat","x509svid_serial_number":"1","x509svid_expires_at":"1767225600","banned":false}]}`,
+	}}
+	out := &bytes.Buffer{}
+	c := newTestClient(t, newServerPod("spire-server-0", "operator", corev1.PodRunning))
+	if err := New(c, executor, out, "operator").Agents(context.Background(), false); err != nil {
+		t.Fatalf("Agents() error = %v", err)
+	}
+	for _, expected := range []string{"spiffe://example.org/spire/agent/k8s_psat/cluster/node-1", "k8s_psat", "2026-01-01T00:00:00Z", "false"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in the output, got %q", expected, out.String())
+		}
+	}
+}
+
+func TestBundle(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "spire-ca"},
+		NotBefore:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := &fakeExecutor{outputs: map[string]string{
+		"bundle show": fmt.Sprintf(`{"trust_domain":"example.org","x509_authorities":[{"asn1":%q}],`+
+			`"jwt_authorities":[{"key_id":"jwt-key","expires_at":"1767225600"}],"refresh_hint":"300","sequence_number":"7"}`,
+			base64.StdEncoding.EncodeToString(der)),
+	}}
+	out := &bytes.Buffer{}
+	c := newTestClient(t, newServerPod("spire-server-0", "operator", corev1.PodRunning))
+	if err := New(c, executor, out, "operator").Bundle(context.Background(), false); err != nil {
+		t.Fatalf("Bundle() error = %v", err)
+	}
+	for _, expected := range []string{"Trust domain:    example.org", "Sequence number: 7", "Refresh hint:    5m0s", "CN=spire-ca", "2026-01-01T00:00:00Z", "jwt-key"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in the output, got %q", expected, out.String())
+		}
+	}
+}
+
+func TestSpireServerErrors(t *testing.T) {
+	t.Run("no running SPIRE server pod", func(t *testing.T) {
+		c := newTestClient(t, newServerPod("spire-server-0", "operator", corev1.PodPending))
+		err := New(c, &fakeExecutor{}, &bytes.Buffer{}, "operator").Entries(context.Background(), false)
+		if err == nil || !strings.Contains(err.Error(), "no running SPIRE server pod found in namespace operator") {
+			t.Errorf("Expected an error for the missing pod, got %v", err)
+		}
+	})
+
+	t.Run("exec error", func(t *testing.T) {
+		c := newTestClient(t, newServerPod("spire-server-0", "operator", corev1.PodRunning))
+		executor := &fakeExecutor{err: errors.New("connection refused")}
+		if err := New(c, executor, &bytes.Buffer{}, "operator").Agents(context.Background(), false); err == nil {
+			t.Error("Expected the exec error to be returned")
+		}
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		c := newTestClient(t, newServerPod("spire-server-0", "operator", corev1.PodRunning))
+		executor := &fakeExecutor{outputs: map[string]string{"bundle show": "not json"}}
+		if err := New(c, executor, &bytes.Buffer{}, "operator").Bundle(context.Background(), false); err == nil {
+			t.Error("Expected an error for an invalid output")
+		}
+	})
+}
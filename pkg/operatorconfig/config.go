@@ -0,0 +1,120 @@
+package operatorconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// ConfigMapName is the name of the ConfigMap in the operator namespace holding the operator configuration
+const ConfigMapName = "zero-trust-workload-identity-manager-config"
+
+// Keys of the operator configuration ConfigMap. The images are overridden with keys named after the
+// RELATED_IMAGE_* environment variables of the operator, e.g. RELATED_IMAGE_SPIRE_SERVER.
+const (
+	ResyncIntervalKey          = "resyncInterval"
+	FeatureGatesKey            = "featureGates"
+	DefaultOperandNamespaceKey = "defaultOperandNamespace"
+)
+
+// imageEnvNames are the image environment variables the ConfigMap can override, including the images
+// of the SPIRE versions the operands can be pinned to
+var imageEnvNames = append([]string{
+	utils.SpireServerImageEnv,
+	utils.SpireAgentImageEnv,
+	utils.SpiffeCSIDriverImageEnv,
+	utils.SpireOIDCDiscoveryProviderImageEnv,
+	utils.SpireControllerManagerImageEnv,
+	utils.NodeDriverRegistrarImageEnv,
+	utils.SpiffeCSIInitContainerImageEnv,
+	utils.SpiffeHelperImageEnv,
+	utils.DatastoreBackupImageEnv,
+	utils.TornjakBackendImageEnv,
+	utils.TornjakFrontendImageEnv,
+	utils.OAuthProxyImageEnv,
+	utils.SpireServerFIPSImageEnv,
+	utils.SpireAgentFIPSImageEnv,
+	utils.SpiffeCSIDriverFIPSImageEnv,
+	utils.SpireOIDCDiscoveryProviderFIPSImageEnv,
+	utils.SpireControllerManagerFIPSImageEnv,
+	utils.NodeDriverRegistrarFIPSImageEnv,
+	utils.SpiffeHelperFIPSImageEnv,
+	utils.SpiffeCSIInitContainerFIPSImageEnv,
+}, utils.VersionedImageEnvNames()...)
+
+// Config is the operator configuration. The zero value of a field leaves the operator flag,
+// or the environment variable for the images, in effect.
+type Config struct {
+	// ResyncInterval overrides the --resync-interval flag
+	ResyncInterval time.Duration
+	// FeatureGates are merged over the --feature-gates flag
+	FeatureGates map[v1alpha1.FeatureGateName]bool
+	// DefaultOperandNamespace is set on the ZeroTrustWorkloadIdentityManager created without an operandNamespace
+	DefaultOperandNamespace string
+	// Images override the image environment variables, keyed by environment variable name
+	Images map[string]string
+}
+
+// Parse returns the configuration held by the data of the operator configuration ConfigMap
+func Parse(data map[string]string) (Config, error) {
+	config := Config{}
+	for key, value := range data {
+		value = strings.TrimSpace(value)
+		switch {
+		case key == ResyncIntervalKey:
+			interval, err := time.ParseDuration(value)
+			if err != nil {
+				return Config{}, fmt.Errorf("%s: %w", ResyncIntervalKey, err)
+			}
+			if interval < time.Minute {
+				return Config{}, fmt.Errorf("%s must be at least 1m, got %s", ResyncIntervalKey, interval)
+			}
+			config.ResyncInterval = interval
+		case key == FeatureGatesKey:
+			gates, err := utils.ParseFeatureGates(value)
+			if err != nil {
+				return Config{}, fmt.Errorf("%s: %w", FeatureGatesKey, err)
+			}
+			config.FeatureGates = gates
+		case key == DefaultOperandNamespaceKey:
+			if errs := validation.IsDNS1123Label(value); len(errs) > 0 {
+				return Config{}, fmt.Errorf("%s %q is not a valid namespace name: %s", DefaultOperandNamespaceKey, value, strings.Join(errs, ", "))
+			}
+			config.DefaultOperandNamespace = value
+		case isImageEnvName(key):
+			if value == "" {
+				return Config{}, fmt.Errorf("%s must not be empty", key)
+			}
+			if config.Images == nil {
+				config.Images = map[string]string{}
+			}
+			config.Images[key] = value
+		default:
+			return Config{}, fmt.Errorf("unknown key %q, valid keys are %s", key, strings.Join(validKeys(), ", "))
+		}
+	}
+	return config, nil
+}
+
+// isImageEnvName reports whether key is an image environment variable the ConfigMap can override
+func isImageEnvName(key string) bool {
+	for _, name := range imageEnvNames {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validKeys returns the sorted keys of the operator configuration ConfigMap
+func validKeys() []string {
+	keys := append([]string{ResyncIntervalKey, FeatureGatesKey, DefaultOperandNamespaceKey}, imageEnvNames...)
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package operatorconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string]string
+		expected    Config
+		expectedErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "all keys",
+			data: map[string]string{
+				ResyncIntervalKey:          "30m",
+				FeatureGatesKey:            "Federation=false",
+				DefaultOperandNamespaceKey: " spire-system\n",
+				utils.SpireServerImageEnv:  "registry.example.com/spire-server:v1.2.4",
+			},
+			expected: Config{
+				ResyncInterval:          30 * time.Minute,
+				FeatureGates:            map[v1alpha1.FeatureGateName]bool{v1alpha1.FeatureGateFederation: false},
+				DefaultOperandNamespace: "spire-system",
+				Images:                  map[string]string{utils.SpireServerImageEnv: "registry.example.com/spire-server:v1.2.4"},
+			},
+		},
+		{
+			name:        "invalid resync interval",
+			data:        map[string]string{ResyncIntervalKey: "often"},
+			expectedErr: "resyncInterval",
+		},
+		{
+			name:        "resync interval too short",
+			data:        map[string]string{ResyncIntervalKey: "30s"},
+			expectedErr: "must be at least 1m",
+		},
+		{
+			name:        "unknown feature gate",
+			data:        map[string]string{FeatureGatesKey: "Teleport=true"},
+			expectedErr: `unknown feature gate "Teleport"`,
+		},
+		{
+			name:        "invalid namespace",
+			data:        map[string]string{DefaultOperandNamespaceKey: "Spire_System"},
+			expectedErr: "is not a valid namespace name",
+		},
+		{
+			name:        "empty image",
+			data:        map[string]string{utils.SpireAgentImageEnv: " "},
+			expectedErr: "must not be empty",
+		},
+		{
+			name:        "unknown key",
+			data:        map[string]string{"resync": "30m"},
+			expectedErr: `unknown key "resync"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Parse(tt.data)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
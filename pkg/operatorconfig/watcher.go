@@ -0,0 +1,147 @@
+package operatorconfig
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// Defaults holds the settings of the operator flags, in effect for the keys the ConfigMap does not set
+type Defaults struct {
+	ResyncInterval time.Duration
+	FeatureGates   map[v1alpha1.FeatureGateName]bool
+}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   []chan event.GenericEvent
+)
+
+// Subscribe returns a channel receiving an event each time the operator configuration is applied,
+// for the controllers to re-reconcile their operands with the new settings
+func Subscribe() <-chan event.GenericEvent {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	// A buffer of one is enough, a pending event already triggers a reconcile with the latest settings
+	ch := make(chan event.GenericEvent, 1)
+	subscribers = append(subscribers, ch)
+	return ch
+}
+
+// notify sends an event to the subscribers that have none pending
+func notify() {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: utils.GetOperatorNamespace()}}
+	for _, ch := range subscribers {
+		select {
+		case ch <- event.GenericEvent{Object: configMap}:
+		default:
+		}
+	}
+}
+
+// Apply sets the operator wide settings from the flag defaults overridden by config
+func Apply(defaults Defaults, config Config) {
+	resyncInterval := defaults.ResyncInterval
+	if config.ResyncInterval > 0 {
+		resyncInterval = config.ResyncInterval
+	}
+	utils.SetOperatorResyncInterval(resyncInterval)
+
+	featureGates := maps.Clone(defaults.FeatureGates)
+	if featureGates == nil {
+		featureGates = map[v1alpha1.FeatureGateName]bool{}
+	}
+	maps.Copy(featureGates, config.FeatureGates)
+	utils.SetOperatorFeatureGates(featureGates)
+
+	utils.SetImageOverrides(config.Images)
+	utils.SetDefaultOperandNamespace(config.DefaultOperandNamespace)
+}
+
+// Watcher applies the operator configuration ConfigMap each time it changes, so that the operator
+// settings are tuned without restarting the operator pod. Deleting the ConfigMap restores the
+// flag defaults, and an invalid ConfigMap leaves the previous settings in effect.
+type Watcher struct {
+	clientset kubernetes.Interface
+	namespace string
+	defaults  Defaults
+	log       logr.Logger
+}
+
+// NewWatcher returns a Watcher of the operator configuration ConfigMap in namespace
+func NewWatcher(clientset kubernetes.Interface, namespace string, defaults Defaults) *Watcher {
+	return &Watcher{
+		clientset: clientset,
+		namespace: namespace,
+		defaults:  defaults,
+		log:       ctrl.Log.WithName("operator-config"),
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica applies the
+// configuration, as the admission webhooks are served by all of them.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable
+func (w *Watcher) Start(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(w.clientset, 0,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", ConfigMapName).String()
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    w.update,
+		UpdateFunc: func(_, obj any) { w.update(obj) },
+		DeleteFunc: func(any) { w.apply(Config{}) },
+	}); err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
+
+// update applies the configuration of the ConfigMap, unless it is invalid
+func (w *Watcher) update(obj any) {
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	config, err := Parse(configMap.Data)
+	if err != nil {
+		w.log.Error(err, "ignoring invalid operator configuration, the previous settings stay in effect",
+			"namespace", configMap.Namespace, "name", configMap.Name)
+		return
+	}
+	w.apply(config)
+}
+
+// apply applies config and triggers the reconciliation of the operands
+func (w *Watcher) apply(config Config) {
+	Apply(w.defaults, config)
+	w.log.Info("applied operator configuration", "resyncInterval", utils.ResyncInterval(nil, nil),
+		"featureGates", config.FeatureGates, "defaultOperandNamespace", config.DefaultOperandNamespace, "images", config.Images)
+	notify()
+}
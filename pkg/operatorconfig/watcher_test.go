@@ -0,0 +1,115 @@
+package operatorconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func resetSettings(t *testing.T) {
+	t.Cleanup(func() {
+		utils.SetOperatorResyncInterval(utils.DefaultResyncInterval)
+		utils.SetOperatorFeatureGates(nil)
+		utils.SetImageOverrides(nil)
+		utils.SetDefaultOperandNamespace("")
+	})
+}
+
+func TestApply(t *testing.T) {
+	resetSettings(t)
+	defaults := Defaults{
+		ResyncInterval: time.Hour,
+		FeatureGates:   map[v1alpha1.FeatureGateName]bool{v1alpha1.FeatureGateFederation: false},
+	}
+
+	Apply(defaults, Config{
+		ResyncInterval:          30 * time.Minute,
+		FeatureGates:            map[v1alpha1.FeatureGateName]bool{v1alpha1.FeatureGateNestedTopology: true},
+		DefaultOperandNamespace: "spire-system",
+	})
+	if interval := utils.ResyncInterval(nil, nil); interval != 30*time.Minute {
+		t.Errorf("expected the resync interval of the ConfigMap, got %s", interval)
+	}
+	if utils.FeatureGateEnabled(nil, v1alpha1.FeatureGateFederation) || !utils.FeatureGateEnabled(nil, v1alpha1.FeatureGateNestedTopology) {
+		t.Error("expected the feature gates of the ConfigMap to be merged over the flag")
+	}
+	if _, ok := defaults.FeatureGates[v1alpha1.FeatureGateNestedTopology]; ok {
+		t.Error("expected the flag defaults not to be modified")
+	}
+	if namespace := utils.GetDefaultOperandNamespace(); namespace != "spire-system" {
+		t.Errorf("expected the default operand namespace of the ConfigMap, got %q", namespace)
+	}
+
+	Apply(defaults, Config{})
+	if interval := utils.ResyncInterval(nil, nil); interval != time.Hour {
+		t.Errorf("expected the resync interval of the flag, got %s", interval)
+	}
+	if utils.FeatureGateEnabled(nil, v1alpha1.FeatureGateNestedTopology) {
+		t.Error("expected the feature gates of the ConfigMap to be removed")
+	}
+	if namespace := utils.GetDefaultOperandNamespace(); namespace != "" {
+		t.Errorf("expected no default operand namespace, got %q", namespace)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	resetSettings(t)
+	events := Subscribe()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "operator"},
+		Data:       map[string]string{ResyncIntervalKey: "20m"},
+	}
+	clientset := fake.NewClientset(configMap)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := NewWatcher(clientset, "operator", Defaults{ResyncInterval: time.Hour})
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	}()
+
+	waitForEvent := func() {
+		t.Helper()
+		select {
+		case <-events:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the operator configuration to be applied")
+		}
+	}
+
+	waitForEvent()
+	if interval := utils.ResyncInterval(nil, nil); interval != 20*time.Minute {
+		t.Errorf("expected the resync interval of the ConfigMap, got %s", interval)
+	}
+
+	// An invalid configuration leaves the previous settings in effect
+	configMap.Data = map[string]string{ResyncIntervalKey: "1s"}
+	if _, err := clientset.CoreV1().ConfigMaps("operator").Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update the ConfigMap: %v", err)
+	}
+	configMap.Data = map[string]string{ResyncIntervalKey: "40m"}
+	if _, err := clientset.CoreV1().ConfigMaps("operator").Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update the ConfigMap: %v", err)
+	}
+	waitForEvent()
+	if interval := utils.ResyncInterval(nil, nil); interval != 40*time.Minute {
+		t.Errorf("expected the updated resync interval of the ConfigMap, got %s", interval)
+	}
+
+	if err := clientset.CoreV1().ConfigMaps("operator").Delete(ctx, ConfigMapName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete the ConfigMap: %v", err)
+	}
+	waitForEvent()
+	if interval := utils.ResyncInterval(nil, nil); interval != time.Hour {
+		t.Errorf("expected the resync interval of the flag once the ConfigMap is deleted, got %s", interval)
+	}
+}
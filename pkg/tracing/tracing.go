@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// TracerName is the instrumentation scope of the operator spans
+	TracerName = "github.com/openshift/zero-trust-workload-identity-manager"
+
+	// The standard OpenTelemetry environment variables enabling the OTLP export of the spans
+	otlpEndpointEnvName       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	otlpTracesEndpointEnvName = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	sdkDisabledEnvName        = "OTEL_SDK_DISABLED"
+)
+
+// Enabled reports whether an OTLP endpoint is configured for the spans
+func Enabled() bool {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv(sdkDisabledEnvName)), "true") {
+		return false
+	}
+	return os.Getenv(otlpEndpointEnvName) != "" || os.Getenv(otlpTracesEndpointEnvName) != ""
+}
+
+// Setup exports the spans with OTLP over gRPC when an OTLP endpoint is configured with the standard
+// OpenTelemetry environment variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT. Otherwise the spans are
+// dropped by the no-op tracer provider. The returned function flushes the pending spans on shutdown.
+func Setup(ctx context.Context, serviceName, serviceVersion string) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(serviceVersion)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of the operator tracer
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ObjectAttributes returns the span attributes identifying an object
+func ObjectAttributes(kind, namespace, name string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("k8s.object.kind", kind),
+		attribute.String("k8s.object.name", name),
+	}
+	if namespace != "" {
+		attrs = append(attrs, attribute.String("k8s.object.namespace", namespace))
+	}
+	return attrs
+}
+
+// reconciler starts a span for every reconcile of the wrapped reconciler
+type reconciler struct {
+	controllerName string
+	reconciler     reconcile.Reconciler
+}
+
+// WrapReconciler returns a reconciler running r in a span named after the controller. Every span
+// started by r, for the rendering of the managed resources and the API requests, is a child of it.
+func WrapReconciler(controllerName string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &reconciler{controllerName: controllerName, reconciler: r}
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Start(ctx, "Reconcile "+r.controllerName,
+		attribute.String("controller", r.controllerName),
+		attribute.String("k8s.object.name", req.Name),
+	)
+	result, err := r.reconciler.Reconcile(ctx, req)
+	if result.RequeueAfter > 0 {
+		span.SetAttributes(attribute.String("reconcile.requeue_after", result.RequeueAfter.String()))
+	}
+	End(span, err)
+	return result, err
+}
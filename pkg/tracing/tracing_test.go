@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// newRecorder routes the spans of the operator tracer to a recorder for the duration of the test
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{
+			name:     "no endpoint",
+			expected: false,
+		},
+		{
+			name:     "endpoint",
+			env:      map[string]string{otlpEndpointEnvName: "http://collector:4317"},
+			expected: true,
+		},
+		{
+			name:     "traces endpoint",
+			env:      map[string]string{otlpTracesEndpointEnvName: "http://collector:4317"},
+			expected: true,
+		},
+		{
+			name:     "sdk disabled",
+			env:      map[string]string{otlpEndpointEnvName: "http://collector:4317", sdkDisabledEnvName: "TRUE"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{otlpEndpointEnvName, otlpTracesEndpointEnvName, sdkDisabledEnvName} {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := Enabled(); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	t.Setenv(otlpEndpointEnvName, "")
+	t.Setenv(otlpTracesEndpointEnvName, "")
+
+	shutdown, err := Setup(context.Background(), "test", "0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := newRecorder(t)
+
+	_, span := Start(context.Background(), "succeeded", ObjectAttributes("ConfigMap", "ns", "name")...)
+	End(span, nil)
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Unset {
+		t.Errorf("expected an unset status, got %v", spans[0].Status().Code)
+	}
+	expectedAttrs := map[attribute.Key]string{"k8s.object.kind": "ConfigMap", "k8s.object.namespace": "ns", "k8s.object.name": "name"}
+	for _, attr := range spans[0].Attributes() {
+		if expectedAttrs[attr.Key] != attr.Value.AsString() {
+			t.Errorf("unexpected attribute %s=%s", attr.Key, attr.Value.AsString())
+		}
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "boom" {
+		t.Errorf("expected an error status, got %v", spans[1].Status())
+	}
+	if len(spans[1].Events()) != 1 {
+		t.Errorf("expected the error to be recorded as an event, got %d events", len(spans[1].Events()))
+	}
+}
+
+func TestWrapReconciler(t *testing.T) {
+	recorder := newRecorder(t)
+
+	var childParent string
+	inner := reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		_, child := Start(ctx, "child")
+		childParent = child.(sdktrace.ReadOnlySpan).Parent().SpanID().String()
+		End(child, nil)
+		return reconcile.Result{RequeueAfter: time.Minute}, errors.New("failed")
+	})
+
+	result, err := WrapReconciler("test-controller", inner).Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster"}})
+	if err == nil || err.Error() != "failed" {
+		t.Fatalf("expected the reconcile error, got %v", err)
+	}
+	if result.RequeueAfter != time.Minute {
+		t.Errorf("expected the reconcile result, got %v", result)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	reconcileSpan := spans[1]
+	if reconcileSpan.Name() != "Reconcile test-controller" {
+		t.Errorf("unexpected span name %q", reconcileSpan.Name())
+	}
+	if childParent != reconcileSpan.SpanContext().SpanID().String() {
+		t.Errorf("expected the child span to be parented to the reconcile span")
+	}
+	if reconcileSpan.Status().Code != codes.Error {
+		t.Errorf("expected an error status, got %v", reconcileSpan.Status())
+	}
+}
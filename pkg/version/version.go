@@ -16,4 +16,10 @@ var (
 	SpireControllerManagerVersion     string = "0.6.3"
 	SpireOIDCDiscoveryProviderVersion string = "1.13.3"
 	SpireServerVersion                string = "1.13.3"
+	TornjakVersion                    string = "2.0.0"
+
+	// SPIRE versions the SPIRE server, agents and OIDC discovery provider can be pinned to, as a
+	// comma-separated list. The images of the versions other than the component versions above are
+	// set with environment variables suffixed with the version, e.g. RELATED_IMAGE_SPIRE_SERVER_1_12_4.
+	SupportedSpireVersions string = "1.12.4,1.13.3"
 )
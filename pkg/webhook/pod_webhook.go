@@ -0,0 +1,267 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// Labels and annotations controlling the spiffe-helper sidecar injection
+const (
+	// SpiffeHelperInjectionLabel opts all the pods of a namespace in to the injection when set to "enabled"
+	SpiffeHelperInjectionLabel = "ztwim.openshift.io/spiffe-helper-injection"
+	// SpiffeHelperInjectLabel opts a pod in to the injection when "true", or out of it when "false",
+	// regardless of the label of its namespace. It is a label so that the webhook objectSelector can match it.
+	SpiffeHelperInjectLabel = "ztwim.openshift.io/inject-spiffe-helper"
+	// SpiffeHelperCertDirAnnotation sets the directory the SVID files are written to in the pod containers
+	SpiffeHelperCertDirAnnotation = "ztwim.openshift.io/spiffe-helper-cert-dir"
+	// spiffeHelperConfigAnnotation holds the generated helper.conf, mounted into the sidecar through the downward API
+	spiffeHelperConfigAnnotation = "ztwim.openshift.io/spiffe-helper-config"
+)
+
+const (
+	spiffeHelperContainerName    = "spiffe-helper"
+	spiffeHelperDefaultCertDir   = "/run/spiffe/certs"
+	spiffeHelperConfigDir        = "/run/spiffe-helper"
+	spiffeWorkloadAPIDir         = "/spiffe-workload-api"
+	spiffeWorkloadAPIVolumeName  = "spiffe-workload-api"
+	spiffeHelperCertsVolumeName  = "spiffe-helper-certs"
+	spiffeHelperConfigVolumeName = "spiffe-helper-config"
+	defaultSpiffeCSIPluginName   = "csi.spiffe.io"
+)
+
+// The pods reach the webhook through two registrations, scoped by the namespace and object selectors patched in
+// config/webhook: mspiffehelper for the pods of the namespaces labeled for the injection, and mspiffehelperpod
+// for the pods labeled for it in the other namespaces.
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mspiffehelper.operator.openshift.io,admissionReviewVersions=v1,timeoutSeconds=5
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mspiffehelperpod.operator.openshift.io,admissionReviewVersions=v1,timeoutSeconds=5
+
+// SpiffeHelperInjector injects a spiffe-helper sidecar and the SPIFFE CSI volume into the pods that
+// opt in, so that workloads reading their certificates from files get rotating X.509 SVIDs without
+// manifest changes. The SVID, its key and the trust bundle are written to an in-memory volume
+// mounted into every container of the pod.
+type SpiffeHelperInjector struct {
+	// reader is used to look up the namespace labels and the CSI plugin name of the SpiffeCSIDriver
+	reader client.Reader
+}
+
+var _ admission.CustomDefaulter = &SpiffeHelperInjector{}
+
+// Default implements admission.CustomDefaulter
+func (d *SpiffeHelperInjector) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod but got %T", obj)
+	}
+
+	namespace := pod.Namespace
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Namespace != "" {
+		namespace = req.Namespace
+	}
+
+	// The system namespaces are never injected, even when labeled. OLM sets the namespace selector of the
+	// webhook from the OperatorGroup, so the exclusion is not left to the selector alone.
+	if isSystemNamespace(namespace) {
+		return nil
+	}
+	// The SpiffeHelperInjection feature gate turns the injection off cluster wide
+	if !d.injectionEnabled(ctx) {
+		return nil
+	}
+
+	// Pods are admitted unchanged when the lookups fail, the injection must never block a workload
+	inject, err := d.injectionRequested(ctx, pod, namespace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "skipping spiffe-helper injection", "namespace", namespace)
+		return nil
+	}
+	if !inject || hasContainer(pod, spiffeHelperContainerName) {
+		return nil
+	}
+	// A non-FIPS sidecar must not be injected on a FIPS cluster
+	if err := utils.ValidateFIPSImages(utils.SpiffeHelperFIPSImageEnv); err != nil {
+		logf.FromContext(ctx).Error(err, "skipping spiffe-helper injection", "namespace", namespace)
+		return nil
+	}
+
+	injectSpiffeHelper(pod, d.pluginName(ctx), d.socketName(ctx))
+	return nil
+}
+
+// isSystemNamespace reports whether namespace belongs to the platform, whose pods are never injected
+func isSystemNamespace(namespace string) bool {
+	return strings.HasPrefix(namespace, "openshift-") || strings.HasPrefix(namespace, "kube-") || namespace == "openshift"
+}
+
+// injectionRequested reports whether the pod label or, when unset, the namespace label requests the injection
+func (d *SpiffeHelperInjector) injectionRequested(ctx context.Context, pod *corev1.Pod, namespace string) (bool, error) {
+	if value, ok := pod.Labels[SpiffeHelperInjectLabel]; ok {
+		return value == "true", nil
+	}
+	if d.reader == nil || namespace == "" {
+		return false, nil
+	}
+	var ns corev1.Namespace
+	if err := d.reader.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return false, fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+	}
+	return ns.Labels[SpiffeHelperInjectionLabel] == "enabled", nil
+}
+
+// injectionEnabled reports whether the SpiffeHelperInjection feature gate is enabled on the cluster
+func (d *SpiffeHelperInjector) injectionEnabled(ctx context.Context) bool {
+	if d.reader == nil {
+		return utils.FeatureGateEnabled(nil, v1alpha1.FeatureGateSpiffeHelperInjection)
+	}
+	var ztwim v1alpha1.ZeroTrustWorkloadIdentityManager
+	if err := d.reader.Get(ctx, types.NamespacedName{Name: "cluster"}, &ztwim); err != nil {
+		return utils.FeatureGateEnabled(nil, v1alpha1.FeatureGateSpiffeHelperInjection)
+	}
+	return utils.FeatureGateEnabled(&ztwim, v1alpha1.FeatureGateSpiffeHelperInjection)
+}
+
+// pluginName returns the CSI plugin name of the cluster SpiffeCSIDriver, falling back to its default
+func (d *SpiffeHelperInjector) pluginName(ctx context.Context) string {
+	if d.reader == nil {
+		return defaultSpiffeCSIPluginName
+	}
+	var driver v1alpha1.SpiffeCSIDriver
+	if err := d.reader.Get(ctx, types.NamespacedName{Name: "cluster"}, &driver); err != nil || driver.Spec.PluginName == "" {
+		return defaultSpiffeCSIPluginName
+	}
+	return driver.Spec.PluginName
+}
+
+// socketName returns the Workload API socket name of the cluster SpireAgent, falling back to its default
+func (d *SpiffeHelperInjector) socketName(ctx context.Context) string {
+	if d.reader == nil {
+		return utils.DefaultAgentSocketName
+	}
+	var agent v1alpha1.SpireAgent
+	if err := d.reader.Get(ctx, types.NamespacedName{Name: "cluster"}, &agent); err != nil {
+		return utils.DefaultAgentSocketName
+	}
+	return utils.GetAgentSocketName(&agent)
+}
+
+// injectSpiffeHelper adds the spiffe-helper sidecar, its volumes and the certificate mounts to pod
+func injectSpiffeHelper(pod *corev1.Pod, pluginName, socketName string) {
+	certDir := spiffeHelperDefaultCertDir
+	if dir := pod.Annotations[SpiffeHelperCertDirAnnotation]; dir != "" && path.IsAbs(dir) {
+		certDir = path.Clean(dir)
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[spiffeHelperConfigAnnotation] = generateSpiffeHelperConfig(certDir, socketName)
+
+	addVolume(pod, corev1.Volume{
+		Name: spiffeWorkloadAPIVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			CSI: &corev1.CSIVolumeSource{
+				Driver:   pluginName,
+				ReadOnly: ptr.To(true),
+			},
+		},
+	})
+	addVolume(pod, corev1.Volume{
+		Name: spiffeHelperCertsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+		},
+	})
+	addVolume(pod, corev1.Volume{
+		Name: spiffeHelperConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: "helper.conf",
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: fmt.Sprintf("metadata.annotations['%s']", spiffeHelperConfigAnnotation),
+						},
+					},
+				},
+			},
+		},
+	})
+
+	certsMount := corev1.VolumeMount{Name: spiffeHelperCertsVolumeName, MountPath: certDir, ReadOnly: true}
+	for i := range pod.Spec.Containers {
+		addVolumeMount(&pod.Spec.Containers[i], certsMount)
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+		Name:            spiffeHelperContainerName,
+		Image:           utils.GetSpiffeHelperImage(),
+		Args:            []string{"-config", path.Join(spiffeHelperConfigDir, "helper.conf")},
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: spiffeWorkloadAPIVolumeName, MountPath: spiffeWorkloadAPIDir, ReadOnly: true},
+			{Name: spiffeHelperCertsVolumeName, MountPath: certDir},
+			{Name: spiffeHelperConfigVolumeName, MountPath: spiffeHelperConfigDir, ReadOnly: true},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			RunAsNonRoot:             ptr.To(true),
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	})
+}
+
+// generateSpiffeHelperConfig renders the helper.conf of the sidecar, which keeps the SVID files in certDir up to date
+// from the Workload API socket socketName
+func generateSpiffeHelperConfig(certDir, socketName string) string {
+	return fmt.Sprintf(`agent_address = %q
+cert_dir = %q
+svid_file_name = %q
+svid_key_file_name = %q
+svid_bundle_file_name = %q
+daemon_mode = true
+`, path.Join(spiffeWorkloadAPIDir, socketName), certDir, "tls.crt", "tls.key", "ca.crt")
+}
+
+// hasContainer reports whether pod already has a container named name
+func hasContainer(pod *corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addVolume adds volume to pod unless the pod already has a volume of that name
+func addVolume(pod *corev1.Pod, volume corev1.Volume) {
+	for _, existing := range pod.Spec.Volumes {
+		if existing.Name == volume.Name {
+			return
+		}
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+}
+
+// addVolumeMount adds mount to container unless the container already mounts something at that path
+func addVolumeMount(container *corev1.Container, mount corev1.VolumeMount) {
+	for _, existing := range container.VolumeMounts {
+		if existing.MountPath == mount.MountPath {
+			return
+		}
+	}
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+}
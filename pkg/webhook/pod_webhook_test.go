@@ -0,0 +1,188 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func newTestPod(labels, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy-app", Namespace: "apps", Labels: labels, Annotations: annotations},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "legacy-app:latest"}},
+		},
+	}
+}
+
+func TestSpiffeHelperInjectorDefault(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	enabledNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "apps",
+		Labels: map[string]string{SpiffeHelperInjectionLabel: "enabled"},
+	}}
+	plainNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}}
+	newZTWIM := func(enabled bool) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+				FeatureGates: []v1alpha1.FeatureGate{{Name: v1alpha1.FeatureGateSpiffeHelperInjection, Enabled: enabled}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		labels         map[string]string
+		namespace      string
+		objects        []client.Object
+		expectInjected bool
+	}{
+		{
+			name:           "pod label opts in",
+			labels:         map[string]string{SpiffeHelperInjectLabel: "true"},
+			objects:        []client.Object{plainNamespace, newZTWIM(true)},
+			expectInjected: true,
+		},
+		{
+			name:           "namespace label opts in",
+			objects:        []client.Object{enabledNamespace, newZTWIM(true)},
+			expectInjected: true,
+		},
+		{
+			name:    "pod label opts out of an enabled namespace",
+			labels:  map[string]string{SpiffeHelperInjectLabel: "false"},
+			objects: []client.Object{enabledNamespace, newZTWIM(true)},
+		},
+		{
+			name:    "not requested",
+			objects: []client.Object{plainNamespace, newZTWIM(true)},
+		},
+		{
+			name:    "namespace lookup failure admits the pod unchanged",
+			objects: []client.Object{newZTWIM(true)},
+		},
+		{
+			name:      "system namespace is never injected",
+			labels:    map[string]string{SpiffeHelperInjectLabel: "true"},
+			namespace: "openshift-monitoring",
+			objects: []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "openshift-monitoring",
+				Labels: map[string]string{SpiffeHelperInjectionLabel: "enabled"},
+			}}},
+		},
+		{
+			name:    "feature gate disabled",
+			labels:  map[string]string{SpiffeHelperInjectLabel: "true"},
+			objects: []client.Object{plainNamespace, newZTWIM(false)},
+		},
+		{
+			name:    "feature gate disabled by default",
+			labels:  map[string]string{SpiffeHelperInjectLabel: "true"},
+			objects: []client.Object{plainNamespace},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := &SpiffeHelperInjector{reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()}
+			pod := newTestPod(tt.labels, nil)
+			if tt.namespace != "" {
+				pod.Namespace = tt.namespace
+			}
+
+			if err := injector.Default(context.Background(), pod); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if injected := hasContainer(pod, spiffeHelperContainerName); injected != tt.expectInjected {
+				t.Errorf("Expected injected=%v, got containers %v", tt.expectInjected, pod.Spec.Containers)
+			}
+		})
+	}
+}
+
+func TestInjectSpiffeHelper(t *testing.T) {
+	pod := newTestPod(nil, map[string]string{SpiffeHelperCertDirAnnotation: "/etc/tls/"})
+	injectSpiffeHelper(pod, "csi.example.org", utils.DefaultAgentSocketName)
+
+	if len(pod.Spec.Containers) != 2 {
+		t.Fatalf("Expected the sidecar to be appended, got %d containers", len(pod.Spec.Containers))
+	}
+	app, sidecar := pod.Spec.Containers[0], pod.Spec.Containers[1]
+	if sidecar.Name != spiffeHelperContainerName {
+		t.Errorf("Expected sidecar %q, got %q", spiffeHelperContainerName, sidecar.Name)
+	}
+	if len(app.VolumeMounts) != 1 || app.VolumeMounts[0].MountPath != "/etc/tls" || !app.VolumeMounts[0].ReadOnly {
+		t.Errorf("Expected the certificates to be mounted read-only at /etc/tls, got %v", app.VolumeMounts)
+	}
+
+	volumes := map[string]corev1.Volume{}
+	for _, volume := range pod.Spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	if csi := volumes[spiffeWorkloadAPIVolumeName].CSI; csi == nil || csi.Driver != "csi.example.org" {
+		t.Errorf("Expected a CSI volume of driver csi.example.org, got %v", volumes[spiffeWorkloadAPIVolumeName])
+	}
+	if volumes[spiffeHelperCertsVolumeName].EmptyDir == nil {
+		t.Errorf("Expected an emptyDir certificates volume, got %v", volumes[spiffeHelperCertsVolumeName])
+	}
+	if volumes[spiffeHelperConfigVolumeName].DownwardAPI == nil {
+		t.Errorf("Expected a downward API config volume, got %v", volumes[spiffeHelperConfigVolumeName])
+	}
+
+	config := pod.Annotations[spiffeHelperConfigAnnotation]
+	for _, expected := range []string{
+		`agent_address = "/spiffe-workload-api/spire-agent.sock"`,
+		`cert_dir = "/etc/tls"`,
+		`svid_file_name = "tls.crt"`,
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("Expected helper.conf to contain %q, got:\n%s", expected, config)
+		}
+	}
+
+	// A pod that already has the sidecar is left unchanged, e.g. when the webhook is reinvoked
+	injector := &SpiffeHelperInjector{}
+	pod.Labels = map[string]string{SpiffeHelperInjectLabel: "true"}
+	if err := injector.Default(context.Background(), pod); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pod.Spec.Containers) != 2 || len(pod.Spec.Volumes) != 3 {
+		t.Errorf("Expected the injection to be idempotent, got %d containers and %d volumes", len(pod.Spec.Containers), len(pod.Spec.Volumes))
+	}
+}
+
+func TestSpiffeHelperInjectorSocketName(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	agent := &v1alpha1.SpireAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.SpireAgentSpec{SocketName: "socket"},
+	}
+
+	injector := &SpiffeHelperInjector{reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()}
+	if got := injector.socketName(context.Background()); got != "socket" {
+		t.Errorf("Expected the socket name of the SpireAgent, got %q", got)
+	}
+	if got := (&SpiffeHelperInjector{}).socketName(context.Background()); got != utils.DefaultAgentSocketName {
+		t.Errorf("Expected the default socket name without a reader, got %q", got)
+	}
+
+	pod := newTestPod(nil, nil)
+	injectSpiffeHelper(pod, "csi.example.org", "socket")
+	if config := pod.Annotations[spiffeHelperConfigAnnotation]; !strings.Contains(config, `agent_address = "/spiffe-workload-api/socket"`) {
+		t.Errorf("Expected helper.conf to use the socket name, got:\n%s", config)
+	}
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-openshift-io-v1alpha1-spiffecsidriver,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spiffecsidrivers,verbs=create,versions=v1alpha1,name=mspiffecsidriver.operator.openshift.io,admissionReviewVersions=v1
+
+// SpiffeCSIDriverDefaulter fills in the SpiffeCSIDriver defaults on create, so that the stored
+// object shows the effective configuration
+type SpiffeCSIDriverDefaulter struct{}
+
+var _ admission.CustomDefaulter = &SpiffeCSIDriverDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *SpiffeCSIDriverDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	driver, ok := obj.(*v1alpha1.SpiffeCSIDriver)
+	if !ok {
+		return fmt.Errorf("expected a SpiffeCSIDriver but got %T", obj)
+	}
+
+	if driver.Spec.AgentSocketPath == "" {
+		driver.Spec.AgentSocketPath = "/run/spire/agent-sockets"
+	}
+	if driver.Spec.PluginName == "" {
+		driver.Spec.PluginName = "csi.spiffe.io"
+	}
+	if driver.Spec.KubeletPath == "" {
+		driver.Spec.KubeletPath = "/var/lib/kubelet"
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-operator-openshift-io-v1alpha1-spiffecsidriver,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spiffecsidrivers,verbs=create;update,versions=v1alpha1,name=vspiffecsidriver.operator.openshift.io,admissionReviewVersions=v1
+
+// SpiffeCSIDriverValidator validates SpiffeCSIDriver resources at admission time
+type SpiffeCSIDriverValidator struct{}
+
+var _ admission.CustomValidator = &SpiffeCSIDriverValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *SpiffeCSIDriverValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *SpiffeCSIDriverValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *SpiffeCSIDriverValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *SpiffeCSIDriverValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	driver, ok := obj.(*v1alpha1.SpiffeCSIDriver)
+	if !ok {
+		return nil, fmt.Errorf("expected a SpiffeCSIDriver but got %T", obj)
+	}
+
+	if fieldErr := validateCommonConfig(&driver.Spec.CommonConfig); fieldErr != nil {
+		return nil, invalid("SpiffeCSIDriver", driver.Name, fieldErr)
+	}
+	if fieldErr := validateSecurityContext(&driver.Spec.CommonConfig, true); fieldErr != nil {
+		return nil, invalid("SpiffeCSIDriver", driver.Name, fieldErr)
+	}
+	return nil, nil
+}
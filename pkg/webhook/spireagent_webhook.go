@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-openshift-io-v1alpha1-spireagent,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spireagents,verbs=create,versions=v1alpha1,name=mspireagent.operator.openshift.io,admissionReviewVersions=v1
+
+// SpireAgentDefaulter fills in the SpireAgent defaults on create, so that the stored
+// object shows the effective configuration
+type SpireAgentDefaulter struct{}
+
+var _ admission.CustomDefaulter = &SpireAgentDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *SpireAgentDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	agent, ok := obj.(*v1alpha1.SpireAgent)
+	if !ok {
+		return fmt.Errorf("expected a SpireAgent but got %T", obj)
+	}
+
+	if agent.Spec.SocketPath == "" {
+		agent.Spec.SocketPath = "/run/spire/agent-sockets"
+	}
+	if agent.Spec.SocketName == "" {
+		agent.Spec.SocketName = utils.DefaultAgentSocketName
+	}
+	if agent.Spec.LogLevel == "" {
+		agent.Spec.LogLevel = defaultLogLevel
+	}
+	if agent.Spec.LogFormat == "" {
+		agent.Spec.LogFormat = defaultLogFormat
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-operator-openshift-io-v1alpha1-spireagent,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spireagents,verbs=create;update,versions=v1alpha1,name=vspireagent.operator.openshift.io,admissionReviewVersions=v1
+
+// SpireAgentValidator validates SpireAgent resources at admission time
+type SpireAgentValidator struct{}
+
+var _ admission.CustomValidator = &SpireAgentValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *SpireAgentValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *SpireAgentValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *SpireAgentValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *SpireAgentValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	agent, ok := obj.(*v1alpha1.SpireAgent)
+	if !ok {
+		return nil, fmt.Errorf("expected a SpireAgent but got %T", obj)
+	}
+
+	if fieldErr := validateCommonConfig(&agent.Spec.CommonConfig); fieldErr != nil {
+		return nil, invalid("SpireAgent", agent.Name, fieldErr)
+	}
+	if fieldErr := validateSecurityContext(&agent.Spec.CommonConfig, true); fieldErr != nil {
+		return nil, invalid("SpireAgent", agent.Name, fieldErr)
+	}
+	if fieldErr := validateExtraContainers(agent.Spec.ExtraContainers, agent.Spec.ExtraInitContainers); fieldErr != nil {
+		return nil, invalid("SpireAgent", agent.Name, fieldErr)
+	}
+	if _, err := utils.DecodeExtraConfig(agent.Spec.ExtraConfig); err != nil {
+		return nil, invalid("SpireAgent", agent.Name,
+			field.Invalid(field.NewPath("spec", "extraConfig"), field.OmitValueType{}, err.Error()))
+	}
+	if err := utils.ValidateExperimentalConfig(agent.Spec.Experimental); err != nil {
+		return nil, invalid("SpireAgent", agent.Name,
+			field.Invalid(field.NewPath("spec", "experimental"), field.OmitValueType{}, err.Error()))
+	}
+	if err := utils.SpireAgentOperand.ValidateVersion(agent.Spec.Version); err != nil {
+		return nil, invalid("SpireAgent", agent.Name,
+			field.Invalid(field.NewPath("spec", "version"), agent.Spec.Version, err.Error()))
+	}
+	return nil, nil
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	spireoidc "github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/spire-oidc-discovery-provider"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-openshift-io-v1alpha1-spireoidcdiscoveryprovider,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spireoidcdiscoveryproviders,verbs=create,versions=v1alpha1,name=mspireoidcdiscoveryprovider.operator.openshift.io,admissionReviewVersions=v1
+
+// SpireOIDCDiscoveryProviderDefaulter fills in the SpireOIDCDiscoveryProvider defaults on create, so that the stored
+// object shows the effective configuration
+type SpireOIDCDiscoveryProviderDefaulter struct{}
+
+var _ admission.CustomDefaulter = &SpireOIDCDiscoveryProviderDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *SpireOIDCDiscoveryProviderDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	oidc, ok := obj.(*v1alpha1.SpireOIDCDiscoveryProvider)
+	if !ok {
+		return fmt.Errorf("expected a SpireOIDCDiscoveryProvider but got %T", obj)
+	}
+
+	spec := &oidc.Spec
+	if spec.LogLevel == "" {
+		spec.LogLevel = defaultLogLevel
+	}
+	if spec.LogFormat == "" {
+		spec.LogFormat = defaultLogFormat
+	}
+	if spec.CSIDriverName == "" {
+		spec.CSIDriverName = "csi.spiffe.io"
+	}
+	if spec.ReplicaCount == 0 {
+		spec.ReplicaCount = 1
+	}
+	if spec.ManagedRoute == "" {
+		spec.ManagedRoute = "true"
+	}
+	if spec.Autoscaling != nil {
+		if spec.Autoscaling.MinReplicas == 0 {
+			spec.Autoscaling.MinReplicas = 1
+		}
+		if spec.Autoscaling.TargetCPUUtilizationPercentage == 0 {
+			spec.Autoscaling.TargetCPUUtilizationPercentage = 80
+		}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-operator-openshift-io-v1alpha1-spireoidcdiscoveryprovider,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spireoidcdiscoveryproviders,verbs=create;update,versions=v1alpha1,name=vspireoidcdiscoveryprovider.operator.openshift.io,admissionReviewVersions=v1
+
+// SpireOIDCDiscoveryProviderValidator validates SpireOIDCDiscoveryProvider resources at admission time
+type SpireOIDCDiscoveryProviderValidator struct{}
+
+var _ admission.CustomValidator = &SpireOIDCDiscoveryProviderValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *SpireOIDCDiscoveryProviderValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *SpireOIDCDiscoveryProviderValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *SpireOIDCDiscoveryProviderValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *SpireOIDCDiscoveryProviderValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	oidc, ok := obj.(*v1alpha1.SpireOIDCDiscoveryProvider)
+	if !ok {
+		return nil, fmt.Errorf("expected a SpireOIDCDiscoveryProvider but got %T", obj)
+	}
+
+	if fieldErr := validateCommonConfig(&oidc.Spec.CommonConfig); fieldErr != nil {
+		return nil, invalid("SpireOIDCDiscoveryProvider", oidc.Name, fieldErr)
+	}
+	if fieldErr := validateSecurityContext(&oidc.Spec.CommonConfig, false); fieldErr != nil {
+		return nil, invalid("SpireOIDCDiscoveryProvider", oidc.Name, fieldErr)
+	}
+	if fieldErr := validateExtraContainers(oidc.Spec.ExtraContainers, oidc.Spec.ExtraInitContainers); fieldErr != nil {
+		return nil, invalid("SpireOIDCDiscoveryProvider", oidc.Name, fieldErr)
+	}
+	if fieldErr := validateTopologySpreadConstraints(oidc.Spec.TopologySpreadConstraints); fieldErr != nil {
+		return nil, invalid("SpireOIDCDiscoveryProvider", oidc.Name, fieldErr)
+	}
+
+	if err := spireoidc.ValidateSpec(&oidc.Spec); err != nil {
+		return nil, invalid("SpireOIDCDiscoveryProvider", oidc.Name, field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error()))
+	}
+	return nil, nil
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestSpireOIDCDiscoveryProviderValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        v1alpha1.SpireOIDCDiscoveryProviderSpec
+		expectError string
+	}{
+		{
+			name: "valid spec",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{JwtIssuer: "https://oidc.example.org"},
+		},
+		{
+			name: "unset JWT issuer is inherited from the SpireServer",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{},
+		},
+		{
+			name:        "invalid JWT issuer",
+			spec:        v1alpha1.SpireOIDCDiscoveryProviderSpec{JwtIssuer: "https://oidc.example.org?query=1"},
+			expectError: "jwtIssuer",
+		},
+		{
+			name: "autoscaling without CPU requests",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+				JwtIssuer:   "https://oidc.example.org",
+				Autoscaling: &v1alpha1.AutoscalingConfig{MaxReplicas: 3},
+			},
+			expectError: "resources.requests.cpu",
+		},
+		{
+			name: "autoscaling with CPU requests",
+			spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+				JwtIssuer:   "https://oidc.example.org",
+				Autoscaling: &v1alpha1.AutoscalingConfig{MaxReplicas: 3},
+				CommonConfig: v1alpha1.CommonConfig{Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				}},
+			},
+		},
+	}
+
+	v := &SpireOIDCDiscoveryProviderValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oidc := &v1alpha1.SpireOIDCDiscoveryProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       tt.spec,
+			}
+			_, err := v.ValidateCreate(context.Background(), oidc)
+			if tt.expectError == "" && err != nil {
+				t.Fatalf("ValidateCreate() unexpected error = %v", err)
+			}
+			if tt.expectError != "" {
+				if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("ValidateCreate() error = %v, expected an Invalid error containing %q", err, tt.expectError)
+				}
+			}
+		})
+	}
+}
+
+func TestSpireOIDCDiscoveryProviderDefaulter(t *testing.T) {
+	oidc := &v1alpha1.SpireOIDCDiscoveryProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.SpireOIDCDiscoveryProviderSpec{
+			JwtIssuer:   "https://oidc.example.org",
+			Autoscaling: &v1alpha1.AutoscalingConfig{MaxReplicas: 3},
+		},
+	}
+	if err := (&SpireOIDCDiscoveryProviderDefaulter{}).Default(context.Background(), oidc); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	spec := oidc.Spec
+	if spec.LogLevel != "info" || spec.LogFormat != "text" {
+		t.Errorf("Expected logging defaults info/text, got %s/%s", spec.LogLevel, spec.LogFormat)
+	}
+	if spec.CSIDriverName != "csi.spiffe.io" || spec.ManagedRoute != "true" || spec.ReplicaCount != 1 {
+		t.Errorf("Unexpected defaults: csiDriverName=%s managedRoute=%s replicaCount=%d", spec.CSIDriverName, spec.ManagedRoute, spec.ReplicaCount)
+	}
+	if spec.Autoscaling.MinReplicas != 1 || spec.Autoscaling.MaxReplicas != 3 || spec.Autoscaling.TargetCPUUtilizationPercentage != 80 {
+		t.Errorf("Unexpected autoscaling defaults: %+v", spec.Autoscaling)
+	}
+}
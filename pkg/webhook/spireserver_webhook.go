@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	spireserver "github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/spire-server"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-openshift-io-v1alpha1-spireserver,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spireservers,verbs=create,versions=v1alpha1,name=mspireserver.operator.openshift.io,admissionReviewVersions=v1
+
+// SpireServerDefaulter fills in the SpireServer defaults on create, so that the stored
+// object shows the effective configuration
+type SpireServerDefaulter struct{}
+
+var _ admission.CustomDefaulter = &SpireServerDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *SpireServerDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	server, ok := obj.(*v1alpha1.SpireServer)
+	if !ok {
+		return fmt.Errorf("expected a SpireServer but got %T", obj)
+	}
+
+	spec := &server.Spec
+	if spec.LogLevel == "" {
+		spec.LogLevel = defaultLogLevel
+	}
+	if spec.LogFormat == "" {
+		spec.LogFormat = defaultLogFormat
+	}
+	if spec.CAValidity.Duration == 0 {
+		spec.CAValidity = metav1.Duration{Duration: 24 * time.Hour}
+	}
+	if spec.DefaultX509Validity.Duration == 0 {
+		spec.DefaultX509Validity = metav1.Duration{Duration: time.Hour}
+	}
+	if spec.DefaultJWTValidity.Duration == 0 {
+		spec.DefaultJWTValidity = metav1.Duration{Duration: 5 * time.Minute}
+	}
+	if spec.CAKeyType == "" {
+		spec.CAKeyType = "rsa-2048"
+	}
+	if spec.KeyManager == nil {
+		spec.KeyManager = &v1alpha1.KeyManager{DiskEnabled: "true", MemoryEnabled: "false"}
+	}
+	if spec.Persistence.Type == "" {
+		spec.Persistence.Type = "PersistentVolumeClaim"
+	}
+	if spec.Persistence.Size == "" {
+		spec.Persistence.Size = "1Gi"
+	}
+	if spec.Persistence.AccessMode == "" {
+		spec.Persistence.AccessMode = "ReadWriteOnce"
+	}
+	if spec.Datastore.DatabaseType == "" {
+		spec.Datastore.DatabaseType = "sqlite3"
+		if spec.Datastore.ConnectionString == "" {
+			spec.Datastore.ConnectionString = "/run/spire/data/datastore.sqlite3"
+		}
+	}
+	if spec.Datastore.MaxOpenConns == 0 {
+		spec.Datastore.MaxOpenConns = 100
+	}
+	if spec.Datastore.DisableMigration == "" {
+		spec.Datastore.DisableMigration = "false"
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-operator-openshift-io-v1alpha1-spireserver,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=spireservers,verbs=create;update,versions=v1alpha1,name=vspireserver.operator.openshift.io,admissionReviewVersions=v1
+
+// SpireServerValidator validates SpireServer resources at admission time
+type SpireServerValidator struct {
+	// reader is used to look up the trust domain configured on the ZeroTrustWorkloadIdentityManager
+	reader client.Reader
+}
+
+var _ admission.CustomValidator = &SpireServerValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *SpireServerValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *SpireServerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *SpireServerValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *SpireServerValidator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	server, ok := obj.(*v1alpha1.SpireServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a SpireServer but got %T", obj)
+	}
+
+	if fieldErr := validateCommonConfig(&server.Spec.CommonConfig); fieldErr != nil {
+		return nil, invalid("SpireServer", server.Name, fieldErr)
+	}
+	if fieldErr := validateSecurityContext(&server.Spec.CommonConfig, false); fieldErr != nil {
+		return nil, invalid("SpireServer", server.Name, fieldErr)
+	}
+	if fieldErr := validateExtraContainers(server.Spec.ExtraContainers, server.Spec.ExtraInitContainers); fieldErr != nil {
+		return nil, invalid("SpireServer", server.Name, fieldErr)
+	}
+	if fieldErr := validateTopologySpreadConstraints(server.Spec.TopologySpreadConstraints); fieldErr != nil {
+		return nil, invalid("SpireServer", server.Name, fieldErr)
+	}
+
+	trustDomain, err := v.trustDomain(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := spireserver.ValidateSpec(&server.Spec, trustDomain)
+	if err != nil {
+		return warnings, invalid("SpireServer", server.Name, field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error()))
+	}
+	return warnings, nil
+}
+
+// trustDomain returns the trust domain of the cluster ZeroTrustWorkloadIdentityManager,
+// or an empty string while it has not been created yet
+func (v *SpireServerValidator) trustDomain(ctx context.Context) (string, error) {
+	if v.reader == nil {
+		return "", nil
+	}
+	var ztwim v1alpha1.ZeroTrustWorkloadIdentityManager
+	if err := v.reader.Get(ctx, types.NamespacedName{Name: "cluster"}, &ztwim); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get ZeroTrustWorkloadIdentityManager: %w", err)
+	}
+	return ztwim.Spec.TrustDomain, nil
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func newTestSpireServer() *v1alpha1.SpireServer {
+	return &v1alpha1.SpireServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.SpireServerSpec{
+			JwtIssuer:           "https://oidc.example.org",
+			CAValidity:          metav1.Duration{Duration: 24 * time.Hour},
+			DefaultX509Validity: metav1.Duration{Duration: time.Hour},
+			DefaultJWTValidity:  metav1.Duration{Duration: 5 * time.Minute},
+			Datastore:           v1alpha1.DataStore{DatabaseType: "sqlite3"},
+		},
+	}
+}
+
+func TestSpireServerValidator(t *testing.T) {
+	t.Setenv("FIPS_MODE", "false")
+
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org"},
+	}
+
+	tests := []struct {
+		name           string
+		mutate         func(*v1alpha1.SpireServer)
+		objects        bool
+		expectError    string
+		expectWarnings int
+	}{
+		{
+			name:   "valid spec",
+			mutate: func(*v1alpha1.SpireServer) {},
+		},
+		{
+			name: "bad TTL combination",
+			mutate: func(s *v1alpha1.SpireServer) {
+				s.Spec.DefaultJWTValidity = metav1.Duration{Duration: 48 * time.Hour}
+			},
+			expectError: "ca_validity must be greater than default_jwt_svid_ttl",
+		},
+		{
+			name: "TTL warnings are returned",
+			mutate: func(s *v1alpha1.SpireServer) {
+				s.Spec.DefaultX509Validity = metav1.Duration{Duration: 12 * time.Hour}
+			},
+			expectWarnings: 1,
+		},
+		{
+			name:        "unknown datastore plugin",
+			mutate:      func(s *v1alpha1.SpireServer) { s.Spec.Datastore.DatabaseType = "mongodb" },
+			expectError: "databaseType",
+		},
+		{
+			name: "self federation uses the ZeroTrustWorkloadIdentityManager trust domain",
+			mutate: func(s *v1alpha1.SpireServer) {
+				s.Spec.Federation = &v1alpha1.FederationConfig{
+					BundleEndpoint: v1alpha1.BundleEndpointConfig{Profile: v1alpha1.HttpsSpiffeProfile},
+					FederatesWith: []v1alpha1.FederatesWithConfig{
+						{TrustDomain: "example.org", BundleEndpointUrl: "https://remote.example", BundleEndpointProfile: v1alpha1.HttpsWebProfile},
+					},
+				}
+			},
+			objects:     true,
+			expectError: "cannot federate with own trust domain",
+		},
+		{
+			name:        "invalid common config",
+			mutate:      func(s *v1alpha1.SpireServer) { s.Spec.Labels = map[string]string{"invalid key": "value"} },
+			expectError: "labels",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.objects {
+				builder = builder.WithObjects(ztwim)
+			}
+			v := &SpireServerValidator{reader: builder.Build()}
+
+			server := newTestSpireServer()
+			tt.mutate(server)
+			warnings, err := v.ValidateCreate(context.Background(), server)
+			if tt.expectError == "" && err != nil {
+				t.Fatalf("ValidateCreate() unexpected error = %v", err)
+			}
+			if tt.expectError != "" {
+				if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("ValidateCreate() error = %v, expected an Invalid error containing %q", err, tt.expectError)
+				}
+			}
+			if len(warnings) != tt.expectWarnings {
+				t.Errorf("ValidateCreate() returned %d warnings, expected %d: %v", len(warnings), tt.expectWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestSpireServerDefaulter(t *testing.T) {
+	t.Run("empty spec is filled with defaults", func(t *testing.T) {
+		server := &v1alpha1.SpireServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		if err := (&SpireServerDefaulter{}).Default(context.Background(), server); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		spec := server.Spec
+		if spec.LogLevel != "info" || spec.LogFormat != "text" {
+			t.Errorf("Expected logging defaults info/text, got %s/%s", spec.LogLevel, spec.LogFormat)
+		}
+		if spec.CAValidity.Duration != 24*time.Hour || spec.DefaultX509Validity.Duration != time.Hour || spec.DefaultJWTValidity.Duration != 5*time.Minute {
+			t.Errorf("Unexpected TTL defaults: %v/%v/%v", spec.CAValidity, spec.DefaultX509Validity, spec.DefaultJWTValidity)
+		}
+		if spec.CAKeyType != "rsa-2048" {
+			t.Errorf("Expected caKeyType rsa-2048, got %s", spec.CAKeyType)
+		}
+		if spec.KeyManager == nil || spec.KeyManager.DiskEnabled != "true" {
+			t.Errorf("Expected the disk key manager to be enabled, got %+v", spec.KeyManager)
+		}
+		if spec.Persistence.Type != "PersistentVolumeClaim" || spec.Persistence.Size != "1Gi" || spec.Persistence.AccessMode != "ReadWriteOnce" {
+			t.Errorf("Unexpected persistence defaults: %+v", spec.Persistence)
+		}
+		if spec.Datastore.DatabaseType != "sqlite3" || spec.Datastore.ConnectionString != "/run/spire/data/datastore.sqlite3" {
+			t.Errorf("Unexpected datastore defaults: %+v", spec.Datastore)
+		}
+	})
+
+	t.Run("explicit values are kept", func(t *testing.T) {
+		server := newTestSpireServer()
+		server.Spec.LogLevel = "debug"
+		server.Spec.Datastore = v1alpha1.DataStore{DatabaseType: "postgres", ConnectionString: "dbname=spire", MaxOpenConns: 10}
+		if err := (&SpireServerDefaulter{}).Default(context.Background(), server); err != nil {
+			t.Fatalf("Default() error = %v", err)
+		}
+		if server.Spec.LogLevel != "debug" {
+			t.Errorf("Expected logLevel debug to be kept, got %s", server.Spec.LogLevel)
+		}
+		if server.Spec.DefaultJWTValidity.Duration != 5*time.Minute {
+			t.Errorf("Expected defaultJWTValidity to be kept, got %v", server.Spec.DefaultJWTValidity)
+		}
+		if server.Spec.Datastore.DatabaseType != "postgres" || server.Spec.Datastore.ConnectionString != "dbname=spire" || server.Spec.Datastore.MaxOpenConns != 10 {
+			t.Errorf("Expected datastore to be kept, got %+v", server.Spec.Datastore)
+		}
+	})
+}
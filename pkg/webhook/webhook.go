@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// Defaults shared by the operand specs, matching the defaults declared on the CRDs
+const (
+	defaultLogLevel  = "info"
+	defaultLogFormat = "text"
+)
+
+// SetupWithManager registers the defaulting and validating admission webhooks for the operator custom resources,
+// and the spiffe-helper sidecar injection webhook for pods
+func SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.ZeroTrustWorkloadIdentityManager{}).
+		WithDefaulter(&ZeroTrustWorkloadIdentityManagerDefaulter{}).
+		WithValidator(&ZeroTrustWorkloadIdentityManagerValidator{}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.SpireServer{}).
+		WithDefaulter(&SpireServerDefaulter{}).
+		WithValidator(&SpireServerValidator{reader: mgr.GetAPIReader()}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.SpireAgent{}).
+		WithDefaulter(&SpireAgentDefaulter{}).
+		WithValidator(&SpireAgentValidator{}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.SpiffeCSIDriver{}).
+		WithDefaulter(&SpiffeCSIDriverDefaulter{}).
+		WithValidator(&SpiffeCSIDriverValidator{}).
+		Complete(); err != nil {
+		return err
+	}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.SpireOIDCDiscoveryProvider{}).
+		WithDefaulter(&SpireOIDCDiscoveryProviderDefaulter{}).
+		WithValidator(&SpireOIDCDiscoveryProviderValidator{}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Pod{}).
+		WithDefaulter(&SpiffeHelperInjector{reader: mgr.GetClient()}).
+		Complete()
+}
+
+// validateCommonConfig validates the scheduling, resources, labels, extra volumes and environment shared by all
+// operand specs. The CRD schema leaves the embedded Kubernetes types of the extra volumes and the environment
+// unvalidated, so they are rejected here.
+func validateCommonConfig(config *v1alpha1.CommonConfig) *field.Error {
+	if err := utils.ValidateCommonConfig(config.Affinity, config.Tolerations, config.NodeSelector, config.Resources, config.Labels); err != nil {
+		return field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())
+	}
+	if err := utils.ValidateExtraVolumes(config.ExtraVolumes, config.ExtraVolumeMounts); err != nil {
+		return field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())
+	}
+	if err := utils.ValidateContainerEnv(config.Env, config.EnvFrom); err != nil {
+		return field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())
+	}
+	return nil
+}
+
+// validateExtraContainers validates the extra containers and init containers of an operand spec, which the CRD
+// schema leaves unvalidated
+func validateExtraContainers(containers, initContainers []corev1.Container) *field.Error {
+	if err := utils.ValidateExtraContainers(containers, initContainers); err != nil {
+		return field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())
+	}
+	return nil
+}
+
+// validateTopologySpreadConstraints validates the topology spread constraints of an operand spec, which the CRD
+// schema leaves unvalidated
+func validateTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint) *field.Error {
+	if err := utils.ValidateTopologySpreadConstraints(constraints); err != nil {
+		return field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())
+	}
+	return nil
+}
+
+// validateSecurityContext validates the security context overrides of an operand spec, which the CRD schema leaves
+// unvalidated. privileged is set for the operands accessing host paths.
+func validateSecurityContext(config *v1alpha1.CommonConfig, privileged bool) *field.Error {
+	if err := utils.ValidateSecurityContext(config.SecurityContext, config.PodSecurityContext, privileged); err != nil {
+		return field.Invalid(field.NewPath("spec"), field.OmitValueType{}, err.Error())
+	}
+	return nil
+}
+
+// invalid returns the admission error reported for a rejected custom resource
+func invalid(kind, name string, errs ...*field.Error) error {
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(kind).GroupKind(), name, errs)
+}
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+)
+
+func TestValidateCommonConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      v1alpha1.CommonConfig
+		expectError bool
+	}{
+		{
+			name:   "empty config",
+			config: v1alpha1.CommonConfig{},
+		},
+		{
+			name:   "valid node selector",
+			config: v1alpha1.CommonConfig{NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""}},
+		},
+		{
+			name:        "invalid label key",
+			config:      v1alpha1.CommonConfig{Labels: map[string]string{"invalid key": "value"}},
+			expectError: true,
+		},
+		{
+			name: "invalid toleration operator",
+			config: v1alpha1.CommonConfig{Tolerations: []*corev1.Toleration{
+				{Key: "key", Operator: "Unknown", Value: "value"},
+			}},
+			expectError: true,
+		},
+		{
+			name: "extra volume mount of an unknown volume",
+			config: v1alpha1.CommonConfig{ExtraVolumeMounts: []corev1.VolumeMount{
+				{Name: "site-ca", MountPath: "/etc/pki/site"},
+			}},
+			expectError: true,
+		},
+		{
+			name:        "env variable without name",
+			config:      v1alpha1.CommonConfig{Env: []corev1.EnvVar{{Value: "true"}}},
+			expectError: true,
+		},
+		{
+			name: "node affinity without node selector terms",
+			config: v1alpha1.CommonConfig{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{},
+			}}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldErr := validateCommonConfig(&tt.config)
+			if (fieldErr != nil) != tt.expectError {
+				t.Errorf("validateCommonConfig() error = %v, expectError = %v", fieldErr, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateTopologySpreadConstraints(t *testing.T) {
+	zones := corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule}
+
+	if fieldErr := validateTopologySpreadConstraints([]corev1.TopologySpreadConstraint{zones}); fieldErr != nil {
+		t.Errorf("Expected a valid constraint to be admitted, got %v", fieldErr)
+	}
+	if fieldErr := validateTopologySpreadConstraints([]corev1.TopologySpreadConstraint{zones, zones}); fieldErr == nil {
+		t.Error("Expected duplicate constraints to be rejected")
+	}
+}
+
+func TestCommonConfigValidators(t *testing.T) {
+	invalidLabels := v1alpha1.CommonConfig{Labels: map[string]string{"invalid key": "value"}}
+
+	t.Run("SpireAgent", func(t *testing.T) {
+		v := &SpireAgentValidator{}
+		agent := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		if _, err := v.ValidateCreate(context.Background(), agent); err != nil {
+			t.Errorf("Expected valid SpireAgent to be admitted, got %v", err)
+		}
+		agent.Spec.CommonConfig = invalidLabels
+		if _, err := v.ValidateUpdate(context.Background(), agent, agent); !apierrors.IsInvalid(err) {
+			t.Errorf("Expected an Invalid error for SpireAgent, got %v", err)
+		}
+		agent.Spec.CommonConfig = v1alpha1.CommonConfig{}
+		agent.Spec.ExtraContainers = []corev1.Container{{Name: "log-shipper"}}
+		if _, err := v.ValidateUpdate(context.Background(), agent, agent); !apierrors.IsInvalid(err) {
+			t.Errorf("Expected an Invalid error for an extra container without image, got %v", err)
+		}
+	})
+
+	t.Run("SpiffeCSIDriver", func(t *testing.T) {
+		v := &SpiffeCSIDriverValidator{}
+		driver := &v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		if _, err := v.ValidateCreate(context.Background(), driver); err != nil {
+			t.Errorf("Expected valid SpiffeCSIDriver to be admitted, got %v", err)
+		}
+		driver.Spec.CommonConfig = invalidLabels
+		if _, err := v.ValidateUpdate(context.Background(), driver, driver); !apierrors.IsInvalid(err) {
+			t.Errorf("Expected an Invalid error for SpiffeCSIDriver, got %v", err)
+		}
+		driver.Spec.CommonConfig = v1alpha1.CommonConfig{SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)}}
+		if _, err := v.ValidateUpdate(context.Background(), driver, driver); !apierrors.IsInvalid(err) {
+			t.Errorf("Expected an Invalid error for a non-root SpiffeCSIDriver, got %v", err)
+		}
+	})
+
+	t.Run("wrong object type", func(t *testing.T) {
+		v := &SpireAgentValidator{}
+		if _, err := v.ValidateCreate(context.Background(), &v1alpha1.SpiffeCSIDriver{}); err == nil {
+			t.Error("Expected an error for an unexpected object type")
+		}
+	})
+}
+
+func TestSocketPathDefaulters(t *testing.T) {
+	agent := &v1alpha1.SpireAgent{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	if err := (&SpireAgentDefaulter{}).Default(context.Background(), agent); err != nil {
+		t.Fatalf("SpireAgent Default() error = %v", err)
+	}
+	if agent.Spec.SocketPath != "/run/spire/agent-sockets" || agent.Spec.LogLevel != "info" || agent.Spec.LogFormat != "text" {
+		t.Errorf("Unexpected SpireAgent defaults: %+v", agent.Spec)
+	}
+
+	driver := &v1alpha1.SpiffeCSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	driver.Spec.PluginName = "csi.example.org"
+	if err := (&SpiffeCSIDriverDefaulter{}).Default(context.Background(), driver); err != nil {
+		t.Fatalf("SpiffeCSIDriver Default() error = %v", err)
+	}
+	if driver.Spec.AgentSocketPath != "/run/spire/agent-sockets" || driver.Spec.PluginName != "csi.example.org" || driver.Spec.KubeletPath != "/var/lib/kubelet" {
+		t.Errorf("Unexpected SpiffeCSIDriver defaults: %+v", driver.Spec)
+	}
+}
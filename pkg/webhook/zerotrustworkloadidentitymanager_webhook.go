@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-openshift-io-v1alpha1-zerotrustworkloadidentitymanager,mutating=true,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=zerotrustworkloadidentitymanagers,verbs=create,versions=v1alpha1,name=mzerotrustworkloadidentitymanager.operator.openshift.io,admissionReviewVersions=v1
+
+// ZeroTrustWorkloadIdentityManagerDefaulter fills in the ZeroTrustWorkloadIdentityManager defaults on create, so that the stored
+// object shows the effective configuration
+type ZeroTrustWorkloadIdentityManagerDefaulter struct{}
+
+var _ admission.CustomDefaulter = &ZeroTrustWorkloadIdentityManagerDefaulter{}
+
+// Default implements admission.CustomDefaulter
+func (d *ZeroTrustWorkloadIdentityManagerDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	ztwim, ok := obj.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+	if !ok {
+		return fmt.Errorf("expected a ZeroTrustWorkloadIdentityManager but got %T", obj)
+	}
+
+	if ztwim.Spec.BundleConfigMap == "" {
+		ztwim.Spec.BundleConfigMap = "spire-bundle"
+	}
+	// The operand namespace is immutable, so the default of the operator configuration only applies on create
+	if ztwim.Spec.OperandNamespace == "" {
+		ztwim.Spec.OperandNamespace = utils.GetDefaultOperandNamespace()
+	}
+	return nil
+}
+
+// AllowIdentityChangeAnnotation lets an update of the ZeroTrustWorkloadIdentityManager change its trust domain or
+// cluster name when set to "true", for an intentional migration
+const AllowIdentityChangeAnnotation = "ztwim.openshift.io/allow-identity-change"
+
+// +kubebuilder:webhook:path=/validate-operator-openshift-io-v1alpha1-zerotrustworkloadidentitymanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.openshift.io,resources=zerotrustworkloadidentitymanagers,verbs=create;update,versions=v1alpha1,name=vzerotrustworkloadidentitymanager.operator.openshift.io,admissionReviewVersions=v1
+
+// ZeroTrustWorkloadIdentityManagerValidator validates ZeroTrustWorkloadIdentityManager resources at admission time
+type ZeroTrustWorkloadIdentityManagerValidator struct{}
+
+var _ admission.CustomValidator = &ZeroTrustWorkloadIdentityManagerValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *ZeroTrustWorkloadIdentityManagerValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *ZeroTrustWorkloadIdentityManagerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	warnings, err := v.validate(newObj)
+	if err != nil {
+		return warnings, err
+	}
+	oldZTWIM, ok := oldObj.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a ZeroTrustWorkloadIdentityManager but got %T", oldObj)
+	}
+	identityWarnings, err := validateIdentityChange(oldZTWIM, newObj.(*v1alpha1.ZeroTrustWorkloadIdentityManager))
+	return append(warnings, identityWarnings...), err
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *ZeroTrustWorkloadIdentityManagerValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ZeroTrustWorkloadIdentityManagerValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	ztwim, ok := obj.(*v1alpha1.ZeroTrustWorkloadIdentityManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a ZeroTrustWorkloadIdentityManager but got %T", obj)
+	}
+
+	if err := utils.IsValidTrustDomain(ztwim.Spec.TrustDomain); err != nil {
+		return nil, invalid("ZeroTrustWorkloadIdentityManager", ztwim.Name,
+			field.Invalid(field.NewPath("spec", "trustDomain"), ztwim.Spec.TrustDomain, err.Error()))
+	}
+	if err := utils.ValidateTopology(ztwim.Spec.Topology); err != nil {
+		return nil, invalid("ZeroTrustWorkloadIdentityManager", ztwim.Name,
+			field.Invalid(field.NewPath("spec", "topology"), ztwim.Spec.Topology, err.Error()))
+	}
+	return nil, nil
+}
+
+// validateIdentityChange rejects the changes of the trust domain and of the cluster name, which invalidate the
+// identities issued, unless the ZeroTrustWorkloadIdentityManager carries the break-glass annotation
+func validateIdentityChange(oldZTWIM, ztwim *v1alpha1.ZeroTrustWorkloadIdentityManager) (admission.Warnings, error) {
+	var changes []string
+	var errs field.ErrorList
+	if oldZTWIM.Spec.TrustDomain != ztwim.Spec.TrustDomain {
+		changes = append(changes, fmt.Sprintf("trustDomain from %q to %q", oldZTWIM.Spec.TrustDomain, ztwim.Spec.TrustDomain))
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "trustDomain"),
+			fmt.Sprintf("trustDomain is immutable, changing it invalidates every identity issued; set the %s annotation to \"true\" to migrate it intentionally", AllowIdentityChangeAnnotation)))
+	}
+	if oldZTWIM.Spec.ClusterName != ztwim.Spec.ClusterName {
+		changes = append(changes, fmt.Sprintf("clusterName from %q to %q", oldZTWIM.Spec.ClusterName, ztwim.Spec.ClusterName))
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "clusterName"),
+			fmt.Sprintf("clusterName is immutable, changing it invalidates the identities of the SPIRE agents; set the %s annotation to \"true\" to migrate it intentionally", AllowIdentityChangeAnnotation)))
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if !utils.StringToBool(ztwim.Annotations[AllowIdentityChangeAnnotation]) {
+		return nil, invalid("ZeroTrustWorkloadIdentityManager", ztwim.Name, errs...)
+	}
+	return admission.Warnings{fmt.Sprintf("Changing %s invalidates the identities issued by SPIRE, the workloads must fetch new SVIDs; "+
+		"remove the %s annotation once the migration is done", strings.Join(changes, " and "), AllowIdentityChangeAnnotation)}, nil
+}
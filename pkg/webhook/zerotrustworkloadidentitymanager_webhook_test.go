@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/zero-trust-workload-identity-manager/api/v1alpha1"
+	"github.com/openshift/zero-trust-workload-identity-manager/pkg/controller/utils"
+)
+
+func TestZeroTrustWorkloadIdentityManagerValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		trustDomain string
+		expectError bool
+	}{
+		{name: "valid trust domain", trustDomain: "example.org"},
+		{name: "spiffe URI", trustDomain: "spiffe://example.org", expectError: true},
+		{name: "uppercase characters", trustDomain: "Example.org", expectError: true},
+		{name: "empty", trustDomain: "", expectError: true},
+	}
+
+	v := &ZeroTrustWorkloadIdentityManagerValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: tt.trustDomain, ClusterName: "test-cluster"},
+			}
+			_, err := v.ValidateCreate(context.Background(), ztwim)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ValidateCreate() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if err != nil && !apierrors.IsInvalid(err) {
+				t.Errorf("Expected an Invalid error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestZeroTrustWorkloadIdentityManagerValidatorTopology(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{
+			TrustDomain: "example.org",
+			ClusterName: "test-cluster",
+			Topology:    &v1alpha1.TopologyConfig{Mode: v1alpha1.TopologyHostedCluster, ServerAddress: "spire-server.apps.example.com"},
+		},
+	}
+	if _, err := (&ZeroTrustWorkloadIdentityManagerValidator{}).ValidateCreate(context.Background(), ztwim); !apierrors.IsInvalid(err) {
+		t.Errorf("Expected an Invalid error for a server address without port, got %v", err)
+	}
+
+	ztwim.Spec.Topology.ServerAddress = "spire-server.apps.example.com:443"
+	if _, err := (&ZeroTrustWorkloadIdentityManagerValidator{}).ValidateCreate(context.Background(), ztwim); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestZeroTrustWorkloadIdentityManagerValidatorIdentityChange(t *testing.T) {
+	newZTWIM := func(trustDomain, clusterName string, annotations map[string]string) *v1alpha1.ZeroTrustWorkloadIdentityManager {
+		return &v1alpha1.ZeroTrustWorkloadIdentityManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Annotations: annotations},
+			Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: trustDomain, ClusterName: clusterName},
+		}
+	}
+	breakGlass := map[string]string{AllowIdentityChangeAnnotation: "true"}
+	oldZTWIM := newZTWIM("example.org", "test-cluster", nil)
+
+	tests := []struct {
+		name          string
+		ztwim         *v1alpha1.ZeroTrustWorkloadIdentityManager
+		expectError   bool
+		expectWarning bool
+	}{
+		{name: "unchanged", ztwim: newZTWIM("example.org", "test-cluster", nil)},
+		{name: "trust domain changed", ztwim: newZTWIM("example.com", "test-cluster", nil), expectError: true},
+		{name: "cluster name changed", ztwim: newZTWIM("example.org", "other-cluster", nil), expectError: true},
+		{name: "annotation not enabled", ztwim: newZTWIM("example.com", "test-cluster", map[string]string{AllowIdentityChangeAnnotation: "false"}), expectError: true},
+		{name: "trust domain changed with the annotation", ztwim: newZTWIM("example.com", "test-cluster", breakGlass), expectWarning: true},
+		{name: "cluster name changed with the annotation", ztwim: newZTWIM("example.org", "other-cluster", breakGlass), expectWarning: true},
+		{name: "annotation without a change", ztwim: newZTWIM("example.org", "test-cluster", breakGlass)},
+	}
+
+	v := &ZeroTrustWorkloadIdentityManagerValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := v.ValidateUpdate(context.Background(), oldZTWIM, tt.ztwim)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ValidateUpdate() error = %v, expectError = %v", err, tt.expectError)
+			}
+			if err != nil && !apierrors.IsInvalid(err) {
+				t.Errorf("Expected an Invalid error, got %v", err)
+			}
+			if (len(warnings) > 0) != tt.expectWarning {
+				t.Errorf("Expected a warning %t, got %v", tt.expectWarning, warnings)
+			}
+		})
+	}
+}
+
+func TestZeroTrustWorkloadIdentityManagerDefaulter(t *testing.T) {
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+	if err := (&ZeroTrustWorkloadIdentityManagerDefaulter{}).Default(context.Background(), ztwim); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if ztwim.Spec.BundleConfigMap != "spire-bundle" {
+		t.Errorf("Expected bundleConfigMap spire-bundle, got %s", ztwim.Spec.BundleConfigMap)
+	}
+}
+
+func TestZeroTrustWorkloadIdentityManagerDefaulterOperandNamespace(t *testing.T) {
+	t.Cleanup(func() { utils.SetDefaultOperandNamespace("") })
+	utils.SetDefaultOperandNamespace("spire-system")
+
+	ztwim := &v1alpha1.ZeroTrustWorkloadIdentityManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       v1alpha1.ZeroTrustWorkloadIdentityManagerSpec{TrustDomain: "example.org", ClusterName: "test-cluster"},
+	}
+	if err := (&ZeroTrustWorkloadIdentityManagerDefaulter{}).Default(context.Background(), ztwim); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if ztwim.Spec.OperandNamespace != "spire-system" {
+		t.Errorf("Expected operandNamespace spire-system, got %s", ztwim.Spec.OperandNamespace)
+	}
+
+	ztwim.Spec.OperandNamespace = "custom"
+	if err := (&ZeroTrustWorkloadIdentityManagerDefaulter{}).Default(context.Background(), ztwim); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if ztwim.Spec.OperandNamespace != "custom" {
+		t.Errorf("Expected the operandNamespace set by the user to be kept, got %s", ztwim.Spec.OperandNamespace)
+	}
+}